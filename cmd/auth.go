@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/toba/bean-me-up/internal/auth"
+)
+
+var (
+	authClientID     string
+	authClientSecret string
+	authPort         int
+	authNoBrowser    bool
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage ClickUp OAuth credentials",
+	Long: `Logs in to ClickUp through an OAuth app instead of a personal pk_ token.
+
+CLICKUP_TOKEN, when set, always takes precedence over a stored login.`,
+}
+
+var authLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Authorize beanup with a ClickUp OAuth app",
+	Long: `Opens ClickUp in the browser to authorize beanup, receives the redirect
+on a local listener, and stores the resulting token.
+
+Create an OAuth app under ClickUp Settings → Integrations → ClickUp API and
+set its redirect URL to 127.0.0.1. Pass the app's credentials with
+--client-id/--client-secret or CLICKUP_CLIENT_ID/CLICKUP_CLIENT_SECRET.
+
+The token is saved to beanup/credentials.json in your user config directory
+(e.g. ~/.config/beanup/credentials.json), readable only by you.`,
+	Args: cobra.NoArgs,
+	RunE: runAuthLogin,
+}
+
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the stored ClickUp login",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		removed, err := auth.Delete(auth.ServiceClickUp)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			fmt.Println("Not logged in")
+			return nil
+		}
+		fmt.Println("Logged out of ClickUp")
+		return nil
+	},
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show which ClickUp credentials beanup will use",
+	Args:  cobra.NoArgs,
+	RunE:  runAuthStatus,
+}
+
+func init() {
+	authLoginCmd.Flags().StringVar(&authClientID, "client-id", "", "OAuth app client ID (default: $CLICKUP_CLIENT_ID)")
+	authLoginCmd.Flags().StringVar(&authClientSecret, "client-secret", "", "OAuth app client secret (default: $CLICKUP_CLIENT_SECRET)")
+	authLoginCmd.Flags().IntVar(&authPort, "port", 8765, "Local port for the OAuth redirect")
+	authLoginCmd.Flags().BoolVar(&authNoBrowser, "no-browser", false, "Print the authorization URL instead of opening a browser")
+	authCmd.AddCommand(authLoginCmd, authLogoutCmd, authStatusCmd)
+	rootCmd.AddCommand(authCmd)
+}
+
+func runAuthLogin(cmd *cobra.Command, args []string) error {
+	if authClientID == "" {
+		authClientID = os.Getenv("CLICKUP_CLIENT_ID")
+	}
+	if authClientSecret == "" {
+		authClientSecret = os.Getenv("CLICKUP_CLIENT_SECRET")
+	}
+	if authClientID == "" || authClientSecret == "" {
+		return fmt.Errorf("OAuth client ID and secret are required (--client-id/--client-secret or CLICKUP_CLIENT_ID/CLICKUP_CLIENT_SECRET)")
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	flow := &auth.Flow{
+		Endpoint:     auth.ClickUpEndpoint,
+		ClientID:     authClientID,
+		ClientSecret: authClientSecret,
+		Port:         authPort,
+	}
+	creds, err := flow.Login(ctx, func(authURL string) {
+		if !authNoBrowser && openBrowser(authURL) == nil {
+			fmt.Println("Opened ClickUp in your browser; waiting for authorization...")
+			fmt.Printf("If nothing happened, visit:\n  %s\n", authURL)
+			return
+		}
+		fmt.Printf("Visit this URL to authorize beanup:\n  %s\n", authURL)
+	})
+	if err != nil {
+		return err
+	}
+
+	user, err := newClickUpClient(ctx, creds.AccessToken).GetAuthorizedUser(ctx)
+	if err != nil {
+		return fmt.Errorf("verifying token: %w", err)
+	}
+	if err := auth.Save(auth.ServiceClickUp, creds); err != nil {
+		return err
+	}
+	fmt.Printf("Logged in to ClickUp as %s\n", user.Username)
+	return nil
+}
+
+func runAuthStatus(cmd *cobra.Command, args []string) error {
+	if os.Getenv("CLICKUP_TOKEN") != "" {
+		fmt.Println("Using CLICKUP_TOKEN from the environment")
+		return nil
+	}
+	creds, err := auth.Load(auth.ServiceClickUp)
+	if err != nil {
+		return err
+	}
+	if creds == nil {
+		fmt.Println("Not logged in (set CLICKUP_TOKEN or run beanup auth login)")
+		return nil
+	}
+	path, _ := auth.Path()
+	fmt.Printf("Using stored OAuth login from %s\n", path)
+	if !creds.Expiry.IsZero() {
+		fmt.Printf("Token expires %s\n", creds.Expiry.Local().Format(time.RFC1123))
+	}
+	return nil
+}
+
+// openBrowser opens url in the user's default browser.
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
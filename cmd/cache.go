@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/toba/bean-me-up/internal/clickup"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the ClickUp response cache",
+	Long: `ClickUp GET responses are cached under the user cache directory and
+revalidated on later runs. List statuses, custom fields, workspaces, custom
+task types, and the authorized user are reused without a request for
+cache_ttl (default 1h).
+
+Clear the cache after changing statuses or fields in ClickUp to see them
+before the TTL runs out, or pass --no-cache to any command to bypass it.`,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete every cached ClickUp response",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := clickup.HTTPCacheDir()
+		if err != nil {
+			return fmt.Errorf("finding cache directory: %w", err)
+		}
+		if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+			fmt.Println("Cache is already empty")
+			return nil
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("clearing cache: %w", err)
+		}
+		fmt.Printf("Cleared %s\n", dir)
+		return nil
+	},
+}
+
+var cacheDirCmd = &cobra.Command{
+	Use:   "dir",
+	Short: "Print the cache directory",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := clickup.HTTPCacheDir()
+		if err != nil {
+			return fmt.Errorf("finding cache directory: %w", err)
+		}
+		fmt.Println(dir)
+		return nil
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheClearCmd)
+	cacheCmd.AddCommand(cacheDirCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -2,9 +2,15 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/toba/bean-me-up/internal/beans"
@@ -14,7 +20,10 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var skipAPI bool
+var (
+	skipAPI  bool
+	checkFix bool
+)
 
 var checkCmd = &cobra.Command{
 	Use:   "check",
@@ -24,18 +33,34 @@ var checkCmd = &cobra.Command{
 Checks include:
   - Configuration file exists and is parseable
   - List ID is configured and accessible
-  - Status, priority, and type mappings are valid
+  - Status, priority, and type mappings are valid, with mapped
+    priorities turned on and offered by the list's space
+  - Every status, priority, and type beans use has a mapping, with
+    similarly named list statuses and task types suggested for gaps
   - Custom fields exist on the ClickUp list (if configured)
-  - CLICKUP_TOKEN is set and valid
+  - A ClickUp token (CLICKUP_TOKEN or beanup auth login) is available and valid
   - Sync state (external metadata on beans)
   - All linked tasks exist in ClickUp
+  - Every task carrying a Bean ID custom field has a local bean
+
+Use --skip-api to perform offline validation only.
+
+With --fix, problems that have a safe repair are fixed and each repair is
+reported:
+  - Links to tasks deleted in ClickUp are cleared
+  - Unlinked beans are re-linked to tasks whose Bean ID custom field
+    names them (when custom_fields.bean_id is configured)
+  - Entries for beans that no longer exist are removed from a legacy
+    .sync.json
+  - Priority mappings outside ClickUp's 1-4 are clamped into range
 
-Use --skip-api to perform offline validation only.`,
+--fix holds the project lock, like sync, while it runs.`,
 	RunE: runCheck,
 }
 
 func init() {
 	checkCmd.Flags().BoolVar(&skipAPI, "skip-api", false, "Skip ClickUp API checks (offline validation only)")
+	checkCmd.Flags().BoolVar(&checkFix, "fix", false, "Repair what can be repaired and report each fix")
 	rootCmd.AddCommand(checkCmd)
 }
 
@@ -46,6 +71,8 @@ const (
 	checkPass checkStatus = "pass"
 	checkWarn checkStatus = "warn"
 	checkFail checkStatus = "fail"
+	// checkFixed is a problem found and repaired by --fix.
+	checkFixed checkStatus = "fixed"
 )
 
 // checkResult holds the result of a single check.
@@ -66,6 +93,7 @@ type checkSummary struct {
 	Passed   int `json:"passed"`
 	Warnings int `json:"warnings"`
 	Failed   int `json:"failed"`
+	Fixed    int `json:"fixed"`
 }
 
 // checkOutput is the JSON output structure.
@@ -78,24 +106,47 @@ func runCheck(cmd *cobra.Command, args []string) error {
 	// Suppress usage on error since check errors are specific validation failures
 	cmd.SilenceUsage = true
 
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	ctx, stop := interruptContext(cmd.Context())
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
+	// --fix writes beans and sync state, so serialize against other beanup
+	// processes the same way sync does
+	if checkFix {
+		projectLock, err := acquireProjectLock(ctx)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = projectLock.Release() }()
+	}
+
 	output := checkOutput{
 		Sections: make([]checkSection, 0, 3),
 	}
 
-	// Configuration section
-	configSection := checkConfiguration(ctx)
-	output.Sections = append(output.Sections, configSection)
-
-	// ClickUp Integration section
-	integrationSection := checkClickUpIntegration(ctx)
-	output.Sections = append(output.Sections, integrationSection)
-
-	// Sync State section
-	syncSection := checkSyncState(ctx)
-	output.Sections = append(output.Sections, syncSection)
+	always := func(check func(context.Context) checkSection) func(context.Context) (checkSection, bool) {
+		return func(ctx context.Context) (checkSection, bool) { return check(ctx), true }
+	}
+	sections := []func(context.Context) (checkSection, bool){
+		always(checkConfiguration),
+		// Mappings compared with the values beans actually use
+		checkMappingCoverage,
+		always(checkClickUpIntegration),
+		always(checkSyncState),
+		// Reverse integrity: tasks whose beans are gone
+		checkOrphanTasks,
+	}
+	for _, run := range sections {
+		section, ok := run(ctx)
+		// A section cut short by an interrupt would report false failures
+		if interrupted(ctx) {
+			break
+		}
+		if ok {
+			output.Sections = append(output.Sections, section)
+		}
+	}
 
 	// Calculate summary
 	for _, section := range output.Sections {
@@ -107,16 +158,29 @@ func runCheck(cmd *cobra.Command, args []string) error {
 				output.Summary.Warnings++
 			case checkFail:
 				output.Summary.Failed++
+			case checkFixed:
+				output.Summary.Fixed++
 			}
 		}
 	}
 
+	var interruptErr error
+	if interrupted(ctx) {
+		interruptErr = interruptedError("only the checks that finished are shown")
+	}
+
 	if jsonOut {
-		return outputJSON(output)
+		if err := outputJSON(output); err != nil {
+			return err
+		}
+		return interruptErr
 	}
 
 	// Text output
 	printCheckOutput(output)
+	if interruptErr != nil {
+		return interruptErr
+	}
 
 	// Exit with error code if any checks failed
 	if output.Summary.Failed > 0 {
@@ -143,7 +207,7 @@ func checkConfiguration(ctx context.Context) checkSection {
 		return section
 	}
 
-	cfg, configDir, err := config.LoadFromDirectory(cwd)
+	cfg, configDir, err := config.LoadFromDirectory(cwd, profile)
 	if err != nil {
 		section.Checks = append(section.Checks, checkResult{
 			Name:    "Config file found",
@@ -178,10 +242,11 @@ func checkConfiguration(ctx context.Context) checkSection {
 	}
 
 	// Check list accessibility (requires API)
+	var space *clickup.Space
 	if !skipAPI && listID != "" {
 		token, _ := getClickUpToken()
 		if token != "" {
-			client := clickup.NewClient(token)
+			client := newClickUpClient(ctx, token)
 			list, err := client.GetList(ctx, listID)
 			if err != nil {
 				section.Checks = append(section.Checks, checkResult{
@@ -199,6 +264,26 @@ func checkConfiguration(ctx context.Context) checkSection {
 				// Check status mapping against list statuses
 				section.Checks = append(section.Checks, checkStatusMapping(cfg, list)...)
 
+				// Priorities are checked against the space's below; without
+				// it, just ClickUp's range
+				if list.SpaceID != "" {
+					space, _ = client.GetSpace(ctx, list.SpaceID)
+				}
+
+				// Check tag mapping against the space's tags
+				if len(cfg.Beans.ClickUp.TagMapping) > 0 && list.SpaceID != "" {
+					tags, err := client.GetSpaceTags(ctx, list.SpaceID)
+					if err != nil {
+						section.Checks = append(section.Checks, checkResult{
+							Name:    "Tag mapping valid",
+							Status:  checkWarn,
+							Message: fmt.Sprintf("Cannot get space tags: %v", err),
+						})
+					} else {
+						section.Checks = append(section.Checks, checkTagMapping(cfg, tags))
+					}
+				}
+
 				// Check custom fields if configured
 				if cfg.Beans.ClickUp.CustomFields != nil {
 					section.Checks = append(section.Checks, checkCustomFields(ctx, cfg, client, listID)...)
@@ -214,26 +299,7 @@ func checkConfiguration(ctx context.Context) checkSection {
 	}
 
 	// Check priority mapping
-	priorityMapping := cfg.GetPriorityMapping()
-	invalidPriorities := []string{}
-	for beanPriority, clickupPriority := range priorityMapping {
-		if clickupPriority < 1 || clickupPriority > 4 {
-			invalidPriorities = append(invalidPriorities, fmt.Sprintf("%s=%d", beanPriority, clickupPriority))
-		}
-	}
-	if len(invalidPriorities) > 0 {
-		section.Checks = append(section.Checks, checkResult{
-			Name:    "Priority mapping valid",
-			Status:  checkWarn,
-			Message: fmt.Sprintf("Invalid priorities (must be 1-4): %v", invalidPriorities),
-		})
-	} else {
-		section.Checks = append(section.Checks, checkResult{
-			Name:    "Priority mapping valid",
-			Status:  checkPass,
-			Message: fmt.Sprintf("%d mappings", len(priorityMapping)),
-		})
-	}
+	section.Checks = append(section.Checks, checkPriorityMapping(cfg, space)...)
 
 	// Check type mapping
 	if len(cfg.Beans.ClickUp.TypeMapping) > 0 {
@@ -250,6 +316,23 @@ func checkConfiguration(ctx context.Context) checkSection {
 		})
 	}
 
+	// Check title template
+	if tmpl := cfg.Beans.ClickUp.TitleTemplate; tmpl != "" {
+		if _, err := clickup.ParseTitleTemplate(tmpl); err != nil {
+			section.Checks = append(section.Checks, checkResult{
+				Name:    "Title template valid",
+				Status:  checkFail,
+				Message: err.Error(),
+			})
+		} else {
+			section.Checks = append(section.Checks, checkResult{
+				Name:    "Title template valid",
+				Status:  checkPass,
+				Message: tmpl,
+			})
+		}
+	}
+
 	return section
 }
 
@@ -335,6 +418,21 @@ func checkCustomFields(ctx context.Context, cfg *config.Config, client *clickup.
 			invalidFields = append(invalidFields, "updated_at")
 		}
 	}
+	if cf.Parent != "" {
+		if _, ok := validFields[cf.Parent]; !ok {
+			invalidFields = append(invalidFields, "parent")
+		}
+	}
+	if cf.Progress != "" {
+		if _, ok := validFields[cf.Progress]; !ok {
+			invalidFields = append(invalidFields, "progress")
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(cf.Fields)) {
+		if _, ok := validFields[cf.Fields[key]]; !ok {
+			invalidFields = append(invalidFields, "fields."+key)
+		}
+	}
 
 	if len(invalidFields) > 0 {
 		results = append(results, checkResult{
@@ -353,6 +451,13 @@ func checkCustomFields(ctx context.Context, cfg *config.Config, client *clickup.
 		if cf.UpdatedAt != "" {
 			configuredCount++
 		}
+		if cf.Parent != "" {
+			configuredCount++
+		}
+		if cf.Progress != "" {
+			configuredCount++
+		}
+		configuredCount += len(cf.Fields)
 		results = append(results, checkResult{
 			Name:    "Custom fields valid",
 			Status:  checkPass,
@@ -369,21 +474,31 @@ func checkClickUpIntegration(ctx context.Context) checkSection {
 		Checks: make([]checkResult, 0),
 	}
 
-	// Check CLICKUP_TOKEN
+	// Check CLICKUP_TOKEN or a stored login
 	token, err := getClickUpToken()
 	if err != nil {
 		section.Checks = append(section.Checks, checkResult{
-			Name:    "CLICKUP_TOKEN set",
+			Name:    "ClickUp token",
 			Status:  checkFail,
-			Message: "Environment variable not set",
+			Message: err.Error(),
 		})
 		return section
 	}
 
+	tokenSource := "CLICKUP_TOKEN"
+	switch src := cfg.Beans.ClickUp.TokenSource; {
+	case os.Getenv("CLICKUP_TOKEN") != "":
+	case src.TokenCommand != "":
+		tokenSource = "token_command"
+	case src.TokenFile != "":
+		tokenSource = "token_file"
+	default:
+		tokenSource = "Stored OAuth login"
+	}
 	section.Checks = append(section.Checks, checkResult{
-		Name:    "CLICKUP_TOKEN set",
+		Name:    "ClickUp token",
 		Status:  checkPass,
-		Message: "Set",
+		Message: tokenSource,
 	})
 
 	if skipAPI {
@@ -396,7 +511,7 @@ func checkClickUpIntegration(ctx context.Context) checkSection {
 	}
 
 	// Validate token by fetching authorized user
-	client := clickup.NewClient(token)
+	client := newClickUpClient(ctx, token)
 	user, err := client.GetAuthorizedUser(ctx)
 	if err != nil {
 		section.Checks = append(section.Checks, checkResult{
@@ -446,6 +561,10 @@ func checkSyncState(ctx context.Context) checkSection {
 		return section
 	}
 
+	if checkFix {
+		section.Checks = append(section.Checks, pruneLegacySyncState(bp, allBeans)...)
+	}
+
 	// Count linked beans
 	linkedCount := 0
 	var linkedBeans []beans.Bean
@@ -463,11 +582,23 @@ func checkSyncState(ctx context.Context) checkSection {
 	})
 
 	if linkedCount == 0 {
+		if checkFix && !skipAPI {
+			section.Checks = append(section.Checks, adoptTasks(ctx, beansClient, allBeans, nil)...)
+		}
 		return section
 	}
 
-	// Check for stale syncs (>7 days)
-	staleThreshold := time.Now().AddDate(0, 0, -7)
+	// Check for stale syncs (older than stale_after)
+	staleAfter, err := cfg.GetStaleAfter()
+	if err != nil {
+		section.Checks = append(section.Checks, checkResult{
+			Name:    "Stale syncs",
+			Status:  checkFail,
+			Message: err.Error(),
+		})
+		staleAfter = config.DefaultStaleAfter
+	}
+	staleThreshold := time.Now().Add(-staleAfter)
 	staleCount := 0
 	for _, b := range linkedBeans {
 		syncedAt := b.GetExtensionTime(beans.PluginClickUp, beans.ExtKeySyncedAt)
@@ -480,7 +611,7 @@ func checkSyncState(ctx context.Context) checkSection {
 		section.Checks = append(section.Checks, checkResult{
 			Name:    "Stale syncs",
 			Status:  checkWarn,
-			Message: fmt.Sprintf("%d beans have stale sync (>7 days)", staleCount),
+			Message: fmt.Sprintf("%d beans have stale sync (>%s); see beanup stale", staleCount, formatAge(staleAfter)),
 		})
 	}
 
@@ -488,20 +619,58 @@ func checkSyncState(ctx context.Context) checkSection {
 	if !skipAPI {
 		token, _ := getClickUpToken()
 		if token != "" {
-			client := clickup.NewClient(token)
+			client := newClickUpClient(ctx, token)
 			missingCount := 0
+			cleared := make(map[string]bool)
+
+			// Epics synced as lists have no task to look up
+			linkedTasks := clickupLinked(linkedBeans)
+			taskIDs := make([]string, len(linkedTasks))
+			for i, b := range linkedTasks {
+				taskIDs[i] = b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID)
+			}
+			lookups, err := verifyTasks(ctx, client, cfg.Beans.ClickUp.ListID, taskIDs)
+			if err != nil {
+				section.Checks = append(section.Checks, checkResult{
+					Name:    "Task exists",
+					Status:  checkWarn,
+					Message: fmt.Sprintf("Cannot verify linked tasks: %v", err),
+				})
+				return section
+			}
 
-			for _, b := range linkedBeans {
+			for _, b := range linkedTasks {
 				taskID := b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID)
-				_, err := client.GetTask(ctx, taskID)
+				err := lookups[taskID]
+				if errors.Is(err, clickup.ErrTaskNotFound) && checkFix {
+					if err := beansClient.RemoveExtensionData(b.ID, beans.PluginClickUp); err != nil {
+						section.Checks = append(section.Checks, checkResult{
+							Name:    "Clear deleted task link",
+							Status:  checkWarn,
+							Message: fmt.Sprintf("%s → %s: %v", b.ID, taskID, err),
+						})
+						continue
+					}
+					cleared[b.ID] = true
+					section.Checks = append(section.Checks, checkResult{
+						Name:    "Cleared deleted task link",
+						Status:  checkFixed,
+						Message: fmt.Sprintf("%s → %s", b.ID, taskID),
+					})
+					continue
+				}
 				if err != nil {
 					missingCount++
+					msg := fmt.Sprintf("%s → %s: not found", b.ID, taskID)
+					if !errors.Is(err, clickup.ErrTaskNotFound) {
+						msg = fmt.Sprintf("%s → %s: %v", b.ID, taskID, err)
+					}
 					// Only report first few missing for brevity
 					if missingCount <= 3 {
 						section.Checks = append(section.Checks, checkResult{
 							Name:    "Task exists",
 							Status:  checkWarn,
-							Message: fmt.Sprintf("%s → %s: not found", b.ID, taskID),
+							Message: msg,
 						})
 					}
 				}
@@ -511,7 +680,7 @@ func checkSyncState(ctx context.Context) checkSection {
 				section.Checks = append(section.Checks, checkResult{
 					Name:    "All linked tasks exist",
 					Status:  checkPass,
-					Message: fmt.Sprintf("Verified %d tasks", linkedCount),
+					Message: fmt.Sprintf("Verified %d tasks", len(linkedTasks)-len(cleared)),
 				})
 			} else if missingCount > 3 {
 				section.Checks = append(section.Checks, checkResult{
@@ -520,12 +689,529 @@ func checkSyncState(ctx context.Context) checkSection {
 					Message: fmt.Sprintf("...and %d more", missingCount-3),
 				})
 			}
+
+			if checkFix {
+				section.Checks = append(section.Checks, adoptTasks(ctx, beansClient, allBeans, cleared)...)
+			}
 		}
 	}
 
 	return section
 }
 
+// verifyTasks reports which of taskIDs exist, returning the lookup error
+// for each (nil if the task exists). Tasks in listID are confirmed with one
+// paginated list request; any not found there, such as tasks moved to
+// another list, are fetched individually with bounded concurrency. An
+// authorization or rate limit error aborts the whole check.
+func verifyTasks(ctx context.Context, client *clickup.Client, listID string, taskIDs []string) (map[string]error, error) {
+	results := make(map[string]error, len(taskIDs))
+
+	inList := make(map[string]bool)
+	if listID != "" {
+		tasks, err := client.GetListTasks(ctx, listID)
+		if errors.Is(err, clickup.ErrUnauthorized) || errors.Is(err, clickup.ErrRateLimited) {
+			return nil, err
+		}
+		// Any other failure just means every task is fetched individually
+		for _, t := range tasks {
+			inList[t.ID] = true
+		}
+	}
+
+	var pending []string
+	for _, id := range taskIDs {
+		if inList[id] {
+			results[id] = nil
+		} else if _, seen := results[id]; !seen {
+			results[id] = nil
+			pending = append(pending, id)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex // protects results and firstErr
+		firstErr error
+	)
+	sem := make(chan struct{}, statusFetchConcurrency)
+	for _, id := range pending {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			_, err := client.GetTask(ctx, id)
+			mu.Lock()
+			defer mu.Unlock()
+			if errors.Is(err, clickup.ErrUnauthorized) || errors.Is(err, clickup.ErrRateLimited) {
+				// Further lookups would fail the same way
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			results[id] = err
+		})
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return results, nil
+}
+
+// checkMappingCoverage reports statuses, priorities, and types used by local
+// beans that have no mapping. With API access, it suggests list statuses and
+// task types with similar names. It reports false if beans can't be listed.
+func checkMappingCoverage(ctx context.Context) (checkSection, bool) {
+	section := checkSection{Name: "Mapping Coverage"}
+
+	allBeans, err := beans.NewClient(getBeansPath()).List()
+	if err != nil {
+		return section, false
+	}
+
+	var (
+		listStatuses []string
+		customItems  []clickup.CustomItem
+	)
+	if token, _ := getClickUpToken(); !skipAPI && token != "" {
+		client := newClickUpClient(ctx, token)
+		if listID := cfg.Beans.ClickUp.ListID; listID != "" {
+			if list, err := client.GetList(ctx, listID); err == nil {
+				for _, st := range list.Statuses {
+					listStatuses = append(listStatuses, st.Status)
+				}
+			}
+		}
+		if len(cfg.Beans.ClickUp.TypeMapping) > 0 {
+			customItems, _ = getCustomItems(ctx, client)
+		}
+	}
+
+	section.Checks = mappingCoverage(cfg, allBeans, listStatuses, customItems)
+	return section, true
+}
+
+// mappingCoverage checks that every status, priority, and (if a type
+// mapping is configured) type used by allBeans maps to ClickUp, as sync
+// would resolve it, suggesting similarly named list statuses and task
+// types for the gaps.
+func mappingCoverage(cfg *config.Config, allBeans []beans.Bean, listStatuses []string, customItems []clickup.CustomItem) []checkResult {
+	used := func(value func(beans.Bean) string) (map[string]int, []string) {
+		counts := make(map[string]int)
+		for _, b := range allBeans {
+			if v := value(b); v != "" {
+				counts[v]++
+			}
+		}
+		return counts, slices.Sorted(maps.Keys(counts))
+	}
+	plural := func(n int) string {
+		if n == 1 {
+			return "1 bean"
+		}
+		return fmt.Sprintf("%d beans", n)
+	}
+	var results []checkResult
+
+	// Statuses, falling back to the defaults as sync does
+	counts, statuses := used(func(b beans.Bean) string { return b.Status })
+	unmapped := 0
+	for _, st := range statuses {
+		if _, ok := cfg.Beans.ClickUp.StatusMapping[st]; ok {
+			continue
+		}
+		if _, ok := config.DefaultStatusMapping[st]; ok {
+			continue
+		}
+		unmapped++
+		msg := fmt.Sprintf("%s (%s) won't change task status", st, plural(counts[st]))
+		if match := closestName(st, listStatuses); match != "" {
+			msg += fmt.Sprintf("; try status_mapping %s: %q", st, match)
+		}
+		results = append(results, checkResult{Name: "Unmapped status", Status: checkWarn, Message: msg})
+	}
+	if unmapped == 0 {
+		results = append(results, checkResult{
+			Name:    "Bean statuses mapped",
+			Status:  checkPass,
+			Message: fmt.Sprintf("%d in use", len(statuses)),
+		})
+	}
+
+	// Priorities
+	counts, priorities := used(func(b beans.Bean) string { return b.Priority })
+	unmapped = 0
+	for _, p := range priorities {
+		if _, ok := cfg.GetPriorityMapping()[p]; ok {
+			continue
+		}
+		if _, ok := config.DefaultPriorityMapping[p]; ok {
+			continue
+		}
+		unmapped++
+		results = append(results, checkResult{
+			Name:    "Unmapped priority",
+			Status:  checkWarn,
+			Message: fmt.Sprintf("%s (%s) won't set task priority; add it to priority_mapping (1=urgent, 2=high, 3=normal, 4=low)", p, plural(counts[p])),
+		})
+	}
+	if unmapped == 0 {
+		results = append(results, checkResult{
+			Name:    "Bean priorities mapped",
+			Status:  checkPass,
+			Message: fmt.Sprintf("%d in use", len(priorities)),
+		})
+	}
+
+	// Types only matter once a type mapping is in use
+	if len(cfg.Beans.ClickUp.TypeMapping) == 0 {
+		return results
+	}
+	itemNames := make([]string, len(customItems))
+	itemIDs := make(map[string]int, len(customItems))
+	for i, item := range customItems {
+		itemNames[i] = item.Name
+		itemIDs[item.Name] = item.ID
+	}
+	counts, types := used(func(b beans.Bean) string { return b.Type })
+	unmapped = 0
+	for _, t := range types {
+		if _, ok := cfg.Beans.ClickUp.TypeMapping[t]; ok {
+			continue
+		}
+		unmapped++
+		msg := fmt.Sprintf("%s (%s) will create regular tasks", t, plural(counts[t]))
+		if match := closestName(t, itemNames); match != "" {
+			msg += fmt.Sprintf("; try type_mapping %s: %d (%s)", t, itemIDs[match], match)
+		}
+		results = append(results, checkResult{Name: "Unmapped type", Status: checkWarn, Message: msg})
+	}
+	if unmapped == 0 {
+		results = append(results, checkResult{
+			Name:    "Bean types mapped",
+			Status:  checkPass,
+			Message: fmt.Sprintf("%d in use", len(types)),
+		})
+	}
+	return results
+}
+
+// closestName returns the candidate most similar to name, ignoring case and
+// punctuation, or "" if none is reasonably close. A candidate containing
+// the name (or contained by it) counts as close.
+func closestName(name string, candidates []string) string {
+	target := normalizeTitle(name)
+	best, bestScore := "", 0.0
+	for _, c := range candidates {
+		n := normalizeTitle(c)
+		score := titleSimilarity(target, n)
+		if target != "" && n != "" && (strings.Contains(n, target) || strings.Contains(target, n)) {
+			score = max(score, 0.75)
+		}
+		if score >= 0.5 && score > bestScore {
+			best, bestScore = c, score
+		}
+	}
+	return best
+}
+
+// checkOrphanTasks scans the list for tasks whose Bean ID custom field names
+// a bean that doesn't exist locally, e.g. one deleted or renamed. It reports
+// false when the check can't run (offline, or no bean_id field mapped).
+func checkOrphanTasks(ctx context.Context) (checkSection, bool) {
+	section := checkSection{
+		Name:   "ClickUp Tasks",
+		Checks: make([]checkResult, 0),
+	}
+
+	cf := cfg.Beans.ClickUp.CustomFields
+	if skipAPI || cf == nil || cf.BeanID == "" || cfg.Beans.ClickUp.ListID == "" {
+		return section, false
+	}
+	token, err := getClickUpToken()
+	if err != nil {
+		return section, false
+	}
+
+	allBeans, err := beans.NewClient(getBeansPath()).List()
+	if err != nil {
+		return section, false
+	}
+	exists := make(map[string]bool, len(allBeans))
+	for _, b := range allBeans {
+		exists[b.ID] = true
+	}
+
+	tasks, err := newClickUpClient(ctx, token).GetListTasks(ctx, cfg.Beans.ClickUp.ListID)
+	if err != nil {
+		section.Checks = append(section.Checks, checkResult{
+			Name:    "Tasks have local beans",
+			Status:  checkWarn,
+			Message: fmt.Sprintf("Cannot list tasks: %v", err),
+		})
+		return section, true
+	}
+
+	tagged, orphans := 0, 0
+	for _, task := range tasks {
+		beanID := task.CustomFieldString(cf.BeanID)
+		if beanID == "" {
+			continue
+		}
+		tagged++
+		if exists[beanID] {
+			continue
+		}
+		orphans++
+		// Only report first few orphans for brevity
+		if orphans <= 3 {
+			section.Checks = append(section.Checks, checkResult{
+				Name:    "Orphaned task",
+				Status:  checkWarn,
+				Message: fmt.Sprintf("%s %q → %s: bean not found", task.ID, task.Name, beanID),
+			})
+		}
+	}
+
+	switch {
+	case orphans == 0:
+		section.Checks = append(section.Checks, checkResult{
+			Name:    "Tasks have local beans",
+			Status:  checkPass,
+			Message: fmt.Sprintf("%d tasks with a Bean ID", tagged),
+		})
+	default:
+		if orphans > 3 {
+			section.Checks = append(section.Checks, checkResult{
+				Name:    "Orphaned tasks",
+				Status:  checkWarn,
+				Message: fmt.Sprintf("...and %d more", orphans-3),
+			})
+		}
+		section.Checks = append(section.Checks, checkResult{
+			Name:    "Orphaned tasks",
+			Status:  checkWarn,
+			Message: "If the bean was renamed, run 'beanup link <bean-id> <task-id>'; otherwise close or delete the task in ClickUp",
+		})
+	}
+	return section, true
+}
+
+// checkTagMapping checks that tag_mapping names are tags of the task's
+// space. Sync creates missing ones, so they only warn; ClickUp compares tag
+// names without case, and so does this.
+func checkTagMapping(cfg *config.Config, spaceTags []clickup.Tag) checkResult {
+	existing := make(map[string]bool, len(spaceTags))
+	for _, t := range spaceTags {
+		existing[strings.ToLower(t.Name)] = true
+	}
+	mapping := cfg.Beans.ClickUp.TagMapping
+	var missing []string
+	for _, beanTag := range slices.Sorted(maps.Keys(mapping)) {
+		if !existing[strings.ToLower(mapping[beanTag])] {
+			missing = append(missing, fmt.Sprintf("%s=%q", beanTag, mapping[beanTag]))
+		}
+	}
+	if len(missing) > 0 {
+		return checkResult{
+			Name:    "Tag mapping valid",
+			Status:  checkWarn,
+			Message: fmt.Sprintf("Not tags of the space yet (sync creates them): %s", strings.Join(missing, ", ")),
+		}
+	}
+	return checkResult{
+		Name:    "Tag mapping valid",
+		Status:  checkPass,
+		Message: fmt.Sprintf("%d mappings", len(mapping)),
+	}
+}
+
+// checkPriorityMapping checks that priority_mapping values are ClickUp
+// priorities (1-4), and with space known, that the space has priorities
+// turned on and offers each one.
+func checkPriorityMapping(cfg *config.Config, space *clickup.Space) []checkResult {
+	priorityMapping := cfg.GetPriorityMapping()
+	beanPriorities := slices.Sorted(maps.Keys(priorityMapping))
+	invalidPriorities := []string{}
+	for _, beanPriority := range beanPriorities {
+		if clickupPriority := priorityMapping[beanPriority]; clickupPriority < 1 || clickupPriority > 4 {
+			invalidPriorities = append(invalidPriorities, fmt.Sprintf("%s=%d", beanPriority, clickupPriority))
+		}
+	}
+	if len(invalidPriorities) > 0 && checkFix {
+		return fixPriorityMapping(cfg)
+	}
+	if len(invalidPriorities) > 0 {
+		return []checkResult{{
+			Name:    "Priority mapping valid",
+			Status:  checkWarn,
+			Message: fmt.Sprintf("Invalid priorities (must be 1-4): %v", invalidPriorities),
+		}}
+	}
+
+	if space != nil && space.Features != nil {
+		priorities := space.Features.Priorities
+		if !priorities.Enabled {
+			return []checkResult{{
+				Name:    "Priority mapping valid",
+				Status:  checkWarn,
+				Message: fmt.Sprintf("Priorities are turned off in space %s, so tasks get none; enable the Priority ClickApp", space.Name),
+			}}
+		}
+		available := make(map[int]string, len(priorities.Priorities))
+		for _, p := range priorities.Priorities {
+			available[p.ID] = p.Priority
+		}
+		var missing []string
+		for _, beanPriority := range beanPriorities {
+			if clickupPriority := priorityMapping[beanPriority]; available[clickupPriority] == "" {
+				missing = append(missing, fmt.Sprintf("%s=%d", beanPriority, clickupPriority))
+			}
+		}
+		if len(missing) > 0 {
+			var offered []string
+			for _, id := range slices.Sorted(maps.Keys(available)) {
+				offered = append(offered, fmt.Sprintf("%d=%s", id, available[id]))
+			}
+			return []checkResult{{
+				Name:    "Priority mapping valid",
+				Status:  checkFail,
+				Message: fmt.Sprintf("Not priorities of space %s: %v (it has %s)", space.Name, missing, strings.Join(offered, ", ")),
+			}}
+		}
+	}
+
+	return []checkResult{{
+		Name:    "Priority mapping valid",
+		Status:  checkPass,
+		Message: fmt.Sprintf("%d mappings", len(priorityMapping)),
+	}}
+}
+
+// fixPriorityMapping clamps configured priorities outside ClickUp's 1-4
+// into range in the config file.
+func fixPriorityMapping(cfg *config.Config) []checkResult {
+	var results []checkResult
+	mapping := cfg.Beans.ClickUp.PriorityMapping
+	for _, beanPriority := range slices.Sorted(maps.Keys(mapping)) {
+		clickupPriority := mapping[beanPriority]
+		if clickupPriority >= 1 && clickupPriority <= 4 {
+			continue
+		}
+		fixed := min(max(clickupPriority, 1), 4)
+		key := "clickup.priority_mapping." + beanPriority
+		if err := config.Set(cfg.Path, key, strconv.Itoa(fixed)); err != nil {
+			results = append(results, checkResult{
+				Name:    "Priority mapping valid",
+				Status:  checkWarn,
+				Message: fmt.Sprintf("%s=%d (must be 1-4), not fixed: %v", beanPriority, clickupPriority, err),
+			})
+			continue
+		}
+		results = append(results, checkResult{
+			Name:    "Normalized priority mapping",
+			Status:  checkFixed,
+			Message: fmt.Sprintf("%s: %d → %d", beanPriority, clickupPriority, fixed),
+		})
+	}
+	return results
+}
+
+// pruneLegacySyncState removes .sync.json entries for beans that no longer
+// exist.
+func pruneLegacySyncState(beansPath string, allBeans []beans.Bean) []checkResult {
+	if _, err := os.Stat(filepath.Join(beansPath, syncstate.SyncFileName)); err != nil {
+		return nil
+	}
+	store, err := syncstate.Load(beansPath)
+	if err != nil {
+		return []checkResult{{Name: "Prune .sync.json", Status: checkWarn, Message: err.Error()}}
+	}
+
+	exists := make(map[string]bool, len(allBeans))
+	for _, b := range allBeans {
+		exists[b.ID] = true
+	}
+	var removed []string
+	for beanID := range store.GetAllBeans() {
+		if !exists[beanID] {
+			store.Clear(beanID)
+			removed = append(removed, beanID)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	if err := store.Save(); err != nil {
+		return []checkResult{{Name: "Prune .sync.json", Status: checkWarn, Message: err.Error()}}
+	}
+	slices.Sort(removed)
+	return []checkResult{{
+		Name:    "Removed .sync.json entries for missing beans",
+		Status:  checkFixed,
+		Message: strings.Join(removed, ", "),
+	}}
+}
+
+// adoptTasks links unlinked beans (including those whose links were just
+// cleared) to tasks in the list whose Bean ID custom field names them.
+func adoptTasks(ctx context.Context, beansClient *beans.Client, allBeans []beans.Bean, cleared map[string]bool) []checkResult {
+	cf := cfg.Beans.ClickUp.CustomFields
+	if cf == nil || cf.BeanID == "" || cfg.Beans.ClickUp.ListID == "" {
+		return nil
+	}
+	token, err := getClickUpToken()
+	if err != nil {
+		return nil
+	}
+
+	unlinked := make(map[string]bool)
+	for _, b := range allBeans {
+		if cleared[b.ID] || b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID) == "" {
+			unlinked[b.ID] = true
+		}
+	}
+	if len(unlinked) == 0 {
+		return nil
+	}
+
+	tasks, err := newClickUpClient(ctx, token).GetListTasks(ctx, cfg.Beans.ClickUp.ListID)
+	if err != nil {
+		return []checkResult{{Name: "Adopt tasks by Bean ID", Status: checkWarn, Message: err.Error()}}
+	}
+
+	var results []checkResult
+	for _, task := range tasks {
+		beanID := task.CustomFieldString(cf.BeanID)
+		if !unlinked[beanID] {
+			continue
+		}
+		// Link only; leaving synced_at unset makes the next sync push the bean
+		if err := beansClient.SetExtensionData(beanID, beans.PluginClickUp, map[string]any{beans.ExtKeyTaskID: task.ID}); err != nil {
+			results = append(results, checkResult{
+				Name:    "Adopt task",
+				Status:  checkWarn,
+				Message: fmt.Sprintf("%s → %s: %v", beanID, task.ID, err),
+			})
+			continue
+		}
+		delete(unlinked, beanID)
+		results = append(results, checkResult{
+			Name:    "Adopted task by Bean ID",
+			Status:  checkFixed,
+			Message: fmt.Sprintf("%s → %s", beanID, task.ID),
+		})
+	}
+	return results
+}
+
 func printCheckOutput(output checkOutput) {
 	for _, section := range output.Sections {
 		_, _ = colorBold.Println(section.Name)
@@ -537,6 +1223,8 @@ func printCheckOutput(output checkOutput) {
 				_, _ = colorYellow.Print("  ⚠ ")
 			case checkFail:
 				_, _ = colorRed.Print("  ✗ ")
+			case checkFixed:
+				_, _ = colorCyan.Print("  ↻ ")
 			}
 
 			fmt.Print(check.Name)
@@ -559,5 +1247,9 @@ func printCheckOutput(output checkOutput) {
 		fmt.Print(", ")
 		_, _ = colorRed.Printf("%d failed", output.Summary.Failed)
 	}
+	if output.Summary.Fixed > 0 {
+		fmt.Print(", ")
+		_, _ = colorCyan.Printf("%d fixed", output.Summary.Fixed)
+	}
 	fmt.Println()
 }
@@ -1,8 +1,20 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
 
+	"github.com/toba/bean-me-up/internal/beans"
 	"github.com/toba/bean-me-up/internal/clickup"
 	"github.com/toba/bean-me-up/internal/config"
 )
@@ -154,3 +166,162 @@ func TestCheckStatusTypes(t *testing.T) {
 		t.Errorf("checkFail should be 'fail', got %q", checkFail)
 	}
 }
+
+func TestFixPriorityMapping(t *testing.T) {
+	path := filepath.Join(t.TempDir(), config.BeansConfigFileName)
+	content := `extensions:
+  clickup:
+    list_id: "123"
+    priority_mapping:
+      critical: 0
+      high: 2
+      low: 7
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadFromBeansYML(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := fixPriorityMapping(cfg)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 repairs, got %+v", results)
+	}
+	for _, r := range results {
+		if r.Status != checkFixed {
+			t.Errorf("expected fixed, got %s: %s", r.Status, r.Message)
+		}
+	}
+	// Repairs are reported in a stable order
+	if !strings.HasPrefix(results[0].Message, "critical:") || !strings.HasPrefix(results[1].Message, "low:") {
+		t.Errorf("repairs out of order: %+v", results)
+	}
+
+	fixed, err := config.LoadFromBeansYML(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"critical": 1, "high": 2, "low": 4}
+	if !maps.Equal(fixed.Beans.ClickUp.PriorityMapping, want) {
+		t.Errorf("priority_mapping = %v, want %v", fixed.Beans.ClickUp.PriorityMapping, want)
+	}
+}
+
+func TestCheckPriorityMapping(t *testing.T) {
+	cfg := &config.Config{Beans: config.BeansWrapper{ClickUp: config.ClickUpConfig{
+		PriorityMapping: map[string]int{"critical": 1, "high": 2, "normal": 3, "low": 4},
+	}}}
+	space := func(enabled bool, ids ...int) *clickup.Space {
+		s := &clickup.Space{Name: "Eng", Features: &clickup.SpaceFeatures{}}
+		s.Features.Priorities.Enabled = enabled
+		for _, id := range ids {
+			s.Features.Priorities.Priorities = append(s.Features.Priorities.Priorities, clickup.SpacePriority{ID: id, Priority: fmt.Sprint("p", id)})
+		}
+		return s
+	}
+
+	tests := []struct {
+		name  string
+		space *clickup.Space
+		want  checkStatus
+	}{
+		{"offline", nil, checkPass},
+		{"all priorities", space(true, 1, 2, 3, 4), checkPass},
+		{"priorities off", space(false), checkWarn},
+		{"missing priority", space(true, 1, 3, 4), checkFail},
+	}
+	for _, tt := range tests {
+		results := checkPriorityMapping(cfg, tt.space)
+		if len(results) != 1 || results[0].Status != tt.want {
+			t.Errorf("%s: %+v, want %s", tt.name, results, tt.want)
+		}
+	}
+}
+
+func TestCheckTagMapping(t *testing.T) {
+	cfg := &config.Config{Beans: config.BeansWrapper{ClickUp: config.ClickUpConfig{
+		TagMapping: map[string]string{"backend": "Back End", "ui": "frontend"},
+	}}}
+
+	tests := []struct {
+		name string
+		tags []clickup.Tag
+		want checkStatus
+	}{
+		{"all tags", []clickup.Tag{{Name: "back end"}, {Name: "frontend"}}, checkPass},
+		{"missing tag", []clickup.Tag{{Name: "frontend"}}, checkWarn},
+		{"no tags", nil, checkWarn},
+	}
+	for _, tt := range tests {
+		if got := checkTagMapping(cfg, tt.tags); got.Status != tt.want {
+			t.Errorf("%s: %+v, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyTasks(t *testing.T) {
+	var single atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/list/") {
+			_, _ = w.Write([]byte(`{"tasks":[{"id":"t1"},{"id":"t2"}],"last_page":true}`))
+			return
+		}
+		single.Add(1)
+		id := strings.TrimPrefix(r.URL.Path, "/task/")
+		if id == "gone" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"err":"Task not found","ECODE":"ITEM_013"}`))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"id":%q}`, id)
+	}))
+	defer server.Close()
+
+	client := clickup.NewClient("test", clickup.WithBaseURL(server.URL))
+	results, err := verifyTasks(context.Background(), client, "list1", []string{"t1", "t2", "moved", "gone"})
+	if err != nil {
+		t.Fatalf("verifyTasks() error = %v", err)
+	}
+	for _, id := range []string{"t1", "t2", "moved"} {
+		if results[id] != nil {
+			t.Errorf("results[%q] = %v, want nil", id, results[id])
+		}
+	}
+	if !errors.Is(results["gone"], clickup.ErrTaskNotFound) {
+		t.Errorf("results[gone] = %v, want ErrTaskNotFound", results["gone"])
+	}
+	// Only tasks missing from the list are fetched individually
+	if got := single.Load(); got != 2 {
+		t.Errorf("individual lookups = %d, want 2", got)
+	}
+}
+
+func TestMappingCoverage(t *testing.T) {
+	cfg := &config.Config{Beans: config.BeansWrapper{ClickUp: config.ClickUpConfig{
+		TypeMapping: map[string]int{"bug": 1},
+	}}}
+	allBeans := []beans.Bean{
+		{ID: "a", Status: "todo", Type: "bug", Priority: "high"},
+		{ID: "b", Status: "blocked", Type: "feature", Priority: "someday"},
+		{ID: "c", Status: "blocked", Type: "bug"},
+	}
+	listStatuses := []string{"to do", "in progress", "blocked by design", "complete"}
+	customItems := []clickup.CustomItem{{ID: 7, Name: "Feature Request"}, {ID: 1, Name: "Bug"}}
+
+	var warnings []string
+	for _, r := range mappingCoverage(cfg, allBeans, listStatuses, customItems) {
+		if r.Status == checkWarn {
+			warnings = append(warnings, r.Message)
+		}
+	}
+	want := []string{
+		`blocked (2 beans) won't change task status; try status_mapping blocked: "blocked by design"`,
+		"someday (1 bean) won't set task priority; add it to priority_mapping (1=urgent, 2=high, 3=normal, 4=low)",
+		"feature (1 bean) will create regular tasks; try type_mapping feature: 7 (Feature Request)",
+	}
+	if !slices.Equal(warnings, want) {
+		t.Errorf("warnings =\n%q\nwant\n%q", warnings, want)
+	}
+}
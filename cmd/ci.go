@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ciFlag bool
+	failOn string
+)
+
+// Values accepted by --fail-on.
+const (
+	failOnErrors = "errors"
+	failOnDrift  = "drift"
+	failOnNever  = "never"
+)
+
+// addCIFlags registers --ci and --fail-on on a command that reports sync
+// health.
+func addCIFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&ciFlag, "ci", false, "CI mode: GitHub Actions annotations, no color or prompts (default when GITHUB_ACTIONS=true)")
+	cmd.Flags().StringVar(&failOn, "fail-on", "", "exit non-zero on: errors, drift (errors or beans out of sync), or never (default: errors in CI mode, otherwise never)")
+}
+
+// ciMode reports whether --ci was given or beanup is running in GitHub
+// Actions.
+func ciMode() bool {
+	return ciFlag || os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+// setupCI validates --fail-on and, in CI mode, disables color. It returns
+// the effective fail-on policy.
+func setupCI(cmd *cobra.Command) (string, error) {
+	policy := failOn
+	if policy == "" {
+		policy = failOnNever
+		if ciMode() {
+			policy = failOnErrors
+		}
+	}
+	switch policy {
+	case failOnErrors, failOnDrift, failOnNever:
+	default:
+		return "", fmt.Errorf("unknown --fail-on %q (use errors, drift, or never)", policy)
+	}
+	if ciMode() {
+		color.NoColor = true
+	}
+	// A policy failure is a finding, not a usage error
+	cmd.SilenceUsage = true
+	return policy, nil
+}
+
+// checkFailOn returns an error if the counts violate the fail-on policy,
+// with the exit code for the most serious violation. conflictCount is the
+// part of driftCount where both sides changed.
+func checkFailOn(policy string, errCount, driftCount, conflictCount int) error {
+	switch {
+	case policy == failOnNever:
+		return nil
+	case errCount > 0:
+		return withExitCode(exitPartialSync, fmt.Errorf("%d error(s) (--fail-on %s)", errCount, policy))
+	case policy == failOnDrift && conflictCount > 0:
+		return withExitCode(exitConflict, fmt.Errorf("%d bean(s) out of sync, %d in conflict (--fail-on %s)", driftCount, conflictCount, policy))
+	case policy == failOnDrift && driftCount > 0:
+		return withExitCode(exitDrift, fmt.Errorf("%d bean(s) out of sync (--fail-on %s)", driftCount, policy))
+	}
+	return nil
+}
+
+// writeAnnotation writes a GitHub Actions workflow command such as
+// "::error title=...::message". level is error, warning, or notice.
+func writeAnnotation(w io.Writer, level, title, message string) {
+	_, _ = fmt.Fprintf(w, "::%s title=%s::%s\n", level, escapeAnnotation(title, true), escapeAnnotation(message, false))
+}
+
+// escapeAnnotation escapes a workflow command value; properties also need
+// ":" and "," escaped.
+func escapeAnnotation(s string, property bool) string {
+	r := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	s = r.Replace(s)
+	if property {
+		s = strings.NewReplacer(":", "%3A", ",", "%2C").Replace(s)
+	}
+	return s
+}
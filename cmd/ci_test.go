@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckFailOn(t *testing.T) {
+	tests := []struct {
+		policy                   string
+		errors, drift, conflicts int
+		wantCode                 int
+	}{
+		{failOnNever, 3, 3, 1, exitOK},
+		{failOnErrors, 0, 3, 1, exitOK},
+		{failOnErrors, 1, 0, 0, exitPartialSync},
+		{failOnDrift, 0, 0, 0, exitOK},
+		{failOnDrift, 0, 1, 0, exitDrift},
+		{failOnDrift, 0, 2, 1, exitConflict},
+		{failOnDrift, 1, 2, 1, exitPartialSync},
+	}
+	for _, tt := range tests {
+		err := checkFailOn(tt.policy, tt.errors, tt.drift, tt.conflicts)
+		if got := ExitCode(err); got != tt.wantCode {
+			t.Errorf("checkFailOn(%s, %d, %d, %d) = %v, exit code %d, want %d", tt.policy, tt.errors, tt.drift, tt.conflicts, err, got, tt.wantCode)
+		}
+	}
+}
+
+func TestWriteAnnotation(t *testing.T) {
+	var out strings.Builder
+	writeAnnotation(&out, "error", "beanup: sync, ClickUp", "bean-a failed\n100% broken")
+	want := "::error title=beanup%3A sync%2C ClickUp::bean-a failed%0A100%25 broken\n"
+	if out.String() != want {
+		t.Errorf("writeAnnotation() = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/clickup"
+)
+
+var (
+	commentAssignee  string
+	commentNotifyAll bool
+)
+
+var commentCmd = &cobra.Command{
+	Use:   "comment <bean-id> <message>",
+	Short: "Comment on a bean's ClickUp task",
+	Long: `Posts a comment on the ClickUp task linked to a bean.
+
+@shortname in the message becomes a mention of the user mapped in
+extensions.clickup.users:
+
+  extensions:
+    clickup:
+      users:
+        ana: 12345678
+
+  beanup comment bean-abc1 "@ana can you review this?"
+
+Use --assignee (a short name or user ID) to make it an assigned comment
+that shows up as a to-do for that person.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		message := strings.Join(args[1:], " ")
+
+		users := cfg.Beans.ClickUp.Users
+		req := &clickup.CreateCommentRequest{
+			Comment:   clickup.BuildComment(message, users),
+			NotifyAll: commentNotifyAll,
+		}
+		if commentAssignee != "" {
+			id, err := resolveUser(commentAssignee, users)
+			if err != nil {
+				return err
+			}
+			req.Assignee = &id
+		}
+
+		beansClient := beans.NewClient(getBeansPath())
+		bean, err := beansClient.Get(args[0])
+		if err != nil {
+			return fmt.Errorf("bean not found: %s", args[0])
+		}
+		taskID := bean.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID)
+		if taskID == "" {
+			return fmt.Errorf("%s is not linked to a ClickUp task (run beanup sync or beanup link)", bean.ID)
+		}
+		if clickup.IsListRef(taskID) {
+			return fmt.Errorf("%s is an epic synced as a ClickUp list, not a task", bean.ID)
+		}
+
+		token, err := getClickUpToken()
+		if err != nil {
+			return err
+		}
+		commentID, err := newClickUpClient(ctx, token).CreateTaskComment(ctx, taskID, req)
+		if errors.Is(err, clickup.ErrTaskNotFound) {
+			return fmt.Errorf("task %s linked to %s no longer exists in ClickUp", taskID, bean.ID)
+		}
+		if err != nil {
+			return err
+		}
+
+		if jsonOut {
+			return outputJSON(map[string]string{
+				"bean_id":    bean.ID,
+				"task_id":    taskID,
+				"comment_id": commentID,
+			})
+		}
+		fmt.Printf("Commented on %s (%s)\n", taskID, bean.ID)
+		return nil
+	},
+}
+
+func init() {
+	commentCmd.Flags().StringVar(&commentAssignee, "assignee", "", "assign the comment to this user (short name from extensions.clickup.users, or user ID)")
+	commentCmd.Flags().BoolVar(&commentNotifyAll, "notify-all", false, "notify everyone watching the task, not just those mentioned")
+	rootCmd.AddCommand(commentCmd)
+}
+
+// resolveUser returns the ClickUp user ID for a short name from users, or
+// for a numeric ID given directly.
+func resolveUser(name string, users map[string]int) (int, error) {
+	if id, ok := users[strings.TrimPrefix(name, "@")]; ok {
+		return id, nil
+	}
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, nil
+	}
+	return 0, fmt.Errorf("unknown user %q (add it to extensions.clickup.users or pass a user ID)", name)
+}
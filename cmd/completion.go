@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/spf13/cobra"
+)
+
+// completionTimeout bounds the ClickUp request a completion may make, so
+// a slow network doesn't hang the shell.
+const completionTimeout = 3 * time.Second
+
+// Which beans completeBeanIDs offers.
+const (
+	completeAnyBean = iota
+	completeLinkedBeans
+	completeUnlinkedBeans
+)
+
+// completeBeanIDs completes the bean ID arguments of a command taking at
+// most maxArgs of them (0 for any number), skipping IDs already given.
+// Beans are read straight from their files, which is faster than the beans
+// CLI, and described by title and linked task.
+func completeBeanIDs(which, maxArgs int) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if maxArgs > 0 && len(args) >= maxArgs {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		beanList, err := beans.NewDir(getBeansPath()).List()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return beanCompletions(beanList, which, args, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// beanCompletions returns the IDs of beanList matching toComplete that
+// aren't in args, with descriptions.
+func beanCompletions(beanList []beans.Bean, which int, args []string, toComplete string) []cobra.Completion {
+	var completions []cobra.Completion
+	for _, b := range beanList {
+		taskID := b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID)
+		switch {
+		case which == completeLinkedBeans && taskID == "",
+			which == completeUnlinkedBeans && taskID != "",
+			!strings.HasPrefix(b.ID, toComplete),
+			slices.Contains(args, b.ID):
+			continue
+		}
+		desc := b.Title
+		if taskID != "" {
+			desc += " (task " + taskID + ")"
+		}
+		completions = append(completions, cobra.CompletionWithDesc(b.ID, desc))
+	}
+	return completions
+}
+
+// completeUnlinkedTaskIDs completes the task ID argument of link with the
+// tasks in the configured list that no bean is linked to yet. It needs a
+// ClickUp token; responses come from the response cache when fresh.
+func completeUnlinkedTaskIDs(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) != 1 || cfg == nil || cfg.Beans.ClickUp.ListID == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	token, err := getClickUpToken()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, completionTimeout)
+	defer cancel()
+	tasks, err := newClickUpClient(ctx, token).GetListTasks(ctx, cfg.Beans.ClickUp.ListID)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	linked := map[string]bool{}
+	if beanList, err := beans.NewDir(getBeansPath()).List(); err == nil {
+		for _, b := range beanList {
+			linked[b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID)] = true
+		}
+	}
+	var completions []cobra.Completion
+	for _, t := range tasks {
+		if !linked[t.ID] && strings.HasPrefix(t.ID, toComplete) {
+			completions = append(completions, cobra.CompletionWithDesc(t.ID, t.Name))
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	syncCmd.ValidArgsFunction = completeBeanIDs(completeAnyBean, 0)
+	statusCmd.ValidArgsFunction = completeBeanIDs(completeAnyBean, 0)
+	pullCmd.ValidArgsFunction = completeBeanIDs(completeLinkedBeans, 0)
+	unlinkCmd.ValidArgsFunction = completeBeanIDs(completeLinkedBeans, 0)
+	diffCmd.ValidArgsFunction = completeBeanIDs(completeLinkedBeans, 1)
+	openCmd.ValidArgsFunction = completeBeanIDs(completeLinkedBeans, 1)
+	taskCmd.ValidArgsFunction = completeBeanIDs(completeLinkedBeans, 1)
+	commentCmd.ValidArgsFunction = completeBeanIDs(completeLinkedBeans, 1)
+	linkCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completeBeanIDs(completeUnlinkedBeans, 1)(cmd, args, toComplete)
+		}
+		return completeUnlinkedTaskIDs(cmd, args, toComplete)
+	}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/spf13/cobra"
+)
+
+func TestBeanCompletions(t *testing.T) {
+	linked := func(id string) beans.Bean {
+		return beans.Bean{ID: id, Title: "Linked " + id, Extensions: map[string]map[string]any{
+			beans.PluginClickUp: {beans.ExtKeyTaskID: "task-" + id},
+		}}
+	}
+	beanList := []beans.Bean{
+		linked("bup-aaaa"),
+		linked("bup-abcd"),
+		{ID: "bup-bbbb", Title: "Unlinked"},
+	}
+
+	tests := []struct {
+		name       string
+		which      int
+		args       []string
+		toComplete string
+		want       []cobra.Completion
+	}{
+		{"any", completeAnyBean, nil, "", []cobra.Completion{"bup-aaaa\tLinked bup-aaaa (task task-bup-aaaa)", "bup-abcd\tLinked bup-abcd (task task-bup-abcd)", "bup-bbbb\tUnlinked"}},
+		{"linked prefix", completeLinkedBeans, nil, "bup-a", []cobra.Completion{"bup-aaaa\tLinked bup-aaaa (task task-bup-aaaa)", "bup-abcd\tLinked bup-abcd (task task-bup-abcd)"}},
+		{"skips given", completeLinkedBeans, []string{"bup-aaaa"}, "", []cobra.Completion{"bup-abcd\tLinked bup-abcd (task task-bup-abcd)"}},
+		{"unlinked", completeUnlinkedBeans, nil, "", []cobra.Completion{"bup-bbbb\tUnlinked"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := beanCompletions(beanList, tt.which, tt.args, tt.toComplete); !slices.Equal(got, tt.want) {
+				t.Errorf("beanCompletions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/toba/bean-me-up/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect beanup configuration",
+}
+
+var configLintCmd = &cobra.Command{
+	Use:   "lint [file...]",
+	Short: "Report unknown or misspelled config keys",
+	Long: `Checks the beanup sections of .beans.yml (and a legacy .beans.clickup.yml)
+for keys beanup doesn't recognize, such as staus_mapping, which would
+otherwise be silently ignored. Each is reported with its file and line and
+the closest known key.
+
+With no arguments, the config files found from the current directory are
+checked. Exits non-zero if any unknown key is found.`,
+	RunE: runConfigLint,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration and where each value came from",
+	Long: `Prints the configuration beanup will use after merging the project file,
+the user profile, flags, and built-in defaults. Each value is annotated
+with its source, e.g. ".beans.yml:12", "profile work", or "default".
+Secrets such as webhook URLs are redacted.
+
+Use --json for machine-readable output.`,
+	RunE: runConfigShow,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a value in .beans.yml",
+	Long: `Sets a key below extensions in .beans.yml, editing only the affected lines
+so comments and formatting are kept. Keys are dotted paths such as
+clickup.assignee or clickup.status_mapping.todo; lists are given
+comma-separated.
+
+  beanup config set clickup.assignee 12345
+  beanup config set clickup.sync_filter.exclude_status scrapped,completed`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := beansConfigPath()
+		if err != nil {
+			return err
+		}
+		if err := config.Set(path, args[0], args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("Set %s in %s\n", args[0], path)
+		return nil
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a value from .beans.yml",
+	Long: `Removes a key below extensions in .beans.yml, along with anything nested
+under it, so the default applies again.
+
+  beanup config unset clickup.assignee`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := beansConfigPath()
+		if err != nil {
+			return err
+		}
+		if err := config.Unset(path, args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Removed %s from %s\n", args[0], path)
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configLintCmd)
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+func runConfigLint(cmd *cobra.Command, args []string) error {
+	// Unknown keys are findings, not usage errors
+	cmd.SilenceUsage = true
+
+	files := args
+	if len(files) == 0 {
+		if cfgFile != "" {
+			files = []string{cfgFile}
+		} else {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("getting working directory: %w", err)
+			}
+			if files, err = config.FindConfigFiles(cwd); err != nil {
+				return err
+			}
+		}
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no %s or %s found", config.BeansConfigFileName, config.LegacyConfigFileName)
+	}
+
+	var found []config.UnknownKey
+	for _, file := range files {
+		unknown, err := config.Lint(file)
+		if err != nil {
+			return err
+		}
+		found = append(found, unknown...)
+	}
+
+	if jsonOut {
+		results := make([]map[string]any, len(found))
+		for i, u := range found {
+			results[i] = map[string]any{
+				"file":       u.File,
+				"line":       u.Line,
+				"column":     u.Column,
+				"section":    u.Section,
+				"key":        u.Key,
+				"suggestion": u.Suggestion,
+			}
+		}
+		if err := outputJSON(results); err != nil {
+			return err
+		}
+	} else {
+		for _, u := range found {
+			fmt.Println(u)
+		}
+	}
+
+	if len(found) > 0 {
+		return fmt.Errorf("%d unknown config key(s)", len(found))
+	}
+	if !jsonOut {
+		fmt.Printf("No unknown keys in %d file(s)\n", len(files))
+	}
+	return nil
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	extensions, sources, err := cfg.Explain()
+	if err != nil {
+		return err
+	}
+
+	beansPathSource := "default"
+	if beansPath != "" {
+		beansPathSource = "--beans-path flag"
+	} else if _, err := config.LoadBeansPath(configDir); err == nil {
+		beansPathSource = config.BeansConfigFileName
+	}
+	var profileName string
+	if cfg.Profile != nil {
+		profileName = cfg.Profile.Name
+	}
+
+	if jsonOut {
+		var values map[string]any
+		if err := extensions.Decode(&values); err != nil {
+			return err
+		}
+		return outputJSON(map[string]any{
+			"config_file": cfg.Path,
+			"profile":     profileName,
+			"beans_path":  map[string]string{"value": getBeansPath(), "source": beansPathSource},
+			"extensions":  values,
+			"sources":     sources,
+		})
+	}
+
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+	add := func(key string, value *yaml.Node) {
+		doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+	}
+	add("config_file", &yaml.Node{Kind: yaml.ScalarNode, Value: cfg.Path})
+	if profileName != "" {
+		add("profile", &yaml.Node{Kind: yaml.ScalarNode, Value: profileName})
+	}
+	add("beans_path", &yaml.Node{Kind: yaml.ScalarNode, Value: getBeansPath(), LineComment: beansPathSource})
+	add("extensions", extensions)
+
+	enc := yaml.NewEncoder(os.Stdout)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// beansConfigPath returns the .beans.yml found from the working directory.
+func beansConfigPath() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("getting working directory: %w", err)
+	}
+	files, err := config.FindConfigFiles(cwd)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range files {
+		if filepath.Base(f) == config.BeansConfigFileName {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("no %s found (searched from %s)", config.BeansConfigFileName, cwd)
+}
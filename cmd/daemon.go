@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/clickup"
+	"github.com/toba/bean-me-up/internal/lock"
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+// daemonPIDFileName is the lock file, recording its PID, that the daemon
+// holds in the beans directory while it runs.
+const daemonPIDFileName = ".beanup-daemon.pid"
+
+var (
+	daemonInterval time.Duration
+	daemonNoRunNow bool
+	daemonPull     bool
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run incremental syncs on a schedule",
+	Long: `Runs an incremental sync immediately and then every --interval until stopped,
+to every configured backend or the one --sink picks.
+
+Only beans that changed since their last sync are pushed each cycle, so an
+idle project costs a single beans CLI call per interval. A lock file in the
+beans directory, recording the daemon's PID, prevents two daemons from
+running against the same project.
+
+The daemon only pushes unless --pull is given, which also pulls fields
+changed in ClickUp back into beans after each sync, as beanup pull does.
+Use beanup serve to pull as soon as ClickUp sends a webhook instead.
+
+On SIGINT or SIGTERM the daemon stops scheduling new cycles and lets the
+current one finish and save its sync state. A second signal aborts the
+in-flight cycle immediately.
+
+Failed cycles are logged and retried at the next interval. Configure
+extensions.clickup.notifications to be alerted about errors.
+
+Each backend reads its token from the environment: CLICKUP_TOKEN,
+GITHUB_TOKEN, JIRA_API_TOKEN (plus JIRA_EMAIL or extensions.jira.email),
+LINEAR_API_KEY, GITLAB_TOKEN, AZURE_DEVOPS_PAT, or NOTION_TOKEN.`,
+	Args: cobra.NoArgs,
+	RunE: runDaemon,
+}
+
+func init() {
+	daemonCmd.Flags().DurationVar(&daemonInterval, "interval", 15*time.Minute, "Time between sync cycles")
+	daemonCmd.Flags().BoolVar(&daemonNoRunNow, "no-run-now", false, "Wait one interval before the first sync")
+	daemonCmd.Flags().BoolVar(&daemonPull, "pull", false, "Also pull ClickUp changes into beans each cycle")
+	daemonCmd.Flags().BoolVar(&syncNoRelationships, "no-relationships", false, "Skip syncing blocking relationships as dependencies")
+	daemonCmd.Flags().StringVar(&syncSink, "sink", "", "Sync only to this backend (default: every configured backend)")
+	rootCmd.AddCommand(daemonCmd)
+}
+
+func runDaemon(cmd *cobra.Command, args []string) error {
+	if daemonInterval < time.Minute {
+		return fmt.Errorf("--interval must be at least 1m (got %s)", daemonInterval)
+	}
+	sinkNames, err := selectedSinkNames()
+	if err != nil {
+		return err
+	}
+	if _, err := selectedSinks(cmd.Context()); err != nil {
+		return err
+	}
+	var pullClient *clickup.Client
+	if daemonPull {
+		if !slices.Contains(sinkNames, "clickup") {
+			return fmt.Errorf("--pull needs the clickup backend, but the daemon syncs to %s", sinkDisplayList(sinkNames))
+		}
+		token, err := getClickUpToken()
+		if err != nil {
+			return err
+		}
+		pullClient = newClickUpClient(cmd.Context(), token)
+	}
+
+	daemonLock, err := acquireDaemonLock(filepath.Join(getBeansPath(), daemonPIDFileName))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = daemonLock.Release() }()
+
+	// stop is closed on the first signal; cycleCtx is cancelled on the second.
+	cycleCtx, cancelCycle := context.WithCancel(cmd.Context())
+	defer cancelCycle()
+	stop := make(chan struct{})
+
+	sigCh := make(chan os.Signal, 2)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	go func() {
+		sig := <-sigCh
+		daemonLogf("received %s, finishing current cycle (signal again to abort)", sig)
+		close(stop)
+		<-sigCh
+		daemonLogf("aborting")
+		cancelCycle()
+	}()
+
+	daemonLogf("started (pid %d, interval %s, syncing to %s)", os.Getpid(), daemonInterval, sinkDisplayList(sinkNames))
+
+	ticker := time.NewTicker(daemonInterval)
+	defer ticker.Stop()
+
+	if !daemonNoRunNow {
+		runDaemonCycle(cycleCtx, pullClient)
+	}
+
+	for {
+		select {
+		case <-stop:
+			daemonLogf("stopped")
+			return nil
+		case <-ticker.C:
+			// select picks randomly among ready cases; a pending stop wins
+			select {
+			case <-stop:
+				daemonLogf("stopped")
+				return nil
+			default:
+			}
+			runDaemonCycle(cycleCtx, pullClient)
+		}
+	}
+}
+
+// runDaemonCycle runs one incremental sync and logs a one-line summary,
+// then, given a pullClient, pulls ClickUp changes into beans.
+func runDaemonCycle(ctx context.Context, pullClient *clickup.Client) {
+	runDaemonSync(ctx)
+	if pullClient != nil && ctx.Err() == nil {
+		if err := runDaemonPull(ctx, pullClient); err != nil {
+			daemonLogf("pull failed: %v", err)
+		}
+	}
+}
+
+// runDaemonPull pulls the fields changed in ClickUp into every linked bean
+// that isn't completed or scrapped, under the project lock.
+func runDaemonPull(ctx context.Context, client *clickup.Client) error {
+	projectLock, err := acquireProjectLock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = projectLock.Release() }()
+
+	beansClient := beans.NewClient(getBeansPath())
+	beanList, err := beansClient.ListWith(beans.ListOptions{ExcludeStatus: []string{"completed", "scrapped"}})
+	if err != nil {
+		return fmt.Errorf("getting beans: %w", err)
+	}
+	linked := clickupLinked(beanList)
+	if len(linked) == 0 {
+		return nil
+	}
+	changes, err := pullBeans(ctx, client, beansClient, linked, false, false, false)
+	if err != nil {
+		return err
+	}
+	logPulled(changes)
+	return nil
+}
+
+// runDaemonSync runs one incremental sync and logs a one-line summary.
+func runDaemonSync(ctx context.Context) {
+	start := time.Now()
+	results, message, err := runSync(ctx, nil, true)
+	switch {
+	case err != nil:
+		daemonLogf("sync failed: %v", err)
+	case message != "":
+		daemonLogf("%s", strings.ToLower(message))
+	default:
+		c := countResults(results)
+		daemonLogf("synced %d beans in %s: %d created, %d updated, %d unchanged, %d errors",
+			len(results), time.Since(start).Round(time.Millisecond), c.created, c.updated, c.unchanged, c.errors)
+		for _, r := range results {
+			switch {
+			case r.Error != nil:
+				daemonLogf("  error: %s - %v", r.BeanID, r.Error)
+			case verbose:
+				daemonLogf("  %s: %s %s%s", r.Action, r.BeanID, r.TaskURL, explainResult(r))
+			}
+		}
+	}
+}
+
+// resultCounts tallies sync results by action.
+type resultCounts struct {
+	created, updated, unchanged, skipped, errors int
+}
+
+func countResults(results []syncer.Result) resultCounts {
+	var c resultCounts
+	for _, r := range results {
+		switch r.Action {
+		case "created":
+			c.created++
+		case "updated":
+			c.updated++
+		case "unchanged":
+			c.unchanged++
+		case "skipped":
+			c.skipped++
+		case "error":
+			c.errors++
+		}
+	}
+	return c
+}
+
+// daemonLogf prints a timestamped daemon log line to stdout.
+func daemonLogf(format string, args ...any) {
+	fmt.Printf("%s %s\n", time.Now().UTC().Format(time.RFC3339), fmt.Sprintf(format, args...))
+}
+
+// acquireDaemonLock takes the daemon's lock file at path, which records
+// its PID. The operating system releases the lock if the daemon dies, so a
+// crash can't leave a stale file blocking the next start.
+func acquireDaemonLock(path string) (*lock.Lock, error) {
+	l, err := lock.TryAcquire(path)
+	if errors.Is(err, lock.ErrLocked) {
+		return nil, fmt.Errorf("daemon already running: %w", err)
+	}
+	return l, err
+}
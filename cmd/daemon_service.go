@@ -0,0 +1,450 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/toba/bean-me-up/internal/lock"
+)
+
+var (
+	serviceInterval time.Duration
+	serviceEnvFile  string
+	serviceNoStart  bool
+)
+
+var daemonInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install the daemon as a user service (systemd or launchd)",
+	Long: `Installs a per-user service that runs 'beanup daemon' for this project.
+
+On Linux a systemd user unit is written to ~/.config/systemd/user and enabled
+with 'systemctl --user enable --now'. On macOS a launchd agent is written to
+~/Library/LaunchAgents and loaded with 'launchctl load -w'.
+
+The service runs in the project directory (where .beans.yml lives) with
+the --profile, --config, and --beans-path given to install, syncing to the
+backend --sink picks and pulling with --pull. Use --env-file to point at a
+file with CLICKUP_TOKEN=... (and any other variables); services do not
+inherit your shell environment.`,
+	Args: cobra.NoArgs,
+	RunE: runDaemonInstall,
+}
+
+var daemonUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Stop and remove the daemon service for this project",
+	Args:  cobra.NoArgs,
+	RunE:  runDaemonUninstall,
+}
+
+var daemonStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the daemon service is installed and running",
+	Args:  cobra.NoArgs,
+	RunE:  runDaemonStatus,
+}
+
+func init() {
+	daemonInstallCmd.Flags().DurationVar(&serviceInterval, "interval", 15*time.Minute, "Time between sync cycles")
+	daemonInstallCmd.Flags().StringVar(&serviceEnvFile, "env-file", "", "File with environment variables (e.g. CLICKUP_TOKEN) for the service")
+	daemonInstallCmd.Flags().BoolVar(&serviceNoStart, "no-start", false, "Write the service file without enabling or starting it")
+	daemonInstallCmd.Flags().BoolVar(&daemonPull, "pull", false, "Also pull ClickUp changes into beans each cycle")
+	daemonInstallCmd.Flags().BoolVar(&syncNoRelationships, "no-relationships", false, "Skip syncing blocking relationships as dependencies")
+	daemonInstallCmd.Flags().StringVar(&syncSink, "sink", "", "Sync only to this backend (default: every configured backend)")
+	daemonCmd.AddCommand(daemonInstallCmd, daemonUninstallCmd, daemonStatusCmd)
+}
+
+// serviceSpec describes the service to install.
+type serviceSpec struct {
+	Name       string // systemd unit name or launchd label
+	Executable string
+	ProjectDir string
+	EnvFile    string
+	LogFile    string
+	Args       []string // beanup's arguments, starting with "daemon"
+}
+
+// serviceFilePath returns where the service file for name lives on this platform.
+func serviceFilePath(name string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("finding home directory: %w", err)
+	}
+	switch runtime.GOOS {
+	case "linux":
+		return filepath.Join(home, ".config", "systemd", "user", name+".service"), nil
+	case "darwin":
+		return filepath.Join(home, "Library", "LaunchAgents", name+".plist"), nil
+	default:
+		return "", fmt.Errorf("service installation is not supported on %s; run 'beanup daemon' under your own process manager", runtime.GOOS)
+	}
+}
+
+var unsafeServiceChars = regexp.MustCompile(`[^a-zA-Z0-9-]+`)
+
+// serviceName derives a stable, per-project service name.
+// A short hash of the path keeps projects with the same directory name apart.
+func serviceName(projectDir string) string {
+	base := strings.Trim(unsafeServiceChars.ReplaceAllString(strings.ToLower(filepath.Base(projectDir)), "-"), "-")
+	if base == "" {
+		base = "project"
+	}
+	sum := sha256.Sum256([]byte(projectDir))
+	suffix := hex.EncodeToString(sum[:])[:8]
+	if runtime.GOOS == "darwin" {
+		return "com.toba.beanup." + base + "-" + suffix
+	}
+	return "beanup-" + base + "-" + suffix
+}
+
+// daemonServiceArgs returns the arguments the service runs beanup with:
+// the daemon command and the flags given to install that choose the
+// project's config and what the daemon does. Paths are made absolute, as
+// they were given relative to where install ran.
+func daemonServiceArgs() ([]string, error) {
+	args := []string{"daemon", "--interval", serviceInterval.String()}
+	if profileName != "" {
+		args = append(args, "--profile", profileName)
+	}
+	for _, f := range []struct{ flag, path string }{{"--config", cfgFile}, {"--beans-path", beansPath}} {
+		if f.path == "" {
+			continue
+		}
+		abs, err := filepath.Abs(f.path)
+		if err != nil {
+			return nil, fmt.Errorf("resolving %s: %w", f.flag, err)
+		}
+		args = append(args, f.flag, abs)
+	}
+	if syncSink != "" {
+		args = append(args, "--sink", syncSink)
+	}
+	if daemonPull {
+		args = append(args, "--pull")
+	}
+	if syncNoRelationships {
+		args = append(args, "--no-relationships")
+	}
+	return args, nil
+}
+
+// projectDirectory returns the absolute directory holding the project config.
+func projectDirectory() (string, error) {
+	dir := configDir
+	if dir == "" {
+		var err error
+		if dir, err = os.Getwd(); err != nil {
+			return "", fmt.Errorf("getting working directory: %w", err)
+		}
+	}
+	return filepath.Abs(dir)
+}
+
+func runDaemonInstall(cmd *cobra.Command, args []string) error {
+	if serviceInterval < time.Minute {
+		return fmt.Errorf("--interval must be at least 1m (got %s)", serviceInterval)
+	}
+	if _, err := selectedSinkNames(); err != nil {
+		return err
+	}
+
+	projectDir, err := projectDirectory()
+	if err != nil {
+		return err
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding beanup executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+
+	beansDir, err := filepath.Abs(getBeansPath())
+	if err != nil {
+		return fmt.Errorf("resolving beans path: %w", err)
+	}
+
+	daemonArgs, err := daemonServiceArgs()
+	if err != nil {
+		return err
+	}
+
+	spec := serviceSpec{
+		Name:       serviceName(projectDir),
+		Executable: exe,
+		ProjectDir: projectDir,
+		LogFile:    filepath.Join(beansDir, ".beanup-daemon.log"),
+		Args:       daemonArgs,
+	}
+	if serviceEnvFile != "" {
+		if spec.EnvFile, err = filepath.Abs(serviceEnvFile); err != nil {
+			return fmt.Errorf("resolving --env-file: %w", err)
+		}
+		if _, err := os.Stat(spec.EnvFile); err != nil {
+			return fmt.Errorf("env file: %w", err)
+		}
+	} else if os.Getenv("CLICKUP_TOKEN") != "" {
+		fmt.Fprintln(os.Stderr, "Warning: no --env-file given; the service will not see CLICKUP_TOKEN from your shell.")
+	}
+
+	path, err := serviceFilePath(spec.Name)
+	if err != nil {
+		return err
+	}
+
+	var content string
+	if runtime.GOOS == "darwin" {
+		content, err = renderLaunchdPlist(spec)
+	} else {
+		content, err = renderSystemdUnit(spec)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	fmt.Printf("Wrote %s\n", path)
+
+	if serviceNoStart {
+		return nil
+	}
+
+	if runtime.GOOS == "darwin" {
+		err = runServiceCommand("launchctl", "load", "-w", path)
+	} else {
+		err = runServiceCommand("systemctl", "--user", "daemon-reload")
+		if err == nil {
+			err = runServiceCommand("systemctl", "--user", "enable", "--now", spec.Name+".service")
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Started %s\n", spec.Name)
+	return nil
+}
+
+func runDaemonUninstall(cmd *cobra.Command, args []string) error {
+	projectDir, err := projectDirectory()
+	if err != nil {
+		return err
+	}
+	name := serviceName(projectDir)
+	path, err := serviceFilePath(name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		fmt.Printf("No service installed for %s\n", projectDir)
+		return nil
+	}
+
+	// Stopping is best-effort: the service may already be stopped or unloaded
+	if runtime.GOOS == "darwin" {
+		_ = runServiceCommand("launchctl", "unload", "-w", path)
+	} else {
+		_ = runServiceCommand("systemctl", "--user", "disable", "--now", name+".service")
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("removing %s: %w", path, err)
+	}
+	if runtime.GOOS != "darwin" {
+		_ = runServiceCommand("systemctl", "--user", "daemon-reload")
+	}
+
+	fmt.Printf("Removed %s\n", path)
+	return nil
+}
+
+func runDaemonStatus(cmd *cobra.Command, args []string) error {
+	projectDir, err := projectDirectory()
+	if err != nil {
+		return err
+	}
+	name := serviceName(projectDir)
+	path, pathErr := serviceFilePath(name)
+
+	installed := false
+	if pathErr == nil {
+		if _, err := os.Stat(path); err == nil {
+			installed = true
+		}
+	}
+
+	serviceState := "not installed"
+	if installed {
+		serviceState = "installed"
+		if runtime.GOOS == "darwin" {
+			if err := exec.Command("launchctl", "list", name).Run(); err == nil {
+				serviceState = "loaded"
+			}
+		} else {
+			// is-active exits non-zero for inactive units but still prints the state
+			out, _ := exec.Command("systemctl", "--user", "is-active", name+".service").Output()
+			if state := strings.TrimSpace(string(out)); state != "" {
+				serviceState = state
+			}
+		}
+	}
+
+	pid, running := lock.Holder(filepath.Join(getBeansPath(), daemonPIDFileName))
+
+	if jsonOut {
+		result := map[string]any{
+			"service":   name,
+			"installed": installed,
+			"state":     serviceState,
+			"running":   running,
+		}
+		if pathErr != nil {
+			result["service_file_error"] = pathErr.Error()
+		} else {
+			result["service_file"] = path
+		}
+		if running {
+			result["pid"] = pid
+		}
+		return outputJSON(result)
+	}
+
+	fmt.Printf("Service: %s (%s)\n", name, serviceState)
+	if pathErr != nil {
+		fmt.Printf("  %v\n", pathErr)
+	} else if installed {
+		fmt.Printf("  File: %s\n", path)
+	}
+	if running {
+		fmt.Printf("Daemon:  running (pid %d)\n", pid)
+	} else {
+		fmt.Println("Daemon:  not running")
+	}
+	return nil
+}
+
+// runServiceCommand runs a service manager command, surfacing its output on failure.
+func runServiceCommand(name string, args ...string) error {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+const systemdUnitTemplate = `# Generated by: beanup daemon install
+[Unit]
+Description=beanup sync daemon for {{systemdText .ProjectDir}}
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=simple
+WorkingDirectory={{systemdText .ProjectDir}}
+{{- if .EnvFile}}
+EnvironmentFile={{systemdText .EnvFile}}
+{{- end}}
+ExecStart={{systemdArg .Executable}}{{range .Args}} {{systemdArg .}}{{end}}
+Restart=on-failure
+RestartSec=30
+
+[Install]
+WantedBy=default.target
+`
+
+const launchdPlistTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<!-- Generated by: beanup daemon install -->
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>{{xml .Name}}</string>
+	<key>WorkingDirectory</key>
+	<string>{{xml .ProjectDir}}</string>
+	<key>ProgramArguments</key>
+	<array>
+{{- if .EnvFile}}
+		<string>/bin/sh</string>
+		<string>-c</string>
+		<string>set -a; . "$0"; set +a; exec "$@"</string>
+		<string>{{xml .EnvFile}}</string>
+{{- end}}
+		<string>{{xml .Executable}}</string>
+{{- range .Args}}
+		<string>{{xml .}}</string>
+{{- end}}
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+	<key>StandardOutPath</key>
+	<string>{{xml .LogFile}}</string>
+	<key>StandardErrorPath</key>
+	<string>{{xml .LogFile}}</string>
+</dict>
+</plist>
+`
+
+func renderSystemdUnit(spec serviceSpec) (string, error) {
+	return renderServiceTemplate("systemd", systemdUnitTemplate, spec)
+}
+
+func renderLaunchdPlist(spec serviceSpec) (string, error) {
+	return renderServiceTemplate("launchd", launchdPlistTemplate, spec)
+}
+
+func renderServiceTemplate(name, text string, spec serviceSpec) (string, error) {
+	funcs := template.FuncMap{
+		"xml": func(s string) (string, error) {
+			var buf strings.Builder
+			err := xml.EscapeText(&buf, []byte(s))
+			return buf.String(), err
+		},
+		"systemdText": systemdText,
+		"systemdArg":  systemdArg,
+	}
+	tmpl, err := template.New(name).Funcs(funcs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing %s template: %w", name, err)
+	}
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, spec); err != nil {
+		return "", fmt.Errorf("rendering %s template: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// systemdText escapes the specifiers systemd expands in unit settings.
+func systemdText(s string) string {
+	return strings.ReplaceAll(s, "%", "%%")
+}
+
+// systemdArg quotes s as one ExecStart argument, so paths with spaces or
+// quotes stay whole and systemd doesn't expand specifiers or variables.
+func systemdArg(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%", "$", "$$")
+	return `"` + r.Replace(s) + `"`
+}
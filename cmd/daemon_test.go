@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/lock"
+)
+
+func TestAcquireDaemonLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), daemonPIDFileName)
+
+	l, err := acquireDaemonLock(path)
+	if err != nil {
+		t.Fatalf("acquireDaemonLock() error = %v", err)
+	}
+	if pid, running := lock.Holder(path); !running || pid != os.Getpid() {
+		t.Fatalf("Holder() = %d, %v, want %d", pid, running, os.Getpid())
+	}
+
+	// The lock is held, so a second daemon must refuse to start
+	if _, err := acquireDaemonLock(path); err == nil || !strings.Contains(err.Error(), "daemon already running") {
+		t.Fatalf("second acquire error = %v, want daemon already running", err)
+	}
+
+	if err := l.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if _, running := lock.Holder(path); running {
+		t.Error("released daemon lock reported running")
+	}
+}
+
+func TestAcquireDaemonLock_Stale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), daemonPIDFileName)
+
+	// A PID file left by a daemon that died doesn't hold the lock
+	if err := os.WriteFile(path, []byte(strconv.Itoa(1<<22-3)+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := acquireDaemonLock(path)
+	if err != nil {
+		t.Fatalf("expected stale PID file to be taken over, got %v", err)
+	}
+	defer func() { _ = l.Release() }()
+
+	if data, _ := os.ReadFile(path); strings.TrimSpace(string(data)) != strconv.Itoa(os.Getpid()) {
+		t.Errorf("PID file contains %q, want %d", data, os.Getpid())
+	}
+}
+
+func TestRenderServiceFiles(t *testing.T) {
+	spec := serviceSpec{
+		Name:       "beanup-demo-1234abcd",
+		Executable: "/opt/My Tools/beanup",
+		ProjectDir: "/home/me/R&D 100%",
+		EnvFile:    "/home/me/.config/beanup.env",
+		LogFile:    "/home/me/R&D 100%/.beans/.beanup-daemon.log",
+		Args:       []string{"daemon", "--interval", "15m0s", "--beans-path", "/home/me/R&D 100%/.beans", "--pull"},
+	}
+
+	unit, err := renderSystemdUnit(spec)
+	if err != nil {
+		t.Fatalf("renderSystemdUnit() error = %v", err)
+	}
+	for _, want := range []string{
+		"WorkingDirectory=/home/me/R&D 100%%\n",
+		"EnvironmentFile=/home/me/.config/beanup.env",
+		`ExecStart="/opt/My Tools/beanup" "daemon" "--interval" "15m0s" "--beans-path" "/home/me/R&D 100%%/.beans" "--pull"` + "\n",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("systemd unit missing %q\nGot:\n%s", want, unit)
+		}
+	}
+
+	plist, err := renderLaunchdPlist(spec)
+	if err != nil {
+		t.Fatalf("renderLaunchdPlist() error = %v", err)
+	}
+	if !strings.Contains(plist, "<key>WorkingDirectory</key>\n\t<string>/home/me/R&amp;D 100%</string>") {
+		t.Errorf("plist should XML-escape paths\nGot:\n%s", plist)
+	}
+	if !strings.Contains(plist, "<string>/home/me/.config/beanup.env</string>\n\t\t<string>/opt/My Tools/beanup</string>") {
+		t.Errorf("plist should source the env file\nGot:\n%s", plist)
+	}
+	if !strings.Contains(plist, "<string>--beans-path</string>\n\t\t<string>/home/me/R&amp;D 100%/.beans</string>\n\t\t<string>--pull</string>") {
+		t.Errorf("plist should pass the daemon flags\nGot:\n%s", plist)
+	}
+
+	spec.EnvFile = ""
+	unit, _ = renderSystemdUnit(spec)
+	if strings.Contains(unit, "EnvironmentFile") {
+		t.Error("systemd unit should omit EnvironmentFile when none is given")
+	}
+}
+
+func TestDaemonServiceArgs(t *testing.T) {
+	oldProfile, oldCfgFile, oldBeansPath := profileName, cfgFile, beansPath
+	oldSink, oldPull, oldNoRel, oldInterval := syncSink, daemonPull, syncNoRelationships, serviceInterval
+	defer func() {
+		profileName, cfgFile, beansPath = oldProfile, oldCfgFile, oldBeansPath
+		syncSink, daemonPull, syncNoRelationships, serviceInterval = oldSink, oldPull, oldNoRel, oldInterval
+	}()
+	profileName, cfgFile, beansPath = "work", "legacy.yml", "../beans"
+	syncSink, daemonPull, syncNoRelationships, serviceInterval = "github", true, true, 5*time.Minute
+
+	args, err := daemonServiceArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd, _ := os.Getwd()
+	want := []string{
+		"daemon", "--interval", "5m0s", "--profile", "work",
+		"--config", filepath.Join(wd, "legacy.yml"), "--beans-path", filepath.Join(filepath.Dir(wd), "beans"),
+		"--sink", "github", "--pull", "--no-relationships",
+	}
+	if !slices.Equal(args, want) {
+		t.Errorf("args = %q\nwant %q", args, want)
+	}
+}
+
+func TestServiceName_Stable(t *testing.T) {
+	a := serviceName("/home/me/My Project")
+	if a != serviceName("/home/me/My Project") {
+		t.Error("service name should be deterministic")
+	}
+	if a == serviceName("/work/My Project") {
+		t.Error("projects with the same directory name should get distinct service names")
+	}
+	if strings.ContainsAny(a, " /") {
+		t.Errorf("service name %q contains unsafe characters", a)
+	}
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/clickup"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <bean-id>",
+	Short: "Compare a bean with its ClickUp task field by field",
+	Long: `Fetches the ClickUp task linked to a bean and compares the fields sync
+manages: title, status, type, priority, due date, tags, and description.
+
+Bean values are shown as sync would write them, after status and priority
+mapping, so any difference listed is one "beanup sync" would push (or that
+was edited in ClickUp since). Descriptions are compared line by line.
+
+Use --json for structured output.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		beansClient := beans.NewClient(getBeansPath())
+		bean, err := beansClient.Get(args[0])
+		if err != nil {
+			return fmt.Errorf("bean not found: %s", args[0])
+		}
+		taskID := bean.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID)
+		if taskID == "" {
+			return fmt.Errorf("%s is not linked to a ClickUp task (run beanup sync or beanup link)", bean.ID)
+		}
+		if clickup.IsListRef(taskID) {
+			return fmt.Errorf("%s is an epic synced as a ClickUp list, not a task", bean.ID)
+		}
+
+		token, err := getClickUpToken()
+		if err != nil {
+			return err
+		}
+		client := newClickUpClient(ctx, token)
+		task, err := client.GetTask(ctx, taskID)
+		if errors.Is(err, clickup.ErrTaskNotFound) {
+			return fmt.Errorf("task %s linked to %s no longer exists in ClickUp", taskID, bean.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("fetching task: %w", err)
+		}
+
+		sink := clickup.NewSink(client, &cfg.Beans.ClickUp, cfg.Beans.ClickUp.ListID)
+		setSourceLinks(sink)
+		fields := sink.Diff(bean, task)
+
+		inSync := true
+		for _, f := range fields {
+			inSync = inSync && !f.Changed
+		}
+
+		if jsonOut {
+			return outputJSON(map[string]any{
+				"bean_id":  bean.ID,
+				"task_id":  task.ID,
+				"task_url": task.URL,
+				"in_sync":  inSync,
+				"fields":   fields,
+			})
+		}
+
+		_, _ = colorBold.Printf("%s → %s", bean.ID, task.ID)
+		if task.URL != "" {
+			fmt.Printf(" (%s)", task.URL)
+		}
+		fmt.Println()
+		for _, f := range fields {
+			printFieldDiff(os.Stdout, f)
+		}
+		if inSync {
+			_, _ = colorGreen.Println("\nBean and task match")
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
+
+// printFieldDiff prints one field to w, with the bean side in green and the
+// task side in red when they differ.
+func printFieldDiff(w io.Writer, f clickup.FieldDiff) {
+	if !f.Changed {
+		fmt.Fprintf(w, "  %-12s %s\n", f.Field, orNone(firstLine(f.Bean)))
+		return
+	}
+
+	_, _ = colorYellow.Fprintf(w, "  %-12s changed\n", f.Field)
+	if f.Field == "description" {
+		for _, line := range diffLines(strings.Split(f.Task, "\n"), strings.Split(f.Bean, "\n")) {
+			switch line[0] {
+			case '+':
+				_, _ = colorGreen.Fprintf(w, "    %s\n", line)
+			case '-':
+				_, _ = colorRed.Fprintf(w, "    %s\n", line)
+			default:
+				fmt.Fprintf(w, "    %s\n", line)
+			}
+		}
+		return
+	}
+	_, _ = colorGreen.Fprintf(w, "    bean: %s\n", orNone(f.Bean))
+	_, _ = colorRed.Fprintf(w, "    task: %s\n", orNone(f.Task))
+}
+
+// diffLines returns a line diff turning from into to, each line prefixed
+// with "-" (only in from), "+" (only in to), or " " (in both).
+func diffLines(from, to []string) []string {
+	// lcs[i][j] is the length of the longest common subsequence of from[i:] and to[j:]
+	lcs := make([][]int, len(from)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []string
+	i, j := 0, 0
+	for i < len(from) && j < len(to) {
+		switch {
+		case from[i] == to[j]:
+			out = append(out, " "+from[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, "-"+from[i])
+			i++
+		default:
+			out = append(out, "+"+to[j])
+			j++
+		}
+	}
+	for ; i < len(from); i++ {
+		out = append(out, "-"+from[i])
+	}
+	for ; j < len(to); j++ {
+		out = append(out, "+"+to[j])
+	}
+	return out
+}
+
+// firstLine returns the first line of s, marking any that were cut.
+func firstLine(s string) string {
+	if line, _, found := strings.Cut(s, "\n"); found {
+		return line + " …"
+	}
+	return s
+}
+
+// orNone returns s, or "(none)" if it is empty.
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	from := strings.Split("a\nb\nc\nd", "\n")
+	to := strings.Split("a\nc\nd\ne", "\n")
+	want := []string{" a", "-b", " c", " d", "+e"}
+	if got := diffLines(from, to); !slices.Equal(got, want) {
+		t.Errorf("diffLines() = %q, want %q", got, want)
+	}
+}
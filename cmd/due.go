@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dueWithin  string
+	dueOverdue bool
+	dueOffline bool
+)
+
+var dueCmd = &cobra.Command{
+	Use:   "due",
+	Short: "List overdue beans and beans due soon",
+	Long: `Lists open beans that are overdue or due within --within (default 7d),
+grouped by the assignees of their ClickUp tasks. Beans without a task, or
+whose task has no assignee, are listed as unassigned.
+
+Each row shows the bean's due date, the task's status, and the task's due
+date if it differs from the bean's. Task state needs a ClickUp token and
+is skipped with --offline. --within takes days ("14d") or a Go duration.
+
+  beanup due --within 14d
+  beanup due --overdue --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		within, err := parseDays(dueWithin)
+		if err != nil {
+			return fmt.Errorf("invalid --within: %w", err)
+		}
+
+		allBeans, err := beans.NewClient(getBeansPath()).List()
+		if err != nil {
+			return fmt.Errorf("listing beans: %w", err)
+		}
+		var beanList []beans.Bean
+		for _, b := range allBeans {
+			archived := b.Status == "completed" || b.Status == "scrapped"
+			if !archived && b.Due != nil && *b.Due != "" {
+				beanList = append(beanList, b)
+			}
+		}
+
+		var live []liveTask
+		if !dueOffline {
+			if token, _ := getClickUpToken(); token != "" {
+				live, err = fetchLiveTasks(ctx, newClickUpClient(ctx, token), beanList, !jsonOut)
+				if err != nil {
+					return fmt.Errorf("fetching tasks: %w", err)
+				}
+			}
+		}
+
+		groups := dueGroups(beanList, live, time.Now(), within, dueOverdue)
+		if jsonOut {
+			return outputJSON(groups)
+		}
+		if len(groups) == 0 {
+			if dueOverdue {
+				fmt.Println("Nothing is overdue")
+			} else {
+				fmt.Printf("Nothing is overdue or due within %d days\n", within)
+			}
+			return nil
+		}
+
+		for i, g := range groups {
+			if i > 0 {
+				fmt.Println()
+			}
+			_, _ = colorBold.Printf("%s (%d)\n", g.Assignee, len(g.Items))
+			for _, item := range g.Items {
+				when := fmt.Sprintf("in %dd", item.Days)
+				switch {
+				case item.Overdue:
+					when = fmt.Sprintf("%dd late", -item.Days)
+				case item.Days == 0:
+					when = "today"
+				}
+				line := fmt.Sprintf("  %-10s %-9s %-15s %s", item.Due, when, item.BeanID, item.Title)
+				if item.TaskStatus != "" {
+					line += " [" + item.TaskStatus + "]"
+				}
+				if item.TaskDue != "" && item.TaskDue != item.Due {
+					line += " (task due " + item.TaskDue + ")"
+				}
+				if item.Overdue {
+					_, _ = colorRed.Println(line)
+				} else {
+					fmt.Println(line)
+				}
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	dueCmd.Flags().StringVar(&dueWithin, "within", "7d", "list beans due within this many days (e.g. 14d)")
+	dueCmd.Flags().BoolVar(&dueOverdue, "overdue", false, "only list overdue beans")
+	dueCmd.Flags().BoolVar(&dueOffline, "offline", false, "don't contact ClickUp; task state and assignees won't be shown")
+	rootCmd.AddCommand(dueCmd)
+}
+
+// dueUnassigned groups beans whose task has no assignee, or that have no
+// task.
+const dueUnassigned = "Unassigned"
+
+// dueItem is a bean that is overdue or due soon.
+type dueItem struct {
+	BeanID     string `json:"bean_id"`
+	Title      string `json:"title"`
+	Due        string `json:"due"`
+	Days       int    `json:"days"` // Until due; negative when overdue
+	Overdue    bool   `json:"overdue"`
+	TaskID     string `json:"task_id,omitempty"`
+	TaskURL    string `json:"task_url,omitempty"`
+	TaskStatus string `json:"task_status,omitempty"`
+	TaskDue    string `json:"task_due,omitempty"`
+}
+
+// dueGroup is the due items of one assignee.
+type dueGroup struct {
+	Assignee string    `json:"assignee"`
+	Items    []dueItem `json:"items"`
+}
+
+// dueGroups lists the beans due before now's date or within days of it
+// (only the former if overdueOnly), grouped by the assignees of their tasks
+// in live (see fetchLiveTasks; nil if ClickUp wasn't consulted). A bean
+// whose task has several assignees is listed under each. Groups are sorted
+// by name with unassigned last, and items by due date.
+func dueGroups(beanList []beans.Bean, live []liveTask, now time.Time, days int, overdueOnly bool) []dueGroup {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	byAssignee := map[string][]dueItem{}
+	for i, b := range beanList {
+		due, err := time.ParseInLocation("2006-01-02", *b.Due, time.Local)
+		if err != nil {
+			continue
+		}
+		item := dueItem{
+			BeanID: b.ID,
+			Title:  b.Title,
+			Due:    *b.Due,
+			Days:   int(due.Sub(today).Round(24*time.Hour) / (24 * time.Hour)),
+			TaskID: b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID),
+		}
+		item.Overdue = item.Days < 0
+		if !item.Overdue && (overdueOnly || item.Days > days) {
+			continue
+		}
+
+		assignees := []string{dueUnassigned}
+		if live != nil {
+			item.TaskStatus, item.TaskURL = live[i].Status, live[i].URL
+			if task := live[i].Task; task != nil {
+				if task.DueDate != nil {
+					item.TaskDue = formatMillis(*task.DueDate, "2006-01-02")
+				}
+				if len(task.Assignees) > 0 {
+					assignees = nil
+					for _, u := range task.Assignees {
+						assignees = append(assignees, cmp.Or(u.Name(), strconv.Itoa(u.ID)))
+					}
+				}
+			}
+		}
+		for _, a := range assignees {
+			byAssignee[a] = append(byAssignee[a], item)
+		}
+	}
+
+	groups := make([]dueGroup, 0, len(byAssignee))
+	for a, items := range byAssignee {
+		slices.SortStableFunc(items, func(x, y dueItem) int { return cmp.Compare(x.Days, y.Days) })
+		groups = append(groups, dueGroup{Assignee: a, Items: items})
+	}
+	slices.SortFunc(groups, func(x, y dueGroup) int {
+		if (x.Assignee == dueUnassigned) != (y.Assignee == dueUnassigned) {
+			if x.Assignee == dueUnassigned {
+				return 1
+			}
+			return -1
+		}
+		return strings.Compare(strings.ToLower(x.Assignee), strings.ToLower(y.Assignee))
+	})
+	return groups
+}
+
+// parseDays parses a number of days given as "14d", "14", or a Go duration
+// ("36h", rounded down to whole days).
+func parseDays(s string) (int, error) {
+	if n, err := strconv.Atoi(strings.TrimSuffix(s, "d")); err == nil && n >= 0 {
+		return n, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%q is not a number of days (e.g. 7d) or a duration", s)
+	}
+	return int(d / (24 * time.Hour)), nil
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/clickup"
+)
+
+func TestDueGroups(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.Local)
+	bean := func(id, due string) beans.Bean { return beans.Bean{ID: id, Title: id, Due: &due} }
+	beanList := []beans.Bean{
+		bean("late", "2026-03-08"),
+		bean("soon", "2026-03-12"),
+		bean("later", "2026-04-01"),
+		bean("today", "2026-03-10"),
+		bean("shared", "2026-03-11"),
+	}
+	taskDue := "1773374400000" // 2026-03-13 in most zones
+	live := []liveTask{
+		{Status: "to do", Task: &clickup.TaskInfo{Assignees: []clickup.TaskUser{{ID: 1, Username: "bob"}}}},
+		{Status: "in progress", Task: &clickup.TaskInfo{DueDate: &taskDue, Assignees: []clickup.TaskUser{{ID: 2, Email: "al@example.com"}}}},
+		{},
+		{},
+		{Task: &clickup.TaskInfo{Assignees: []clickup.TaskUser{{ID: 1, Username: "bob"}, {ID: 2, Email: "al@example.com"}}}},
+	}
+
+	groups := dueGroups(beanList, live, now, 7, false)
+	want := map[string][]string{
+		"al@example.com": {"shared", "soon"},
+		"bob":            {"late", "shared"},
+		dueUnassigned:    {"today"},
+	}
+	if len(groups) != 3 || groups[0].Assignee != "al@example.com" || groups[2].Assignee != dueUnassigned {
+		t.Fatalf("groups = %+v", groups)
+	}
+	for _, g := range groups {
+		var ids []string
+		for _, item := range g.Items {
+			ids = append(ids, item.BeanID)
+		}
+		if len(ids) != len(want[g.Assignee]) || ids[0] != want[g.Assignee][0] || ids[1%len(ids)] != want[g.Assignee][1%len(ids)] {
+			t.Errorf("%s: %v, want %v", g.Assignee, ids, want[g.Assignee])
+		}
+	}
+	late := groups[1].Items[0]
+	if !late.Overdue || late.Days != -2 || late.TaskStatus != "to do" {
+		t.Errorf("late = %+v", late)
+	}
+	if soon := groups[0].Items[1]; soon.Days != 2 || soon.TaskDue == "" {
+		t.Errorf("soon = %+v", soon)
+	}
+
+	overdue := dueGroups(beanList, nil, now, 7, true)
+	if len(overdue) != 1 || overdue[0].Assignee != dueUnassigned || len(overdue[0].Items) != 1 || overdue[0].Items[0].BeanID != "late" {
+		t.Errorf("overdue only = %+v", overdue)
+	}
+}
+
+func TestParseDays(t *testing.T) {
+	for s, want := range map[string]int{"7d": 7, "14": 14, "0d": 0, "48h": 2, "36h": 1} {
+		if got, err := parseDays(s); err != nil || got != want {
+			t.Errorf("parseDays(%q) = %d, %v; want %d", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"", "soon", "-3d", "-1h"} {
+		if _, err := parseDays(s); err == nil {
+			t.Errorf("parseDays(%q) succeeded", s)
+		}
+	}
+}
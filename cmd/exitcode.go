@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/toba/bean-me-up/internal/auth"
+	"github.com/toba/bean-me-up/internal/clickup"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes, documented by "beanup help exit-codes".
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitConfig      = 2
+	exitAuth        = 3
+	exitPartialSync = 4
+	exitConflict    = 5
+	exitDrift       = 6
+	// Like a shell's 128 + SIGINT
+	exitInterrupted = 130
+)
+
+// codedError is an error that exits with a specific code.
+type codedError struct {
+	code int
+	err  error
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+
+func (e *codedError) Unwrap() error { return e.err }
+
+// withExitCode makes err exit with code, or returns nil if err is nil.
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: code, err: err}
+}
+
+// ExitCode returns the process exit code for an error returned by Execute.
+// Errors without an explicit code that come from a missing or rejected
+// token are auth errors; anything else is a general error.
+func ExitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	if coded, ok := errors.AsType[*codedError](err); ok {
+		return coded.code
+	}
+	if errors.Is(err, auth.ErrNoToken) || errors.Is(err, clickup.ErrUnauthorized) {
+		return exitAuth
+	}
+	return exitError
+}
+
+var exitCodesCmd = &cobra.Command{
+	Use:   "exit-codes",
+	Short: "Exit codes scripts can branch on",
+	Long: `beanup exits with one of these codes:
+
+    0  success
+    1  any other error
+    2  configuration error: a config file that can't be loaded, or an
+       invalid or missing value in it
+    3  auth error: no ClickUp token, or ClickUp rejected it
+    4  partial sync failure: some beans failed to sync (or, for status,
+       their tasks were deleted)
+    5  conflict detected: a bean and its task both changed since the last
+       sync (status --fail-on drift)
+    6  drift found: beans need syncing (sync or status --fail-on drift)
+  130  interrupted with Ctrl-C; sync, pull, and check keep what
+       completed
+
+Codes 4 to 6 are only used when --fail-on asks for them; in CI the
+default is --fail-on errors. When several apply, the lowest wins.
+
+  beanup sync --dry-run --fail-on drift
+  case $? in
+    0) echo "up to date" ;;
+    6) echo "needs sync" ;;
+    *) exit 1 ;;
+  esac`,
+}
+
+func init() {
+	rootCmd.AddCommand(exitCodesCmd)
+}
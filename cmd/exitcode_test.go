@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/auth"
+	"github.com/toba/bean-me-up/internal/clickup"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, exitOK},
+		{"other error", errors.New("boom"), exitError},
+		{"coded", withExitCode(exitConfig, errors.New("bad config")), exitConfig},
+		{"coded and wrapped", fmt.Errorf("syncing: %w", withExitCode(exitDrift, errors.New("drift"))), exitDrift},
+		{"no token", fmt.Errorf("creating sink: %w", auth.ErrNoToken), exitAuth},
+		{"rejected token", &clickup.APIError{StatusCode: 401, Code: "OAUTH_019", Message: "Oauth token not found"}, exitAuth},
+		{"other API error", &clickup.APIError{StatusCode: 500, Message: "oops"}, exitError},
+	}
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("%s: ExitCode(%v) = %d, want %d", tt.name, tt.err, got, tt.want)
+		}
+	}
+	if withExitCode(exitConfig, nil) != nil {
+		t.Error("withExitCode(nil) should be nil")
+	}
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/clickup"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat  string
+	exportOutput  string
+	exportAll     bool
+	exportOffline bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the bean to ClickUp task mapping",
+	Long: `Writes the mapping between beans and ClickUp tasks (bean ID, title,
+status, task ID, task URL, last sync time, and sync state) as CSV, JSON,
+or markdown, for reporting, backup, or feeding other tools.
+
+Only linked beans are exported unless --all is given. The sync state is
+the same as the Sync column of "beanup status"; task-side changes are
+detected only when a ClickUp token is available and --offline is not set.
+
+  beanup export --format json -o mapping.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		format := exportFormat
+		if jsonOut {
+			format = "json"
+		}
+		if !slices.Contains(exportFormats, format) {
+			return fmt.Errorf("unknown --format %q (use %s)", format, strings.Join(exportFormats, ", "))
+		}
+
+		allBeans, err := beans.NewClient(getBeansPath()).List()
+		if err != nil {
+			return fmt.Errorf("listing beans: %w", err)
+		}
+		var beanList []beans.Bean
+		for _, b := range allBeans {
+			if exportAll || b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID) != "" {
+				beanList = append(beanList, b)
+			}
+		}
+
+		var live []liveTask
+		if !exportOffline {
+			if token, _ := getClickUpToken(); token != "" {
+				live, err = fetchLiveTasks(ctx, newClickUpClient(ctx, token), beanList, false)
+				if err != nil {
+					return fmt.Errorf("fetching task status: %w", err)
+				}
+			}
+		}
+
+		rows := make([]exportRow, len(beanList))
+		for i, b := range beanList {
+			var l *liveTask
+			if live != nil {
+				l = &live[i]
+			}
+			rows[i] = newExportRow(b, l)
+		}
+
+		if exportOutput == "" {
+			return writeExport(os.Stdout, format, rows)
+		}
+		f, err := os.Create(exportOutput)
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", exportOutput, err)
+		}
+		if err := writeExport(f, format, rows); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("writing %s: %w", exportOutput, err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("writing %s: %w", exportOutput, err)
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d beans to %s\n", len(rows), exportOutput)
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", "csv", "output format: csv, json, or markdown")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "write to this file instead of stdout")
+	exportCmd.Flags().BoolVar(&exportAll, "all", false, "include beans not linked to a task")
+	exportCmd.Flags().BoolVar(&exportOffline, "offline", false, "don't contact ClickUp; task-side changes won't be detected")
+	rootCmd.AddCommand(exportCmd)
+}
+
+// exportFormats are the values accepted by export --format.
+var exportFormats = []string{"csv", "json", "markdown"}
+
+// exportColumns are the csv and markdown export columns.
+var exportColumns = []string{"Bean ID", "Title", "Status", "Task ID", "Task URL", "Synced At", "Sync"}
+
+// exportRow is one bean's entry in the mapping export.
+type exportRow struct {
+	BeanID   string     `json:"bean_id"`
+	Title    string     `json:"title"`
+	Status   string     `json:"status"`
+	TaskID   string     `json:"task_id,omitempty"`
+	TaskURL  string     `json:"task_url,omitempty"`
+	SyncedAt *time.Time `json:"synced_at,omitempty"`
+	Drift    string     `json:"drift"`
+}
+
+// newExportRow builds the export entry for a bean. live is the fetched task,
+// or nil when ClickUp wasn't consulted.
+func newExportRow(b beans.Bean, live *liveTask) exportRow {
+	taskID := b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID)
+	row := exportRow{
+		BeanID:   b.ID,
+		Title:    b.Title,
+		Status:   b.Status,
+		TaskID:   taskID,
+		SyncedAt: b.GetExtensionTime(beans.PluginClickUp, beans.ExtKeySyncedAt),
+		Drift:    driftUnlinked,
+	}
+	if taskID == "" {
+		return row
+	}
+
+	var taskUpdated *time.Time
+	row.TaskURL = b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskURL)
+	if row.TaskURL == "" {
+		row.TaskURL = clickup.TaskURL(taskID)
+	}
+	if live != nil {
+		taskUpdated = live.UpdatedAt
+		if live.URL != "" {
+			row.TaskURL = live.URL
+		}
+	}
+	row.Drift = syncDrift(b.UpdatedAt, taskUpdated, row.SyncedAt)
+	return row
+}
+
+func (r exportRow) columns() []string {
+	syncedAt := ""
+	if r.SyncedAt != nil {
+		syncedAt = r.SyncedAt.UTC().Format(time.RFC3339)
+	}
+	return []string{r.BeanID, r.Title, r.Status, r.TaskID, r.TaskURL, syncedAt, r.Drift}
+}
+
+// writeExport writes export rows in the given format.
+func writeExport(w io.Writer, format string, rows []exportRow) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+
+	cells := make([][]string, len(rows))
+	for i, r := range rows {
+		cells[i] = r.columns()
+	}
+	if format == "markdown" {
+		return writeMarkdownTable(w, exportColumns, cells)
+	}
+	return writeCSV(w, exportColumns, cells)
+}
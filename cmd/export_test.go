@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/beans"
+)
+
+func TestExportRows(t *testing.T) {
+	synced := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	edited := synced.Add(time.Hour)
+	before := synced.Add(-time.Hour)
+
+	linked := beans.Bean{ID: "bean-a", Title: "Fix a, b", Status: "todo", UpdatedAt: &before, Extensions: map[string]map[string]any{
+		beans.PluginClickUp: {beans.ExtKeyTaskID: "abc", beans.ExtKeySyncedAt: synced.Format(time.RFC3339)},
+	}}
+	unlinked := beans.Bean{ID: "bean-b", Title: "Plan", Status: "draft"}
+
+	rows := []exportRow{
+		newExportRow(linked, &liveTask{UpdatedAt: &edited}),
+		newExportRow(unlinked, nil),
+	}
+	if rows[0].Drift != driftTaskAhead {
+		t.Errorf("linked drift = %q, want %q", rows[0].Drift, driftTaskAhead)
+	}
+	if rows[0].TaskURL != "https://app.clickup.com/t/abc" {
+		t.Errorf("linked task URL = %q", rows[0].TaskURL)
+	}
+
+	var csv strings.Builder
+	if err := writeExport(&csv, "csv", rows); err != nil {
+		t.Fatal(err)
+	}
+	wantCSV := `Bean ID,Title,Status,Task ID,Task URL,Synced At,Sync
+bean-a,"Fix a, b",todo,abc,https://app.clickup.com/t/abc,2026-03-01T12:00:00Z,task ahead
+bean-b,Plan,draft,,,,not linked
+`
+	if csv.String() != wantCSV {
+		t.Errorf("csv =\n%s\nwant\n%s", csv.String(), wantCSV)
+	}
+}
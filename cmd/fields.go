@@ -1,10 +1,11 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
+	"path/filepath"
 
 	"github.com/toba/bean-me-up/internal/clickup"
+	"github.com/toba/bean-me-up/internal/config"
 	"github.com/spf13/cobra"
 )
 
@@ -18,7 +19,7 @@ in your .beans.clickup.yml configuration.
 
 Requires CLICKUP_TOKEN environment variable to be set.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		ctx := context.Background()
+		ctx := cmd.Context()
 
 		// Validate config
 		if err := requireListID(); err != nil {
@@ -32,7 +33,7 @@ Requires CLICKUP_TOKEN environment variable to be set.`,
 		}
 
 		// Create client
-		client := clickup.NewClient(token)
+		client := newClickUpClient(ctx, token)
 
 		// Fetch custom fields
 		fields, err := client.GetAccessibleCustomFields(ctx, cfg.Beans.ClickUp.ListID)
@@ -78,10 +79,63 @@ func outputFieldsText(fields []clickup.FieldInfo) error {
 	fmt.Println("        bean_id: \"<text-field-id>\"")
 	fmt.Println("        created_at: \"<date-field-id>\"")
 	fmt.Println("        updated_at: \"<date-field-id>\"")
+	fmt.Println()
+	fmt.Println("Or run \"beanup fields create\" to create and map them automatically.")
 
 	return nil
 }
 
+var fieldsCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create the custom fields beanup fills in and map them",
+	Long: `Creates the Bean ID (text), Bean Created (date), and Bean Updated (date)
+custom fields on the configured ClickUp list, reusing any that already exist,
+and writes their IDs to extensions.clickup.custom_fields in .beans.yml.
+
+Requires CLICKUP_TOKEN environment variable to be set.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		if err := requireListID(); err != nil {
+			return err
+		}
+		token, err := getClickUpToken()
+		if err != nil {
+			return err
+		}
+
+		client := newClickUpClient(ctx, token)
+		mapping, created, err := client.EnsureBeanFields(ctx, cfg.Beans.ClickUp.ListID)
+		for _, name := range created {
+			fmt.Printf("Created custom field %q\n", name)
+		}
+		if err != nil {
+			return err
+		}
+
+		values := [][2]string{
+			{"clickup.custom_fields.bean_id", mapping.BeanID},
+			{"clickup.custom_fields.created_at", mapping.CreatedAt},
+			{"clickup.custom_fields.updated_at", mapping.UpdatedAt},
+		}
+		if filepath.Base(cfg.Path) != config.BeansConfigFileName {
+			fmt.Printf("\nAdd these IDs to %s under custom_fields:\n", cfg.Path)
+			for _, kv := range values {
+				fmt.Printf("  %s: %q\n", filepath.Ext(kv[0])[1:], kv[1])
+			}
+			return nil
+		}
+		for _, kv := range values {
+			if err := config.Set(cfg.Path, kv[0], kv[1]); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Mapped custom_fields in %s\n", cfg.Path)
+		return nil
+	},
+}
+
 func init() {
+	fieldsCmd.AddCommand(fieldsCreateCmd)
 	rootCmd.AddCommand(fieldsCmd)
 }
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/audit"
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/queue"
+	"github.com/toba/bean-me-up/internal/syncer"
+	"github.com/spf13/cobra"
+)
+
+var flushList bool
+
+var flushCmd = &cobra.Command{
+	Use:   "flush",
+	Short: "Replay syncs queued while a backend was unreachable",
+	Long: `When a backend can't be reached during sync (no network, DNS failure,
+connection refused), the beans that should have been created or updated
+are recorded in .beans/` + queue.FileName + `. The next sync replays them
+first, in the order queued; flush replays only the queued beans.
+
+Entries are removed once their bean syncs, and kept if it fails again.
+Use --list to see what is queued without syncing.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := queue.Load(queuePath())
+		if err != nil {
+			return err
+		}
+		if flushList {
+			if jsonOut {
+				return outputJSON(entries)
+			}
+			for _, e := range entries {
+				fmt.Printf("  %s %-6s %s %s\n", e.Time.Local().Format(time.DateTime), e.Op, sinkDisplayName(e.Sink), e.BeanID)
+			}
+			fmt.Printf("%d queued\n", len(entries))
+			return nil
+		}
+		if len(entries) == 0 {
+			if jsonOut {
+				fmt.Println("[]")
+			} else {
+				fmt.Println("Nothing queued")
+			}
+			return nil
+		}
+
+		var ids []string
+		seen := make(map[string]bool)
+		for _, e := range entries {
+			if !seen[e.BeanID] {
+				seen[e.BeanID] = true
+				ids = append(ids, e.BeanID)
+			}
+		}
+		results, message, err := runSync(cmd.Context(), ids, jsonOut)
+		if err != nil {
+			return err
+		}
+		switch {
+		case message != "" && jsonOut:
+			fmt.Println("[]")
+		case message != "":
+			fmt.Println(message)
+		case jsonOut:
+			return outputResultsJSON(results)
+		default:
+			return outputResultsText(results)
+		}
+		return nil
+	},
+}
+
+func init() {
+	flushCmd.Flags().StringVar(&syncSink, "sink", "", "Replay only this backend's queue (default: every configured backend)")
+	flushCmd.Flags().BoolVar(&flushList, "list", false, "List queued syncs without replaying them")
+	rootCmd.AddCommand(flushCmd)
+}
+
+// queuePath is the offline sync journal in the beans directory.
+func queuePath() string {
+	return filepath.Join(getBeansPath(), queue.FileName)
+}
+
+// auditPath is the sync history log in the beans directory.
+func auditPath() string {
+	return filepath.Join(getBeansPath(), audit.FileName)
+}
+
+// recordSyncErrors logs the beans that failed to sync to sink, so beanup
+// report can list them after the queue has moved on.
+func recordSyncErrors(sink string, results []syncer.Result) error {
+	var failed []audit.Entry
+	for _, r := range results {
+		if r.Error == nil || r.Action == "cancelled" {
+			continue
+		}
+		failed = append(failed, audit.Entry{
+			Time:   time.Now().UTC(),
+			Kind:   audit.KindSyncError,
+			Sink:   sink,
+			BeanID: r.BeanID,
+			TaskID: r.TaskID,
+			Error:  r.Error.Error(),
+		})
+	}
+	return audit.Append(auditPath(), failed...)
+}
+
+// withQueued returns toSync with the beans queued for a sink put first, in
+// the order queued. Queued beans are added even if they look up to date.
+func withQueued(beanList, toSync []beans.Bean, queued []string) []beans.Bean {
+	if len(queued) == 0 {
+		return toSync
+	}
+	byID := make(map[string]beans.Bean, len(beanList))
+	for _, b := range beanList {
+		byID[b.ID] = b
+	}
+	out := make([]beans.Bean, 0, len(toSync)+len(queued))
+	first := make(map[string]bool, len(queued))
+	for _, id := range queued {
+		if b, ok := byID[id]; ok && !first[id] {
+			first[id] = true
+			out = append(out, b)
+		}
+	}
+	for _, b := range toSync {
+		if !first[b.ID] {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// updateQueue drops queued syncs that have now succeeded and queues beans
+// that failed because the sink was unreachable. It returns how many beans
+// failed that way.
+func updateQueue(path, sink string, entries []queue.Entry, results []syncer.Result) (int, error) {
+	done := make(map[string]bool)
+	var failed []queue.Entry
+	for _, r := range results {
+		switch {
+		case r.Error == nil:
+			done[r.BeanID] = true
+		case syncer.IsUnreachable(r.Error):
+			op := queue.OpUpdate
+			if r.TaskID == "" {
+				op = queue.OpCreate
+			}
+			failed = append(failed, queue.Entry{
+				Time:   time.Now().UTC(),
+				Sink:   sink,
+				BeanID: r.BeanID,
+				Op:     op,
+				Error:  r.Error.Error(),
+			})
+		}
+	}
+	if len(failed) == 0 && len(queue.For(entries, sink)) == 0 {
+		return 0, nil
+	}
+	if err := queue.Save(path, queue.Update(entries, sink, done, failed)); err != nil {
+		return 0, err
+	}
+	return len(failed), nil
+}
+
+// warnQueued tells the user that syncs were queued for later.
+func warnQueued(sink string, n int) {
+	if n == 0 {
+		return
+	}
+	_, _ = colorYellow.Fprintf(os.Stderr, "Warning: %s is unreachable; %d bean(s) queued. They sync on the next run, or with 'beanup flush'.\n",
+		sinkDisplayName(sink), n)
+}
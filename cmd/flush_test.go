@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/queue"
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+func TestWithQueued(t *testing.T) {
+	all := []beans.Bean{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	toSync := []beans.Bean{{ID: "a"}, {ID: "c"}}
+
+	got := withQueued(all, toSync, []string{"b", "c", "gone"})
+	var ids []string
+	for _, b := range got {
+		ids = append(ids, b.ID)
+	}
+	if fmt.Sprint(ids) != "[b c a]" {
+		t.Errorf("withQueued() = %v, want [b c a]", ids)
+	}
+}
+
+func TestUpdateQueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), queue.FileName)
+	offline := fmt.Errorf("creating task: %w", &net.OpError{Op: "dial", Err: fmt.Errorf("no route to host")})
+
+	// A run that can't reach the sink queues creates and updates
+	n, err := updateQueue(path, "clickup", nil, []syncer.Result{
+		{BeanID: "new", Action: "error", Error: offline},
+		{BeanID: "linked", TaskID: "t1", Action: "error", Error: offline},
+		{BeanID: "bad", TaskID: "t2", Action: "error", Error: fmt.Errorf("validation failed")},
+	})
+	if err != nil || n != 2 {
+		t.Fatalf("updateQueue() = %d, %v; want 2 queued", n, err)
+	}
+	entries, _ := queue.Load(path)
+	if len(entries) != 2 || entries[0].Op != queue.OpCreate || entries[1].Op != queue.OpUpdate {
+		t.Fatalf("queued %+v", entries)
+	}
+
+	// Once they sync the journal is cleared
+	n, err = updateQueue(path, "clickup", entries, []syncer.Result{
+		{BeanID: "new", TaskID: "t3", Action: "created"},
+		{BeanID: "linked", TaskID: "t1", Action: "updated"},
+	})
+	if err != nil || n != 0 {
+		t.Fatalf("updateQueue() = %d, %v", n, err)
+	}
+	if entries, _ := queue.Load(path); len(entries) != 0 {
+		t.Errorf("journal not cleared: %+v", entries)
+	}
+}
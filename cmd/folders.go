@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var foldersCmd = &cobra.Command{
+	Use:   "folders <space-id>",
+	Short: "List the folders in a ClickUp space",
+	Long: `Lists the folders in a ClickUp space along with the lists in each.
+
+Find space IDs with "beanup spaces".
+
+Requires CLICKUP_TOKEN environment variable to be set.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		token, err := getClickUpToken()
+		if err != nil {
+			return err
+		}
+		client := newClickUpClient(ctx, token)
+
+		folders, err := client.GetFolders(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("fetching folders: %w", err)
+		}
+
+		if jsonOut {
+			return outputJSON(folders)
+		}
+
+		if len(folders) == 0 {
+			fmt.Println("No folders in this space.")
+			fmt.Printf("Lists outside folders: beanup lists %s\n", args[0])
+			return nil
+		}
+		for _, f := range folders {
+			fmt.Printf("%s\n  ID: %s\n", f.Name, f.ID)
+			for _, l := range f.Lists {
+				fmt.Printf("    - %s (list %s)\n", l.Name, l.ID)
+			}
+			fmt.Println()
+		}
+		fmt.Printf("Lists outside folders: beanup lists %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(foldersCmd)
+}
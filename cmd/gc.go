@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/tombstone"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gcOlderThan time.Duration
+	gcAll       bool
+	gcDryRun    bool
+)
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Purge old tombstones of deleted beans",
+	Long: `A full sync records a tombstone in .beans/` + tombstone.FileName + ` for each
+bean that had a linked task and has since been deleted, and applies the
+backend's on_delete setting to the task. Tombstones are kept afterwards as
+a record of what was done.
+
+gc removes tombstones of beans deleted more than --older-than ago (30 days
+by default), or all of them with --all. A purged tombstone whose task was
+never handled is forgotten, leaving the task as is.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := tombstone.Load(tombstonePath())
+		if err != nil {
+			return err
+		}
+		cutoff := time.Now().Add(-gcOlderThan)
+		if gcAll {
+			cutoff = time.Now()
+		}
+		n := len(store.Tombstones)
+		purged := store.Purge(cutoff)
+
+		if !gcDryRun && purged > 0 {
+			if err := store.Save(tombstonePath()); err != nil {
+				return err
+			}
+		}
+		if jsonOut {
+			return outputJSON(map[string]int{"purged": purged, "kept": n - purged})
+		}
+		verb := "Purged"
+		if gcDryRun {
+			verb = "Would purge"
+		}
+		fmt.Printf("%s %d tombstones, %d kept\n", verb, purged, n-purged)
+		return nil
+	},
+}
+
+func init() {
+	gcCmd.Flags().DurationVar(&gcOlderThan, "older-than", 30*24*time.Hour, "Purge tombstones of beans deleted longer ago than this")
+	gcCmd.Flags().BoolVar(&gcAll, "all", false, "Purge every tombstone")
+	gcCmd.Flags().BoolVar(&gcDryRun, "dry-run", false, "Show how many would be purged without changing anything")
+	rootCmd.AddCommand(gcCmd)
+}
@@ -5,24 +5,37 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 	"text/template"
 	"time"
 
-	"github.com/toba/bean-me-up/internal/clickup"
+	"github.com/toba/bean-me-up/internal/config"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
-var initOutputPath string
+var (
+	initOutputPath string
+	initListID     string
+	initForce      bool
+	initYes        bool
+	initAssignee   int
+	initStatusMap  []string
+	initFields     bool
+)
 
 var initCmd = &cobra.Command{
 	Use:   "init [list-id]",
 	Short: "Initialize ClickUp configuration in .beans.yml",
 	Long: `Initializes ClickUp configuration by adding an extensions.clickup section to .beans.yml.
 
+The section is merged into the project's existing .beans.yml (found by searching
+upward from the current directory), keeping its other content and comments. A
+new .beans.yml is created here if there is none.
+
 This command fetches your list's statuses, custom fields, and custom task types to
 generate a config section with helpful comments and examples.
 
@@ -31,13 +44,29 @@ The list ID can be found in the ClickUp URL when viewing a list:
                             ^^^^^^^^^
                             This is the list ID
 
-Requires CLICKUP_TOKEN environment variable to be set.`,
+Requires CLICKUP_TOKEN environment variable to be set.
+
+Pass --create-fields to add the Bean ID, Bean Created, and Bean Updated custom
+fields to the list (unless they already exist) and map them in custom_fields.
+
+For provisioning scripts, pass --yes to fail instead of prompting, --force
+to replace an existing extensions.clickup section, and --assignee and
+--status-map to write those settings rather than commented examples:
+
+  beanup init --yes --force --list-id 987654321 --assignee 12345 \
+    --status-map todo="to do" --status-map completed=complete`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runInit,
 }
 
 func init() {
-	initCmd.Flags().StringVarP(&initOutputPath, "output", "o", ".beans.yml", "Output file path")
+	initCmd.Flags().StringVarP(&initOutputPath, "output", "o", ".beans.yml", "Output file path (default: the project's .beans.yml, or a new one here)")
+	initCmd.Flags().StringVar(&initListID, "list-id", "", "ClickUp list ID (instead of the argument)")
+	initCmd.Flags().BoolVar(&initForce, "force", false, "replace an existing extensions.clickup section")
+	initCmd.Flags().BoolVarP(&initYes, "yes", "y", false, "never prompt; fail if input is missing")
+	initCmd.Flags().IntVar(&initAssignee, "assignee", 0, "ClickUp user ID to assign new tasks to")
+	initCmd.Flags().BoolVar(&initFields, "create-fields", false, "create the Bean ID, Bean Created, and Bean Updated custom fields if missing")
+	initCmd.Flags().StringArrayVar(&initStatusMap, "status-map", nil, "bean status to ClickUp status, as status=name (repeatable)")
 	rootCmd.AddCommand(initCmd)
 }
 
@@ -52,11 +81,20 @@ var (
 
 // configTemplateData holds the data for the config template.
 type configTemplateData struct {
-	ListID       string
-	ListName     string
-	Statuses     []string
-	CustomFields []fieldEntry
-	CustomItems  []customItemEntry
+	ListID        string
+	ListName      string
+	TeamID        string
+	Assignee      *int
+	StatusMapping []statusMapEntry
+	Statuses      []string
+	CustomFields  []fieldEntry
+	FieldMapping  *config.CustomFieldsMap
+	CustomItems   []customItemEntry
+}
+
+type statusMapEntry struct {
+	Bean    string
+	ClickUp string
 }
 
 type customItemEntry struct {
@@ -71,50 +109,71 @@ type fieldEntry struct {
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
 	defer cancel()
 
-	// Check for CLICKUP_TOKEN
-	token := os.Getenv("CLICKUP_TOKEN")
-	if token == "" {
-		_, _ = colorRed.Fprintln(os.Stderr, "Error: CLICKUP_TOKEN environment variable is not set")
+	// Check for CLICKUP_TOKEN or a stored login
+	token, err := getClickUpToken()
+	if err != nil {
+		_, _ = colorRed.Fprintf(os.Stderr, "Error: %v\n", err)
 		fmt.Fprintln(os.Stderr)
 		fmt.Fprintln(os.Stderr, "Get your API token from: https://app.clickup.com/settings/apps")
 		fmt.Fprintln(os.Stderr, "Then run: export CLICKUP_TOKEN=\"pk_your_token\"")
-		return fmt.Errorf("CLICKUP_TOKEN not set")
+		fmt.Fprintln(os.Stderr, "Or log in with an OAuth app: beanup auth login")
+		return fmt.Errorf("no ClickUp token")
 	}
 
 	// Warn if beans CLI not found
 	if !checkBeansInstalled() {
 		_, _ = colorYellow.Fprintln(os.Stderr, "Warning: beans CLI not found in PATH")
-		fmt.Fprintln(os.Stderr, "The init command will continue, but sync commands require beans.")
+		fmt.Fprintln(os.Stderr, "Other commands will read and write bean files directly.")
 		fmt.Fprintln(os.Stderr)
 	}
 
-	// Get list ID from args or prompt
+	statusMapping, err := parseStatusMap(initStatusMap)
+	if err != nil {
+		return err
+	}
+
+	// Get list ID from args, flag, or prompt
 	var listID string
-	if len(args) > 0 {
+	switch {
+	case len(args) > 0:
 		listID = args[0]
-	} else {
-		var err error
+	case initListID != "":
+		listID = initListID
+	case initYes:
+		return fmt.Errorf("list ID is required: pass it as an argument or with --list-id")
+	default:
 		listID, err = promptListID()
 		if err != nil {
 			return err
 		}
 	}
 
+	// Write into the project's .beans.yml, even from a subdirectory, unless --output is given
+	outputPath := initOutputPath
+	if !cmd.Flags().Changed("output") {
+		if path, err := beansConfigPath(); err == nil {
+			outputPath = path
+		}
+	}
+
 	// Check if extensions.clickup already exists in the output file
-	if _, err := os.Stat(initOutputPath); err == nil {
-		data, readErr := os.ReadFile(initOutputPath)
+	replacing := false
+	if _, err := os.Stat(outputPath); err == nil {
+		data, readErr := os.ReadFile(outputPath)
 		if readErr == nil {
 			var existing map[string]any
 			if yamlErr := yaml.Unmarshal(data, &existing); yamlErr == nil {
 				if ext, ok := existing["extensions"]; ok {
 					if extMap, ok := ext.(map[string]any); ok {
-						if _, ok := extMap["clickup"]; ok {
-							_, _ = colorRed.Fprintf(os.Stderr, "Error: extensions.clickup already exists in %s\n", initOutputPath)
+						if _, ok := extMap["clickup"]; ok && initForce {
+							replacing = true
+						} else if ok {
+							_, _ = colorRed.Fprintf(os.Stderr, "Error: extensions.clickup already exists in %s\n", outputPath)
 							fmt.Fprintln(os.Stderr)
-							fmt.Fprintln(os.Stderr, "Remove the existing extensions.clickup section first, or edit it manually.")
+							fmt.Fprintln(os.Stderr, "Remove the existing extensions.clickup section first, edit it manually, or pass --force to replace it.")
 							return fmt.Errorf("extensions.clickup already exists")
 						}
 					}
@@ -124,7 +183,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create ClickUp client
-	client := clickup.NewClient(token)
+	client := newClickUpClient(ctx, token)
 
 	// Fetch list info (required)
 	_, _ = colorCyan.Print("Fetching list info... ")
@@ -143,14 +202,40 @@ func runInit(cmd *cobra.Command, args []string) error {
 
 	// Prepare template data
 	data := configTemplateData{
-		ListID:   listID,
-		ListName: list.Name,
+		ListID:        listID,
+		ListName:      list.Name,
+		StatusMapping: statusMapping,
+		TeamID:        teamID,
+	}
+	if cmd.Flags().Changed("assignee") {
+		data.Assignee = &initAssignee
 	}
 
 	// Extract statuses
 	for _, s := range list.Statuses {
 		data.Statuses = append(data.Statuses, s.Status)
 	}
+	for _, m := range statusMapping {
+		if !slices.ContainsFunc(data.Statuses, func(s string) bool { return strings.EqualFold(s, m.ClickUp) }) {
+			_, _ = colorYellow.Fprintf(os.Stderr, "Warning: status %q is not on list %s\n", m.ClickUp, list.Name)
+		}
+	}
+
+	// Create the custom fields beanup fills in (optional)
+	if initFields {
+		_, _ = colorCyan.Print("Creating custom fields... ")
+		mapping, created, err := client.EnsureBeanFields(ctx, listID)
+		if err != nil {
+			_, _ = colorRed.Println("failed")
+			return fmt.Errorf("creating custom fields: %w", err)
+		}
+		if len(created) == 0 {
+			_, _ = colorGreen.Println("already present")
+		} else {
+			_, _ = colorGreen.Printf("created %s\n", strings.Join(created, ", "))
+		}
+		data.FieldMapping = mapping
+	}
 
 	// Fetch custom fields (optional)
 	_, _ = colorCyan.Print("Fetching custom fields... ")
@@ -171,7 +256,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 
 	// Fetch custom task types (optional)
 	_, _ = colorCyan.Print("Fetching custom task types... ")
-	customItems, err := client.GetCustomItems(ctx)
+	customItems, err := getCustomItems(ctx, client)
 	if err != nil {
 		_, _ = colorYellow.Println("skipped")
 		_, _ = colorYellow.Fprintf(os.Stderr, "Warning: Could not fetch custom task types: %v\n", err)
@@ -198,35 +283,34 @@ func runInit(cmd *cobra.Command, args []string) error {
 	}
 
 	// Append to existing file or create new
-	if _, err := os.Stat(initOutputPath); err == nil {
-		// File exists — append the extensions section
-		f, err := os.OpenFile(initOutputPath, os.O_APPEND|os.O_WRONLY, 0644)
-		if err != nil {
-			_, _ = colorRed.Println("failed")
-			return fmt.Errorf("opening %s: %w", initOutputPath, err)
-		}
-		_, writeErr := f.WriteString("\n" + content)
-		closeErr := f.Close()
-		if writeErr != nil {
-			_, _ = colorRed.Println("failed")
-			return fmt.Errorf("appending to %s: %w", initOutputPath, writeErr)
+	if _, err := os.Stat(outputPath); err == nil {
+		// File exists — drop the section being replaced, then add the new one
+		if replacing {
+			if err := config.Unset(outputPath, "clickup"); err != nil {
+				_, _ = colorRed.Println("failed")
+				return fmt.Errorf("removing existing extensions.clickup: %w", err)
+			}
 		}
-		if closeErr != nil {
+		if err := config.AppendExtension(outputPath, content); err != nil {
 			_, _ = colorRed.Println("failed")
-			return fmt.Errorf("closing %s: %w", initOutputPath, closeErr)
+			return fmt.Errorf("appending to %s: %w", outputPath, err)
 		}
 	} else {
 		// File doesn't exist — create it
-		if err := os.WriteFile(initOutputPath, []byte(content), 0644); err != nil {
+		if err := os.WriteFile(outputPath, []byte(content), 0644); err != nil {
 			_, _ = colorRed.Println("failed")
-			return fmt.Errorf("writing %s: %w", initOutputPath, err)
+			return fmt.Errorf("writing %s: %w", outputPath, err)
 		}
 	}
 	_, _ = colorGreen.Println("done")
 
 	// Print success message
 	fmt.Println()
-	_, _ = colorGreen.Printf("Added extensions.clickup to %s\n", initOutputPath)
+	if replacing {
+		_, _ = colorGreen.Printf("Replaced extensions.clickup in %s\n", outputPath)
+	} else {
+		_, _ = colorGreen.Printf("Added extensions.clickup to %s\n", outputPath)
+	}
 	fmt.Println()
 	_, _ = colorBold.Println("Next steps:")
 	fmt.Println("  1. Review and customize the generated config")
@@ -251,6 +335,20 @@ func promptListID() (string, error) {
 	return listID, nil
 }
 
+// parseStatusMap parses --status-map values of the form status=name.
+func parseStatusMap(values []string) ([]statusMapEntry, error) {
+	entries := make([]statusMapEntry, 0, len(values))
+	for _, v := range values {
+		bean, clickupStatus, ok := strings.Cut(v, "=")
+		bean, clickupStatus = strings.TrimSpace(bean), strings.TrimSpace(clickupStatus)
+		if !ok || bean == "" || clickupStatus == "" {
+			return nil, fmt.Errorf("invalid --status-map %q: want status=name", v)
+		}
+		entries = append(entries, statusMapEntry{Bean: bean, ClickUp: clickupStatus})
+	}
+	return entries, nil
+}
+
 const configTemplate = `# bean-me-up ClickUp configuration
 # Generated by: beanup init
 extensions:
@@ -258,19 +356,38 @@ extensions:
     # ClickUp list to sync tasks to
     # List: {{.ListName}}
     list_id: "{{.ListID}}"
+{{- if .TeamID}}
+
+    # ClickUp workspace for custom task types and other workspace lookups
+    team_id: "{{.TeamID}}"
+{{- end}}
+{{- if .Assignee}}
+
+    # ClickUp user to assign new tasks to
+    assignee: {{.Assignee}}
+{{- end}}
 
     # Status mapping: bean status -> ClickUp status
+{{- if not .StatusMapping}}
     # Uncomment and customize to match your workflow
+{{- end}}
     # Available statuses on this list:
 {{- range .Statuses}}
     #   - "{{.}}"
 {{- end}}
+{{- if .StatusMapping}}
+    status_mapping:
+{{- range .StatusMapping}}
+      {{.Bean}}: {{printf "%q" .ClickUp}}
+{{- end}}
+{{- else}}
     # status_mapping:
     #   draft: "backlog"
     #   todo: "to do"
     #   in-progress: "in progress"
     #   completed: "complete"
     #   scrapped: "closed"
+{{- end}}
 {{if .CustomItems}}
     # Type mapping: bean type -> ClickUp custom task type ID
     # This maps bean types (bug, feature, milestone, etc.) to ClickUp task types
@@ -291,10 +408,17 @@ extensions:
 {{- range .CustomFields}}
     #   - "{{.Name}}" ({{.Type}}): {{.ID}}
 {{- end}}
+{{- if .FieldMapping}}
+    custom_fields:
+      bean_id: "{{.FieldMapping.BeanID}}"
+      created_at: "{{.FieldMapping.CreatedAt}}"
+      updated_at: "{{.FieldMapping.UpdatedAt}}"
+{{- else}}
     # custom_fields:
     #   bean_id: "uuid-for-text-field"
     #   created_at: "uuid-for-date-field"
     #   updated_at: "uuid-for-date-field"
+{{- end}}
 {{end}}
     # Optional: Control which beans are synced
     # sync_filter:
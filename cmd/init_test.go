@@ -3,6 +3,9 @@ package cmd
 import (
 	"strings"
 	"testing"
+
+	"github.com/toba/bean-me-up/internal/config"
+	"gopkg.in/yaml.v3"
 )
 
 func TestGenerateConfig(t *testing.T) {
@@ -73,3 +76,66 @@ func TestGenerateConfig_NoOptionalData(t *testing.T) {
 		t.Error("should not have custom fields section when no fields provided")
 	}
 }
+
+func TestGenerateConfig_Settings(t *testing.T) {
+	assignee := 0
+	result, err := generateConfig(configTemplateData{
+		ListID:   "999",
+		ListName: "List",
+		Assignee: &assignee,
+		StatusMapping: []statusMapEntry{
+			{Bean: "todo", ClickUp: "to do"},
+			{Bean: "completed", ClickUp: "complete"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed struct {
+		Extensions struct {
+			ClickUp struct {
+				Assignee      *int              `yaml:"assignee"`
+				StatusMapping map[string]string `yaml:"status_mapping"`
+			} `yaml:"clickup"`
+		} `yaml:"extensions"`
+	}
+	if err := yaml.Unmarshal([]byte(result), &parsed); err != nil {
+		t.Fatalf("generated config is not valid YAML: %v\n%s", err, result)
+	}
+	cu := parsed.Extensions.ClickUp
+	if cu.Assignee == nil || *cu.Assignee != 0 {
+		t.Errorf("assignee = %v, want 0\n%s", cu.Assignee, result)
+	}
+	if cu.StatusMapping["todo"] != "to do" || cu.StatusMapping["completed"] != "complete" {
+		t.Errorf("status_mapping = %v\n%s", cu.StatusMapping, result)
+	}
+}
+
+func TestParseStatusMap(t *testing.T) {
+	entries, err := parseStatusMap([]string{"todo=to do", " completed = complete "})
+	if err != nil || len(entries) != 2 || entries[0].ClickUp != "to do" || entries[1].Bean != "completed" {
+		t.Errorf("parseStatusMap() = %+v, %v", entries, err)
+	}
+	for _, bad := range []string{"todo", "=x", "todo="} {
+		if _, err := parseStatusMap([]string{bad}); err == nil {
+			t.Errorf("parseStatusMap(%q) should fail", bad)
+		}
+	}
+}
+
+func TestGenerateConfig_FieldMapping(t *testing.T) {
+	result, err := generateConfig(configTemplateData{
+		ListID:       "999",
+		ListName:     "List",
+		CustomFields: []fieldEntry{{Name: "Bean ID", Type: "short_text", ID: "f1"}},
+		FieldMapping: &config.CustomFieldsMap{BeanID: "f1", CreatedAt: "f2", UpdatedAt: "f3"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`      bean_id: "f1"`, `      created_at: "f2"`, `      updated_at: "f3"`} {
+		if !strings.Contains(result, "\n"+want+"\n") {
+			t.Errorf("missing %q in:\n%s", want, result)
+		}
+	}
+}
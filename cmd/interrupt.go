@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// errInterrupted is the cause of a context cancelled by Ctrl-C or SIGTERM.
+var errInterrupted = errors.New("interrupted")
+
+// interruptContext returns a context that the first Ctrl-C (or SIGTERM)
+// cancels, so a command can stop its requests and save what completed. A
+// second Ctrl-C kills the process as usual. Call stop when done.
+func interruptContext(parent context.Context) (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancelCause(parent)
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-sigCh:
+			signal.Stop(sigCh)
+			fmt.Fprintln(os.Stderr, "\nInterrupted; saving what completed (press Ctrl-C again to quit now)")
+			cancel(errInterrupted)
+		case <-ctx.Done():
+			signal.Stop(sigCh)
+		}
+	}()
+	return ctx, func() { cancel(nil) }
+}
+
+// interrupted reports whether ctx was cancelled by interruptContext.
+func interrupted(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errInterrupted)
+}
+
+// interruptedError is returned by a command that stopped early after an
+// interrupt; what says what was kept.
+func interruptedError(what string) error {
+	return withExitCode(exitInterrupted, fmt.Errorf("interrupted; %s", what))
+}
@@ -0,0 +1,36 @@
+//go:build unix
+
+package cmd
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestInterruptContext(t *testing.T) {
+	ctx, stop := interruptContext(context.Background())
+	defer stop()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGINT); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("SIGINT didn't cancel the context")
+	}
+	if !interrupted(ctx) {
+		t.Errorf("interrupted() = false, cause %v", context.Cause(ctx))
+	}
+	if got := ExitCode(interruptedError("nothing kept")); got != exitInterrupted {
+		t.Errorf("exit code = %d, want %d", got, exitInterrupted)
+	}
+
+	ctx, stop = interruptContext(context.Background())
+	stop()
+	if ctx.Err() == nil || interrupted(ctx) {
+		t.Error("stop should cancel the context without counting as an interrupt")
+	}
+}
@@ -1,25 +1,58 @@
 package cmd
 
 import (
-	"context"
+	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/toba/bean-me-up/internal/beans"
-	"github.com/toba/bean-me-up/internal/clickup"
 	"github.com/spf13/cobra"
 )
 
+var (
+	linkFromFile  string
+	linkAutoMatch bool
+	linkThreshold float64
+	linkYes       bool
+)
+
 var linkCmd = &cobra.Command{
-	Use:   "link <bean-id> <task-id>",
+	Use:   "link <bean-id> <task-id> | --from-file <mapping.csv> | --auto",
 	Short: "Link a bean to an existing ClickUp task",
 	Long: `Manually links a bean to an existing ClickUp task by storing
 the task ID in the bean's extension metadata.
 
 This is useful when you have an existing ClickUp task that you want to
-associate with a bean, or when syncing fails and you need to fix the link.`,
-	Args: cobra.ExactArgs(2),
+associate with a bean, or when syncing fails and you need to fix the link.
+
+With --from-file, links are restored in bulk from a CSV file with bean_id
+and task_id columns (and optionally synced_at), such as one written by
+"beanup export". Beans that no longer exist are skipped.
+
+With --auto, tasks in the configured list whose names closely match the
+titles of unlinked beans are offered for linking one pair at a time.
+Titles are compared ignoring case and punctuation; --threshold sets how
+close a match must be (0.8 means at most one edit in five characters).
+Tasks already linked or carrying a Bean ID custom field are not offered.
+Pass --yes to link every match without asking.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if linkFromFile != "" || linkAutoMatch {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if linkFromFile != "" {
+			return linkFromMapping(linkFromFile)
+		}
+		if linkAutoMatch {
+			return linkAuto(cmd.Context())
+		}
+
 		beanID := args[0]
 		taskID := args[1]
 
@@ -43,8 +76,8 @@ associate with a bean, or when syncing fails and you need to fix the link.`,
 		// Try to verify the task exists if we have a token
 		token, tokenErr := getClickUpToken()
 		if tokenErr == nil {
-			client := clickup.NewClient(token)
-			ctx := context.Background()
+			ctx := cmd.Context()
+			client := newClickUpClient(ctx, token)
 			if _, err := client.GetTask(ctx, taskID); err != nil {
 				// Warn but don't fail
 				fmt.Printf("Warning: Could not verify task %s: %v\n", taskID, err)
@@ -70,9 +103,162 @@ associate with a bean, or when syncing fails and you need to fix the link.`,
 }
 
 func init() {
+	linkCmd.Flags().StringVar(&linkFromFile, "from-file", "", "restore links from a CSV file with bean_id and task_id columns")
+	linkCmd.Flags().BoolVar(&linkAutoMatch, "auto", false, "link unlinked beans to list tasks with similar names")
+	linkCmd.Flags().Float64Var(&linkThreshold, "threshold", 0.8, "minimum title similarity for --auto, from 0 to 1")
+	linkCmd.Flags().BoolVarP(&linkYes, "yes", "y", false, "with --auto, link all matches without asking")
+	linkCmd.MarkFlagsMutuallyExclusive("from-file", "auto")
 	rootCmd.AddCommand(linkCmd)
 }
 
+// linkMapping is one row of a link --from-file mapping.
+type linkMapping struct {
+	BeanID   string
+	TaskID   string
+	SyncedAt *time.Time
+}
+
+// parseLinkMappings reads a CSV mapping with a header row. Column names are
+// matched case-insensitively with spaces treated as underscores, so both
+// bean_id and the "Bean ID" header written by export are accepted.
+func parseLinkMappings(r io.Reader) ([]linkMapping, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	col := map[string]int{}
+	for i, h := range header {
+		col[strings.ReplaceAll(strings.ToLower(strings.TrimSpace(h)), " ", "_")] = i
+	}
+	beanCol, ok := col["bean_id"]
+	if !ok {
+		return nil, errors.New("missing bean_id column")
+	}
+	taskCol, ok := col["task_id"]
+	if !ok {
+		return nil, errors.New("missing task_id column")
+	}
+	syncedCol, hasSynced := col["synced_at"]
+
+	cell := func(record []string, i int) string {
+		if i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var mappings []linkMapping
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return mappings, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		m := linkMapping{BeanID: cell(record, beanCol), TaskID: cell(record, taskCol)}
+		if m.BeanID == "" || m.TaskID == "" {
+			// Unlinked rows from export --all
+			continue
+		}
+		if hasSynced {
+			if v := cell(record, syncedCol); v != "" {
+				t, err := time.Parse(time.RFC3339, v)
+				if err != nil {
+					line, _ := cr.FieldPos(syncedCol)
+					return nil, fmt.Errorf("line %d: invalid synced_at %q", line, v)
+				}
+				m.SyncedAt = &t
+			}
+		}
+		mappings = append(mappings, m)
+	}
+}
+
+// linkFromMapping writes the links in a CSV mapping file to bean extension
+// metadata in one batch.
+func linkFromMapping(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	mappings, err := parseLinkMappings(f)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	beansClient := beans.NewClient(getBeansPath())
+	allBeans, err := beansClient.List()
+	if err != nil {
+		return fmt.Errorf("listing beans: %w", err)
+	}
+	existing := make(map[string]*beans.Bean, len(allBeans))
+	for i := range allBeans {
+		existing[allBeans[i].ID] = &allBeans[i]
+	}
+
+	var (
+		ops     []beans.ExtensionDataOp
+		results []map[string]string
+	)
+	now := time.Now().UTC()
+	for _, m := range mappings {
+		result := map[string]string{"bean_id": m.BeanID, "task_id": m.TaskID}
+		bean, ok := existing[m.BeanID]
+		switch {
+		case !ok:
+			result["action"] = "not_found"
+		case bean.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID) == m.TaskID:
+			result["action"] = "already_linked"
+		default:
+			syncedAt := now
+			if m.SyncedAt != nil {
+				syncedAt = m.SyncedAt.UTC()
+			}
+			ops = append(ops, beans.ExtensionDataOp{
+				ID:   m.BeanID,
+				Name: beans.PluginClickUp,
+				Data: map[string]any{
+					beans.ExtKeyTaskID:   m.TaskID,
+					beans.ExtKeySyncedAt: syncedAt.Format(time.RFC3339),
+				},
+			})
+			result["action"] = "linked"
+		}
+		if ok {
+			result["bean_title"] = bean.Title
+		}
+		results = append(results, result)
+	}
+
+	if len(ops) > 0 {
+		if err := beansClient.SetExtensionDataBatch(ops); err != nil {
+			return fmt.Errorf("saving sync state: %w", err)
+		}
+	}
+
+	if jsonOut {
+		return outputJSON(results)
+	}
+	missing := 0
+	for _, r := range results {
+		if r["action"] == "not_found" {
+			missing++
+			fmt.Printf("Skipped: %s not found\n", r["bean_id"])
+		}
+	}
+	fmt.Printf("Linked %d bean(s), %d already linked, %d not found\n",
+		len(ops), len(results)-len(ops)-missing, missing)
+	return nil
+}
+
 func outputLinkJSON(bean *beans.Bean, taskID, action string) error {
 	result := map[string]string{
 		"bean_id":    bean.ID,
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/clickup"
+)
+
+// titleMatch pairs an unlinked bean with a task whose name resembles its
+// title.
+type titleMatch struct {
+	Bean  beans.Bean
+	Task  clickup.TaskInfo
+	Score float64
+}
+
+// linkAuto links unlinked beans to tasks in the configured list by fuzzy
+// title match, after the user confirms each pair (or all with --yes).
+func linkAuto(ctx context.Context) error {
+	if linkThreshold <= 0 || linkThreshold > 1 {
+		return fmt.Errorf("--threshold must be between 0 and 1")
+	}
+	if err := requireListID(); err != nil {
+		return err
+	}
+	token, err := getClickUpToken()
+	if err != nil {
+		return err
+	}
+
+	beansClient := beans.NewClient(getBeansPath())
+	allBeans, err := beansClient.List()
+	if err != nil {
+		return fmt.Errorf("listing beans: %w", err)
+	}
+	tasks, err := newClickUpClient(ctx, token).GetListTasks(ctx, cfg.Beans.ClickUp.ListID)
+	if err != nil {
+		return err
+	}
+
+	// Tasks already linked, or tagged with a bean ID, are not candidates
+	taken := make(map[string]bool)
+	var unlinked []beans.Bean
+	for _, b := range allBeans {
+		if taskID := b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID); taskID != "" {
+			taken[taskID] = true
+		} else {
+			unlinked = append(unlinked, b)
+		}
+	}
+	var beanIDField string
+	if cf := cfg.Beans.ClickUp.CustomFields; cf != nil {
+		beanIDField = cf.BeanID
+	}
+	tasks = slices.DeleteFunc(tasks, func(t clickup.TaskInfo) bool {
+		return taken[t.ID] || (beanIDField != "" && t.CustomFieldString(beanIDField) != "")
+	})
+
+	matches := matchTitles(unlinked, tasks, linkThreshold)
+	if len(matches) == 0 {
+		if jsonOut {
+			return outputJSON([]map[string]string{})
+		}
+		fmt.Printf("No task names match unlinked bean titles (threshold %.0f%%)\n", linkThreshold*100)
+		return nil
+	}
+
+	accepted := matches
+	if !linkYes {
+		accepted, err = reviewMatches(os.Stdin, os.Stderr, matches)
+		if err != nil {
+			return err
+		}
+	}
+
+	var ops []beans.ExtensionDataOp
+	results := make([]map[string]string, 0, len(accepted))
+	for _, m := range accepted {
+		// Link only; leaving synced_at unset makes the next sync push the bean
+		ops = append(ops, beans.ExtensionDataOp{
+			ID:   m.Bean.ID,
+			Name: beans.PluginClickUp,
+			Data: map[string]any{beans.ExtKeyTaskID: m.Task.ID},
+		})
+		results = append(results, map[string]string{
+			"bean_id":    m.Bean.ID,
+			"bean_title": m.Bean.Title,
+			"task_id":    m.Task.ID,
+			"task_name":  m.Task.Name,
+			"score":      fmt.Sprintf("%.2f", m.Score),
+			"action":     "linked",
+		})
+	}
+	if len(ops) > 0 {
+		if err := beansClient.SetExtensionDataBatch(ops); err != nil {
+			return fmt.Errorf("saving sync state: %w", err)
+		}
+	}
+
+	if jsonOut {
+		return outputJSON(results)
+	}
+	for _, r := range results {
+		fmt.Printf("Linked: %s → %s\n", r["bean_id"], r["task_id"])
+	}
+	fmt.Printf("Linked %d of %d candidate(s)\n", len(accepted), len(matches))
+	return nil
+}
+
+// matchTitles pairs beans with tasks whose normalized names score at least
+// threshold, best matches first. Each bean and task is used at most once.
+func matchTitles(beanList []beans.Bean, tasks []clickup.TaskInfo, threshold float64) []titleMatch {
+	taskNames := make([]string, len(tasks))
+	for i, t := range tasks {
+		taskNames[i] = normalizeTitle(t.Name)
+	}
+
+	var candidates []titleMatch
+	for _, b := range beanList {
+		title := normalizeTitle(b.Title)
+		if title == "" {
+			continue
+		}
+		for i, name := range taskNames {
+			if score := titleSimilarity(title, name); score >= threshold {
+				candidates = append(candidates, titleMatch{Bean: b, Task: tasks[i], Score: score})
+			}
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b titleMatch) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		}
+		return 0
+	})
+
+	usedBeans, usedTasks := make(map[string]bool), make(map[string]bool)
+	var matches []titleMatch
+	for _, c := range candidates {
+		if usedBeans[c.Bean.ID] || usedTasks[c.Task.ID] {
+			continue
+		}
+		usedBeans[c.Bean.ID], usedTasks[c.Task.ID] = true, true
+		matches = append(matches, c)
+	}
+	return matches
+}
+
+// normalizeTitle lowercases s, turns punctuation into spaces, and collapses
+// whitespace, so "Fix: login-page bug" matches "fix login page bug".
+func normalizeTitle(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// titleSimilarity scores two normalized titles from 0 to 1 by edit distance
+// relative to the longer title.
+func titleSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// reviewMatches asks about each candidate pair and returns those accepted.
+// Answers are y (link), n (skip), a (link this and the rest), q (stop).
+func reviewMatches(in io.Reader, out io.Writer, matches []titleMatch) ([]titleMatch, error) {
+	reader := bufio.NewReader(in)
+	var accepted []titleMatch
+	for i, m := range matches {
+		_, _ = colorBold.Fprintf(out, "\n[%d/%d] %s %q\n", i+1, len(matches), m.Bean.ID, m.Bean.Title)
+		_, _ = fmt.Fprintf(out, "  → %s %q (%.0f%% match)\n", m.Task.ID, m.Task.Name, m.Score*100)
+
+		for answered := false; !answered; {
+			_, _ = colorCyan.Fprint(out, "Link [y,n,a,q]? ")
+			line, err := reader.ReadString('\n')
+			if err != nil && line == "" {
+				return nil, fmt.Errorf("no answer to confirmation prompt (pass --yes to link all matches)")
+			}
+			switch strings.ToLower(strings.TrimSpace(line)) {
+			case "y":
+				accepted = append(accepted, m)
+				answered = true
+			case "n":
+				answered = true
+			case "a":
+				return append(accepted, matches[i:]...), nil
+			case "q":
+				return accepted, nil
+			default:
+				_, _ = fmt.Fprintln(out, "y - link, n - skip, a - link this and all remaining, q - stop here")
+			}
+		}
+	}
+	return accepted, nil
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/clickup"
+)
+
+func TestParseLinkMappings(t *testing.T) {
+	// Header as written by beanup export
+	input := `Bean ID,Title,Status,Task ID,Task URL,Synced At,Sync
+bean-a,"Fix a, b",todo,abc,https://app.clickup.com/t/abc,2026-03-01T12:00:00Z,in sync
+bean-b,Plan,draft,,,,not linked
+bean-c,Ship,todo,def,,,bean ahead
+`
+	mappings, err := parseLinkMappings(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parseLinkMappings() error = %v", err)
+	}
+	if len(mappings) != 2 {
+		t.Fatalf("got %d mappings, want 2: %+v", len(mappings), mappings)
+	}
+	if m := mappings[0]; m.BeanID != "bean-a" || m.TaskID != "abc" || m.SyncedAt == nil {
+		t.Errorf("mappings[0] = %+v", m)
+	}
+	if m := mappings[1]; m.BeanID != "bean-c" || m.TaskID != "def" || m.SyncedAt != nil {
+		t.Errorf("mappings[1] = %+v", m)
+	}
+
+	if _, err := parseLinkMappings(strings.NewReader("bean_id,task\nbean-a,abc\n")); err == nil {
+		t.Error("expected error for missing task_id column")
+	}
+	if _, err := parseLinkMappings(strings.NewReader("bean_id,task_id,synced_at\nbean-a,abc,yesterday\n")); err == nil {
+		t.Error("expected error for invalid synced_at")
+	}
+}
+
+func TestMatchTitles(t *testing.T) {
+	beanList := []beans.Bean{
+		{ID: "bean-a", Title: "Fix: login-page bug"},
+		{ID: "bean-b", Title: "Add dark mode"},
+		{ID: "bean-c", Title: "Write release notes"},
+	}
+	tasks := []clickup.TaskInfo{
+		{ID: "t1", Name: "fix login page bugs"},
+		{ID: "t2", Name: "Add dark mode toggle to settings"},
+		{ID: "t3", Name: "Fix login page bug"},
+	}
+
+	matches := matchTitles(beanList, tasks, 0.8)
+	if len(matches) != 1 {
+		t.Fatalf("got %d matches, want 1: %+v", len(matches), matches)
+	}
+	// The exact match wins over the near one, and each bean is used once
+	if m := matches[0]; m.Bean.ID != "bean-a" || m.Task.ID != "t3" || m.Score != 1 {
+		t.Errorf("match = %s → %s (%.2f), want bean-a → t3 (1.00)", m.Bean.ID, m.Task.ID, m.Score)
+	}
+
+	if got := titleSimilarity("kitten", "sitting"); got < 0.57 || got > 0.58 {
+		t.Errorf("titleSimilarity(kitten, sitting) = %.3f, want 4/7", got)
+	}
+}
+
+func TestReviewMatches(t *testing.T) {
+	matches := []titleMatch{
+		{Bean: beans.Bean{ID: "a"}, Task: clickup.TaskInfo{ID: "t1"}},
+		{Bean: beans.Bean{ID: "b"}, Task: clickup.TaskInfo{ID: "t2"}},
+		{Bean: beans.Bean{ID: "c"}, Task: clickup.TaskInfo{ID: "t3"}},
+	}
+	accepted, err := reviewMatches(strings.NewReader("n\n?\ny\nq\n"), io.Discard, matches)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accepted) != 1 || accepted[0].Bean.ID != "b" {
+		t.Errorf("accepted = %+v, want only b", accepted)
+	}
+	if _, err := reviewMatches(strings.NewReader(""), io.Discard, matches); err == nil {
+		t.Error("expected error with no input")
+	}
+}
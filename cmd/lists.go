@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var listsCmd = &cobra.Command{
+	Use:   "lists <folder-or-space-id>",
+	Short: "List the ClickUp lists in a folder or space",
+	Long: `Lists the ClickUp lists in a folder, or the lists directly in a space
+(outside any folder). The ID is tried as a folder first, then as a space.
+
+Put the chosen ID in extensions.clickup.list_id, or pass it to beanup init.
+
+Requires CLICKUP_TOKEN environment variable to be set.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		token, err := getClickUpToken()
+		if err != nil {
+			return err
+		}
+		client := newClickUpClient(ctx, token)
+
+		lists, folderErr := client.GetFolderLists(ctx, args[0])
+		if folderErr != nil {
+			var spaceErr error
+			if lists, spaceErr = client.GetSpaceLists(ctx, args[0]); spaceErr != nil {
+				return fmt.Errorf("%s is not an accessible folder or space: %w", args[0], folderErr)
+			}
+		}
+
+		if jsonOut {
+			return outputJSON(lists)
+		}
+
+		if len(lists) == 0 {
+			fmt.Println("No lists found.")
+			return nil
+		}
+		for _, l := range lists {
+			fmt.Printf("%s\n  ID: %s\n", l.Name, l.ID)
+		}
+		fmt.Println()
+		fmt.Println("Next: beanup init <list-id>")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listsCmd)
+}
@@ -16,6 +16,7 @@ import (
 var (
 	migrateDryRun         bool
 	migrateDeleteSyncFile bool
+	migrateToSyncFileFlag bool
 )
 
 var migrateCmd = &cobra.Command{
@@ -30,13 +31,30 @@ var migrateCmd = &cobra.Command{
    metadata.
 
 Use --dry-run to preview the migration without making changes.
-Use --delete-sync-file to also remove .sync.json after a successful migration.`,
+Use --delete-sync-file to also remove .sync.json after a successful migration.
+
+Use --to-sync-file to go the other way: export the ClickUp links in bean
+extension metadata into .beans/.sync.json, e.g. to roll back to an older
+beanup or beans CLI. Existing .sync.json entries for other beans are kept,
+and the extension metadata is left in place.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		bp := getBeansPath()
 		if bp == "" {
 			bp = ".beans"
 		}
 
+		if !migrateDryRun {
+			projectLock, err := acquireProjectLock(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer func() { _ = projectLock.Release() }()
+		}
+
+		if migrateToSyncFileFlag {
+			return migrateToSyncFile(bp, migrateDryRun)
+		}
+
 		// Migrate .beans.clickup.yml → .beans.yml extensions.clickup
 		if err := migrateConfig(bp, migrateDryRun); err != nil {
 			return err
@@ -93,7 +111,7 @@ Use --delete-sync-file to also remove .sync.json after a successful migration.`,
 			ops = append(ops, beans.ExtensionDataOp{
 				ID:   beanID,
 				Name: beans.PluginClickUp,
-				Data:   data,
+				Data: data,
 			})
 		}
 
@@ -140,6 +158,53 @@ Use --delete-sync-file to also remove .sync.json after a successful migration.`,
 	},
 }
 
+// migrateToSyncFile writes the ClickUp task ID and sync time from each
+// bean's extension metadata into the legacy .sync.json.
+func migrateToSyncFile(beansPath string, dryRun bool) error {
+	allBeans, err := beans.NewClient(beansPath).List()
+	if err != nil {
+		return fmt.Errorf("listing beans: %w", err)
+	}
+
+	store, err := syncstate.Load(beansPath)
+	if err != nil {
+		return fmt.Errorf("loading sync state: %w", err)
+	}
+
+	var linked []beans.Bean
+	for _, b := range allBeans {
+		if b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID) != "" {
+			linked = append(linked, b)
+		}
+	}
+	if len(linked) == 0 {
+		fmt.Println("No beans have ClickUp extension metadata — nothing to export.")
+		return nil
+	}
+
+	syncFilePath := filepath.Join(beansPath, syncstate.SyncFileName)
+	if dryRun {
+		fmt.Printf("Would export %d bean(s) to %s:\n", len(linked), syncFilePath)
+		for _, b := range linked {
+			fmt.Printf("  %s → task_id=%s\n", b.ID, b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID))
+		}
+		return nil
+	}
+
+	for _, b := range linked {
+		store.SetTaskID(b.ID, b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID))
+		if syncedAt := b.GetExtensionTime(beans.PluginClickUp, beans.ExtKeySyncedAt); syncedAt != nil {
+			store.SetSyncedAt(b.ID, *syncedAt)
+		}
+	}
+	if err := store.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %d bean(s) to %s.\n", len(linked), syncFilePath)
+	return nil
+}
+
 // migrateConfig moves ClickUp configuration from .beans.clickup.yml into .beans.yml
 // under extensions.clickup, then deletes the legacy file.
 func migrateConfig(beansPath string, dryRun bool) error {
@@ -159,7 +224,7 @@ func migrateConfig(beansPath string, dryRun bool) error {
 	}
 
 	// Load the legacy config
-	legacyCfg, err := config.Load(legacyPath)
+	legacyCfg, err := config.Load(legacyPath, nil)
 	if err != nil {
 		return fmt.Errorf("loading %s: %w", config.LegacyConfigFileName, err)
 	}
@@ -250,5 +315,7 @@ func migrateConfig(beansPath string, dryRun bool) error {
 func init() {
 	migrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Preview migration without making changes")
 	migrateCmd.Flags().BoolVar(&migrateDeleteSyncFile, "delete-sync-file", false, "Delete .sync.json after successful migration")
+	migrateCmd.Flags().BoolVar(&migrateToSyncFileFlag, "to-sync-file", false, "Export extension metadata back into .sync.json instead")
+	migrateCmd.MarkFlagsMutuallyExclusive("to-sync-file", "delete-sync-file")
 	rootCmd.AddCommand(migrateCmd)
 }
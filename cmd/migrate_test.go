@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/syncstate"
+)
+
+func TestMigrateToSyncFile(t *testing.T) {
+	beans.NoCLI = true
+	defer func() { beans.NoCLI = false }()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"bup-a--linked.md":   "---\ntitle: Linked\nstatus: todo\nextensions:\n    clickup:\n        synced_at: \"2026-02-08T23:56:52Z\"\n        task_id: 868abc\n---\n",
+		"bup-b--unlinked.md": "---\ntitle: Unlinked\nstatus: todo\n---\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Links already in .sync.json for other beans survive the export
+	store, _ := syncstate.Load(dir)
+	store.SetTaskID("bup-old", "868old")
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := migrateToSyncFile(dir, false); err != nil {
+		t.Fatal(err)
+	}
+	store, err := syncstate.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := store.GetTaskID("bup-a"); id == nil || *id != "868abc" {
+		t.Errorf("bup-a task = %v, want 868abc", id)
+	}
+	if at := store.GetSyncedAt("bup-a"); at == nil || at.Minute() != 56 {
+		t.Errorf("bup-a synced at = %v", at)
+	}
+	if store.GetTaskID("bup-b") != nil || store.GetTaskID("bup-old") == nil {
+		t.Errorf("entries = %v", store.GetAllBeans())
+	}
+}
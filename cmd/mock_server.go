@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/clickuptest"
+	"github.com/spf13/cobra"
+)
+
+var mockServerAddr string
+
+var mockServerCmd = &cobra.Command{
+	Use:    "mock-server",
+	Short:  "Run an in-memory ClickUp API for end-to-end tests",
+	Hidden: true,
+	Long: `Serves a mock ClickUp API from memory until interrupted. It has one
+workspace, space, and list (ID 100) with the default statuses, and supports
+tasks, tags, custom fields, dependencies, comments, attachments, and webhooks.
+
+Point beanup at it with CLICKUP_API_URL; any token is accepted:
+
+  beanup mock-server --addr 127.0.0.1:7357 &
+  CLICKUP_API_URL=http://127.0.0.1:7357 CLICKUP_TOKEN=test beanup sync`,
+	Args: cobra.NoArgs,
+	RunE: runMockServer,
+}
+
+func init() {
+	mockServerCmd.Flags().StringVar(&mockServerAddr, "addr", "127.0.0.1:7357", "Address to listen on")
+	rootCmd.AddCommand(mockServerCmd)
+}
+
+func runMockServer(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+
+	ln, err := net.Listen("tcp", mockServerAddr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", mockServerAddr, err)
+	}
+	mock := clickuptest.New()
+	srv := &http.Server{Handler: mock, ReadHeaderTimeout: 10 * time.Second}
+	fmt.Printf("Mock ClickUp API on http://%s (list %s)\n", ln.Addr(), clickuptest.ListID)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(ln) }()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	mock.Close()
+	return nil
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/clickup"
+)
+
+var openPrint bool
+
+var openCmd = &cobra.Command{
+	Use:   "open <bean-id>",
+	Short: "Open a bean's ClickUp task in the browser",
+	Long: `Opens the ClickUp task linked to a bean in your default browser.
+
+The task is looked up to get its URL and confirm it still exists; without a
+ClickUp token the standard app.clickup.com/t/<task-id> URL is used.
+
+Use --print to write the URL to stdout instead, e.g. for scripting:
+
+  beanup open bean-abc1 --print | pbcopy`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		beansClient := beans.NewClient(getBeansPath())
+		bean, err := beansClient.Get(args[0])
+		if err != nil {
+			return fmt.Errorf("bean not found: %s", args[0])
+		}
+		taskID := bean.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID)
+		if taskID == "" {
+			return fmt.Errorf("%s is not linked to a ClickUp task (run beanup sync or beanup link)", bean.ID)
+		}
+
+		url := clickup.TaskURL(taskID)
+		if clickup.IsListRef(taskID) {
+			// An epic list has no task to look up; its URL was stored on sync
+			url = bean.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskURL)
+			if url == "" {
+				return fmt.Errorf("%s is an epic synced as a ClickUp list with no stored URL (run beanup sync)", bean.ID)
+			}
+		} else if token, err := getClickUpToken(); err == nil {
+			task, err := newClickUpClient(cmd.Context(), token).GetTask(cmd.Context(), taskID)
+			switch {
+			case errors.Is(err, clickup.ErrTaskNotFound):
+				return fmt.Errorf("task %s linked to %s no longer exists in ClickUp", taskID, bean.ID)
+			case err != nil:
+				return fmt.Errorf("fetching task: %w", err)
+			case task.URL != "":
+				url = task.URL
+			}
+		}
+
+		if jsonOut {
+			return outputJSON(map[string]string{
+				"bean_id":  bean.ID,
+				"task_id":  taskID,
+				"task_url": url,
+			})
+		}
+		if openPrint {
+			fmt.Println(url)
+			return nil
+		}
+		if err := openBrowser(url); err != nil {
+			return fmt.Errorf("opening browser (use --print to get the URL): %w", err)
+		}
+		fmt.Printf("Opened %s\n", url)
+		return nil
+	},
+}
+
+func init() {
+	openCmd.Flags().BoolVar(&openPrint, "print", false, "print the task URL instead of opening it")
+	rootCmd.AddCommand(openCmd)
+}
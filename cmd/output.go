@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// noColor turns off colored output, as do NO_COLOR and output that
+	// isn't a terminal
+	noColor bool
+	// quiet discards standard output other than --json data; errors and
+	// warnings still go to stderr
+	quiet bool
+	// verbose shows per-bean detail that is otherwise only counted
+	verbose bool
+)
+
+// setupOutput applies the output flags to the whole run of cmd.
+func setupOutput(cmd *cobra.Command) error {
+	if quiet && verbose {
+		return fmt.Errorf("--quiet and --verbose can't be combined")
+	}
+	// fatih/color already honors NO_COLOR and non-terminal stdout
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		color.NoColor = true
+	}
+	// init prompts on stdout, and completion scripts are the output itself
+	interactive := cmd.Name() == "init" || (cmd.HasParent() && cmd.Parent().Name() == "completion") ||
+		cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd
+	if quiet && !jsonOut && !interactive {
+		devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		if err != nil {
+			return fmt.Errorf("opening %s: %w", os.DevNull, err)
+		}
+		os.Stdout = devNull
+		color.Output = io.Discard
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+// progressBarWidth is the number of cells in the progress bar.
+const progressBarWidth = 24
+
+// syncProgress reports sync progress as results arrive: a redrawn bar with
+// counters, rate, and ETA on a terminal, or a dot per bean ("x" for errors)
+// otherwise, so logs and pipes don't fill with carriage returns.
+type syncProgress struct {
+	out   io.Writer
+	total int
+	tty   bool
+	start time.Time
+	now   func() time.Time
+
+	created, updated, errors int
+}
+
+func newSyncProgress(out io.Writer, total int) *syncProgress {
+	return &syncProgress{
+		out:   out,
+		total: total,
+		tty:   isTerminal(out),
+		start: time.Now(),
+		now:   time.Now,
+	}
+}
+
+// Update records a finished bean. Calls must not overlap.
+func (p *syncProgress) Update(result syncer.Result, completed int) {
+	switch result.Action {
+	case "created", "would create":
+		p.created++
+	case "updated", "would update":
+		p.updated++
+	}
+	if result.Error != nil {
+		p.errors++
+	}
+
+	if !p.tty {
+		if result.Error != nil {
+			_, _ = fmt.Fprint(p.out, "x")
+		} else {
+			_, _ = fmt.Fprint(p.out, ".")
+		}
+		return
+	}
+	_, _ = fmt.Fprint(p.out, "\r"+p.line(completed)+"\033[K")
+}
+
+// line renders the progress bar and counters for completed of total beans.
+func (p *syncProgress) line(completed int) string {
+	filled := progressBarWidth * completed / max(p.total, 1)
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+
+	line := fmt.Sprintf("%s %d/%d  %d created  %d updated  %d errors", bar, completed, p.total, p.created, p.updated, p.errors)
+
+	elapsed := p.now().Sub(p.start)
+	if completed > 0 && elapsed > 0 {
+		rate := float64(completed) / elapsed.Seconds()
+		line += fmt.Sprintf("  %.1f/s", rate)
+		if remaining := p.total - completed; remaining > 0 {
+			eta := time.Duration(float64(remaining) / rate * float64(time.Second))
+			line += "  ETA " + eta.Round(time.Second).String()
+		}
+	}
+	return line
+}
+
+// isTerminal reports whether w is an interactive terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
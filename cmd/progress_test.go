@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+func TestSyncProgress(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+
+	var out strings.Builder
+	p := newSyncProgress(&out, 8)
+	p.start, p.now = start, func() time.Time { return now }
+
+	// A strings.Builder isn't a terminal, so dots are the fallback
+	p.Update(syncer.Result{Action: "created"}, 1)
+	p.Update(syncer.Result{Action: "error", Error: errors.New("boom")}, 2)
+	if out.String() != ".x" {
+		t.Errorf("non-terminal output = %q, want %q", out.String(), ".x")
+	}
+
+	p.Update(syncer.Result{Action: "updated"}, 3)
+	p.Update(syncer.Result{Action: "unchanged"}, 4)
+	now = start.Add(2 * time.Second)
+	want := "████████████░░░░░░░░░░░░ 4/8  1 created  1 updated  1 errors  2.0/s  ETA 2s"
+	if got := p.line(4); got != want {
+		t.Errorf("line() = %q\nwant     %q", got, want)
+	}
+}
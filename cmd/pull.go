@@ -0,0 +1,501 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/audit"
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/clickup"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pullDryRun      bool
+	pullAttachments bool
+)
+
+var pullCmd = &cobra.Command{
+	Use:   "pull [bean-id...]",
+	Short: "Copy fields changed in ClickUp back into beans",
+	Long: `Fetches the ClickUp tasks linked to beans and writes fields that were
+changed in ClickUp back into the beans, so the next sync doesn't undo them.
+
+Pulled fields:
+  type       the task's custom task type, named as a bean type by type_mapping
+  priority   the task's priority, named as a bean priority by priority_mapping
+  checklist  the task's checklists, as a markdown task list in the bean body
+             section named by checklist_section, if set
+  status     the task's status, named as a bean status by status_mapping,
+             if field_ownership gives it to clickup
+  due        the task's due date, if field_ownership gives it to clickup
+  <key>      each custom field custom_fields.fields maps, into the bean's
+             extensions.clickup key: drop-down and label option names,
+             YYYY-MM-DD dates, numbers, and locations (relationship
+             fields are only pushed)
+
+A task type or priority with no mapping entry can't be named in bean
+terms, so it is reported and skipped. Where the task's history is
+available, only fields edited in ClickUp since the bean was last synced
+are pulled, and each is credited to the user who edited it, both here and
+in .beans/` + audit.FileName + `, where every pulled field is logged; a field that
+differs only because the bean changed is left for sync to push. Where it
+isn't, a bean edited since it was last synced has its fields skipped.
+Fields field_ownership gives to clickup are always pulled, and those it
+gives to beans never are.
+
+With --attachments, the task's attachments are downloaded into
+assets/<bean-id>/ next to the bean file, and links to them in the bean
+body are pointed at the local copies. If bean IDs are provided, only those beans are
+pulled; otherwise every linked bean that isn't completed or scrapped.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := interruptContext(cmd.Context())
+		defer stop()
+
+		// Serialize against other beanup processes writing the beans
+		if !pullDryRun {
+			projectLock, err := acquireProjectLock(ctx)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = projectLock.Release() }()
+		}
+
+		beansClient := beans.NewClient(getBeansPath())
+		var beanList []beans.Bean
+		var err error
+		if len(args) > 0 {
+			beanList, err = beansClient.GetMultiple(args)
+		} else {
+			// Archived beans' tasks are left alone, as sync does
+			beanList, err = beansClient.ListWith(beans.ListOptions{ExcludeStatus: []string{"completed", "scrapped"}})
+		}
+		if err != nil {
+			return fmt.Errorf("getting beans: %w", err)
+		}
+		linked := clickupLinked(beanList)
+
+		token, err := getClickUpToken()
+		if err != nil {
+			return err
+		}
+		changes, err := pullBeans(ctx, newClickUpClient(ctx, token), beansClient, linked, pullDryRun, pullAttachments, !jsonOut)
+		if err != nil {
+			return err
+		}
+
+		// Tasks fetched before an interrupt are still pulled
+		var fetchErr error
+		if interrupted(ctx) {
+			fetchErr = interruptedError("changes from the tasks fetched before it were pulled")
+		}
+
+		if jsonOut {
+			if err := outputJSON(changes); err != nil {
+				return err
+			}
+			return fetchErr
+		}
+		if len(changes) == 0 {
+			if fetchErr == nil {
+				fmt.Println("Nothing to pull")
+			}
+			return fetchErr
+		}
+		verb := "Pulled"
+		if pullDryRun {
+			verb = "Would pull"
+		}
+		for _, c := range changes {
+			if c.Skipped != "" {
+				_, _ = colorYellow.Printf("Skipped: %s %s: %s\n", c.BeanID, c.Field, c.Skipped)
+				continue
+			}
+			fmt.Printf("%s: %s %s %s → %s%s\n", verb, c.BeanID, c.Field, orNone(c.From), c.To, c.credit())
+		}
+		return fetchErr
+	},
+}
+
+func init() {
+	pullCmd.Flags().BoolVar(&pullDryRun, "dry-run", false, "show what would be pulled without changing beans")
+	pullCmd.Flags().BoolVar(&pullAttachments, "attachments", false, "download task attachments next to the beans")
+	rootCmd.AddCommand(pullCmd)
+}
+
+// clickupLinked returns the beans in beanList linked to a ClickUp task.
+// Epics materialized as lists have no task to pull from.
+func clickupLinked(beanList []beans.Bean) []beans.Bean {
+	var linked []beans.Bean
+	for _, b := range beanList {
+		taskID := b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID)
+		if taskID != "" && !clickup.IsListRef(taskID) {
+			linked = append(linked, b)
+		}
+	}
+	return linked
+}
+
+// pullBeans fetches the tasks of linked beans and writes the fields
+// changed in ClickUp back into the beans, unless dryRun. With attachments,
+// task attachments are downloaded too. Fields that can't be written are
+// returned as skipped.
+func pullBeans(ctx context.Context, client *clickup.Client, beansClient *beans.Client, linked []beans.Bean, dryRun, attachments, progress bool) ([]pulledField, error) {
+	live, err := fetchLiveTasks(ctx, client, linked, progress)
+	if err != nil {
+		return nil, fmt.Errorf("fetching tasks: %w", err)
+	}
+	sink := clickup.NewSink(client, &cfg.Beans.ClickUp, cfg.Beans.ClickUp.ListID)
+	changes := pullChanges(ctx, sink, linked, live)
+	changes = attributeChanges(changes, linked, fetchRemoteChanges(ctx, client, linked, changes))
+
+	if !dryRun {
+		for i, c := range changes {
+			if c.Skipped != "" || c.ext {
+				continue
+			}
+			if err := beansClient.Update(c.BeanID, c.update()); err != nil {
+				changes[i].Skipped = err.Error()
+			}
+		}
+		applyExtensionChanges(beansClient, linked, changes)
+	}
+	if attachments {
+		// Links are rewritten in the body as pulled, so the checklist stays
+		changes = append(changes, pullTaskAttachments(ctx, client, beansClient, getBeansPath(), pulledBodies(linked, changes), live, dryRun)...)
+	}
+	if !dryRun {
+		if err := recordPulls(linked, changes); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	return changes, nil
+}
+
+// recordPulls logs the fields written into beans, with who changed each in
+// ClickUp where known.
+func recordPulls(linked []beans.Bean, changes []pulledField) error {
+	taskIDs := make(map[string]string, len(linked))
+	for _, b := range linked {
+		taskIDs[b.ID] = b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID)
+	}
+	var pulled []audit.Entry
+	for _, c := range changes {
+		if c.Skipped != "" {
+			continue
+		}
+		pulled = append(pulled, audit.Entry{
+			Time:   time.Now().UTC(),
+			Kind:   audit.KindPull,
+			Sink:   beans.PluginClickUp,
+			BeanID: c.BeanID,
+			TaskID: taskIDs[c.BeanID],
+			Field:  c.Field,
+			From:   c.From,
+			To:     c.To,
+			By:     c.By,
+		})
+	}
+	return audit.Append(auditPath(), pulled...)
+}
+
+// pulledBodies returns a copy of beanList with the bodies the pulled
+// checklists in changes gave them.
+func pulledBodies(beanList []beans.Bean, changes []pulledField) []beans.Bean {
+	bodies := make(map[string]string)
+	for _, c := range changes {
+		if c.Field == "checklist" && c.Skipped == "" {
+			bodies[c.BeanID] = c.body
+		}
+	}
+	beanList = slices.Clone(beanList)
+	for i, b := range beanList {
+		if body, ok := bodies[b.ID]; ok {
+			beanList[i].Body = body
+		}
+	}
+	return beanList
+}
+
+// pulledField is a bean field whose task side differs and is written back
+// to the bean, unless Skipped says why not.
+type pulledField struct {
+	BeanID  string `json:"bean_id"`
+	Field   string `json:"field"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	By      string `json:"by,omitempty"` // the ClickUp user who made the change, if known
+	Skipped string `json:"skipped,omitempty"`
+
+	body  string // the new bean body, for body fields
+	owned bool   // field_ownership makes ClickUp authoritative for the field
+	ext   bool   // Field is an extensions.clickup key, set to value
+	value any
+}
+
+// credit returns " (by <user>)" if the change's author is known.
+func (p pulledField) credit() string {
+	if p.By == "" {
+		return ""
+	}
+	return " (by " + p.By + ")"
+}
+
+// update returns the bean update that applies the field.
+func (p pulledField) update() beans.BeanUpdate {
+	var u beans.BeanUpdate
+	switch p.Field {
+	case "type":
+		u.Type = &p.To
+	case "priority":
+		u.Priority = &p.To
+	case "status":
+		u.Status = &p.To
+	case "due":
+		u.Due = &p.To
+	case "checklist":
+		u.Body = &p.body
+	}
+	return u
+}
+
+// pullChanges lists the fields to pull for each bean whose task was
+// fetched into live, in bean order.
+func pullChanges(ctx context.Context, sink *clickup.Sink, beanList []beans.Bean, live []liveTask) []pulledField {
+	var changes []pulledField
+	for i, b := range beanList {
+		task := live[i].Task
+		if task == nil {
+			continue
+		}
+		owned := func(field string) bool { return sink.FieldOwner(field) == clickup.OwnerClickUp }
+		pulls := func(field string) bool { return sink.FieldOwner(field) != clickup.OwnerBeans }
+
+		if d := sink.TypeDiff(&b, task); d.Changed && pulls("type") {
+			c := pulledField{BeanID: b.ID, Field: "type", From: b.Type, To: d.Task, owned: owned("type")}
+			if beanType, ok := sink.BeanType(task.CustomItemID); ok {
+				c.To = beanType
+			} else {
+				c.Skipped = fmt.Sprintf("task type %s has no type_mapping entry", orNone(d.Task))
+			}
+			changes = append(changes, c)
+		}
+		if d := sink.PriorityDiff(&b, task); d.Changed && pulls("priority") {
+			c := pulledField{BeanID: b.ID, Field: "priority", From: b.Priority, To: d.Task, owned: owned("priority")}
+			var taskPriority *int
+			if task.Priority != nil {
+				taskPriority = &task.Priority.ID
+			}
+			if beanPriority, ok := sink.BeanPriority(taskPriority); ok {
+				c.To = beanPriority
+			} else {
+				c.Skipped = fmt.Sprintf("task priority %s has no priority_mapping entry", orNone(d.Task))
+			}
+			changes = append(changes, c)
+		}
+		// Status and due date are only pulled when ClickUp owns them
+		if d := sink.StatusDiff(&b, task); d.Changed && owned("status") {
+			c := pulledField{BeanID: b.ID, Field: "status", From: b.Status, To: d.Task, owned: true}
+			if beanStatus, ok := sink.BeanStatus(d.Task); ok {
+				c.To = beanStatus
+			} else {
+				c.Skipped = fmt.Sprintf("task status %s has no status_mapping entry", orNone(d.Task))
+			}
+			changes = append(changes, c)
+		}
+		if d := sink.DueDiff(&b, task); d.Changed && owned("due") {
+			c := pulledField{BeanID: b.ID, Field: "due", From: d.Bean, To: d.Task, owned: true}
+			if d.Task == "" {
+				c.Skipped = "a bean due date can't be cleared"
+			}
+			changes = append(changes, c)
+		}
+		if d, body := sink.ChecklistDiff(&b, task); d.Changed {
+			changes = append(changes, pulledField{BeanID: b.ID, Field: "checklist", From: d.Bean, To: d.Task, body: body})
+		}
+		fields, err := sink.CustomFieldChanges(ctx, &b, task)
+		if err != nil {
+			changes = append(changes, pulledField{BeanID: b.ID, Field: "custom_fields", Skipped: err.Error()})
+		}
+		for _, f := range fields {
+			changes = append(changes, pulledField{BeanID: b.ID, Field: f.Key, From: f.Bean, To: f.Task, ext: true, value: f.Value})
+		}
+	}
+	return changes
+}
+
+// applyExtensionChanges writes the extensions.clickup keys among changes
+// into their beans, one write per bean. A failed write marks the bean's
+// keys skipped.
+func applyExtensionChanges(beansClient *beans.Client, beanList []beans.Bean, changes []pulledField) {
+	for _, b := range beanList {
+		var indexes []int
+		data := maps.Clone(b.Extensions[beans.PluginClickUp])
+		if data == nil {
+			data = make(map[string]any)
+		}
+		for i, c := range changes {
+			if c.BeanID != b.ID || !c.ext || c.Skipped != "" {
+				continue
+			}
+			indexes = append(indexes, i)
+			if c.value == nil {
+				delete(data, c.Field)
+			} else {
+				data[c.Field] = c.value
+			}
+		}
+		if len(indexes) == 0 {
+			continue
+		}
+		if err := beansClient.SetExtensionData(b.ID, beans.PluginClickUp, data); err != nil {
+			for _, i := range indexes {
+				changes[i].Skipped = err.Error()
+			}
+		}
+	}
+}
+
+// fetchRemoteChanges fetches the history since synced_at of the tasks of
+// beans with changes, returning the fields edited in ClickUp by bean ID.
+// Beans never synced, or whose history couldn't be fetched, are left out.
+func fetchRemoteChanges(ctx context.Context, client *clickup.Client, beanList []beans.Bean, changes []pulledField) map[string]map[string]clickup.HistoryItem {
+	changed := make(map[string]bool)
+	for _, c := range changes {
+		changed[c.BeanID] = true
+	}
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex // protects remote
+		remote = make(map[string]map[string]clickup.HistoryItem)
+	)
+	sem := make(chan struct{}, statusFetchConcurrency)
+	for _, b := range beanList {
+		syncedAt := b.GetExtensionTime(beans.PluginClickUp, beans.ExtKeySyncedAt)
+		if !changed[b.ID] || syncedAt == nil {
+			continue
+		}
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			items, err := client.GetTaskHistory(ctx, b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID), *syncedAt)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			remote[b.ID] = clickup.RemoteChanges(items)
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+	return remote
+}
+
+// attributeChanges drops the changes to fields that remote, the task edits
+// by bean ID, shows weren't edited in ClickUp, and credits the rest to
+// whoever edited them. Fields history doesn't track and fields ClickUp owns
+// keep their changes. For beans missing from remote, whose edits can't be
+// told apart, changes are skipped if the bean was edited since it was last
+// synced, unless ClickUp owns the field, so pulling doesn't undo them.
+func attributeChanges(changes []pulledField, beanList []beans.Bean, remote map[string]map[string]clickup.HistoryItem) []pulledField {
+	editedSinceSync := make(map[string]bool)
+	for _, b := range beanList {
+		syncedAt := b.GetExtensionTime(beans.PluginClickUp, beans.ExtKeySyncedAt)
+		editedSinceSync[b.ID] = syncedAt != nil && b.UpdatedAt != nil && b.UpdatedAt.After(*syncedAt)
+	}
+
+	var kept []pulledField
+	for _, c := range changes {
+		edits, known := remote[c.BeanID]
+		edit, edited := edits[c.Field]
+		if known && !edited && !c.owned && clickup.HistoryTracks(c.Field) {
+			continue
+		}
+		if !known && !c.owned && c.Skipped == "" && editedSinceSync[c.BeanID] {
+			c.Skipped = "bean changed since last sync and task history is unavailable"
+		}
+		if edited {
+			c.By = edit.User.Name()
+		}
+		kept = append(kept, c)
+	}
+	return kept
+}
+
+// pullTaskAttachments downloads the attachments of each fetched task into
+// assets/<bean-id>/ next to the bean file in beansDir, and points links to
+// the attachment URLs in the bean body at the local copies. Files already
+// downloaded are kept and not reported. With dryRun nothing is written.
+func pullTaskAttachments(ctx context.Context, client *clickup.Client, beansClient *beans.Client, beansDir string, beanList []beans.Bean, live []liveTask, dryRun bool) []pulledField {
+	var pulled []pulledField
+	for i, b := range beanList {
+		task := live[i].Task
+		if task == nil || len(task.Attachments) == 0 {
+			continue
+		}
+		assets := filepath.Join(filepath.Dir(b.Path), "assets", b.ID)
+		body := b.Body
+		used := make(map[string]bool)
+		for _, a := range task.Attachments {
+			name := attachmentFileName(a, used)
+			rel := filepath.ToSlash(filepath.Join("assets", b.ID, name))
+			path := filepath.Join(beansDir, assets, name)
+			if _, err := os.Stat(path); err != nil {
+				c := pulledField{BeanID: b.ID, Field: "attachment", To: rel}
+				if !dryRun {
+					if err := downloadAttachment(ctx, client, a.URL, path); err != nil {
+						c.Skipped = err.Error()
+						pulled = append(pulled, c)
+						continue
+					}
+				}
+				pulled = append(pulled, c)
+			}
+			body = strings.ReplaceAll(body, a.URL, rel)
+		}
+		if body != b.Body && !dryRun {
+			if err := beansClient.Update(b.ID, beans.BeanUpdate{Body: &body}); err != nil {
+				pulled = append(pulled, pulledField{BeanID: b.ID, Field: "body", Skipped: err.Error()})
+			}
+		}
+	}
+	return pulled
+}
+
+// attachmentFileName returns a file name for a, made safe to join to a
+// path and unique among the names in used, which it's added to.
+func attachmentFileName(a clickup.TaskAttachment, used map[string]bool) string {
+	name := filepath.Base(filepath.Clean("/" + a.Title))
+	if name == "/" || name == "." {
+		name = a.ID
+	}
+	if used[name] {
+		name = a.ID + "-" + name
+	}
+	used[name] = true
+	return name
+}
+
+// downloadAttachment writes the attachment at url to path, creating its
+// directory.
+func downloadAttachment(ctx context.Context, client *clickup.Client, url, path string) error {
+	data, err := client.DownloadAttachment(ctx, url)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating assets directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing attachment: %w", err)
+	}
+	return nil
+}
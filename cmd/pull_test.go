@@ -0,0 +1,352 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/audit"
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/clickup"
+	"github.com/toba/bean-me-up/internal/config"
+)
+
+func TestPullChanges(t *testing.T) {
+	sink := clickup.NewSink(nil, &config.ClickUpConfig{TypeMapping: map[string]int{"bug": 1, "feature": 2}}, "list")
+	bugType, unmappedType := 1, 9
+
+	beanList := []beans.Bean{
+		{ID: "same", Type: "bug"},
+		{ID: "changed", Type: "feature"},
+		{ID: "unmapped", Type: "feature"},
+		{ID: "unfetched", Type: "feature"},
+	}
+	live := []liveTask{
+		{Task: &clickup.TaskInfo{CustomItemID: &bugType}},
+		{Task: &clickup.TaskInfo{CustomItemID: &bugType}},
+		{Task: &clickup.TaskInfo{CustomItemID: &unmappedType}},
+		{},
+	}
+
+	changes := pullChanges(context.Background(), sink, beanList, live)
+	if len(changes) != 2 {
+		t.Fatalf("pullChanges() = %+v, want 2", changes)
+	}
+	if c := changes[0]; c.BeanID != "changed" || c.From != "feature" || c.To != "bug" || c.Skipped != "" {
+		t.Errorf("changed = %+v", c)
+	}
+	if u := changes[0].update(); u.Type == nil || *u.Type != "bug" {
+		t.Errorf("update() = %+v", u)
+	}
+	if c := changes[1]; c.BeanID != "unmapped" || c.Skipped == "" {
+		t.Errorf("unmapped = %+v", c)
+	}
+}
+
+func TestPullChanges_Priority(t *testing.T) {
+	sink := clickup.NewSink(nil, &config.ClickUpConfig{PriorityMapping: map[string]int{"blocker": 1}}, "list")
+
+	beanList := []beans.Bean{
+		{ID: "same", Priority: "normal"},
+		{ID: "shared", Priority: "normal"},
+		{ID: "custom", Priority: "normal"},
+		{ID: "cleared", Priority: "normal"},
+	}
+	live := []liveTask{
+		{Task: &clickup.TaskInfo{Priority: &clickup.TaskPriority{ID: 3}}},
+		{Task: &clickup.TaskInfo{Priority: &clickup.TaskPriority{ID: 4}}},
+		{Task: &clickup.TaskInfo{Priority: &clickup.TaskPriority{ID: 1}}},
+		{Task: &clickup.TaskInfo{}},
+	}
+
+	changes := pullChanges(context.Background(), sink, beanList, live)
+	if len(changes) != 3 {
+		t.Fatalf("pullChanges() = %+v, want 3", changes)
+	}
+	// low and deferred both map to 4; the one named like it wins
+	if c := changes[0]; c.BeanID != "shared" || c.Field != "priority" || c.To != "low" || c.Skipped != "" {
+		t.Errorf("shared = %+v", c)
+	}
+	// critical and blocker both map to 1; neither is named urgent
+	if c := changes[1]; c.BeanID != "custom" || c.To != "blocker" || c.Skipped != "" {
+		t.Errorf("custom = %+v", c)
+	}
+	if u := changes[1].update(); u.Priority == nil || *u.Priority != "blocker" || u.Type != nil {
+		t.Errorf("update() = %+v", u)
+	}
+	if c := changes[2]; c.BeanID != "cleared" || c.Skipped == "" {
+		t.Errorf("cleared = %+v", c)
+	}
+}
+
+func TestAttributeChanges(t *testing.T) {
+	changes := []pulledField{
+		{BeanID: "edited", Field: "priority", To: "high"},
+		{BeanID: "edited", Field: "type", To: "bug"},
+		{BeanID: "unknown", Field: "type", To: "bug"},
+		{BeanID: "edited", Field: "checklist", body: "- [x] Done"},
+	}
+	remote := map[string]map[string]clickup.HistoryItem{
+		"edited": {"priority": {Field: "priority", User: clickup.TaskUser{Username: "ann"}}},
+	}
+
+	got := attributeChanges(changes, nil, remote)
+	// The type differs only because the bean changed, so it's left for sync
+	if len(got) != 3 {
+		t.Fatalf("attributeChanges() = %+v, want 3", got)
+	}
+	if c := got[0]; c.Field != "priority" || c.By != "ann" || c.credit() != " (by ann)" {
+		t.Errorf("edited = %+v", c)
+	}
+	// Without history, a bean not edited since it was synced has every
+	// differing field pulled, uncredited
+	if c := got[1]; c.BeanID != "unknown" || c.By != "" || c.credit() != "" {
+		t.Errorf("unknown = %+v", c)
+	}
+	// History doesn't track checklists, so they're always pulled
+	if u := got[2].update(); u.Body == nil || *u.Body != "- [x] Done" {
+		t.Errorf("checklist update() = %+v", u)
+	}
+}
+
+func TestRecordPulls(t *testing.T) {
+	dir := t.TempDir()
+	beansPath = dir
+	defer func() { beansPath = "" }()
+
+	linked := []beans.Bean{{ID: "a", Extensions: map[string]map[string]any{beans.PluginClickUp: {beans.ExtKeyTaskID: "t-a"}}}}
+	changes := []pulledField{
+		{BeanID: "a", Field: "priority", From: "normal", To: "high", By: "Ana"},
+		{BeanID: "a", Field: "type", Skipped: "task type bug has no type_mapping entry"},
+	}
+	if err := recordPulls(linked, changes); err != nil {
+		t.Fatal(err)
+	}
+	logged, err := audit.Load(filepath.Join(dir, audit.FileName), audit.KindPull, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logged) != 1 || logged[0].TaskID != "t-a" || logged[0].Field != "priority" || logged[0].To != "high" || logged[0].By != "Ana" {
+		t.Errorf("audit log = %+v, want the priority pull credited to Ana", logged)
+	}
+}
+
+func TestPullTaskAttachments(t *testing.T) {
+	beans.NoCLI = true
+	defer func() { beans.NoCLI = false }()
+
+	files := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Error("API token sent with an attachment download")
+		}
+		_, _ = w.Write([]byte("png:" + r.URL.Path))
+	}))
+	defer files.Close()
+
+	dir := t.TempDir()
+	content := "---\ntitle: Linked\nstatus: todo\n---\n\nSee " + files.URL + "/a1/plan.png\n"
+	if err := os.WriteFile(filepath.Join(dir, "bup-a--linked.md"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	beansClient := beans.NewClient(dir)
+	b, err := beansClient.Get("bup-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	live := []liveTask{{Task: &clickup.TaskInfo{Attachments: []clickup.TaskAttachment{
+		{ID: "a1", Title: "plan.png", URL: files.URL + "/a1/plan.png"},
+		{ID: "a2", Title: "../plan.png", URL: files.URL + "/a2/plan.png"},
+	}}}}
+	pull := func(dryRun bool) []pulledField {
+		return pullTaskAttachments(context.Background(), clickup.NewClient("token"), beansClient, dir, []beans.Bean{*b}, live, dryRun)
+	}
+
+	if got := pull(true); len(got) != 2 {
+		t.Fatalf("dry run = %+v, want 2 attachments", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "assets")); err == nil {
+		t.Error("dry run wrote attachments")
+	}
+
+	got := pull(false)
+	// A second attachment with the same name gets its ID, and can't escape assets
+	if len(got) != 2 || got[0].To != "assets/bup-a/plan.png" || got[1].To != "assets/bup-a/a2-plan.png" || got[1].Skipped != "" {
+		t.Fatalf("pulled = %+v", got)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "assets", "bup-a", "a2-plan.png")); string(data) != "png:/a2/plan.png" {
+		t.Errorf("a2-plan.png = %q", data)
+	}
+	if b, _ = beansClient.Get("bup-a"); b.Body != "See assets/bup-a/plan.png" {
+		t.Errorf("body = %q", b.Body)
+	}
+
+	// Attachments already downloaded aren't fetched again
+	if got := pull(false); len(got) != 0 {
+		t.Errorf("second pull = %+v", got)
+	}
+}
+
+func TestPullBeans_ChecklistAndAttachments(t *testing.T) {
+	beans.NoCLI = true
+	defer func() { beans.NoCLI = false }()
+	oldCfg := cfg
+	defer func() { cfg = oldCfg }()
+	cfg = &config.Config{Beans: config.BeansWrapper{ClickUp: config.ClickUpConfig{ListID: "list", ChecklistSection: "Checklist"}}}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/files/") {
+			_, _ = w.Write([]byte("png"))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"id":"t1","checklists":[{"id":"c1","name":"Checklist","items":[{"id":"i1","name":"Ship","resolved":true}]}],`+
+			`"attachments":[{"id":"a1","title":"plan.png","url":%q}]}`, server.URL+"/files/a1/plan.png")
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	beansPath = dir
+	defer func() { beansPath = "" }()
+	content := "---\ntitle: Linked\nstatus: todo\nextensions:\n    clickup:\n        task_id: t1\n---\n\nSee " + server.URL + "/files/a1/plan.png\n\n## Checklist\n\n- [ ] Ship\n"
+	if err := os.WriteFile(filepath.Join(dir, "bup-a--linked.md"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	beansClient := beans.NewClient(dir)
+	b, err := beansClient.Get("bup-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := clickup.NewClient("test", clickup.WithBaseURL(server.URL))
+	changes, err := pullBeans(context.Background(), client, beansClient, []beans.Bean{*b}, false, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 || changes[0].Field != "checklist" || changes[1].Field != "attachment" {
+		t.Fatalf("changes = %+v", changes)
+	}
+	// The attachment links are rewritten without undoing the checklist
+	if b, _ = beansClient.Get("bup-a"); b.Body != "See assets/bup-a/plan.png\n\n## Checklist\n\n- [x] Ship" {
+		t.Errorf("body = %q", b.Body)
+	}
+
+	logged, err := audit.Load(filepath.Join(dir, audit.FileName), audit.KindPull, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logged) != 2 || logged[0].Field != "checklist" || logged[0].TaskID != "t1" {
+		t.Errorf("audit log = %+v, want the checklist and attachment pulls", logged)
+	}
+}
+
+func TestPullChanges_Ownership(t *testing.T) {
+	sink := clickup.NewSink(nil, &config.ClickUpConfig{
+		TypeMapping:    map[string]int{"bug": 1},
+		FieldOwnership: map[string]string{"type": clickup.OwnerBeans, "status": clickup.OwnerClickUp, "due": clickup.OwnerClickUp},
+	}, "list")
+	bugType, due := 1, "2026-03-01"
+	dueMillis := fmt.Sprint(time.Date(2026, 3, 5, 0, 0, 0, 0, time.Local).UnixMilli())
+
+	beanList := []beans.Bean{{ID: "owned", Type: "feature", Status: "todo", Due: &due}}
+	live := []liveTask{{Task: &clickup.TaskInfo{CustomItemID: &bugType, Status: clickup.Status{Status: "complete"}, DueDate: &dueMillis}}}
+
+	// The type is the bean's to set, so it isn't pulled
+	changes := pullChanges(context.Background(), sink, beanList, live)
+	if len(changes) != 2 {
+		t.Fatalf("pullChanges() = %+v, want status and due", changes)
+	}
+	if c := changes[0]; c.Field != "status" || c.To != "completed" || !c.owned {
+		t.Errorf("status = %+v", c)
+	}
+	if u := changes[1].update(); u.Due == nil || *u.Due != "2026-03-05" {
+		t.Errorf("due update() = %+v", u)
+	}
+
+	// Fields ClickUp owns are pulled even when history doesn't show an edit
+	remote := map[string]map[string]clickup.HistoryItem{"owned": {}}
+	if got := attributeChanges(changes, nil, remote); len(got) != 2 {
+		t.Errorf("attributeChanges() = %+v, want both kept", got)
+	}
+}
+
+func TestAttributeChanges_HistoryUnavailable(t *testing.T) {
+	syncedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	before, after := syncedAt.Add(-time.Hour), syncedAt.Add(time.Hour)
+	synced := map[string]map[string]any{beans.PluginClickUp: {beans.ExtKeySyncedAt: syncedAt.Format(time.RFC3339)}}
+	beanList := []beans.Bean{
+		{ID: "edited", UpdatedAt: &after, Extensions: synced},
+		{ID: "clean", UpdatedAt: &before, Extensions: synced},
+		{ID: "unsynced", UpdatedAt: &after},
+	}
+	changes := []pulledField{
+		{BeanID: "edited", Field: "priority", To: "high"},
+		{BeanID: "edited", Field: "status", To: "completed", owned: true},
+		{BeanID: "clean", Field: "priority", To: "high"},
+		{BeanID: "unsynced", Field: "priority", To: "high"},
+	}
+
+	// No bean's history could be fetched
+	got := attributeChanges(changes, beanList, nil)
+	if len(got) != 4 {
+		t.Fatalf("attributeChanges() = %+v, want 4", got)
+	}
+	// The bean's own edit might be what differs, so it isn't overwritten
+	if c := got[0]; c.Skipped == "" {
+		t.Errorf("edited priority = %+v, want skipped", c)
+	}
+	// ClickUp owns the status, so it's pulled regardless
+	if c := got[1]; c.Skipped != "" {
+		t.Errorf("edited status = %+v, want pulled", c)
+	}
+	if c := got[2]; c.Skipped != "" {
+		t.Errorf("clean = %+v, want pulled", c)
+	}
+	if c := got[3]; c.Skipped != "" {
+		t.Errorf("unsynced = %+v, want pulled", c)
+	}
+}
+
+func TestApplyExtensionChanges(t *testing.T) {
+	beans.NoCLI = true
+	defer func() { beans.NoCLI = false }()
+
+	dir := t.TempDir()
+	content := "---\ntitle: Linked\nstatus: todo\nextensions:\n    clickup:\n        task_id: 868abc\n        area: API\n        stale: x\n---\n"
+	if err := os.WriteFile(filepath.Join(dir, "bup-a--linked.md"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	beansClient := beans.NewClient(dir)
+	b, err := beansClient.Get("bup-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := []pulledField{
+		{BeanID: "bup-a", Field: "area", ext: true, value: "Web"},
+		{BeanID: "bup-a", Field: "estimate", ext: true, value: int64(5)},
+		{BeanID: "bup-a", Field: "stale", ext: true},
+		{BeanID: "bup-a", Field: "type", To: "bug"},
+	}
+	applyExtensionChanges(beansClient, []beans.Bean{*b}, changes)
+	for _, c := range changes {
+		if c.Skipped != "" {
+			t.Errorf("%s skipped: %s", c.Field, c.Skipped)
+		}
+	}
+
+	// Both keys land in one write, next to the ones beanup keeps there
+	b, _ = beansClient.Get("bup-a")
+	ext := b.Extensions[beans.PluginClickUp]
+	if ext["task_id"] != "868abc" || ext["area"] != "Web" || ext["estimate"] != 5 {
+		t.Errorf("extensions = %v", ext)
+	}
+	if _, ok := ext["stale"]; ok {
+		t.Errorf("cleared key kept: %v", ext)
+	}
+}
@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/audit"
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/clickup"
+	"github.com/toba/bean-me-up/internal/queue"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportDays    int
+	reportFormat  string
+	reportOffline bool
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Summarize sync state for a status update",
+	Long: `Summarizes the sync state of every bean:
+
+  - bean counts by status, and how many are linked to ClickUp tasks
+  - beans synced in the last --days days
+  - linked beans by sync state (the Sync column of "beanup status")
+  - stale links: beans whose ClickUp task was deleted
+  - sync errors logged in the last --days days, from .beans/` + audit.FileName + `
+  - syncs still queued for retry (see "beanup flush")
+
+Task-side changes and deleted tasks are detected only when a ClickUp
+token is available and --offline is not set. Markdown output is meant
+for pasting into a weekly update:
+
+  beanup report --days 7 --format markdown`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		format := reportFormat
+		if jsonOut {
+			format = "json"
+		}
+		if !slices.Contains(reportFormats, format) {
+			return fmt.Errorf("unknown --format %q (use %s)", format, strings.Join(reportFormats, ", "))
+		}
+		if reportDays < 1 {
+			return fmt.Errorf("--days must be at least 1")
+		}
+
+		beanList, err := beans.NewClient(getBeansPath()).List()
+		if err != nil {
+			return fmt.Errorf("listing beans: %w", err)
+		}
+
+		var live []liveTask
+		var sink *clickup.Sink
+		if !reportOffline {
+			if token, _ := getClickUpToken(); token != "" {
+				client := newClickUpClient(ctx, token)
+				sink = clickup.NewSink(client, &cfg.Beans.ClickUp, cfg.Beans.ClickUp.ListID)
+				live, err = fetchLiveTasks(ctx, client, beanList, format == "text")
+				if err != nil {
+					return fmt.Errorf("fetching task status: %w", err)
+				}
+			}
+		}
+
+		queued, err := queue.Load(queuePath())
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		history, err := audit.Load(auditPath(), audit.KindSyncError, now.AddDate(0, 0, -reportDays))
+		if err != nil {
+			return err
+		}
+
+		report := buildReport(beanList, beanStatuses(beanList, sink, live), history, queued, reportDays, now)
+		report.TasksChecked = live != nil
+		return writeReport(os.Stdout, format, report)
+	},
+}
+
+func init() {
+	reportCmd.Flags().IntVar(&reportDays, "days", 7, "count beans synced within this many days")
+	reportCmd.Flags().StringVar(&reportFormat, "format", "text", "output format: text, json, or markdown")
+	reportCmd.Flags().BoolVar(&reportOffline, "offline", false, "don't contact ClickUp; task-side changes and deleted tasks won't be detected")
+	rootCmd.AddCommand(reportCmd)
+}
+
+// reportFormats are the values accepted by report --format.
+var reportFormats = []string{"text", "json", "markdown"}
+
+// syncReport aggregates the sync state of a project's beans.
+type syncReport struct {
+	GeneratedAt    time.Time      `json:"generated_at"`
+	Days           int            `json:"days"`
+	Beans          int            `json:"beans"`
+	Linked         int            `json:"linked"`
+	ByStatus       map[string]int `json:"by_status"`
+	RecentlySynced []reportBean   `json:"recently_synced"`
+	Drift          map[string]int `json:"drift"`
+	StaleLinks     []reportBean   `json:"stale_links"`
+	Errors         []audit.Entry  `json:"errors"`
+	Queued         []queue.Entry  `json:"queued"`
+	// TasksChecked is false when ClickUp wasn't consulted, so task-side
+	// drift and stale links are unknown.
+	TasksChecked bool `json:"tasks_checked"`
+}
+
+// reportBean is a bean listed in a report section.
+type reportBean struct {
+	BeanID   string     `json:"bean_id"`
+	Title    string     `json:"title"`
+	TaskID   string     `json:"task_id,omitempty"`
+	SyncedAt *time.Time `json:"synced_at,omitempty"`
+}
+
+// buildReport aggregates beanList and its status rows (see beanStatuses)
+// with the logged sync errors and queued syncs, counting syncs within days
+// before now.
+func buildReport(beanList []beans.Bean, statuses []beanSyncStatus, history []audit.Entry, queued []queue.Entry, days int, now time.Time) syncReport {
+	report := syncReport{
+		GeneratedAt:    now.UTC(),
+		Days:           days,
+		Beans:          len(beanList),
+		ByStatus:       map[string]int{},
+		RecentlySynced: []reportBean{},
+		Drift:          map[string]int{},
+		StaleLinks:     []reportBean{},
+		Errors:         history,
+		Queued:         queued,
+	}
+	if report.Errors == nil {
+		report.Errors = []audit.Entry{}
+	}
+	if report.Queued == nil {
+		report.Queued = []queue.Entry{}
+	}
+
+	since := now.AddDate(0, 0, -days)
+	for i, b := range beanList {
+		report.ByStatus[b.Status]++
+		s := statuses[i]
+		if !s.Linked {
+			continue
+		}
+		report.Linked++
+		report.Drift[s.Drift]++
+
+		entry := reportBean{BeanID: b.ID, Title: b.Title, TaskID: s.TaskID, SyncedAt: b.GetExtensionTime(beans.PluginClickUp, beans.ExtKeySyncedAt)}
+		if entry.SyncedAt != nil && entry.SyncedAt.After(since) {
+			report.RecentlySynced = append(report.RecentlySynced, entry)
+		}
+		if s.TaskStatus == "(deleted)" {
+			report.StaleLinks = append(report.StaleLinks, entry)
+		}
+	}
+	// Most recent first
+	slices.SortStableFunc(report.RecentlySynced, func(a, b reportBean) int { return b.SyncedAt.Compare(*a.SyncedAt) })
+	return report
+}
+
+// writeReport writes the report in the given format.
+func writeReport(w io.Writer, format string, r syncReport) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case "markdown":
+		return writeReportMarkdown(w, r)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Beans: %d (%d linked, %d not linked)\n", r.Beans, r.Linked, r.Beans-r.Linked)
+	for _, status := range slices.Sorted(maps.Keys(r.ByStatus)) {
+		fmt.Fprintf(&b, "  %-15s %d\n", status, r.ByStatus[status])
+	}
+
+	fmt.Fprintf(&b, "\nSynced in the last %d days: %d\n", r.Days, len(r.RecentlySynced))
+	for _, e := range r.RecentlySynced {
+		fmt.Fprintf(&b, "  %-15s %s  %s\n", e.BeanID, e.SyncedAt.Local().Format("2006-01-02 15:04"), e.Title)
+	}
+
+	fmt.Fprintln(&b, "\nSync state of linked beans:")
+	for _, drift := range reportDrift(r) {
+		fmt.Fprintf(&b, "  %-15s %d\n", drift, r.Drift[drift])
+	}
+	if !r.TasksChecked {
+		fmt.Fprintln(&b, "  (ClickUp not checked; task-side changes unknown)")
+	}
+
+	if r.TasksChecked {
+		fmt.Fprintf(&b, "\nStale links (task deleted): %d\n", len(r.StaleLinks))
+		for _, e := range r.StaleLinks {
+			fmt.Fprintf(&b, "  %-15s task %s  %s\n", e.BeanID, e.TaskID, e.Title)
+		}
+	}
+
+	fmt.Fprintf(&b, "\nSync errors in the last %d days: %d\n", r.Days, len(r.Errors))
+	for _, e := range r.Errors {
+		fmt.Fprintf(&b, "  %s  %-8s %-15s %s\n", e.Time.Local().Format("2006-01-02 15:04"), e.Sink, e.BeanID, e.Error)
+	}
+
+	fmt.Fprintf(&b, "\nSyncs queued for retry: %d\n", len(r.Queued))
+	for _, e := range r.Queued {
+		fmt.Fprintf(&b, "  %s  %-8s %-15s %s: %s\n", e.Time.Local().Format("2006-01-02 15:04"), e.Sink, e.BeanID, e.Op, e.Error)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeReportMarkdown writes the report as markdown sections with tables.
+func writeReportMarkdown(w io.Writer, r syncReport) error {
+	section := func(title string, header []string, rows [][]string) error {
+		if _, err := fmt.Fprintf(w, "\n### %s\n\n", title); err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			_, err := io.WriteString(w, "None\n")
+			return err
+		}
+		return writeMarkdownTable(w, header, rows)
+	}
+
+	if _, err := fmt.Fprintf(w, "## Bean sync report, %s\n\n%d beans, %d linked to ClickUp tasks.\n",
+		r.GeneratedAt.Local().Format("2006-01-02"), r.Beans, r.Linked); err != nil {
+		return err
+	}
+
+	var rows [][]string
+	for _, status := range slices.Sorted(maps.Keys(r.ByStatus)) {
+		rows = append(rows, []string{status, strconv.Itoa(r.ByStatus[status])})
+	}
+	if err := section("Beans by status", []string{"Status", "Beans"}, rows); err != nil {
+		return err
+	}
+
+	rows = nil
+	for _, e := range r.RecentlySynced {
+		rows = append(rows, []string{e.BeanID, e.Title, e.SyncedAt.Local().Format("2006-01-02")})
+	}
+	if err := section(fmt.Sprintf("Synced in the last %d days", r.Days), []string{"Bean ID", "Title", "Synced"}, rows); err != nil {
+		return err
+	}
+
+	rows = nil
+	for _, drift := range reportDrift(r) {
+		rows = append(rows, []string{drift, strconv.Itoa(r.Drift[drift])})
+	}
+	title := "Sync state"
+	if !r.TasksChecked {
+		title += " (ClickUp not checked)"
+	}
+	if err := section(title, []string{"Sync", "Beans"}, rows); err != nil {
+		return err
+	}
+
+	if r.TasksChecked {
+		rows = nil
+		for _, e := range r.StaleLinks {
+			rows = append(rows, []string{e.BeanID, e.Title, e.TaskID})
+		}
+		if err := section("Stale links", []string{"Bean ID", "Title", "Deleted task"}, rows); err != nil {
+			return err
+		}
+	}
+
+	rows = nil
+	for _, e := range r.Errors {
+		rows = append(rows, []string{e.Time.Local().Format("2006-01-02 15:04"), e.Sink, e.BeanID, e.Error})
+	}
+	if err := section(fmt.Sprintf("Sync errors in the last %d days", r.Days), []string{"Time", "Backend", "Bean ID", "Error"}, rows); err != nil {
+		return err
+	}
+
+	rows = nil
+	for _, e := range r.Queued {
+		rows = append(rows, []string{e.Time.Local().Format("2006-01-02 15:04"), e.Sink, e.BeanID, e.Op, e.Error})
+	}
+	return section("Syncs queued for retry", []string{"Time", "Backend", "Bean ID", "Op", "Error"}, rows)
+}
+
+// reportDrift returns the sync states present in the report, needing
+// attention first.
+func reportDrift(r syncReport) []string {
+	return slices.SortedFunc(maps.Keys(r.Drift), func(a, b string) int { return driftOrder[a] - driftOrder[b] })
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/audit"
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/queue"
+)
+
+func TestBuildReport(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	linked := func(id, status string, syncedAt time.Time) beans.Bean {
+		return beans.Bean{ID: id, Title: strings.ToUpper(id), Status: status, Extensions: map[string]map[string]any{
+			beans.PluginClickUp: {beans.ExtKeyTaskID: "task-" + id, beans.ExtKeySyncedAt: syncedAt.Format(time.RFC3339)},
+		}}
+	}
+	beanList := []beans.Bean{
+		linked("old", "todo", now.AddDate(0, 0, -30)),
+		linked("recent", "todo", now.AddDate(0, 0, -2)),
+		linked("newest", "completed", now.Add(-time.Hour)),
+		{ID: "draft", Title: "Draft", Status: "draft"},
+	}
+	statuses := []beanSyncStatus{
+		{Linked: true, TaskID: "task-old", TaskStatus: "(deleted)", Drift: driftInSync},
+		{Linked: true, TaskID: "task-recent", Drift: driftTaskAhead},
+		{Linked: true, TaskID: "task-newest", Drift: driftInSync},
+		{Drift: driftUnlinked},
+	}
+	queued := []queue.Entry{{Time: now, Sink: "clickup", BeanID: "recent", Op: queue.OpUpdate, Error: "dial tcp: refused"}}
+
+	history := []audit.Entry{{Time: now.Add(-time.Hour), Kind: audit.KindSyncError, Sink: "clickup", BeanID: "newest", Error: "400 bad status"}}
+
+	r := buildReport(beanList, statuses, history, queued, 7, now)
+	if r.Beans != 4 || r.Linked != 3 || r.ByStatus["todo"] != 2 || r.ByStatus["draft"] != 1 {
+		t.Errorf("counts = %d beans, %d linked, by status %v", r.Beans, r.Linked, r.ByStatus)
+	}
+	if len(r.RecentlySynced) != 2 || r.RecentlySynced[0].BeanID != "newest" || r.RecentlySynced[1].BeanID != "recent" {
+		t.Errorf("recently synced = %+v, want newest then recent", r.RecentlySynced)
+	}
+	if r.Drift[driftInSync] != 2 || r.Drift[driftTaskAhead] != 1 || r.Drift[driftUnlinked] != 0 {
+		t.Errorf("drift = %v", r.Drift)
+	}
+	if len(r.StaleLinks) != 1 || r.StaleLinks[0].BeanID != "old" || r.StaleLinks[0].TaskID != "task-old" {
+		t.Errorf("stale links = %+v", r.StaleLinks)
+	}
+	if got := reportDrift(r); len(got) != 2 || got[0] != driftTaskAhead {
+		t.Errorf("reportDrift() = %v, want task ahead first", got)
+	}
+
+	r.TasksChecked = true
+	var md strings.Builder
+	if err := writeReport(&md, "markdown", r); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"4 beans, 3 linked",
+		"### Synced in the last 7 days",
+		"| newest | NEWEST |",
+		"| task ahead | 1 |",
+		"### Stale links",
+		"| old | OLD | task-old |",
+		"### Sync errors in the last 7 days",
+		"| clickup | newest | 400 bad status |",
+		"### Syncs queued for retry",
+		"| clickup | recent | update | dial tcp: refused |",
+	} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("markdown report missing %q:\n%s", want, md.String())
+		}
+	}
+}
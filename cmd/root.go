@@ -2,14 +2,23 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/toba/bean-me-up/internal/auth"
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/cassette"
+	"github.com/toba/bean-me-up/internal/clickup"
 	"github.com/toba/bean-me-up/internal/config"
+	"github.com/toba/bean-me-up/internal/lock"
+	"github.com/toba/bean-me-up/internal/tracing"
 )
 
 var (
@@ -17,10 +26,29 @@ var (
 	cfgFile   string
 	beansPath string
 	jsonOut   bool
+	// noBeansCLI reads bean files directly even if the beans CLI is installed
+	noBeansCLI bool
+	// profileName selects a profile from the user config
+	profileName string
+
+	// teamID selects a ClickUp workspace, overriding extensions.clickup.team_id
+	teamID string
+
+	lockTimeout time.Duration
+
+	// noCache turns off the ClickUp response cache
+	noCache bool
+
+	// recordPath and replayPath name a cassette of ClickUp traffic to
+	// record this run into, or to answer it from
+	recordPath string
+	replayPath string
+	recording  *cassette.Cassette
 
 	// Loaded configuration
 	cfg       *config.Config
 	configDir string
+	profile   *config.Profile
 )
 
 var rootCmd = &cobra.Command{
@@ -34,14 +62,50 @@ state in bean extension metadata.
 Configuration is stored in the extensions.clickup section of .beans.yml,
 or in a legacy .beans.clickup.yml file.`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		// Skip config loading for help commands and init
-		if cmd.Name() == "help" || cmd.Name() == "completion" || cmd.Name() == "init" || cmd.Name() == "migrate" {
+		tracing.SpanFromContext(cmd.Context()).SetAttributes(tracing.String("beanup.command", cmd.CommandPath()))
+
+		if err := setupOutput(cmd); err != nil {
+			return err
+		}
+		clickupOpts, err := clientOptions()
+		if err != nil {
+			return err
+		}
+		setClientOptions(cmd, clickupOpts)
+
+		// Skip config loading for help commands, init, auth, cache, the mock
+		// server, and config edits, which are used before a project is configured
+		if cmd.Name() == "help" || cmd.Name() == "completion" || cmd.Name() == "init" || cmd.Name() == "migrate" ||
+			cmd == mockServerCmd || cmd.Parent() == authCmd || cmd.Parent() == cacheCmd || (cmd.Parent() == configCmd && cmd != configShowCmd) {
+			return nil
+		}
+
+		// Workspace discovery doesn't need a project, but honors its team_id.
+		// Shell completion must not fail or warn for lack of one either
+		if cmd == spacesCmd || cmd == foldersCmd || cmd == listsCmd ||
+			cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd {
+			if cwd, err := os.Getwd(); err == nil {
+				profile, _ = config.LoadProfile(profileName)
+				cfg, configDir, _ = config.LoadFromDirectory(cwd, profile)
+			}
 			return nil
 		}
 
-		// Check if beans CLI is installed
-		if !checkBeansInstalled() {
-			fmt.Fprintln(os.Stderr, "Warning: beans CLI not found in PATH")
+		// Without the beans CLI, bean files are read directly
+		beans.NoCLI = noBeansCLI
+		if !noBeansCLI && !checkBeansInstalled() {
+			fmt.Fprintln(os.Stderr, "Warning: beans CLI not found in PATH; reading bean files directly")
+		}
+
+		// Apply the user profile, if any, before the project config it underlies
+		profile, err = config.LoadProfile(profileName)
+		if err != nil {
+			return withExitCode(exitConfig, fmt.Errorf("loading profile: %w", err))
+		}
+		if profile != nil {
+			if err := profile.ApplyEnv(); err != nil {
+				return err
+			}
 		}
 
 		// Load configuration
@@ -51,37 +115,183 @@ or in a legacy .beans.clickup.yml file.`,
 		}
 
 		if cfgFile != "" {
-			cfg, err = config.Load(cfgFile)
+			cfg, err = config.Load(cfgFile, profile)
 			if err != nil {
-				return fmt.Errorf("loading config: %w", err)
+				return withExitCode(exitConfig, fmt.Errorf("loading config: %w", err))
 			}
 			configDir = filepath.Dir(cfgFile)
 		} else {
-			cfg, configDir, err = config.LoadFromDirectory(cwd)
+			cfg, configDir, err = config.LoadFromDirectory(cwd, profile)
+			if err != nil {
+				return withExitCode(exitConfig, fmt.Errorf("loading config: %w", err))
+			}
+		}
+
+		if ttl := cfg.Beans.ClickUp.CacheTTL; ttl != "" && !noCache {
+			d, err := time.ParseDuration(ttl)
+			if err != nil {
+				return withExitCode(exitConfig, fmt.Errorf("invalid cache_ttl %q: %w", ttl, err))
+			}
+			clickupOpts = append(clickupOpts, clickup.WithMetadataTTL(d))
+		}
+		if h := cfg.Beans.ClickUp.HTTP; h != nil {
+			opts, err := httpClientOptions(h)
 			if err != nil {
-				return fmt.Errorf("loading config: %w", err)
+				return withExitCode(exitConfig, err)
 			}
+			clickupOpts = append(clickupOpts, opts...)
 		}
+		setClientOptions(cmd, clickupOpts)
 
 		return nil
 	},
 }
 
+// clientOptions returns the options every ClickUp client the run creates
+// starts from: the API root, the response cache, or a cassette to record
+// into or replay from.
+func clientOptions() ([]clickup.ClientOption, error) {
+	var opts []clickup.ClientOption
+	// Point at another API root, such as beanup mock-server
+	if base := os.Getenv("CLICKUP_API_URL"); base != "" {
+		opts = append(opts, clickup.WithBaseURL(base))
+	}
+	switch {
+	case recordPath != "" && replayPath != "":
+		return nil, fmt.Errorf("--record and --replay can't be combined")
+	case replayPath != "":
+		cas, err := cassette.Load(replayPath)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, clickup.WithHTTPClient(cas.Replayer()))
+		// The recorded token was redacted; any token will do offline
+		if os.Getenv("CLICKUP_TOKEN") == "" {
+			_ = os.Setenv("CLICKUP_TOKEN", cassette.Redacted)
+		}
+		return opts, nil
+	}
+
+	// Revalidate cached ClickUp GETs instead of refetching them
+	if dir, err := clickup.HTTPCacheDir(); err == nil && !noCache {
+		opts = append(opts, clickup.WithResponseCache(dir))
+	}
+	if recordPath != "" {
+		recording = cassette.New()
+		opts = append(opts, clickup.WithRecorder(recording))
+	}
+	return opts, nil
+}
+
+// clientOptionsKey is the context key for the run's ClickUp client options.
+type clientOptionsKey struct{}
+
+// setClientOptions stores opts in cmd's context for newClickUpClient.
+func setClientOptions(cmd *cobra.Command, opts []clickup.ClientOption) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cmd.SetContext(context.WithValue(ctx, clientOptionsKey{}, opts))
+}
+
+// runClientOptions returns a copy of the run's ClickUp client options
+// from ctx. A context without them, as in tests, has none.
+func runClientOptions(ctx context.Context) []clickup.ClientOption {
+	opts, _ := ctx.Value(clientOptionsKey{}).([]clickup.ClientOption)
+	return slices.Clone(opts)
+}
+
+// newClickUpClient returns a ClickUp client with the run's options from
+// ctx, then extra.
+func newClickUpClient(ctx context.Context, token string, extra ...clickup.ClientOption) *clickup.Client {
+	return clickup.NewClient(token, append(runClientOptions(ctx), extra...)...)
+}
+
+// httpClientOptions returns the ClickUp client options for the http config.
+func httpClientOptions(h *config.HTTPConfig) ([]clickup.ClientOption, error) {
+	var opts []clickup.ClientOption
+	if h.Timeout != "" {
+		d, err := time.ParseDuration(h.Timeout)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid http.timeout %q: use a duration such as 30s", h.Timeout)
+		}
+		opts = append(opts, clickup.WithTimeout(d))
+	}
+	if h.MaxRetries != nil || h.BackoffBase != "" {
+		rc := clickup.DefaultRetryConfig()
+		if h.MaxRetries != nil {
+			if *h.MaxRetries < 0 {
+				return nil, fmt.Errorf("invalid http.max_retries %d: must be 0 or more", *h.MaxRetries)
+			}
+			rc.MaxRetries = *h.MaxRetries
+		}
+		if h.BackoffBase != "" {
+			d, err := time.ParseDuration(h.BackoffBase)
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("invalid http.backoff_base %q: use a duration such as 1s", h.BackoffBase)
+			}
+			rc.BaseRetryDelay = d
+		}
+		opts = append(opts, clickup.WithRetryConfig(rc))
+	}
+	if h.MaxIdleConns < 0 {
+		return nil, fmt.Errorf("invalid http.max_idle_conns %d: must be 0 or more", h.MaxIdleConns)
+	}
+	if h.MaxIdleConns > 0 {
+		opts = append(opts, clickup.WithMaxIdleConns(h.MaxIdleConns))
+	}
+	return opts, nil
+}
+
 // Execute runs the root command.
+// When OTEL_EXPORTER_OTLP_ENDPOINT is set, the run is traced and spans are
+// exported before returning.
 func Execute() error {
-	return rootCmd.Execute()
+	shutdown := tracing.Init("beanup", "")
+
+	ctx, span := tracing.Start(context.Background(), "beanup")
+	err := rootCmd.ExecuteContext(ctx)
+	span.RecordError(err)
+	span.End()
+
+	if recording != nil {
+		if saveErr := recording.Save(recordPath); saveErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", saveErr)
+		} else {
+			fmt.Fprintf(os.Stderr, "Recorded %d ClickUp requests to %s\n", recording.Len(), recordPath)
+		}
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if traceErr := shutdown(shutdownCtx); traceErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: exporting traces: %v\n", traceErr)
+	}
+
+	return err
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "Path to legacy .beans.clickup.yml config file")
 	rootCmd.PersistentFlags().StringVar(&beansPath, "beans-path", "", "path to beans directory (default: from .beans.yml)")
+	rootCmd.PersistentFlags().BoolVar(&noBeansCLI, "no-beans-cli", false, "read and write bean files directly instead of running the beans CLI")
 	rootCmd.PersistentFlags().BoolVar(&jsonOut, "json", false, "output as JSON")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "profile from the user config to apply (default: its default_profile)")
+	rootCmd.PersistentFlags().StringVar(&teamID, "team", "", "ClickUp workspace (team) ID to use when the token can access several (default: extensions.clickup.team_id)")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "don't use or update the ClickUp response cache")
+	rootCmd.PersistentFlags().StringVar(&recordPath, "record", "", "record this run's ClickUp requests and responses, sanitized, to a cassette file for a bug report")
+	rootCmd.PersistentFlags().StringVar(&replayPath, "replay", "", "answer ClickUp requests from a recorded cassette instead of the network")
+	_ = rootCmd.PersistentFlags().MarkHidden("replay")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "don't color output (also set by NO_COLOR, or when output isn't a terminal)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "only print errors and warnings (and --json output)")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "show per-bean detail, such as beans sync left unchanged")
+	rootCmd.PersistentFlags().DurationVar(&lockTimeout, "lock-timeout", 30*time.Second, "how long to wait for another beanup process to release the project lock")
 }
 
 // checkBeansInstalled returns true if the beans CLI is installed.
 func checkBeansInstalled() bool {
-	_, err := exec.LookPath("beans")
-	return err == nil
+	return beans.CLIInstalled()
 }
 
 // getBeansPath returns the resolved beans path.
@@ -100,13 +310,64 @@ func getBeansPath() string {
 	return path
 }
 
-// getClickUpToken returns the ClickUp API token from environment.
+// acquireProjectLock takes the cross-process lock in the beans directory,
+// waiting up to --lock-timeout for another beanup process to finish.
+func acquireProjectLock(ctx context.Context) (*lock.Lock, error) {
+	l, err := lock.Acquire(ctx, filepath.Join(getBeansPath(), lock.FileName), lockTimeout)
+	if errors.Is(err, lock.ErrLocked) {
+		return nil, fmt.Errorf("another beanup process is syncing this project (%w); retry later or raise --lock-timeout", err)
+	}
+	return l, err
+}
+
+// getClickUpToken returns the ClickUp API token from CLICKUP_TOKEN, the
+// configured token_command or token_file, or the stored `beanup auth login`.
 func getClickUpToken() (string, error) {
-	token := os.Getenv("CLICKUP_TOKEN")
-	if token == "" {
-		return "", fmt.Errorf("CLICKUP_TOKEN environment variable is not set")
+	var src config.TokenSource
+	if cfg != nil {
+		src = cfg.Beans.ClickUp.TokenSource
+	}
+	return auth.ClickUpToken(context.Background(), src)
+}
+
+// getTeamID returns the ClickUp workspace selected by --team or
+// extensions.clickup.team_id, or "" to use every accessible workspace.
+func getTeamID() string {
+	if teamID != "" {
+		return teamID
+	}
+	if cfg != nil {
+		return cfg.Beans.ClickUp.TeamID
+	}
+	return ""
+}
+
+// getTeams returns the selected ClickUp workspace, or every accessible one
+// if none is selected.
+func getTeams(ctx context.Context, client *clickup.Client) ([]clickup.Team, error) {
+	teams, err := client.GetTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id := getTeamID()
+	if id == "" {
+		return teams, nil
+	}
+	for _, team := range teams {
+		if team.ID == id {
+			return []clickup.Team{team}, nil
+		}
+	}
+	return nil, fmt.Errorf("workspace %s is not accessible with this token (run beanup spaces without --team to see available workspaces)", id)
+}
+
+// getCustomItems returns the custom task types of the selected workspace,
+// or of every accessible workspace if none is selected.
+func getCustomItems(ctx context.Context, client *clickup.Client) ([]clickup.CustomItem, error) {
+	if id := getTeamID(); id != "" {
+		return client.GetTeamCustomItems(ctx, id)
 	}
-	return token, nil
+	return client.GetCustomItems(ctx)
 }
 
 // outputJSON writes a value as indented JSON to stdout.
@@ -119,7 +380,7 @@ func outputJSON(v any) error {
 // requireListID returns an error if list_id is not configured.
 func requireListID() error {
 	if cfg.Beans.ClickUp.ListID == "" {
-		return fmt.Errorf("ClickUp list_id is required in .beans.yml extensions.clickup or .beans.clickup.yml")
+		return withExitCode(exitConfig, fmt.Errorf("ClickUp list_id is required in .beans.yml extensions.clickup or .beans.clickup.yml"))
 	}
 	return nil
 }
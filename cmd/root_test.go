@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/config"
+)
+
+func TestHTTPClientOptions(t *testing.T) {
+	zero, three, negative := 0, 3, -1
+	tests := []struct {
+		name     string
+		http     config.HTTPConfig
+		wantOpts int
+		wantErr  bool
+	}{
+		{"empty", config.HTTPConfig{}, 0, false},
+		{"all set", config.HTTPConfig{Timeout: "10s", MaxRetries: &three, BackoffBase: "500ms", MaxIdleConns: 20}, 3, false},
+		{"no timeout, no retries", config.HTTPConfig{Timeout: "0", MaxRetries: &zero}, 2, false},
+		{"bad timeout", config.HTTPConfig{Timeout: "soon"}, 0, true},
+		{"negative retries", config.HTTPConfig{MaxRetries: &negative}, 0, true},
+		{"zero backoff", config.HTTPConfig{BackoffBase: "0s"}, 0, true},
+		{"negative idle conns", config.HTTPConfig{MaxIdleConns: -1}, 0, true},
+	}
+	for _, tt := range tests {
+		opts, err := httpClientOptions(&tt.http)
+		if (err != nil) != tt.wantErr || len(opts) != tt.wantOpts {
+			t.Errorf("%s: httpClientOptions() = %d options, %v; want %d, error %v", tt.name, len(opts), err, tt.wantOpts, tt.wantErr)
+		}
+	}
+}
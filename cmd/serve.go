@@ -0,0 +1,506 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/clickup"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// serveStateFileName holds serve's cursor and recent webhook event IDs
+	// in the beans directory.
+	serveStateFileName = ".beanup-serve.json"
+	// seenEventLimit is how many recent event IDs are kept to spot retries.
+	seenEventLimit = 1000
+	// maxWebhookBody bounds the webhook payloads serve reads.
+	maxWebhookBody = 1 << 20
+	// maxEventAge is how old an event can be and still be accepted, so a
+	// captured delivery can't be replayed once it has left the seen IDs.
+	maxEventAge = time.Hour
+	// pullRetryDelay is how long serve waits to retry a failed pull.
+	pullRetryDelay = 30 * time.Second
+)
+
+var serveAddr string
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Pull ClickUp changes into beans as webhooks arrive",
+	Long: `Listens for ClickUp webhooks and pulls each changed task's fields back
+into its bean, as beanup pull does, within seconds of the edit.
+
+Point a ClickUp webhook for the workspace at http://<host><addr>/webhook
+and set its secret as extensions.clickup.webhook_secret or
+CLICKUP_WEBHOOK_SECRET. Requests without a valid X-Signature are rejected.
+
+Events are acknowledged as soon as they're verified, and their tasks are
+pulled in the background; failed pulls are retried. ClickUp retries
+deliveries it doesn't see acknowledged, so the IDs of recent events are
+remembered and repeats are ignored. Events more than an hour old, or
+without history items to date them, are rejected, so old deliveries
+can't be replayed.
+
+As with beanup pull, only beans that aren't completed or scrapped are
+pulled. The time through which every event has been pulled is saved in
+.beanup-serve.json in the beans directory; on restart, linked tasks whose
+history shows a change since then are pulled first, so edits made while
+serve was down, or queued when it stopped, aren't missed.`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", ":8787", "address to listen on")
+	rootCmd.AddCommand(serveCmd)
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	secret := cmp.Or(os.Getenv("CLICKUP_WEBHOOK_SECRET"), cfg.Beans.ClickUp.WebhookSecret)
+	if secret == "" {
+		return withExitCode(exitConfig, fmt.Errorf("no webhook secret: set extensions.clickup.webhook_secret or CLICKUP_WEBHOOK_SECRET"))
+	}
+	token, err := getClickUpToken()
+	if err != nil {
+		return err
+	}
+	client := newClickUpClient(cmd.Context(), token)
+	beansClient := beans.NewClient(getBeansPath())
+
+	state, err := loadWebhookState(filepath.Join(getBeansPath(), serveStateFileName))
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := interruptContext(cmd.Context())
+	defer stop()
+
+	pull := func(ctx context.Context, taskIDs []string) error {
+		return pullTasks(ctx, client, beansClient, taskIDs)
+	}
+	if err := backfillWebhooks(ctx, client, beansClient, state, pull); err != nil {
+		daemonLogf("backfill failed: %v", err)
+	}
+
+	workerCtx, stopWorker := context.WithCancel(ctx)
+	defer stopWorker()
+	queue := newPullQueue(state, pull)
+	var wg sync.WaitGroup
+	wg.Go(func() { queue.run(workerCtx, pullRetryDelay) })
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /webhook", &webhookHandler{secret: secret, state: state, queue: queue})
+	server := &http.Server{Addr: serveAddr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.ListenAndServe() }()
+	daemonLogf("listening on %s", serveAddr)
+
+	select {
+	case err := <-errCh:
+		stopWorker()
+		wg.Wait()
+		return fmt.Errorf("serving webhooks: %w", err)
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+	err = server.Shutdown(shutdownCtx)
+	stopWorker()
+	wg.Wait()
+	if err != nil {
+		return fmt.Errorf("stopping server: %w", err)
+	}
+	daemonLogf("stopped")
+	return nil
+}
+
+// backfillWebhooks pulls the linked tasks whose history shows a change
+// since the saved cursor, catching up on events missed while serve wasn't
+// running, whichever list the tasks are in now. A first run has no cursor
+// and pulls nothing.
+func backfillWebhooks(ctx context.Context, client *clickup.Client, beansClient *beans.Client, state *webhookState, pull func(context.Context, []string) error) error {
+	since := state.cursor()
+	if since.IsZero() {
+		return nil
+	}
+	beanList, err := beansClient.ListWith(beans.ListOptions{ExcludeStatus: []string{"completed", "scrapped"}})
+	if err != nil {
+		return fmt.Errorf("getting beans: %w", err)
+	}
+	taskIDs, latest := tasksChangedSince(ctx, client, clickupLinked(beanList), since)
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	daemonLogf("backfilling %d tasks changed since %s", len(taskIDs), since.UTC().Format(time.RFC3339))
+	if err := pull(ctx, taskIDs); err != nil {
+		return err
+	}
+	return state.advance(latest)
+}
+
+// tasksChangedSince fetches the history since since of the tasks linked to
+// beanList, returning the IDs of those changed, in order, and when the
+// latest change happened. A task whose history can't be fetched is
+// returned too, as it may have changed.
+func tasksChangedSince(ctx context.Context, client *clickup.Client, beanList []beans.Bean, since time.Time) ([]string, time.Time) {
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex // protects changed and latest
+		changed []string
+		latest  = since
+	)
+	sem := make(chan struct{}, statusFetchConcurrency)
+	for _, b := range beanList {
+		taskID := b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID)
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			items, err := client.GetTaskHistory(ctx, taskID, since)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				daemonLogf("task %s: %v", taskID, err)
+				changed = append(changed, taskID)
+				return
+			}
+			for _, h := range items {
+				if t := h.Time(); t.After(latest) {
+					latest = t
+				}
+			}
+			if len(items) > 0 {
+				changed = append(changed, taskID)
+			}
+		})
+	}
+	wg.Wait()
+	slices.Sort(changed)
+	return changed, latest
+}
+
+// pullTasks pulls the beans linked to taskIDs under the project lock,
+// logging what changed. Tasks no bean is linked to, and beans that are
+// completed or scrapped, are ignored.
+func pullTasks(ctx context.Context, client *clickup.Client, beansClient *beans.Client, taskIDs []string) error {
+	projectLock, err := acquireProjectLock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = projectLock.Release() }()
+
+	beanList, err := beansClient.ListWith(beans.ListOptions{ExcludeStatus: []string{"completed", "scrapped"}})
+	if err != nil {
+		return fmt.Errorf("getting beans: %w", err)
+	}
+	var linked []beans.Bean
+	for _, b := range beanList {
+		if slices.Contains(taskIDs, b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID)) {
+			linked = append(linked, b)
+		}
+	}
+	if len(linked) == 0 {
+		return nil
+	}
+	changes, err := pullBeans(ctx, client, beansClient, linked, false, false, false)
+	if err != nil {
+		return err
+	}
+	logPulled(changes)
+	return nil
+}
+
+// logPulled logs each pulled or skipped field.
+func logPulled(changes []pulledField) {
+	for _, c := range changes {
+		if c.Skipped != "" {
+			daemonLogf("skipped: %s %s: %s", c.BeanID, c.Field, c.Skipped)
+			continue
+		}
+		daemonLogf("pulled: %s %s %s → %s%s", c.BeanID, c.Field, orNone(c.From), c.To, c.credit())
+	}
+}
+
+// webhookEvent is the part of a ClickUp webhook payload serve uses.
+type webhookEvent struct {
+	Event        string                `json:"event"`
+	TaskID       string                `json:"task_id"`
+	WebhookID    string                `json:"webhook_id"`
+	HistoryItems []clickup.HistoryItem `json:"history_items"`
+}
+
+// id identifies the event across redeliveries: its history item IDs, or
+// for events without any, a hash of the payload.
+func (e *webhookEvent) id(body []byte) string {
+	var ids []string
+	for _, h := range e.HistoryItems {
+		if h.ID != "" {
+			ids = append(ids, h.ID)
+		}
+	}
+	if len(ids) > 0 {
+		return e.WebhookID + ":" + strings.Join(ids, ",")
+	}
+	sum := sha256.Sum256(body)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// time returns when the latest of the event's changes happened, or the
+// zero time if the payload doesn't say, as when it has no history items.
+func (e *webhookEvent) time() time.Time {
+	var latest time.Time
+	for _, h := range e.HistoryItems {
+		if t := h.Time(); t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
+
+// validSignature reports whether signature, an X-Signature header, is the
+// hex HMAC-SHA256 of body under secret.
+func validSignature(secret string, body []byte, signature string) bool {
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// webhookHandler verifies and dedupes ClickUp webhook events, queueing
+// their tasks to be pulled once the event is acknowledged.
+type webhookHandler struct {
+	secret string
+	state  *webhookState
+	queue  *pullQueue
+
+	mu sync.Mutex
+}
+
+func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "reading body", http.StatusBadRequest)
+		return
+	}
+	if !validSignature(h.secret, body, r.Header.Get("X-Signature")) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	var event webhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	// An event with no time can't be told from a replay once its ID has
+	// left the seen events
+	at := event.time()
+	if at.IsZero() {
+		http.Error(w, "event has no timestamp", http.StatusBadRequest)
+		return
+	}
+	if time.Since(at) > maxEventAge {
+		http.Error(w, "stale event", http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	id := event.id(body)
+	if h.state.seen(id) {
+		w.WriteHeader(http.StatusOK) // a retry of an event already queued
+		return
+	}
+	if err := h.state.record(id); err != nil {
+		daemonLogf("saving %s: %v", serveStateFileName, err)
+	}
+	if event.TaskID != "" {
+		h.queue.add(event.TaskID, at)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// pullQueue holds the tasks of acknowledged events until a single worker
+// pulls them, so pulls of the same bean can't interleave.
+type pullQueue struct {
+	state *webhookState
+	pull  func(ctx context.Context, taskIDs []string) error
+	wake  chan struct{}
+
+	mu      sync.Mutex
+	pending map[string]bool
+	latest  time.Time // when the latest queued event happened
+}
+
+func newPullQueue(state *webhookState, pull func(ctx context.Context, taskIDs []string) error) *pullQueue {
+	return &pullQueue{state: state, pull: pull, wake: make(chan struct{}, 1), pending: make(map[string]bool)}
+}
+
+// add queues taskID, changed at at, and wakes the worker.
+func (q *pullQueue) add(taskID string, at time.Time) {
+	q.mu.Lock()
+	q.pending[taskID] = true
+	if at.After(q.latest) {
+		q.latest = at
+	}
+	q.mu.Unlock()
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// pullPending pulls the queued tasks, queueing them again if the pull
+// fails. Once the queue is empty, the cursor advances to the latest event,
+// since every event up to it has been pulled.
+func (q *pullQueue) pullPending(ctx context.Context) error {
+	q.mu.Lock()
+	taskIDs := slices.Sorted(maps.Keys(q.pending))
+	clear(q.pending)
+	latest := q.latest
+	q.mu.Unlock()
+	if len(taskIDs) == 0 {
+		return nil
+	}
+
+	if err := q.pull(ctx, taskIDs); err != nil {
+		q.mu.Lock()
+		for _, id := range taskIDs {
+			q.pending[id] = true
+		}
+		q.mu.Unlock()
+		return err
+	}
+	q.mu.Lock()
+	drained := len(q.pending) == 0
+	q.mu.Unlock()
+	if !drained {
+		return nil
+	}
+	return q.state.advance(latest)
+}
+
+// run pulls queued tasks as they arrive until ctx is done, retrying failed
+// pulls after retryDelay. Tasks still queued then are left to the next
+// start's backfill, as the cursor hasn't passed them.
+func (q *pullQueue) run(ctx context.Context, retryDelay time.Duration) {
+	var retry <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		case <-retry:
+		}
+		retry = nil
+		if err := q.pullPending(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			daemonLogf("pulling tasks %s: %v", strings.Join(q.queued(), ", "), err)
+			retry = time.After(retryDelay)
+		}
+	}
+}
+
+// queued returns the IDs of the queued tasks, in order.
+func (q *pullQueue) queued() []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return slices.Sorted(maps.Keys(q.pending))
+}
+
+// webhookState is serve's cursor, the time through which every event has
+// been pulled, and the IDs of recent events, saved to path after every
+// change.
+type webhookState struct {
+	path string
+
+	mu     sync.Mutex
+	Cursor time.Time `json:"cursor"`
+	Seen   []string  `json:"seen"`
+}
+
+// loadWebhookState reads the state at path, or starts empty if there's none.
+func loadWebhookState(path string) (*webhookState, error) {
+	s := &webhookState{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func (s *webhookState) cursor() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Cursor
+}
+
+func (s *webhookState) seen(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Contains(s.Seen, id)
+}
+
+// record remembers event id, dropping the oldest beyond seenEventLimit.
+func (s *webhookState) record(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Seen = append(s.Seen, id)
+	if n := len(s.Seen) - seenEventLimit; n > 0 {
+		s.Seen = slices.Delete(s.Seen, 0, n)
+	}
+	return s.save()
+}
+
+// advance moves the cursor forward to at.
+func (s *webhookState) advance(at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !at.After(s.Cursor) {
+		return nil
+	}
+	s.Cursor = at
+	return s.save()
+}
+
+// save writes the state through a temporary file, so a crash can't leave
+// it half written. The caller holds mu.
+func (s *webhookState) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/clickup"
+	"github.com/toba/bean-me-up/internal/clickuptest"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	state, err := loadWebhookState(filepath.Join(t.TempDir(), serveStateFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pulled []string
+	failPull := false
+	queue := newPullQueue(state, func(ctx context.Context, taskIDs []string) error {
+		if failPull {
+			return errors.New("ClickUp unreachable")
+		}
+		pulled = append(pulled, taskIDs...)
+		return nil
+	})
+	h := &webhookHandler{secret: "s3cret", state: state, queue: queue}
+	deliver := func(body, signature string) int {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+		req.Header.Set("X-Signature", signature)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	now := time.Now().Truncate(time.Millisecond)
+	event := func(historyID, taskID string, at time.Time) string {
+		return fmt.Sprintf(`{"event":"taskUpdated","task_id":%q,"webhook_id":"wh1","history_items":[{"id":%q,"date":"%d"}]}`, taskID, historyID, at.UnixMilli())
+	}
+	signed := func(body string) string { return clickuptest.Signature("s3cret", []byte(body)) }
+	ctx := context.Background()
+
+	body := event("h1", "task-1", now)
+	if code := deliver(body, clickuptest.Signature("wrong", []byte(body))); code != http.StatusUnauthorized {
+		t.Errorf("bad signature: status %d, want 401", code)
+	}
+	if code := deliver(body, signed(body)); code != http.StatusOK {
+		t.Errorf("first delivery: status %d, want 200", code)
+	}
+	if code := deliver(body, signed(body)); code != http.StatusOK {
+		t.Errorf("redelivery: status %d, want 200", code)
+	}
+	// Events are acknowledged before they're pulled
+	if len(pulled) != 0 || !slices.Equal(queue.queued(), []string{"task-1"}) {
+		t.Errorf("pulled %v, queued %v before the worker ran", pulled, queue.queued())
+	}
+	if err := queue.pullPending(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(pulled, []string{"task-1"}) {
+		t.Errorf("pulled %v, want task-1 once", pulled)
+	}
+
+	// Old events are rejected, so they can't be replayed
+	body = event("h0", "task-0", now.Add(-2*maxEventAge))
+	if code := deliver(body, signed(body)); code != http.StatusBadRequest {
+		t.Errorf("stale event: status %d, want 400", code)
+	}
+
+	// So are events without a time, which can't be checked
+	body = `{"event":"taskUpdated","task_id":"task-0","webhook_id":"wh1"}`
+	if code := deliver(body, signed(body)); code != http.StatusBadRequest {
+		t.Errorf("undated event: status %d, want 400", code)
+	}
+
+	// A failed pull stays queued and holds back the cursor until it's retried
+	failPull = true
+	body = event("h2", "task-2", now.Add(time.Second))
+	if code := deliver(body, signed(body)); code != http.StatusOK {
+		t.Errorf("second event: status %d, want 200", code)
+	}
+	if err := queue.pullPending(ctx); err == nil {
+		t.Error("failed pull returned no error")
+	}
+	if !slices.Equal(queue.queued(), []string{"task-2"}) || !state.cursor().Equal(now) {
+		t.Errorf("after failed pull: queued %v, cursor %s", queue.queued(), state.cursor())
+	}
+	failPull = false
+	if err := queue.pullPending(ctx); err != nil || !slices.Equal(pulled, []string{"task-1", "task-2"}) {
+		t.Errorf("retry: %v, pulled %v", err, pulled)
+	}
+
+	// The cursor and seen events survive a restart
+	reloaded, err := loadWebhookState(state.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := now.Add(time.Second); !reloaded.cursor().Equal(want) {
+		t.Errorf("cursor = %s, want %s", reloaded.cursor(), want)
+	}
+	if !reloaded.seen("wh1:h1") || !reloaded.seen("wh1:h2") || reloaded.seen("wh1:h0") {
+		t.Errorf("seen = %v", reloaded.Seen)
+	}
+}
+
+func TestBackfillWebhooks(t *testing.T) {
+	beans.NoCLI = true
+	defer func() { beans.NoCLI = false }()
+
+	since := time.UnixMilli(1_000_000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/task/t-edited/history":
+			_, _ = w.Write([]byte(`{"history_items":[{"id":"h1","field":"priority","date":"1005000"},{"id":"h2","field":"name","date":"1002000"}]}`))
+		case "/task/t-quiet/history":
+			_, _ = w.Write([]byte(`{"history_items":[]}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"err":"Team not authorized","ECODE":"OAUTH_027"}`))
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"bup-a--edited.md": "---\ntitle: Edited\nstatus: todo\nextensions:\n    clickup:\n        task_id: t-edited\n---\n",
+		"bup-b--quiet.md":  "---\ntitle: Quiet\nstatus: todo\nextensions:\n    clickup:\n        task_id: t-quiet\n---\n",
+		"bup-c--broken.md": "---\ntitle: Broken\nstatus: todo\nextensions:\n    clickup:\n        task_id: t-broken\n---\n",
+		"bup-d--done.md":   "---\ntitle: Done\nstatus: completed\nextensions:\n    clickup:\n        task_id: t-done\n---\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	state, err := loadWebhookState(filepath.Join(dir, serveStateFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := state.advance(since); err != nil {
+		t.Fatal(err)
+	}
+
+	var pulled []string
+	client := clickup.NewClient("test", clickup.WithBaseURL(server.URL))
+	err = backfillWebhooks(context.Background(), client, beans.NewClient(dir), state, func(ctx context.Context, taskIDs []string) error {
+		pulled = taskIDs
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A task whose history can't be fetched is pulled in case it changed;
+	// completed beans are left alone
+	if !slices.Equal(pulled, []string{"t-broken", "t-edited"}) {
+		t.Errorf("pulled %v, want t-broken and t-edited", pulled)
+	}
+	if want := time.UnixMilli(1_005_000); !state.cursor().Equal(want) {
+		t.Errorf("cursor = %s, want %s", state.cursor(), want)
+	}
+}
+
+func TestWebhookState_Limit(t *testing.T) {
+	state, err := loadWebhookState(filepath.Join(t.TempDir(), serveStateFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	for i := range seenEventLimit + 5 {
+		if err := state.record(fmt.Sprint("e", i)); err != nil {
+			t.Fatal(err)
+		}
+		if err := state.advance(start.Add(time.Duration(i) * time.Second)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(state.Seen) != seenEventLimit || state.seen("e4") || !state.seen("e5") {
+		t.Errorf("kept %d events, from %s", len(state.Seen), state.Seen[0])
+	}
+	// The cursor never moves back
+	if err := state.advance(start); err != nil {
+		t.Fatal(err)
+	}
+	if want := start.Add((seenEventLimit + 4) * time.Second); !state.cursor().Equal(want) {
+		t.Errorf("cursor = %s, want %s", state.cursor(), want)
+	}
+}
+
+func TestWebhookEventID(t *testing.T) {
+	withHistory := &webhookEvent{WebhookID: "wh1", HistoryItems: []clickup.HistoryItem{{ID: "h1", Date: "1000"}}}
+	if id := withHistory.id([]byte("{}")); id != "wh1:h1" {
+		t.Errorf("id = %q, want wh1:h1", id)
+	}
+	if got := withHistory.time(); !got.Equal(time.UnixMilli(1000)) {
+		t.Errorf("time = %s", got)
+	}
+
+	// Payloads without history items are told apart by content
+	bare := &webhookEvent{}
+	a, b := bare.id([]byte(`{"task_id":"1"}`)), bare.id([]byte(`{"task_id":"2"}`))
+	if a == b || !strings.HasPrefix(a, "sha256:") {
+		t.Errorf("ids %q, %q", a, b)
+	}
+	if !bare.time().IsZero() {
+		t.Errorf("time = %s, want zero", bare.time())
+	}
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/clickup"
+	"github.com/toba/bean-me-up/internal/gitlink"
+	"github.com/toba/bean-me-up/internal/syncer"
+
+	// Register sync backends
+	_ "github.com/toba/bean-me-up/internal/azure"
+	_ "github.com/toba/bean-me-up/internal/github"
+	_ "github.com/toba/bean-me-up/internal/gitlab"
+	_ "github.com/toba/bean-me-up/internal/jira"
+	_ "github.com/toba/bean-me-up/internal/linear"
+	_ "github.com/toba/bean-me-up/internal/notion"
+)
+
+// sinkDisplayNames are the user-facing names of the sync backends.
+var sinkDisplayNames = map[string]string{
+	"clickup": "ClickUp",
+	"github":  "GitHub",
+	"gitlab":  "GitLab",
+	"jira":    "Jira",
+	"linear":  "Linear",
+	"azure":   "Azure Boards",
+	"notion":  "Notion",
+}
+
+// sinkDisplayName returns the user-facing name of a backend.
+func sinkDisplayName(name string) string {
+	if display, ok := sinkDisplayNames[name]; ok {
+		return display
+	}
+	return name
+}
+
+// selectedSinkNames returns the backend chosen with --sink, or every
+// configured backend.
+func selectedSinkNames() ([]string, error) {
+	if syncSink != "" {
+		return []string{syncSink}, nil
+	}
+	names := cfg.Sinks()
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no sync backend configured: set extensions.clickup, extensions.github, extensions.gitlab, extensions.jira, extensions.linear, extensions.azure, or extensions.notion in .beans.yml")
+	}
+	return names, nil
+}
+
+// selectedSinks builds the backends chosen with --sink, or every configured
+// backend. Missing configuration or credentials are reported here. opts
+// configure the ClickUp client after the run's own options from ctx.
+func selectedSinks(ctx context.Context, opts ...clickup.ClientOption) ([]syncer.Sink, error) {
+	names, err := selectedSinkNames()
+	if err != nil {
+		return nil, err
+	}
+	sinks := make([]syncer.Sink, 0, len(names))
+	for _, name := range names {
+		if name == clickup.SinkName {
+			cs, err := clickup.NewSinkFromConfig(cfg, append(runClientOptions(ctx), opts...)...)
+			if err != nil {
+				return nil, err
+			}
+			setSourceLinks(cs)
+			sinks = append(sinks, cs)
+			continue
+		}
+		sink, err := syncer.NewSink(name, cfg)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// setSourceLinks gives the ClickUp sink permalinks to bean files when
+// source_footer or the source_url field is configured. Without a usable
+// git origin remote the links are left out, with a warning.
+func setSourceLinks(sink *clickup.Sink) {
+	c := cfg.Beans.ClickUp
+	if !c.SourceFooter && (c.CustomFields == nil || c.CustomFields.SourceURL == "") {
+		return
+	}
+	beansDir := getBeansPath()
+	linker, err := gitlink.New(beansDir)
+	if err != nil {
+		_, _ = colorYellow.Fprintf(os.Stderr, "Warning: no source links: %v\n", err)
+		return
+	}
+	sink.SetSourceURL(func(b *beans.Bean) string {
+		if b.Path == "" {
+			return ""
+		}
+		return linker.URL(filepath.Join(beansDir, b.Path))
+	})
+}
+
+// sinkDisplayList joins display names for log messages.
+func sinkDisplayList(names []string) string {
+	display := make([]string, len(names))
+	for i, name := range names {
+		display[i] = sinkDisplayName(name)
+	}
+	return strings.Join(display, ", ")
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/toba/bean-me-up/internal/clickup"
+)
+
+var spacesCmd = &cobra.Command{
+	Use:   "spaces",
+	Short: "List ClickUp spaces in your workspaces",
+	Long: `Lists the spaces in every ClickUp workspace your token can access, or only
+the one selected with --team or extensions.clickup.team_id.
+
+Use the space ID with "beanup folders <space-id>" or "beanup lists <space-id>"
+to find the list ID for extensions.clickup.list_id.
+
+Requires CLICKUP_TOKEN environment variable to be set.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		token, err := getClickUpToken()
+		if err != nil {
+			return err
+		}
+		client := newClickUpClient(ctx, token)
+
+		teams, err := getTeams(ctx, client)
+		if err != nil {
+			return fmt.Errorf("fetching workspaces: %w", err)
+		}
+
+		type workspaceSpaces struct {
+			clickup.Team
+			Spaces []clickup.Space `json:"spaces"`
+		}
+		result := make([]workspaceSpaces, 0, len(teams))
+		for _, team := range teams {
+			spaces, err := client.GetSpaces(ctx, team.ID)
+			if err != nil {
+				return fmt.Errorf("fetching spaces for workspace %s: %w", team.Name, err)
+			}
+			result = append(result, workspaceSpaces{Team: team, Spaces: spaces})
+		}
+
+		if jsonOut {
+			return outputJSON(result)
+		}
+
+		if len(result) == 0 {
+			fmt.Println("No workspaces found for this token.")
+			return nil
+		}
+		for _, w := range result {
+			fmt.Printf("Workspace %q (%s):\n", w.Name, w.ID)
+			if len(w.Spaces) == 0 {
+				fmt.Println("  No spaces")
+			}
+			for _, s := range w.Spaces {
+				fmt.Printf("  %s\n    ID: %s\n", s.Name, s.ID)
+			}
+			fmt.Println()
+		}
+		fmt.Println("Next: beanup folders <space-id>")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(spacesCmd)
+}
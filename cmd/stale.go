@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/spf13/cobra"
+)
+
+var (
+	staleOlderThan time.Duration
+	staleOffline   bool
+)
+
+var staleCmd = &cobra.Command{
+	Use:   "stale",
+	Short: "List linked beans that haven't been synced lately",
+	Long: `Lists linked beans that are stale:
+
+  - not synced within stale_after (default a week), or never synced
+  - whose ClickUp task hasn't been updated within stale_after either
+
+Each row shows when the bean last changed, when it was last synced, and
+when its task last changed. Task times need a ClickUp token and are
+skipped with --offline. Completed and scrapped beans aren't listed.
+
+--older-than overrides stale_after for one run:
+
+  beanup stale --older-than 72h`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		staleAfter := staleOlderThan
+		if !cmd.Flags().Changed("older-than") {
+			var err error
+			if staleAfter, err = cfg.GetStaleAfter(); err != nil {
+				return withExitCode(exitConfig, err)
+			}
+		}
+
+		allBeans, err := beans.NewClient(getBeansPath()).List()
+		if err != nil {
+			return fmt.Errorf("listing beans: %w", err)
+		}
+		var beanList []beans.Bean
+		for _, b := range allBeans {
+			archived := b.Status == "completed" || b.Status == "scrapped"
+			if !archived && b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID) != "" {
+				beanList = append(beanList, b)
+			}
+		}
+
+		var live []liveTask
+		if !staleOffline {
+			if token, _ := getClickUpToken(); token != "" {
+				live, err = fetchLiveTasks(ctx, newClickUpClient(ctx, token), beanList, !jsonOut)
+				if err != nil {
+					return fmt.Errorf("fetching tasks: %w", err)
+				}
+			}
+		}
+
+		stale := staleBeans(beanList, live, staleAfter, time.Now())
+		if jsonOut {
+			return outputJSON(stale)
+		}
+		if len(stale) == 0 {
+			fmt.Printf("No stale beans (synced within %s)\n", formatAge(staleAfter))
+			return nil
+		}
+
+		fmt.Printf("%-15s %-16s %-16s %-16s %-20s %s\n",
+			"Bean ID", "Bean Updated", "Synced", "Task Updated", "Reason", "Title")
+		fmt.Println(strings.Repeat("─", 110))
+		for _, s := range stale {
+			title := s.Title
+			if len(title) > 30 {
+				title = title[:27] + "..."
+			}
+			fmt.Printf("%-15s %-16s %-16s %-16s %-20s %s\n",
+				s.BeanID, formatStaleTime(s.BeanUpdated), formatStaleTime(s.SyncedAt), formatStaleTime(s.TaskUpdated), s.Reason, title)
+		}
+		fmt.Printf("\n%d stale beans; run \"beanup sync\" to bring their tasks up to date\n", len(stale))
+		return nil
+	},
+}
+
+func init() {
+	staleCmd.Flags().DurationVar(&staleOlderThan, "older-than", 0, "list beans not synced within this long (default stale_after, or 168h)")
+	staleCmd.Flags().BoolVar(&staleOffline, "offline", false, "don't contact ClickUp; task update times won't be shown")
+	rootCmd.AddCommand(staleCmd)
+}
+
+// Reasons a bean is listed as stale.
+const (
+	staleNeverSynced = "never synced"
+	staleOldSync     = "sync older than limit"
+	staleTaskIdle    = "task not updated"
+)
+
+// staleBean is one row of the stale report.
+type staleBean struct {
+	BeanID      string     `json:"bean_id"`
+	Title       string     `json:"title"`
+	TaskID      string     `json:"task_id"`
+	BeanUpdated *time.Time `json:"bean_updated,omitempty"`
+	SyncedAt    *time.Time `json:"synced_at,omitempty"`
+	TaskUpdated *time.Time `json:"task_updated,omitempty"`
+	Reason      string     `json:"reason"`
+}
+
+// staleBeans returns the beans not synced within staleAfter before now, or
+// whose task per live (see fetchLiveTasks; nil if ClickUp wasn't
+// consulted) wasn't updated within it. Least recently synced come first.
+func staleBeans(beanList []beans.Bean, live []liveTask, staleAfter time.Duration, now time.Time) []staleBean {
+	threshold := now.Add(-staleAfter)
+	var stale []staleBean
+	for i, b := range beanList {
+		s := staleBean{
+			BeanID:      b.ID,
+			Title:       b.Title,
+			TaskID:      b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID),
+			BeanUpdated: b.UpdatedAt,
+			SyncedAt:    b.GetExtensionTime(beans.PluginClickUp, beans.ExtKeySyncedAt),
+		}
+		if live != nil {
+			s.TaskUpdated = live[i].UpdatedAt
+		}
+		switch {
+		case s.SyncedAt == nil:
+			s.Reason = staleNeverSynced
+		case s.SyncedAt.Before(threshold):
+			s.Reason = staleOldSync
+		case s.TaskUpdated != nil && s.TaskUpdated.Before(threshold):
+			s.Reason = staleTaskIdle
+		default:
+			continue
+		}
+		stale = append(stale, s)
+	}
+	slices.SortStableFunc(stale, func(a, b staleBean) int {
+		switch {
+		case a.SyncedAt == nil && b.SyncedAt == nil:
+			return 0
+		case a.SyncedAt == nil:
+			return -1
+		case b.SyncedAt == nil:
+			return 1
+		}
+		return a.SyncedAt.Compare(*b.SyncedAt)
+	})
+	return stale
+}
+
+// formatStaleTime formats a time for the stale table, or "-" if unknown.
+func formatStaleTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// formatAge formats a staleness limit: whole days as "7d", otherwise as a
+// Go duration without trailing zero units ("36h", "1h30m").
+func formatAge(d time.Duration) string {
+	const day = 24 * time.Hour
+	if d >= day && d%day == 0 {
+		return fmt.Sprintf("%dd", d/day)
+	}
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/beans"
+)
+
+func TestStaleBeans(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(n int) *time.Time {
+		t := now.AddDate(0, 0, -n)
+		return &t
+	}
+	linked := func(id string, syncedAt *time.Time) beans.Bean {
+		ext := map[string]any{beans.ExtKeyTaskID: "task-" + id}
+		if syncedAt != nil {
+			ext[beans.ExtKeySyncedAt] = syncedAt.Format(time.RFC3339)
+		}
+		return beans.Bean{ID: id, UpdatedAt: daysAgo(20), Extensions: map[string]map[string]any{beans.PluginClickUp: ext}}
+	}
+	beanList := []beans.Bean{
+		linked("fresh", daysAgo(1)),
+		linked("old", daysAgo(10)),
+		linked("never", nil),
+		linked("older", daysAgo(12)),
+		linked("idle", daysAgo(2)),
+	}
+	live := []liveTask{
+		{UpdatedAt: daysAgo(1)},
+		{UpdatedAt: daysAgo(1)},
+		{},
+		{},
+		{UpdatedAt: daysAgo(9)},
+	}
+
+	stale := staleBeans(beanList, live, 7*24*time.Hour, now)
+	want := []struct{ id, reason string }{
+		{"never", staleNeverSynced},
+		{"older", staleOldSync},
+		{"old", staleOldSync},
+		{"idle", staleTaskIdle},
+	}
+	if len(stale) != len(want) {
+		t.Fatalf("staleBeans() = %+v, want %d beans", stale, len(want))
+	}
+	for i, w := range want {
+		if stale[i].BeanID != w.id || stale[i].Reason != w.reason {
+			t.Errorf("stale[%d] = %s %q, want %s %q", i, stale[i].BeanID, stale[i].Reason, w.id, w.reason)
+		}
+	}
+	if stale[1].TaskUpdated != nil || stale[2].TaskUpdated == nil {
+		t.Error("task update times not carried over from live")
+	}
+
+	// Without ClickUp only sync times count
+	if stale := staleBeans(beanList, nil, 7*24*time.Hour, now); len(stale) != 3 {
+		t.Errorf("offline staleBeans() = %+v, want 3 beans", stale)
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		7 * 24 * time.Hour:           "7d",
+		36 * time.Hour:               "36h",
+		90 * time.Minute:             "1h30m",
+		30 * time.Second:             "30s",
+		2*time.Hour + 30*time.Second: "2h0m30s",
+	} {
+		if got := formatAge(d); got != want {
+			t.Errorf("formatAge(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
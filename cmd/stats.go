@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/clickup"
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+// slowestBeans is how many beans the sync statistics list by duration.
+const slowestBeans = 5
+
+// runStats are the statistics of one sync run. API calls are counted for
+// ClickUp only.
+type runStats struct {
+	DurationMS      int64           `json:"duration_ms"`
+	APICalls        int             `json:"api_calls"`
+	Retries         int             `json:"retries"`
+	RateLimited     int             `json:"rate_limited"` // Calls that backed off after a rate limit
+	RateLimitWaitMS int64           `json:"rate_limit_wait_ms"`
+	Endpoints       []endpointStats `json:"endpoints"`
+	Slowest         []beanTiming    `json:"slowest_beans"`
+}
+
+// endpointStats counts the calls to one API endpoint.
+type endpointStats struct {
+	Endpoint string `json:"endpoint"` // e.g. "GET /task/{id}"
+	Calls    int    `json:"calls"`
+	Retries  int    `json:"retries"`
+	TotalMS  int64  `json:"total_ms"`
+}
+
+// beanTiming is how long one bean took to sync.
+type beanTiming struct {
+	BeanID     string `json:"bean_id"`
+	Title      string `json:"title"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// statsCollector gathers runStats from the ClickUp client's request hook,
+// which concurrent sync workers call.
+type statsCollector struct {
+	start time.Time
+
+	mu            sync.Mutex
+	endpoints     map[string]*endpointStats
+	retries       int
+	rateLimited   int
+	rateLimitWait time.Duration
+}
+
+func newStatsCollector() *statsCollector {
+	return &statsCollector{start: time.Now(), endpoints: map[string]*endpointStats{}}
+}
+
+// observe records one API call; see clickup.WithRequestHook.
+func (c *statsCollector) observe(e clickup.RequestEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := e.Method + " " + e.Endpoint
+	ep := c.endpoints[key]
+	if ep == nil {
+		ep = &endpointStats{Endpoint: key}
+		c.endpoints[key] = ep
+	}
+	ep.Calls++
+	ep.TotalMS += e.Duration.Milliseconds()
+	if e.Attempts > 1 {
+		ep.Retries += e.Attempts - 1
+		c.retries += e.Attempts - 1
+	}
+	if e.RateLimitWait > 0 {
+		c.rateLimited++
+		c.rateLimitWait += e.RateLimitWait
+	}
+}
+
+// stats returns the statistics so far, with the slowest of results.
+// Endpoints are sorted by calls, most first.
+func (c *statsCollector) stats(results []syncer.Result) runStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := runStats{
+		DurationMS:      time.Since(c.start).Milliseconds(),
+		Retries:         c.retries,
+		RateLimited:     c.rateLimited,
+		RateLimitWaitMS: c.rateLimitWait.Milliseconds(),
+		Endpoints:       []endpointStats{},
+		Slowest:         []beanTiming{},
+	}
+	for _, key := range slices.Sorted(maps.Keys(c.endpoints)) {
+		s.APICalls += c.endpoints[key].Calls
+		s.Endpoints = append(s.Endpoints, *c.endpoints[key])
+	}
+	slices.SortStableFunc(s.Endpoints, func(a, b endpointStats) int { return b.Calls - a.Calls })
+
+	timed := slices.Clone(results)
+	slices.SortStableFunc(timed, func(a, b syncer.Result) int { return cmp.Compare(b.Duration, a.Duration) })
+	for _, r := range timed[:min(slowestBeans, len(timed))] {
+		if r.Duration > 0 {
+			s.Slowest = append(s.Slowest, beanTiming{BeanID: r.BeanID, Title: r.BeanTitle, DurationMS: r.Duration.Milliseconds()})
+		}
+	}
+	return s
+}
+
+// writeStats writes a one-line summary of s, and with detail the calls per
+// endpoint and the slowest beans.
+func writeStats(w io.Writer, s runStats, detail bool) {
+	ms := func(n int64) time.Duration { return time.Duration(n) * time.Millisecond }
+	_, _ = fmt.Fprintf(w, "Stats: %d API calls, %d retries", s.APICalls, s.Retries)
+	if s.RateLimited > 0 {
+		_, _ = fmt.Fprintf(w, ", %d rate limited (waited %s)", s.RateLimited, ms(s.RateLimitWaitMS))
+	}
+	_, _ = fmt.Fprintf(w, " in %s\n", ms(s.DurationMS))
+	if !detail {
+		return
+	}
+	if len(s.Endpoints) > 0 {
+		_, _ = fmt.Fprintln(w, "\nAPI calls by endpoint:")
+		for _, ep := range s.Endpoints {
+			_, _ = fmt.Fprintf(w, "  %5d  %-40s %8s", ep.Calls, ep.Endpoint, ms(ep.TotalMS))
+			if ep.Retries > 0 {
+				_, _ = fmt.Fprintf(w, "  (%d retries)", ep.Retries)
+			}
+			_, _ = fmt.Fprintln(w)
+		}
+	}
+	if len(s.Slowest) > 0 {
+		_, _ = fmt.Fprintln(w, "\nSlowest beans:")
+		for _, b := range s.Slowest {
+			_, _ = fmt.Fprintf(w, "  %8s  %-15s %s\n", ms(b.DurationMS), b.BeanID, truncateTitle(b.Title, 40))
+		}
+	}
+}
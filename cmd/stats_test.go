@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/clickup"
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+func TestStatsCollector(t *testing.T) {
+	c := newStatsCollector()
+	c.observe(clickup.RequestEvent{Method: "GET", Endpoint: "/task/{id}", Attempts: 1, Duration: 20 * time.Millisecond})
+	c.observe(clickup.RequestEvent{Method: "GET", Endpoint: "/task/{id}", Attempts: 3, RateLimitWait: 2 * time.Second, Duration: 2100 * time.Millisecond})
+	c.observe(clickup.RequestEvent{Method: "PUT", Endpoint: "/task/{id}", Attempts: 1, Duration: 50 * time.Millisecond})
+
+	results := []syncer.Result{
+		{BeanID: "bean-a", BeanTitle: "Fast", Duration: 10 * time.Millisecond},
+		{BeanID: "bean-b", BeanTitle: "Slow", Duration: 2 * time.Second},
+		{BeanID: "bean-c", BeanTitle: "Failed", Action: "error", Error: errors.New("boom"), Duration: 300 * time.Millisecond},
+		{BeanID: "bean-d", BeanTitle: "Relationships only"},
+	}
+	s := c.stats(results)
+
+	if s.APICalls != 3 || s.Retries != 2 || s.RateLimited != 1 || s.RateLimitWaitMS != 2000 {
+		t.Errorf("stats = %+v", s)
+	}
+	if len(s.Endpoints) != 2 || s.Endpoints[0].Endpoint != "GET /task/{id}" || s.Endpoints[0].Calls != 2 || s.Endpoints[0].Retries != 2 {
+		t.Errorf("Endpoints = %+v", s.Endpoints)
+	}
+	var slowest []string
+	for _, b := range s.Slowest {
+		slowest = append(slowest, b.BeanID)
+	}
+	if got := strings.Join(slowest, ","); got != "bean-b,bean-c,bean-a" {
+		t.Errorf("Slowest = %s, want bean-b,bean-c,bean-a (untimed beans left out)", got)
+	}
+
+	var out strings.Builder
+	writeStats(&out, s, false)
+	if !strings.HasPrefix(out.String(), "Stats: 3 API calls, 2 retries, 1 rate limited (waited 2s) in ") || strings.Count(out.String(), "\n") != 1 {
+		t.Errorf("summary = %q", out.String())
+	}
+	out.Reset()
+	writeStats(&out, s, true)
+	for _, want := range []string{"API calls by endpoint:", "PUT /task/{id}", "(2 retries)", "Slowest beans:", "bean-b"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("detail missing %q:\n%s", want, out.String())
+		}
+	}
+}
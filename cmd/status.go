@@ -2,27 +2,85 @@ package cmd
 
 import (
 	"context"
+	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/toba/bean-me-up/internal/beans"
 	"github.com/toba/bean-me-up/internal/clickup"
+	"github.com/toba/bean-me-up/internal/syncer"
 	"github.com/spf13/cobra"
 )
 
+var (
+	statusNeedsSync bool
+	statusUnlinked  bool
+	statusFilter    []string
+	statusSort      string
+	statusFormat    string
+)
+
 var statusCmd = &cobra.Command{
 	Use:   "status [bean-id...]",
 	Short: "Show ClickUp sync status for beans",
 	Long: `Shows the sync status of beans with their linked ClickUp tasks.
 
 If bean IDs are provided, shows status for those beans. Otherwise, shows
-status for all beans that are linked to ClickUp tasks.`,
+status for all beans that are linked to ClickUp tasks, or with --unlinked,
+for the beans that are not.
+
+The Sync column compares when the bean and the ClickUp task last changed
+with when they were last synced:
+
+  in sync      neither side changed since the last sync
+  bean ahead   the bean changed; "beanup sync" will push it
+  task ahead   the task was edited in ClickUp since the last sync
+  conflict     both sides changed since the last sync
+  type differs neither changed, but the task type doesn't match the bean's
+               (see "beanup pull")
+  not linked   the bean has no ClickUp task
+
+Task changes are only detected when a ClickUp token is available.
+
+Reports can be narrowed and reshaped for sharing, e.g. a markdown table of
+everything out of sync for a standup doc:
+
+  beanup status --needs-sync --sort sync --format markdown
+
+In CI (--ci, or automatically under GitHub Actions) deleted tasks and
+out-of-sync beans are reported as workflow annotations. --fail-on drift
+exits non-zero if any bean is out of sync, --fail-on errors only if a
+linked task was deleted.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		ctx := context.Background()
+		ctx := cmd.Context()
+
+		policy, err := setupCI(cmd)
+		if err != nil {
+			return err
+		}
+
+		format := statusFormat
+		if jsonOut {
+			format = "json"
+		}
+		if !slices.Contains(statusFormats, format) {
+			return fmt.Errorf("unknown --format %q (use %s)", format, strings.Join(statusFormats, ", "))
+		}
+		less, ok := statusSorts[statusSort]
+		if statusSort != "" && !ok {
+			return fmt.Errorf("unknown --sort %q (use %s)", statusSort, strings.Join(slices.Sorted(maps.Keys(statusSorts)), ", "))
+		}
 
 		// Get beans to check
 		beansClient := beans.NewClient(getBeansPath())
 		var beanList []beans.Bean
-		var err error
 
 		if len(args) > 0 {
 			// Check specific beans
@@ -31,122 +89,430 @@ status for all beans that are linked to ClickUp tasks.`,
 				return fmt.Errorf("getting beans: %w", err)
 			}
 		} else {
-			// Show all linked beans
+			// Show all linked (or, with --unlinked, unlinked) beans
 			allBeans, err := beansClient.List()
 			if err != nil {
 				return fmt.Errorf("listing beans: %w", err)
 			}
-			// Filter to only beans with ClickUp extension data
+			// Filter on ClickUp extension data
 			for _, b := range allBeans {
-				if b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID) != "" {
+				linked := b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID) != ""
+				if linked != statusUnlinked {
 					beanList = append(beanList, b)
 				}
 			}
 		}
 
-		if len(beanList) == 0 {
-			if jsonOut {
-				fmt.Println("[]")
-				return nil
-			}
-			fmt.Println("No beans are linked to ClickUp tasks")
-			return nil
+		// Filter by bean status before fetching any tasks
+		if len(statusFilter) > 0 {
+			beanList = slices.DeleteFunc(beanList, func(b beans.Bean) bool {
+				return !slices.Contains(statusFilter, b.Status)
+			})
 		}
 
 		// Try to get ClickUp client for live status check
 		var client *clickup.Client
 		token, _ := getClickUpToken()
 		if token != "" {
-			client = clickup.NewClient(token)
-		}
-
-		// Build status info
-		type statusInfo struct {
-			BeanID     string `json:"bean_id"`
-			BeanTitle  string `json:"bean_title"`
-			BeanStatus string `json:"bean_status"`
-			TaskID     string `json:"task_id,omitempty"`
-			TaskStatus string `json:"task_status,omitempty"`
-			TaskURL    string `json:"task_url,omitempty"`
-			Linked     bool   `json:"linked"`
-			NeedsSync  bool   `json:"needs_sync"`
-		}
-
-		statuses := make([]statusInfo, len(beanList))
-		for i, b := range beanList {
-			taskID := b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID)
-			syncedAt := b.GetExtensionTime(beans.PluginClickUp, beans.ExtKeySyncedAt)
-
-			// Calculate needsSync
-			needsSync := true
-			if syncedAt != nil && b.UpdatedAt != nil {
-				needsSync = b.UpdatedAt.After(*syncedAt)
-			} else if syncedAt != nil {
-				needsSync = false
-			}
+			client = newClickUpClient(ctx, token)
+		}
 
-			statuses[i] = statusInfo{
-				BeanID:     b.ID,
-				BeanTitle:  b.Title,
-				BeanStatus: b.Status,
-				TaskID:     taskID,
-				Linked:     taskID != "",
-				NeedsSync:  needsSync,
+		// Fetch live task status if we have a client
+		var live []liveTask
+		var sink *clickup.Sink
+		if client != nil {
+			sink = clickup.NewSink(client, &cfg.Beans.ClickUp, cfg.Beans.ClickUp.ListID)
+			live, err = fetchLiveTasks(ctx, client, beanList, format == "table")
+			if err != nil {
+				return fmt.Errorf("fetching task status: %w", err)
 			}
+		}
 
-			// Fetch live task status if we have a client and task ID
-			if client != nil && taskID != "" {
-				// Skip archived beans (completed, scrapped)
-				if b.Status == "completed" || b.Status == "scrapped" {
-					continue
-				}
-				task, err := client.GetTask(ctx, taskID)
-				if err == nil {
-					statuses[i].TaskStatus = task.Status.Status
-					statuses[i].TaskURL = task.URL
+		statuses := beanStatuses(beanList, sink, live)
+		if statusNeedsSync {
+			statuses = slices.DeleteFunc(statuses, func(s beanSyncStatus) bool { return !s.NeedsSync })
+		}
+
+		if less != nil {
+			slices.SortStableFunc(statuses, less)
+		}
+
+		errCount, driftCount, conflictCount := 0, 0, 0
+		for _, s := range statuses {
+			if s.TaskStatus == "(deleted)" {
+				errCount++
+			} else if s.NeedsSync {
+				driftCount++
+				if s.Drift == driftConflict {
+					conflictCount++
 				}
 			}
 		}
+		if ciMode() {
+			annotateStatuses(os.Stderr, statuses)
+		}
 
-		if jsonOut {
-			return outputJSON(statuses)
+		if err := outputStatuses(format, statuses); err != nil {
+			return err
+		}
+		return checkFailOn(policy, errCount, driftCount, conflictCount)
+	},
+}
+
+// outputStatuses writes the status report in the given format.
+func outputStatuses(format string, statuses []beanSyncStatus) error {
+	switch format {
+	case "json":
+		return outputJSON(statuses)
+	case "csv":
+		return writeStatusCSV(os.Stdout, statuses)
+	case "markdown":
+		return writeStatusMarkdown(os.Stdout, statuses)
+	}
+
+	if len(statuses) == 0 {
+		switch {
+		case statusNeedsSync || len(statusFilter) > 0:
+			fmt.Println("No beans match the given filters")
+		case statusUnlinked:
+			fmt.Println("All beans are linked to ClickUp tasks")
+		default:
+			fmt.Println("No beans are linked to ClickUp tasks")
 		}
+		return nil
+	}
 
-		// Text output
-		fmt.Printf("%-15s %-15s %-15s %-15s %s\n",
-			"Bean ID", "Status", "Task ID", "Task Status", "Title")
-		fmt.Println("───────────────────────────────────────────────────────────────────────────────────")
+	// Text output
+	fmt.Printf("%-15s %-15s %-15s %-15s %-12s %s\n",
+		"Bean ID", "Status", "Task ID", "Task Status", "Sync", "Title")
+	fmt.Println("───────────────────────────────────────────────────────────────────────────────────────────────")
 
-		for _, s := range statuses {
-			taskStr := "-"
-			taskStatusStr := "-"
-			if s.TaskID != "" {
-				taskStr = s.TaskID
-				if len(taskStr) > 12 {
-					taskStr = taskStr[:12] + "..."
+	for _, s := range statuses {
+		taskStr := "-"
+		taskStatusStr := "-"
+		if s.TaskID != "" {
+			taskStr = s.TaskID
+			if len(taskStr) > 12 {
+				taskStr = taskStr[:12] + "..."
+			}
+		}
+		if s.TaskStatus != "" {
+			taskStatusStr = s.TaskStatus
+		}
+
+		title := s.BeanTitle
+		if len(title) > 40 {
+			title = title[:37] + "..."
+		}
+
+		fmt.Printf("%-15s %-15s %-15s %-15s %-12s %s\n",
+			s.BeanID,
+			s.BeanStatus,
+			taskStr,
+			taskStatusStr,
+			s.Drift,
+			title)
+	}
+
+	return nil
+}
+
+// beanStatuses builds the status row of each bean. Task changes are only
+// considered when live holds the beans' fetched tasks (see fetchLiveTasks),
+// with sink to compare them.
+func beanStatuses(beanList []beans.Bean, sink *clickup.Sink, live []liveTask) []beanSyncStatus {
+	taskIDs := make(map[string]string, len(beanList))
+	for _, b := range beanList {
+		taskIDs[b.ID] = b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID)
+	}
+
+	statuses := make([]beanSyncStatus, 0, len(beanList))
+	for i, b := range beanList {
+		taskID := b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID)
+		syncedAt := b.GetExtensionTime(beans.PluginClickUp, beans.ExtKeySyncedAt)
+
+		s := beanSyncStatus{
+			BeanID:     b.ID,
+			BeanTitle:  b.Title,
+			BeanStatus: b.Status,
+			TaskID:     taskID,
+			Linked:     taskID != "",
+		}
+		var taskUpdatedAt *time.Time
+		typeDiffers, remoteDrift := false, ""
+		if live != nil {
+			s.TaskStatus = live[i].Status
+			s.TaskURL = live[i].URL
+			taskUpdatedAt = live[i].UpdatedAt
+			if task := live[i].Task; task != nil {
+				typeDiff := sink.TypeDiff(&b, task)
+				s.TaskType, typeDiffers = typeDiff.Task, typeDiff.Changed
+				if syncedAt != nil {
+					remoteDrift = syncer.RemoteDrift(sink, clickup.NewTaskRef(task), &b, *syncedAt, blockedTaskIDs(&b, taskIDs))
 				}
 			}
-			if s.TaskStatus != "" {
-				taskStatusStr = s.TaskStatus
+		}
+
+		s.Drift = driftUnlinked
+		if s.Linked {
+			s.Drift = syncDrift(b.UpdatedAt, taskUpdatedAt, syncedAt)
+		}
+		// Tag and relationship edits in ClickUp may not move date_updated
+		if s.Drift == driftInSync && remoteDrift != "" {
+			s.Drift = driftTaskAhead
+		}
+		if s.Drift == driftInSync && typeDiffers {
+			s.Drift = driftTypeDiffers
+		}
+		s.NeedsSync = s.Drift != driftInSync
+		statuses = append(statuses, s)
+	}
+	return statuses
+}
+
+// annotateStatuses writes GitHub Actions annotations for beans whose task
+// was deleted and for beans out of sync.
+func annotateStatuses(w io.Writer, statuses []beanSyncStatus) {
+	for _, s := range statuses {
+		switch {
+		case s.TaskStatus == "(deleted)":
+			writeAnnotation(w, "error", "beanup status", fmt.Sprintf("%s (%s): linked task %s was deleted", s.BeanID, s.BeanTitle, s.TaskID))
+		case s.NeedsSync:
+			writeAnnotation(w, "warning", "beanup status", fmt.Sprintf("%s (%s): %s", s.BeanID, s.BeanTitle, s.Drift))
+		}
+	}
+}
+
+// statusFetchConcurrency bounds the GetTask calls status makes at once.
+// The client already backs off on rate limiting.
+const statusFetchConcurrency = 8
+
+// liveTask is the ClickUp side of a status row.
+type liveTask struct {
+	Status    string
+	URL       string
+	UpdatedAt *time.Time
+	Task      *clickup.TaskInfo // nil if the task wasn't fetched
+}
+
+// fetchLiveTasks fetches the linked task of each bean concurrently,
+// returning results in bean order. Archived beans (completed, scrapped),
+// unlinked beans and epics synced as lists are skipped. With progress set, a dot per task is
+// printed to stderr for larger projects.
+func fetchLiveTasks(ctx context.Context, client *clickup.Client, beanList []beans.Bean, progress bool) ([]liveTask, error) {
+	live := make([]liveTask, len(beanList))
+	var pending []int
+	for i, b := range beanList {
+		archived := b.Status == "completed" || b.Status == "scrapped"
+		taskID := b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID)
+		if !archived && taskID != "" && !clickup.IsListRef(taskID) {
+			pending = append(pending, i)
+		}
+	}
+
+	// Only show dots for 5+ tasks to avoid clutter
+	progress = progress && len(pending) >= 5
+	if progress {
+		fmt.Fprintf(os.Stderr, "Fetching %d tasks ", len(pending))
+		defer fmt.Fprintln(os.Stderr)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex // protects firstErr and progress output
+		firstErr error
+	)
+	sem := make(chan struct{}, statusFetchConcurrency)
+	for _, i := range pending {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			taskID := beanList[i].GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID)
+			task, err := client.GetTask(ctx, taskID)
+			switch {
+			case err == nil:
+				live[i] = liveTask{Status: task.Status.Status, URL: task.URL, UpdatedAt: task.UpdatedAt(), Task: task}
+			case errors.Is(err, clickup.ErrTaskNotFound):
+				live[i].Status = "(deleted)"
+			case errors.Is(err, clickup.ErrUnauthorized):
+				// Every other call will fail the same way
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
 			}
 
-			title := s.BeanTitle
-			if len(title) > 40 {
-				title = title[:37] + "..."
+			if progress {
+				mu.Lock()
+				fmt.Fprint(os.Stderr, ".")
+				mu.Unlock()
 			}
+		})
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return live, nil
+}
+
+// beanSyncStatus is one row of the status report.
+type beanSyncStatus struct {
+	BeanID     string `json:"bean_id"`
+	BeanTitle  string `json:"bean_title"`
+	BeanStatus string `json:"bean_status"`
+	TaskID     string `json:"task_id,omitempty"`
+	TaskStatus string `json:"task_status,omitempty"`
+	TaskURL    string `json:"task_url,omitempty"`
+	TaskType   string `json:"task_type,omitempty"`
+	Linked     bool   `json:"linked"`
+	NeedsSync  bool   `json:"needs_sync"`
+	Drift      string `json:"drift"`
+}
+
+// statusFormats are the values accepted by status --format.
+var statusFormats = []string{"table", "json", "csv", "markdown"}
 
-			fmt.Printf("%-15s %-15s %-15s %-15s %s\n",
-				s.BeanID,
-				s.BeanStatus,
-				taskStr,
-				taskStatusStr,
-				title)
+// statusSorts orders status rows for each value accepted by status --sort.
+var statusSorts = map[string]func(a, b beanSyncStatus) int{
+	"id":     func(a, b beanSyncStatus) int { return strings.Compare(a.BeanID, b.BeanID) },
+	"status": func(a, b beanSyncStatus) int { return strings.Compare(a.BeanStatus, b.BeanStatus) },
+	"title":  func(a, b beanSyncStatus) int { return strings.Compare(a.BeanTitle, b.BeanTitle) },
+	"sync":   func(a, b beanSyncStatus) int { return driftOrder[a.Drift] - driftOrder[b.Drift] },
+}
+
+// statusColumns are the csv and markdown report columns.
+var statusColumns = []string{"Bean ID", "Status", "Task ID", "Task Status", "Sync", "Title", "Task URL"}
+
+func (s beanSyncStatus) columns() []string {
+	return []string{s.BeanID, s.BeanStatus, s.TaskID, s.TaskStatus, s.Drift, s.BeanTitle, s.TaskURL}
+}
+
+// writeStatusCSV writes status rows as CSV with a header row.
+func writeStatusCSV(w io.Writer, statuses []beanSyncStatus) error {
+	rows := make([][]string, len(statuses))
+	for i, s := range statuses {
+		rows[i] = s.columns()
+	}
+	return writeCSV(w, statusColumns, rows)
+}
+
+// writeStatusMarkdown writes status rows as a GitHub-flavored markdown table.
+func writeStatusMarkdown(w io.Writer, statuses []beanSyncStatus) error {
+	rows := make([][]string, len(statuses))
+	for i, s := range statuses {
+		rows[i] = s.columns()
+	}
+	return writeMarkdownTable(w, statusColumns, rows)
+}
+
+// writeCSV writes a header row followed by rows as CSV.
+func writeCSV(w io.Writer, header []string, rows [][]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if err := cw.Write(r); err != nil {
+			return err
 		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
 
-		return nil
-	},
+// writeMarkdownTable writes a GitHub-flavored markdown table, escaping pipes
+// and newlines in cells.
+func writeMarkdownTable(w io.Writer, header []string, rows [][]string) error {
+	row := func(cells []string) string {
+		escaped := make([]string, len(cells))
+		for i, c := range cells {
+			escaped[i] = strings.ReplaceAll(strings.ReplaceAll(c, "|", "\\|"), "\n", " ")
+		}
+		return "| " + strings.Join(escaped, " | ") + " |\n"
+	}
+	var b strings.Builder
+	b.WriteString(row(header))
+	b.WriteString("|" + strings.Repeat(" --- |", len(header)) + "\n")
+	for _, r := range rows {
+		b.WriteString(row(r))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Sync drift directions reported by the status command.
+const (
+	driftInSync    = "in sync"
+	driftBeanAhead = "bean ahead"
+	driftTaskAhead = "task ahead"
+	driftConflict  = "conflict"
+	driftUnlinked  = "not linked"
+
+	// driftTypeDiffers is a linked pair whose timestamps agree but whose
+	// task type doesn't match the bean type.
+	driftTypeDiffers = "type differs"
+)
+
+// driftOrder sorts the rows needing attention first.
+var driftOrder = map[string]int{
+	driftConflict:    0,
+	driftTaskAhead:   1,
+	driftBeanAhead:   2,
+	driftTypeDiffers: 3,
+	driftUnlinked:    4,
+	driftInSync:      5,
+}
+
+// taskClockSkew is how far a task's date_updated may trail past synced_at
+// without counting as a ClickUp-side edit.
+const taskClockSkew = syncer.TaskClockSkew
+
+// blockedTaskIDs returns the task IDs of the beans b blocks that are linked,
+// per taskIDs (bean ID to task ID).
+func blockedTaskIDs(b *beans.Bean, taskIDs map[string]string) []string {
+	var ids []string
+	for _, blockedID := range b.Blocking {
+		if id := taskIDs[blockedID]; id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// syncDrift reports which side changed since the last sync. A bean that
+// was never synced is ahead; a nil taskUpdated means the task side is unknown.
+func syncDrift(beanUpdated, taskUpdated, syncedAt *time.Time) string {
+	if syncedAt == nil {
+		return driftBeanAhead
+	}
+	beanAhead := beanUpdated != nil && beanUpdated.After(*syncedAt)
+	taskAhead := taskUpdated != nil && taskUpdated.After(syncedAt.Add(taskClockSkew))
+	switch {
+	case beanAhead && taskAhead:
+		return driftConflict
+	case beanAhead:
+		return driftBeanAhead
+	case taskAhead:
+		return driftTaskAhead
+	default:
+		return driftInSync
+	}
 }
 
 func init() {
+	statusCmd.Flags().BoolVar(&statusNeedsSync, "needs-sync", false, "only show beans that are not in sync")
+	statusCmd.Flags().BoolVar(&statusUnlinked, "unlinked", false, "show beans not linked to a ClickUp task instead of linked ones")
+	statusCmd.Flags().StringSliceVar(&statusFilter, "status", nil, "only show beans with these bean statuses (comma-separated or repeated)")
+	statusCmd.Flags().StringVar(&statusSort, "sort", "", "sort by id, status, title, or sync (needing attention first)")
+	statusCmd.Flags().StringVar(&statusFormat, "format", "table", "output format: table, json, csv, or markdown")
+	addCIFlags(statusCmd)
 	rootCmd.AddCommand(statusCmd)
 }
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/clickup"
+)
+
+func TestSyncDrift(t *testing.T) {
+	synced := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	before := synced.Add(-time.Hour)
+	after := synced.Add(time.Hour)
+	skewed := synced.Add(time.Second)
+
+	tests := []struct {
+		name        string
+		beanUpdated *time.Time
+		taskUpdated *time.Time
+		syncedAt    *time.Time
+		want        string
+	}{
+		{"never synced", &before, nil, nil, driftBeanAhead},
+		{"nothing changed", &before, &before, &synced, driftInSync},
+		{"task unknown", &before, nil, &synced, driftInSync},
+		{"bean changed", &after, &before, &synced, driftBeanAhead},
+		{"task changed", &before, &after, &synced, driftTaskAhead},
+		{"both changed", &after, &after, &synced, driftConflict},
+		{"task written during sync", &before, &skewed, &synced, driftInSync},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := syncDrift(tt.beanUpdated, tt.taskUpdated, tt.syncedAt); got != tt.want {
+				t.Errorf("syncDrift() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteStatusReports(t *testing.T) {
+	statuses := []beanSyncStatus{
+		{BeanID: "bean-a", BeanStatus: "todo", TaskID: "abc", TaskStatus: "to do", Drift: driftBeanAhead, BeanTitle: "Fix a | b"},
+		{BeanID: "bean-b", BeanStatus: "draft", Drift: driftUnlinked, BeanTitle: "Plan, then build"},
+	}
+
+	var md strings.Builder
+	if err := writeStatusMarkdown(&md, statuses); err != nil {
+		t.Fatal(err)
+	}
+	wantMD := `| Bean ID | Status | Task ID | Task Status | Sync | Title | Task URL |
+| --- | --- | --- | --- | --- | --- | --- |
+| bean-a | todo | abc | to do | bean ahead | Fix a \| b |  |
+| bean-b | draft |  |  | not linked | Plan, then build |  |
+`
+	if md.String() != wantMD {
+		t.Errorf("markdown =\n%s\nwant\n%s", md.String(), wantMD)
+	}
+
+	var csv strings.Builder
+	if err := writeStatusCSV(&csv, statuses); err != nil {
+		t.Fatal(err)
+	}
+	wantCSV := `Bean ID,Status,Task ID,Task Status,Sync,Title,Task URL
+bean-a,todo,abc,to do,bean ahead,Fix a | b,
+bean-b,draft,,,not linked,"Plan, then build",
+`
+	if csv.String() != wantCSV {
+		t.Errorf("csv =\n%s\nwant\n%s", csv.String(), wantCSV)
+	}
+}
+
+func TestStatusSortSync(t *testing.T) {
+	statuses := []beanSyncStatus{
+		{BeanID: "a", Drift: driftInSync},
+		{BeanID: "b", Drift: driftBeanAhead},
+		{BeanID: "c", Drift: driftConflict},
+	}
+	slices.SortStableFunc(statuses, statusSorts["sync"])
+	if statuses[0].BeanID != "c" || statuses[1].BeanID != "b" || statuses[2].BeanID != "a" {
+		t.Errorf("sorted = %+v", statuses)
+	}
+}
+
+func TestFetchLiveTasks(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		id := strings.TrimPrefix(r.URL.Path, "/task/")
+		if id == "gone" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"err":"Task not found","ECODE":"ITEM_013"}`))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"id":%q,"status":{"status":"open"},"url":"https://app.clickup.com/t/%s","date_updated":"1772366400000"}`, id, id)
+	}))
+	defer server.Close()
+
+	linked := func(id, taskID, status string) beans.Bean {
+		return beans.Bean{ID: id, Status: status, Extensions: map[string]map[string]any{
+			beans.PluginClickUp: {beans.ExtKeyTaskID: taskID},
+		}}
+	}
+	var beanList []beans.Bean
+	for i := range 20 {
+		beanList = append(beanList, linked(fmt.Sprintf("bean-%d", i), fmt.Sprintf("t%d", i), "todo"))
+	}
+	beanList = append(beanList,
+		linked("bean-gone", "gone", "todo"),
+		linked("bean-done", "t-done", "completed"),
+		beans.Bean{ID: "bean-unlinked", Status: "todo"},
+		linked("bean-epic", "list:901", "todo"),
+	)
+
+	client := clickup.NewClient("test", clickup.WithBaseURL(server.URL))
+	live, err := fetchLiveTasks(context.Background(), client, beanList, false)
+	if err != nil {
+		t.Fatalf("fetchLiveTasks() error = %v", err)
+	}
+
+	if live[3].Status != "open" || live[3].URL != "https://app.clickup.com/t/t3" || live[3].UpdatedAt == nil {
+		t.Errorf("live[3] = %+v", live[3])
+	}
+	if got := live[20].Status; got != "(deleted)" {
+		t.Errorf("deleted task status = %q", got)
+	}
+	if live[21] != (liveTask{}) || live[22] != (liveTask{}) {
+		t.Errorf("archived or unlinked beans were fetched: %+v, %+v", live[21], live[22])
+	}
+	if live[23] != (liveTask{}) {
+		t.Errorf("epic list was fetched as a task: %+v", live[23])
+	}
+	if m := maxInFlight.Load(); m > statusFetchConcurrency {
+		t.Errorf("max concurrent requests = %d, want <= %d", m, statusFetchConcurrency)
+	}
+}
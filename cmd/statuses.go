@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"strings"
 
@@ -19,7 +18,7 @@ status_mapping in your .beans.clickup.yml configuration.
 
 Requires CLICKUP_TOKEN environment variable to be set.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		ctx := context.Background()
+		ctx := cmd.Context()
 
 		// Validate config
 		if err := requireListID(); err != nil {
@@ -33,7 +32,7 @@ Requires CLICKUP_TOKEN environment variable to be set.`,
 		}
 
 		// Create client
-		client := clickup.NewClient(token)
+		client := newClickUpClient(ctx, token)
 
 		// Fetch list info (includes statuses)
 		list, err := client.GetList(ctx, cfg.Beans.ClickUp.ListID)
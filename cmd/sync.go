@@ -1,13 +1,25 @@
 package cmd
 
 import (
+	"bufio"
+	"cmp"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/toba/bean-me-up/internal/beans"
 	"github.com/toba/bean-me-up/internal/clickup"
+	"github.com/toba/bean-me-up/internal/notify"
+	"github.com/toba/bean-me-up/internal/queue"
+	"github.com/toba/bean-me-up/internal/syncer"
 	"github.com/toba/bean-me-up/internal/syncstate"
 	"github.com/spf13/cobra"
 )
@@ -16,170 +28,543 @@ var (
 	syncDryRun          bool
 	syncForce           bool
 	syncNoRelationships bool
+	syncRelationsOnly   bool
+	syncStatusOnly      bool
+	syncNoNotify        bool
+	syncSink            string
+	syncInteractive     bool
+	syncJSONStream      bool
+	syncStats           bool
 )
 
 var syncCmd = &cobra.Command{
 	Use:   "sync [bean-id...]",
-	Short: "Sync beans to ClickUp tasks",
-	Long: `Syncs beans to ClickUp tasks using the ClickUp REST API.
+	Short: "Sync beans to ClickUp, GitHub, Jira, Linear, GitLab, Azure Boards, or Notion",
+	Long: `Syncs beans to every configured backend: ClickUp tasks (extensions.clickup),
+GitHub issues (extensions.github), Jira issues (extensions.jira), Linear
+issues (extensions.linear), GitLab issues (extensions.gitlab), Azure Boards
+work items (extensions.azure), and Notion database pages
+(extensions.notion). Use --sink to pick one.
 
 If bean IDs are provided, only those beans are synced. Otherwise, all beans
-matching the sync filter are synced.
+matching the backend's sync filter are synced.
 
 The sync operation:
-1. Creates new ClickUp tasks for beans without a linked task
+1. Creates new tasks for beans without a linked task
 2. Updates existing tasks if the bean has changed since last sync
 3. Optionally syncs blocking relationships as task dependencies
 
-Requires CLICKUP_TOKEN environment variable to be set.`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		ctx := context.Background()
+With --status-only, only the status of changed beans that already have a
+task is pushed, checking only the task's current status and skipping it
+if that already matches. New beans are left for the next full sync, which
+still picks up the other changes.
+
+With --relationships-only, no tasks are created or updated. Every linked
+bean's parent (as a subtask) and blocking relationships (as dependencies)
+are set on its task, whether or not the bean changed, e.g. after linking
+beans to existing tasks in bulk. Relationships are only added, never
+removed.
+
+With --interactive, the beans that need syncing are listed first, all
+selected. Toggle beans by number, expand a bean's diff against its task
+with d <number>, and sync the selection with y (like git add -i).
 
-		// Validate config
-		if err := requireListID(); err != nil {
+In CI (--ci, or automatically under GitHub Actions) errors and pending
+changes are reported as workflow annotations, color and prompts are off,
+and the run fails on sync errors. --fail-on drift also fails when any bean
+needed syncing, e.g. with --dry-run to gate merges on an up-to-date
+tracker; --fail-on never always exits zero.
+
+Each backend reads its token from the environment: CLICKUP_TOKEN,
+GITHUB_TOKEN, JIRA_API_TOKEN (plus JIRA_EMAIL or extensions.jira.email),
+LINEAR_API_KEY, GITLAB_TOKEN, AZURE_DEVOPS_PAT, or NOTION_TOKEN.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policy, err := setupCI(cmd)
+		if err != nil {
 			return err
 		}
+		if syncInteractive && (jsonOut || syncJSONStream || quiet || ciMode()) {
+			return fmt.Errorf("--interactive can't be combined with --json, --json-stream, --quiet, or CI mode")
+		}
+		if syncRelationsOnly && (syncStatusOnly || syncNoRelationships || syncInteractive) {
+			return fmt.Errorf("--relationships-only can't be combined with --status-only, --no-relationships, or --interactive")
+		}
 
-		// Get ClickUp token
-		token, err := getClickUpToken()
+		ctx, stop := interruptContext(cmd.Context())
+		defer stop()
+
+		collector := newStatsCollector()
+		results, message, err := runSync(ctx, args, jsonOut || quiet || syncJSONStream, clickup.WithRequestHook(collector.observe))
+		if aborted, ok := errors.AsType[*reviewAbortedError](err); ok {
+			fmt.Fprintf(cmd.OutOrStdout(), "Sync cancelled; already synced to %s\n", strings.Join(aborted.synced, ", "))
+			return nil
+		}
+		if errors.Is(err, errReviewAborted) {
+			fmt.Fprintln(cmd.OutOrStdout(), "Sync cancelled; nothing was changed")
+			return nil
+		}
 		if err != nil {
+			if interrupted(ctx) {
+				return interruptedError("beans synced before it were saved")
+			}
 			return err
 		}
 
-		// Check for legacy .sync.json and warn
-		syncFilePath := filepath.Join(getBeansPath(), syncstate.SyncFileName)
-		if _, err := os.Stat(syncFilePath); err == nil {
-			fmt.Fprintln(os.Stderr, "Warning: Legacy .sync.json found. Run 'beanup migrate' to migrate sync state to bean extension metadata.")
+		errCount, driftCount := countSyncHealth(results)
+		if ciMode() {
+			annotateSyncResults(os.Stderr, results)
 		}
 
-		// Create clients
-		client := clickup.NewClient(token)
-		beansClient := beans.NewClient(getBeansPath())
-
-		// Get beans to sync
-		var beanList []beans.Bean
-		if len(args) > 0 {
-			// Sync specific beans
-			beanList, err = beansClient.GetMultiple(args)
-			if err != nil {
-				return fmt.Errorf("getting beans: %w", err)
+		stats := collector.stats(results)
+		switch {
+		case syncJSONStream:
+			// Each result was already written as it completed
+			if syncStats {
+				err = json.NewEncoder(os.Stdout).Encode(struct {
+					Stats runStats `json:"stats"`
+				}{stats})
 			}
-		} else {
-			// Sync all beans matching filter
-			beanList, err = beansClient.List()
-			if err != nil {
-				return fmt.Errorf("listing beans: %w", err)
+		case jsonOut && syncStats:
+			err = outputJSON(struct {
+				Results []jsonResult `json:"results"`
+				Stats   runStats     `json:"stats"`
+			}{newJSONResults(results), stats})
+		case message != "" && jsonOut:
+			fmt.Println("[]")
+		case message != "":
+			fmt.Println(message)
+		case jsonOut:
+			err = outputResultsJSON(results)
+		default:
+			if err = outputResultsText(results); err == nil {
+				writeStats(os.Stdout, stats, syncStats || verbose)
 			}
-			beanList = clickup.FilterBeansForSync(beanList, cfg.Beans.ClickUp.SyncFilter)
 		}
+		if err != nil {
+			return err
+		}
+		if interrupted(ctx) {
+			return interruptedError("beans synced before it were saved; run beanup sync again to finish")
+		}
+		return checkFailOn(policy, errCount, driftCount, 0)
+	},
+}
 
-		if len(beanList) == 0 {
-			if jsonOut {
-				fmt.Println("[]")
-				return nil
-			}
-			fmt.Println("No beans to sync")
-			return nil
+// runSync performs a single sync pass to each selected backend using the
+// global sync flags. If there is nothing to sync, it returns a nil result
+// slice and a message explaining why. Progress is printed unless quiet is set.
+// opts apply only to the ClickUp client this sync builds.
+func runSync(ctx context.Context, args []string, quiet bool, opts ...clickup.ClientOption) ([]syncer.Result, string, error) {
+	// Build sinks first so missing config or tokens fail fast
+	sinks, err := selectedSinks(ctx, opts...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Check for legacy .sync.json and warn
+	syncFilePath := filepath.Join(getBeansPath(), syncstate.SyncFileName)
+	if _, err := os.Stat(syncFilePath); err == nil {
+		fmt.Fprintln(os.Stderr, "Warning: Legacy .sync.json found. Run 'beanup migrate' to migrate sync state to bean extension metadata.")
+	}
+
+	// Serialize against other beanup processes (e.g. a git hook or the daemon)
+	if !syncDryRun {
+		projectLock, err := acquireProjectLock(ctx)
+		if err != nil {
+			return nil, "", err
 		}
+		defer func() { _ = projectLock.Release() }()
+	}
 
-		// Create sync state provider from bean extension metadata
-		syncProvider := clickup.NewExtensionSyncProvider(beansClient, beanList)
+	beansClient := beans.NewClient(getBeansPath())
 
-		// Pre-filter to beans that actually need syncing
-		beansToSync := clickup.FilterBeansNeedingSync(beanList, syncProvider, syncForce)
-		if len(beansToSync) == 0 {
-			if jsonOut {
-				fmt.Println("[]")
-				return nil
-			}
-			fmt.Println("All beans up to date")
-			return nil
+	// Get beans to sync
+	var beanList []beans.Bean
+	if len(args) > 0 {
+		// Sync specific beans
+		beanList, err = beansClient.GetMultiple(args)
+		if err != nil {
+			return nil, "", fmt.Errorf("getting beans: %w", err)
+		}
+	} else {
+		// Sync all beans; each sink applies its own filter below. Statuses
+		// every sink excludes are filtered by beans, and bodies are loaded
+		// later for just the beans that need syncing
+		beanList, err = beansClient.ListWith(beans.ListOptions{
+			ExcludeStatus: commonExcludedStatuses(sinks),
+			NoBody:        true,
+		})
+		if err != nil {
+			return nil, "", fmt.Errorf("listing beans: %w", err)
 		}
+	}
+
+	var rev *review
+	if syncInteractive {
+		rev = &review{in: bufio.NewReader(rootCmd.InOrStdin()), out: rootCmd.OutOrStdout()}
+	}
 
-		// Create syncer with progress callback
-		opts := clickup.SyncOptions{
-			DryRun:          syncDryRun,
-			Force:           syncForce,
-			NoRelationships: syncNoRelationships,
-			ListID:          cfg.Beans.ClickUp.ListID,
+	var results []syncer.Result
+	var message string
+	var synced []string // backends that changed something, for a quit review
+	bySink := make(map[string][]syncer.Result)
+	for _, sink := range sinks {
+		sinkBeans := beanList
+		if len(args) == 0 {
+			sinkBeans = syncer.FilterBeansForSync(beanList, cfg.SyncFilterFor(sink.Name()))
 		}
 
-		// Show progress unless JSON output is requested
-		// Only show dots for 5+ beans to avoid clutter
-		if !jsonOut {
-			fmt.Printf("Syncing %d beans to ClickUp", len(beansToSync))
-			if len(beansToSync) >= 5 {
-				fmt.Print(" ")
-				opts.OnProgress = func(result clickup.SyncResult, completed, total int) {
-					if result.Error != nil {
-						fmt.Print("x")
-					} else {
-						fmt.Print(".")
-					}
-				}
+		sinkResults, sinkMessage, err := syncToSink(ctx, sink, beansClient, sinkBeans, quiet, rev)
+		if errors.Is(err, errReviewAborted) && len(synced) > 0 {
+			return nil, "", &reviewAbortedError{synced: synced}
+		}
+		if err != nil {
+			if !syncDryRun && !errors.Is(err, errReviewAborted) && ctx.Err() == nil {
+				sendSyncNotification(ctx, results, err)
 			}
+			return nil, "", err
+		}
+		results = append(results, sinkResults...)
+		bySink[sink.Name()] = sinkResults
+		if len(sinkResults) > 0 && !syncDryRun {
+			synced = append(synced, sinkDisplayName(sink.Name()))
 		}
+		message = sinkMessage
+		// Interrupted: keep what this sink completed, skip the rest
+		if ctx.Err() != nil {
+			break
+		}
+	}
 
-		syncer := clickup.NewSyncer(client, &cfg.Beans.ClickUp, opts, getBeansPath(), syncProvider)
+	// Only a full listing shows which beans were deleted
+	if len(args) == 0 && !syncDryRun && !syncRelationsOnly && ctx.Err() == nil {
+		if err := buryDeletedBeans(ctx, sinks, beansClient, beanList, bySink, quiet); err != nil {
+			return nil, "", err
+		}
+	}
 
-		// Run sync
-		results, err := syncer.SyncBeans(ctx, beansToSync)
+	if len(results) == 0 {
+		return nil, message, nil
+	}
+	if !syncDryRun && ctx.Err() == nil {
+		sendSyncNotification(ctx, results, nil)
+	}
+	return results, "", nil
+}
 
-		// Print newline after progress dots
-		if !jsonOut {
-			fmt.Println()
+// commonExcludedStatuses returns the statuses excluded by every sink's
+// sync_filter, which can be left out of the listing altogether.
+func commonExcludedStatuses(sinks []syncer.Sink) []string {
+	var common []string
+	for i, sink := range sinks {
+		var excluded []string
+		if filter := cfg.SyncFilterFor(sink.Name()); filter != nil {
+			excluded = filter.ExcludeStatus
 		}
+		if i == 0 {
+			common = slices.Clone(excluded)
+			continue
+		}
+		common = slices.DeleteFunc(common, func(s string) bool { return !slices.Contains(excluded, s) })
+	}
+	return common
+}
+
+// syncToSink syncs beanList to one backend and flushes its sync state.
+// With rev, the user picks the beans to sync there first.
+func syncToSink(ctx context.Context, sink syncer.Sink, beansClient *beans.Client, beanList []beans.Bean, quiet bool, rev *review) ([]syncer.Result, string, error) {
+	if len(beanList) == 0 {
+		return nil, "No beans to sync", nil
+	}
+
+	// Create sync state provider from bean extension metadata
+	syncProvider := syncer.NewExtensionStateProvider(beansClient, sink.Name(), beanList)
+
+	if syncRelationsOnly {
+		return syncRelationships(ctx, sink, syncProvider, beanList, quiet)
+	}
+
+	// Pre-filter to beans changed since their last sync and, unless only
+	// statuses are pushed, beans whose task was edited remotely
+	var remote map[string]*syncer.TaskRef
+	var beansToSync []beans.Bean
+	if syncStatusOnly {
+		beansToSync = syncer.FilterBeansNeedingSync(beanList, syncProvider, syncForce)
+	} else {
+		beansToSync, remote = syncer.FilterBeansNeedingRemoteSync(ctx, sink, beanList, syncProvider, syncForce)
+	}
+
+	// Syncs queued while the sink was unreachable go first, in order
+	journal, err := queue.Load(queuePath())
+	if err != nil {
+		return nil, "", err
+	}
+	beansToSync = withQueued(beanList, beansToSync, queue.For(journal, sink.Name()))
+	if len(beansToSync) == 0 {
+		return nil, "All beans up to date", nil
+	}
+	if !syncStatusOnly {
+		if err := beansClient.LoadBodies(beansToSync); err != nil {
+			return nil, "", fmt.Errorf("loading bean bodies: %w", err)
+		}
+	}
+
+	// Let the user pick which beans to sync
+	if rev != nil {
+		_, _ = fmt.Fprintf(rev.out, "%d beans need syncing to %s\n", len(beansToSync), sinkDisplayName(sink.Name()))
+		selected, err := reviewBeans(rev.in, rev.out, beansToSync, syncProvider, sinkDiffer(ctx, sink, syncProvider))
 		if err != nil {
-			return fmt.Errorf("sync failed: %w", err)
+			return nil, "", err
 		}
+		if len(selected) == 0 {
+			return nil, "No beans selected", nil
+		}
+		beansToSync = selected
+	}
 
-		// Flush sync state to bean extension metadata
-		if !syncDryRun {
-			if flushErr := syncProvider.Flush(); flushErr != nil {
-				return fmt.Errorf("saving sync state: %w", flushErr)
+	// Create syncer with progress callback
+	opts := syncer.Options{
+		DryRun:          syncDryRun,
+		Force:           syncForce,
+		NoRelationships: syncNoRelationships,
+		StatusOnly:      syncStatusOnly,
+		StoreTaskURL:    sink.Name() == beans.PluginClickUp && cfg.Beans.ClickUp.StoreTaskURL,
+		RemoteTasks:     remote,
+	}
+	if sink.Name() == beans.PluginClickUp {
+		opts.OnMove = cfg.Beans.ClickUp.OnMove
+	}
+
+	// Stream each result as a JSON line as soon as it completes
+	if syncJSONStream {
+		enc := json.NewEncoder(os.Stdout)
+		var mu sync.Mutex // OnProgress is called from concurrent workers
+		opts.OnProgress = func(result syncer.Result, completed, total int) {
+			mu.Lock()
+			defer mu.Unlock()
+			line := struct {
+				jsonResult
+				Sink      string `json:"sink"`
+				Completed int    `json:"completed"`
+				Total     int    `json:"total"`
+			}{newJSONResult(result), sink.Name(), completed, total}
+			if err := enc.Encode(line); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: writing result: %v\n", err)
 			}
 		}
+	}
 
-		// Output results
-		if jsonOut {
-			return outputResultsJSON(results)
+	// Show progress unless quiet (e.g. JSON output is requested)
+	// Only show progress for 5+ beans to avoid clutter
+	if !quiet {
+		fmt.Printf("Syncing %d beans to %s", len(beansToSync), sinkDisplayName(sink.Name()))
+		if len(beansToSync) >= 5 {
+			progress := newSyncProgress(os.Stdout, len(beansToSync))
+			if progress.tty {
+				fmt.Println()
+			} else {
+				fmt.Print(" ")
+			}
+			var mu sync.Mutex // OnProgress is called from concurrent workers
+			opts.OnProgress = func(result syncer.Result, completed, total int) {
+				mu.Lock()
+				defer mu.Unlock()
+				progress.Update(result, completed)
+			}
 		}
-		return outputResultsText(results)
-	},
+	}
+
+	// Run sync
+	results, err := syncer.New(sink, opts, syncProvider).SyncBeans(ctx, beansToSync)
+
+	// Print newline after progress
+	if !quiet {
+		fmt.Println()
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("sync failed: %w", err)
+	}
+	// Tags still reach tasks, but not the space's tag picker
+	if cs, ok := sink.(*clickup.Sink); ok {
+		for _, err := range cs.SpaceTagErrors() {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	// Flush sync state to bean extension metadata, and queue what couldn't
+	// reach the sink
+	if !syncDryRun {
+		if err := syncProvider.Flush(); err != nil {
+			return nil, "", fmt.Errorf("saving sync state: %w", err)
+		}
+		queued, err := updateQueue(queuePath(), sink.Name(), journal, results)
+		if err != nil {
+			return nil, "", err
+		}
+		warnQueued(sink.Name(), queued)
+		if err := recordSyncErrors(sink.Name(), results); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	return results, "", nil
+}
+
+// syncRelationships sets the relationships of every linked bean in
+// beanList on its task, for --relationships-only. Beans whose relationships
+// aren't also in beanList can't be linked to them, so it takes every bean
+// rather than just the changed ones.
+func syncRelationships(ctx context.Context, sink syncer.Sink, syncProvider syncer.StateProvider, beanList []beans.Bean, quiet bool) ([]syncer.Result, string, error) {
+	opts := syncer.Options{
+		DryRun:            syncDryRun,
+		RelationshipsOnly: true,
+		StoreTaskURL:      sink.Name() == beans.PluginClickUp && cfg.Beans.ClickUp.StoreTaskURL,
+	}
+	if !quiet {
+		fmt.Printf("Syncing relationships of %d beans to %s\n", len(beanList), sinkDisplayName(sink.Name()))
+	}
+	results, err := syncer.New(sink, opts, syncProvider).SyncBeans(ctx, beanList)
+	if err != nil {
+		return nil, "", fmt.Errorf("sync failed: %w", err)
+	}
+	return results, "", nil
 }
 
 func init() {
 	syncCmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "Show what would be done without making changes")
 	syncCmd.Flags().BoolVar(&syncForce, "force", false, "Force update even if unchanged")
 	syncCmd.Flags().BoolVar(&syncNoRelationships, "no-relationships", false, "Skip syncing blocking relationships as dependencies")
+	syncCmd.Flags().BoolVar(&syncRelationsOnly, "relationships-only", false, "Only set parent and blocking relationships of already linked beans")
+	syncCmd.Flags().BoolVar(&syncStatusOnly, "status-only", false, "Push only status changes of already linked beans")
+	syncCmd.Flags().BoolVar(&syncNoNotify, "no-notify", false, "Don't post the configured webhook notification")
+	syncCmd.Flags().StringVar(&syncSink, "sink", "", "Sync only to this backend (default: every configured backend)")
+	syncCmd.Flags().BoolVar(&syncJSONStream, "json-stream", false, "Write each result as a JSON line as soon as it completes")
+	syncCmd.Flags().BoolVar(&syncStats, "stats", false, "Show API calls by endpoint and the slowest beans, and include statistics in JSON output")
+	syncCmd.Flags().BoolVarP(&syncInteractive, "interactive", "i", false, "List the beans to sync and choose which to sync")
+	addCIFlags(syncCmd)
 	rootCmd.AddCommand(syncCmd)
 }
 
-func outputResultsJSON(results []clickup.SyncResult) error {
-	type jsonResult struct {
-		BeanID    string `json:"bean_id"`
-		BeanTitle string `json:"bean_title"`
-		TaskID    string `json:"task_id,omitempty"`
-		TaskURL   string `json:"task_url,omitempty"`
-		Action    string `json:"action"`
-		Error     string `json:"error,omitempty"`
+// sendSyncNotification posts a sync summary to the configured webhook, if any.
+// Failures are reported as warnings and never fail the sync.
+func sendSyncNotification(ctx context.Context, results []syncer.Result, failure error) {
+	nc := cfg.Beans.ClickUp.Notifications
+	if syncNoNotify || nc == nil || nc.WebhookURL == "" {
+		return
+	}
+
+	minSeverity, err := notify.ParseSeverity(nc.MinSeverity)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: notifications: %v\n", err)
+		return
+	}
+	notifier, err := notify.New(nc.WebhookURL, nc.Format, minSeverity)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: notifications: %v\n", err)
+		return
 	}
 
+	summary := &notify.Summary{Failure: failure}
+	for _, r := range results {
+		item := notify.Item{BeanID: r.BeanID, Title: r.BeanTitle, URL: r.TaskURL}
+		switch r.Action {
+		case "created":
+			summary.Created = append(summary.Created, item)
+		case "updated":
+			summary.Updated = append(summary.Updated, item)
+		case "unchanged", "skipped":
+			summary.Unchanged++
+		case "error":
+			if r.Error != nil {
+				item.Error = r.Error.Error()
+			}
+			summary.Errors = append(summary.Errors, item)
+		}
+	}
+
+	title := "beanup sync"
+	if configDir != "" {
+		title += " (" + filepath.Base(configDir) + ")"
+	}
+	if _, err := notifier.Notify(ctx, title, summary); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// jsonResult is a sync result as written by --json and --json-stream.
+type jsonResult struct {
+	BeanID    string `json:"bean_id"`
+	BeanTitle string `json:"bean_title"`
+	TaskID    string `json:"task_id,omitempty"`
+	TaskURL   string `json:"task_url,omitempty"`
+	Action    string `json:"action"`
+	Error     string `json:"error,omitempty"`
+	MovedTo   string `json:"moved_to,omitempty"`
+	// Why the bean was synced or left alone; see syncer.Result
+	Reason        string   `json:"reason,omitempty"`
+	FieldsChanged []string `json:"fields_changed,omitempty"`
+	Retried       bool     `json:"retried,omitempty"`
+	Conflict      bool     `json:"conflict,omitempty"`
+}
+
+func newJSONResult(r syncer.Result) jsonResult {
+	result := jsonResult{
+		BeanID:    r.BeanID,
+		BeanTitle: r.BeanTitle,
+		TaskID:    r.TaskID,
+		TaskURL:   r.TaskURL,
+		Action:    r.Action,
+		MovedTo:   r.MovedTo,
+
+		Reason:        r.Reason,
+		FieldsChanged: r.FieldsChanged,
+		Retried:       r.Retried,
+		Conflict:      r.Conflict,
+	}
+	if r.Error != nil {
+		result.Error = r.Error.Error()
+	}
+	return result
+}
+
+func outputResultsJSON(results []syncer.Result) error {
+	return outputJSON(newJSONResults(results))
+}
+
+func newJSONResults(results []syncer.Result) []jsonResult {
 	jsonResults := make([]jsonResult, len(results))
 	for i, r := range results {
-		jsonResults[i] = jsonResult{
-			BeanID:    r.BeanID,
-			BeanTitle: r.BeanTitle,
-			TaskID:    r.TaskID,
-			TaskURL:   r.TaskURL,
-			Action:    r.Action,
-		}
-		if r.Error != nil {
-			jsonResults[i].Error = r.Error.Error()
+		jsonResults[i] = newJSONResult(r)
+	}
+	return jsonResults
+}
+
+// countSyncHealth counts failed beans and beans that needed syncing.
+func countSyncHealth(results []syncer.Result) (errCount, driftCount int) {
+	for _, r := range results {
+		switch r.Action {
+		case "error":
+			errCount++
+		case "created", "updated", "moved", "would create", "would update":
+			driftCount++
 		}
 	}
+	return errCount, driftCount
+}
 
-	return outputJSON(jsonResults)
+// annotateSyncResults writes GitHub Actions annotations for failed beans
+// and for the changes a dry run would make.
+func annotateSyncResults(w io.Writer, results []syncer.Result) {
+	for _, r := range results {
+		switch r.Action {
+		case "error":
+			writeAnnotation(w, "error", "beanup sync", fmt.Sprintf("%s (%s): %v", r.BeanID, r.BeanTitle, r.Error))
+		case "would create", "would update":
+			writeAnnotation(w, "warning", "beanup sync", fmt.Sprintf("%s (%s) is out of sync: %s", r.BeanID, r.BeanTitle, r.Action))
+		case "moved":
+			writeAnnotation(w, "warning", "beanup sync", fmt.Sprintf("%s (%s): task moved to %s", r.BeanID, r.BeanTitle, r.MovedTo))
+		}
+	}
 }
 
 func truncateTitle(title string, maxLen int) string {
@@ -189,32 +574,95 @@ func truncateTitle(title string, maxLen int) string {
 	return title[:maxLen] + "…"
 }
 
-func outputResultsText(results []clickup.SyncResult) error {
-	var created, updated, unchanged, skipped, errors int
+func outputResultsText(results []syncer.Result) error {
+	var created, updated, unchanged, skipped, moved, cancelled, errors, conflicts int
+	reasons := map[string]int{}
 
 	for _, r := range results {
+		why := explainResult(r)
 		switch r.Action {
 		case "created":
 			created++
-			fmt.Printf("  Created: %s → %s \"%s\"\n", r.BeanID, r.TaskURL, truncateTitle(r.BeanTitle, 20))
+			fmt.Printf("  Created: %s → %s \"%s\"%s\n", r.BeanID, r.TaskURL, truncateTitle(r.BeanTitle, 20), why)
 		case "updated":
 			updated++
-			fmt.Printf("  Updated: %s → %s \"%s\"\n", r.BeanID, r.TaskURL, truncateTitle(r.BeanTitle, 20))
+			fmt.Printf("  Updated: %s → %s \"%s\"%s\n", r.BeanID, r.TaskURL, truncateTitle(r.BeanTitle, 20), why)
 		case "unchanged":
 			unchanged++
+			if verbose {
+				fmt.Printf("  Unchanged: %s → %s \"%s\"%s\n", r.BeanID, r.TaskURL, truncateTitle(r.BeanTitle, 20), why)
+			}
 		case "skipped":
 			skipped++
+			if verbose {
+				fmt.Printf("  Skipped: %s - %s%s\n", r.BeanID, r.BeanTitle, why)
+			}
+		case "moved":
+			moved++
+			fmt.Printf("  Moved: %s → %s is in %s\n", r.BeanID, r.TaskURL, r.MovedTo)
 		case "would create":
-			fmt.Printf("  Would create: %s - %s\n", r.BeanID, r.BeanTitle)
+			fmt.Printf("  Would create: %s - %s%s\n", r.BeanID, r.BeanTitle, why)
 		case "would update":
-			fmt.Printf("  Would update: %s - %s\n", r.BeanID, r.BeanTitle)
+			fmt.Printf("  Would update: %s - %s%s\n", r.BeanID, r.BeanTitle, why)
+		case "cancelled":
+			cancelled++
+			if verbose {
+				fmt.Printf("  Cancelled: %s - %s\n", r.BeanID, r.BeanTitle)
+			}
 		case "error":
 			errors++
 			fmt.Printf("  Error: %s - %v\n", r.BeanID, r.Error)
 		}
+		switch r.Action {
+		case "created", "updated", "would create", "would update":
+			if r.Reason != "" {
+				reasons[r.Reason]++
+			}
+		}
+		if r.Conflict {
+			conflicts++
+		}
 	}
 
 	fmt.Printf("\nSummary: %d created, %d updated, %d unchanged, %d skipped, %d errors\n",
 		created, updated, unchanged, skipped, errors)
+	if len(reasons) > 0 {
+		var why []string
+		for _, reason := range slices.Sorted(maps.Keys(reasons)) {
+			why = append(why, fmt.Sprintf("%d %s", reasons[reason], reason))
+		}
+		fmt.Printf("Synced because: %s\n", strings.Join(why, ", "))
+	}
+	if conflicts > 0 {
+		_, _ = colorYellow.Printf("%d beans and their tasks both changed since the last sync; the tasks' edits were overwritten\n", conflicts)
+	}
+	if moved > 0 && (cfg == nil || cmp.Or(cfg.Beans.ClickUp.OnMove, syncer.OnMoveWarn) == syncer.OnMoveWarn) {
+		fmt.Printf("%d tasks moved to another list; set on_move to follow them or move them back\n", moved)
+	}
+	if cancelled > 0 {
+		_, _ = colorYellow.Printf("%d beans not synced before the interrupt; they sync on the next run\n", cancelled)
+	}
 	return nil
 }
+
+// explainResult says why a bean was synced or left alone, as a
+// parenthesized suffix for its result line, or "" if there's nothing to say.
+func explainResult(r syncer.Result) string {
+	var parts []string
+	if r.Reason != "" {
+		parts = append(parts, r.Reason)
+	}
+	if r.Conflict {
+		parts = append(parts, "conflict: task also edited")
+	}
+	if len(r.FieldsChanged) > 0 {
+		parts = append(parts, "changed "+strings.Join(r.FieldsChanged, ", "))
+	}
+	if r.Retried {
+		parts = append(parts, "retried")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, "; ") + ")"
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/clickup"
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+// errReviewAborted is returned when the user quits an interactive review.
+var errReviewAborted = errors.New("sync cancelled")
+
+// reviewAbortedError is errReviewAborted once earlier backends had already
+// synced in the same run.
+type reviewAbortedError struct {
+	synced []string // display names of the backends that synced
+}
+
+func (e *reviewAbortedError) Error() string {
+	return "sync cancelled after syncing to " + strings.Join(e.synced, ", ")
+}
+
+func (e *reviewAbortedError) Unwrap() error { return errReviewAborted }
+
+// review is the terminal an interactive sync asks about each bean on. One
+// is shared by every backend's review, so answers typed ahead aren't lost
+// in a discarded buffer between them.
+type review struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+const reviewHelp = `2, 1,3, 4-6 - toggle those beans on or off
+a - select every bean
+n - select no beans
+d 2 - expand or collapse a field-by-field diff of bean 2 with its task
+y - sync the selected beans
+q - quit without syncing anything more
+? - show this help
+`
+
+// taskLinks looks up the task a bean is linked to; syncer.StateProvider
+// satisfies it.
+type taskLinks interface {
+	GetTaskID(beanID string) *string
+}
+
+// reviewItem is one bean in the review list.
+type reviewItem struct {
+	bean     *beans.Bean
+	action   string // "create" or "update <task ID>"
+	selected bool
+	diff     string // rendered diff while expanded, else ""
+}
+
+// reviewBeans lists the beans that need syncing, all selected, and lets
+// the user toggle them and expand diffs until they apply the selection,
+// like git add -i. It returns the beans that were selected. showDiff
+// writes a bean's diff to w; it may be nil if diffs aren't available.
+func reviewBeans(reader *bufio.Reader, out io.Writer, beanList []beans.Bean, state taskLinks, showDiff func(w io.Writer, b *beans.Bean) error) ([]beans.Bean, error) {
+	items := make([]reviewItem, len(beanList))
+	for i := range beanList {
+		items[i] = reviewItem{bean: &beanList[i], action: "create", selected: true}
+		if taskID := state.GetTaskID(beanList[i].ID); taskID != nil && *taskID != "" {
+			items[i].action = "update " + *taskID
+		}
+	}
+
+	printReviewList(out, items)
+	for {
+		_, _ = colorCyan.Fprint(out, "Toggle beans, or sync the selection [1-n,a,n,d,y,q,?]? ")
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			// End of input: treat like quitting
+			return nil, errReviewAborted
+		}
+
+		cmd := strings.ToLower(strings.TrimSpace(line))
+		switch {
+		case cmd == "":
+		case cmd == "y":
+			var approved []beans.Bean
+			for _, it := range items {
+				if it.selected {
+					approved = append(approved, *it.bean)
+				}
+			}
+			return approved, nil
+		case cmd == "q":
+			return nil, errReviewAborted
+		case cmd == "a" || cmd == "n":
+			for i := range items {
+				items[i].selected = cmd == "a"
+			}
+		case strings.HasPrefix(cmd, "d"):
+			n, err := strconv.Atoi(strings.TrimSpace(cmd[1:]))
+			if err != nil || n < 1 || n > len(items) {
+				_, _ = fmt.Fprintf(out, "No bean %q; use d followed by a number from the list\n", strings.TrimSpace(cmd[1:]))
+				continue
+			}
+			toggleDiff(out, &items[n-1], showDiff)
+		default:
+			picks, err := parseReviewPicks(cmd, len(items))
+			if err != nil {
+				_, _ = fmt.Fprintf(out, "%v\n", err)
+				_, _ = fmt.Fprint(out, reviewHelp)
+				continue
+			}
+			for _, i := range picks {
+				items[i].selected = !items[i].selected
+			}
+		}
+		printReviewList(out, items)
+	}
+}
+
+// printReviewList writes the numbered review list with each bean's
+// selection, and the diff of each expanded bean below it.
+func printReviewList(out io.Writer, items []reviewItem) {
+	selected := 0
+	_, _ = fmt.Fprintln(out)
+	for i, it := range items {
+		mark := "[ ]"
+		if it.selected {
+			mark = "[x]"
+			selected++
+		}
+		_, _ = fmt.Fprintf(out, "%3d %s ", i+1, mark)
+		_, _ = colorBold.Fprintf(out, "%s %s", it.bean.ID, it.bean.Title)
+		_, _ = fmt.Fprintf(out, " (%s, %s)\n", it.bean.Status, it.action)
+		if it.diff != "" {
+			_, _ = fmt.Fprint(out, it.diff)
+		}
+	}
+	_, _ = fmt.Fprintf(out, "%d of %d selected\n", selected, len(items))
+}
+
+// toggleDiff expands an item's diff, rendering it with showDiff, or
+// collapses it if it is already expanded.
+func toggleDiff(out io.Writer, it *reviewItem, showDiff func(io.Writer, *beans.Bean) error) {
+	if it.diff != "" {
+		it.diff = ""
+		return
+	}
+	if showDiff == nil || !strings.HasPrefix(it.action, "update") {
+		_, _ = fmt.Fprintf(out, "%s has no linked task to diff against\n", it.bean.ID)
+		return
+	}
+	var buf strings.Builder
+	if err := showDiff(&buf, it.bean); err != nil {
+		_, _ = colorYellow.Fprintf(out, "Could not diff %s: %v\n", it.bean.ID, err)
+		return
+	}
+	if buf.Len() == 0 {
+		buf.WriteString("      no differences\n")
+	}
+	it.diff = buf.String()
+}
+
+// parseReviewPicks parses a comma- or space-separated list of bean numbers
+// and ranges such as "1,3-5" into zero-based indexes below n.
+func parseReviewPicks(s string, n int) ([]int, error) {
+	var picks []int
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		lo, hi, isRange := strings.Cut(field, "-")
+		from, err := strconv.Atoi(lo)
+		to := from
+		if err == nil && isRange {
+			to, err = strconv.Atoi(hi)
+		}
+		if err != nil || from < 1 || to > n || from > to {
+			return nil, fmt.Errorf("%q is not a bean number from 1 to %d", field, n)
+		}
+		for i := from; i <= to; i++ {
+			picks = append(picks, i-1)
+		}
+	}
+	return picks, nil
+}
+
+// sinkDiffer returns a function that writes a bean's diff against its task
+// for sinks that support it, or nil.
+func sinkDiffer(ctx context.Context, sink syncer.Sink, state syncer.StateProvider) func(io.Writer, *beans.Bean) error {
+	cs, ok := sink.(*clickup.Sink)
+	if !ok {
+		return nil
+	}
+	return func(out io.Writer, b *beans.Bean) error {
+		ref, err := sink.GetTask(ctx, *state.GetTaskID(b.ID))
+		if err != nil {
+			return err
+		}
+		task, ok := ref.Remote.(*clickup.TaskInfo)
+		if !ok {
+			return fmt.Errorf("unexpected task type %T", ref.Remote)
+		}
+		for _, f := range cs.Diff(b, task) {
+			printFieldDiff(out, f)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/beans"
+)
+
+// reviewState is a StateProvider with fixed task links.
+type reviewState map[string]string
+
+func (s reviewState) GetTaskID(beanID string) *string {
+	if id, ok := s[beanID]; ok {
+		return &id
+	}
+	return nil
+}
+
+func TestReviewBeans(t *testing.T) {
+	beanList := []beans.Bean{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}}
+	state := reviewState{"b": "task-b"}
+
+	ids := func(list []beans.Bean) string {
+		var out []string
+		for _, b := range list {
+			out = append(out, b.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{"apply all", "y\n", "a,b,c,d", nil},
+		{"toggle some off", "2,4\ny\n", "a,c", nil},
+		{"toggle back on", "2-4\n3\n?\ny\n", "a,c", nil},
+		{"none then pick", "n\n2\ny\n", "b", nil},
+		{"bad pick is ignored", "7\nx\ny\n", "a,b,c,d", nil},
+		{"expand diff then choose", "d 2\nn\n2\ny\n", "b", nil},
+		{"quit", "2\nq\n", "", errReviewAborted},
+		{"end of input", "2\n", "", errReviewAborted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diffed := ""
+			showDiff := func(w io.Writer, b *beans.Bean) error {
+				diffed = b.ID
+				_, err := io.WriteString(w, "      title changed\n")
+				return err
+			}
+			var out strings.Builder
+			got, err := reviewBeans(bufio.NewReader(strings.NewReader(tt.input)), &out, beanList, state, showDiff)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("reviewBeans() error = %v, want %v", err, tt.wantErr)
+			}
+			if ids(got) != tt.want {
+				t.Errorf("reviewBeans() = %q, want %q", ids(got), tt.want)
+			}
+			if strings.Contains(tt.input, "d 2") && (diffed != "b" || !strings.Contains(out.String(), "title changed")) {
+				t.Errorf("diff shown for %q, want b expanded in the list", diffed)
+			}
+		})
+	}
+}
+
+func TestReviewBeans_CollapseDiff(t *testing.T) {
+	beanList := []beans.Bean{{ID: "a"}}
+	calls := 0
+	showDiff := func(w io.Writer, b *beans.Bean) error {
+		calls++
+		_, err := io.WriteString(w, "      DIFF\n")
+		return err
+	}
+	var out strings.Builder
+	if _, err := reviewBeans(bufio.NewReader(strings.NewReader("d 1\n\nd 1\ny\n")), &out, beanList, reviewState{"a": "t"}, showDiff); err != nil {
+		t.Fatal(err)
+	}
+	// Shown while expanded (twice: after d and after the redraw), then gone
+	if n := strings.Count(out.String(), "DIFF"); n != 2 || calls != 1 {
+		t.Errorf("diff printed %d times from %d fetches, want 2 from 1", n, calls)
+	}
+}
+
+func TestSyncToSink_SharedReview(t *testing.T) {
+	beans.NoCLI = true
+	defer func() { beans.NoCLI = false }()
+	dir := t.TempDir()
+	beansPath = dir
+	defer func() { beansPath = "" }()
+	syncForce = true
+	defer func() { syncForce = false }()
+
+	for _, id := range []string{"bup-a", "bup-b"} {
+		content := "---\ntitle: " + id + "\nstatus: todo\nextensions:\n    fake:\n        task_id: t-" + id + "\n---\n"
+		if err := os.WriteFile(filepath.Join(dir, id+"--x.md"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	beansClient := beans.NewClient(dir)
+	beanList, err := beansClient.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Answers for both backends are typed ahead; the second review must
+	// still see its own
+	var out strings.Builder
+	rev := &review{in: bufio.NewReader(strings.NewReader("y\nn\ny\n")), out: &out}
+	sink := &listingSink{}
+	if _, _, err := syncToSink(context.Background(), sink, beansClient, beanList, true, rev); err != nil {
+		t.Fatal(err)
+	}
+	_, message, err := syncToSink(context.Background(), sink, beansClient, beanList, true, rev)
+	if err != nil || message != "No beans selected" {
+		t.Fatalf("second review: %q, %v; want no beans selected", message, err)
+	}
+	if len(sink.updated) != 2 {
+		t.Errorf("updated = %v, want both beans from the first review", sink.updated)
+	}
+	if n := strings.Count(out.String(), "2 beans need syncing to"); n != 2 {
+		t.Errorf("review output:\n%s", out.String())
+	}
+}
+
+func TestReviewAbortedError(t *testing.T) {
+	err := error(&reviewAbortedError{synced: []string{"ClickUp", "GitHub"}})
+	if !errors.Is(err, errReviewAborted) || err.Error() != "sync cancelled after syncing to ClickUp, GitHub" {
+		t.Errorf("error = %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+func TestExplainResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		result syncer.Result
+		want   string
+	}{
+		{"nothing to say", syncer.Result{Action: "updated"}, ""},
+		{"reason only", syncer.Result{Action: "skipped", Reason: syncer.ReasonUpToDate}, " (up to date)"},
+		{
+			"everything",
+			syncer.Result{Action: "updated", Reason: syncer.ReasonBeanChanged, Conflict: true, FieldsChanged: []string{"status", "tags"}, Retried: true},
+			" (bean changed; conflict: task also edited; changed status, tags; retried)",
+		},
+	}
+	for _, tt := range tests {
+		if got := explainResult(tt.result); got != tt.want {
+			t.Errorf("%s: explainResult() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestJSONResult_Reason(t *testing.T) {
+	data, err := json.Marshal(newJSONResult(syncer.Result{
+		BeanID: "bean-1", Action: "updated", Reason: syncer.ReasonTaskEdited, FieldsChanged: []string{"status"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{`"reason":"task edited remotely"`, `"fields_changed":["status"]`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("JSON %s is missing %s", got, want)
+		}
+	}
+	// Unset flags are left out, so existing consumers see no new noise
+	if strings.Contains(got, "retried") || strings.Contains(got, "conflict") {
+		t.Errorf("JSON %s has unset flags", got)
+	}
+}
+
+// listingSink is a syncer.TaskLister whose one task was edited remotely.
+type listingSink struct {
+	mu      sync.Mutex
+	updated []string
+	edited  time.Time
+}
+
+func (l *listingSink) Name() string                      { return "fake" }
+func (l *listingSink) Prepare(ctx context.Context) error { return nil }
+func (l *listingSink) GetTask(ctx context.Context, taskID string) (*syncer.TaskRef, error) {
+	return &syncer.TaskRef{ID: taskID, UpdatedAt: &l.edited}, nil
+}
+func (l *listingSink) CreateTask(ctx context.Context, b *beans.Bean, parentTaskID string) (*syncer.TaskRef, error) {
+	return &syncer.TaskRef{ID: "new"}, nil
+}
+func (l *listingSink) UpdateTask(ctx context.Context, current *syncer.TaskRef, b *beans.Bean) (*syncer.TaskRef, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.updated = append(l.updated, b.ID)
+	return current, true, nil
+}
+func (l *listingSink) SyncTags(ctx context.Context, task *syncer.TaskRef, b *beans.Bean) bool {
+	return false
+}
+func (l *listingSink) SetRelationship(ctx context.Context, blockerTaskID, blockedTaskID string) error {
+	return nil
+}
+func (l *listingSink) ListTasks(ctx context.Context) (map[string]*syncer.TaskRef, error) {
+	return map[string]*syncer.TaskRef{
+		"t-drifted": {ID: "t-drifted", UpdatedAt: &l.edited},
+		"t-same":    {ID: "t-same"},
+	}, nil
+}
+func (l *listingSink) TagFingerprints(task *syncer.TaskRef, b *beans.Bean) (string, string) {
+	return "", ""
+}
+
+func TestSyncToSink_RemoteDrift(t *testing.T) {
+	beans.NoCLI = true
+	defer func() { beans.NoCLI = false }()
+	dir := t.TempDir()
+	beansPath = dir
+	defer func() { beansPath = "" }()
+
+	// Both beans are unchanged since their last sync; only one task was edited
+	for id, taskID := range map[string]string{"bup-drift": "t-drifted", "bup-same": "t-same"} {
+		content := "---\ntitle: " + id + "\nstatus: todo\nupdated_at: 2026-03-01T10:00:00Z\nextensions:\n    fake:\n        task_id: " +
+			taskID + "\n        synced_at: \"2026-03-01T11:00:00Z\"\n---\n"
+		if err := os.WriteFile(filepath.Join(dir, id+"--x.md"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	beansClient := beans.NewClient(dir)
+	beanList, err := beansClient.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sink := &listingSink{edited: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)}
+	results, _, err := syncToSink(context.Background(), sink, beansClient, beanList, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].BeanID != "bup-drift" || results[0].Reason != syncer.ReasonTaskEdited {
+		t.Errorf("results = %+v, want only the drifted bean", results)
+	}
+	if len(sink.updated) != 1 || sink.updated[0] != "bup-drift" {
+		t.Errorf("updated = %v, want the drifted bean re-pushed", sink.updated)
+	}
+}
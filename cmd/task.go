@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/clickup"
+)
+
+var taskCmd = &cobra.Command{
+	Use:   "task <bean-id>",
+	Short: "Show the ClickUp task linked to a bean",
+	Long: `Fetches the ClickUp task linked to a bean and prints a summary including
+custom fields, assignees, watchers, and dependencies, for debugging
+mapping issues without calling the API by hand.
+
+Use --json to print the task exactly as the ClickUp API returned it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		beansClient := beans.NewClient(getBeansPath())
+		bean, err := beansClient.Get(args[0])
+		if err != nil {
+			return fmt.Errorf("bean not found: %s", args[0])
+		}
+		taskID := bean.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID)
+		if taskID == "" {
+			return fmt.Errorf("%s is not linked to a ClickUp task (run beanup sync or beanup link)", bean.ID)
+		}
+		if clickup.IsListRef(taskID) {
+			return fmt.Errorf("%s is an epic synced as a ClickUp list, not a task", bean.ID)
+		}
+
+		token, err := getClickUpToken()
+		if err != nil {
+			return err
+		}
+		raw, err := newClickUpClient(ctx, token).GetTaskRaw(ctx, taskID)
+		if errors.Is(err, clickup.ErrTaskNotFound) {
+			return fmt.Errorf("task %s linked to %s no longer exists in ClickUp", taskID, bean.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("fetching task: %w", err)
+		}
+
+		if jsonOut {
+			var v any
+			if err := json.Unmarshal(raw, &v); err != nil {
+				_, err = os.Stdout.Write(raw)
+				return err
+			}
+			return outputJSON(v)
+		}
+
+		var task taskDetail
+		if err := json.Unmarshal(raw, &task); err != nil {
+			return fmt.Errorf("decoding task: %w", err)
+		}
+		printTaskDetail(bean.ID, &task)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(taskCmd)
+}
+
+// taskDetail is the part of the ClickUp task JSON shown by the task command.
+type taskDetail struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Status struct {
+		Status string `json:"status"`
+	} `json:"status"`
+	Priority *struct {
+		Priority string `json:"priority"`
+	} `json:"priority"`
+	DueDate      string `json:"due_date"`
+	DateCreated  string `json:"date_created"`
+	DateUpdated  string `json:"date_updated"`
+	Parent       string `json:"parent"`
+	CustomItemID *int   `json:"custom_item_id"`
+	List         struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"list"`
+	Creator      taskUser         `json:"creator"`
+	Assignees    []taskUser       `json:"assignees"`
+	Watchers     []taskUser       `json:"watchers"`
+	Tags         []clickup.Tag    `json:"tags"`
+	CustomFields []taskFieldValue `json:"custom_fields"`
+	Dependencies []struct {
+		TaskID    string `json:"task_id"`
+		DependsOn string `json:"depends_on"`
+	} `json:"dependencies"`
+}
+
+type taskUser struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+func (u taskUser) String() string {
+	name := u.Username
+	if name == "" {
+		name = u.Email
+	}
+	return fmt.Sprintf("%s (%d)", name, u.ID)
+}
+
+type taskFieldValue struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+}
+
+func printTaskDetail(beanID string, t *taskDetail) {
+	_, _ = colorBold.Printf("%s\n", t.Name)
+	fmt.Printf("  ID:        %s (linked to %s)\n", t.ID, beanID)
+	fmt.Printf("  URL:       %s\n", t.URL)
+	fmt.Printf("  List:      %s (%s)\n", t.List.Name, t.List.ID)
+	fmt.Printf("  Status:    %s\n", t.Status.Status)
+	if t.Priority != nil {
+		fmt.Printf("  Priority:  %s\n", t.Priority.Priority)
+	}
+	if t.CustomItemID != nil {
+		fmt.Printf("  Type ID:   %d\n", *t.CustomItemID)
+	}
+	if t.DueDate != "" {
+		fmt.Printf("  Due:       %s\n", formatMillis(t.DueDate, "2006-01-02"))
+	}
+	if t.Parent != "" {
+		fmt.Printf("  Parent:    %s\n", t.Parent)
+	}
+	fmt.Printf("  Created:   %s by %s\n", formatMillis(t.DateCreated, time.DateTime), t.Creator)
+	fmt.Printf("  Updated:   %s\n", formatMillis(t.DateUpdated, time.DateTime))
+
+	if len(t.Tags) > 0 {
+		fmt.Print("  Tags:     ")
+		for _, tag := range t.Tags {
+			fmt.Printf(" %s", tag.Name)
+		}
+		fmt.Println()
+	}
+
+	printUsers := func(label string, users []taskUser) {
+		if len(users) == 0 {
+			return
+		}
+		fmt.Printf("\n%s:\n", label)
+		for _, u := range users {
+			fmt.Printf("  %s\n", u)
+		}
+	}
+	printUsers("Assignees", t.Assignees)
+	printUsers("Watchers", t.Watchers)
+
+	if len(t.Dependencies) > 0 {
+		fmt.Println("\nDependencies:")
+		for _, d := range t.Dependencies {
+			if d.TaskID == t.ID {
+				fmt.Printf("  waiting on %s\n", d.DependsOn)
+			} else {
+				fmt.Printf("  blocking %s\n", d.TaskID)
+			}
+		}
+	}
+
+	if len(t.CustomFields) > 0 {
+		fmt.Println("\nCustom fields:")
+		for _, f := range t.CustomFields {
+			value := "(empty)"
+			if f.Value != nil {
+				data, _ := json.Marshal(f.Value)
+				value = string(data)
+			}
+			fmt.Printf("  %s [%s, %s]: %s\n", f.Name, f.Type, f.ID, value)
+		}
+	}
+}
+
+// formatMillis formats a ClickUp Unix-milliseconds string as a local time.
+func formatMillis(s, layout string) string {
+	millis, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return s
+	}
+	return time.UnixMilli(millis).Local().Format(layout)
+}
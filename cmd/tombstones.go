@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/syncer"
+	"github.com/toba/bean-me-up/internal/tombstone"
+)
+
+// tombstonePath is the deleted-bean store in the beans directory.
+func tombstonePath() string {
+	return filepath.Join(getBeansPath(), tombstone.FileName)
+}
+
+// buryDeletedBeans runs after a full sync. It remembers the task linked to
+// each listed bean, buries the beans that have disappeared since, and
+// applies each sink's on_delete setting to their tasks. results are the
+// sync's results by sink, for links made in this sync. Tasks that can't
+// be handled yet stay pending for the next sync.
+func buryDeletedBeans(ctx context.Context, sinks []syncer.Sink, beansClient *beans.Client, beanList []beans.Bean, results map[string][]syncer.Result, quiet bool) error {
+	// The listing may leave out filtered statuses, so deletion is checked
+	// against every bean ID
+	ids, err := beansClient.ListIDs()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: checking for deleted beans: %v\n", err)
+		return nil
+	}
+	if len(ids) == 0 {
+		return nil // more likely the wrong beans path than every bean deleted
+	}
+	slices.Sort(ids)
+	exists := func(id string) bool {
+		_, found := slices.BinarySearch(ids, id)
+		return found
+	}
+
+	store, err := tombstone.Load(tombstonePath())
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, sink := range sinks {
+		seen := make(map[string]string, len(beanList))
+		for _, b := range beanList {
+			seen[b.ID] = b.GetExtensionString(sink.Name(), beans.ExtKeyTaskID)
+		}
+		for _, r := range results[sink.Name()] {
+			if r.TaskID != "" {
+				seen[r.BeanID] = r.TaskID
+			}
+		}
+		buried := store.Observe(sink.Name(), seen, exists, now)
+
+		remover, ok := sink.(syncer.TaskRemover)
+		if !ok {
+			for _, t := range buried {
+				fmt.Fprintf(os.Stderr, "Warning: bean %s was deleted; %s can't remove its task %s\n", t.BeanID, sinkDisplayName(t.Sink), t.TaskID)
+			}
+			continue
+		}
+		for _, t := range store.Pending(sink.Name()) {
+			action, err := remover.RemoveTask(ctx, t.TaskID)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: removing task %s of deleted bean %s: %v\n", t.TaskID, t.BeanID, err)
+				continue
+			}
+			if action == "" {
+				if slices.ContainsFunc(buried, func(b tombstone.Tombstone) bool { return b.BeanID == t.BeanID }) && !quiet {
+					fmt.Printf("Bean %s was deleted; its %s task %s was left as is (set on_delete to handle it)\n", t.BeanID, sinkDisplayName(t.Sink), t.TaskID)
+				}
+				continue
+			}
+			t.Action, t.Handled = action, &now
+			if !quiet {
+				fmt.Printf("Bean %s was deleted; applied %s to its %s task %s\n", t.BeanID, action, sinkDisplayName(t.Sink), t.TaskID)
+			}
+		}
+	}
+	return store.Save(tombstonePath())
+}
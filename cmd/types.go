@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/toba/bean-me-up/internal/beans"
@@ -14,12 +13,15 @@ var typesCmd = &cobra.Command{
 	Short: "List available custom task types",
 	Long: `Lists all custom task types (e.g., Bug, Milestone) available in your ClickUp workspaces.
 
+With several workspaces, pass --team or set extensions.clickup.team_id to
+list only one workspace's types.
+
 Use this command to find task type IDs for configuring type_mapping
 in your .beans.clickup.yml configuration.
 
 Requires CLICKUP_TOKEN environment variable to be set.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		ctx := context.Background()
+		ctx := cmd.Context()
 
 		// Get ClickUp token
 		token, err := getClickUpToken()
@@ -28,10 +30,10 @@ Requires CLICKUP_TOKEN environment variable to be set.`,
 		}
 
 		// Create client
-		client := clickup.NewClient(token)
+		client := newClickUpClient(ctx, token)
 
 		// Fetch custom items
-		items, err := client.GetCustomItems(ctx)
+		items, err := getCustomItems(ctx, client)
 		if err != nil {
 			return fmt.Errorf("fetching custom task types: %w", err)
 		}
@@ -1,21 +1,45 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
 
 	"github.com/toba/bean-me-up/internal/beans"
 	"github.com/spf13/cobra"
 )
 
+var (
+	unlinkAll    bool
+	unlinkStatus []string
+	unlinkTags   []string
+	unlinkYes    bool
+)
+
 var unlinkCmd = &cobra.Command{
-	Use:   "unlink <bean-id>",
-	Short: "Remove the link between a bean and its ClickUp task",
+	Use:   "unlink [bean-id...]",
+	Short: "Remove the link between beans and their ClickUp tasks",
 	Long: `Removes the ClickUp sync metadata from a bean's extension data,
 unlinking it from its associated ClickUp task.
 
-Note: This does not delete or modify the ClickUp task itself.`,
-	Args: cobra.ExactArgs(1),
+Several beans can be unlinked at once by ID, with --all, or by filter:
+
+  beanup unlink --status scrapped
+  beanup unlink --all --tag archived
+
+--status and --tag narrow the given IDs, or all linked beans if no IDs
+are given. Unlinking more than one bean asks for confirmation unless
+--yes is passed.
+
+Note: This does not delete or modify the ClickUp tasks themselves.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		bulk := len(args) != 1 || unlinkAll || len(unlinkStatus) > 0 || len(unlinkTags) > 0
+		if bulk {
+			return unlinkBeans(args)
+		}
 		beanID := args[0]
 
 		// Get the bean
@@ -50,9 +74,115 @@ Note: This does not delete or modify the ClickUp task itself.`,
 }
 
 func init() {
+	unlinkCmd.Flags().BoolVar(&unlinkAll, "all", false, "unlink every linked bean")
+	unlinkCmd.Flags().StringSliceVar(&unlinkStatus, "status", nil, "only unlink beans with these statuses (repeatable or comma-separated)")
+	unlinkCmd.Flags().StringSliceVar(&unlinkTags, "tag", nil, "only unlink beans with any of these tags (repeatable or comma-separated)")
+	unlinkCmd.Flags().BoolVarP(&unlinkYes, "yes", "y", false, "don't ask for confirmation")
 	rootCmd.AddCommand(unlinkCmd)
 }
 
+// unlinkBeans unlinks the linked beans among ids (or all beans) that match
+// the --status and --tag filters, after confirmation.
+func unlinkBeans(ids []string) error {
+	if len(ids) == 0 && !unlinkAll && len(unlinkStatus) == 0 && len(unlinkTags) == 0 {
+		return fmt.Errorf("specify bean IDs, --all, or a --status or --tag filter")
+	}
+
+	beansClient := beans.NewClient(getBeansPath())
+	var (
+		candidates []beans.Bean
+		err        error
+	)
+	if len(ids) > 0 {
+		candidates, err = beansClient.GetMultiple(ids)
+		if err != nil {
+			return fmt.Errorf("getting beans: %w", err)
+		}
+	} else {
+		candidates, err = beansClient.List()
+		if err != nil {
+			return fmt.Errorf("listing beans: %w", err)
+		}
+	}
+
+	selected := filterUnlink(candidates, unlinkStatus, unlinkTags)
+	if len(selected) == 0 {
+		if jsonOut {
+			return outputJSON([]map[string]string{})
+		}
+		fmt.Println("No linked beans match.")
+		return nil
+	}
+
+	if len(selected) > 1 && !unlinkYes {
+		// Keep stdout clean for --json
+		for _, b := range selected {
+			fmt.Fprintf(os.Stderr, "  %s → %s  %s\n", b.ID, b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID), b.Title)
+		}
+		ok, err := confirm(os.Stdin, os.Stderr, fmt.Sprintf("Unlink %d beans?", len(selected)))
+		if err != nil {
+			return fmt.Errorf("%w (pass --yes to skip confirmation)", err)
+		}
+		if !ok {
+			return fmt.Errorf("unlink cancelled")
+		}
+	}
+
+	results := make([]map[string]string, 0, len(selected))
+	for _, b := range selected {
+		taskID := b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID)
+		if err := beansClient.RemoveExtensionData(b.ID, beans.PluginClickUp); err != nil {
+			return fmt.Errorf("removing sync state for %s: %w", b.ID, err)
+		}
+		results = append(results, map[string]string{
+			"bean_id":    b.ID,
+			"bean_title": b.Title,
+			"task_id":    taskID,
+			"action":     "unlinked",
+		})
+		if !jsonOut {
+			fmt.Printf("Unlinked: %s (was %s)\n", b.ID, taskID)
+		}
+	}
+
+	if jsonOut {
+		return outputJSON(results)
+	}
+	return nil
+}
+
+// filterUnlink returns the linked beans matching any of statuses (if set)
+// and any of tags (if set).
+func filterUnlink(candidates []beans.Bean, statuses, tags []string) []beans.Bean {
+	var selected []beans.Bean
+	for _, b := range candidates {
+		if b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID) == "" {
+			continue
+		}
+		if len(statuses) > 0 && !slices.Contains(statuses, b.Status) {
+			continue
+		}
+		if len(tags) > 0 && !slices.ContainsFunc(tags, func(t string) bool { return slices.Contains(b.Tags, t) }) {
+			continue
+		}
+		selected = append(selected, b)
+	}
+	return selected
+}
+
+// confirm asks a yes/no question, defaulting to no. It fails if in has no
+// answer, e.g. when stdin is not a terminal.
+func confirm(in io.Reader, out io.Writer, question string) (bool, error) {
+	_, _ = colorCyan.Fprintf(out, "%s [y/N] ", question)
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && line == "" {
+		_, _ = fmt.Fprintln(out)
+		return false, fmt.Errorf("no answer to confirmation prompt")
+	}
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes", nil
+}
+
 func outputUnlinkJSON(bean *beans.Bean, taskID, action string) error {
 	result := map[string]string{
 		"bean_id":    bean.ID,
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/beans"
+)
+
+func TestFilterUnlink(t *testing.T) {
+	linked := func(id, status string, tags ...string) beans.Bean {
+		return beans.Bean{ID: id, Status: status, Tags: tags, Extensions: map[string]map[string]any{
+			beans.PluginClickUp: {beans.ExtKeyTaskID: "t-" + id},
+		}}
+	}
+	candidates := []beans.Bean{
+		linked("a", "scrapped"),
+		linked("b", "todo", "archived"),
+		linked("c", "scrapped", "archived"),
+		{ID: "d", Status: "scrapped"},
+	}
+
+	ids := func(bs []beans.Bean) string {
+		var out []string
+		for _, b := range bs {
+			out = append(out, b.ID)
+		}
+		return strings.Join(out, ",")
+	}
+	tests := []struct {
+		name     string
+		statuses []string
+		tags     []string
+		want     string
+	}{
+		{"all linked", nil, nil, "a,b,c"},
+		{"by status", []string{"scrapped"}, nil, "a,c"},
+		{"by tag", nil, []string{"archived"}, "b,c"},
+		{"status and tag", []string{"scrapped"}, []string{"archived"}, "c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ids(filterUnlink(candidates, tt.statuses, tt.tags)); got != tt.want {
+				t.Errorf("filterUnlink() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	for input, want := range map[string]bool{"y\n": true, "YES\n": true, "n\n": false, "\n": false} {
+		got, err := confirm(strings.NewReader(input), io.Discard, "Unlink?")
+		if err != nil || got != want {
+			t.Errorf("confirm(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	if _, err := confirm(strings.NewReader(""), io.Discard, "Unlink?"); err == nil {
+		t.Error("expected error with no input")
+	}
+}
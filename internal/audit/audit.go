@@ -0,0 +1,93 @@
+// Package audit keeps an append-only history of sync errors and pulled
+// fields in the beans directory, so they can still be reported after the
+// run that produced them.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// FileName is the log created inside the beans directory.
+const FileName = ".beanup-audit.jsonl"
+
+// Kinds of entries in the log.
+const (
+	KindSyncError = "sync_error"
+	KindPull      = "pull" // a field copied from a task into its bean
+)
+
+// Entry is one logged event.
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`
+	Sink   string    `json:"sink"`
+	BeanID string    `json:"bean_id"`
+	TaskID string    `json:"task_id,omitempty"`
+	Error  string    `json:"error,omitempty"`
+	// Field, From, and To describe a pulled field; By is the remote user
+	// who changed it, if the task's history names them.
+	Field string `json:"field,omitempty"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+	By    string `json:"by,omitempty"`
+}
+
+// Append adds entries to the end of the log at path, creating it if needed.
+func Append(path string, entries ...Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("writing audit log: %w", err)
+	}
+	enc := json.NewEncoder(f)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("writing audit log: %w", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing audit log: %w", err)
+	}
+	return nil
+}
+
+// Load reads the entries of kind logged at or after since, oldest first.
+// A missing log is empty.
+func Load(path, kind string, since time.Time) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading audit log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if e.Kind == kind && !e.Time.Before(since) {
+			entries = append(entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading audit log: %w", err)
+	}
+	return entries, nil
+}
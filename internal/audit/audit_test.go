@@ -0,0 +1,42 @@
+package audit
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestAppendLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+
+	entries, err := Load(path, KindSyncError, time.Time{})
+	if err != nil || entries != nil {
+		t.Fatalf("Load() of missing log = %v, %v", entries, err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	old := Entry{Time: now.Add(-48 * time.Hour), Kind: KindSyncError, Sink: "clickup", BeanID: "b1", Error: "boom"}
+	recent := Entry{Time: now, Kind: KindSyncError, Sink: "github", BeanID: "b2", TaskID: "7", Error: "422"}
+	if err := Append(path, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := Append(path, recent, Entry{Time: now, Kind: "other", BeanID: "b3"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Load(path, KindSyncError, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Entry{old, recent}; !slices.Equal(got, want) {
+		t.Errorf("Load() = %v, want %v", got, want)
+	}
+	got, err = Load(path, KindSyncError, now.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Entry{recent}; !slices.Equal(got, want) {
+		t.Errorf("Load(since) = %v, want %v", got, want)
+	}
+}
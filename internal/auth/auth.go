@@ -0,0 +1,128 @@
+// Package auth stores OAuth credentials and runs the browser-based
+// authorization code flow used by `beanup auth login`.
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ServiceClickUp is the credentials key for ClickUp.
+const ServiceClickUp = "clickup"
+
+// Credentials is a stored OAuth token for one service.
+type Credentials struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// Expiry is zero for tokens that don't expire (ClickUp's currently don't).
+	Expiry time.Time `json:"expiry,omitzero"`
+	// The OAuth app the token was issued to, kept for refreshing.
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+// Expired reports whether the access token has expired or will within a minute.
+func (c *Credentials) Expired() bool {
+	return !c.Expiry.IsZero() && time.Now().Add(time.Minute).After(c.Expiry)
+}
+
+// Path returns the credentials file: beanup/credentials.json in the user
+// config directory (e.g. ~/.config on Linux).
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("finding user config directory: %w", err)
+	}
+	return filepath.Join(dir, "beanup", "credentials.json"), nil
+}
+
+// Load returns the stored credentials for service, or nil if there are none.
+func Load(service string) (*Credentials, error) {
+	all, err := readAll()
+	if err != nil {
+		return nil, err
+	}
+	return all[service], nil
+}
+
+// Save stores credentials for service. The file is readable only by the user.
+func Save(service string, creds *Credentials) error {
+	all, err := readAll()
+	if err != nil {
+		return err
+	}
+	all[service] = creds
+	return writeAll(all)
+}
+
+// Delete removes the stored credentials for service. It reports whether any existed.
+func Delete(service string) (bool, error) {
+	all, err := readAll()
+	if err != nil {
+		return false, err
+	}
+	if _, ok := all[service]; !ok {
+		return false, nil
+	}
+	delete(all, service)
+	return true, writeAll(all)
+}
+
+func readAll() (map[string]*Credentials, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	all := make(map[string]*Credentials)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return all, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading credentials: %w", err)
+	}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return all, nil
+}
+
+func writeAll(all map[string]*Credentials) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("creating credentials directory: %w", err)
+	}
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a private temp file and rename so a crash can't leave a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".credentials-*.json")
+	if err != nil {
+		return fmt.Errorf("writing credentials: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing credentials: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing credentials: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing credentials: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("writing credentials: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/config"
+)
+
+// useTempConfigDir points os.UserConfigDir at a temporary directory.
+func useTempConfigDir(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("AppData", dir)
+}
+
+func TestSaveLoadDelete(t *testing.T) {
+	useTempConfigDir(t)
+
+	if creds, err := Load(ServiceClickUp); err != nil || creds != nil {
+		t.Fatalf("Load() with no file = %v, %v; want nil, nil", creds, err)
+	}
+
+	if err := Save(ServiceClickUp, &Credentials{AccessToken: "tok", ClientID: "app"}); err != nil {
+		t.Fatal(err)
+	}
+	path, _ := Path()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("credentials file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	creds, err := Load(ServiceClickUp)
+	if err != nil || creds == nil || creds.AccessToken != "tok" {
+		t.Fatalf("Load() = %+v, %v", creds, err)
+	}
+
+	if removed, err := Delete(ServiceClickUp); err != nil || !removed {
+		t.Fatalf("Delete() = %v, %v", removed, err)
+	}
+	if removed, _ := Delete(ServiceClickUp); removed {
+		t.Error("second Delete() should report nothing removed")
+	}
+}
+
+func TestFlowLogin(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("code") != "the-code" || q.Get("client_secret") != "shh" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"err":"Code not found","ECODE":"OAUTH_014"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"oauth-token"}`))
+	}))
+	defer tokenServer.Close()
+
+	flow := &Flow{
+		Endpoint:     Endpoint{AuthURL: "https://example.com/authorize", TokenURL: tokenServer.URL},
+		ClientID:     "app",
+		ClientSecret: "shh",
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Play the browser: follow the redirect with the code and the state we were given
+	creds, err := flow.Login(ctx, func(authURL string) {
+		u, _ := url.Parse(authURL)
+		q := u.Query()
+		redirect := q.Get("redirect_uri") + "?code=the-code&state=" + url.QueryEscape(q.Get("state"))
+		go func() {
+			resp, err := http.Get(redirect)
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.AccessToken != "oauth-token" || creds.ClientID != "app" || !creds.Expiry.IsZero() {
+		t.Errorf("creds = %+v", creds)
+	}
+}
+
+func TestFlowLogin_RejectsWrongState(t *testing.T) {
+	flow := &Flow{Endpoint: Endpoint{AuthURL: "https://example.com/authorize", TokenURL: "http://127.0.0.1:1"}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := flow.Login(ctx, func(authURL string) {
+		u, _ := url.Parse(authURL)
+		go func() {
+			resp, err := http.Get(u.Query().Get("redirect_uri") + "?code=x&state=forged")
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+	})
+	if err == nil {
+		t.Fatal("expected error for mismatched state")
+	}
+}
+
+func TestClickUpToken(t *testing.T) {
+	useTempConfigDir(t)
+
+	t.Setenv("CLICKUP_TOKEN", "")
+	if _, err := ClickUpToken(context.Background(), config.TokenSource{}); err == nil {
+		t.Error("expected error with no env token and no login")
+	}
+
+	if err := Save(ServiceClickUp, &Credentials{AccessToken: "stored"}); err != nil {
+		t.Fatal(err)
+	}
+	if token, err := ClickUpToken(context.Background(), config.TokenSource{}); err != nil || token != "stored" {
+		t.Errorf("ClickUpToken() = %q, %v; want stored login", token, err)
+	}
+
+	t.Setenv("CLICKUP_TOKEN", "pk_env")
+	if token, _ := ClickUpToken(context.Background(), config.TokenSource{}); token != "pk_env" {
+		t.Errorf("ClickUpToken() = %q; CLICKUP_TOKEN should take precedence", token)
+	}
+}
+
+func TestToken(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("TEST_TOKEN", "")
+
+	if _, err := Token(ctx, "TEST_TOKEN", "github", config.TokenSource{}); err == nil || !strings.Contains(err.Error(), "extensions.github") {
+		t.Errorf("Token() with nothing configured: err = %v", err)
+	}
+
+	file := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(file, []byte("from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if token, err := Token(ctx, "TEST_TOKEN", "github", config.TokenSource{TokenFile: file}); err != nil || token != "from-file" {
+		t.Errorf("Token() from file = %q, %v", token, err)
+	}
+
+	if runtime.GOOS != "windows" {
+		src := config.TokenSource{TokenCommand: "echo from-command", TokenFile: file}
+		if token, err := Token(ctx, "TEST_TOKEN", "github", src); err != nil || token != "from-command" {
+			t.Errorf("Token() from command = %q, %v; command should win over file", token, err)
+		}
+		_, err := Token(ctx, "TEST_TOKEN", "github", config.TokenSource{TokenCommand: "echo locked >&2; exit 1"})
+		if err == nil || !strings.Contains(err.Error(), "locked") {
+			t.Errorf("failing command: err = %v, want stderr in message", err)
+		}
+	}
+
+	t.Setenv("TEST_TOKEN", "from-env")
+	if token, _ := Token(ctx, "TEST_TOKEN", "github", config.TokenSource{TokenFile: file}); token != "from-env" {
+		t.Errorf("Token() = %q; environment should take precedence", token)
+	}
+}
+
+func TestToken_CommandRunsOnce(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	t.Setenv("TEST_TOKEN", "")
+	count := filepath.Join(t.TempDir(), "count")
+	src := config.TokenSource{TokenCommand: "echo x >> " + count + "; echo once"}
+	for range 3 {
+		if token, err := Token(context.Background(), "TEST_TOKEN", "github", src); err != nil || token != "once" {
+			t.Fatalf("Token() = %q, %v", token, err)
+		}
+	}
+	data, err := os.ReadFile(count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runs := strings.Count(string(data), "x"); runs != 1 {
+		t.Errorf("token_command ran %d times, want 1", runs)
+	}
+}
+
+func TestToken_RefusesUntrustedCommand(t *testing.T) {
+	useTempConfigDir(t)
+	t.Setenv("TEST_TOKEN", "")
+	marker := filepath.Join(t.TempDir(), "ran")
+	src := config.TokenSource{TokenCommand: "touch " + marker + "; echo tok", UntrustedPath: "/repo/.beans.yml"}
+	_, err := Token(context.Background(), "TEST_TOKEN", "github", src)
+	if err == nil || !strings.Contains(err.Error(), "trusted_projects") {
+		t.Errorf("Token() err = %v, want refusal naming trusted_projects", err)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("untrusted token_command was run")
+	}
+}
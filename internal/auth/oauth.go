@@ -0,0 +1,235 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/config"
+)
+
+// Endpoint holds an OAuth provider's URLs.
+type Endpoint struct {
+	AuthURL  string
+	TokenURL string
+}
+
+// ClickUpEndpoint is ClickUp's OAuth app endpoint.
+var ClickUpEndpoint = Endpoint{
+	AuthURL:  "https://app.clickup.com/api",
+	TokenURL: "https://api.clickup.com/api/v2/oauth/token",
+}
+
+// Flow is an authorization code flow for one OAuth app.
+type Flow struct {
+	Endpoint     Endpoint
+	ClientID     string
+	ClientSecret string
+	// Port is the local port for the redirect listener; 0 picks a free port.
+	// The app's redirect URL must allow http://127.0.0.1:<port>/callback.
+	Port       int
+	HTTPClient *http.Client
+}
+
+// tokenResponse is the body returned by the token endpoint.
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	// Error fields: RFC 6749 style and ClickUp's own
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+	Err              string `json:"err"`
+}
+
+// Login listens for the OAuth redirect on 127.0.0.1, calls open with the
+// authorization URL for the user to visit, and exchanges the returned code
+// for credentials. It gives up when ctx is done.
+func (f *Flow) Login(ctx context.Context, open func(authURL string)) (*Credentials, error) {
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", f.Port))
+	if err != nil {
+		return nil, fmt.Errorf("starting redirect listener: %w", err)
+	}
+	redirectURI := fmt.Sprintf("http://%s/callback", ln.Addr())
+
+	state, err := randomState()
+	if err != nil {
+		ln.Close()
+		return nil, err
+	}
+
+	type result struct {
+		code string
+		err  error
+	}
+	done := make(chan result, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		var res result
+		switch {
+		case q.Get("state") != state:
+			res.err = errors.New("OAuth redirect has the wrong state parameter")
+		case q.Get("error") != "":
+			res.err = fmt.Errorf("authorization denied: %s", q.Get("error"))
+		case q.Get("code") == "":
+			res.err = errors.New("OAuth redirect has no code")
+		default:
+			res.code = q.Get("code")
+		}
+		if res.err != nil {
+			http.Error(w, res.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "beanup is authorized. You can close this window.")
+		}
+		select {
+		case done <- res:
+		default:
+		}
+	})
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = server.Serve(ln) }()
+	defer server.Close()
+
+	q := url.Values{}
+	q.Set("client_id", f.ClientID)
+	q.Set("redirect_uri", redirectURI)
+	q.Set("state", state)
+	open(f.Endpoint.AuthURL + "?" + q.Encode())
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for authorization: %w", ctx.Err())
+	case res := <-done:
+		if res.err != nil {
+			return nil, res.err
+		}
+		return f.Exchange(ctx, res.code, redirectURI)
+	}
+}
+
+// Exchange trades an authorization code for credentials.
+func (f *Flow) Exchange(ctx context.Context, code, redirectURI string) (*Credentials, error) {
+	params := url.Values{}
+	params.Set("grant_type", "authorization_code")
+	params.Set("code", code)
+	params.Set("redirect_uri", redirectURI)
+	return f.token(ctx, params)
+}
+
+// Refresh trades creds' refresh token for a new access token.
+func (f *Flow) Refresh(ctx context.Context, creds *Credentials) (*Credentials, error) {
+	if creds.RefreshToken == "" {
+		return nil, errors.New("stored login has expired and cannot be refreshed; run beanup auth login")
+	}
+	params := url.Values{}
+	params.Set("grant_type", "refresh_token")
+	params.Set("refresh_token", creds.RefreshToken)
+	refreshed, err := f.token(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = creds.RefreshToken
+	}
+	return refreshed, nil
+}
+
+// token calls the token endpoint. ClickUp reads the parameters from the
+// query string, so they are sent there rather than in a form body.
+func (f *Flow) token(ctx context.Context, params url.Values) (*Credentials, error) {
+	params.Set("client_id", f.ClientID)
+	params.Set("client_secret", f.ClientSecret)
+	req, err := http.NewRequestWithContext(ctx, "POST", f.Endpoint.TokenURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := f.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting token: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading token response: %w", err)
+	}
+
+	var tr tokenResponse
+	_ = json.Unmarshal(body, &tr)
+	if resp.StatusCode >= 400 || tr.AccessToken == "" {
+		msg := firstNonEmpty(tr.ErrorDescription, tr.Error, tr.Err, strings.TrimSpace(string(body)))
+		return nil, fmt.Errorf("token request failed: HTTP %d: %s", resp.StatusCode, msg)
+	}
+
+	creds := &Credentials{
+		AccessToken:  tr.AccessToken,
+		RefreshToken: tr.RefreshToken,
+		ClientID:     f.ClientID,
+		ClientSecret: f.ClientSecret,
+	}
+	if tr.ExpiresIn > 0 {
+		creds.Expiry = time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+	}
+	return creds, nil
+}
+
+// ErrNoToken means no ClickUp token is set, configured, or stored.
+var ErrNoToken = errors.New("CLICKUP_TOKEN environment variable is not set (or set token_command or token_file in extensions.clickup, or run beanup auth login)")
+
+// ClickUpToken returns the ClickUp API token: CLICKUP_TOKEN if set, then
+// the configured token_command or token_file, otherwise the login stored by
+// `beanup auth login`, refreshed and saved again if it has expired.
+func ClickUpToken(ctx context.Context, src config.TokenSource) (string, error) {
+	if token, err := lookupToken(ctx, "CLICKUP_TOKEN", src); err != nil || token != "" {
+		return token, err
+	}
+	creds, err := Load(ServiceClickUp)
+	if err != nil {
+		return "", err
+	}
+	if creds == nil || creds.AccessToken == "" {
+		return "", ErrNoToken
+	}
+	if creds.Expired() {
+		flow := &Flow{Endpoint: ClickUpEndpoint, ClientID: creds.ClientID, ClientSecret: creds.ClientSecret}
+		if creds, err = flow.Refresh(ctx, creds); err != nil {
+			return "", err
+		}
+		if err := Save(ServiceClickUp, creds); err != nil {
+			return "", err
+		}
+	}
+	return creds.AccessToken, nil
+}
+
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating state: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
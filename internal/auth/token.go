@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/config"
+)
+
+// tokenCommandTimeout bounds how long a secret helper may take, e.g. while
+// waiting for a password manager unlock prompt.
+const tokenCommandTimeout = 30 * time.Second
+
+// tokenCommandCache holds each token_command's output for the rest of the
+// process, so a password manager prompts once rather than once per client.
+var tokenCommandCache struct {
+	sync.Mutex
+	tokens map[string]string
+}
+
+// Token returns a backend's API token from envVar, then the configured
+// token_command, then token_file. extension names the .beans.yml section
+// for the error when none of them is set.
+func Token(ctx context.Context, envVar, extension string, src config.TokenSource) (string, error) {
+	token, err := lookupToken(ctx, envVar, src)
+	if err != nil {
+		return "", err
+	}
+	if token == "" {
+		return "", fmt.Errorf("%s environment variable is not set (or set token_command or token_file in extensions.%s)", envVar, extension)
+	}
+	return token, nil
+}
+
+// lookupToken is Token without the missing-token error; it returns "" when
+// no source is configured.
+func lookupToken(ctx context.Context, envVar string, src config.TokenSource) (string, error) {
+	if token := os.Getenv(envVar); token != "" {
+		return token, nil
+	}
+	if src.TokenCommand != "" {
+		if src.UntrustedPath != "" {
+			return "", untrustedCommandError(src.UntrustedPath)
+		}
+		return cachedTokenCommand(ctx, src.TokenCommand)
+	}
+	if src.TokenFile != "" {
+		return readTokenFile(src.TokenFile)
+	}
+	return "", nil
+}
+
+// untrustedCommandError explains why a project's token_command was not run.
+func untrustedCommandError(path string) error {
+	userPath, err := config.UserConfigPath()
+	if err != nil {
+		userPath = "the user config"
+	}
+	return fmt.Errorf("refusing to run token_command from project config %s: move it to a profile in %s, or add %s to trusted_projects there",
+		path, userPath, filepath.Dir(path))
+}
+
+// cachedTokenCommand returns command's token, running it only the first
+// time it is asked for. Failures are not cached.
+func cachedTokenCommand(ctx context.Context, command string) (string, error) {
+	tokenCommandCache.Lock()
+	defer tokenCommandCache.Unlock()
+	if token, ok := tokenCommandCache.tokens[command]; ok {
+		return token, nil
+	}
+	token, err := runTokenCommand(ctx, command)
+	if err != nil {
+		return "", err
+	}
+	if tokenCommandCache.tokens == nil {
+		tokenCommandCache.tokens = make(map[string]string)
+	}
+	tokenCommandCache.tokens[command] = token
+	return token, nil
+}
+
+// runTokenCommand runs command through the shell and returns its trimmed stdout.
+func runTokenCommand(ctx context.Context, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, tokenCommandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	cmd.Stdin = os.Stdin
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("running token_command: %w: %s", err, msg)
+		}
+		return "", fmt.Errorf("running token_command: %w", err)
+	}
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", fmt.Errorf("token_command %q printed nothing", command)
+	}
+	return token, nil
+}
+
+// readTokenFile returns the trimmed contents of path, expanding a leading ~.
+func readTokenFile(path string) (string, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("expanding token_file: %w", err)
+		}
+		path = filepath.Join(home, rest)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading token_file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("token_file %s is empty", path)
+	}
+	return token, nil
+}
@@ -0,0 +1,122 @@
+package azure
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/toba/bean-me-up/internal/restapi"
+)
+
+// DefaultURL is the Azure DevOps Services root.
+const DefaultURL = "https://dev.azure.com"
+
+// apiVersion is sent with every request.
+const apiVersion = "7.1"
+
+// HTTPDoer sends HTTP requests. *http.Client satisfies it.
+type HTTPDoer = restapi.HTTPDoer
+
+// Client provides work item access for a single Azure DevOps project.
+type Client struct {
+	api *restapi.Client
+	// orgURL is the organization root, used for work item relation URLs
+	orgURL  string
+	project string
+}
+
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the transport used for API requests.
+func WithHTTPClient(doer HTTPDoer) ClientOption {
+	return func(c *Client) { c.api.HTTP = doer }
+}
+
+// NewClient creates a client for project in organization, authenticated
+// with a personal access token. baseURL defaults to DefaultURL.
+func NewClient(baseURL, organization, project, pat string, opts ...ClientOption) (*Client, error) {
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+	if u, err := url.Parse(baseURL); err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid Azure DevOps URL %q", baseURL)
+	}
+	if organization == "" || project == "" {
+		return nil, fmt.Errorf("Azure DevOps organization and project are required")
+	}
+
+	orgURL := strings.TrimRight(baseURL, "/") + "/" + url.PathEscape(organization)
+	api := restapi.New("azure", orgURL+"/"+url.PathEscape(project)+"/_apis")
+	// Work item writes are JSON Patch documents
+	api.ContentType = "application/json-patch+json"
+	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(":"+pat))
+	api.Authorize = func(req *http.Request) {
+		req.Header.Set("Authorization", auth)
+	}
+	api.ErrorMessage = func(body []byte) string {
+		var errResp errorResponse
+		_ = json.Unmarshal(body, &errResp)
+		return errResp.Message
+	}
+
+	c := &Client{api: api, orgURL: orgURL, project: project}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// WorkItemURL returns the API URL identifying a work item in relations.
+func (c *Client) WorkItemURL(id int) string {
+	return fmt.Sprintf("%s/_apis/wit/workItems/%d", c.orgURL, id)
+}
+
+// EditURL returns the browser URL of a work item.
+func (c *Client) EditURL(id int) string {
+	return fmt.Sprintf("%s/%s/_workitems/edit/%d", c.orgURL, url.PathEscape(c.project), id)
+}
+
+// GetWorkItem fetches a work item with its relations.
+func (c *Client) GetWorkItem(ctx context.Context, id int) (*WorkItem, error) {
+	var item WorkItem
+	path := fmt.Sprintf("/wit/workitems/%d?$expand=relations&api-version=%s", id, apiVersion)
+	if err := c.api.Do(ctx, "GET", path, nil, &item); err != nil {
+		return nil, fmt.Errorf("getting work item %d: %w", id, err)
+	}
+	return &item, nil
+}
+
+// GetProject fetches the project's root area, failing if the token can't
+// see the project.
+func (c *Client) GetProject(ctx context.Context) error {
+	path := "/wit/classificationnodes/areas?api-version=" + apiVersion
+	if err := c.api.Do(ctx, "GET", path, nil, nil); err != nil {
+		return fmt.Errorf("getting project %s: %w", c.project, err)
+	}
+	return nil
+}
+
+// CreateWorkItem creates a work item of the given type from patch operations.
+func (c *Client) CreateWorkItem(ctx context.Context, workItemType string, ops []PatchOp) (*WorkItem, error) {
+	var item WorkItem
+	path := fmt.Sprintf("/wit/workitems/$%s?api-version=%s", url.PathEscape(workItemType), apiVersion)
+	if err := c.api.Do(ctx, "POST", path, ops, &item); err != nil {
+		return nil, fmt.Errorf("creating %s: %w", workItemType, err)
+	}
+	return &item, nil
+}
+
+// UpdateWorkItem applies patch operations to a work item.
+func (c *Client) UpdateWorkItem(ctx context.Context, id int, ops []PatchOp) (*WorkItem, error) {
+	var item WorkItem
+	path := fmt.Sprintf("/wit/workitems/%d?$expand=relations&api-version=%s", id, apiVersion)
+	if err := c.api.Do(ctx, "PATCH", path, ops, &item); err != nil {
+		return nil, fmt.Errorf("updating work item %d: %w", id, err)
+	}
+	return &item, nil
+}
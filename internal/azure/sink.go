@@ -0,0 +1,311 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/toba/bean-me-up/internal/auth"
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+	"github.com/toba/bean-me-up/internal/restapi"
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+// SinkName is the registry key and bean extension name for Azure Boards.
+const SinkName = "azure"
+
+// defaultWorkItemType is used for bean types without a mapping.
+const defaultWorkItemType = "Task"
+
+// defaultPriorityMapping maps bean priorities to work item priorities (1 is highest).
+var defaultPriorityMapping = map[string]int{
+	"critical": 1,
+	"high":     2,
+	"normal":   3,
+	"low":      4,
+	"deferred": 4,
+}
+
+func init() {
+	syncer.Register(SinkName, newSinkFromConfig)
+}
+
+// newSinkFromConfig builds the Azure Boards sink using AZURE_DEVOPS_PAT.
+func newSinkFromConfig(cfg *config.Config) (syncer.Sink, error) {
+	ac := cfg.Beans.Azure
+	if ac == nil || ac.Organization == "" || ac.Project == "" {
+		return nil, fmt.Errorf("Azure DevOps organization and project are required in .beans.yml extensions.azure")
+	}
+	pat, err := auth.Token(context.Background(), "AZURE_DEVOPS_PAT", SinkName, ac.TokenSource)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewClient(ac.URL, ac.Organization, ac.Project, pat)
+	if err != nil {
+		return nil, err
+	}
+	return NewSink(client, ac), nil
+}
+
+// Sink syncs beans to work items in an Azure DevOps project.
+//
+// Bean types map to work item types, statuses to states, parents to
+// parent/child links, and blocking relationships to predecessor/successor
+// dependency links.
+type Sink struct {
+	client *Client
+	config *config.AzureConfig
+
+	// The project, read before a missing work item counts as deleted
+	project restapi.Container
+}
+
+// NewSink creates a sink that writes work items with client using the mappings in cfg.
+func NewSink(client *Client, cfg *config.AzureConfig) *Sink {
+	s := &Sink{client: client, config: cfg}
+	s.project.Check = client.GetProject
+	return s
+}
+
+// Name returns SinkName.
+func (s *Sink) Name() string { return SinkName }
+
+// Prepare is a no-op; the client needs no lookups before syncing.
+func (s *Sink) Prepare(ctx context.Context) error { return nil }
+
+// GetTask fetches the work item. The *WorkItem is kept in the ref for UpdateTask.
+func (s *Sink) GetTask(ctx context.Context, taskID string) (*syncer.TaskRef, error) {
+	id, err := workItemID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	item, err := s.client.GetWorkItem(ctx, id)
+	if err != nil {
+		return nil, s.project.Missing(ctx, err)
+	}
+	return s.workItemRef(item), nil
+}
+
+// CreateTask creates a work item under parentTaskID (if any), then moves it
+// to the bean's mapped state; new items always start in the initial state.
+func (s *Sink) CreateTask(ctx context.Context, b *beans.Bean, parentTaskID string) (*syncer.TaskRef, error) {
+	ops := []PatchOp{
+		{Op: "add", Path: fieldTitle, Value: b.Title},
+		{Op: "add", Path: fieldDescription, Value: toHTML(b.Body)},
+		{Op: "add", Path: fieldTags, Value: strings.Join(tags(b.Tags), "; ")},
+	}
+	if p, ok := s.priority(b); ok {
+		ops = append(ops, PatchOp{Op: "add", Path: fieldPriority, Value: p})
+	}
+	if parentTaskID != "" {
+		parentID, err := workItemID(parentTaskID)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, s.relationOp(relParent, parentID))
+	}
+
+	item, err := s.client.CreateWorkItem(ctx, s.workItemType(b.Type), ops)
+	if err != nil {
+		return nil, err
+	}
+	if state := s.state(b); state != "" && !strings.EqualFold(item.Fields.State, state) {
+		moved, err := s.client.UpdateWorkItem(ctx, item.ID, []PatchOp{{Op: "add", Path: fieldState, Value: state}})
+		if err != nil {
+			// Keep the link; the next update retries the state
+			return s.workItemRef(item), fmt.Errorf("setting state of work item %d: %w", item.ID, err)
+		}
+		item = moved
+	}
+	return s.workItemRef(item), nil
+}
+
+// UpdateTask patches only the fields that differ from the current work item.
+func (s *Sink) UpdateTask(ctx context.Context, current *syncer.TaskRef, b *beans.Bean) (*syncer.TaskRef, bool, error) {
+	id, err := workItemID(current.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	item, ok := current.Remote.(*WorkItem)
+	if !ok {
+		if item, err = s.client.GetWorkItem(ctx, id); err != nil {
+			return nil, false, err
+		}
+	}
+
+	ops := s.buildUpdateOps(item, b)
+	if len(ops) == 0 {
+		return current, false, nil
+	}
+	updated, err := s.client.UpdateWorkItem(ctx, id, ops)
+	if err != nil {
+		return nil, false, err
+	}
+	return s.workItemRef(updated), true, nil
+}
+
+// SyncTags is a no-op: bean tags are sent with every create and update.
+func (s *Sink) SyncTags(ctx context.Context, task *syncer.TaskRef, b *beans.Bean) bool {
+	return false
+}
+
+// SetParent links the child work item to its parent, replacing any other parent.
+func (s *Sink) SetParent(ctx context.Context, parentTaskID, childTaskID string) error {
+	parentID, err := workItemID(parentTaskID)
+	if err != nil {
+		return err
+	}
+	childID, err := workItemID(childTaskID)
+	if err != nil {
+		return err
+	}
+	child, err := s.client.GetWorkItem(ctx, childID)
+	if err != nil {
+		return err
+	}
+
+	var ops []PatchOp
+	for i, r := range child.Relations {
+		if r.Rel != relParent {
+			continue
+		}
+		if linksTo(r, parentID) {
+			return nil
+		}
+		// A work item has at most one parent
+		ops = append(ops, PatchOp{Op: "remove", Path: "/relations/" + strconv.Itoa(i)})
+	}
+	ops = append(ops, s.relationOp(relParent, parentID))
+	_, err = s.client.UpdateWorkItem(ctx, childID, ops)
+	return err
+}
+
+// SetRelationship makes the blocked work item a successor of the blocker.
+func (s *Sink) SetRelationship(ctx context.Context, blockerTaskID, blockedTaskID string) error {
+	blockerID, err := workItemID(blockerTaskID)
+	if err != nil {
+		return err
+	}
+	blockedID, err := workItemID(blockedTaskID)
+	if err != nil {
+		return err
+	}
+	blocker, err := s.client.GetWorkItem(ctx, blockerID)
+	if err != nil {
+		return err
+	}
+	for _, r := range blocker.Relations {
+		if r.Rel == relSuccessor && linksTo(r, blockedID) {
+			return nil
+		}
+	}
+	_, err = s.client.UpdateWorkItem(ctx, blockerID, []PatchOp{s.relationOp(relSuccessor, blockedID)})
+	return err
+}
+
+// buildUpdateOps returns patch operations for fields that differ from current.
+// The work item type is only set on create.
+func (s *Sink) buildUpdateOps(current *WorkItem, b *beans.Bean) []PatchOp {
+	var ops []PatchOp
+
+	if current.Fields.Title != b.Title {
+		ops = append(ops, PatchOp{Op: "add", Path: fieldTitle, Value: b.Title})
+	}
+	if desc := toHTML(b.Body); current.Fields.Description != desc {
+		ops = append(ops, PatchOp{Op: "add", Path: fieldDescription, Value: desc})
+	}
+	if state := s.state(b); state != "" && !strings.EqualFold(current.Fields.State, state) {
+		ops = append(ops, PatchOp{Op: "add", Path: fieldState, Value: state})
+	}
+	if p, ok := s.priority(b); ok && current.Fields.Priority != p {
+		ops = append(ops, PatchOp{Op: "add", Path: fieldPriority, Value: p})
+	}
+	if want := tags(b.Tags); !slices.Equal(tags(splitTags(current.Fields.Tags)), want) {
+		ops = append(ops, PatchOp{Op: "add", Path: fieldTags, Value: strings.Join(want, "; ")})
+	}
+
+	return ops
+}
+
+// relationOp returns a patch operation adding a link of rel to work item id.
+func (s *Sink) relationOp(rel string, id int) PatchOp {
+	return PatchOp{Op: "add", Path: "/relations/-", Value: Relation{Rel: rel, URL: s.client.WorkItemURL(id)}}
+}
+
+// workItemType returns the mapped work item type for a bean type.
+func (s *Sink) workItemType(beanType string) string {
+	if t, ok := s.config.TypeMapping[beanType]; ok && t != "" {
+		return t
+	}
+	if t, ok := config.DefaultAzureTypeMapping[beanType]; ok {
+		return t
+	}
+	return defaultWorkItemType
+}
+
+// state returns the mapped work item state for the bean's status.
+func (s *Sink) state(b *beans.Bean) string {
+	if state, ok := s.config.StatusMapping[b.Status]; ok {
+		return state
+	}
+	return config.DefaultAzureStatusMapping[b.Status]
+}
+
+// priority returns the mapped work item priority for the bean.
+func (s *Sink) priority(b *beans.Bean) (int, bool) {
+	if p, ok := s.config.PriorityMapping[b.Priority]; ok {
+		return p, true
+	}
+	p, ok := defaultPriorityMapping[b.Priority]
+	return p, ok
+}
+
+// workItemRef converts a work item to a sink-neutral reference.
+func (s *Sink) workItemRef(item *WorkItem) *syncer.TaskRef {
+	url := item.Links.HTML.Href
+	if url == "" {
+		url = s.client.EditURL(item.ID)
+	}
+	return &syncer.TaskRef{ID: strconv.Itoa(item.ID), URL: url, Tags: splitTags(item.Fields.Tags), Remote: item}
+}
+
+// toHTML renders a bean body as work item description HTML, keeping line breaks.
+func toHTML(body string) string {
+	return strings.ReplaceAll(html.EscapeString(body), "\n", "<br>")
+}
+
+// tags returns the sorted, de-duplicated tag list.
+func tags(list []string) []string {
+	sorted := slices.Clone(list)
+	slices.Sort(sorted)
+	return slices.Compact(sorted)
+}
+
+// splitTags parses System.Tags ("a; b").
+func splitTags(field string) []string {
+	var out []string
+	for t := range strings.SplitSeq(field, ";") {
+		if t = strings.TrimSpace(t); t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// linksTo reports whether a relation points at work item id.
+func linksTo(r Relation, id int) bool {
+	return strings.HasSuffix(strings.ToLower(r.URL), "/workitems/"+strconv.Itoa(id))
+}
+
+// workItemID parses a stored task ID as a work item ID.
+func workItemID(taskID string) (int, error) {
+	n, err := strconv.Atoi(taskID)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid work item ID %q", taskID)
+	}
+	return n, nil
+}
@@ -0,0 +1,223 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+	"github.com/toba/bean-me-up/internal/syncer"
+	"github.com/toba/bean-me-up/internal/syncertest"
+)
+
+// fakeAzure is an in-memory work item API for organization acme, project Widgets.
+type fakeAzure struct {
+	mu    sync.Mutex
+	items map[int]*WorkItem
+	// failPatches makes that many PATCHes fail, e.g. setting a new item's state
+	failPatches int
+}
+
+func newFakeAzure(t *testing.T) (*fakeAzure, *Client) {
+	t.Helper()
+	f := &fakeAzure{items: map[int]*WorkItem{}}
+	url := syncertest.Serve(t, f)
+
+	client, err := NewClient(url, "acme", "Widgets", "pat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f, client
+}
+
+func (f *fakeAzure) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, pass, ok := r.BasicAuth(); !ok || pass != "pat" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.Method != "GET" && r.Header.Get("Content-Type") != "application/json-patch+json" {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+
+	if r.URL.Path == "/acme/Widgets/_apis/wit/classificationnodes/areas" {
+		_, _ = w.Write([]byte(`{"name":"Widgets"}`))
+		return
+	}
+
+	rest := strings.TrimPrefix(r.URL.Path, "/acme/Widgets/_apis/wit/workitems/")
+	var ops []PatchOp
+	_ = json.NewDecoder(r.Body).Decode(&ops)
+
+	if typ, ok := strings.CutPrefix(rest, "$"); ok && r.Method == "POST" {
+		item := &WorkItem{ID: len(f.items) + 1, Fields: Fields{State: "New", WorkItemType: typ}}
+		f.items[item.ID] = item
+		apply(item, ops)
+		_ = json.NewEncoder(w).Encode(item)
+		return
+	}
+
+	id, _ := strconv.Atoi(rest)
+	item, ok := f.items[id]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"TF401232: Work item does not exist."}`))
+		return
+	}
+	if r.Method == "PATCH" && f.failPatches > 0 {
+		f.failPatches--
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message":"TF401320: Rule error for field State."}`))
+		return
+	}
+	if r.Method == "PATCH" {
+		apply(item, ops)
+	}
+	_ = json.NewEncoder(w).Encode(item)
+}
+
+func apply(item *WorkItem, ops []PatchOp) {
+	// Removals are applied first so indexes refer to the original relations
+	var kept []Relation
+	removed := map[int]bool{}
+	for _, op := range ops {
+		if i, ok := strings.CutPrefix(op.Path, "/relations/"); ok && op.Op == "remove" {
+			n, _ := strconv.Atoi(i)
+			removed[n] = true
+		}
+	}
+	for i, r := range item.Relations {
+		if !removed[i] {
+			kept = append(kept, r)
+		}
+	}
+	item.Relations = kept
+
+	for _, op := range ops {
+		switch op.Path {
+		case fieldTitle:
+			item.Fields.Title = op.Value.(string)
+		case fieldDescription:
+			item.Fields.Description = op.Value.(string)
+		case fieldState:
+			item.Fields.State = op.Value.(string)
+		case fieldTags:
+			item.Fields.Tags = op.Value.(string)
+		case fieldPriority:
+			item.Fields.Priority = int(op.Value.(float64))
+		case "/relations/-":
+			v := op.Value.(map[string]any)
+			item.Relations = append(item.Relations, Relation{Rel: v["rel"].(string), URL: v["url"].(string)})
+		}
+	}
+}
+
+func TestSink(t *testing.T) {
+	syncertest.RunSinkTests(t, func(t *testing.T) syncertest.SinkFixture {
+		_, client := newFakeAzure(t)
+		return syncertest.SinkFixture{Sink: NewSink(client, &config.AzureConfig{Organization: "acme", Project: "Widgets"}), DeletedTaskID: "99"}
+	})
+}
+
+func TestSink_MapsBeanFields(t *testing.T) {
+	az, client := newFakeAzure(t)
+	sink := NewSink(client, &config.AzureConfig{Organization: "acme", Project: "Widgets"})
+	state := syncertest.NewState()
+
+	beanList := []beans.Bean{
+		{ID: "epic", Title: "Epic", Type: "feature", Status: "in-progress"},
+		{ID: "fix", Title: "Fix crash", Body: "Steps:\n1. <click>", Type: "bug", Priority: "high", Status: "todo", Parent: "epic", Tags: []string{"ui", "api"}, Blocking: []string{"done"}},
+		{ID: "done", Title: "Shipped", Status: "completed"},
+	}
+
+	results := syncertest.Sync(t, sink, syncer.Options{}, state, beanList)
+	syncertest.RequireActions(t, results, "created")
+
+	item := func(beanID string) *WorkItem {
+		id, _ := strconv.Atoi(*state.GetTaskID(beanID))
+		return az.items[id]
+	}
+	epic, fix, done := item("epic"), item("fix"), item("done")
+
+	if epic.Fields.WorkItemType != "Feature" || fix.Fields.WorkItemType != "Bug" || done.Fields.WorkItemType != "Task" {
+		t.Errorf("types = %q, %q, %q", epic.Fields.WorkItemType, fix.Fields.WorkItemType, done.Fields.WorkItemType)
+	}
+	if epic.Fields.State != "Active" || done.Fields.State != "Closed" {
+		t.Errorf("states = %q, %q; want Active, Closed", epic.Fields.State, done.Fields.State)
+	}
+	if fix.Fields.Description != "Steps:<br>1. &lt;click&gt;" {
+		t.Errorf("description = %q", fix.Fields.Description)
+	}
+	if fix.Fields.Tags != "api; ui" || fix.Fields.Priority != 2 {
+		t.Errorf("tags %q priority %d", fix.Fields.Tags, fix.Fields.Priority)
+	}
+
+	if !strings.HasSuffix(results[1].TaskURL, "/acme/Widgets/_workitems/edit/"+strconv.Itoa(fix.ID)) {
+		t.Errorf("task URL = %q", results[1].TaskURL)
+	}
+
+	var parents, successors []string
+	for _, r := range fix.Relations {
+		switch r.Rel {
+		case relParent:
+			parents = append(parents, r.URL)
+		case relSuccessor:
+			successors = append(successors, r.URL)
+		}
+	}
+	if len(parents) != 1 || parents[0] != client.WorkItemURL(epic.ID) {
+		t.Errorf("parent links = %v", parents)
+	}
+	if len(successors) != 1 || successors[0] != client.WorkItemURL(done.ID) {
+		t.Errorf("successor links = %v", successors)
+	}
+
+	// A second sync changes nothing and adds no links
+	syncertest.RequireActions(t, syncertest.Sync(t, sink, syncer.Options{Force: true}, state, beanList), "unchanged")
+	if len(fix.Relations) != 2 {
+		t.Errorf("second sync changed relations: %+v", fix.Relations)
+	}
+}
+
+func TestSink_SetParentReplacesParent(t *testing.T) {
+	az, client := newFakeAzure(t)
+	sink := NewSink(client, &config.AzureConfig{Organization: "acme", Project: "Widgets"})
+	az.items[1] = &WorkItem{ID: 1}
+	az.items[2] = &WorkItem{ID: 2}
+	az.items[3] = &WorkItem{ID: 3, Relations: []Relation{{Rel: relParent, URL: client.WorkItemURL(1)}}}
+
+	if err := sink.SetParent(context.Background(), "2", "3"); err != nil {
+		t.Fatal(err)
+	}
+	if rels := az.items[3].Relations; len(rels) != 1 || rels[0].URL != client.WorkItemURL(2) {
+		t.Errorf("relations = %+v, want only parent 2", rels)
+	}
+}
+
+func TestSink_FailedStateKeepsNewItem(t *testing.T) {
+	az, client := newFakeAzure(t)
+	az.failPatches = 1
+	sink := NewSink(client, &config.AzureConfig{Organization: "acme", Project: "Widgets"})
+	state := syncertest.NewState()
+	beanList := []beans.Bean{{ID: "done", Title: "Shipped", Status: "completed"}}
+
+	results := syncertest.Sync(t, sink, syncer.Options{}, state, beanList)
+	if results[0].Action != "error" || state.GetTaskID("done") == nil || state.GetSyncedAt("done") != nil {
+		t.Fatalf("first sync: action %q, err %v; want an error with the new item linked but not synced", results[0].Action, results[0].Error)
+	}
+
+	// The next sync moves the same item rather than creating another
+	results = syncertest.Sync(t, sink, syncer.Options{}, state, beanList)
+	syncertest.RequireActions(t, results, "updated")
+	if len(az.items) != 1 || az.items[1].Fields.State != "Closed" {
+		t.Errorf("items = %+v, want one closed item", az.items)
+	}
+}
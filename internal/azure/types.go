@@ -0,0 +1,59 @@
+// Package azure provides Azure DevOps Boards integration.
+package azure
+
+// Work item link types.
+const (
+	// relParent on a child points at its parent.
+	relParent = "System.LinkTypes.Hierarchy-Reverse"
+	// relSuccessor on a blocker points at the work item it blocks.
+	relSuccessor = "System.LinkTypes.Dependency-Forward"
+)
+
+// WorkItem holds work item data returned from Azure DevOps.
+type WorkItem struct {
+	ID        int        `json:"id"`
+	Fields    Fields     `json:"fields"`
+	Relations []Relation `json:"relations"`
+	Links     struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"_links"`
+}
+
+// Fields are the work item fields beanup reads.
+type Fields struct {
+	Title        string `json:"System.Title"`
+	Description  string `json:"System.Description"` // HTML
+	State        string `json:"System.State"`
+	WorkItemType string `json:"System.WorkItemType"`
+	Tags         string `json:"System.Tags"` // "a; b"
+	Priority     int    `json:"Microsoft.VSTS.Common.Priority"`
+}
+
+// Relation is a link from a work item to another resource.
+type Relation struct {
+	Rel string `json:"rel"`
+	URL string `json:"url"`
+}
+
+// PatchOp is one JSON Patch operation in a create or update request.
+type PatchOp struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value,omitempty"`
+}
+
+// Field paths used in patch operations.
+const (
+	fieldTitle       = "/fields/System.Title"
+	fieldDescription = "/fields/System.Description"
+	fieldState       = "/fields/System.State"
+	fieldTags        = "/fields/System.Tags"
+	fieldPriority    = "/fields/Microsoft.VSTS.Common.Priority"
+)
+
+// errorResponse is Azure DevOps' error body.
+type errorResponse struct {
+	Message string `json:"message"`
+}
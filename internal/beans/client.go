@@ -13,22 +13,42 @@ import (
 // ExtensionDataOp is an alias for the beans client package type.
 type ExtensionDataOp = client.ExtensionDataOp
 
-// Client executes beans CLI commands and parses output.
+// Client executes beans CLI commands and parses output, or reads the beans
+// directory itself when the CLI isn't used.
 type Client struct {
 	beansPath string
 	gc        *client.Client
+	dir       *Dir // Set when reading bean files directly
 }
 
-// NewClient creates a new beans CLI client.
+// NoCLI makes NewClient read bean files directly even when the beans CLI
+// is installed.
+var NoCLI bool
+
+// CLIInstalled returns true if the beans CLI is in PATH.
+func CLIInstalled() bool {
+	_, err := exec.LookPath("beans")
+	return err == nil
+}
+
+// NewClient creates a new beans client. It uses the beans CLI if installed
+// and NoCLI isn't set, and otherwise reads the directory with Dir.
 func NewClient(beansPath string) *Client {
-	return &Client{
+	c := &Client{
 		beansPath: beansPath,
 		gc:        client.New(client.WithBeansPath(beansPath)),
 	}
+	if NoCLI || !CLIInstalled() {
+		c.dir = NewDir(beansPath)
+	}
+	return c
 }
 
 // List returns all beans from the beans CLI.
 func (c *Client) List() ([]Bean, error) {
+	if c.dir != nil {
+		return c.dir.List()
+	}
 	args := []string{"list", "--json", "--full"}
 	if c.beansPath != "" {
 		args = append(args, "--beans-path", c.beansPath)
@@ -49,6 +69,9 @@ func (c *Client) List() ([]Bean, error) {
 
 // Get returns a specific bean by ID.
 func (c *Client) Get(id string) (*Bean, error) {
+	if c.dir != nil {
+		return c.dir.Get(id)
+	}
 	args := []string{"show", "--json", id}
 	if c.beansPath != "" {
 		args = append(args, "--beans-path", c.beansPath)
@@ -77,6 +100,9 @@ func (c *Client) GetMultiple(ids []string) ([]Bean, error) {
 	if len(ids) == 0 {
 		return nil, nil
 	}
+	if c.dir != nil {
+		return c.dir.GetMultiple(ids)
+	}
 
 	// For a single ID, use Get (beans show returns object, not array)
 	if len(ids) == 1 {
@@ -106,19 +132,65 @@ func (c *Client) GetMultiple(ids []string) ([]Bean, error) {
 	return beans, nil
 }
 
+// BeanUpdate holds bean fields to change; nil fields are left alone.
+type BeanUpdate struct {
+	Status   *string
+	Type     *string
+	Priority *string
+	Due      *string // YYYY-MM-DD
+	Body     *string
+}
+
+// Update changes the bean's fields that are set in u.
+func (c *Client) Update(id string, u BeanUpdate) error {
+	if c.dir != nil {
+		return c.dir.Update(id, u)
+	}
+	args := []string{"update", id}
+	if u.Status != nil {
+		args = append(args, "--status", *u.Status)
+	}
+	if u.Type != nil {
+		args = append(args, "--type", *u.Type)
+	}
+	if u.Priority != nil {
+		args = append(args, "--priority", *u.Priority)
+	}
+	if u.Due != nil {
+		args = append(args, "--due", *u.Due)
+	}
+	if u.Body != nil {
+		args = append(args, "--body", *u.Body)
+	}
+	if c.beansPath != "" {
+		args = append(args, "--beans-path", c.beansPath)
+	}
+	_, err := c.exec(args...)
+	return err
+}
+
 // SetExtensionData sets extension data on a single bean.
 func (c *Client) SetExtensionData(id, name string, data map[string]any) error {
+	if c.dir != nil {
+		return c.dir.SetExtensionData(id, name, data)
+	}
 	return c.gc.SetExtensionData(id, name, data)
 }
 
 // RemoveExtensionData removes extension data from a single bean.
 func (c *Client) RemoveExtensionData(id, name string) error {
+	if c.dir != nil {
+		return c.dir.RemoveExtensionData(id, name)
+	}
 	return c.gc.RemoveExtensionData(id, name)
 }
 
 // SetExtensionDataBatch sets extension data on multiple beans in a single
 // GraphQL call using aliased mutations.
 func (c *Client) SetExtensionDataBatch(ops []ExtensionDataOp) error {
+	if c.dir != nil {
+		return c.dir.SetExtensionDataBatch(ops)
+	}
 	return c.gc.SetExtensionDataBatch(ops)
 }
 
@@ -0,0 +1,334 @@
+package beans
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Dir reads and writes bean files directly, for machines without the beans
+// CLI. It understands the files the CLI writes: markdown named
+// <id>--<slug>.md with YAML frontmatter between --- lines.
+type Dir struct {
+	path string
+}
+
+// NewDir returns a reader for the beans directory at path (default .beans).
+func NewDir(path string) *Dir {
+	if path == "" {
+		path = ".beans"
+	}
+	return &Dir{path: path}
+}
+
+// frontmatter is the YAML header of a bean file.
+type frontmatter struct {
+	Title      string                    `yaml:"title"`
+	Status     string                    `yaml:"status"`
+	Type       string                    `yaml:"type"`
+	Priority   string                    `yaml:"priority"`
+	Tags       []string                  `yaml:"tags"`
+	CreatedAt  *time.Time                `yaml:"created_at"`
+	UpdatedAt  *time.Time                `yaml:"updated_at"`
+	Due        *string                   `yaml:"due"`
+	Parent     string                    `yaml:"parent"`
+	Blocking   []string                  `yaml:"blocking"`
+	Extensions map[string]map[string]any `yaml:"extensions"`
+}
+
+// List returns every bean in the directory, sorted by ID. Like beans list,
+// it skips subdirectories such as the archive.
+func (d *Dir) List() ([]Bean, error) {
+	entries, err := os.ReadDir(d.path)
+	if err != nil {
+		return nil, fmt.Errorf("reading beans directory: %w", err)
+	}
+	var beans []Bean
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".md") || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		b, err := d.read(e.Name())
+		if err != nil {
+			return nil, err
+		}
+		beans = append(beans, *b)
+	}
+	slices.SortFunc(beans, func(a, b Bean) int { return strings.Compare(a.ID, b.ID) })
+	return beans, nil
+}
+
+// Get returns a specific bean by ID.
+func (d *Dir) Get(id string) (*Bean, error) {
+	name, err := d.file(id)
+	if err != nil {
+		return nil, err
+	}
+	return d.read(name)
+}
+
+// GetMultiple returns multiple beans by ID.
+func (d *Dir) GetMultiple(ids []string) ([]Bean, error) {
+	var beans []Bean
+	for _, id := range ids {
+		b, err := d.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		beans = append(beans, *b)
+	}
+	return beans, nil
+}
+
+// SetExtensionData replaces a bean's extension data for name.
+func (d *Dir) SetExtensionData(id, name string, data map[string]any) error {
+	return d.editExtensions(id, func(ext *yaml.Node) error {
+		var value yaml.Node
+		if err := value.Encode(data); err != nil {
+			return fmt.Errorf("encoding extension data: %w", err)
+		}
+		setKey(ext, name, &value)
+		return nil
+	})
+}
+
+// RemoveExtensionData removes a bean's extension data for name.
+func (d *Dir) RemoveExtensionData(id, name string) error {
+	return d.editExtensions(id, func(ext *yaml.Node) error {
+		deleteKey(ext, name)
+		return nil
+	})
+}
+
+// SetExtensionDataBatch sets extension data on several beans. It stops at
+// the first failure; earlier beans keep their new data.
+func (d *Dir) SetExtensionDataBatch(ops []ExtensionDataOp) error {
+	for _, op := range ops {
+		if err := d.SetExtensionData(op.ID, op.Name, op.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// file returns the name of the bean's file.
+func (d *Dir) file(id string) (string, error) {
+	if _, err := os.Stat(filepath.Join(d.path, id+".md")); err == nil {
+		return id + ".md", nil
+	}
+	matches, err := filepath.Glob(filepath.Join(d.path, id+"--*.md"))
+	if err != nil || len(matches) == 0 {
+		return "", fmt.Errorf("bean not found: %s", id)
+	}
+	return filepath.Base(matches[0]), nil
+}
+
+// read parses the bean file name.
+func (d *Dir) read(name string) (*Bean, error) {
+	data, err := os.ReadFile(filepath.Join(d.path, name))
+	if err != nil {
+		return nil, fmt.Errorf("reading bean: %w", err)
+	}
+	header, body, err := splitFrontmatter(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", name, err)
+	}
+	var fm frontmatter
+	if err := yaml.Unmarshal(header, &fm); err != nil {
+		return nil, fmt.Errorf("parsing %s frontmatter: %w", name, err)
+	}
+
+	id, slug, _ := strings.Cut(strings.TrimSuffix(name, ".md"), "--")
+	return &Bean{
+		ID:         id,
+		Slug:       slug,
+		Path:       name,
+		Title:      fm.Title,
+		Status:     fm.Status,
+		Type:       fm.Type,
+		Priority:   fm.Priority,
+		CreatedAt:  fm.CreatedAt,
+		UpdatedAt:  fm.UpdatedAt,
+		Body:       strings.Trim(string(body), "\n"),
+		Parent:     fm.Parent,
+		Blocking:   fm.Blocking,
+		Due:        fm.Due,
+		Tags:       fm.Tags,
+		Extensions: fm.Extensions,
+	}, nil
+}
+
+// Update sets the bean's frontmatter fields that are set in u, and bumps
+// updated_at as the beans CLI does.
+func (d *Dir) Update(id string, u BeanUpdate) error {
+	return d.editFile(id, func(root *yaml.Node, body *[]byte) error {
+		if u.Body != nil {
+			*body = []byte("\n" + *u.Body + "\n")
+		}
+		if u.Status != nil {
+			setKey(root, "status", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: *u.Status})
+		}
+		if u.Type != nil {
+			setKey(root, "type", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: *u.Type})
+		}
+		if u.Priority != nil {
+			setKey(root, "priority", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: *u.Priority})
+		}
+		if u.Due != nil {
+			// Written as a plain date, as the beans CLI does
+			setKey(root, "due", &yaml.Node{Kind: yaml.ScalarNode, Value: *u.Due})
+		}
+		setKey(root, "updated_at", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!timestamp", Value: time.Now().UTC().Format(time.RFC3339)})
+		return nil
+	})
+}
+
+// editExtensions rewrites the bean's frontmatter after edit changes its
+// extensions mapping. The mapping is removed if edit leaves it empty.
+func (d *Dir) editExtensions(id string, edit func(ext *yaml.Node) error) error {
+	return d.editFrontmatter(id, func(root *yaml.Node) error {
+		ext := getKey(root, "extensions")
+		if ext == nil {
+			ext = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		if err := edit(ext); err != nil {
+			return err
+		}
+		if len(ext.Content) == 0 {
+			deleteKey(root, "extensions")
+		} else {
+			setKey(root, "extensions", ext)
+		}
+		return nil
+	})
+}
+
+// editFrontmatter rewrites the bean's frontmatter after edit changes its
+// root mapping. The body and the fields edit leaves alone are kept as
+// written.
+func (d *Dir) editFrontmatter(id string, edit func(root *yaml.Node) error) error {
+	return d.editFile(id, func(root *yaml.Node, _ *[]byte) error { return edit(root) })
+}
+
+// editFile rewrites the bean file after edit changes its frontmatter root
+// mapping or its body.
+func (d *Dir) editFile(id string, edit func(root *yaml.Node, body *[]byte) error) error {
+	name, err := d.file(id)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(d.path, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading bean: %w", err)
+	}
+	header, body, err := splitFrontmatter(data)
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", name, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(header, &doc); err != nil {
+		return fmt.Errorf("parsing %s frontmatter: %w", name, err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("parsing %s frontmatter: not a mapping", name)
+	}
+	if err := edit(doc.Content[0], &body); err != nil {
+		return err
+	}
+
+	// The beans CLI writes frontmatter with yaml.v3's default indent
+	var out bytes.Buffer
+	out.WriteString("---\n")
+	enc := yaml.NewEncoder(&out)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("encoding %s frontmatter: %w", name, err)
+	}
+	_ = enc.Close()
+	out.WriteString("---\n")
+	out.Write(body)
+	return writeFileAtomic(path, out.Bytes())
+}
+
+// splitFrontmatter returns the YAML between the leading --- lines and the
+// rest of the file.
+func splitFrontmatter(data []byte) (header, body []byte, err error) {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	rest, ok := bytes.CutPrefix(data, []byte("---\n"))
+	if !ok {
+		return nil, nil, fmt.Errorf("no frontmatter")
+	}
+	if bytes.HasPrefix(rest, []byte("---\n")) {
+		return nil, rest[4:], nil
+	}
+	i := bytes.Index(rest, []byte("\n---\n"))
+	if i < 0 {
+		if !bytes.HasSuffix(rest, []byte("\n---")) {
+			return nil, nil, fmt.Errorf("unterminated frontmatter")
+		}
+		return rest[:len(rest)-3], nil, nil
+	}
+	return rest[:i+1], rest[i+5:], nil
+}
+
+func getKey(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setKey sets key in mapping m, appending it if new.
+func setKey(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+func deleteKey(m *yaml.Node, key string) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = slices.Delete(m.Content, i, i+2)
+			return
+		}
+	}
+}
+
+// writeFileAtomic replaces path with data, so a crash never leaves a
+// truncated bean.
+func writeFileAtomic(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".bean-*")
+	if err != nil {
+		return fmt.Errorf("writing bean: %w", err)
+	}
+	_, werr := tmp.Write(data)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("writing bean: %w", errors.Join(werr, cerr))
+	}
+	_ = os.Chmod(tmp.Name(), info.Mode().Perm())
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("writing bean: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package beans
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sampleBean = `---
+# bup-0yu8
+title: Migrate sync state
+status: in-progress
+type: feature
+priority: high
+tags: [sync, state]
+created_at: 2026-02-08T22:18:41Z
+updated_at: 2026-02-08T23:56:53Z
+due: 2026-03-01
+parent: bup-epic
+blocking:
+    - bup-next
+---
+
+Replace syncstate.Store.
+
+- [ ] Migrate
+`
+
+func TestDir(t *testing.T) {
+	path := t.TempDir()
+	file := filepath.Join(path, "bup-0yu8--migrate-sync-state.md")
+	if err := os.WriteFile(file, []byte(sampleBean), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Mkdir(filepath.Join(path, "archive"), 0o755)
+	_ = os.WriteFile(filepath.Join(path, "archive", "bup-old--old.md"), []byte("---\ntitle: Old\n---\n"), 0o644)
+
+	dir := NewDir(path)
+	all, err := dir.List()
+	if err != nil || len(all) != 1 {
+		t.Fatalf("List() = %+v, %v", all, err)
+	}
+	b := all[0]
+	if b.ID != "bup-0yu8" || b.Slug != "migrate-sync-state" || b.Title != "Migrate sync state" || b.Status != "in-progress" ||
+		b.Priority != "high" || b.Parent != "bup-epic" || len(b.Blocking) != 1 || len(b.Tags) != 2 {
+		t.Errorf("bean = %+v", b)
+	}
+	if b.Due == nil || *b.Due != "2026-03-01" || b.UpdatedAt == nil || b.UpdatedAt.Minute() != 56 {
+		t.Errorf("due = %v, updated = %v", b.Due, b.UpdatedAt)
+	}
+	if b.Body != "Replace syncstate.Store.\n\n- [ ] Migrate" {
+		t.Errorf("body = %q", b.Body)
+	}
+
+	// Extension data is written into the frontmatter, leaving the rest alone
+	data := map[string]any{"task_id": "868hdrehu", "synced_at": "2026-02-08T23:56:52Z"}
+	if err := dir.SetExtensionData("bup-0yu8", PluginClickUp, data); err != nil {
+		t.Fatal(err)
+	}
+	got, err := dir.Get("bup-0yu8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetExtensionString(PluginClickUp, ExtKeyTaskID) != "868hdrehu" || got.GetExtensionTime(PluginClickUp, ExtKeySyncedAt) == nil {
+		t.Errorf("extensions = %v", got.Extensions)
+	}
+	written, _ := os.ReadFile(file)
+	for _, want := range []string{"# bup-0yu8\n", "tags: [sync, state]\n", "extensions:\n    clickup:\n        synced_at: \"2026-02-08T23:56:52Z\"\n", "---\n\nReplace syncstate.Store.\n\n- [ ] Migrate\n"} {
+		if !strings.Contains(string(written), want) {
+			t.Errorf("file lacks %q:\n%s", want, written)
+		}
+	}
+
+	// Removing the only extension drops the mapping
+	if err := dir.RemoveExtensionData("bup-0yu8", PluginClickUp); err != nil {
+		t.Fatal(err)
+	}
+	written, _ = os.ReadFile(file)
+	if strings.Contains(string(written), "extensions") {
+		t.Errorf("extensions left behind:\n%s", written)
+	}
+
+	// Updates change the field in place and bump updated_at
+	bug, low := "bug", "low"
+	if err := dir.Update("bup-0yu8", BeanUpdate{Type: &bug, Priority: &low}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ = dir.Get("bup-0yu8"); got.Type != "bug" || got.Priority != "low" || !got.UpdatedAt.After(*b.UpdatedAt) {
+		t.Errorf("after Update: type %q, priority %q, updated %v", got.Type, got.Priority, got.UpdatedAt)
+	}
+	written, _ = os.ReadFile(file)
+	if !strings.Contains(string(written), "status: in-progress\ntype: bug\npriority: low\n") || strings.Contains(string(written), "!!timestamp") {
+		t.Errorf("file after Update:\n%s", written)
+	}
+
+	done, due := "completed", "2026-04-01"
+	if err := dir.Update("bup-0yu8", BeanUpdate{Status: &done, Due: &due}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ = dir.Get("bup-0yu8"); got.Status != "completed" || got.Due == nil || *got.Due != due {
+		t.Errorf("after status Update: status %q, due %v", got.Status, got.Due)
+	}
+	if written, _ = os.ReadFile(file); !strings.Contains(string(written), "due: 2026-04-01\n") {
+		t.Errorf("file after due Update:\n%s", written)
+	}
+
+	body := "Replace syncstate.Store.\n\n![plan](assets/bup-0yu8/plan.png)"
+	if err := dir.Update("bup-0yu8", BeanUpdate{Body: &body}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ = dir.Get("bup-0yu8"); got.Body != body || got.Type != "bug" {
+		t.Errorf("after body Update: body %q, type %q", got.Body, got.Type)
+	}
+
+	if _, err := dir.Get("bup-none"); err == nil {
+		t.Error("Get() of a missing bean should fail")
+	}
+}
@@ -0,0 +1,248 @@
+package beans
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ListOptions narrows a listing. Filters are pushed down to the beans
+// GraphQL endpoint so unwanted beans never leave the beans process.
+type ListOptions struct {
+	// Status keeps only beans in these statuses; empty means any
+	Status []string
+	// ExcludeStatus drops beans in these statuses
+	ExcludeStatus []string
+	// Tags keeps only beans with at least one of these tags
+	Tags []string
+	// NoBody leaves Body empty; fetch it later with LoadBodies for the
+	// beans that need it
+	NoBody bool
+}
+
+// match reports whether b passes the filters.
+func (o ListOptions) match(b *Bean) bool {
+	if len(o.Status) > 0 && !slices.Contains(o.Status, b.Status) {
+		return false
+	}
+	if slices.Contains(o.ExcludeStatus, b.Status) {
+		return false
+	}
+	if len(o.Tags) > 0 && !slices.ContainsFunc(b.Tags, func(t string) bool { return slices.Contains(o.Tags, t) }) {
+		return false
+	}
+	return true
+}
+
+// gqlBeanFields are the fields selected for each bean, without the body.
+const gqlBeanFields = "id slug path title status type priority tags createdAt updatedAt due parentId blockingIds extensions"
+
+// gqlBean is a bean as the GraphQL endpoint returns it.
+type gqlBean struct {
+	ID          string                    `json:"id"`
+	Slug        string                    `json:"slug"`
+	Path        string                    `json:"path"`
+	Title       string                    `json:"title"`
+	Status      string                    `json:"status"`
+	Type        string                    `json:"type"`
+	Priority    string                    `json:"priority"`
+	Tags        []string                  `json:"tags"`
+	CreatedAt   *time.Time                `json:"createdAt"`
+	UpdatedAt   *time.Time                `json:"updatedAt"`
+	Due         *string                   `json:"due"`
+	Body        string                    `json:"body"`
+	ParentID    string                    `json:"parentId"`
+	BlockingIDs []string                  `json:"blockingIds"`
+	Extensions  map[string]map[string]any `json:"extensions"`
+}
+
+func (g *gqlBean) bean() Bean {
+	return Bean{
+		ID:         g.ID,
+		Slug:       g.Slug,
+		Path:       g.Path,
+		Title:      g.Title,
+		Status:     g.Status,
+		Type:       g.Type,
+		Priority:   g.Priority,
+		CreatedAt:  g.CreatedAt,
+		UpdatedAt:  g.UpdatedAt,
+		Body:       g.Body,
+		Parent:     g.ParentID,
+		Blocking:   g.BlockingIDs,
+		Due:        g.Due,
+		Tags:       g.Tags,
+		Extensions: g.Extensions,
+	}
+}
+
+// ListWith returns the beans matching opts. With the beans CLI it runs one
+// GraphQL query selecting only the fields beanup uses; a CLI whose schema
+// lacks them gets the full `beans list` filtered here instead.
+func (c *Client) ListWith(opts ListOptions) ([]Bean, error) {
+	if c.dir == nil {
+		if beans, err := c.queryBeans(opts); err == nil {
+			return beans, nil
+		}
+	}
+
+	all, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	var beans []Bean
+	for _, b := range all {
+		if opts.match(&b) {
+			beans = append(beans, b)
+		}
+	}
+	return beans, nil
+}
+
+// ListIDs returns the ID of every bean, whatever its status. It is a
+// cheap way to tell deleted beans from ones a filtered listing left out.
+func (c *Client) ListIDs() ([]string, error) {
+	if c.dir == nil {
+		var resp struct {
+			Beans []struct {
+				ID string `json:"id"`
+			} `json:"beans"`
+		}
+		if err := c.query("{ beans { id } }", &resp); err == nil {
+			ids := make([]string, len(resp.Beans))
+			for i, b := range resp.Beans {
+				ids[i] = b.ID
+			}
+			return ids, nil
+		}
+	}
+
+	all, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(all))
+	for i, b := range all {
+		ids[i] = b.ID
+	}
+	return ids, nil
+}
+
+// LoadBodies fills in the empty Body of each bean, e.g. after a NoBody
+// listing, in one query.
+func (c *Client) LoadBodies(all []Bean) error {
+	if c.dir != nil {
+		return nil // Dir always reads bodies
+	}
+	var beans []*Bean
+	for i := range all {
+		if all[i].Body == "" {
+			beans = append(beans, &all[i])
+		}
+	}
+	if len(beans) == 0 {
+		return nil
+	}
+
+	var q strings.Builder
+	q.WriteString("{")
+	for i, b := range beans {
+		fmt.Fprintf(&q, " b%d: bean(id: %s) { body }", i, gqlString(b.ID))
+	}
+	q.WriteString(" }")
+
+	var resp map[string]*struct {
+		Body string `json:"body"`
+	}
+	if err := c.query(q.String(), &resp); err != nil {
+		// Older CLIs: fall back to show, which returns full beans
+		ids := make([]string, len(beans))
+		for i, b := range beans {
+			ids[i] = b.ID
+		}
+		full, err := c.GetMultiple(ids)
+		if err != nil {
+			return err
+		}
+		for _, b := range beans {
+			if j := slices.IndexFunc(full, func(f Bean) bool { return f.ID == b.ID }); j >= 0 {
+				b.Body = full[j].Body
+			}
+		}
+		return nil
+	}
+	for i, b := range beans {
+		if r := resp[fmt.Sprintf("b%d", i)]; r != nil {
+			b.Body = r.Body
+		}
+	}
+	return nil
+}
+
+// queryBeans lists beans through the GraphQL endpoint.
+func (c *Client) queryBeans(opts ListOptions) ([]Bean, error) {
+	var filter []string
+	for _, f := range []struct {
+		name   string
+		values []string
+	}{
+		{"status", opts.Status},
+		{"excludeStatus", opts.ExcludeStatus},
+		{"tags", opts.Tags},
+	} {
+		if len(f.values) == 0 {
+			continue
+		}
+		quoted := make([]string, len(f.values))
+		for i, v := range f.values {
+			quoted[i] = gqlString(v)
+		}
+		filter = append(filter, fmt.Sprintf("%s: [%s]", f.name, strings.Join(quoted, ", ")))
+	}
+
+	fields := gqlBeanFields
+	if !opts.NoBody {
+		fields += " body"
+	}
+	args := ""
+	if len(filter) > 0 {
+		args = "(filter: {" + strings.Join(filter, ", ") + "})"
+	}
+
+	var resp struct {
+		Beans []gqlBean `json:"beans"`
+	}
+	if err := c.query(fmt.Sprintf("{ beans%s { %s } }", args, fields), &resp); err != nil {
+		return nil, err
+	}
+	beans := make([]Bean, len(resp.Beans))
+	for i := range resp.Beans {
+		beans[i] = resp.Beans[i].bean()
+	}
+	return beans, nil
+}
+
+// query runs a GraphQL query with `beans query` and decodes its data.
+func (c *Client) query(q string, result any) error {
+	args := []string{"query", "--json", q}
+	if c.beansPath != "" {
+		args = append(args, "--beans-path", c.beansPath)
+	}
+	out, err := c.exec(args...)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(out, result); err != nil {
+		return fmt.Errorf("parsing beans query result: %w", err)
+	}
+	return nil
+}
+
+// gqlString quotes s as a GraphQL string literal. JSON string escaping is
+// a subset of GraphQL's.
+func gqlString(s string) string {
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
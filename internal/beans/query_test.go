@@ -0,0 +1,34 @@
+package beans
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestListWithDir(t *testing.T) {
+	path := t.TempDir()
+	for name, status := range map[string]string{"a--one": "todo", "b--two": "completed", "c--three": "in-progress"} {
+		bean := "---\ntitle: " + name + "\nstatus: " + status + "\ntags: [" + status + "]\n---\n\nBody\n"
+		if err := os.WriteFile(filepath.Join(path, name+".md"), []byte(bean), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	NoCLI = true
+	defer func() { NoCLI = false }()
+	client := NewClient(path)
+
+	got, err := client.ListWith(ListOptions{ExcludeStatus: []string{"completed"}, NoBody: true})
+	if err != nil || len(got) != 2 || got[0].ID != "a" || got[1].ID != "c" {
+		t.Fatalf("ListWith(ExcludeStatus) = %+v, %v", got, err)
+	}
+	if err := client.LoadBodies(got); err != nil || got[0].Body != "Body" {
+		t.Errorf("LoadBodies() = %v, body %q", err, got[0].Body)
+	}
+
+	got, err = client.ListWith(ListOptions{Tags: []string{"in-progress"}})
+	if err != nil || len(got) != 1 || got[0].ID != "c" {
+		t.Errorf("ListWith(Tags) = %+v, %v", got, err)
+	}
+}
@@ -1,4 +1,5 @@
-// Package beans provides a wrapper for the beans CLI.
+// Package beans provides a wrapper for the beans CLI, with a direct reader
+// of the beans directory for machines without it.
 package beans
 
 import (
@@ -17,9 +18,14 @@ const (
 
 // Extension metadata constants
 const (
-	PluginClickUp = "clickup"
-	ExtKeyTaskID  = "task_id"
+	PluginClickUp  = "clickup"
+	ExtKeyTaskID   = "task_id"
 	ExtKeySyncedAt = "synced_at"
+	ExtKeyTaskURL  = "task_url"
+	ExtKeySprint   = "sprint"
+	ExtKeyAssignee = "assignee"
+	ExtKeyReview   = "review"
+	ExtKeyProgress = "progress"
 )
 
 // StandardTypes is the list of all standard bean types.
@@ -32,21 +38,21 @@ func IsStandardType(t string) bool {
 
 // Bean represents a bean from the beans CLI JSON output.
 type Bean struct {
-	ID        string                        `json:"id"`
-	Slug      string                        `json:"slug"`
-	Path      string                        `json:"path"`
-	Title     string                        `json:"title"`
-	Status    string                        `json:"status"`
-	Type      string                        `json:"type"`
-	Priority  string                        `json:"priority,omitempty"`
-	CreatedAt *time.Time                    `json:"created_at,omitempty"`
-	UpdatedAt *time.Time                    `json:"updated_at,omitempty"`
-	Body      string                        `json:"body,omitempty"`
-	Parent    string                        `json:"parent,omitempty"`
-	Blocking  []string                      `json:"blocking,omitempty"`
-	Due       *string                        `json:"due,omitempty"`
-	Tags      []string                      `json:"tags,omitempty"`
-	Extensions map[string]map[string]any    `json:"extensions,omitempty"`
+	ID         string                    `json:"id"`
+	Slug       string                    `json:"slug"`
+	Path       string                    `json:"path"`
+	Title      string                    `json:"title"`
+	Status     string                    `json:"status"`
+	Type       string                    `json:"type"`
+	Priority   string                    `json:"priority,omitempty"`
+	CreatedAt  *time.Time                `json:"created_at,omitempty"`
+	UpdatedAt  *time.Time                `json:"updated_at,omitempty"`
+	Body       string                    `json:"body,omitempty"`
+	Parent     string                    `json:"parent,omitempty"`
+	Blocking   []string                  `json:"blocking,omitempty"`
+	Due        *string                   `json:"due,omitempty"`
+	Tags       []string                  `json:"tags,omitempty"`
+	Extensions map[string]map[string]any `json:"extensions,omitempty"`
 }
 
 // GetExtensionString returns a string value from extension data.
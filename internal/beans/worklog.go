@@ -0,0 +1,86 @@
+package beans
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WorklogEntry is time spent on a bean, recorded in its body under a
+// worklog: line as "- <date> <duration> [note]", e.g.
+//
+//	worklog:
+//	- 2026-03-01 1h30m Reproduced the bug
+//	- 2026-03-02 45m
+type WorklogEntry struct {
+	Date     time.Time // Day the work was done, at midnight UTC
+	Duration time.Duration
+	Note     string
+
+	// repeat counts the identical entries before this one on the bean, so
+	// two equal sessions on a day hash differently
+	repeat int
+}
+
+// Hash identifies the entry on the bean, so a tracker can tell which
+// entries it already has. Editing an entry gives it a new hash, and
+// repeated identical lines each get their own.
+func (e WorklogEntry) Hash(beanID string) string {
+	parts := []string{beanID, e.Date.Format(time.DateOnly), e.Duration.String(), e.Note}
+	// The first of identical entries keeps the hash it had before repeats
+	// were counted
+	if e.repeat > 0 {
+		parts = append(parts, strconv.Itoa(e.repeat))
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:6])
+}
+
+// Worklog returns the entries in the bean's worklog: sections. Lines that
+// don't parse are ignored.
+func (b *Bean) Worklog() []WorklogEntry {
+	var entries []WorklogEntry
+	seen := make(map[WorklogEntry]int)
+	inLog := false
+	for line := range strings.Lines(b.Body) {
+		line = strings.TrimSpace(line)
+		if strings.EqualFold(line, "worklog:") {
+			inLog = true
+			continue
+		}
+		item, ok := strings.CutPrefix(line, "- ")
+		if !inLog || !ok {
+			inLog = inLog && line == ""
+			continue
+		}
+		if e, ok := parseWorklogEntry(item); ok {
+			key := e
+			e.repeat = seen[key]
+			seen[key]++
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+func parseWorklogEntry(item string) (WorklogEntry, bool) {
+	fields := strings.SplitN(strings.TrimSpace(item), " ", 3)
+	if len(fields) < 2 {
+		return WorklogEntry{}, false
+	}
+	date, err := time.Parse(time.DateOnly, fields[0])
+	if err != nil {
+		return WorklogEntry{}, false
+	}
+	d, err := time.ParseDuration(fields[1])
+	if err != nil || d <= 0 {
+		return WorklogEntry{}, false
+	}
+	e := WorklogEntry{Date: date, Duration: d}
+	if len(fields) == 3 {
+		e.Note = strings.TrimSpace(fields[2])
+	}
+	return e, true
+}
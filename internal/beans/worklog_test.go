@@ -0,0 +1,44 @@
+package beans
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWorklog(t *testing.T) {
+	b := &Bean{ID: "bup-1", Body: `Fix the parser.
+
+worklog:
+- 2026-03-01 1h30m Reproduced the bug
+- 2026-03-02 45m
+
+- yesterday 2h not a date
+- 2026-03-03 soon not a duration
+
+## Notes
+- 2026-03-04 1h not in a worklog
+`}
+	entries := b.Worklog()
+	if len(entries) != 2 {
+		t.Fatalf("Worklog() = %+v, want 2 entries", entries)
+	}
+	if e := entries[0]; e.Date.Format(time.DateOnly) != "2026-03-01" || e.Duration != 90*time.Minute || e.Note != "Reproduced the bug" {
+		t.Errorf("first entry = %+v", e)
+	}
+	if e := entries[1]; e.Duration != 45*time.Minute || e.Note != "" {
+		t.Errorf("second entry = %+v", e)
+	}
+
+	if entries[0].Hash("bup-1") == entries[0].Hash("bup-2") || entries[0].Hash("bup-1") == entries[1].Hash("bup-1") {
+		t.Error("hashes should differ by bean and entry")
+	}
+
+	repeated := (&Bean{Body: "worklog:\n- 2026-03-01 1h\n- 2026-03-01 1h\n- 2026-03-01 1h\n"}).Worklog()
+	hashes := map[string]bool{}
+	for _, e := range repeated {
+		hashes[e.Hash("bup-1")] = true
+	}
+	if len(repeated) != 3 || len(hashes) != 3 {
+		t.Errorf("%d identical entries gave %d distinct hashes, want 3", len(repeated), len(hashes))
+	}
+}
@@ -0,0 +1,267 @@
+// Package cassette records HTTP interactions to a file and replays them,
+// so a user's sync run can be reproduced without their account. Recorded
+// interactions are sanitized: credentials and personal fields never reach
+// the file.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Doer sends HTTP requests, like *http.Client.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Interaction is one recorded request and its response.
+type Interaction struct {
+	Method   string      `json:"method"`
+	URL      string      `json:"url"`
+	Body     string      `json:"body,omitempty"`
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header,omitempty"`
+	Response string      `json:"response"`
+}
+
+// Cassette is an ordered list of interactions. It is safe for concurrent
+// use.
+type Cassette struct {
+	mu           sync.Mutex
+	Interactions []Interaction `json:"interactions"`
+	used         []bool
+	secrets      []string
+}
+
+// New returns an empty cassette for recording. secrets are replaced
+// wherever they appear in recorded URLs and bodies; the Authorization
+// header of each request is added to them automatically and never stored.
+func New(secrets ...string) *Cassette {
+	c := &Cassette{}
+	for _, s := range secrets {
+		if s != "" {
+			c.secrets = append(c.secrets, s)
+		}
+	}
+	return c
+}
+
+// Load reads a cassette for replay.
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading cassette: %w", err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parsing cassette %s: %w", path, err)
+	}
+	c.used = make([]bool, len(c.Interactions))
+	return &c, nil
+}
+
+// Save writes the recorded interactions to path.
+func (c *Cassette) Save(path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding cassette: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("writing cassette: %w", err)
+	}
+	return nil
+}
+
+// Len returns the number of interactions.
+func (c *Cassette) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.Interactions)
+}
+
+// Recorder returns a Doer that sends requests through next and records
+// each exchange.
+func (c *Cassette) Recorder(next Doer) Doer {
+	return &recorder{next: next, cassette: c}
+}
+
+// Replayer returns a Doer that answers requests from the cassette without
+// any network access. Each request gets the first unused interaction with
+// the same method and URL; a request with none left fails.
+func (c *Cassette) Replayer() Doer {
+	return replayer{c}
+}
+
+type recorder struct {
+	next     Doer
+	cassette *Cassette
+}
+
+func (r *recorder) Do(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := r.next.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	c := r.cassette
+	c.mu.Lock()
+	if auth := req.Header.Get("Authorization"); auth != "" && !slices.Contains(c.secrets, auth) {
+		c.secrets = append(c.secrets, auth)
+	}
+	c.Interactions = append(c.Interactions, Interaction{
+		Method:   req.Method,
+		URL:      c.redact(req.URL.String()),
+		Body:     c.sanitize(reqBody),
+		Status:   resp.StatusCode,
+		Header:   keptHeaders(resp.Header),
+		Response: c.sanitize(respBody),
+	})
+	c.mu.Unlock()
+	return resp, nil
+}
+
+type replayer struct {
+	cassette *Cassette
+}
+
+func (r replayer) Do(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	c := r.cassette
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	url := req.URL.String()
+	for i, in := range c.Interactions {
+		if c.used[i] || in.Method != req.Method || in.URL != url {
+			continue
+		}
+		c.used[i] = true
+		return &http.Response{
+			Status:     fmt.Sprintf("%d %s", in.Status, http.StatusText(in.Status)),
+			StatusCode: in.Status,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     in.Header.Clone(),
+			Body:       io.NopCloser(strings.NewReader(in.Response)),
+			Request:    req,
+		}, nil
+	}
+	return nil, fmt.Errorf("cassette: no recorded response for %s %s", req.Method, url)
+}
+
+// Unused returns the interactions a replay never requested, e.g. to assert
+// that a test exercised the whole cassette.
+func (c *Cassette) Unused() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var unused []Interaction
+	for i, in := range c.Interactions {
+		if !c.used[i] {
+			unused = append(unused, in)
+		}
+	}
+	return unused
+}
+
+// keptHeaders are the response headers worth replaying; the rest (cookies,
+// request IDs, CDN headers) are dropped.
+func keptHeaders(h http.Header) http.Header {
+	kept := make(http.Header)
+	for _, name := range []string{"Content-Type", "ETag", "Last-Modified", "Cache-Control", "Retry-After",
+		"X-Ratelimit-Limit", "X-Ratelimit-Remaining", "X-Ratelimit-Reset"} {
+		if v := h.Values(name); len(v) > 0 {
+			kept[name] = v
+		}
+	}
+	return kept
+}
+
+// personalKeys are JSON fields replaced in recorded bodies.
+var personalKeys = map[string]bool{
+	"email":          true,
+	"username":       true,
+	"initials":       true,
+	"profilePicture": true,
+	"access_token":   true,
+	"client_secret":  true,
+}
+
+// Redacted replaces sanitized values.
+const Redacted = "REDACTED"
+
+// sanitize returns body with secrets and personal fields redacted. JSON
+// bodies are rewritten field by field; others only have secrets replaced.
+func (c *Cassette) sanitize(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	// Numbers stay as written so large IDs keep their precision
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return c.redact(string(body))
+	}
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(redactJSON(v)); err != nil {
+		return c.redact(string(body))
+	}
+	return c.redact(strings.TrimSuffix(out.String(), "\n"))
+}
+
+// redact replaces every secret in s.
+func (c *Cassette) redact(s string) string {
+	for _, secret := range c.secrets {
+		s = strings.ReplaceAll(s, secret, Redacted)
+	}
+	return s
+}
+
+func redactJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if personalKeys[k] {
+				if _, isString := child.(string); isString {
+					v[k] = Redacted
+					continue
+				}
+			}
+			v[k] = redactJSON(child)
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = redactJSON(child)
+		}
+	}
+	return v
+}
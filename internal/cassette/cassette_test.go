@@ -0,0 +1,65 @@
+package cassette
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		_, _ = w.Write([]byte(`{"user":{"id":9007199254740993,"username":"ana","email":"ana@example.com"},"note":"a<b"}`))
+	}))
+	defer server.Close()
+
+	rec := New("pk_secret")
+	client := rec.Recorder(http.DefaultClient)
+	req, _ := http.NewRequest("POST", server.URL+"/user", strings.NewReader(`{"token":"pk_secret"}`))
+	req.Header.Set("Authorization", "pk_header")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "ana@example.com") {
+		t.Errorf("caller got the sanitized body: %s", body)
+	}
+
+	path := filepath.Join(t.TempDir(), "run.json")
+	if err := rec.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	cas, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := cas.Interactions[0]
+	for _, leak := range []string{"ana", "pk_secret", "session"} {
+		if strings.Contains(in.Body+in.Response+strings.Join(in.Header.Values("Set-Cookie"), ""), leak) {
+			t.Errorf("cassette leaks %q: %+v", leak, in)
+		}
+	}
+	if !strings.Contains(in.Response, "9007199254740993") || !strings.Contains(in.Response, "a<b") {
+		t.Errorf("response not preserved: %s", in.Response)
+	}
+
+	// Replay answers the recorded request once, without the server
+	server.Close()
+	replay := cas.Replayer()
+	req, _ = http.NewRequest("POST", in.URL, nil)
+	resp, err = replay.Do(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("replay = %v, %v", resp, err)
+	}
+	if len(cas.Unused()) != 0 {
+		t.Errorf("Unused() = %v after replay", cas.Unused())
+	}
+	if _, err := replay.Do(req); err == nil {
+		t.Error("replaying past the cassette should fail")
+	}
+}
@@ -0,0 +1,231 @@
+package clickup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// WithResponseCache caches GET responses in dir and revalidates them with
+// If-None-Match / If-Modified-Since, so an unchanged resource costs a 304
+// instead of a full body. Responses with Cache-Control max-age are reused
+// without a request while fresh. An empty dir disables the cache.
+func WithResponseCache(dir string) ClientOption {
+	return func(c *Client) { c.cacheDir = dir }
+}
+
+// DefaultMetadataTTL is how long list, custom field, task type, workspace,
+// and user metadata is reused without asking ClickUp.
+const DefaultMetadataTTL = time.Hour
+
+// WithMetadataTTL sets how long cached metadata responses are used without
+// a request; 0 always revalidates them. It applies only with
+// WithResponseCache.
+func WithMetadataTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) { c.metadataTTL = &ttl }
+}
+
+// metadataPathRe matches the endpoints for metadata that rarely changes:
+// a list (with its statuses), its custom fields, workspaces, custom task
+// types, and the authorized user.
+var metadataPathRe = regexp.MustCompile(`/(list/[^/]+(/field)?|team|team/[^/]+/custom_item|user)$`)
+
+// HTTPCacheDir returns the default response cache directory, beanup/http
+// under os.UserCacheDir (~/.cache on Linux).
+func HTTPCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "beanup", "http"), nil
+}
+
+// cacheEntry is a cached GET response on disk.
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	CacheControl string    `json:"cache_control,omitempty"`
+	Stored       time.Time `json:"stored"`
+	Body         []byte    `json:"body"`
+}
+
+// cachingDoer serves GETs from an on-disk cache, revalidating them with
+// conditional requests. Other methods pass straight through.
+type cachingDoer struct {
+	next HTTPDoer
+	dir  string
+	now  func() time.Time
+	ttl  time.Duration // Freshness of metadata responses
+
+	// Set after the first write; from then on cached responses are always
+	// revalidated, so reads see this run's own changes
+	mutated atomic.Bool
+}
+
+func (d *cachingDoer) Do(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		// A write makes the cached read of the same URL stale, e.g. a
+		// new custom field on a list
+		d.mutated.Store(true)
+		_ = os.Remove(d.path(req))
+		return d.next.Do(req)
+	}
+
+	path := d.path(req)
+	entry := d.load(path)
+	if entry != nil {
+		if !d.mutated.Load() && d.now().Before(d.expires(req, entry)) {
+			return cachedResponse(req, entry), nil
+		}
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := d.next.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		_ = resp.Body.Close()
+		entry.CacheControl = resp.Header.Get("Cache-Control")
+		entry.Stored = d.now()
+		d.store(path, entry)
+		return cachedResponse(req, entry), nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	fresh := &cacheEntry{
+		URL:          req.URL.String(),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		CacheControl: resp.Header.Get("Cache-Control"),
+		Stored:       d.now(),
+	}
+	if fresh.ETag == "" && fresh.LastModified == "" && d.expires(req, fresh).IsZero() ||
+		strings.Contains(fresh.CacheControl, "no-store") {
+		return resp, nil // Nothing to revalidate with
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	fresh.Body = body
+	d.store(path, fresh)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// path is the cache file for a request. The key includes the token so
+// accounts sharing a machine never see each other's responses.
+func (d *cachingDoer) path(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Header.Get("Authorization") + "\n" + req.URL.String()))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// load returns the cached entry at path, or nil. Unreadable entries are
+// treated as misses.
+func (d *cachingDoer) load(path string) *cacheEntry {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+	return &entry
+}
+
+// store writes entry to path. The cache is best-effort; failures only mean
+// the next request isn't conditional.
+func (d *cachingDoer) store(path string, entry *cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(d.dir, 0o700); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(d.dir, ".entry-*")
+	if err != nil {
+		return
+	}
+	_, werr := tmp.Write(data)
+	if cerr := tmp.Close(); werr != nil || cerr != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+// expires returns when a cached response stops being fresh: its
+// Cache-Control max-age, or else the metadata TTL for metadata endpoints.
+// It is evaluated on use, so a changed cache_ttl applies to old entries.
+func (d *cachingDoer) expires(req *http.Request, entry *cacheEntry) time.Time {
+	if entry.CacheControl != "" {
+		return maxAge(entry.CacheControl, entry.Stored)
+	}
+	if d.ttl > 0 && req.URL.RawQuery == "" && metadataPathRe.MatchString(req.URL.Path) {
+		return entry.Stored.Add(d.ttl)
+	}
+	return time.Time{}
+}
+
+// maxAge returns when a response stored at stored stops being fresh under
+// the Cache-Control header value cc, or zero if it must be revalidated.
+func maxAge(cc string, stored time.Time) time.Time {
+	var seconds int
+	for directive := range strings.SplitSeq(cc, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "no-cache", "no-store":
+			return time.Time{}
+		case "max-age":
+			seconds, _ = strconv.Atoi(value)
+		}
+	}
+	if seconds <= 0 {
+		return time.Time{}
+	}
+	return stored.Add(time.Duration(seconds) * time.Second)
+}
+
+// cachedResponse builds a 200 response from a cache entry.
+func cachedResponse(req *http.Request, entry *cacheEntry) *http.Response {
+	h := make(http.Header)
+	h.Set("Content-Type", "application/json")
+	if entry.ETag != "" {
+		h.Set("ETag", entry.ETag)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h,
+		Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+	}
+}
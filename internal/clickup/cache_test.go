@@ -0,0 +1,117 @@
+package clickup
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponseCache(t *testing.T) {
+	var full, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/task/t1":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			full++
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write([]byte(`{"id":"t1","name":"Cached"}`))
+		case "/list/l1":
+			full++
+			w.Header().Set("Cache-Control", "max-age=60")
+			_, _ = w.Write([]byte(`{"id":"l1","name":"Sprint"}`))
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	newClient := func() *Client {
+		return NewClient("token", WithBaseURL(server.URL), WithResponseCache(dir))
+	}
+	ctx := context.Background()
+
+	// A second run revalidates the task and reuses the cached body
+	for range 2 {
+		task, err := newClient().GetTask(ctx, "t1")
+		if err != nil || task.Name != "Cached" {
+			t.Fatalf("GetTask() = %+v, %v", task, err)
+		}
+	}
+	if full != 1 || notModified != 1 {
+		t.Errorf("task: %d full and %d not-modified responses, want 1 and 1", full, notModified)
+	}
+
+	// A fresh max-age response isn't requested again
+	for range 2 {
+		if list, err := newClient().GetList(ctx, "l1"); err != nil || list.Name != "Sprint" {
+			t.Fatalf("GetList() = %+v, %v", list, err)
+		}
+	}
+	if full != 2 {
+		t.Errorf("%d full responses, want the list fetched once", full)
+	}
+
+	// Other tokens don't share entries
+	if _, err := NewClient("other", WithBaseURL(server.URL), WithResponseCache(dir)).GetTask(ctx, "t1"); err != nil {
+		t.Fatal(err)
+	}
+	if full != 3 {
+		t.Errorf("%d full responses, want a miss for another token", full)
+	}
+}
+
+func TestMetadataTTL(t *testing.T) {
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.Method+" "+r.URL.Path]++
+		switch r.URL.Path {
+		case "/list/l1":
+			_, _ = w.Write([]byte(`{"id":"l1","name":"Sprint"}`))
+		case "/list/l1/field":
+			_, _ = w.Write([]byte(`{"fields":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	newClient := func(opts ...ClientOption) *Client {
+		return NewClient("token", append([]ClientOption{WithBaseURL(server.URL), WithResponseCache(dir)}, opts...)...)
+	}
+
+	// List metadata is reused across runs without validators
+	for range 2 {
+		if _, err := newClient().GetList(ctx, "l1"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := newClient().GetAccessibleCustomFields(ctx, "l1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if requests["GET /list/l1"] != 1 || requests["GET /list/l1/field"] != 1 {
+		t.Errorf("requests = %v, want one GET each", requests)
+	}
+
+	// A TTL of zero always asks again
+	if _, err := newClient(WithMetadataTTL(0)).GetList(ctx, "l1"); err != nil {
+		t.Fatal(err)
+	}
+	if requests["GET /list/l1"] != 2 {
+		t.Errorf("GET /list/l1 sent %d times with no TTL, want 2", requests["GET /list/l1"])
+	}
+
+	// Creating a field drops the cached field list
+	if _, err := newClient().CreateCustomField(ctx, "l1", &CreateFieldRequest{Name: "Bean ID", Type: "text"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newClient().GetAccessibleCustomFields(ctx, "l1"); err != nil {
+		t.Fatal(err)
+	}
+	if requests["GET /list/l1/field"] != 2 {
+		t.Errorf("GET /list/l1/field sent %d times after a write, want 2", requests["GET /list/l1/field"])
+	}
+}
@@ -0,0 +1,36 @@
+package clickup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/cassette"
+	"github.com/toba/bean-me-up/internal/config"
+)
+
+// TestReplayRenameTask replays a recorded sync of a renamed bean; cassettes
+// recorded with --record for bug reports are replayed the same way.
+func TestReplayRenameTask(t *testing.T) {
+	cas, err := cassette.Load("testdata/cassettes/rename_task.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := NewSink(NewClient("token", WithHTTPClient(cas.Replayer())), &config.ClickUpConfig{}, "list")
+	ctx := context.Background()
+
+	task, err := sink.GetTask(ctx, "86abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated, changed, err := sink.UpdateTask(ctx, task, &beans.Bean{ID: "bean-1", Title: "New title"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed || updated.ID != "86abc" {
+		t.Errorf("UpdateTask() = %+v, changed %v", updated, changed)
+	}
+	if unused := cas.Unused(); len(unused) != 0 {
+		t.Errorf("requests not made: %+v", unused)
+	}
+}
@@ -0,0 +1,171 @@
+package clickup
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/toba/bean-me-up/internal/beans"
+)
+
+// Checklist is a checklist on a task.
+type Checklist struct {
+	ID         string          `json:"id"`
+	Name       string          `json:"name"`
+	OrderIndex float64         `json:"orderindex"`
+	Items      []ChecklistItem `json:"items"`
+}
+
+// ChecklistItem is one item of a checklist.
+type ChecklistItem struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Resolved   bool    `json:"resolved"`
+	OrderIndex float64 `json:"orderindex"`
+}
+
+// headingRe matches an ATX markdown heading, capturing its level and text.
+var headingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+
+// taskItemRe matches a markdown task list item, capturing its check mark.
+var taskItemRe = regexp.MustCompile(`^\s*[-*+]\s+\[([ xX])\]\s`)
+
+// ChecklistDiff compares the task's checklists with the bean body section
+// named by checklist_section, returning the diff as "done/total" counts and
+// the bean body with the section rewritten from the task. Without
+// checklist_section nothing is compared.
+func (s *Sink) ChecklistDiff(b *beans.Bean, task *TaskInfo) (FieldDiff, string) {
+	d := FieldDiff{Field: "checklist"}
+	heading := s.config.ChecklistSection
+	if heading == "" {
+		return d, b.Body
+	}
+	body := replaceSection(b.Body, heading, func(level int) string {
+		return checklistMarkdown(task.Checklists, min(level+1, 6))
+	})
+	d.Bean = taskListSummary(sectionContent(b.Body, heading))
+	d.Task = taskListSummary(sectionContent(body, heading))
+	d.Changed = body != b.Body
+	return d, body
+}
+
+// checklistMarkdown renders checklists as markdown task lists, in ClickUp's
+// order. Several checklists each get a heading at level.
+func checklistMarkdown(lists []Checklist, level int) string {
+	lists = slices.SortedStableFunc(slices.Values(lists), func(a, b Checklist) int {
+		return cmp.Compare(a.OrderIndex, b.OrderIndex)
+	})
+	var sb strings.Builder
+	for i, list := range lists {
+		if len(lists) > 1 {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			fmt.Fprintf(&sb, "%s %s\n\n", strings.Repeat("#", level), list.Name)
+		}
+		items := slices.SortedStableFunc(slices.Values(list.Items), func(a, b ChecklistItem) int {
+			return cmp.Compare(a.OrderIndex, b.OrderIndex)
+		})
+		for _, item := range items {
+			mark := " "
+			if item.Resolved {
+				mark = "x"
+			}
+			fmt.Fprintf(&sb, "- [%s] %s\n", mark, item.Name)
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// findSection returns the line range of the body of the section under the
+// heading named heading (case-insensitively), up to the next heading of the
+// same or a higher level, and the heading's level. ok is false if there's
+// no such heading outside code fences.
+func findSection(lines []string, heading string) (start, end, level int, ok bool) {
+	mask := fenceMask(lines)
+	for i, line := range lines {
+		if mask[i] {
+			continue
+		}
+		m := headingRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if ok {
+			if len(m[1]) <= level {
+				return start, i, level, true
+			}
+			continue
+		}
+		if strings.EqualFold(m[2], strings.TrimSpace(heading)) {
+			start, level, ok = i+1, len(m[1]), true
+		}
+	}
+	return start, len(lines), level, ok
+}
+
+// sectionContent returns the body of the section under heading, or "" if
+// there's none.
+func sectionContent(body, heading string) string {
+	lines := strings.Split(body, "\n")
+	start, end, _, ok := findSection(lines, heading)
+	if !ok {
+		return ""
+	}
+	return strings.Join(lines[start:end], "\n")
+}
+
+// replaceSection returns body with the section under heading holding the
+// markdown content returns for the heading's level. A missing section is
+// added at the end as a level 2 heading, unless content is empty.
+func replaceSection(body, heading string, content func(level int) string) string {
+	lines := strings.Split(body, "\n")
+	start, end, level, ok := findSection(lines, heading)
+	if !ok {
+		text := content(2)
+		if text == "" {
+			return body
+		}
+		section := "## " + heading + "\n\n" + text
+		if strings.TrimSpace(body) == "" {
+			return section
+		}
+		return strings.TrimRight(body, "\n") + "\n\n" + section
+	}
+
+	out := slices.Clone(lines[:start])
+	out = append(out, "")
+	if text := content(level); text != "" {
+		out = append(out, strings.Split(text, "\n")...)
+		out = append(out, "")
+	}
+	if end < len(lines) {
+		out = append(out, lines[end:]...)
+	}
+	return strings.Trim(strings.Join(out, "\n"), "\n")
+}
+
+// taskListSummary counts the task list items in markdown as "done/total",
+// or returns "" if there are none.
+func taskListSummary(markdown string) string {
+	done, total := taskListCounts(markdown)
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d done", done, total)
+}
+
+// taskListCounts counts the task list items in markdown, and those checked.
+func taskListCounts(markdown string) (done, total int) {
+	for line := range strings.SplitSeq(markdown, "\n") {
+		if m := taskItemRe.FindStringSubmatch(line); m != nil {
+			total++
+			if m[1] != " " {
+				done++
+			}
+		}
+	}
+	return done, total
+}
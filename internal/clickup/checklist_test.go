@@ -0,0 +1,69 @@
+package clickup
+
+import (
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+)
+
+func TestChecklistDiff(t *testing.T) {
+	sink := NewSink(nil, &config.ClickUpConfig{ChecklistSection: "Checklist"}, "test-list")
+	task := &TaskInfo{Checklists: []Checklist{{Name: "Release", Items: []ChecklistItem{
+		{Name: "Tag", Resolved: true, OrderIndex: 1},
+		{Name: "Build", OrderIndex: 0},
+	}}}}
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"added at the end", "Intro", "Intro\n\n## Checklist\n\n- [ ] Build\n- [x] Tag"},
+		{"empty body", "", "## Checklist\n\n- [ ] Build\n- [x] Tag"},
+		{
+			"replaced up to the next heading",
+			"# Plan\n\n### checklist\n- [ ] Old\n### Notes\nKeep",
+			"# Plan\n\n### checklist\n\n- [ ] Build\n- [x] Tag\n\n### Notes\nKeep",
+		},
+		{
+			"subsections are part of it",
+			"## Checklist\n\n- [ ] Old\n\n### Detail\n\nGone\n\n## Notes",
+			"## Checklist\n\n- [ ] Build\n- [x] Tag\n\n## Notes",
+		},
+		{
+			"headings in code don't count",
+			"```\n## Checklist\n```",
+			"```\n## Checklist\n```\n\n## Checklist\n\n- [ ] Build\n- [x] Tag",
+		},
+	}
+	for _, tt := range tests {
+		d, body := sink.ChecklistDiff(&beans.Bean{Body: tt.body}, task)
+		if body != tt.want {
+			t.Errorf("%s: body = %q, want %q", tt.name, body, tt.want)
+		}
+		if !d.Changed || d.Task != "1/2 done" {
+			t.Errorf("%s: diff = %+v", tt.name, d)
+		}
+	}
+
+	// Pulling again changes nothing
+	d, _ := sink.ChecklistDiff(&beans.Bean{Body: tests[0].want}, task)
+	if d.Changed || d.Bean != "1/2 done" {
+		t.Errorf("second pull diff = %+v", d)
+	}
+
+	// Several checklists get their own headings, one level down
+	task.Checklists = append(task.Checklists, Checklist{Name: "Docs", OrderIndex: 1, Items: []ChecklistItem{{Name: "README", Resolved: true}}})
+	if _, body := sink.ChecklistDiff(&beans.Bean{Body: "## Checklist"}, task); body != "## Checklist\n\n### Release\n\n- [ ] Build\n- [x] Tag\n\n### Docs\n\n- [x] README" {
+		t.Errorf("several checklists = %q", body)
+	}
+
+	// Without checklist_section, and with no checklists to add, nothing changes
+	if d, _ := NewSink(nil, &config.ClickUpConfig{}, "test-list").ChecklistDiff(&beans.Bean{Body: "x"}, task); d.Changed {
+		t.Errorf("unconfigured diff = %+v", d)
+	}
+	if d, _ := sink.ChecklistDiff(&beans.Bean{Body: "x"}, &TaskInfo{}); d.Changed {
+		t.Errorf("no checklists diff = %+v", d)
+	}
+}
@@ -4,15 +4,24 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math/rand/v2"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/toba/bean-me-up/internal/cassette"
+	"github.com/toba/bean-me-up/internal/syncer"
+	"github.com/toba/bean-me-up/internal/tracing"
 )
 
-const baseURL = "https://api.clickup.com/api/v2"
+// DefaultBaseURL is the ClickUp REST API v2 endpoint.
+const DefaultBaseURL = "https://api.clickup.com/api/v2"
 
 // Default retry configuration for rate limit handling
 const (
@@ -21,6 +30,10 @@ const (
 	defaultMaxRetryDelay  = 30 * time.Second
 )
 
+// DefaultTimeout bounds each request attempt unless WithTimeout says
+// otherwise.
+const DefaultTimeout = 30 * time.Second
+
 // RateLimitError represents a ClickUp rate limit error.
 type RateLimitError struct {
 	Message string
@@ -34,12 +47,16 @@ func (e *RateLimitError) Error() string {
 // TransientError represents a transient error that can be retried.
 type TransientError struct {
 	Message string
+	// Err is the network error, if the request got no response
+	Err error
 }
 
 func (e *TransientError) Error() string {
 	return fmt.Sprintf("transient error: %s", e.Message)
 }
 
+func (e *TransientError) Unwrap() error { return e.Err }
+
 // RetryConfig holds retry settings for rate limit handling.
 type RetryConfig struct {
 	MaxRetries     int
@@ -47,26 +64,55 @@ type RetryConfig struct {
 	MaxRetryDelay  time.Duration
 }
 
+// HTTPDoer sends HTTP requests. *http.Client satisfies it; supply your own
+// to add instrumentation, proxies, or canned responses in tests.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
 // Client provides ClickUp API access via REST.
 type Client struct {
 	token      string
-	httpClient *http.Client
+	httpClient HTTPDoer
+	baseURL    string
 
 	// Retry configuration (uses defaults if nil)
 	retryConfig *RetryConfig
+	// Settings of the default transport; see WithTimeout and WithMaxIdleConns
+	timeout      *time.Duration
+	maxIdleConns int
+
+	// Response cache directory, empty for none (see WithResponseCache)
+	cacheDir string
+	// Metadata freshness; nil means DefaultMetadataTTL
+	metadataTTL *time.Duration
+	// Records every request and response, outside the cache
+	recorder *cassette.Cassette
+	// Called after each API call (see WithRequestHook)
+	requestHook func(RequestEvent)
 
 	// Cached list info
 	listInfo *List
 	// Cached authorized user
 	authorizedUser *AuthorizedUser
-	// Cached space tags (tag name -> true)
-	spaceTags map[string]bool
+	// Cached space tags (lowercased tag name -> true), shared by concurrent
+	// tag mutations
+	spaceTagsMu sync.Mutex
+	spaceTags   map[string]bool
+	// Set when a create was rejected in a way that suggests the cache is
+	// out of date; the next EnsureSpaceTag refetches it
+	spaceTagsStale bool
 }
 
 func (c *Client) getRetryConfig() RetryConfig {
 	if c.retryConfig != nil {
 		return *c.retryConfig
 	}
+	return DefaultRetryConfig()
+}
+
+// DefaultRetryConfig returns the retry settings used without WithRetryConfig.
+func DefaultRetryConfig() RetryConfig {
 	return RetryConfig{
 		MaxRetries:     defaultMaxRetries,
 		BaseRetryDelay: defaultBaseRetryDelay,
@@ -74,13 +120,82 @@ func (c *Client) getRetryConfig() RetryConfig {
 	}
 }
 
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the transport used for API requests.
+func WithHTTPClient(doer HTTPDoer) ClientOption {
+	return func(c *Client) { c.httpClient = doer }
+}
+
+// WithBaseURL points the client at a different API root, such as a mock server.
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) { c.baseURL = strings.TrimRight(url, "/") }
+}
+
+// WithRetryConfig overrides the default rate limit and transient error retry settings.
+func WithRetryConfig(rc RetryConfig) ClientOption {
+	return func(c *Client) { c.retryConfig = &rc }
+}
+
+// WithTimeout bounds each request attempt; 0 means no limit. It applies
+// only to the default transport, not one set with WithHTTPClient.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) { c.timeout = &d }
+}
+
+// WithMaxIdleConns sets how many idle keep-alive connections to ClickUp the
+// default transport keeps, so concurrent sync workers reuse them. 0 means
+// Go's default of 2. It doesn't apply with WithHTTPClient.
+func WithMaxIdleConns(n int) ClientOption {
+	return func(c *Client) { c.maxIdleConns = n }
+}
+
+// WithRecorder records the client's API traffic, sanitized, into cas. See
+// package cassette for replaying it.
+func WithRecorder(cas *cassette.Cassette) ClientOption {
+	return func(c *Client) { c.recorder = cas }
+}
+
 // NewClient creates a new ClickUp client.
 // The token should be a ClickUp API token.
-func NewClient(token string) *Client {
-	return &Client{
-		token:      token,
-		httpClient: &http.Client{},
+func NewClient(token string, opts ...ClientOption) *Client {
+	c := &Client{
+		token:   token,
+		baseURL: DefaultBaseURL,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.httpClient == nil {
+		c.httpClient = c.defaultHTTPClient()
+	}
+	if c.cacheDir != "" {
+		ttl := DefaultMetadataTTL
+		if c.metadataTTL != nil {
+			ttl = *c.metadataTTL
+		}
+		c.httpClient = &cachingDoer{next: c.httpClient, dir: c.cacheDir, now: time.Now, ttl: ttl}
+	}
+	if c.recorder != nil {
+		c.httpClient = c.recorder.Recorder(c.httpClient)
+	}
+	return c
+}
+
+// defaultHTTPClient builds the transport used without WithHTTPClient.
+func (c *Client) defaultHTTPClient() *http.Client {
+	hc := &http.Client{Timeout: DefaultTimeout}
+	if c.timeout != nil {
+		hc.Timeout = *c.timeout
+	}
+	if c.maxIdleConns > 0 {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.MaxIdleConns = max(transport.MaxIdleConns, c.maxIdleConns)
+		transport.MaxIdleConnsPerHost = c.maxIdleConns
+		hc.Transport = transport
 	}
+	return hc
 }
 
 // GetList fetches list metadata including available statuses.
@@ -89,7 +204,7 @@ func (c *Client) GetList(ctx context.Context, listID string) (*List, error) {
 		return c.listInfo, nil
 	}
 
-	url := fmt.Sprintf("%s/list/%s", baseURL, listID)
+	url := fmt.Sprintf("%s/list/%s", c.baseURL, listID)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
@@ -106,13 +221,22 @@ func (c *Client) GetList(ctx context.Context, listID string) (*List, error) {
 		SpaceID:  resp.Space.ID,
 		Statuses: resp.Statuses,
 	}
+	if !resp.Folder.Hidden {
+		c.listInfo.FolderID = resp.Folder.ID
+	}
 
 	return c.listInfo, nil
 }
 
+// TaskURL returns the web URL of a task, for when the task itself hasn't
+// been fetched.
+func TaskURL(taskID string) string {
+	return "https://app.clickup.com/t/" + taskID
+}
+
 // GetTask fetches a task by ID.
 func (c *Client) GetTask(ctx context.Context, taskID string) (*TaskInfo, error) {
-	url := fmt.Sprintf("%s/task/%s", baseURL, taskID)
+	url := fmt.Sprintf("%s/task/%s", c.baseURL, taskID)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
@@ -120,15 +244,44 @@ func (c *Client) GetTask(ctx context.Context, taskID string) (*TaskInfo, error)
 
 	var resp taskResponse
 	if err := c.doRequest(req, &resp); err != nil {
-		return nil, fmt.Errorf("getting task: %w", err)
+		return nil, fmt.Errorf("getting task: %w", taskNotFound(err))
 	}
 
 	return resp.toTaskInfo(), nil
 }
 
+// GetTaskRaw fetches a task by ID as the unmodified API JSON, including the
+// fields TaskInfo omits such as watchers and dependencies.
+func (c *Client) GetTaskRaw(ctx context.Context, taskID string) (json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := c.get(ctx, "/task/"+taskID, &raw); err != nil {
+		return nil, fmt.Errorf("getting task: %w", taskNotFound(err))
+	}
+	return raw, nil
+}
+
+// GetListTasks fetches every task in a list, including closed tasks and
+// subtasks, following pagination.
+func (c *Client) GetListTasks(ctx context.Context, listID string) ([]TaskInfo, error) {
+	var tasks []TaskInfo
+	for page := 0; ; page++ {
+		var resp listTasksResponse
+		path := fmt.Sprintf("/list/%s/task?include_closed=true&subtasks=true&page=%d", listID, page)
+		if err := c.get(ctx, path, &resp); err != nil {
+			return nil, fmt.Errorf("getting list tasks: %w", err)
+		}
+		for i := range resp.Tasks {
+			tasks = append(tasks, *resp.Tasks[i].toTaskInfo())
+		}
+		if resp.LastPage || len(resp.Tasks) == 0 {
+			return tasks, nil
+		}
+	}
+}
+
 // CreateTask creates a new task in the given list.
 func (c *Client) CreateTask(ctx context.Context, listID string, task *CreateTaskRequest) (*TaskInfo, error) {
-	url := fmt.Sprintf("%s/list/%s/task", baseURL, listID)
+	url := fmt.Sprintf("%s/list/%s/task", c.baseURL, listID)
 
 	body, err := json.Marshal(task)
 	if err != nil {
@@ -149,9 +302,106 @@ func (c *Client) CreateTask(ctx context.Context, listID string, task *CreateTask
 	return resp.toTaskInfo(), nil
 }
 
+// CreateTaskFromTemplate creates a task named name in a list from a task
+// template and returns its ID.
+func (c *Client) CreateTaskFromTemplate(ctx context.Context, listID, templateID, name string) (string, error) {
+	var resp struct {
+		ID string `json:"id"`
+	}
+	path := fmt.Sprintf("/list/%s/taskTemplate/%s", listID, templateID)
+	if err := c.send(ctx, "POST", path, map[string]string{"name": name}, &resp); err != nil {
+		return "", fmt.Errorf("creating task from template: %w", err)
+	}
+	return resp.ID, nil
+}
+
+// CreateTaskComment posts a comment on a task and returns the comment ID.
+func (c *Client) CreateTaskComment(ctx context.Context, taskID string, comment *CreateCommentRequest) (string, error) {
+	url := fmt.Sprintf("%s/task/%s/comment", c.baseURL, taskID)
+
+	body, err := json.Marshal(comment)
+	if err != nil {
+		return "", fmt.Errorf("marshaling request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var resp commentResponse
+	if err := c.doRequest(req, &resp); err != nil {
+		return "", fmt.Errorf("creating comment: %w", err)
+	}
+
+	return strings.Trim(string(resp.ID), `"`), nil
+}
+
+// GetTaskComments fetches a task's most recent comments, newest first.
+// ClickUp returns up to 25 per request.
+func (c *Client) GetTaskComments(ctx context.Context, taskID string) ([]TaskComment, error) {
+	var resp taskCommentsResponse
+	if err := c.get(ctx, "/task/"+taskID+"/comment", &resp); err != nil {
+		return nil, fmt.Errorf("getting comments: %w", err)
+	}
+	return resp.Comments, nil
+}
+
+// CreateTaskAttachment uploads data as a file attached to a task.
+func (c *Client) CreateTaskAttachment(ctx context.Context, taskID, filename string, data []byte) error {
+	url := fmt.Sprintf("%s/task/%s/attachment", c.baseURL, taskID)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("attachment", filename)
+	if err != nil {
+		return fmt.Errorf("creating form file: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return fmt.Errorf("writing form file: %w", err)
+	}
+	if err := mw.Close(); err != nil {
+		return fmt.Errorf("closing form: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, &body)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	if err := c.doRequest(req, nil); err != nil {
+		return fmt.Errorf("uploading attachment: %w", err)
+	}
+	return nil
+}
+
+// DownloadAttachment fetches the content of a task attachment from its
+// URL. Attachment URLs are signed file links, so the API token isn't sent.
+func (c *Client) DownloadAttachment(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("downloading attachment: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading attachment: HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("downloading attachment: %w", err)
+	}
+	return data, nil
+}
+
 // UpdateTask updates an existing task.
 func (c *Client) UpdateTask(ctx context.Context, taskID string, update *UpdateTaskRequest) (*TaskInfo, error) {
-	url := fmt.Sprintf("%s/task/%s", baseURL, taskID)
+	url := fmt.Sprintf("%s/task/%s", c.baseURL, taskID)
 
 	body, err := json.Marshal(update)
 	if err != nil {
@@ -172,11 +422,77 @@ func (c *Client) UpdateTask(ctx context.Context, taskID string, update *UpdateTa
 	return resp.toTaskInfo(), nil
 }
 
+// MoveTask changes a task's home list. The endpoint is only in API v3,
+// which lives next to v2 under the base URL's parent.
+func (c *Client) MoveTask(ctx context.Context, teamID, taskID, listID string) error {
+	url := fmt.Sprintf("%s/v3/workspaces/%s/tasks/%s/home_list/%s", strings.TrimSuffix(c.baseURL, "/v2"), teamID, taskID, listID)
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	if err := c.doRequest(req, nil); err != nil {
+		return fmt.Errorf("moving task: %w", err)
+	}
+
+	return nil
+}
+
+// AddTaskToList adds a task to a list besides its home list. The workspace
+// must have Tasks in Multiple Lists enabled.
+func (c *Client) AddTaskToList(ctx context.Context, listID, taskID string) error {
+	url := fmt.Sprintf("%s/list/%s/task/%s", c.baseURL, listID, taskID)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	if err := c.doRequest(req, nil); err != nil {
+		return fmt.Errorf("adding task to list: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveTaskFromList removes a task from a list other than its home list.
+func (c *Client) RemoveTaskFromList(ctx context.Context, listID, taskID string) error {
+	url := fmt.Sprintf("%s/list/%s/task/%s", c.baseURL, listID, taskID)
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	if err := c.doRequest(req, nil); err != nil {
+		return fmt.Errorf("removing task from list: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteTask permanently deletes a task.
+func (c *Client) DeleteTask(ctx context.Context, taskID string) error {
+	url := fmt.Sprintf("%s/task/%s", c.baseURL, taskID)
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	if err := c.doRequest(req, nil); err != nil {
+		return fmt.Errorf("deleting task: %w", err)
+	}
+
+	return nil
+}
+
 // AddDependency adds a dependency to a task.
 // This sets the task with taskID as waiting on (depends on) the task with dependsOnID.
 // In other words: dependsOnID is blocking taskID.
 func (c *Client) AddDependency(ctx context.Context, taskID, dependsOnID string) error {
-	url := fmt.Sprintf("%s/task/%s/dependency", baseURL, taskID)
+	url := fmt.Sprintf("%s/task/%s/dependency", c.baseURL, taskID)
 
 	body, err := json.Marshal(&AddDependencyRequest{
 		DependsOn: dependsOnID,
@@ -205,7 +521,7 @@ func (c *Client) GetAuthorizedUser(ctx context.Context) (*AuthorizedUser, error)
 		return c.authorizedUser, nil
 	}
 
-	url := fmt.Sprintf("%s/user", baseURL)
+	url := fmt.Sprintf("%s/user", c.baseURL)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
@@ -222,7 +538,7 @@ func (c *Client) GetAuthorizedUser(ctx context.Context) (*AuthorizedUser, error)
 
 // GetAccessibleCustomFields fetches available custom fields for a list.
 func (c *Client) GetAccessibleCustomFields(ctx context.Context, listID string) ([]FieldInfo, error) {
-	url := fmt.Sprintf("%s/list/%s/field", baseURL, listID)
+	url := fmt.Sprintf("%s/list/%s/field", c.baseURL, listID)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
@@ -236,38 +552,197 @@ func (c *Client) GetAccessibleCustomFields(ctx context.Context, listID string) (
 	return resp.Fields, nil
 }
 
-// GetCustomItems fetches custom task types from all accessible workspaces.
-// Returns custom items with their IDs, names, and descriptions.
-func (c *Client) GetCustomItems(ctx context.Context) ([]CustomItem, error) {
-	// First get all teams to iterate through workspaces
-	teamsURL := fmt.Sprintf("%s/team", baseURL)
-	teamsReq, err := http.NewRequestWithContext(ctx, "GET", teamsURL, nil)
+// CreateCustomField adds a custom field to a list.
+func (c *Client) CreateCustomField(ctx context.Context, listID string, field *CreateFieldRequest) (*FieldInfo, error) {
+	url := fmt.Sprintf("%s/list/%s/field", c.baseURL, listID)
+
+	body, err := json.Marshal(field)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
 	if err != nil {
-		return nil, fmt.Errorf("creating teams request: %w", err)
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var resp createFieldResponse
+	if err := c.doRequest(req, &resp); err != nil {
+		return nil, fmt.Errorf("creating custom field: %w", err)
+	}
+	if resp.Field != nil {
+		return resp.Field, nil
 	}
+	return &resp.FieldInfo, nil
+}
 
-	var teamsResp teamsResponse
-	if err := c.doRequest(teamsReq, &teamsResp); err != nil {
+// GetTeams fetches the workspaces the token can access.
+func (c *Client) GetTeams(ctx context.Context) ([]Team, error) {
+	var resp teamsResponse
+	if err := c.get(ctx, "/team", &resp); err != nil {
 		return nil, fmt.Errorf("getting teams: %w", err)
 	}
+	return resp.Teams, nil
+}
+
+// GetSpaces fetches the spaces in a workspace.
+func (c *Client) GetSpaces(ctx context.Context, teamID string) ([]Space, error) {
+	var resp spacesResponse
+	if err := c.get(ctx, "/team/"+teamID+"/space", &resp); err != nil {
+		return nil, fmt.Errorf("getting spaces: %w", err)
+	}
+	return resp.Spaces, nil
+}
+
+// GetSpace fetches a space with its features, such as its priorities.
+func (c *Client) GetSpace(ctx context.Context, spaceID string) (*Space, error) {
+	var space Space
+	if err := c.get(ctx, "/space/"+spaceID, &space); err != nil {
+		return nil, fmt.Errorf("getting space: %w", err)
+	}
+	return &space, nil
+}
+
+// GetFolders fetches the folders in a space, each with its lists.
+func (c *Client) GetFolders(ctx context.Context, spaceID string) ([]Folder, error) {
+	var resp foldersResponse
+	if err := c.get(ctx, "/space/"+spaceID+"/folder", &resp); err != nil {
+		return nil, fmt.Errorf("getting folders: %w", err)
+	}
+	return resp.Folders, nil
+}
+
+// GetFolderLists fetches the lists in a folder.
+func (c *Client) GetFolderLists(ctx context.Context, folderID string) ([]ListSummary, error) {
+	var resp listsResponse
+	if err := c.get(ctx, "/folder/"+folderID+"/list", &resp); err != nil {
+		return nil, fmt.Errorf("getting folder lists: %w", err)
+	}
+	return resp.Lists, nil
+}
+
+// GetSpaceLists fetches the lists directly in a space, outside any folder.
+func (c *Client) GetSpaceLists(ctx context.Context, spaceID string) ([]ListSummary, error) {
+	var resp listsResponse
+	if err := c.get(ctx, "/space/"+spaceID+"/list", &resp); err != nil {
+		return nil, fmt.Errorf("getting space lists: %w", err)
+	}
+	return resp.Lists, nil
+}
+
+// GetListDetails fetches a list with its description. Unlike GetList it
+// isn't cached, so it can be used for lists other than the configured one.
+func (c *Client) GetListDetails(ctx context.Context, listID string) (*ListDetails, error) {
+	var list ListDetails
+	if err := c.get(ctx, "/list/"+listID, &list); err != nil {
+		return nil, fmt.Errorf("getting list: %w", err)
+	}
+	return &list, nil
+}
+
+// CreateList creates a list in a folder, or directly in the space if
+// folderID is empty.
+func (c *Client) CreateList(ctx context.Context, spaceID, folderID string, list *ListRequest) (*ListDetails, error) {
+	path := "/space/" + spaceID + "/list"
+	if folderID != "" {
+		path = "/folder/" + folderID + "/list"
+	}
+	var created ListDetails
+	if err := c.send(ctx, "POST", path, list, &created); err != nil {
+		return nil, fmt.Errorf("creating list: %w", err)
+	}
+	return &created, nil
+}
+
+// UpdateList renames a list or changes its description.
+func (c *Client) UpdateList(ctx context.Context, listID string, list *ListRequest) (*ListDetails, error) {
+	var updated ListDetails
+	if err := c.send(ctx, "PUT", "/list/"+listID, list, &updated); err != nil {
+		return nil, fmt.Errorf("updating list: %w", err)
+	}
+	return &updated, nil
+}
+
+// CreateFolder creates a folder in a space.
+func (c *Client) CreateFolder(ctx context.Context, spaceID, name string) (*Folder, error) {
+	var folder Folder
+	if err := c.send(ctx, "POST", "/space/"+spaceID+"/folder", map[string]string{"name": name}, &folder); err != nil {
+		return nil, fmt.Errorf("creating folder: %w", err)
+	}
+	return &folder, nil
+}
+
+// RenameFolder renames a folder.
+func (c *Client) RenameFolder(ctx context.Context, folderID, name string) error {
+	if err := c.send(ctx, "PUT", "/folder/"+folderID, map[string]string{"name": name}, nil); err != nil {
+		return fmt.Errorf("renaming folder: %w", err)
+	}
+	return nil
+}
+
+// GetTaskTimeEntries fetches a task's time entries that start between
+// start and end. ClickUp only returns the last 30 days without a range.
+func (c *Client) GetTaskTimeEntries(ctx context.Context, teamID, taskID string, start, end time.Time) ([]TimeEntry, error) {
+	path := fmt.Sprintf("/team/%s/time_entries?task_id=%s&start_date=%d&end_date=%d", teamID, taskID, start.UnixMilli(), end.UnixMilli())
+	var resp timeEntriesResponse
+	if err := c.get(ctx, path, &resp); err != nil {
+		return nil, fmt.Errorf("getting time entries: %w", err)
+	}
+	return resp.Data, nil
+}
+
+// CreateTimeEntry records time spent on a task.
+func (c *Client) CreateTimeEntry(ctx context.Context, teamID string, entry *CreateTimeEntryRequest) error {
+	if err := c.send(ctx, "POST", "/team/"+teamID+"/time_entries", entry, nil); err != nil {
+		return fmt.Errorf("creating time entry: %w", err)
+	}
+	return nil
+}
+
+// send sends body as JSON with method to path (relative to the base URL)
+// and decodes the response into result, if not nil.
+func (c *Client) send(ctx context.Context, method, path string, body, result any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshaling request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.doRequest(req, result)
+}
+
+// get sends a GET request for path (relative to the base URL) and decodes the response.
+func (c *Client) get(ctx context.Context, path string, result any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	return c.doRequest(req, result)
+}
+
+// GetCustomItems fetches custom task types from all accessible workspaces.
+// Returns custom items with their IDs, names, and descriptions.
+func (c *Client) GetCustomItems(ctx context.Context) ([]CustomItem, error) {
+	teams, err := c.GetTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	// Collect custom items from all teams
 	seen := make(map[int]bool)
 	var items []CustomItem
-	for _, team := range teamsResp.Teams {
-		url := fmt.Sprintf("%s/team/%s/custom_item", baseURL, team.ID)
-		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	for _, team := range teams {
+		teamItems, err := c.GetTeamCustomItems(ctx, team.ID)
 		if err != nil {
-			return nil, fmt.Errorf("creating request: %w", err)
-		}
-
-		var resp customItemsResponse
-		if err := c.doRequest(req, &resp); err != nil {
 			// Skip teams that don't support custom items
 			continue
 		}
 
-		for _, item := range resp.CustomItems {
+		for _, item := range teamItems {
 			if !seen[item.ID] {
 				seen[item.ID] = true
 				items = append(items, item)
@@ -278,11 +753,22 @@ func (c *Client) GetCustomItems(ctx context.Context) ([]CustomItem, error) {
 	return items, nil
 }
 
-// AddTagToTask adds a tag to a task.
+// GetTeamCustomItems fetches custom task types from a single workspace.
+func (c *Client) GetTeamCustomItems(ctx context.Context, teamID string) ([]CustomItem, error) {
+	var resp customItemsResponse
+	if err := c.get(ctx, "/team/"+teamID+"/custom_item", &resp); err != nil {
+		return nil, fmt.Errorf("getting custom task types: %w", err)
+	}
+	return resp.CustomItems, nil
+}
+
+// AddTagToTask adds a tag to a task. The tag name is escaped as a single
+// path segment, so prefixed tags like "bean/ui" reach the right endpoint.
 // Note: This creates a task-level tag but does NOT register it as a space-level tag.
 // Use EnsureSpaceTag before this to make tags discoverable in the space tag picker.
 func (c *Client) AddTagToTask(ctx context.Context, taskID, tagName string) error {
-	url := fmt.Sprintf("%s/task/%s/tag/%s", baseURL, taskID, tagName)
+	tag := url.PathEscape(tagName)
+	url := fmt.Sprintf("%s/task/%s/tag/%s", c.baseURL, taskID, tag)
 
 	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
 	if err != nil {
@@ -298,7 +784,8 @@ func (c *Client) AddTagToTask(ctx context.Context, taskID, tagName string) error
 
 // RemoveTagFromTask removes a tag from a task.
 func (c *Client) RemoveTagFromTask(ctx context.Context, taskID, tagName string) error {
-	url := fmt.Sprintf("%s/task/%s/tag/%s", baseURL, taskID, tagName)
+	tag := url.PathEscape(tagName)
+	url := fmt.Sprintf("%s/task/%s/tag/%s", c.baseURL, taskID, tag)
 
 	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
@@ -319,7 +806,7 @@ type spaceTagsResponse struct {
 
 // GetSpaceTags fetches all tags for a space.
 func (c *Client) GetSpaceTags(ctx context.Context, spaceID string) ([]Tag, error) {
-	url := fmt.Sprintf("%s/space/%s/tag", baseURL, spaceID)
+	url := fmt.Sprintf("%s/space/%s/tag", c.baseURL, spaceID)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
@@ -333,11 +820,12 @@ func (c *Client) GetSpaceTags(ctx context.Context, spaceID string) ([]Tag, error
 	return resp.Tags, nil
 }
 
-// CreateSpaceTag creates a tag at the space level so it appears in the tag picker.
-func (c *Client) CreateSpaceTag(ctx context.Context, spaceID, tagName string) error {
-	url := fmt.Sprintf("%s/space/%s/tag", baseURL, spaceID)
+// CreateSpaceTag creates a tag at the space level so it appears in the tag
+// picker, in the tag's colors if set.
+func (c *Client) CreateSpaceTag(ctx context.Context, spaceID string, tag Tag) error {
+	url := fmt.Sprintf("%s/space/%s/tag", c.baseURL, spaceID)
 
-	body, err := json.Marshal(map[string]any{"tag": map[string]string{"name": tagName}})
+	body, err := json.Marshal(map[string]any{"tag": tag})
 	if err != nil {
 		return fmt.Errorf("marshaling request: %w", err)
 	}
@@ -362,41 +850,78 @@ func (c *Client) PopulateSpaceTagCache(ctx context.Context, spaceID string) erro
 		return err
 	}
 
+	c.spaceTagsMu.Lock()
+	defer c.spaceTagsMu.Unlock()
 	c.spaceTags = make(map[string]bool, len(tags))
 	for _, t := range tags {
-		c.spaceTags[t.Name] = true
+		c.spaceTags[strings.ToLower(t.Name)] = true
 	}
+	c.spaceTagsStale = false
 
 	return nil
 }
 
 // EnsureSpaceTag creates a tag at the space level if it doesn't already exist in the cache.
-func (c *Client) EnsureSpaceTag(ctx context.Context, spaceID, tagName string) error {
-	if c.spaceTags != nil && c.spaceTags[tagName] {
+// ClickUp tag names are case-insensitive, so a cached tag in any case counts.
+//
+// The cache is filled once per sync, so it misses tags teammates add
+// meanwhile: a create rejected because the tag exists just caches it, and
+// any other 4xx response has the next call refetch the space's tags.
+func (c *Client) EnsureSpaceTag(ctx context.Context, spaceID string, tag Tag) error {
+	if c.takeSpaceTagsStale() {
+		_ = c.PopulateSpaceTagCache(ctx, spaceID) // Best-effort; creating below still works
+	}
+	if c.HasSpaceTag(tag.Name) {
 		return nil
 	}
 
-	if err := c.CreateSpaceTag(ctx, spaceID, tagName); err != nil {
+	err := c.CreateSpaceTag(ctx, spaceID, tag)
+	if err != nil && !errors.Is(err, ErrTagExists) {
+		if apiErr, ok := errors.AsType[*APIError](err); ok && apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 {
+			c.InvalidateSpaceTagCache()
+		}
 		return err
 	}
 
+	c.spaceTagsMu.Lock()
+	defer c.spaceTagsMu.Unlock()
 	if c.spaceTags == nil {
 		c.spaceTags = make(map[string]bool)
 	}
-	c.spaceTags[tagName] = true
+	c.spaceTags[strings.ToLower(tag.Name)] = true
 
 	return nil
 }
 
-// HasSpaceTag returns true if the tag exists in the space tag cache.
+// InvalidateSpaceTagCache marks the space tag cache out of date, so the
+// next EnsureSpaceTag refetches it.
+func (c *Client) InvalidateSpaceTagCache() {
+	c.spaceTagsMu.Lock()
+	defer c.spaceTagsMu.Unlock()
+	c.spaceTagsStale = true
+}
+
+// takeSpaceTagsStale reports whether the space tag cache needs refetching,
+// clearing the mark so only one caller refetches.
+func (c *Client) takeSpaceTagsStale() bool {
+	c.spaceTagsMu.Lock()
+	defer c.spaceTagsMu.Unlock()
+	stale := c.spaceTagsStale
+	c.spaceTagsStale = false
+	return stale
+}
+
+// HasSpaceTag returns true if the tag exists in the space tag cache, in any case.
 // PopulateSpaceTagCache must be called first.
 func (c *Client) HasSpaceTag(tagName string) bool {
-	return c.spaceTags != nil && c.spaceTags[tagName]
+	c.spaceTagsMu.Lock()
+	defer c.spaceTagsMu.Unlock()
+	return c.spaceTags != nil && c.spaceTags[strings.ToLower(tagName)]
 }
 
 // SetCustomFieldValue sets a custom field value on a task.
 func (c *Client) SetCustomFieldValue(ctx context.Context, taskID, fieldID string, value any) error {
-	url := fmt.Sprintf("%s/task/%s/field/%s", baseURL, taskID, fieldID)
+	url := fmt.Sprintf("%s/task/%s/field/%s", c.baseURL, taskID, fieldID)
 
 	body, err := json.Marshal(map[string]any{"value": value})
 	if err != nil {
@@ -418,9 +943,29 @@ func (c *Client) SetCustomFieldValue(ctx context.Context, taskID, fieldID string
 
 // doRequest executes an HTTP request and decodes the response.
 // It automatically retries on rate limit errors and transient errors with exponential backoff.
-func (c *Client) doRequest(req *http.Request, result any) error {
+func (c *Client) doRequest(req *http.Request, result any) (err error) {
 	cfg := c.getRetryConfig()
 
+	_, span := tracing.StartClient(req.Context(), "clickup "+req.Method+" "+req.URL.Path,
+		tracing.String("http.request.method", req.Method),
+		tracing.String("url.path", req.URL.Path),
+	)
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	// Filled in as attempts complete, and reported when the call returns
+	var event *RequestEvent
+	if c.requestHook != nil {
+		event = &RequestEvent{Method: req.Method, Endpoint: endpointOf(req.URL.Path)}
+		start := time.Now()
+		defer func() {
+			event.Duration = time.Since(start)
+			c.requestHook(*event)
+		}()
+	}
+
 	// We need to be able to retry the request, so we need to save the body
 	var bodyBytes []byte
 	if req.Body != nil {
@@ -443,14 +988,18 @@ func (c *Client) doRequest(req *http.Request, result any) error {
 			// Calculate delay with exponential backoff and jitter
 			delay := min(cfg.BaseRetryDelay*time.Duration(1<<(attempt-1)), cfg.MaxRetryDelay)
 			// Add jitter (0-25% of delay)
-			jitter := time.Duration(rand.Int64N(int64(delay / 4)))
+			jitter := time.Duration(rand.Int64N(int64(delay/4) + 1))
 			delay += jitter
+			if _, ok := lastErr.(*RateLimitError); ok && event != nil {
+				event.RateLimitWait += delay
+			}
 
 			select {
 			case <-req.Context().Done():
 				return req.Context().Err()
 			case <-time.After(delay):
 			}
+			syncer.NoteRetry(req.Context())
 
 			// Reset the body for retry
 			if bodyBytes != nil {
@@ -460,16 +1009,26 @@ func (c *Client) doRequest(req *http.Request, result any) error {
 
 		req.Header.Set("Authorization", c.token)
 
+		span.SetAttributes(tracing.Int("http.request.resend_count", attempt))
+		if event != nil {
+			event.Attempts = attempt + 1
+		}
+
 		resp, err := c.httpClient.Do(req)
 		if err != nil {
 			// Check for transient network errors (stream errors, connection resets, etc.)
 			if isTransientNetworkError(err) {
-				lastErr = &TransientError{Message: err.Error()}
+				lastErr = &TransientError{Message: err.Error(), Err: err}
 				continue // Retry
 			}
 			return fmt.Errorf("executing request: %w", err)
 		}
 
+		span.SetAttributes(tracing.Int("http.response.status_code", resp.StatusCode))
+		if event != nil {
+			event.StatusCode = resp.StatusCode
+		}
+
 		body, err := io.ReadAll(resp.Body)
 		_ = resp.Body.Close()
 		if err != nil {
@@ -480,11 +1039,11 @@ func (c *Client) doRequest(req *http.Request, result any) error {
 			// Check for rate limit errors
 			var errResp errorResponse
 			if err := json.Unmarshal(body, &errResp); err == nil && errResp.Err != "" {
-				if resp.StatusCode == 429 || errResp.ECODE == "APP_002" {
+				if resp.StatusCode == 429 || errResp.ECODE == ecodeRateLimited {
 					lastErr = &RateLimitError{Message: errResp.Err, Code: errResp.ECODE}
 					continue // Retry
 				}
-				return fmt.Errorf("API error: %s (code: %s)", errResp.Err, errResp.ECODE)
+				return &APIError{StatusCode: resp.StatusCode, Code: errResp.ECODE, Message: errResp.Err, clickUpBody: true}
 			}
 
 			// Check for transient HTTP errors (5xx, CloudFront errors, etc.)
@@ -493,7 +1052,7 @@ func (c *Client) doRequest(req *http.Request, result any) error {
 				continue // Retry
 			}
 
-			return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+			return &APIError{StatusCode: resp.StatusCode, Message: string(body)}
 		}
 
 		if result != nil && len(body) > 0 {
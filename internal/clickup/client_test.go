@@ -0,0 +1,165 @@
+package clickup
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHierarchy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/team":
+			_, _ = w.Write([]byte(`{"teams":[{"id":"1","name":"Acme"}]}`))
+		case "/api/v2/team/1/space":
+			_, _ = w.Write([]byte(`{"spaces":[{"id":"10","name":"Engineering","private":false}]}`))
+		case "/api/v2/team/1/custom_item":
+			_, _ = w.Write([]byte(`{"custom_items":[{"id":1001,"name":"Bug"}]}`))
+		case "/api/v2/space/10/folder":
+			_, _ = w.Write([]byte(`{"folders":[{"id":"100","name":"Roadmap","lists":[{"id":"1000","name":"Sprint","task_count":"3"}]}]}`))
+		case "/api/v2/space/10/list":
+			_, _ = w.Write([]byte(`{"lists":[{"id":"1001","name":"Inbox","task_count":0}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"err":"Not found","ECODE":"OAUTH_027"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token:      "test",
+		httpClient: &http.Client{Transport: &redirectTransport{target: server.URL}},
+		baseURL:    DefaultBaseURL,
+	}
+	ctx := context.Background()
+
+	teams, err := client.GetTeams(ctx)
+	if err != nil || len(teams) != 1 || teams[0].Name != "Acme" {
+		t.Fatalf("GetTeams() = %+v, %v", teams, err)
+	}
+	spaces, err := client.GetSpaces(ctx, "1")
+	if err != nil || len(spaces) != 1 || spaces[0].ID != "10" {
+		t.Fatalf("GetSpaces() = %+v, %v", spaces, err)
+	}
+	items, err := client.GetTeamCustomItems(ctx, "1")
+	if err != nil || len(items) != 1 || items[0].Name != "Bug" {
+		t.Fatalf("GetTeamCustomItems() = %+v, %v", items, err)
+	}
+	folders, err := client.GetFolders(ctx, "10")
+	if err != nil || len(folders) != 1 || len(folders[0].Lists) != 1 || folders[0].Lists[0].ID != "1000" {
+		t.Fatalf("GetFolders() = %+v, %v", folders, err)
+	}
+	lists, err := client.GetSpaceLists(ctx, "10")
+	if err != nil || len(lists) != 1 || lists[0].Name != "Inbox" {
+		t.Fatalf("GetSpaceLists() = %+v, %v", lists, err)
+	}
+	if _, err := client.GetFolderLists(ctx, "10"); err == nil {
+		t.Error("GetFolderLists() on a space ID should fail")
+	}
+}
+
+func TestGetListTasks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/list/L1/task" || r.URL.Query().Get("include_closed") != "true" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.URL.Query().Get("page") {
+		case "0":
+			_, _ = w.Write([]byte(`{"tasks":[{"id":"t1","custom_fields":[{"id":"f1","value":"bean-a"}]}],"last_page":false}`))
+		case "1":
+			_, _ = w.Write([]byte(`{"tasks":[{"id":"t2"}],"last_page":true}`))
+		default:
+			t.Errorf("unexpected page %q", r.URL.Query().Get("page"))
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token:      "test",
+		httpClient: &http.Client{Transport: &redirectTransport{target: server.URL}},
+		baseURL:    DefaultBaseURL,
+	}
+	tasks, err := client.GetListTasks(context.Background(), "L1")
+	if err != nil || len(tasks) != 2 {
+		t.Fatalf("GetListTasks() = %+v, %v", tasks, err)
+	}
+	if got := tasks[0].CustomFieldString("f1"); got != "bean-a" {
+		t.Errorf("CustomFieldString() = %q, want bean-a", got)
+	}
+	if got := tasks[1].CustomFieldString("f1"); got != "" {
+		t.Errorf("CustomFieldString() on unset field = %q", got)
+	}
+}
+
+func TestGetTaskHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/task/t1/history" || r.URL.Query().Get("date_gt") != "2000" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"history_items":[
+			{"id":"h0","field":"priority","date":"2000","user":{"username":"old"}},
+			{"id":"h1","field":"priority","date":"3000","user":{"username":"ann"}},
+			{"id":"h2","field":"custom_type","date":"4000","user":{"email":"bo@example.com"}},
+			{"id":"h3","field":"priority","date":"5000","user":{"username":"cy"}},
+			{"id":"h4","field":"name","date":"6000","user":{"username":"dee"}}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token:      "test",
+		httpClient: &http.Client{Transport: &redirectTransport{target: server.URL}},
+		baseURL:    DefaultBaseURL,
+	}
+	items, err := client.GetTaskHistory(context.Background(), "t1", time.UnixMilli(2000))
+	if err != nil || len(items) != 4 {
+		t.Fatalf("GetTaskHistory() = %+v, %v, want the 4 items after since", items, err)
+	}
+
+	// The latest edit of each pulled field wins; fields pull doesn't write are dropped
+	changes := RemoteChanges(items)
+	if len(changes) != 2 || changes["priority"].User.Name() != "cy" || changes["type"].User.Name() != "bo@example.com" {
+		t.Errorf("RemoteChanges() = %+v", changes)
+	}
+}
+
+func TestDefaultHTTPClient(t *testing.T) {
+	hc := NewClient("test").httpClient.(*http.Client)
+	if hc.Timeout != DefaultTimeout || hc.Transport != nil {
+		t.Errorf("default client: Timeout = %v, Transport = %v", hc.Timeout, hc.Transport)
+	}
+
+	hc = NewClient("test", WithTimeout(0), WithMaxIdleConns(16)).httpClient.(*http.Client)
+	transport, ok := hc.Transport.(*http.Transport)
+	if hc.Timeout != 0 || !ok || transport.MaxIdleConnsPerHost != 16 {
+		t.Errorf("configured client: Timeout = %v, Transport = %+v", hc.Timeout, hc.Transport)
+	}
+
+	doer := &http.Client{}
+	if got := NewClient("test", WithHTTPClient(doer), WithTimeout(time.Second)).httpClient; got != doer {
+		t.Error("WithTimeout should leave a client set with WithHTTPClient alone")
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test", WithBaseURL(server.URL), WithTimeout(20*time.Millisecond), WithRetryConfig(RetryConfig{}))
+	start := time.Now()
+	if _, err := client.GetTask(context.Background(), "86abc"); err == nil {
+		t.Fatal("GetTask() should time out")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("GetTask() took %v; the timeout wasn't applied", elapsed)
+	}
+}
@@ -0,0 +1,37 @@
+package clickup
+
+import "regexp"
+
+// mentionRef matches @shortname, allowing dots inside but not at the end
+// so a trailing full stop stays text.
+var mentionRef = regexp.MustCompile(`@([A-Za-z0-9_-]+(?:\.[A-Za-z0-9_-]+)*)`)
+
+// BuildComment splits a message into comment parts, turning each
+// @shortname found in users into a mention of that user ID. Unknown
+// names are left as text.
+func BuildComment(message string, users map[string]int) []CommentPart {
+	var parts []CommentPart
+	text := func(s string) {
+		if s == "" {
+			return
+		}
+		if n := len(parts); n > 0 && parts[n-1].Type == "" {
+			parts[n-1].Text += s
+			return
+		}
+		parts = append(parts, CommentPart{Text: s})
+	}
+
+	last := 0
+	for _, m := range mentionRef.FindAllStringSubmatchIndex(message, -1) {
+		id, ok := users[message[m[2]:m[3]]]
+		if !ok {
+			continue
+		}
+		text(message[last:m[0]])
+		parts = append(parts, CommentPart{Type: "tag", User: &CommentUser{ID: id}})
+		last = m[1]
+	}
+	text(message[last:])
+	return parts
+}
@@ -0,0 +1,27 @@
+package clickup
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildComment(t *testing.T) {
+	users := map[string]int{"ana": 11, "bo.li": 22}
+
+	got := BuildComment("@ana and @bo.li, see @carl. Thanks @ana.", users)
+	want := []CommentPart{
+		{Type: "tag", User: &CommentUser{ID: 11}},
+		{Text: " and "},
+		{Type: "tag", User: &CommentUser{ID: 22}},
+		{Text: ", see @carl. Thanks "},
+		{Type: "tag", User: &CommentUser{ID: 11}},
+		{Text: "."},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildComment() = %+v, want %+v", got, want)
+	}
+
+	if got := BuildComment("no mentions", nil); !reflect.DeepEqual(got, []CommentPart{{Text: "no mentions"}}) {
+		t.Errorf("BuildComment() without mentions = %+v", got)
+	}
+}
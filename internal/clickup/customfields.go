@@ -0,0 +1,456 @@
+package clickup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/toba/bean-me-up/internal/beans"
+)
+
+// Custom field types whose values custom_fields.fields converts.
+const (
+	fieldTypeText      = "text"
+	fieldTypeShortText = "short_text"
+	fieldTypeURL       = "url"
+	fieldTypeNumber    = "number"
+	fieldTypeDate      = "date"
+	fieldTypeDropDown  = "drop_down"
+	fieldTypeLabels    = "labels"
+)
+
+// reservedExtKeys are the extensions.clickup keys beanup itself uses, which
+// custom_fields.fields can't map.
+var reservedExtKeys = []string{
+	beans.ExtKeyTaskID, beans.ExtKeySyncedAt, beans.ExtKeyTaskURL,
+	beans.ExtKeySprint, beans.ExtKeyAssignee, beans.ExtKeyReview,
+	beans.ExtKeyProgress,
+}
+
+// validateMappedFields checks that custom_fields.fields maps keys beanup
+// doesn't use itself.
+func validateMappedFields(fields map[string]string) error {
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		if slices.Contains(reservedExtKeys, key) {
+			return fmt.Errorf("custom_fields.fields can't map %q, which beanup uses itself", key)
+		}
+	}
+	return nil
+}
+
+// FieldOption is an option of a drop-down or labels custom field.
+type FieldOption struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Label      string `json:"label"`      // labels fields name options here
+	OrderIndex any    `json:"orderindex"` // a number, or a number as a string
+}
+
+// options returns the field's drop-down or labels options, from its
+// type_config, with Name set for both.
+func (f FieldInfo) options() []FieldOption {
+	data, err := json.Marshal(f.TypeConfig)
+	if err != nil {
+		return nil
+	}
+	var tc struct {
+		Options []FieldOption `json:"options"`
+	}
+	_ = json.Unmarshal(data, &tc)
+	for i, o := range tc.Options {
+		if o.Name == "" {
+			tc.Options[i].Name = o.Label
+		}
+	}
+	return tc.Options
+}
+
+// resolveOption finds the option of def a bean names. An exact name or
+// option ID wins, then a name differing only in case, then one differing
+// only in case, spacing, and punctuation, so "in-review" finds "In Review".
+// A loose match must be the only one.
+func resolveOption(def FieldInfo, opts []FieldOption, name string) (FieldOption, error) {
+	for _, o := range opts {
+		if o.Name == name || o.ID == name {
+			return o, nil
+		}
+	}
+	for _, match := range []func(string) bool{
+		func(n string) bool { return strings.EqualFold(n, name) },
+		func(n string) bool { return looseName(n) != "" && looseName(n) == looseName(name) },
+	} {
+		var found []FieldOption
+		for _, o := range opts {
+			if match(o.Name) {
+				found = append(found, o)
+			}
+		}
+		switch len(found) {
+		case 0:
+			continue
+		case 1:
+			return found[0], nil
+		}
+		return FieldOption{}, fmt.Errorf("%s option %q is ambiguous (matches %s)", def.Name, name, optionNames(found))
+	}
+	if len(opts) == 0 {
+		return FieldOption{}, fmt.Errorf("%s has no option %q", def.Name, name)
+	}
+	return FieldOption{}, fmt.Errorf("%s has no option %q (use %s)", def.Name, name, optionNames(opts))
+}
+
+// looseName lowercases name and drops everything but letters and digits.
+func looseName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// optionNames lists the options' names for an error message.
+func optionNames(opts []FieldOption) string {
+	names := make([]string, len(opts))
+	for i, o := range opts {
+		names[i] = strconv.Quote(o.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// CustomFieldChange is a custom field mapped by custom_fields.fields whose
+// task value differs from the bean's extensions.clickup value.
+type CustomFieldChange struct {
+	Key  string // the extensions.clickup key
+	Bean string // the bean's value, "" if unset
+	Task string // the task's value, "" if unset
+	// Value is what the bean's key is set to, in frontmatter form; nil
+	// removes it.
+	Value any
+}
+
+// fieldDefs returns the list's custom fields by ID, fetched on first use.
+func (s *Sink) fieldDefs(ctx context.Context) (map[string]FieldInfo, error) {
+	s.fieldsMu.Lock()
+	defer s.fieldsMu.Unlock()
+	if s.fieldInfos != nil {
+		return s.fieldInfos, nil
+	}
+	fields, err := s.client.GetAccessibleCustomFields(ctx, s.listID)
+	if err != nil {
+		return nil, err
+	}
+	s.fieldInfos = make(map[string]FieldInfo, len(fields))
+	for _, f := range fields {
+		s.fieldInfos[f.ID] = f
+	}
+	return s.fieldInfos, nil
+}
+
+// mappedFields returns the keys custom_fields.fields maps, in order, or
+// nil if it maps none.
+func (s *Sink) mappedFields() []string {
+	if s.config == nil || s.config.CustomFields == nil {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(s.config.CustomFields.Fields))
+}
+
+// mappedFieldValues returns the values to set for the bean's mapped custom
+// fields whose task value, from current, differs. Fields the bean has no
+// value for, and values that can't be converted, are left alone.
+func (s *Sink) mappedFieldValues(ctx context.Context, b *beans.Bean, current map[string]any) []CustomField {
+	keys := s.mappedFields()
+	if len(keys) == 0 {
+		return nil
+	}
+	defs, err := s.fieldDefs(ctx)
+	if err != nil {
+		return nil
+	}
+	var fields []CustomField
+	for _, key := range keys {
+		id := s.config.CustomFields.Fields[key]
+		v, ok := b.Extensions[beans.PluginClickUp][key]
+		def, known := defs[id]
+		if !ok || !known || isRelationship(def) {
+			continue
+		}
+		value, canon, err := beanFieldValue(def, v)
+		if err != nil {
+			continue
+		}
+		if _, taskCanon, _ := taskFieldValue(def, current[id]); taskCanon != canon {
+			fields = append(fields, CustomField{ID: id, Value: value})
+		}
+	}
+	return fields
+}
+
+// ValidateBean checks that the bean's values for the custom fields mapped
+// by custom_fields.fields, and its progress, can be sent, so a bad value
+// fails the bean before any request rather than as a ClickUp 400. Fields
+// not on the list are left to beanup check.
+func (s *Sink) ValidateBean(ctx context.Context, b *beans.Bean) error {
+	if s.config == nil {
+		return nil
+	}
+	var errs []error
+	if cf := s.config.CustomFields; cf != nil && cf.Progress != "" {
+		if v, ok := b.Extensions[beans.PluginClickUp][beans.ExtKeyProgress]; ok {
+			if _, ok := BeanProgress(b); !ok {
+				errs = append(errs, fmt.Errorf("extensions.clickup.progress: %v is not a number", v))
+			}
+		}
+	}
+	keys := s.mappedFields()
+	if len(keys) == 0 {
+		return errors.Join(errs...)
+	}
+	defs, err := s.fieldDefs(ctx)
+	if err != nil {
+		return errors.Join(errs...) // the sync itself will fail
+	}
+	for _, key := range keys {
+		v, ok := b.Extensions[beans.PluginClickUp][key]
+		def, known := defs[s.config.CustomFields.Fields[key]]
+		if !ok || !known || isRelationship(def) {
+			continue
+		}
+		if _, _, err := beanFieldValue(def, v); err != nil {
+			errs = append(errs, fmt.Errorf("extensions.clickup.%s: %w", key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CustomFieldChanges compares the task's values of the custom fields
+// mapped by custom_fields.fields with the bean's, converting them to
+// frontmatter form: option names, YYYY-MM-DD dates, and numbers.
+func (s *Sink) CustomFieldChanges(ctx context.Context, b *beans.Bean, task *TaskInfo) ([]CustomFieldChange, error) {
+	keys := s.mappedFields()
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	defs, err := s.fieldDefs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting custom fields: %w", err)
+	}
+	var changes []CustomFieldChange
+	for _, key := range keys {
+		id := s.config.CustomFields.Fields[key]
+		def, ok := defs[id]
+		if !ok || isRelationship(def) {
+			continue // relationships are only pushed
+		}
+		var taskValue any
+		for _, f := range task.CustomFields {
+			if f.ID == id {
+				taskValue = f.Value
+			}
+		}
+		c := CustomFieldChange{Key: key}
+		c.Value, c.Task, _ = taskFieldValue(def, taskValue)
+		if v, ok := b.Extensions[beans.PluginClickUp][key]; ok {
+			if _, canon, err := beanFieldValue(def, v); err == nil {
+				c.Bean = canon
+			} else {
+				c.Bean = fmt.Sprint(v)
+			}
+		}
+		if c.Bean != c.Task {
+			changes = append(changes, c)
+		}
+	}
+	return changes, nil
+}
+
+// beanFieldValue converts a bean's frontmatter value to the value ClickUp
+// sets for a field like def, and to its canonical form for comparing with
+// the task's.
+func beanFieldValue(def FieldInfo, v any) (value any, canon string, err error) {
+	switch def.Type {
+	case fieldTypeText, fieldTypeShortText, fieldTypeURL:
+		s := fmt.Sprint(v)
+		return s, s, nil
+	case fieldTypeNumber:
+		n, ok := toNumber(v)
+		if !ok {
+			return nil, "", fmt.Errorf("%s: %v is not a number", def.Name, v)
+		}
+		return n, formatNumber(n), nil
+	case fieldTypeDate:
+		var t time.Time
+		switch v := v.(type) {
+		case time.Time:
+			t = v
+		case string:
+			if t, err = parseBeanDueDate(v); err != nil {
+				return nil, "", fmt.Errorf("%s: %q is not a YYYY-MM-DD date", def.Name, v)
+			}
+		default:
+			return nil, "", fmt.Errorf("%s: %v is not a YYYY-MM-DD date", def.Name, v)
+		}
+		ms := toLocalDateMillis(t)
+		return ms, formatDueMillis(&ms), nil
+	case fieldTypeDropDown:
+		o, err := resolveOption(def, def.options(), fmt.Sprint(v))
+		if err != nil {
+			return nil, "", err
+		}
+		return o.ID, o.Name, nil
+	case fieldTypeLabels:
+		opts := def.options()
+		ids, names := []string{}, []string{}
+		for _, name := range stringList(v) {
+			o, err := resolveOption(def, opts, name)
+			if err != nil {
+				return nil, "", err
+			}
+			if !slices.Contains(ids, o.ID) {
+				ids = append(ids, o.ID)
+				names = append(names, o.Name)
+			}
+		}
+		return ids, labelsCanon(names), nil
+	case fieldTypeEmail:
+		s, err := emailValue(def, v)
+		return s, s, err
+	case fieldTypePhone:
+		s, err := phoneValue(def, v)
+		return s, s, err
+	case fieldTypeLocation:
+		l, err := beanLocation(def, v)
+		if err != nil {
+			return nil, "", err
+		}
+		return locationValue(l), l.canon(), nil
+	case fieldTypeCurrency:
+		n, err := currencyValue(def, v)
+		if err != nil {
+			return nil, "", err
+		}
+		return n, formatNumber(n), nil
+	}
+	return nil, "", fmt.Errorf("%s: %s fields aren't supported", def.Name, def.Type)
+}
+
+// taskFieldValue converts a task's value of a field like def to the value
+// pull writes to the bean and its canonical form. ok is false if the task
+// has no value, or one that can't be converted.
+func taskFieldValue(def FieldInfo, v any) (value any, canon string, ok bool) {
+	if v == nil || v == "" {
+		return nil, "", false
+	}
+	switch def.Type {
+	case fieldTypeText, fieldTypeShortText, fieldTypeURL, fieldTypeEmail:
+		s := fmt.Sprint(v)
+		return s, s, true
+	case fieldTypePhone:
+		// Compared in the form sync sends, whatever spacing ClickUp shows
+		s, err := phoneValue(def, v)
+		if err != nil {
+			s = fmt.Sprint(v)
+		}
+		return s, s, true
+	case fieldTypeLocation:
+		l, ok := taskLocation(v)
+		if !ok {
+			return nil, "", false
+		}
+		return l.frontmatter(), l.canon(), true
+	case fieldTypeNumber, fieldTypeCurrency:
+		n, ok := toNumber(v)
+		if !ok {
+			return nil, "", false
+		}
+		if n == float64(int64(n)) {
+			return int64(n), formatNumber(n), true
+		}
+		return n, formatNumber(n), true
+	case fieldTypeDate:
+		n, ok := toNumber(v)
+		if !ok {
+			return nil, "", false
+		}
+		ms := int64(n)
+		date := formatDueMillis(&ms)
+		return date, date, true
+	case fieldTypeDropDown:
+		// The value is the option's orderindex, or its ID
+		s := fmt.Sprint(v)
+		if n, ok := toNumber(v); ok {
+			s = formatNumber(n)
+		}
+		for _, o := range def.options() {
+			index, hasIndex := toNumber(o.OrderIndex)
+			if o.ID == s || hasIndex && formatNumber(index) == s {
+				return o.Name, o.Name, true
+			}
+		}
+	case fieldTypeLabels:
+		// The value is a list of option IDs
+		ids, _ := v.([]any)
+		var names []string
+		for _, o := range def.options() {
+			if slices.Contains(ids, any(o.ID)) {
+				names = append(names, o.Name)
+			}
+		}
+		if len(names) == 0 {
+			return nil, "", false
+		}
+		slices.Sort(names)
+		return names, labelsCanon(names), true
+	}
+	return nil, "", false
+}
+
+// stringList returns a frontmatter value that may be a list or a single
+// value, such as a labels field's option names, as strings.
+func stringList(v any) []string {
+	list, ok := v.([]any)
+	if !ok {
+		return []string{fmt.Sprint(v)}
+	}
+	names := make([]string, len(list))
+	for i, item := range list {
+		names[i] = fmt.Sprint(item)
+	}
+	return names
+}
+
+// labelsCanon is the canonical form of a labels value, which is unordered.
+func labelsCanon(names []string) string {
+	return strings.Join(slices.Sorted(slices.Values(names)), ", ")
+}
+
+// toNumber converts a YAML or JSON number, or a number in a string, to a
+// float64.
+func toNumber(v any) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// formatNumber formats n without trailing zeros or an exponent.
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
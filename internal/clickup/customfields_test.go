@@ -0,0 +1,242 @@
+package clickup
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+)
+
+func TestCustomFieldChanges(t *testing.T) {
+	sink := NewSink(nil, &config.ClickUpConfig{CustomFields: &config.CustomFieldsMap{Fields: map[string]string{
+		"estimate": "f-num", "release": "f-date", "area": "f-drop", "owner": "f-text", "gone": "f-missing",
+	}}}, "list")
+	sink.fieldInfos = map[string]FieldInfo{
+		"f-num":  {ID: "f-num", Name: "Estimate", Type: fieldTypeNumber},
+		"f-date": {ID: "f-date", Name: "Release", Type: fieldTypeDate},
+		"f-drop": {ID: "f-drop", Name: "Area", Type: fieldTypeDropDown, TypeConfig: map[string]any{"options": []any{
+			map[string]any{"id": "opt-api", "name": "API", "orderindex": 0},
+			map[string]any{"id": "opt-web", "name": "Web", "orderindex": "1"},
+		}}},
+		"f-text": {ID: "f-text", Name: "Owner", Type: fieldTypeText},
+	}
+	release := time.Date(2026, 5, 1, 0, 0, 0, 0, time.Local).UnixMilli()
+
+	b := &beans.Bean{ID: "bean-1", Extensions: map[string]map[string]any{beans.PluginClickUp: {
+		"estimate": 3, "release": "2026-04-01", "area": "API", "owner": "ann",
+	}}}
+	task := &TaskInfo{CustomFields: []TaskCustomField{
+		{ID: "f-num", Value: "5"},
+		{ID: "f-date", Value: formatNumber(float64(release))}, // ms as a string
+		{ID: "f-drop", Value: float64(1)},
+		{ID: "f-text", Value: "ann"},
+	}}
+
+	changes, err := sink.CustomFieldChanges(context.Background(), b, task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Keys are in order; the owner matches and unknown fields are ignored
+	want := []CustomFieldChange{
+		{Key: "area", Bean: "API", Task: "Web", Value: "Web"},
+		{Key: "estimate", Bean: "3", Task: "5", Value: int64(5)},
+		{Key: "release", Bean: "2026-04-01", Task: "2026-05-01", Value: "2026-05-01"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("CustomFieldChanges() = %+v, want %+v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("change %d = %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+
+	// Sync pushes the bean's values the other way, as ClickUp takes them
+	current := map[string]any{}
+	for _, f := range task.CustomFields {
+		current[f.ID] = f.Value
+	}
+	pushed := sink.mappedFieldValues(context.Background(), b, current)
+	got := map[string]any{}
+	for _, f := range pushed {
+		got[f.ID] = f.Value
+	}
+	wantRelease := time.Date(2026, 4, 1, 0, 0, 0, 0, time.Local).UnixMilli()
+	if len(got) != 3 || got["f-num"] != float64(3) || got["f-date"] != wantRelease || got["f-drop"] != "opt-api" {
+		t.Errorf("mappedFieldValues() = %v", got)
+	}
+
+	// A cleared task value removes the bean's key
+	task.CustomFields = nil
+	changes, _ = sink.CustomFieldChanges(context.Background(), b, task)
+	if len(changes) != 4 || changes[0].Value != nil || changes[0].Task != "" {
+		t.Errorf("cleared = %+v", changes)
+	}
+}
+
+func TestBeanFieldValue_Invalid(t *testing.T) {
+	for _, tt := range []struct {
+		def   FieldInfo
+		value any
+		want  string
+	}{
+		{FieldInfo{Name: "Estimate", Type: fieldTypeNumber}, "lots", "not a number"},
+		{FieldInfo{Name: "Release", Type: fieldTypeDate}, "May", "not a YYYY-MM-DD date"},
+		{FieldInfo{Name: "Area", Type: fieldTypeDropDown}, "API", `has no option "API"`},
+		{FieldInfo{Name: "Vote", Type: "emoji"}, 3, "emoji fields aren't supported"},
+		{FieldInfo{Name: "Contact", Type: fieldTypeEmail}, "Ann <ann@example.com>", "not an email address"},
+		{FieldInfo{Name: "Contact", Type: fieldTypeEmail}, "ann@", "not an email address"},
+		{FieldInfo{Name: "Phone", Type: fieldTypePhone}, "555 0100", "with a +country code"},
+		{FieldInfo{Name: "Site", Type: fieldTypeLocation}, "Paris", "not a location"},
+		{FieldInfo{Name: "Site", Type: fieldTypeLocation}, "91, 0", "out of range"},
+		{FieldInfo{Name: "Cost", Type: fieldTypeCurrency, TypeConfig: map[string]any{"currency_type": "USD"}}, "EUR 5", `"EUR 5" is not in USD`},
+		{FieldInfo{Name: "Cost", Type: fieldTypeCurrency, TypeConfig: map[string]any{"precision": 2}}, 1.005, "more than 2 decimal places"},
+		{FieldInfo{Name: "Cost", Type: fieldTypeCurrency}, "cheap", "not an amount"},
+	} {
+		if _, _, err := beanFieldValue(tt.def, tt.value); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s %v: error %v, want %q", tt.def.Type, tt.value, err, tt.want)
+		}
+	}
+}
+
+func TestResolveOption(t *testing.T) {
+	def := FieldInfo{Name: "Stage", Type: fieldTypeDropDown, TypeConfig: map[string]any{"options": []any{
+		map[string]any{"id": "opt-review", "name": "In Review"},
+		map[string]any{"id": "opt-done", "name": "Done"},
+		map[string]any{"id": "opt-done2", "name": "DONE!"},
+	}}}
+	for _, tt := range []struct {
+		name, want, err string
+	}{
+		{"In Review", "opt-review", ""},
+		{"opt-done", "opt-done", ""},
+		{"in review", "opt-review", ""},
+		{"in-review", "opt-review", ""},
+		{"done", "opt-done", ""},
+		{"done.", "", `option "done." is ambiguous (matches "Done", "DONE!")`},
+		{"DONE!", "opt-done2", ""},
+		{"Shipped", "", `Stage has no option "Shipped" (use "In Review", "Done", "DONE!")`},
+		{"--", "", "has no option"},
+	} {
+		got, _, err := beanFieldValue(def, tt.name)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%q: error %v, want %q", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%q = %v, %v, want %s", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestLabelsField(t *testing.T) {
+	def := FieldInfo{Name: "Platforms", Type: fieldTypeLabels, TypeConfig: map[string]any{"options": []any{
+		map[string]any{"id": "lbl-ios", "label": "iOS"},
+		map[string]any{"id": "lbl-android", "label": "Android"},
+		map[string]any{"id": "lbl-web", "label": "Web"},
+	}}}
+
+	value, canon, err := beanFieldValue(def, []any{"web", "ios", "iOS"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := value.([]string); !slices.Equal(ids, []string{"lbl-web", "lbl-ios"}) || canon != "Web, iOS" {
+		t.Errorf("beanFieldValue() = %v, %q", value, canon)
+	}
+	if value, canon, _ := beanFieldValue(def, "Android"); !slices.Equal(value.([]string), []string{"lbl-android"}) || canon != "Android" {
+		t.Errorf("single name = %v, %q", value, canon)
+	}
+	if _, _, err := beanFieldValue(def, []any{"iOS", "Linux"}); err == nil || !strings.Contains(err.Error(), `no option "Linux"`) {
+		t.Errorf("unknown label error %v", err)
+	}
+
+	// The task's IDs come back as names, in the same canonical form
+	value, taskCanon, ok := taskFieldValue(def, []any{"lbl-ios", "lbl-web", "lbl-gone"})
+	if !ok || !slices.Equal(value.([]string), []string{"Web", "iOS"}) || taskCanon != canon {
+		t.Errorf("taskFieldValue() = %v, %q, %v", value, taskCanon, ok)
+	}
+	if _, _, ok := taskFieldValue(def, []any{}); ok {
+		t.Error("no labels should have no value")
+	}
+}
+
+func TestFieldValues(t *testing.T) {
+	cost := FieldInfo{Type: fieldTypeCurrency, TypeConfig: map[string]any{"currency_type": "USD", "precision": 2}}
+	for _, tt := range []struct {
+		def   FieldInfo
+		value any
+		canon string
+	}{
+		{FieldInfo{Type: fieldTypeEmail}, " ann@example.com ", "ann@example.com"},
+		{FieldInfo{Type: fieldTypePhone}, "+1 (555) 010-0199", "+15550100199"},
+		{FieldInfo{Type: fieldTypeLocation}, "48.8584, 2.2945", "48.8584,2.2945"},
+		{FieldInfo{Type: fieldTypeLocation}, map[string]any{"lat": 48.8584, "lng": 2.2945, "address": "Paris"}, "48.8584,2.2945"},
+		{cost, 12.5, "12.5"},
+		{cost, "USD 12.50", "12.5"},
+		{cost, "12.50 usd", "12.5"},
+	} {
+		value, canon, err := beanFieldValue(tt.def, tt.value)
+		if err != nil || canon != tt.canon {
+			t.Errorf("%s %v = %q, %v, want %q", tt.def.Type, tt.value, canon, err, tt.canon)
+			continue
+		}
+		// The value ClickUp stores reads back the same
+		if tt.def.Type == fieldTypeLocation {
+			value = map[string]any{"location": value.(map[string]any)["location"]}
+		}
+		if _, taskCanon, ok := taskFieldValue(tt.def, value); !ok || taskCanon != canon {
+			t.Errorf("%s %v: task canon %q, %v, want %q", tt.def.Type, tt.value, taskCanon, ok, canon)
+		}
+	}
+
+	// A location pulls back with its address
+	got, _, _ := taskFieldValue(FieldInfo{Type: fieldTypeLocation}, map[string]any{
+		"location": map[string]any{"lat": 1.5, "lng": -2.0}, "formatted_address": "Somewhere",
+	})
+	if m := got.(map[string]any); m["lat"] != 1.5 || m["lng"] != -2.0 || m["address"] != "Somewhere" {
+		t.Errorf("pulled location = %v", got)
+	}
+}
+
+func TestValidateBean(t *testing.T) {
+	sink := NewSink(nil, &config.ClickUpConfig{CustomFields: &config.CustomFieldsMap{
+		Progress: "f-progress",
+		Fields:   map[string]string{"contact": "f-email", "phone": "f-phone", "missing": "f-missing"},
+	}}, "list")
+	sink.fieldInfos = map[string]FieldInfo{
+		"f-email": {ID: "f-email", Name: "Contact", Type: fieldTypeEmail},
+		"f-phone": {ID: "f-phone", Name: "Phone", Type: fieldTypePhone},
+	}
+
+	b := &beans.Bean{Extensions: map[string]map[string]any{beans.PluginClickUp: {
+		"contact": "ann@example.com", "phone": "555", "missing": "x",
+	}}}
+	err := sink.ValidateBean(context.Background(), b)
+	if err == nil || !strings.Contains(err.Error(), `extensions.clickup.phone: Phone: "555" is not a phone number`) || strings.Contains(err.Error(), "contact") {
+		t.Errorf("ValidateBean() = %v, want only the phone rejected", err)
+	}
+
+	b.Extensions[beans.PluginClickUp]["phone"] = "+44 20 7946 0958"
+	b.Extensions[beans.PluginClickUp]["progress"] = "half"
+	if err := sink.ValidateBean(context.Background(), b); err == nil || !strings.Contains(err.Error(), "progress") {
+		t.Errorf("ValidateBean() = %v, want the progress rejected", err)
+	}
+	delete(b.Extensions[beans.PluginClickUp], "progress")
+	if err := sink.ValidateBean(context.Background(), b); err != nil {
+		t.Errorf("ValidateBean() = %v for valid values", err)
+	}
+}
+
+func TestNewSinkFromConfig_MappedFields(t *testing.T) {
+	cfg := config.ClickUpConfig{ListID: "1", CustomFields: &config.CustomFieldsMap{Fields: map[string]string{"sprint": "f1"}}}
+	_, err := newSinkFromConfig(&config.Config{Beans: config.BeansWrapper{ClickUp: cfg}})
+	if err == nil || !strings.Contains(err.Error(), `can't map "sprint"`) {
+		t.Errorf("error %v, want reserved key error", err)
+	}
+}
@@ -0,0 +1,168 @@
+package clickup
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+)
+
+// Values accepted by description_overflow.
+const (
+	OverflowComment    = "comment"
+	OverflowAttachment = "attachment"
+	OverflowTruncate   = "truncate"
+)
+
+// overflowNotes end a truncated description, saying where the rest went.
+var overflowNotes = map[string]string{
+	OverflowComment:    "\n\n---\n_Description truncated; continued in the comments._",
+	OverflowAttachment: "\n\n---\n_Description truncated; the full text is attached as %s._",
+	OverflowTruncate:   "\n\n---\n_Description truncated._",
+}
+
+// overflowMode returns the configured description_overflow, defaulting to
+// comment.
+func (s *Sink) overflowMode() string {
+	if s.config != nil && s.config.DescriptionOverflow != "" {
+		return s.config.DescriptionOverflow
+	}
+	return OverflowComment
+}
+
+func (s *Sink) descriptionLimit() int {
+	if s.config != nil && s.config.DescriptionLimit > 0 {
+		return s.config.DescriptionLimit
+	}
+	return config.DefaultDescriptionLimit
+}
+
+// sourceFooter returns the source_footer line for a bean, or "".
+func (s *Sink) sourceFooter(b *beans.Bean) string {
+	if s.config == nil || !s.config.SourceFooter {
+		return ""
+	}
+	u := s.SourceURL(b)
+	if u == "" {
+		return ""
+	}
+	return fmt.Sprintf("\n\n---\n_Source: [%s](%s)_", b.Path, u)
+}
+
+// splitDescription returns the task description for a bean, after
+// FormatMarkdown unless raw_markdown is set, and the part that didn't fit
+// within the description limit, if any. A long body is cut at the last
+// line break before the limit where possible and ends with a note saying
+// where the rest went. The source footer, if any, always fits.
+func (s *Sink) splitDescription(b *beans.Bean) (description, overflow string) {
+	text := b.Body
+	if s.config == nil || !s.config.RawMarkdown {
+		text = FormatMarkdown(text)
+	}
+	footer := s.sourceFooter(b)
+	if text == "" {
+		footer = strings.TrimPrefix(footer, "\n\n---\n")
+	}
+	body := []rune(text)
+	limit := max(s.descriptionLimit()-utf8.RuneCountInString(footer), 0)
+	if len(body) <= limit {
+		return text + footer, ""
+	}
+
+	note := overflowNotes[s.overflowMode()]
+	if s.overflowMode() == OverflowAttachment {
+		note = fmt.Sprintf(note, attachmentName(b))
+	}
+	cut := max(limit-utf8.RuneCountInString(note), 0)
+	// Prefer a line break in the last tenth, so lines aren't split
+	head := string(body[:cut])
+	if i := strings.LastIndex(head, "\n"); i >= 0 && utf8.RuneCountInString(head[:i]) >= cut*9/10 {
+		cut = utf8.RuneCountInString(head[:i])
+	}
+	return string(body[:cut]) + note + footer, strings.TrimLeft(string(body[cut:]), "\n")
+}
+
+// attachmentName is the file the full body is attached as.
+func attachmentName(b *beans.Bean) string {
+	return b.ID + ".md"
+}
+
+// writeOverflow puts the part of a bean's body that didn't fit in the task
+// description where description_overflow says: comments (split to fit),
+// or the full body as a markdown attachment. Best-effort, like tags.
+//
+// current is the existing task, or nil for a new one. An existing task
+// isn't given the same overflow again, so rewriting the start of a long
+// description doesn't repeat the comments or attachment each time.
+func (s *Sink) writeOverflow(ctx context.Context, taskID string, current *TaskInfo, b *beans.Bean, overflow string) {
+	if overflow == "" {
+		return
+	}
+	switch s.overflowMode() {
+	case OverflowComment:
+		parts := overflowComments(overflow, s.descriptionLimit())
+		if current != nil && s.hasComments(ctx, taskID, parts) {
+			return
+		}
+		for _, part := range parts {
+			req := &CreateCommentRequest{Comment: []CommentPart{{Text: part}}}
+			if _, err := s.client.CreateTaskComment(ctx, taskID, req); err != nil {
+				return // Best-effort
+			}
+		}
+	case OverflowAttachment:
+		if current != nil && s.hasAttachment(ctx, current, attachmentName(b), b.Body) {
+			return
+		}
+		if err := s.client.CreateTaskAttachment(ctx, taskID, attachmentName(b), []byte(b.Body)); err != nil {
+			_ = err // Best-effort
+		}
+	}
+}
+
+// overflowComments splits overflow into comments of at most limit runes.
+func overflowComments(overflow string, limit int) []string {
+	var parts []string
+	for rest := []rune(overflow); len(rest) > 0; {
+		n := min(len(rest), limit)
+		parts = append(parts, string(rest[:n]))
+		rest = rest[n:]
+	}
+	return parts
+}
+
+// hasComments reports whether every part is already a comment on the task.
+// If the comments can't be read, it reports false so they're posted.
+func (s *Sink) hasComments(ctx context.Context, taskID string, parts []string) bool {
+	comments, err := s.client.GetTaskComments(ctx, taskID)
+	if err != nil {
+		return false
+	}
+	posted := make(map[string]bool, len(comments))
+	for _, c := range comments {
+		posted[c.CommentText] = true
+	}
+	for _, part := range parts {
+		if !posted[part] {
+			return false
+		}
+	}
+	return true
+}
+
+// hasAttachment reports whether the task's latest attachment named name
+// holds body. If it can't be downloaded, it reports false so it's uploaded.
+func (s *Sink) hasAttachment(ctx context.Context, task *TaskInfo, name, body string) bool {
+	for _, a := range slices.Backward(task.Attachments) {
+		if a.Title != name {
+			continue
+		}
+		data, err := s.client.DownloadAttachment(ctx, a.URL)
+		return err == nil && string(data) == body
+	}
+	return false
+}
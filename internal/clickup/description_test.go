@@ -0,0 +1,93 @@
+package clickup
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+)
+
+func TestSplitDescription(t *testing.T) {
+	sink := NewSink(nil, &config.ClickUpConfig{DescriptionLimit: 100}, "test-list")
+
+	short := &beans.Bean{ID: "bean-1", Body: "short"}
+	if desc, overflow := sink.splitDescription(short); desc != "short" || overflow != "" {
+		t.Errorf("short body = %q, %q", desc, overflow)
+	}
+
+	// The cut lands on the line break just before the limit
+	b := &beans.Bean{ID: "bean-1", Body: strings.Repeat("a", 40) + "\n" + strings.Repeat("b", 100)}
+	sink.config.DescriptionLimit = 40 + len(overflowNotes[OverflowComment]) + 3
+	desc, overflow := sink.splitDescription(b)
+	if want := strings.Repeat("a", 40) + overflowNotes[OverflowComment]; desc != want {
+		t.Errorf("description = %q, want %q", desc, want)
+	}
+	if overflow != strings.Repeat("b", 100) {
+		t.Errorf("overflow = %q", overflow)
+	}
+	if len([]rune(desc)) > sink.config.DescriptionLimit {
+		t.Errorf("description is %d characters, over the limit", len([]rune(desc)))
+	}
+}
+
+func TestWriteOverflow(t *testing.T) {
+	var comments int
+	var attachment string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/comment"):
+			comments++
+			_, _ = w.Write([]byte(`{"id":1}`))
+		case strings.HasSuffix(r.URL.Path, "/attachment"):
+			if _, header, err := r.FormFile("attachment"); err == nil {
+				attachment = header.Filename
+			}
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+	client := NewClient("test", WithBaseURL(server.URL))
+	b := &beans.Bean{ID: "bean-1", Body: "full body"}
+
+	sink := NewSink(client, &config.ClickUpConfig{DescriptionLimit: 10}, "test-list")
+	sink.writeOverflow(context.Background(), "task-1", nil, b, strings.Repeat("x", 25))
+	if comments != 3 {
+		t.Errorf("posted %d comments, want 3", comments)
+	}
+
+	sink.config.DescriptionOverflow = OverflowAttachment
+	sink.writeOverflow(context.Background(), "task-1", nil, b, "rest")
+	if attachment != "bean-1.md" {
+		t.Errorf("attachment = %q, want bean-1.md", attachment)
+	}
+}
+
+func TestSourceFooter(t *testing.T) {
+	sink := NewSink(nil, &config.ClickUpConfig{SourceFooter: true, DescriptionLimit: 300}, "test-list")
+	b := &beans.Bean{ID: "bean-1", Path: "bean-1--x.md", Body: "body"}
+
+	// No links configured: no footer
+	if desc, _ := sink.splitDescription(b); desc != "body" {
+		t.Errorf("without links, description = %q", desc)
+	}
+
+	sink.SetSourceURL(func(b *beans.Bean) string { return "https://github.com/o/r/blob/abc/.beans/" + b.Path })
+	footer := "\n\n---\n_Source: [bean-1--x.md](https://github.com/o/r/blob/abc/.beans/bean-1--x.md)_"
+	if desc, _ := sink.splitDescription(b); desc != "body"+footer {
+		t.Errorf("description = %q", desc)
+	}
+
+	// A truncated description keeps the footer within the limit
+	b.Body = strings.Repeat("a", 400)
+	desc, overflow := sink.splitDescription(b)
+	if !strings.HasSuffix(desc, footer) || overflow == "" {
+		t.Errorf("truncated description = %q, overflow %q", desc, overflow)
+	}
+	if len([]rune(desc)) > 300 {
+		t.Errorf("description is %d characters, over the limit", len([]rune(desc)))
+	}
+}
@@ -0,0 +1,255 @@
+package clickup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+// FieldDiff compares one field of a bean, mapped to ClickUp's vocabulary,
+// with the same field of its task.
+type FieldDiff struct {
+	Field   string `json:"field"`
+	Bean    string `json:"bean"`
+	Task    string `json:"task"`
+	Changed bool   `json:"changed"`
+}
+
+// priorityNames are ClickUp's names for its priority IDs.
+var priorityNames = map[int]string{
+	1: "urgent",
+	2: "high",
+	3: "normal",
+	4: "low",
+}
+
+// Diff compares the fields sync manages — title, status, type, priority,
+// due date, tags, and description — between a bean and its task. Bean values
+// are shown as sync would write them, using the configured mappings.
+func (s *Sink) Diff(b *beans.Bean, task *TaskInfo) []FieldDiff {
+	field := func(name, bean, task string) FieldDiff {
+		return FieldDiff{Field: name, Bean: bean, Task: task, Changed: bean != task}
+	}
+
+	status := field("status", s.getClickUpStatus(b.Status), task.Status.Status)
+	if status.Bean == "" {
+		// Unmapped statuses are left alone by sync
+		status.Bean, status.Changed = b.Status+" (unmapped)", false
+	}
+
+	beanTags := s.taskTags(b)
+	var taskTags []string
+	for _, t := range task.Tags {
+		taskTags = append(taskTags, t.Name)
+	}
+	taskTags = s.managedTaskTags(beanTags, taskTags)
+	slices.SortFunc(taskTags, compareFold)
+	slices.SortFunc(beanTags, compareFold)
+	tags := field("tags", strings.Join(beanTags, ", "), strings.Join(taskTags, ", "))
+	// Matching ignores case, as sync does
+	tags.Changed = !strings.EqualFold(tags.Bean, tags.Task)
+
+	return []FieldDiff{
+		field("title", s.TaskName(b), task.Name),
+		status,
+		s.TypeDiff(b, task),
+		s.PriorityDiff(b, task),
+		field("due", formatDueMillis(beanDueToMillis(b.Due)), formatDueMillis(clickUpDueToMillis(task.DueDate))),
+		tags,
+		field("description", s.buildTaskDescription(b), task.Description),
+	}
+}
+
+// TagFingerprints hashes the task's tags that sync manages and the tags it
+// would give the task for the bean, ignoring case and order as sync does.
+func (s *Sink) TagFingerprints(task *syncer.TaskRef, b *beans.Bean) (taskTags, beanTags string) {
+	if s.clickUpOwns("tags") {
+		return "", "" // task tag edits are ClickUp's to make
+	}
+	want := s.taskTags(b)
+	return tagFingerprint(s.managedTaskTags(want, task.Tags)), tagFingerprint(want)
+}
+
+// managedTaskTags returns the task tags that are the bean's or that sync
+// manages. The rest (protected, or outside tag_prefix) sync leaves alone,
+// so they aren't drift.
+func (s *Sink) managedTaskTags(beanTags, taskTags []string) []string {
+	var managed []string
+	for _, t := range taskTags {
+		isBeanTag := slices.ContainsFunc(beanTags, func(bt string) bool { return strings.EqualFold(bt, t) })
+		if isBeanTag || s.managesTag(t) {
+			managed = append(managed, t)
+		}
+	}
+	return managed
+}
+
+// tagFingerprint hashes a tag set, ignoring case, order, and duplicates.
+func tagFingerprint(tags []string) string {
+	lower := make([]string, len(tags))
+	for i, t := range tags {
+		lower[i] = strings.ToLower(t)
+	}
+	slices.Sort(lower)
+	sum := sha256.Sum256([]byte(strings.Join(slices.Compact(lower), "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
+func compareFold(a, b string) int {
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// TypeDiff compares the bean's type with the task's custom task type, both
+// named as bean types via type_mapping. Bean types without a mapping are
+// left alone by sync, so they never differ.
+func (s *Sink) TypeDiff(b *beans.Bean, task *TaskInfo) FieldDiff {
+	want := s.getClickUpCustomItemID(b.Type)
+	d := FieldDiff{Field: "type", Bean: b.Type, Task: s.typeName(task.CustomItemID)}
+	switch {
+	case want == nil:
+		d.Bean = b.Type + " (unmapped)"
+	case task.CustomItemID != nil && *task.CustomItemID == *want:
+		d.Task = b.Type // Several bean types may share a task type
+	default:
+		d.Changed = true
+	}
+	return d
+}
+
+// BeanType returns the bean type a custom task type ID is mapped from in
+// type_mapping, taking the first by name when several share it.
+func (s *Sink) BeanType(customItemID *int) (string, bool) {
+	if customItemID == nil || s.config == nil {
+		return "", false
+	}
+	for _, beanType := range slices.Sorted(maps.Keys(s.config.TypeMapping)) {
+		if s.config.TypeMapping[beanType] == *customItemID {
+			return beanType, true
+		}
+	}
+	return "", false
+}
+
+// StatusDiff compares the bean's status, mapped by status_mapping, with
+// the task's. Unmapped bean statuses are left alone by sync, so they
+// aren't a difference.
+func (s *Sink) StatusDiff(b *beans.Bean, task *TaskInfo) FieldDiff {
+	d := FieldDiff{Field: "status", Bean: s.getClickUpStatus(b.Status), Task: task.Status.Status}
+	d.Changed = d.Bean != "" && !strings.EqualFold(d.Bean, d.Task)
+	return d
+}
+
+// BeanStatus returns the bean status that status_mapping, over the
+// defaults, maps to the ClickUp status. When several do, the one named
+// like the ClickUp status wins, then the first by name.
+func (s *Sink) BeanStatus(status string) (string, bool) {
+	beanStatuses := slices.Collect(maps.Keys(config.DefaultStatusMapping))
+	if s.config != nil {
+		beanStatuses = slices.AppendSeq(beanStatuses, maps.Keys(s.config.StatusMapping))
+	}
+	slices.Sort(beanStatuses)
+	var mapped []string
+	for _, beanStatus := range slices.Compact(beanStatuses) {
+		if strings.EqualFold(s.getClickUpStatus(beanStatus), status) {
+			mapped = append(mapped, beanStatus)
+		}
+	}
+	if len(mapped) == 0 {
+		return "", false
+	}
+	if i := slices.IndexFunc(mapped, func(m string) bool { return strings.EqualFold(m, status) }); i >= 0 {
+		return mapped[i], true
+	}
+	return mapped[0], true
+}
+
+// DueDiff compares the bean's due date with the task's, as YYYY-MM-DD.
+func (s *Sink) DueDiff(b *beans.Bean, task *TaskInfo) FieldDiff {
+	d := FieldDiff{Field: "due", Bean: formatDueMillis(beanDueToMillis(b.Due)), Task: formatDueMillis(clickUpDueToMillis(task.DueDate))}
+	d.Changed = d.Bean != d.Task
+	return d
+}
+
+// PriorityDiff compares the bean's priority with the task's, both named as
+// ClickUp priorities via priority_mapping.
+func (s *Sink) PriorityDiff(b *beans.Bean, task *TaskInfo) FieldDiff {
+	var taskPriority *int
+	if task.Priority != nil {
+		taskPriority = &task.Priority.ID
+	}
+	d := FieldDiff{Field: "priority", Bean: priorityName(s.getClickUpPriority(b.Priority)), Task: priorityName(taskPriority)}
+	d.Changed = d.Bean != d.Task
+	return d
+}
+
+// BeanPriority returns the bean priority a ClickUp priority ID is mapped
+// from in priority_mapping. When several share it, the one named like the
+// ClickUp priority wins, then the first by name.
+func (s *Sink) BeanPriority(id *int) (string, bool) {
+	if id == nil {
+		return "", false
+	}
+	mapping := s.priorityMapping()
+	var mapped []string
+	for _, beanPriority := range slices.Sorted(maps.Keys(mapping)) {
+		if mapping[beanPriority] == *id {
+			mapped = append(mapped, beanPriority)
+		}
+	}
+	if len(mapped) == 0 {
+		return "", false
+	}
+	if slices.Contains(mapped, priorityNames[*id]) {
+		return priorityNames[*id], true
+	}
+	return mapped[0], true
+}
+
+// priorityMapping returns the effective bean→ClickUp priority mapping:
+// priority_mapping over the defaults.
+func (s *Sink) priorityMapping() map[string]int {
+	mapping := maps.Clone(config.DefaultPriorityMapping)
+	if s.config != nil {
+		maps.Copy(mapping, s.config.PriorityMapping)
+	}
+	return mapping
+}
+
+// typeName names a task's custom task type as a bean type, or by ID if it
+// isn't mapped. A nil ID is ClickUp's default task type.
+func (s *Sink) typeName(customItemID *int) string {
+	if customItemID == nil {
+		return ""
+	}
+	if beanType, ok := s.BeanType(customItemID); ok {
+		return beanType
+	}
+	return "custom item " + strconv.Itoa(*customItemID)
+}
+
+// priorityName returns the ClickUp name of a priority ID, or "" for none.
+func priorityName(id *int) string {
+	if id == nil {
+		return ""
+	}
+	if name, ok := priorityNames[*id]; ok {
+		return name
+	}
+	return strconv.Itoa(*id)
+}
+
+// formatDueMillis formats a due date in Unix milliseconds as a local date.
+func formatDueMillis(millis *int64) string {
+	if millis == nil {
+		return ""
+	}
+	return time.UnixMilli(*millis).In(time.Local).Format("2006-01-02")
+}
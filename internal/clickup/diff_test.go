@@ -0,0 +1,144 @@
+package clickup
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+)
+
+func TestSinkDiff(t *testing.T) {
+	sink := NewSink(nil, &config.ClickUpConfig{
+		StatusMapping: map[string]string{"in-progress": "doing"},
+		TypeMapping:   map[string]int{"bug": 1001, "feature": 1002, "task": 1002},
+	}, "test-list")
+
+	due := "2026-03-01"
+	dueMillis := "1"
+	if millis := beanDueToMillis(&due); millis != nil {
+		dueMillis = strconv.FormatInt(*millis, 10)
+	}
+	b := &beans.Bean{
+		ID:       "bean-1",
+		Title:    "Fix login",
+		Status:   "in-progress",
+		Type:     "bug",
+		Priority: "high",
+		Due:      &due,
+		Tags:     []string{"ui", "auth"},
+		Body:     "Steps\nmore",
+	}
+	featureType := 1002
+	task := &TaskInfo{
+		Name:         "Fix login page",
+		Status:       Status{Status: "doing"},
+		CustomItemID: &featureType,
+		Priority:     &TaskPriority{ID: 3},
+		DueDate:      &dueMillis,
+		Tags:         []Tag{{Name: "auth"}, {Name: "ui"}},
+		Description:  "Steps",
+	}
+
+	got := make(map[string]FieldDiff)
+	for _, f := range sink.Diff(b, task) {
+		got[f.Field] = f
+	}
+
+	tests := []struct {
+		field      string
+		bean, task string
+		changed    bool
+	}{
+		{"title", "Fix login", "Fix login page", true},
+		{"status", "doing", "doing", false},
+		{"type", "bug", "feature", true},
+		{"priority", "high", "normal", true},
+		{"due", "2026-03-01", "2026-03-01", false},
+		{"tags", "auth, ui", "auth, ui", false},
+		{"description", "Steps\nmore", "Steps", true},
+	}
+	for _, tt := range tests {
+		f := got[tt.field]
+		if f.Bean != tt.bean || f.Task != tt.task || f.Changed != tt.changed {
+			t.Errorf("%s = %+v, want bean %q task %q changed %v", tt.field, f, tt.bean, tt.task, tt.changed)
+		}
+	}
+
+	b.Status = "someday"
+	for _, f := range sink.Diff(b, task) {
+		if f.Field == "status" && f.Changed {
+			t.Errorf("unmapped status reported as changed: %+v", f)
+		}
+	}
+
+	// Bean types sharing the task's type match it
+	b.Type = "task"
+	if f := sink.TypeDiff(b, task); f.Changed || f.Task != "task" {
+		t.Errorf("shared type = %+v", f)
+	}
+	b.Type = "milestone"
+	if f := sink.TypeDiff(b, task); f.Changed || f.Bean != "milestone (unmapped)" {
+		t.Errorf("unmapped type = %+v", f)
+	}
+	if beanType, ok := sink.BeanType(&featureType); !ok || beanType != "feature" {
+		t.Errorf("BeanType(1002) = %q, %v", beanType, ok)
+	}
+}
+
+func TestTitleTemplate(t *testing.T) {
+	sink := NewSink(nil, &config.ClickUpConfig{TitleTemplate: "[{{.ID}}] {{.Title}}"}, "test-list")
+	b := &beans.Bean{ID: "bean-1", Title: "Fix login"}
+
+	if got := sink.TaskName(b); got != "[bean-1] Fix login" {
+		t.Errorf("TaskName() = %q", got)
+	}
+
+	// A task already carrying the templated name is not renamed or flagged
+	task := &TaskInfo{Name: "[bean-1] Fix login"}
+	if update := sink.buildUpdateRequest(task, b, "", nil, ""); update.Name != nil {
+		t.Errorf("update.Name = %q, want unchanged", *update.Name)
+	}
+	if f := sink.Diff(b, task)[0]; f.Field != "title" || f.Changed {
+		t.Errorf("title diff = %+v, want unchanged", f)
+	}
+
+	if _, err := ParseTitleTemplate("{{.Nope}}"); err == nil {
+		t.Error("expected error for unknown field")
+	}
+	if _, err := ParseTitleTemplate("{{.Title"); err == nil {
+		t.Error("expected error for bad syntax")
+	}
+}
+
+func TestTaskTags(t *testing.T) {
+	sink := NewSink(nil, &config.ClickUpConfig{
+		TagMapping:   map[string]string{"backend": "Back End"},
+		TagCase:      "title",
+		TagSeparator: " ",
+		TagPrefix:    "bean/",
+	}, "test-list")
+
+	got := sink.taskTags(&beans.Bean{Tags: []string{"backend", "needs_review", "ui"}})
+	want := []string{"Back End", "bean/Needs Review", "bean/Ui"}
+	if !slices.Equal(got, want) {
+		t.Errorf("taskTags() = %q, want %q", got, want)
+	}
+
+	// Mapped targets are managed even without the prefix
+	if !sink.managesTag("back end") {
+		t.Error("managesTag(back end) = false, want true")
+	}
+	if sink.managesTag("manual") {
+		t.Error("managesTag(manual) = true, want false")
+	}
+
+	// ClickUp's lower-cased copy of a mapped tag isn't drift
+	task := &TaskInfo{Tags: []Tag{{Name: "back end"}, {Name: "manual"}}}
+	for _, f := range sink.Diff(&beans.Bean{Tags: []string{"backend"}}, task) {
+		if f.Field == "tags" && f.Changed {
+			t.Errorf("tags diff = %+v, want unchanged", f)
+		}
+	}
+}
@@ -0,0 +1,682 @@
+package clickup
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/clickuptest"
+	"github.com/toba/bean-me-up/internal/config"
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+func TestSyncAgainstMockServer(t *testing.T) {
+	mock := clickuptest.Start()
+	defer mock.Close()
+	beanIDField := mock.AddField(clickuptest.ListID, "Bean ID", "text")
+
+	client := NewClient("token", WithBaseURL(mock.URL))
+	sink := NewSink(client, &config.ClickUpConfig{
+		CustomFields: &config.CustomFieldsMap{BeanID: beanIDField},
+	}, clickuptest.ListID)
+	state := newMemorySyncProvider()
+	ctx := context.Background()
+
+	now := time.Now()
+	due := "2026-03-01"
+	all := []beans.Bean{
+		{ID: "epic", Title: "Epic", Status: "in-progress", Type: "epic", Blocking: []string{"other"}, UpdatedAt: &now},
+		{ID: "child", Title: "Child", Status: "todo", Type: "task", Parent: "epic", Priority: "high", Due: &due,
+			Tags: []string{"frontend"}, UpdatedAt: &now},
+		{ID: "other", Title: "Other", Status: "completed", Type: "bug", UpdatedAt: &now},
+	}
+	results, err := syncer.New(sink, syncer.Options{}, state).SyncBeans(ctx, all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.Action != "created" {
+			t.Fatalf("first sync: %s %s (%v)", r.BeanID, r.Action, r.Error)
+		}
+	}
+
+	tasks := mock.Tasks(clickuptest.ListID)
+	if len(tasks) != 3 {
+		t.Fatalf("mock has %d tasks, want 3", len(tasks))
+	}
+	epic, _ := mock.Task(*state.GetTaskID("epic"))
+	child, _ := mock.Task(*state.GetTaskID("child"))
+	other, _ := mock.Task(*state.GetTaskID("other"))
+	if epic.Status != "in progress" || other.Status != "complete" || child.Status != "to do" {
+		t.Errorf("statuses = %q, %q, %q", epic.Status, child.Status, other.Status)
+	}
+	if child.Parent != epic.ID || child.Priority == nil || *child.Priority != 2 || child.DueDate == nil {
+		t.Errorf("child = %+v", child)
+	}
+	if !slices.Equal(child.Tags, []string{"frontend"}) || !slices.Contains(mock.SpaceTags(), "frontend") {
+		t.Errorf("child tags = %v, space tags = %v", child.Tags, mock.SpaceTags())
+	}
+	if child.Fields[beanIDField] != "child" {
+		t.Errorf("child Bean ID field = %v", child.Fields[beanIDField])
+	}
+	if !slices.Equal(other.DependsOn, []string{epic.ID}) {
+		t.Errorf("other depends on %v, want the epic", other.DependsOn)
+	}
+
+	// Changing a bean updates only its task
+	later := now.Add(time.Minute)
+	all[1].Title, all[1].Tags, all[1].UpdatedAt = "Renamed", nil, &later
+	results, err = syncer.New(sink, syncer.Options{}, state).SyncBeans(ctx, all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		want := "skipped"
+		if r.BeanID == "child" {
+			want = "updated"
+		}
+		if r.Action != want {
+			t.Errorf("second sync: %s %s, want %s (%v)", r.BeanID, r.Action, want, r.Error)
+		}
+	}
+	child, _ = mock.Task(child.ID)
+	if child.Name != "Renamed" || len(child.Tags) != 0 {
+		t.Errorf("child after update = %+v", child)
+	}
+}
+
+func TestStatusOnlyAgainstMockServer(t *testing.T) {
+	mock := clickuptest.Start()
+	defer mock.Close()
+	taskID := mock.AddTask(clickuptest.Task{Name: "Linked", Status: "to do"})
+
+	sink := NewSink(NewClient("token", WithBaseURL(mock.URL)), &config.ClickUpConfig{}, clickuptest.ListID)
+	state := newMemorySyncProvider()
+	state.SetTaskID("linked", taskID)
+
+	all := []beans.Bean{
+		{ID: "linked", Title: "Renamed", Status: "completed"},
+		{ID: "new", Title: "New", Status: "todo"},
+	}
+	results, err := syncer.New(sink, syncer.Options{StatusOnly: true}, state).SyncBeans(context.Background(), all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Action != "updated" || results[1].Action != "skipped" {
+		t.Fatalf("results = %+v", results)
+	}
+
+	task, _ := mock.Task(taskID)
+	if task.Status != "complete" || task.Name != "Linked" {
+		t.Errorf("task = %q %q, want only the status changed", task.Name, task.Status)
+	}
+	if n := len(mock.Tasks(clickuptest.ListID)); n != 1 {
+		t.Errorf("%d tasks, want no task created", n)
+	}
+	var taskRequests []string
+	for _, r := range mock.Requests() {
+		if strings.Contains(r, "/task") {
+			taskRequests = append(taskRequests, r)
+		}
+	}
+	if !slices.Equal(taskRequests, []string{"GET /task/" + taskID, "PUT /task/" + taskID}) {
+		t.Errorf("task requests = %v, want a GET and a single PUT", taskRequests)
+	}
+	if state.GetSyncedAt("linked") != nil {
+		t.Error("status-only sync recorded synced_at")
+	}
+
+	// Pushing the same status again leaves the task alone
+	results, err = syncer.New(sink, syncer.Options{StatusOnly: true}, state).SyncBeans(context.Background(), all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Action != "unchanged" {
+		t.Errorf("second sync: %s, want unchanged", results[0].Action)
+	}
+	puts := 0
+	for _, r := range mock.Requests() {
+		if strings.HasPrefix(r, "PUT /task/") {
+			puts++
+		}
+	}
+	if puts != 1 {
+		t.Errorf("%d task PUTs, want no second one", puts)
+	}
+}
+
+func TestStatusOnlyReviewRequestAgainstMockServer(t *testing.T) {
+	mock := clickuptest.Start()
+	defer mock.Close()
+	taskID := mock.AddTask(clickuptest.Task{Name: "Linked", Status: "in progress"})
+
+	sink := NewSink(NewClient("token", WithBaseURL(mock.URL)), &config.ClickUpConfig{
+		Users:         map[string]int{"alice": 42},
+		ReviewRequest: &config.ReviewRequestConfig{Status: "completed", Message: "please review"},
+	}, clickuptest.ListID)
+	state := newMemorySyncProvider()
+	state.SetTaskID("linked", taskID)
+
+	all := []beans.Bean{{ID: "linked", Title: "Linked", Status: "completed", Extensions: map[string]map[string]any{
+		beans.PluginClickUp: {beans.ExtKeyReview: "alice"},
+	}}}
+	for range 2 {
+		if _, err := syncer.New(sink, syncer.Options{StatusOnly: true}, state).SyncBeans(context.Background(), all); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Only the sync that changed the status asks for review
+	task, _ := mock.Task(taskID)
+	if len(task.Comments) != 1 || task.Comments[0] != " please review" {
+		t.Errorf("comments = %q, want one review request", task.Comments)
+	}
+}
+
+func TestRelationshipsOnlyAgainstMockServer(t *testing.T) {
+	mock := clickuptest.Start()
+	defer mock.Close()
+	// Tasks linked to beans after the fact, without their relationships
+	epicID := mock.AddTask(clickuptest.Task{Name: "Epic", Status: "to do"})
+	childID := mock.AddTask(clickuptest.Task{Name: "Child", Status: "to do"})
+
+	sink := NewSink(NewClient("token", WithBaseURL(mock.URL)), &config.ClickUpConfig{}, clickuptest.ListID)
+	state := newMemorySyncProvider()
+	state.SetTaskID("epic", epicID)
+	state.SetTaskID("child", childID)
+
+	all := []beans.Bean{
+		{ID: "epic", Title: "Renamed", Status: "completed", Blocking: []string{"child"}},
+		{ID: "child", Title: "Child", Status: "todo", Parent: "epic"},
+		{ID: "new", Title: "New", Status: "todo", Parent: "epic"},
+	}
+	opts := syncer.Options{RelationshipsOnly: true}
+	results, err := syncer.New(sink, opts, state).SyncBeans(context.Background(), all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{"updated", "updated", "skipped"} {
+		if results[i].Action != want {
+			t.Errorf("%s: %s (%v), want %s", results[i].BeanID, results[i].Action, results[i].Error, want)
+		}
+	}
+
+	epic, _ := mock.Task(epicID)
+	child, _ := mock.Task(childID)
+	if epic.Name != "Epic" || epic.Status != "to do" {
+		t.Errorf("epic = %q %q, want it left alone", epic.Name, epic.Status)
+	}
+	if child.Parent != epicID || !slices.Equal(child.DependsOn, []string{epicID}) {
+		t.Errorf("child under %q depending on %v, want the epic for both", child.Parent, child.DependsOn)
+	}
+	if n := len(mock.Tasks(clickuptest.ListID)); n != 2 {
+		t.Errorf("%d tasks, want no task created", n)
+	}
+
+	// Once set, the parent isn't sent again
+	before := len(mock.Requests())
+	if _, err := syncer.New(sink, opts, state).SyncBeans(context.Background(), all); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range mock.Requests()[before:] {
+		if strings.HasPrefix(r, "PUT ") {
+			t.Errorf("second pass sent %s", r)
+		}
+	}
+}
+
+func TestRemoveTaskAgainstMockServer(t *testing.T) {
+	mock := clickuptest.Start()
+	defer mock.Close()
+	client := NewClient("token", WithBaseURL(mock.URL))
+
+	for _, action := range []string{"", OnDeleteClose, OnDeleteArchive, OnDeleteDelete} {
+		taskID := mock.AddTask(clickuptest.Task{Name: "Deleted bean", Status: "to do"})
+		sink := NewSink(client, &config.ClickUpConfig{OnDelete: action}, clickuptest.ListID)
+		got, err := sink.RemoveTask(context.Background(), taskID)
+		if err != nil || got != action {
+			t.Fatalf("RemoveTask() with on_delete %q = %q, %v", action, got, err)
+		}
+
+		task, ok := mock.Task(taskID)
+		switch action {
+		case "":
+			if task.Status != "to do" || task.Archived {
+				t.Errorf("on_delete unset changed the task: %+v", task)
+			}
+		case OnDeleteClose:
+			if task.Status != "closed" {
+				t.Errorf("closed task status = %q", task.Status)
+			}
+		case OnDeleteArchive:
+			if !task.Archived {
+				t.Error("task not archived")
+			}
+		case OnDeleteDelete:
+			if ok {
+				t.Error("task not deleted")
+			}
+			// A task that is already gone counts as removed
+			if _, err := sink.RemoveTask(context.Background(), taskID); err != nil {
+				t.Errorf("removing a deleted task: %v", err)
+			}
+		}
+	}
+}
+
+func TestRemoveTask_CloseStatus(t *testing.T) {
+	mock := clickuptest.Start()
+	defer mock.Close()
+	mock.AddList("200", "No closed status", "to do", "done")
+	client := NewClient("token", WithBaseURL(mock.URL))
+	ctx := context.Background()
+
+	// scrapped mapped to nothing, or to a status the list lacks, falls
+	// back to the list's closed status
+	for _, mapped := range []string{"", "dropped"} {
+		taskID := mock.AddTask(clickuptest.Task{Name: "Deleted bean", Status: "to do"})
+		sink := NewSink(client, &config.ClickUpConfig{OnDelete: OnDeleteClose, StatusMapping: map[string]string{"scrapped": mapped}}, clickuptest.ListID)
+		if _, err := sink.RemoveTask(ctx, taskID); err != nil {
+			t.Fatalf("scrapped → %q: %v", mapped, err)
+		}
+		if task, _ := mock.Task(taskID); task.Status != "closed" {
+			t.Errorf("scrapped → %q: status = %q, want closed", mapped, task.Status)
+		}
+	}
+
+	// Without a closed status to fall back on it's a config error, and
+	// nothing is sent
+	sink := NewSink(NewClient("token", WithBaseURL(mock.URL)), &config.ClickUpConfig{OnDelete: OnDeleteClose}, "200")
+	before := len(mock.Requests())
+	if _, err := sink.RemoveTask(ctx, "t1"); err == nil || !strings.Contains(err.Error(), "status_mapping.scrapped") {
+		t.Errorf("RemoveTask() error = %v, want a status_mapping error", err)
+	}
+	for _, r := range mock.Requests()[before:] {
+		if strings.HasPrefix(r, "PUT ") {
+			t.Errorf("sent %s", r)
+		}
+	}
+}
+
+func TestMovedTaskAgainstMockServer(t *testing.T) {
+	mock := clickuptest.Start()
+	defer mock.Close()
+	mock.AddList("200", "Elsewhere", clickuptest.DefaultStatuses...)
+	client := NewClient("token", WithBaseURL(mock.URL))
+	ctx := context.Background()
+
+	for _, onMove := range []string{"", syncer.OnMoveFollow, syncer.OnMoveReturn} {
+		taskID := mock.AddTask(clickuptest.Task{Name: "Bean", Status: "to do"})
+		mock.UpdateTask(taskID, func(task *clickuptest.Task) { task.ListID = "200" })
+
+		sink := NewSink(client, &config.ClickUpConfig{}, clickuptest.ListID)
+		state := newMemorySyncProvider()
+		state.SetTaskID("bean", taskID)
+		all := []beans.Bean{{ID: "bean", Title: "Renamed", Status: "todo"}}
+		results, err := syncer.New(sink, syncer.Options{OnMove: onMove}, state).SyncBeans(ctx, all)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r := results[0]; r.Action != "moved" || r.MovedTo != "list Elsewhere" {
+			t.Fatalf("on_move %q: result = %+v", onMove, r)
+		}
+
+		task, _ := mock.Task(taskID)
+		switch onMove {
+		case "":
+			if task.Name != "Bean" || state.GetSyncedAt("bean") != nil {
+				t.Errorf("warn: task %q was edited", task.Name)
+			}
+		case syncer.OnMoveFollow:
+			if task.Name != "Renamed" || task.ListID != "200" {
+				t.Errorf("follow: task = %q in list %s", task.Name, task.ListID)
+			}
+		case syncer.OnMoveReturn:
+			if task.Name != "Renamed" || task.ListID != clickuptest.ListID {
+				t.Errorf("return: task = %q in list %s", task.Name, task.ListID)
+			}
+		}
+	}
+}
+
+func TestEpicsAsListsAgainstMockServer(t *testing.T) {
+	for _, epicsAs := range []string{EpicsAsList, EpicsAsFolder} {
+		t.Run(epicsAs, func(t *testing.T) {
+			mock := clickuptest.Start()
+			defer mock.Close()
+
+			sink := NewSink(NewClient("token", WithBaseURL(mock.URL)), &config.ClickUpConfig{EpicsAs: epicsAs}, clickuptest.ListID)
+			state := newMemorySyncProvider()
+			ctx := context.Background()
+
+			now := time.Now()
+			all := []beans.Bean{
+				{ID: "grandchild", Title: "Grandchild", Status: "todo", Type: "task", Parent: "child", UpdatedAt: &now},
+				{ID: "child", Title: "Child", Status: "todo", Type: "feature", Parent: "epic", UpdatedAt: &now},
+				{ID: "epic", Title: "Epic", Status: "in-progress", Type: "epic", Body: "The goal.", Blocking: []string{"loose"}, UpdatedAt: &now},
+				{ID: "loose", Title: "Loose", Status: "todo", Type: "task", UpdatedAt: &now},
+			}
+			results, err := syncer.New(sink, syncer.Options{}, state).SyncBeans(ctx, all)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range results {
+				if r.Action != "created" {
+					t.Fatalf("%s %s (%v)", r.BeanID, r.Action, r.Error)
+				}
+			}
+
+			listID, ok := parseListRef(*state.GetTaskID("epic"))
+			list, found := mock.List(listID)
+			if !ok || !found {
+				t.Fatalf("epic linked to %q, want a list", *state.GetTaskID("epic"))
+			}
+			if list.Name != "Epic" || !strings.HasPrefix(list.Content, "The goal.\n") || !strings.Contains(list.Content, "Bean: epic · Status: in-progress") {
+				t.Errorf("list = %+v", list)
+			}
+			folder, inFolder := mock.Folder(list.FolderID)
+			if epicsAs == EpicsAsFolder && (!inFolder || folder != "Epic") {
+				t.Errorf("list folder = %q %q, want a folder named Epic", list.FolderID, folder)
+			}
+			if epicsAs == EpicsAsList && list.FolderID != "" {
+				t.Errorf("list is in folder %q, want it next to the folderless list_id", list.FolderID)
+			}
+
+			child, _ := mock.Task(*state.GetTaskID("child"))
+			grandchild, _ := mock.Task(*state.GetTaskID("grandchild"))
+			loose, _ := mock.Task(*state.GetTaskID("loose"))
+			if child.ListID != listID || child.Parent != "" {
+				t.Errorf("child in list %s under %q, want top level in the epic's list", child.ListID, child.Parent)
+			}
+			if grandchild.ListID != listID || grandchild.Parent != child.ID {
+				t.Errorf("grandchild in list %s under %q", grandchild.ListID, grandchild.Parent)
+			}
+			if loose.ListID != clickuptest.ListID || len(loose.DependsOn) != 0 {
+				t.Errorf("loose = %+v", loose)
+			}
+
+			// Renaming the epic renames its list (and folder); tasks in the
+			// epic's list aren't reported as moved
+			later := now.Add(time.Minute)
+			all[2].Title, all[2].UpdatedAt = "Renamed", &later
+			all[1].Title, all[1].UpdatedAt = "Child 2", &later
+			results, err = syncer.New(sink, syncer.Options{}, state).SyncBeans(ctx, all)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if results[1].Action != "updated" || results[2].Action != "updated" {
+				t.Errorf("second sync: child %s, epic %s", results[1].Action, results[2].Action)
+			}
+			list, _ = mock.List(listID)
+			folder, _ = mock.Folder(list.FolderID)
+			if list.Name != "Renamed" || (epicsAs == EpicsAsFolder && folder != "Renamed") {
+				t.Errorf("after rename: list %q, folder %q", list.Name, folder)
+			}
+		})
+	}
+}
+
+func TestWorklogAgainstMockServer(t *testing.T) {
+	mock := clickuptest.Start()
+	defer mock.Close()
+
+	sink := NewSink(NewClient("token", WithBaseURL(mock.URL)), &config.ClickUpConfig{TimeTracking: true}, clickuptest.ListID)
+	state := newMemorySyncProvider()
+	ctx := context.Background()
+
+	now := time.Now()
+	all := []beans.Bean{{ID: "bean", Title: "Bean", Status: "todo", UpdatedAt: &now,
+		Body: "Work.\n\nworklog:\n- 2026-03-01 1h30m Investigated\n- 2026-03-02 45m\n"}}
+	if _, err := syncer.New(sink, syncer.Options{}, state).SyncBeans(ctx, all); err != nil {
+		t.Fatal(err)
+	}
+	taskID := *state.GetTaskID("bean")
+	entries := mock.TimeEntries(taskID)
+	if len(entries) != 2 || entries[0].Duration != (90*time.Minute).Milliseconds() || !strings.HasPrefix(entries[0].Description, "Investigated [bean worklog ") {
+		t.Fatalf("time entries = %+v", entries)
+	}
+
+	// Only the new entry is added when the worklog grows
+	later := now.Add(time.Minute)
+	all[0].Body += "- 2026-03-03 2h Fixed\n"
+	all[0].UpdatedAt = &later
+	results, err := syncer.New(sink, syncer.Options{}, state).SyncBeans(ctx, all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Action != "updated" {
+		t.Errorf("second sync: %s (%v)", results[0].Action, results[0].Error)
+	}
+	if entries = mock.TimeEntries(taskID); len(entries) != 3 || !strings.HasPrefix(entries[2].Description, "Fixed ") {
+		t.Errorf("time entries after second sync = %+v", entries)
+	}
+}
+
+func TestStatusChangeCommentAgainstMockServer(t *testing.T) {
+	mock := clickuptest.Start()
+	defer mock.Close()
+
+	sink := NewSink(NewClient("token", WithBaseURL(mock.URL)), &config.ClickUpConfig{CommentOnStatusChange: true}, clickuptest.ListID)
+	state := newMemorySyncProvider()
+	ctx := context.Background()
+
+	now := time.Now()
+	all := []beans.Bean{{ID: "bean", Title: "Bean", Status: "todo", UpdatedAt: &now}}
+	if _, err := syncer.New(sink, syncer.Options{}, state).SyncBeans(ctx, all); err != nil {
+		t.Fatal(err)
+	}
+	taskID := *state.GetTaskID("bean")
+
+	// A retitle leaves the status, so nothing is posted
+	later := now.Add(time.Minute)
+	all[0].Title, all[0].UpdatedAt = "Renamed", &later
+	if _, err := syncer.New(sink, syncer.Options{}, state).SyncBeans(ctx, all); err != nil {
+		t.Fatal(err)
+	}
+	if task, _ := mock.Task(taskID); len(task.Comments) != 0 {
+		t.Fatalf("comments after retitle = %q", task.Comments)
+	}
+
+	latest := later.Add(time.Minute)
+	all[0].Status, all[0].UpdatedAt = "in-progress", &latest
+	if _, err := syncer.New(sink, syncer.Options{}, state).SyncBeans(ctx, all); err != nil {
+		t.Fatal(err)
+	}
+	task, _ := mock.Task(taskID)
+	want := `beanup: moved from "to do" to "in progress" to match bean bean (in-progress)`
+	if len(task.Comments) != 1 || task.Comments[0] != want {
+		t.Errorf("comments = %q, want [%q]", task.Comments, want)
+	}
+
+	// Status-only syncs comment too
+	done := latest.Add(time.Minute)
+	all[0].Status, all[0].UpdatedAt = "completed", &done
+	if _, err := syncer.New(sink, syncer.Options{StatusOnly: true}, state).SyncBeans(ctx, all); err != nil {
+		t.Fatal(err)
+	}
+	task, _ = mock.Task(taskID)
+	want = `beanup: moved from "in progress" to "complete" to match bean bean (completed)`
+	if len(task.Comments) != 2 || task.Comments[1] != want {
+		t.Errorf("comments after status-only sync = %q, want %q last", task.Comments, want)
+	}
+}
+
+func TestDescriptionOverflowAgainstMockServer(t *testing.T) {
+	mock := clickuptest.Start()
+	defer mock.Close()
+
+	cfg := &config.ClickUpConfig{DescriptionLimit: 200, SourceFooter: true}
+	sink := NewSink(NewClient("token", WithBaseURL(mock.URL)), cfg, clickuptest.ListID)
+	commit := "abc"
+	sink.SetSourceURL(func(b *beans.Bean) string { return "https://github.com/o/r/blob/" + commit + "/" + b.Path })
+	state := newMemorySyncProvider()
+	ctx := context.Background()
+
+	now := time.Now()
+	tail := strings.Repeat("b", 300)
+	all := []beans.Bean{{ID: "bean", Path: "bean.md", Title: "Bean", Status: "todo", Body: "a\n" + tail, UpdatedAt: &now}}
+	sync := func() {
+		t.Helper()
+		later := now.Add(time.Minute)
+		now, all[0].UpdatedAt = later, &later
+		if _, err := syncer.New(sink, syncer.Options{}, state).SyncBeans(ctx, all); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sync()
+	taskID := *state.GetTaskID("bean")
+	task, _ := mock.Task(taskID)
+	posted := len(task.Comments)
+	if posted == 0 {
+		t.Fatal("no overflow comments posted")
+	}
+
+	// Rewriting the start of the description leaves the same overflow
+	all[0].Body = "A\n" + tail
+	sync()
+	if task, _ = mock.Task(taskID); len(task.Comments) != posted {
+		t.Errorf("%d comments after an unchanged overflow, want %d", len(task.Comments), posted)
+	}
+
+	// A new overflow is posted
+	all[0].Body = "A\n" + strings.Repeat("c", 300)
+	sync()
+	if task, _ = mock.Task(taskID); len(task.Comments) <= posted {
+		t.Errorf("%d comments after a new overflow, want more than %d", len(task.Comments), posted)
+	}
+
+	// Attachments are only uploaded again when the body changed
+	cfg.DescriptionOverflow = OverflowAttachment
+	sync()
+	commit = "def"
+	sync()
+	if task, _ = mock.Task(taskID); len(task.Attachments) != 1 {
+		t.Errorf("attachments after a footer change = %q, want one", task.Attachments)
+	}
+	all[0].Body = "B\n" + tail
+	sync()
+	if task, _ = mock.Task(taskID); len(task.Attachments) != 2 {
+		t.Errorf("attachments after a body change = %q, want two", task.Attachments)
+	}
+}
+
+func TestSprintListsAgainstMockServer(t *testing.T) {
+	mock := clickuptest.Start()
+	defer mock.Close()
+	mock.AddList("sprint1", "Sprint 1")
+	mock.AddList("sprint2", "Sprint 2")
+
+	cfg := &config.ClickUpConfig{SprintLists: map[string]string{"sprint-2026-07": "sprint1", "2026-08": "sprint2"}}
+	sink := NewSink(NewClient("token", WithBaseURL(mock.URL)), cfg, clickuptest.ListID)
+	state := newMemorySyncProvider()
+	ctx := context.Background()
+
+	now := time.Now()
+	all := []beans.Bean{{ID: "bean", Title: "Bean", Status: "todo", Tags: []string{"backend", "sprint-2026-07"}, UpdatedAt: &now}}
+	if _, err := syncer.New(sink, syncer.Options{}, state).SyncBeans(ctx, all); err != nil {
+		t.Fatal(err)
+	}
+	taskID := *state.GetTaskID("bean")
+	if task, _ := mock.Task(taskID); task.ListID != clickuptest.ListID || !slices.Equal(task.OtherLists, []string{"sprint1"}) {
+		t.Fatalf("after create: home %s, other lists %v", task.ListID, task.OtherLists)
+	}
+
+	// The sprint extension wins over tags, and the task leaves its old sprint
+	later := now.Add(time.Minute)
+	all[0].Extensions = map[string]map[string]any{beans.PluginClickUp: {beans.ExtKeySprint: "2026-08"}}
+	all[0].UpdatedAt = &later
+	results, err := syncer.New(sink, syncer.Options{}, state).SyncBeans(ctx, all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Action != "updated" {
+		t.Errorf("second sync: %s (%v)", results[0].Action, results[0].Error)
+	}
+	if task, _ := mock.Task(taskID); !slices.Equal(task.OtherLists, []string{"sprint2"}) {
+		t.Errorf("after update: other lists %v", task.OtherLists)
+	}
+}
+
+func TestTaskTemplatesAgainstMockServer(t *testing.T) {
+	mock := clickuptest.Start()
+	defer mock.Close()
+	mock.AddTemplate("t-default", clickuptest.Task{Description: "- [ ] Write tests", Tags: []string{"templated"}})
+	mock.AddTemplate("t-bug", clickuptest.Task{Description: "- [ ] Reproduce"})
+
+	cfg := &config.ClickUpConfig{TaskTemplateID: "t-default", TaskTemplates: map[string]string{"bug": "t-bug"}, ProtectedTags: []string{"templated"}}
+	sink := NewSink(NewClient("token", WithBaseURL(mock.URL)), cfg, clickuptest.ListID)
+	state := newMemorySyncProvider()
+
+	now := time.Now()
+	all := []beans.Bean{
+		{ID: "feature", Title: "Feature", Status: "in-progress", Type: "feature", UpdatedAt: &now},
+		{ID: "bug", Title: "Bug", Status: "todo", Type: "bug", Body: "Crashes on start.", UpdatedAt: &now},
+		{ID: "sub", Title: "Sub", Status: "todo", Type: "task", Parent: "feature", UpdatedAt: &now},
+	}
+	results, err := syncer.New(sink, syncer.Options{}, state).SyncBeans(context.Background(), all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.Action != "created" {
+			t.Errorf("%s: %s (%v)", r.BeanID, r.Action, r.Error)
+		}
+	}
+
+	// The template's description stays unless the bean has a body
+	feature, _ := mock.Task(*state.GetTaskID("feature"))
+	if feature.Name != "Feature" || feature.Status != "in progress" || feature.Description != "- [ ] Write tests" || !slices.Contains(feature.Tags, "templated") {
+		t.Errorf("feature task = %+v", feature)
+	}
+	if bug, _ := mock.Task(*state.GetTaskID("bug")); bug.Description != "Crashes on start." || len(bug.Tags) != 0 {
+		t.Errorf("bug task = %+v", bug)
+	}
+	// Subtasks can't come from a template
+	if sub, _ := mock.Task(*state.GetTaskID("sub")); sub.Parent != feature.ID || sub.Description != "" {
+		t.Errorf("subtask = %+v", sub)
+	}
+}
+
+func TestRemoteDriftAgainstMockServer(t *testing.T) {
+	mock := clickuptest.Start()
+	defer mock.Close()
+
+	sink := NewSink(NewClient("token", WithBaseURL(mock.URL)), &config.ClickUpConfig{}, clickuptest.ListID)
+	state := newMemorySyncProvider()
+	ctx := context.Background()
+
+	now := time.Now()
+	all := []beans.Bean{
+		{ID: "a", Title: "A", Status: "todo", Tags: []string{"backend"}, Blocking: []string{"b"}, UpdatedAt: &now},
+		{ID: "b", Title: "B", Status: "todo", UpdatedAt: &now},
+	}
+	if _, err := syncer.New(sink, syncer.Options{}, state).SyncBeans(ctx, all); err != nil {
+		t.Fatal(err)
+	}
+	results, err := syncer.New(sink, syncer.Options{}, state).SyncBeans(ctx, all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Action != "skipped" || results[1].Action != "skipped" {
+		t.Fatalf("resync of unchanged beans: %s, %s", results[0].Action, results[1].Action)
+	}
+
+	// A tag added in ClickUp is noticed by its fingerprint even when
+	// date_updated doesn't pass synced_at, and sync removes it
+	taskA := *state.GetTaskID("a")
+	mock.UpdateTask(taskA, func(task *clickuptest.Task) { task.Tags = append(task.Tags, "urgent") })
+	state.SetSyncedAt("a", time.Now().Add(time.Minute))
+	state.SetSyncedAt("b", time.Now().Add(time.Minute))
+	if results, err = syncer.New(sink, syncer.Options{}, state).SyncBeans(ctx, all); err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Action != "updated" || results[1].Action != "skipped" {
+		t.Errorf("after tag edit: %s, %s", results[0].Action, results[1].Action)
+	}
+	if task, _ := mock.Task(taskA); slices.Contains(task.Tags, "urgent") {
+		t.Errorf("hand-added tag kept: %v", task.Tags)
+	}
+}
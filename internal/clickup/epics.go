@@ -0,0 +1,175 @@
+package clickup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+// Values for epics_as.
+const (
+	EpicsAsList   = "list"
+	EpicsAsFolder = "folder"
+)
+
+// listRefPrefix marks a sync state task ID that is really the list an
+// epic was materialized as, e.g. "list:901234".
+const listRefPrefix = "list:"
+
+// parseListRef returns the list ID of an epic's list ref.
+func parseListRef(taskID string) (string, bool) {
+	return strings.CutPrefix(taskID, listRefPrefix)
+}
+
+// IsListRef reports whether a linked task ID is really the list an epic
+// was materialized as.
+func IsListRef(taskID string) bool {
+	_, ok := parseListRef(taskID)
+	return ok
+}
+
+// isEpicList reports whether the bean is materialized as a list.
+func (s *Sink) isEpicList(b *beans.Bean) bool {
+	return s.config.EpicsAs != "" && b.Type == beans.TypeEpic
+}
+
+// epicListContent is the list description for an epic: its body, then the
+// metadata a task would have carried in its fields.
+func epicListContent(b *beans.Bean) string {
+	meta := []string{"Bean: " + b.ID, "Status: " + b.Status}
+	if b.Priority != "" {
+		meta = append(meta, "Priority: "+b.Priority)
+	}
+	if b.Due != nil {
+		meta = append(meta, "Due: "+*b.Due)
+	}
+	if len(b.Tags) > 0 {
+		meta = append(meta, "Tags: "+strings.Join(b.Tags, ", "))
+	}
+	footer := strings.Join(meta, " · ")
+	if b.Body == "" {
+		return footer
+	}
+	return b.Body + "\n\n---\n" + footer
+}
+
+// listRef converts an epic's list to a sink-neutral reference.
+func (s *Sink) listRef(list *ListDetails) *syncer.TaskRef {
+	ref := &syncer.TaskRef{ID: listRefPrefix + list.ID, Remote: list}
+	if s.config.TeamID != "" {
+		ref.URL = fmt.Sprintf("https://app.clickup.com/%s/v/li/%s", s.config.TeamID, list.ID)
+	}
+	return ref
+}
+
+// createEpicList creates the list for an epic: next to the configured list
+// (in its folder, if it has one), or in a new folder named after the epic.
+func (s *Sink) createEpicList(ctx context.Context, b *beans.Bean) (*syncer.TaskRef, error) {
+	home, err := s.client.GetList(ctx, s.listID)
+	if err != nil {
+		return nil, err
+	}
+	name := s.TaskName(b)
+	folderID := home.FolderID
+	if s.config.EpicsAs == EpicsAsFolder {
+		folder, err := s.client.CreateFolder(ctx, home.SpaceID, name)
+		if err != nil {
+			return nil, err
+		}
+		folderID = folder.ID
+	}
+
+	content := epicListContent(b)
+	list, err := s.client.CreateList(ctx, home.SpaceID, folderID, &ListRequest{Name: name, Content: &content})
+	if err != nil {
+		return nil, err
+	}
+	return s.listRef(list), nil
+}
+
+// getEpicList fetches an epic's list.
+func (s *Sink) getEpicList(ctx context.Context, listID string) (*syncer.TaskRef, error) {
+	list, err := s.client.GetListDetails(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	return s.listRef(list), nil
+}
+
+// updateEpicList renames an epic's list, and its folder with epics_as
+// folder, and rewrites its description when they differ from the bean.
+func (s *Sink) updateEpicList(ctx context.Context, current *syncer.TaskRef, b *beans.Bean) (*syncer.TaskRef, []string, error) {
+	listID, _ := parseListRef(current.ID)
+	list, ok := current.Remote.(*ListDetails)
+	if !ok {
+		var err error
+		if list, err = s.client.GetListDetails(ctx, listID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	name, content := s.TaskName(b), epicListContent(b)
+	var fields []string
+	if s.config.EpicsAs == EpicsAsFolder && !list.Folder.Hidden && list.Folder.ID != "" && list.Folder.Name != name {
+		if err := s.client.RenameFolder(ctx, list.Folder.ID, name); err != nil {
+			return nil, nil, err
+		}
+		fields = append(fields, "folder")
+	}
+	if list.Name == name && list.Content == content {
+		return current, fields, nil
+	}
+	updated, err := s.client.UpdateList(ctx, listID, &ListRequest{Name: name, Content: &content})
+	if err != nil {
+		return nil, nil, err
+	}
+	if list.Name != name {
+		fields = append(fields, "name")
+	}
+	if list.Content != content {
+		fields = append(fields, "description")
+	}
+	return s.listRef(updated), fields, nil
+}
+
+// taskList returns the list to create a task in under parentTaskID: the
+// epic's list for an epic, or the parent task's own list, since ClickUp
+// keeps subtasks in their parent's list. parentTaskID is cleared for epics.
+func (s *Sink) taskList(ctx context.Context, parentTaskID *string) (string, error) {
+	if *parentTaskID == "" || s.config.EpicsAs == "" {
+		return s.listID, nil
+	}
+	if listID, ok := parseListRef(*parentTaskID); ok {
+		*parentTaskID = ""
+		return listID, nil
+	}
+
+	s.mu.Lock()
+	listID, ok := s.taskLists[*parentTaskID]
+	s.mu.Unlock()
+	if ok {
+		return listID, nil
+	}
+	parent, err := s.client.GetTask(ctx, *parentTaskID)
+	if err != nil {
+		return "", fmt.Errorf("getting parent task: %w", err)
+	}
+	s.rememberList(parent.ID, parent.List.ID)
+	return parent.List.ID, nil
+}
+
+// rememberList records the list a task is in, for creating its subtasks.
+func (s *Sink) rememberList(taskID, listID string) {
+	if s.config.EpicsAs == "" || listID == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.taskLists == nil {
+		s.taskLists = make(map[string]string)
+	}
+	s.taskLists[taskID] = listID
+}
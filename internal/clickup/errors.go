@@ -0,0 +1,88 @@
+package clickup
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+// Error kinds returned by the client. Match them with errors.Is; use
+// errors.As with *APIError to get the HTTP status and ClickUp ECODE.
+var (
+	// ErrTaskNotFound means the requested task does not exist or was deleted.
+	// It is syncer.ErrTaskNotFound, so the Syncer recreates deleted tasks.
+	ErrTaskNotFound = syncer.ErrTaskNotFound
+	// ErrRateLimited means ClickUp rejected the request for exceeding its rate limit.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrUnauthorized means the API token is missing, invalid, or lacks access.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrValidation means ClickUp rejected the request payload.
+	ErrValidation = errors.New("validation failed")
+	// ErrTagExists means a space tag couldn't be created because the space
+	// already has one by that name.
+	ErrTagExists = errors.New("tag already exists")
+)
+
+// ClickUp error codes with a known meaning.
+const (
+	ecodeTaskNotFound = "ITEM_013"
+	ecodeRateLimited  = "APP_002"
+)
+
+// APIError is a non-retryable error response from the ClickUp API.
+type APIError struct {
+	StatusCode int
+	// Code is ClickUp's ECODE (e.g. "ITEM_013"); empty if the body wasn't a ClickUp error.
+	Code    string
+	Message string
+
+	clickUpBody bool // the body was a ClickUp error, not e.g. a proxy's page
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("API error: %s (code: %s)", e.Message, e.Code)
+}
+
+// Is reports whether the error belongs to one of the client's error kinds.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrTaskNotFound:
+		// Other 404s, e.g. from a proxy or a wrong CLICKUP_API_URL, must not
+		// pass for a deleted task, which the Syncer unlinks
+		return e.Code == ecodeTaskNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests || e.Code == ecodeRateLimited
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || strings.HasPrefix(e.Code, "OAUTH_")
+	case ErrValidation:
+		return (e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity) &&
+			!strings.HasPrefix(e.Code, "OAUTH_")
+	case ErrTagExists:
+		return e.StatusCode >= 400 && e.StatusCode < 500 &&
+			strings.Contains(strings.ToLower(e.Message), "already exists")
+	}
+	return false
+}
+
+// taskNotFound marks err from fetching one task as ErrTaskNotFound when
+// ClickUp's own error body says the task wasn't found but carries no
+// ITEM_013, as older responses do.
+func taskNotFound(err error) error {
+	apiErr, ok := errors.AsType[*APIError](err)
+	if ok && apiErr.clickUpBody && apiErr.StatusCode == http.StatusNotFound && apiErr.Code != ecodeTaskNotFound &&
+		strings.Contains(strings.ToLower(apiErr.Message), "task not found") {
+		return fmt.Errorf("%w: %w", ErrTaskNotFound, err)
+	}
+	return err
+}
+
+// Is reports whether target is ErrRateLimited.
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
@@ -0,0 +1,54 @@
+package clickup
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/toba/bean-me-up/internal/config"
+)
+
+// beanField describes a custom field beanup can fill in for each task.
+type beanField struct {
+	Name  string
+	Type  string
+	Types []string // existing field types that can be reused
+	set   func(m *config.CustomFieldsMap, id string)
+}
+
+// beanFields are the custom fields mapped by custom_fields in the config.
+var beanFields = []beanField{
+	{Name: "Bean ID", Type: "short_text", Types: []string{"short_text", "text"}, set: func(m *config.CustomFieldsMap, id string) { m.BeanID = id }},
+	{Name: "Bean Created", Type: "date", Types: []string{"date"}, set: func(m *config.CustomFieldsMap, id string) { m.CreatedAt = id }},
+	{Name: "Bean Updated", Type: "date", Types: []string{"date"}, set: func(m *config.CustomFieldsMap, id string) { m.UpdatedAt = id }},
+}
+
+// EnsureBeanFields finds or creates the Bean ID, Bean Created, and Bean
+// Updated custom fields on a list and returns their IDs as a custom_fields
+// mapping, along with the names of the fields it created. Existing fields
+// are matched by name (ignoring case) and type.
+func (c *Client) EnsureBeanFields(ctx context.Context, listID string) (*config.CustomFieldsMap, []string, error) {
+	existing, err := c.GetAccessibleCustomFields(ctx, listID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mapping := &config.CustomFieldsMap{}
+	var created []string
+	for _, bf := range beanFields {
+		i := slices.IndexFunc(existing, func(f FieldInfo) bool {
+			return strings.EqualFold(f.Name, bf.Name) && slices.Contains(bf.Types, f.Type)
+		})
+		if i >= 0 {
+			bf.set(mapping, existing[i].ID)
+			continue
+		}
+		field, err := c.CreateCustomField(ctx, listID, &CreateFieldRequest{Name: bf.Name, Type: bf.Type})
+		if err != nil {
+			return nil, created, err
+		}
+		bf.set(mapping, field.ID)
+		created = append(created, bf.Name)
+	}
+	return mapping, created, nil
+}
@@ -0,0 +1,54 @@
+package clickup
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEnsureBeanFields(t *testing.T) {
+	var created []CreateFieldRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/list/list1/field" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == "POST" {
+			var req CreateFieldRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			created = append(created, req)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"field": FieldInfo{ID: "new-" + req.Type, Name: req.Name, Type: req.Type},
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(fieldsResponse{Fields: []FieldInfo{
+			{ID: "existing-id", Name: "bean id", Type: "short_text"},
+			// Same name but the wrong type is not reused
+			{ID: "wrong-type", Name: "Bean Created", Type: "text"},
+		}})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token:      "test",
+		httpClient: &http.Client{Transport: &redirectTransport{target: server.URL}},
+		baseURL:    DefaultBaseURL,
+	}
+
+	mapping, names, err := client.EnsureBeanFields(context.Background(), "list1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mapping.BeanID != "existing-id" || mapping.CreatedAt != "new-date" || mapping.UpdatedAt != "new-date" {
+		t.Errorf("mapping = %+v", mapping)
+	}
+	if len(created) != 2 || created[0].Name != "Bean Created" || created[1].Name != "Bean Updated" {
+		t.Errorf("created requests = %+v", created)
+	}
+	if len(names) != 2 {
+		t.Errorf("created names = %v", names)
+	}
+}
@@ -0,0 +1,148 @@
+package clickup
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Custom field types with values beanup checks before sending, since
+// ClickUp rejects malformed ones with little explanation.
+const (
+	fieldTypeEmail    = "email"
+	fieldTypePhone    = "phone"
+	fieldTypeLocation = "location"
+	fieldTypeCurrency = "currency"
+)
+
+// phoneRe matches an international phone number once spacing and
+// punctuation are removed: a + and country code, then up to 15 digits.
+var phoneRe = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// emailValue checks that v is a bare email address.
+func emailValue(def FieldInfo, v any) (string, error) {
+	s := strings.TrimSpace(fmt.Sprint(v))
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s || addr.Name != "" {
+		return "", fmt.Errorf("%s: %q is not an email address", def.Name, s)
+	}
+	return s, nil
+}
+
+// phoneValue normalizes v to the +<country code><number> form ClickUp
+// takes, dropping spaces, dashes, dots, and parentheses.
+func phoneValue(def FieldInfo, v any) (string, error) {
+	s := strings.TrimSpace(fmt.Sprint(v))
+	phone := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(" -.()", r) {
+			return -1
+		}
+		return r
+	}, s)
+	if !phoneRe.MatchString(phone) {
+		return "", fmt.Errorf("%s: %q is not a phone number with a +country code, e.g. +1 555 010 0199", def.Name, s)
+	}
+	return phone, nil
+}
+
+// location is a location field's value in frontmatter form.
+type location struct {
+	Lat, Lng float64
+	Address  string
+}
+
+// canon is the location's canonical form, its coordinates; the address
+// only describes them.
+func (l location) canon() string {
+	return formatNumber(l.Lat) + "," + formatNumber(l.Lng)
+}
+
+// frontmatter returns the location as the bean's value.
+func (l location) frontmatter() map[string]any {
+	m := map[string]any{"lat": l.Lat, "lng": l.Lng}
+	if l.Address != "" {
+		m["address"] = l.Address
+	}
+	return m
+}
+
+// beanLocation reads a bean's location value: a map with lat, lng, and an
+// optional address, or "lat, lng".
+func beanLocation(def FieldInfo, v any) (location, error) {
+	var l location
+	var latOK, lngOK bool
+	switch v := v.(type) {
+	case map[string]any:
+		l.Lat, latOK = toNumber(v["lat"])
+		l.Lng, lngOK = toNumber(v["lng"])
+		l.Address, _ = v["address"].(string)
+	case string:
+		if lat, lng, found := strings.Cut(v, ","); found {
+			l.Lat, latOK = toNumber(lat)
+			l.Lng, lngOK = toNumber(lng)
+		}
+	}
+	if !latOK || !lngOK {
+		return location{}, fmt.Errorf("%s: %v is not a location (use lat and lng, or \"lat, lng\")", def.Name, v)
+	}
+	if math.Abs(l.Lat) > 90 || math.Abs(l.Lng) > 180 {
+		return location{}, fmt.Errorf("%s: %s is out of range (latitude ±90, longitude ±180)", def.Name, l.canon())
+	}
+	return l, nil
+}
+
+// locationValue converts a location to the value ClickUp sets.
+func locationValue(l location) map[string]any {
+	value := map[string]any{"location": map[string]any{"lat": l.Lat, "lng": l.Lng}}
+	if l.Address != "" {
+		value["formatted_address"] = l.Address
+	}
+	return value
+}
+
+// taskLocation reads a location field's task value.
+func taskLocation(v any) (location, bool) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return location{}, false
+	}
+	coords, _ := m["location"].(map[string]any)
+	lat, latOK := toNumber(coords["lat"])
+	lng, lngOK := toNumber(coords["lng"])
+	address, _ := m["formatted_address"].(string)
+	return location{Lat: lat, Lng: lng, Address: address}, latOK && lngOK
+}
+
+// currencyValue reads an amount for a currency field: a number, or a
+// number with the field's currency code before or after it, and no more
+// decimal places than the field shows.
+func currencyValue(def FieldInfo, v any) (float64, error) {
+	tc, _ := def.TypeConfig.(map[string]any)
+	code, _ := tc["currency_type"].(string)
+	s := strings.TrimSpace(fmt.Sprint(v))
+	amount := s
+	if fields := strings.Fields(s); len(fields) == 2 {
+		i := 0 // index of the code
+		if _, err := strconv.ParseFloat(fields[0], 64); err == nil {
+			i = 1
+		}
+		if code == "" || !strings.EqualFold(fields[i], code) {
+			return 0, fmt.Errorf("%s: %q is not in %s", def.Name, s, cmp.Or(code, "the field's currency"))
+		}
+		amount = fields[1-i]
+	}
+	n, ok := toNumber(amount)
+	if !ok {
+		return 0, fmt.Errorf("%s: %q is not an amount", def.Name, s)
+	}
+	if precision, ok := toNumber(tc["precision"]); ok {
+		if _, frac, _ := strings.Cut(formatNumber(n), "."); len(frac) > int(precision) {
+			return 0, fmt.Errorf("%s: %s has more than %d decimal places", def.Name, formatNumber(n), int(precision))
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,87 @@
+package clickup
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// HistoryItem is one change in a task's activity history, as returned by
+// the history endpoint and sent in webhook payloads.
+type HistoryItem struct {
+	ID    string   `json:"id"`
+	Field string   `json:"field"`
+	Date  string   `json:"date"` // Unix ms
+	User  TaskUser `json:"user"`
+}
+
+// Time returns when the change happened, or the zero time if Date isn't
+// a timestamp.
+func (h HistoryItem) Time() time.Time {
+	ms, err := strconv.ParseInt(h.Date, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// historyResponse is the API response for getting a task's history.
+type historyResponse struct {
+	HistoryItems []HistoryItem `json:"history_items"`
+}
+
+// historyFields names the bean field each pulled task field is recorded
+// under in task history.
+var historyFields = map[string]string{
+	"status":      "status",
+	"priority":    "priority",
+	"custom_type": "type",
+	"due_date":    "due",
+}
+
+// HistoryTracks reports whether task history records the changes to the
+// pulled bean field, so RemoteChanges can tell whether it was edited.
+func HistoryTracks(field string) bool {
+	for _, f := range historyFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTaskHistory fetches the changes made to a task after since, oldest
+// first.
+func (c *Client) GetTaskHistory(ctx context.Context, taskID string, since time.Time) ([]HistoryItem, error) {
+	var resp historyResponse
+	path := fmt.Sprintf("/task/%s/history?date_gt=%d", taskID, since.UnixMilli())
+	if err := c.get(ctx, path, &resp); err != nil {
+		return nil, fmt.Errorf("getting task history: %w", err)
+	}
+	var items []HistoryItem
+	for _, h := range resp.HistoryItems {
+		// Filtered here as well, so a change made exactly at since is left out
+		if h.Time().After(since) {
+			items = append(items, h)
+		}
+	}
+	return items, nil
+}
+
+// RemoteChanges returns the latest change to each pulled bean field among
+// items, keyed by bean field. Changes to fields pull doesn't write are left
+// out.
+func RemoteChanges(items []HistoryItem) map[string]HistoryItem {
+	changes := make(map[string]HistoryItem)
+	for _, h := range items {
+		field, ok := historyFields[h.Field]
+		if !ok {
+			continue
+		}
+		if prev, ok := changes[field]; !ok || !h.Time().Before(prev.Time()) {
+			changes[field] = h
+		}
+	}
+	return changes
+}
@@ -0,0 +1,291 @@
+package clickup
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// markdownFixes rewrite markdown constructs ClickUp renders badly. They
+// run in order on the lines of a bean body; all but fixFences leave fenced
+// code untouched.
+var markdownFixes = []func([]string) []string{
+	fixFences,
+	fixNestedLists,
+	fixTables,
+	fixFootnotes,
+}
+
+// FormatMarkdown adapts a bean body for a ClickUp task description:
+//   - code fences use backticks and a plain, known language hint
+//   - nested list items are indented four spaces per level
+//   - tables are set off by blank lines so they aren't read as paragraphs
+//   - footnotes become numbered references with a Notes list at the end
+func FormatMarkdown(body string) string {
+	if body == "" {
+		return body
+	}
+	lines := strings.Split(body, "\n")
+	for _, fix := range markdownFixes {
+		lines = fix(lines)
+	}
+	return strings.Join(lines, "\n")
+}
+
+var fenceRe = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})(.*)$")
+
+// closesFence reports whether line closes a block opened with fence.
+func closesFence(line, fence string) bool {
+	m := fenceRe.FindStringSubmatch(line)
+	return m != nil && m[2][0] == fence[0] && len(m[2]) >= len(fence) && strings.TrimSpace(m[3]) == ""
+}
+
+// fenceMask reports, for each line, whether it is part of a fenced code
+// block, fences included.
+func fenceMask(lines []string) []bool {
+	mask := make([]bool, len(lines))
+	var open string
+	for i, line := range lines {
+		switch {
+		case open != "":
+			mask[i] = true
+			if closesFence(line, open) {
+				open = ""
+			}
+		default:
+			if m := fenceRe.FindStringSubmatch(line); m != nil {
+				open = m[2]
+				mask[i] = true
+			}
+		}
+	}
+	return mask
+}
+
+// languageAliases maps language hints to the names ClickUp highlights.
+var languageAliases = map[string]string{
+	"golang":     "go",
+	"js":         "javascript",
+	"jsx":        "javascript",
+	"ts":         "typescript",
+	"tsx":        "typescript",
+	"py":         "python",
+	"rb":         "ruby",
+	"sh":         "bash",
+	"shell":      "bash",
+	"zsh":        "bash",
+	"console":    "bash",
+	"yml":        "yaml",
+	"md":         "markdown",
+	"rs":         "rust",
+	"kt":         "kotlin",
+	"cs":         "csharp",
+	"c#":         "csharp",
+	"c++":        "cpp",
+	"dockerfile": "docker",
+}
+
+// fixFences rewrites tilde fences as backtick fences and reduces the info
+// string to a single lower-case language name, dropping attributes such as
+// {.python} or title="main.go" that ClickUp shows as code.
+func fixFences(lines []string) []string {
+	out := slices.Clone(lines)
+	for i := 0; i < len(lines); i++ {
+		m := fenceRe.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+		indent, open := m[1], m[2]
+
+		// An unclosed block runs to the end
+		end := i + 1
+		for end < len(lines) && !closesFence(lines[end], open) {
+			end++
+		}
+
+		// Backticks must outnumber any backtick fence inside the block
+		ticks := 3
+		if open[0] == '`' {
+			ticks = len(open)
+		}
+		for _, l := range lines[i+1 : end] {
+			if inner := fenceRe.FindStringSubmatch(l); inner != nil && inner[2][0] == '`' {
+				ticks = max(ticks, len(inner[2])+1)
+			}
+		}
+		fence := strings.Repeat("`", ticks)
+
+		out[i] = indent + fence + fenceLanguage(m[3])
+		if end < len(lines) {
+			out[end] = indent + fence
+		}
+		i = end
+	}
+	return out
+}
+
+// fenceLanguage returns the normalized language from a fence info string.
+func fenceLanguage(info string) string {
+	fields := strings.Fields(strings.NewReplacer("{", " ", "}", " ").Replace(info))
+	if len(fields) == 0 {
+		return ""
+	}
+	lang := strings.ToLower(strings.TrimPrefix(fields[0], "."))
+	if strings.Contains(lang, "=") {
+		return ""
+	}
+	if alias, ok := languageAliases[lang]; ok {
+		return alias
+	}
+	return lang
+}
+
+var listItemRe = regexp.MustCompile(`^([-*+]|\d+[.)])\s+`)
+
+// fixNestedLists re-indents nested list items (and their continuation
+// lines) to four spaces per level; ClickUp flattens items indented less.
+func fixNestedLists(lines []string) []string {
+	out := slices.Clone(lines)
+	mask := fenceMask(lines)
+
+	var stack []int // original indents of the open list levels
+	for i, line := range lines {
+		if mask[i] || strings.TrimSpace(line) == "" {
+			continue
+		}
+		content := strings.TrimLeft(line, " \t")
+		indent := len(strings.ReplaceAll(line[:len(line)-len(content)], "\t", "    "))
+
+		if listItemRe.MatchString(content) {
+			for len(stack) > 0 && stack[len(stack)-1] > indent {
+				stack = stack[:len(stack)-1]
+			}
+			if len(stack) == 0 || stack[len(stack)-1] < indent {
+				stack = append(stack, indent)
+			}
+			out[i] = strings.Repeat(" ", 4*(len(stack)-1)) + content
+			continue
+		}
+
+		if indent == 0 {
+			// Back to a paragraph: the list is over
+			stack = nil
+			continue
+		}
+		// A continuation line keeps its offset from the item it belongs to
+		for level := len(stack) - 1; level >= 0; level-- {
+			if stack[level] < indent {
+				out[i] = strings.Repeat(" ", 4*level+indent-stack[level]) + content
+				break
+			}
+		}
+	}
+	return out
+}
+
+var tableDelimiterRe = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+
+// fixTables puts a blank line before and after each table; a table that
+// directly follows a paragraph is otherwise rendered as text.
+func fixTables(lines []string) []string {
+	mask := fenceMask(lines)
+	out := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		isStart := !mask[i] && i+1 < len(lines) && strings.Contains(lines[i], "|") &&
+			strings.Contains(lines[i+1], "-") && tableDelimiterRe.MatchString(lines[i+1])
+		if !isStart {
+			out = append(out, lines[i])
+			continue
+		}
+		if len(out) > 0 && strings.TrimSpace(out[len(out)-1]) != "" {
+			out = append(out, "")
+		}
+		end := i + 2
+		for end < len(lines) && strings.Contains(lines[end], "|") && strings.TrimSpace(lines[end]) != "" {
+			end++
+		}
+		out = append(out, lines[i:end]...)
+		if end < len(lines) && strings.TrimSpace(lines[end]) != "" {
+			out = append(out, "")
+		}
+		i = end - 1
+	}
+	return out
+}
+
+var (
+	footnoteDefRe = regexp.MustCompile(`^\[\^([^\]\s]+)\]:\s?(.*)$`)
+	footnoteRefRe = regexp.MustCompile(`\[\^([^\]\s]+)\]`)
+)
+
+// fixFootnotes replaces footnote references with [n], numbered in order of
+// first use, and moves the definitions to a numbered Notes list at the end.
+// ClickUp has no footnote syntax and would show the raw markers.
+func fixFootnotes(lines []string) []string {
+	mask := fenceMask(lines)
+
+	// Collect definitions, including indented continuation lines
+	defs := make(map[string]string)
+	var defOrder []string
+	var body []string
+	var bodyMask []bool
+	for i := 0; i < len(lines); i++ {
+		m := footnoteDefRe.FindStringSubmatch(lines[i])
+		if mask[i] || m == nil {
+			body = append(body, lines[i])
+			bodyMask = append(bodyMask, mask[i])
+			continue
+		}
+		text := m[2]
+		for i+1 < len(lines) && !mask[i+1] && strings.HasPrefix(lines[i+1], "    ") {
+			i++
+			text += " " + strings.TrimSpace(lines[i])
+		}
+		if _, dup := defs[m[1]]; !dup {
+			defOrder = append(defOrder, m[1])
+		}
+		defs[m[1]] = text
+	}
+	if len(defs) == 0 {
+		return lines
+	}
+
+	numbers := make(map[string]int)
+	for i, line := range body {
+		if bodyMask[i] {
+			continue
+		}
+		body[i] = footnoteRefRe.ReplaceAllStringFunc(line, func(ref string) string {
+			id := footnoteRefRe.FindStringSubmatch(ref)[1]
+			if _, ok := defs[id]; !ok {
+				return ref
+			}
+			if numbers[id] == 0 {
+				numbers[id] = len(numbers) + 1
+			}
+			return fmt.Sprintf("[%d]", numbers[id])
+		})
+	}
+	// Definitions never referenced still get listed, after the rest
+	for _, id := range defOrder {
+		if numbers[id] == 0 {
+			numbers[id] = len(numbers) + 1
+		}
+	}
+	notes := make([]string, len(numbers))
+	for id, n := range numbers {
+		notes[n-1] = fmt.Sprintf("%d. %s", n, defs[id])
+	}
+
+	trailingNewline := lines[len(lines)-1] == ""
+	for len(body) > 0 && strings.TrimSpace(body[len(body)-1]) == "" {
+		body = body[:len(body)-1]
+	}
+	body = append(body, "", "---", "", "**Notes**", "")
+	body = append(body, notes...)
+	if trailingNewline {
+		body = append(body, "")
+	}
+	return body
+}
@@ -0,0 +1,52 @@
+package clickup
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata/markdown")
+
+// TestFormatMarkdown compares FormatMarkdown on each testdata/markdown/*.md
+// with its .golden file. Run with -update to regenerate them.
+func TestFormatMarkdown(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "markdown", "*.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		t.Fatal("no testdata/markdown inputs")
+	}
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".md")
+		t.Run(name, func(t *testing.T) {
+			src, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := FormatMarkdown(string(src))
+
+			golden := strings.TrimSuffix(input, ".md") + ".golden"
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run go test -update to create it)", err)
+			}
+			if got != string(want) {
+				t.Errorf("FormatMarkdown(%s) =\n%s\nwant\n%s", input, got, want)
+			}
+
+			// Formatting must be stable, or every sync would rewrite the task
+			if again := FormatMarkdown(got); again != got {
+				t.Errorf("FormatMarkdown is not idempotent on %s:\n%s", input, again)
+			}
+		})
+	}
+}
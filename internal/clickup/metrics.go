@@ -0,0 +1,44 @@
+package clickup
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// RequestEvent describes one API call after any retries.
+type RequestEvent struct {
+	Method string
+	// Endpoint is the request path with IDs replaced by {id}, e.g.
+	// "/task/{id}/comment", so calls can be grouped.
+	Endpoint string
+	// StatusCode is the final response status, 0 if there was none.
+	StatusCode int
+	// Attempts is 1 plus the number of retries.
+	Attempts int
+	// RateLimitWait is the time spent backing off after rate limit
+	// responses; waits after other transient errors aren't included.
+	RateLimitWait time.Duration
+	// Duration is the wall time of the call, retries and waits included.
+	Duration time.Duration
+}
+
+// WithRequestHook calls hook after every API request the client makes,
+// e.g. to collect per-run statistics. Clients may call it concurrently.
+func WithRequestHook(hook func(RequestEvent)) ClientOption {
+	return func(c *Client) { c.requestHook = hook }
+}
+
+// apiVersionRe matches the API version prefix of a request path.
+var apiVersionRe = regexp.MustCompile(`^(/api)?/v\d+`)
+
+// endpointOf returns the endpoint of a request path for RequestEvent.
+// ClickUp paths alternate between a resource and its ID (/list/{id}/task),
+// so every second segment is an ID.
+func endpointOf(path string) string {
+	segments := strings.Split(strings.Trim(apiVersionRe.ReplaceAllString(path, ""), "/"), "/")
+	for i := 1; i < len(segments); i += 2 {
+		segments[i] = "{id}"
+	}
+	return "/" + strings.Join(segments, "/")
+}
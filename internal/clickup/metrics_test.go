@@ -0,0 +1,60 @@
+package clickup
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEndpointOf(t *testing.T) {
+	tests := map[string]string{
+		"/api/v2/task/86abc":                    "/task/{id}",
+		"/api/v2/list/901/task":                 "/list/{id}/task",
+		"/api/v2/task/86abc/field/f1":           "/task/{id}/field/{id}",
+		"/api/v2/team":                          "/team",
+		"/api/v3/workspaces/1/tasks/86abc/home": "/workspaces/{id}/tasks/{id}/home",
+		"/user":                                 "/user",
+	}
+	for path, want := range tests {
+		if got := endpointOf(path); got != want {
+			t.Errorf("endpointOf(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestRequestHook(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"err":"Rate limit reached","ECODE":"APP_002"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"86abc","name":"Task"}`))
+	}))
+	defer server.Close()
+
+	var events []RequestEvent
+	client := NewClient("test",
+		WithBaseURL(server.URL+"/api/v2"),
+		WithRetryConfig(RetryConfig{MaxRetries: 2, BaseRetryDelay: 4 * time.Millisecond, MaxRetryDelay: 4 * time.Millisecond}),
+		WithRequestHook(func(e RequestEvent) { events = append(events, e) }),
+	)
+	if _, err := client.GetTask(context.Background(), "86abc"); err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1: %+v", len(events), events)
+	}
+	e := events[0]
+	if e.Method != "GET" || e.Endpoint != "/task/{id}" || e.StatusCode != http.StatusOK || e.Attempts != 2 {
+		t.Errorf("event = %+v", e)
+	}
+	if e.RateLimitWait < 4*time.Millisecond || e.Duration < e.RateLimitWait {
+		t.Errorf("RateLimitWait = %v, Duration = %v", e.RateLimitWait, e.Duration)
+	}
+}
@@ -0,0 +1,49 @@
+package clickup
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Owners for field_ownership.
+const (
+	OwnerBeans   = "beans"
+	OwnerClickUp = "clickup"
+)
+
+// ownedFields are the fields field_ownership can give an owner.
+var ownedFields = []string{"title", "description", "status", "priority", "type", "due", "tags"}
+
+// validateFieldOwnership checks that field_ownership names known fields
+// and owners.
+func validateFieldOwnership(ownership map[string]string) error {
+	for _, field := range slices.Sorted(maps.Keys(ownership)) {
+		if !slices.Contains(ownedFields, field) {
+			return fmt.Errorf("unknown field_ownership field %q (use %s)", field, strings.Join(ownedFields, ", "))
+		}
+		switch owner := ownership[field]; owner {
+		case OwnerBeans, OwnerClickUp:
+		default:
+			return fmt.Errorf("unknown field_ownership owner %q for %s (use beans or clickup)", owner, field)
+		}
+	}
+	return nil
+}
+
+// FieldOwner returns the side field_ownership makes authoritative for
+// field, or "" if it doesn't say. Sync pushes fields without an owner, as
+// it does those owned by beans.
+func (s *Sink) FieldOwner(field string) string {
+	if s.config == nil {
+		return ""
+	}
+	return s.config.FieldOwnership[field]
+}
+
+// clickUpOwns reports whether field_ownership makes ClickUp authoritative
+// for field, so sync leaves it alone on existing tasks.
+func (s *Sink) clickUpOwns(field string) bool {
+	return s.FieldOwner(field) == OwnerClickUp
+}
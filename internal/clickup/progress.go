@@ -0,0 +1,81 @@
+package clickup
+
+import (
+	"context"
+	"math"
+
+	"github.com/toba/bean-me-up/internal/beans"
+)
+
+// fieldTypeManualProgress is a progress custom field set by hand, which
+// custom_fields.progress can fill in. Automatic progress fields can't be
+// set.
+const fieldTypeManualProgress = "manual_progress"
+
+// BeanProgress returns how complete the bean is, as a whole percentage:
+// its extensions.clickup progress value if set, else the share of its
+// body's task list items that are checked. ok is false if it has neither.
+func BeanProgress(b *beans.Bean) (percent float64, ok bool) {
+	if v, set := b.Extensions[beans.PluginClickUp][beans.ExtKeyProgress]; set {
+		n, ok := toNumber(v)
+		if !ok {
+			return 0, false
+		}
+		return math.Round(min(max(n, 0), 100)), true
+	}
+	done, total := taskListCounts(b.Body)
+	if total == 0 {
+		return 0, false
+	}
+	return math.Round(float64(done) * 100 / float64(total)), true
+}
+
+// progressField returns the value to set custom_fields.progress to for the
+// bean, given the task's current value, or ok false if it has no progress,
+// the field isn't a number or manual progress field, or the value already
+// matches.
+func (s *Sink) progressField(ctx context.Context, b *beans.Bean, current any) (field CustomField, ok bool) {
+	if s.config == nil || s.config.CustomFields == nil || s.config.CustomFields.Progress == "" {
+		return CustomField{}, false
+	}
+	percent, ok := BeanProgress(b)
+	if !ok {
+		return CustomField{}, false
+	}
+	defs, err := s.fieldDefs(ctx)
+	if err != nil {
+		return CustomField{}, false
+	}
+	id := s.config.CustomFields.Progress
+	switch def := defs[id]; def.Type {
+	case fieldTypeNumber:
+		if n, ok := toNumber(current); ok && n == percent {
+			return CustomField{}, false
+		}
+		return CustomField{ID: id, Value: percent}, true
+	case fieldTypeManualProgress:
+		start, end := progressRange(def)
+		value := start + percent*(end-start)/100
+		if m, ok := current.(map[string]any); ok {
+			if n, ok := toNumber(m["current"]); ok && n == value {
+				return CustomField{}, false
+			}
+		}
+		return CustomField{ID: id, Value: map[string]any{"current": value}}, true
+	}
+	return CustomField{}, false
+}
+
+// progressRange returns the values a manual progress field runs between,
+// 0 to 100 unless its type_config says otherwise.
+func progressRange(def FieldInfo) (start, end float64) {
+	start, end = 0, 100
+	tc, _ := def.TypeConfig.(map[string]any)
+	if n, ok := toNumber(tc["start"]); ok {
+		start = n
+	}
+	if n, ok := toNumber(tc["end"]); ok && n != start {
+		end = n
+	}
+	return start, end
+}
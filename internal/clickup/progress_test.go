@@ -0,0 +1,58 @@
+package clickup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+)
+
+func TestBeanProgress(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		bean beans.Bean
+		want float64
+		ok   bool
+	}{
+		{"task list", beans.Bean{Body: "- [x] One\n- [ ] Two\n* [X] Three"}, 67, true},
+		{"progress value wins", beans.Bean{Body: "- [ ] One", Extensions: map[string]map[string]any{beans.PluginClickUp: {"progress": 40}}}, 40, true},
+		{"clamped", beans.Bean{Extensions: map[string]map[string]any{beans.PluginClickUp: {"progress": "120"}}}, 100, true},
+		{"not a number", beans.Bean{Extensions: map[string]map[string]any{beans.PluginClickUp: {"progress": "most"}}}, 0, false},
+		{"nothing to go on", beans.Bean{Body: "Plain"}, 0, false},
+	} {
+		got, ok := BeanProgress(&tt.bean)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: BeanProgress() = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestProgressField(t *testing.T) {
+	sink := NewSink(nil, &config.ClickUpConfig{CustomFields: &config.CustomFieldsMap{Progress: "f-progress"}}, "list")
+	b := &beans.Bean{Body: "- [x] One\n- [ ] Two"}
+	ctx := context.Background()
+
+	sink.fieldInfos = map[string]FieldInfo{"f-progress": {ID: "f-progress", Type: fieldTypeNumber}}
+	if f, ok := sink.progressField(ctx, b, "25"); !ok || f.Value != float64(50) {
+		t.Errorf("number field = %+v, %v", f, ok)
+	}
+	if _, ok := sink.progressField(ctx, b, float64(50)); ok {
+		t.Error("number field already at 50% was set")
+	}
+
+	// Manual progress fields are scaled to their range
+	sink.fieldInfos = map[string]FieldInfo{"f-progress": {ID: "f-progress", Type: fieldTypeManualProgress, TypeConfig: map[string]any{"start": 0, "end": 10}}}
+	f, ok := sink.progressField(ctx, b, map[string]any{"percent_complete": 20, "current": "2"})
+	if value, _ := f.Value.(map[string]any); !ok || value["current"] != float64(5) {
+		t.Errorf("manual progress field = %+v, %v", f, ok)
+	}
+	if _, ok := sink.progressField(ctx, b, map[string]any{"current": "5"}); ok {
+		t.Error("manual progress field already at 5 of 10 was set")
+	}
+
+	sink.fieldInfos = map[string]FieldInfo{"f-progress": {ID: "f-progress", Type: "automatic_progress"}}
+	if _, ok := sink.progressField(ctx, b, nil); ok {
+		t.Error("automatic progress field was set")
+	}
+}
@@ -0,0 +1,145 @@
+package clickup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/toba/bean-me-up/internal/beans"
+)
+
+// Relationship custom field types, whose values are linked tasks.
+const (
+	fieldTypeTasks            = "tasks"
+	fieldTypeListRelationship = "list_relationship"
+)
+
+// isRelationship reports whether def links tasks, so the values mapped to
+// it are bean IDs that sync links in the relationship pass.
+func isRelationship(def FieldInfo) bool {
+	return def.Type == fieldTypeTasks || def.Type == fieldTypeListRelationship
+}
+
+// parentField returns the custom_fields.parent field ID, or "" if the
+// parent is linked as a subtask.
+func (s *Sink) parentField() string {
+	if s.config == nil || s.config.CustomFields == nil {
+		return ""
+	}
+	return s.config.CustomFields.Parent
+}
+
+// referenceFields returns the bean IDs b refers to in each relationship
+// field, keyed by field ID: its parent in custom_fields.parent, and the
+// values of keys custom_fields.fields maps to relationship fields.
+func (s *Sink) referenceFields(ctx context.Context, b *beans.Bean) map[string][]string {
+	refs := make(map[string][]string)
+	if id := s.parentField(); id != "" && b.Parent != "" {
+		refs[id] = []string{b.Parent}
+	}
+	keys := s.mappedFields()
+	if len(keys) == 0 {
+		return refs
+	}
+	defs, err := s.fieldDefs(ctx)
+	if err != nil {
+		return refs
+	}
+	for _, key := range keys {
+		id := s.config.CustomFields.Fields[key]
+		v, ok := b.Extensions[beans.PluginClickUp][key]
+		if def, known := defs[id]; ok && known && isRelationship(def) {
+			refs[id] = append(refs[id], stringList(v)...)
+		}
+	}
+	return refs
+}
+
+// References returns the IDs of the beans b refers to in relationship
+// fields.
+func (s *Sink) References(ctx context.Context, b *beans.Bean) []string {
+	var ids []string
+	for _, refs := range s.referenceFields(ctx, b) {
+		for _, id := range refs {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// SetReferences links the task's relationship fields to the tasks of the
+// beans b refers to, given by taskIDs, and unlinks any others. A field
+// none of whose beans are synced is left alone, as are epic lists, which
+// relationships can't link.
+func (s *Sink) SetReferences(ctx context.Context, taskID string, b *beans.Bean, taskIDs map[string]string) error {
+	if IsListRef(taskID) {
+		return nil
+	}
+	refs := s.referenceFields(ctx, b)
+	if len(refs) == 0 {
+		return nil
+	}
+	task, err := s.client.GetTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, fieldID := range slices.Sorted(maps.Keys(refs)) {
+		var want []string
+		for _, beanID := range refs[fieldID] {
+			if id, ok := taskIDs[beanID]; ok && !IsListRef(id) && !slices.Contains(want, id) {
+				want = append(want, id)
+			}
+		}
+		if len(want) == 0 {
+			continue
+		}
+		var have []any
+		for _, f := range task.CustomFields {
+			if f.ID == fieldID {
+				have, _ = f.Value.([]any)
+			}
+		}
+		linked := linkedTaskIDs(have)
+		add, rem := []string{}, []string{}
+		for _, id := range want {
+			if !slices.Contains(linked, id) {
+				add = append(add, id)
+			}
+		}
+		for _, id := range linked {
+			if !slices.Contains(want, id) {
+				rem = append(rem, id)
+			}
+		}
+		if len(add) == 0 && len(rem) == 0 {
+			continue
+		}
+		value := map[string][]string{"add": add, "rem": rem}
+		if err := s.client.SetCustomFieldValue(ctx, taskID, fieldID, value); err != nil {
+			errs = append(errs, fmt.Errorf("field %s: %w", fieldID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// linkedTaskIDs returns the IDs of the tasks in a relationship field's
+// value, which lists them as objects.
+func linkedTaskIDs(value []any) []string {
+	var ids []string
+	for _, v := range value {
+		switch v := v.(type) {
+		case map[string]any:
+			if id, ok := v["id"].(string); ok {
+				ids = append(ids, id)
+			}
+		case string:
+			ids = append(ids, v)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,100 @@
+package clickup
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+)
+
+func TestSetReferences(t *testing.T) {
+	var posted map[string]map[string][]string // field ID -> value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/task/t-child":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "t-child", "custom_fields": []any{
+				map[string]any{"id": "f-parent", "value": []any{map[string]any{"id": "t-old"}}},
+				map[string]any{"id": "f-related", "value": []any{map[string]any{"id": "t-a"}}},
+			}})
+		case r.Method == http.MethodPost:
+			var body struct {
+				Value map[string][]string `json:"value"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			posted[r.URL.Path] = body.Value
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.ClickUpConfig{CustomFields: &config.CustomFieldsMap{
+		Parent: "f-parent",
+		Fields: map[string]string{"related": "f-related", "area": "f-text"},
+	}}
+	sink := NewSink(NewClient("token", WithBaseURL(server.URL)), cfg, "list")
+	sink.fieldInfos = map[string]FieldInfo{
+		"f-parent":  {ID: "f-parent", Type: fieldTypeTasks},
+		"f-related": {ID: "f-related", Type: fieldTypeListRelationship},
+		"f-text":    {ID: "f-text", Type: fieldTypeText},
+	}
+	b := &beans.Bean{ID: "child", Parent: "epic", Extensions: map[string]map[string]any{beans.PluginClickUp: {
+		"related": []any{"bean-a", "bean-b", "unsynced"}, "area": "API",
+	}}}
+
+	refs := sink.References(context.Background(), b)
+	slices.Sort(refs)
+	if !slices.Equal(refs, []string{"bean-a", "bean-b", "epic", "unsynced"}) {
+		t.Errorf("References() = %v", refs)
+	}
+
+	posted = map[string]map[string][]string{}
+	taskIDs := map[string]string{"epic": "t-epic", "bean-a": "t-a", "bean-b": "t-b"}
+	if err := sink.SetReferences(context.Background(), "t-child", b, taskIDs); err != nil {
+		t.Fatal(err)
+	}
+	parent := posted["/task/t-child/field/f-parent"]
+	if !slices.Equal(parent["add"], []string{"t-epic"}) || !slices.Equal(parent["rem"], []string{"t-old"}) {
+		t.Errorf("parent field = %v", parent)
+	}
+	related := posted["/task/t-child/field/f-related"]
+	if !slices.Equal(related["add"], []string{"t-b"}) || len(related["rem"]) != 0 {
+		t.Errorf("related field = %v", related)
+	}
+
+	// Fields already linked to the right tasks aren't written
+	posted = map[string]map[string][]string{}
+	if err := sink.SetReferences(context.Background(), "t-child", &beans.Bean{ID: "child", Parent: "old"}, map[string]string{"old": "t-old"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(posted) != 0 {
+		t.Errorf("unchanged references posted %v", posted)
+	}
+}
+
+func TestCreateTask_ParentField(t *testing.T) {
+	var created CreateTaskRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&created)
+		_, _ = w.Write([]byte(`{"id": "t-new"}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.ClickUpConfig{CustomFields: &config.CustomFieldsMap{Parent: "f-parent"}}
+	sink := NewSink(NewClient("token", WithBaseURL(server.URL)), cfg, "list")
+	if _, err := sink.CreateTask(context.Background(), &beans.Bean{ID: "child", Title: "Child"}, "t-epic"); err != nil {
+		t.Fatal(err)
+	}
+	if created.Parent != nil {
+		t.Errorf("task created as a subtask of %s, want top-level", *created.Parent)
+	}
+	if err := sink.SetParent(context.Background(), "t-epic", "t-new"); err != nil {
+		t.Errorf("SetParent() = %v, want nothing done", err)
+	}
+}
@@ -0,0 +1,33 @@
+package clickup
+
+import (
+	"cmp"
+	"context"
+
+	"github.com/toba/bean-me-up/internal/beans"
+)
+
+// defaultReviewMessage follows the reviewer's mention when review_request
+// has no message.
+const defaultReviewMessage = "please review"
+
+// requestReview posts an assigned comment asking the bean's reviewer, from
+// its review extension field, for a review if review_request is set and
+// the bean is in its status. Callers invoke it only when sync has just set
+// the task's status. Best-effort: the task is already written.
+func (s *Sink) requestReview(ctx context.Context, taskID string, b *beans.Bean) {
+	rr := s.config.ReviewRequest
+	if rr == nil || b.Status != rr.Status {
+		return
+	}
+	reviewer, ok := s.beanUser(b, beans.ExtKeyReview)
+	if !ok {
+		return
+	}
+	comment := &CreateCommentRequest{
+		Comment: append([]CommentPart{{Type: "tag", User: &CommentUser{ID: reviewer}}},
+			BuildComment(" "+cmp.Or(rr.Message, defaultReviewMessage), s.config.Users)...),
+		Assignee: &reviewer,
+	}
+	_, _ = s.client.CreateTaskComment(ctx, taskID, comment)
+}
@@ -0,0 +1,1112 @@
+package clickup
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/toba/bean-me-up/internal/auth"
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+// SinkName is the registry key and bean extension name for ClickUp.
+const SinkName = beans.PluginClickUp
+
+func init() {
+	syncer.Register(SinkName, newSinkFromConfig)
+}
+
+// newSinkFromConfig is the registry factory: NewSinkFromConfig with a
+// default client.
+func newSinkFromConfig(cfg *config.Config) (syncer.Sink, error) {
+	sink, err := NewSinkFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+// NewSinkFromConfig validates the ClickUp config and builds the sink using
+// CLICKUP_TOKEN, the configured token source, or the stored OAuth login.
+// opts configure its client.
+func NewSinkFromConfig(cfg *config.Config, opts ...ClientOption) (*Sink, error) {
+	if cfg.Beans.ClickUp.ListID == "" {
+		return nil, fmt.Errorf("ClickUp list_id is required in .beans.yml extensions.clickup or .beans.clickup.yml")
+	}
+	if _, err := ParseTitleTemplate(cfg.Beans.ClickUp.TitleTemplate); err != nil {
+		return nil, err
+	}
+	switch cfg.Beans.ClickUp.DescriptionOverflow {
+	case "", OverflowComment, OverflowAttachment, OverflowTruncate:
+	default:
+		return nil, fmt.Errorf("unknown description_overflow %q (use comment, attachment, or truncate)", cfg.Beans.ClickUp.DescriptionOverflow)
+	}
+	switch cfg.Beans.ClickUp.OnDelete {
+	case "", OnDeleteClose, OnDeleteArchive, OnDeleteDelete:
+	default:
+		return nil, fmt.Errorf("unknown on_delete %q (use close, archive, or delete)", cfg.Beans.ClickUp.OnDelete)
+	}
+	switch cfg.Beans.ClickUp.EpicsAs {
+	case "", EpicsAsList, EpicsAsFolder:
+	default:
+		return nil, fmt.Errorf("unknown epics_as %q (use list or folder)", cfg.Beans.ClickUp.EpicsAs)
+	}
+	for sprint, listID := range cfg.Beans.ClickUp.SprintLists {
+		if listID == "" {
+			return nil, fmt.Errorf("sprint_lists: no list ID for %q", sprint)
+		}
+	}
+	switch cfg.Beans.ClickUp.OnMove {
+	case "", syncer.OnMoveWarn, syncer.OnMoveFollow, syncer.OnMoveReturn:
+	default:
+		return nil, fmt.Errorf("unknown on_move %q (use warn, follow, or return)", cfg.Beans.ClickUp.OnMove)
+	}
+	if rr := cfg.Beans.ClickUp.ReviewRequest; rr != nil && rr.Status == "" {
+		return nil, fmt.Errorf("review_request needs a status")
+	}
+	switch cfg.Beans.ClickUp.AssigneeStrategy {
+	case "", AssigneeTokenOwner, AssigneeBean, AssigneeNone:
+	case AssigneeFixed:
+		if a := cfg.Beans.ClickUp.Assignee; a == nil || *a == 0 {
+			return nil, fmt.Errorf("assignee_strategy fixed needs an assignee user ID")
+		}
+	default:
+		return nil, fmt.Errorf("unknown assignee_strategy %q (use token_owner, bean_assignee, fixed, or none)", cfg.Beans.ClickUp.AssigneeStrategy)
+	}
+	if !slices.Contains(tagCases, cfg.Beans.ClickUp.TagCase) {
+		return nil, fmt.Errorf("unknown tag_case %q (use lower, upper, or title)", cfg.Beans.ClickUp.TagCase)
+	}
+	if err := validateFieldOwnership(cfg.Beans.ClickUp.FieldOwnership); err != nil {
+		return nil, err
+	}
+	if err := validateTagColors(cfg.Beans.ClickUp.TagColors); err != nil {
+		return nil, err
+	}
+	if cf := cfg.Beans.ClickUp.CustomFields; cf != nil {
+		if err := validateMappedFields(cf.Fields); err != nil {
+			return nil, err
+		}
+	}
+	token, err := auth.ClickUpToken(context.Background(), cfg.Beans.ClickUp.TokenSource)
+	if err != nil {
+		return nil, err
+	}
+	return NewSink(NewClient(token, opts...), &cfg.Beans.ClickUp, cfg.Beans.ClickUp.ListID), nil
+}
+
+// Values for assignee_strategy.
+const (
+	AssigneeTokenOwner = "token_owner"
+	AssigneeBean       = "bean_assignee"
+	AssigneeFixed      = "fixed"
+	AssigneeNone       = "none"
+)
+
+// Actions for on_delete.
+const (
+	OnDeleteClose   = "close"
+	OnDeleteArchive = "archive"
+	OnDeleteDelete  = "delete"
+)
+
+// Sink syncs beans to tasks in a ClickUp list.
+type Sink struct {
+	client *Client
+	config *config.ClickUpConfig
+	listID string
+
+	// Space ID for space-level tag management
+	spaceID string
+
+	// Parsed title_template, nil for plain bean titles
+	titleTemplate *template.Template
+
+	// Permalink to a bean's file, for source_footer and the source_url field
+	sourceURL func(*beans.Bean) string
+
+	// With epics_as, the list each task is in by ID, so subtasks can be
+	// created next to their parent
+	mu        sync.Mutex
+	taskLists map[string]string
+
+	// The list's custom fields by ID, for custom_fields.fields
+	fieldsMu   sync.Mutex
+	fieldInfos map[string]FieldInfo
+
+	// Space tags that couldn't be created, by tag, and whether creating
+	// them was refused outright
+	spaceTagsMu     sync.Mutex
+	spaceTagErrs    map[string]error
+	spaceTagsDenied bool
+}
+
+// NewSink creates a sink that creates tasks in listID using the mappings in cfg.
+// An invalid title_template is ignored; use ParseTitleTemplate to validate it.
+func NewSink(client *Client, cfg *config.ClickUpConfig, listID string) *Sink {
+	tmpl, _ := ParseTitleTemplate(cfg.TitleTemplate)
+	return &Sink{
+		client:        client,
+		config:        cfg,
+		listID:        listID,
+		titleTemplate: tmpl,
+	}
+}
+
+// ParseTitleTemplate parses a title_template. It returns nil for an empty
+// template.
+func ParseTitleTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New("title_template").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid title_template: %w", err)
+	}
+	// Catch references to fields beans don't have before the first sync
+	sample := &beans.Bean{ID: "bean-abc1", Slug: "sample", Title: "Sample", Status: "todo", Type: beans.TypeTask}
+	if err := tmpl.Execute(new(strings.Builder), sample); err != nil {
+		return nil, fmt.Errorf("invalid title_template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// TaskName returns the task name for a bean: the title_template applied to
+// the bean, or its title. Sync compares this with the task's name, so a
+// templated name is only rewritten when the bean's fields change.
+func (s *Sink) TaskName(b *beans.Bean) string {
+	if s.titleTemplate == nil {
+		return b.Title
+	}
+	var name strings.Builder
+	if err := s.titleTemplate.Execute(&name, b); err != nil {
+		return b.Title
+	}
+	return name.String()
+}
+
+// SetSourceURL sets how permalinks to bean files are built for
+// source_footer and the source_url custom field. Without it neither is
+// written.
+func (s *Sink) SetSourceURL(fn func(*beans.Bean) string) {
+	s.sourceURL = fn
+}
+
+// SourceURL returns the permalink to the bean's file, or "" if unknown.
+func (s *Sink) SourceURL(b *beans.Bean) string {
+	if s.sourceURL == nil {
+		return ""
+	}
+	return s.sourceURL(b)
+}
+
+// Name returns SinkName.
+func (s *Sink) Name() string { return SinkName }
+
+// Prepare pre-fetches the authorized user and the list's space tags.
+func (s *Sink) Prepare(ctx context.Context) error {
+	// Pre-fetch authorized user to avoid per-task API calls
+	if _, err := s.client.GetAuthorizedUser(ctx); err != nil {
+		// Non-fatal - will just create unassigned tasks if this fails
+		_ = err
+	}
+
+	// Pre-fetch list info for space ID, then populate space tag cache
+	list, err := s.client.GetList(ctx, s.listID)
+	if err != nil {
+		return err
+	}
+	if list.SpaceID != "" {
+		s.spaceID = list.SpaceID
+		if !s.createsSpaceTags() {
+			return nil
+		}
+		// Non-fatal - tags will still be added at task level
+		return s.client.PopulateSpaceTagCache(ctx, s.spaceID)
+	}
+	return nil
+}
+
+// GetTask fetches a task. The *TaskInfo is kept in the ref for UpdateTask.
+// An epic's list (see epics_as) is kept as a *ListDetails instead.
+func (s *Sink) GetTask(ctx context.Context, taskID string) (*syncer.TaskRef, error) {
+	if listID, ok := parseListRef(taskID); ok {
+		return s.getEpicList(ctx, listID)
+	}
+	task, err := s.client.GetTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	s.rememberList(task.ID, task.List.ID)
+	ref := taskRef(task)
+	switch {
+	case s.config.EpicsAs != "":
+		// Tasks live in their epic's list, so only leaving the space is a move
+		if task.Space.ID != "" && s.spaceID != "" && task.Space.ID != s.spaceID {
+			ref.MovedTo = "space " + task.Space.ID
+		}
+	case task.List.ID != "" && task.List.ID != s.listID:
+		ref.MovedTo = "list " + cmp.Or(task.List.Name, task.List.ID)
+	}
+	return ref, nil
+}
+
+// ListTasks fetches every task in the configured list in one paginated
+// request, for spotting tasks edited in ClickUp since they were synced.
+func (s *Sink) ListTasks(ctx context.Context) (map[string]*syncer.TaskRef, error) {
+	tasks, err := s.client.GetListTasks(ctx, s.listID)
+	if err != nil {
+		return nil, err
+	}
+	refs := make(map[string]*syncer.TaskRef, len(tasks))
+	for i := range tasks {
+		refs[tasks[i].ID] = taskRef(&tasks[i])
+	}
+	return refs, nil
+}
+
+// ReturnTask moves a task that left the configured list back into it.
+func (s *Sink) ReturnTask(ctx context.Context, task *syncer.TaskRef) (*syncer.TaskRef, error) {
+	info, _ := task.Remote.(*TaskInfo)
+	teamID := s.config.TeamID
+	if info != nil && info.TeamID != "" {
+		teamID = info.TeamID
+	}
+	if teamID == "" {
+		return nil, fmt.Errorf("moving task %s: workspace unknown; set team_id", task.ID)
+	}
+	if err := s.client.MoveTask(ctx, teamID, task.ID, s.listID); err != nil {
+		return nil, err
+	}
+	returned := *task
+	returned.MovedTo = ""
+	return &returned, nil
+}
+
+// CreateTask creates a task for the bean, as a subtask of parentTaskID if set.
+// With epics_as, epics become lists and their children's tasks go in them.
+// With custom_fields.parent, the task is top-level and SetReferences links
+// it to its parent.
+func (s *Sink) CreateTask(ctx context.Context, b *beans.Bean, parentTaskID string) (*syncer.TaskRef, error) {
+	if s.isEpicList(b) {
+		return s.createEpicList(ctx, b)
+	}
+	if s.parentField() != "" && !IsListRef(parentTaskID) {
+		parentTaskID = ""
+	}
+	listID, err := s.taskList(ctx, &parentTaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	createReq := &CreateTaskRequest{
+		Name:                s.TaskName(b),
+		MarkdownDescription: s.buildTaskDescription(b),
+		Status:              s.getClickUpStatus(b.Status),
+		Priority:            s.getClickUpPriority(b.Priority),
+		Assignees:           s.getAssignees(ctx, b),
+		CustomFields:        s.buildCustomFields(ctx, b),
+		CustomItemID:        s.getClickUpCustomItemID(b.Type),
+	}
+
+	// Set due date if bean has one
+	if b.Due != nil {
+		if dueTime, err := parseBeanDueDate(*b.Due); err == nil {
+			millis := toLocalDateMillis(dueTime)
+			createReq.DueDate = &millis
+			createReq.DueDatetime = ptrBool(false)
+		}
+	}
+
+	if parentTaskID != "" {
+		createReq.Parent = &parentTaskID
+	}
+
+	// Templates apply to top-level tasks; ClickUp can't create a subtask from one
+	templateID := s.taskTemplate(b)
+	var task *TaskInfo
+	if templateID != "" && parentTaskID == "" {
+		task, err = s.createFromTemplate(ctx, listID, templateID, createReq)
+	} else {
+		task, err = s.client.CreateTask(ctx, listID, createReq)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.rememberList(task.ID, listID)
+	if _, overflow := s.splitDescription(b); overflow != "" {
+		s.writeOverflow(ctx, task.ID, nil, b, overflow)
+	}
+	// Best-effort; failing now would lose the new task's link
+	_, _ = s.syncSprint(ctx, task, b)
+	_, _ = s.syncWorklog(ctx, task.ID, task.TeamID, b)
+	if createReq.Status != "" {
+		s.requestReview(ctx, task.ID, b)
+	}
+	ref := taskRef(task)
+	if templateID == "" || parentTaskID != "" {
+		ref.Tags = nil // a new task has no tags yet
+	}
+	return ref, nil
+}
+
+// UpdateTask is UpdateTaskFields, reporting only whether anything changed.
+func (s *Sink) UpdateTask(ctx context.Context, current *syncer.TaskRef, b *beans.Bean) (*syncer.TaskRef, bool, error) {
+	ref, fields, err := s.UpdateTaskFields(ctx, current, b)
+	return ref, len(fields) > 0, err
+}
+
+// UpdateTaskFields sends only the fields that differ from the current task,
+// then updates changed custom fields (best-effort), and returns the names
+// of the fields it changed.
+func (s *Sink) UpdateTaskFields(ctx context.Context, current *syncer.TaskRef, b *beans.Bean) (*syncer.TaskRef, []string, error) {
+	if _, ok := parseListRef(current.ID); ok {
+		return s.updateEpicList(ctx, current, b)
+	}
+	task, ok := current.Remote.(*TaskInfo)
+	if !ok {
+		var err error
+		if task, err = s.client.GetTask(ctx, current.ID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	update := s.buildUpdateRequest(task, b, s.buildTaskDescription(b), s.getClickUpPriority(b.Priority), s.getClickUpStatus(b.Status))
+
+	ref := current
+	fields := update.changedFields()
+	if update.hasChanges() {
+		updatedTask, err := s.client.UpdateTask(ctx, current.ID, update)
+		if err != nil {
+			return nil, nil, err
+		}
+		ref = taskRef(updatedTask)
+
+		// Only a rewritten description needs its overflow written again
+		if _, overflow := s.splitDescription(b); update.MarkdownDescription != nil && overflow != "" {
+			s.writeOverflow(ctx, current.ID, task, b, overflow)
+		}
+	}
+
+	if s.updateChangedCustomFields(ctx, task, current.ID, b) {
+		fields = append(fields, "custom_fields")
+	}
+
+	sprintChanged, err := s.syncSprint(ctx, task, b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sprintChanged {
+		fields = append(fields, "sprint")
+	}
+	worklogged, err := s.syncWorklog(ctx, current.ID, task.TeamID, b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if worklogged {
+		fields = append(fields, "time_tracked")
+	}
+	if update.Status != nil {
+		s.commentStatusChange(ctx, current.ID, task.Status.Status, *update.Status, b)
+		s.requestReview(ctx, current.ID, b)
+	}
+
+	return ref, fields, nil
+}
+
+// UpdateStatus sets the task's status from the bean's, skipping the update
+// if the task already has it.
+func (s *Sink) UpdateStatus(ctx context.Context, taskID string, b *beans.Bean) (*syncer.TaskRef, bool, error) {
+	status := s.getClickUpStatus(b.Status)
+	if _, isList := parseListRef(taskID); status == "" || isList || s.clickUpOwns("status") {
+		return nil, false, nil
+	}
+	current, err := s.client.GetTask(ctx, taskID)
+	if err != nil {
+		return nil, false, err
+	}
+	if strings.EqualFold(current.Status.Status, status) {
+		return taskRef(current), false, nil
+	}
+	task, err := s.client.UpdateTask(ctx, taskID, &UpdateTaskRequest{Status: &status})
+	if err != nil {
+		return nil, false, err
+	}
+	s.commentStatusChange(ctx, taskID, current.Status.Status, status, b)
+	s.requestReview(ctx, taskID, b)
+	return taskRef(task), true, nil
+}
+
+// RemoveTask applies on_delete to the task of a deleted bean and returns
+// the action taken, or "" if on_delete isn't set. A task that is already
+// gone counts as removed.
+func (s *Sink) RemoveTask(ctx context.Context, taskID string) (string, error) {
+	if _, ok := parseListRef(taskID); ok {
+		return "", nil // an epic's list may hold other beans' tasks
+	}
+	var err error
+	switch action := s.config.OnDelete; action {
+	case OnDeleteClose:
+		var status string
+		if status, err = s.closeStatus(ctx); err != nil {
+			return "", err
+		}
+		_, err = s.client.UpdateTask(ctx, taskID, &UpdateTaskRequest{Status: &status})
+	case OnDeleteArchive:
+		_, err = s.client.UpdateTask(ctx, taskID, &UpdateTaskRequest{Archived: ptrBool(true)})
+	case OnDeleteDelete:
+		err = s.client.DeleteTask(ctx, taskID)
+	default:
+		return "", nil
+	}
+	if err != nil && !errors.Is(err, ErrTaskNotFound) {
+		return "", err
+	}
+	return s.config.OnDelete, nil
+}
+
+// closeStatus returns the status on_delete: close sets: the one scrapped
+// maps to if the list has it, else the list's closed status.
+func (s *Sink) closeStatus(ctx context.Context) (string, error) {
+	list, err := s.client.GetList(ctx, s.listID)
+	if err != nil {
+		return "", err
+	}
+	mapped := s.getClickUpStatus("scrapped")
+	var closed string
+	for _, st := range list.Statuses {
+		if mapped != "" && strings.EqualFold(st.Status, mapped) {
+			return st.Status, nil
+		}
+		if st.Type == statusTypeClosed && closed == "" {
+			closed = st.Status
+		}
+	}
+	if closed == "" {
+		return "", fmt.Errorf("on_delete close: list %s has no status %q for scrapped and no closed status; set status_mapping.scrapped", s.listID, mapped)
+	}
+	return closed, nil
+}
+
+// SyncTags adds and removes task tags to match the bean.
+func (s *Sink) SyncTags(ctx context.Context, task *syncer.TaskRef, b *beans.Bean) bool {
+	if _, ok := parseListRef(task.ID); ok {
+		return false // lists have no tags; they're in the description
+	}
+	if s.clickUpOwns("tags") {
+		return false
+	}
+	current := make([]Tag, len(task.Tags))
+	for i, name := range task.Tags {
+		current[i] = Tag{Name: name}
+	}
+	return s.syncTags(ctx, task.ID, b, current)
+}
+
+// SetRelationship marks the blocked task as waiting on the blocker.
+// In ClickUp: we set B as "waiting on" A (depends_on = A).
+func (s *Sink) SetRelationship(ctx context.Context, blockerTaskID, blockedTaskID string) error {
+	_, blockerIsList := parseListRef(blockerTaskID)
+	if _, blockedIsList := parseListRef(blockedTaskID); blockerIsList || blockedIsList {
+		return nil // lists can't have dependencies
+	}
+	return s.client.AddDependency(ctx, blockedTaskID, blockerTaskID)
+}
+
+// SetParent makes the child task a subtask of the parent, e.g. for a task
+// linked to an existing bean rather than created under its parent. Epic
+// lists aren't tasks, so they are left alone, as are tasks whose parent
+// custom_fields.parent links instead.
+func (s *Sink) SetParent(ctx context.Context, parentTaskID, childTaskID string) error {
+	if IsListRef(parentTaskID) || IsListRef(childTaskID) || s.parentField() != "" {
+		return nil
+	}
+	child, err := s.client.GetTask(ctx, childTaskID)
+	if err != nil {
+		return err
+	}
+	if child.Parent != nil && *child.Parent == parentTaskID {
+		return nil
+	}
+	_, err = s.client.UpdateTask(ctx, childTaskID, &UpdateTaskRequest{Parent: &parentTaskID})
+	return err
+}
+
+// NewTaskRef converts a task to the sink-neutral reference sync works with.
+func NewTaskRef(task *TaskInfo) *syncer.TaskRef {
+	return taskRef(task)
+}
+
+// taskRef converts a ClickUp task to a sink-neutral reference.
+func taskRef(task *TaskInfo) *syncer.TaskRef {
+	ref := &syncer.TaskRef{ID: task.ID, URL: task.URL, Remote: task, UpdatedAt: task.UpdatedAt(), Blocking: task.Blocking()}
+	for _, t := range task.Tags {
+		ref.Tags = append(ref.Tags, t.Name)
+	}
+	return ref
+}
+
+// buildTaskDescription builds the ClickUp task markdown description from a
+// bean, truncated to the description limit.
+func (s *Sink) buildTaskDescription(b *beans.Bean) string {
+	description, _ := s.splitDescription(b)
+	return description
+}
+
+// getClickUpPriority maps a bean priority to a ClickUp priority value.
+// Returns nil if no mapping exists (bean has no priority or unknown priority).
+func (s *Sink) getClickUpPriority(beanPriority string) *int {
+	if beanPriority == "" {
+		return nil
+	}
+
+	// Use custom mapping if configured
+	if s.config != nil && s.config.PriorityMapping != nil {
+		if priority, ok := s.config.PriorityMapping[beanPriority]; ok {
+			return &priority
+		}
+	}
+
+	// Fall back to default mapping
+	if priority, ok := config.DefaultPriorityMapping[beanPriority]; ok {
+		return &priority
+	}
+
+	return nil
+}
+
+// buildCustomFields builds the custom fields array for task creation.
+func (s *Sink) buildCustomFields(ctx context.Context, b *beans.Bean) []CustomField {
+	if s.config == nil || s.config.CustomFields == nil {
+		return nil
+	}
+
+	var fields []CustomField
+	cf := s.config.CustomFields
+
+	// Bean ID field (text)
+	if cf.BeanID != "" {
+		fields = append(fields, CustomField{
+			ID:    cf.BeanID,
+			Value: b.ID,
+		})
+	}
+
+	// Source URL field (url)
+	if cf.SourceURL != "" {
+		if u := s.SourceURL(b); u != "" {
+			fields = append(fields, CustomField{
+				ID:    cf.SourceURL,
+				Value: u,
+			})
+		}
+	}
+
+	// Created at field (date - Unix milliseconds)
+	// Convert to local date at midnight to avoid timezone display issues in ClickUp
+	if cf.CreatedAt != "" && b.CreatedAt != nil {
+		fields = append(fields, CustomField{
+			ID:    cf.CreatedAt,
+			Value: toLocalDateMillis(*b.CreatedAt),
+		})
+	}
+
+	// Updated at field (date - Unix milliseconds)
+	// Convert to local date at midnight to avoid timezone display issues in ClickUp
+	if cf.UpdatedAt != "" && b.UpdatedAt != nil {
+		fields = append(fields, CustomField{
+			ID:    cf.UpdatedAt,
+			Value: toLocalDateMillis(*b.UpdatedAt),
+		})
+	}
+
+	if f, ok := s.progressField(ctx, b, nil); ok {
+		fields = append(fields, f)
+	}
+
+	// Fields mapped from the bean's extensions.clickup values
+	fields = append(fields, s.mappedFieldValues(ctx, b, nil)...)
+
+	return fields
+}
+
+// toLocalDateMillis converts a timestamp to midnight of that date in local timezone.
+// This ensures ClickUp displays the date the user expects (the local date when the bean
+// was created) rather than potentially showing "Tomorrow" due to UTC offset.
+func toLocalDateMillis(t time.Time) int64 {
+	local := t.Local()
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)
+	return midnight.UnixMilli()
+}
+
+// assigneeStrategy returns the assignee_strategy in effect. Without one,
+// an assignee means fixed, or none if it's 0, as before the setting existed.
+func (s *Sink) assigneeStrategy() string {
+	if s.config == nil {
+		return AssigneeTokenOwner
+	}
+	if s.config.AssigneeStrategy != "" {
+		return s.config.AssigneeStrategy
+	}
+	if s.config.Assignee != nil {
+		if *s.config.Assignee == 0 {
+			return AssigneeNone
+		}
+		return AssigneeFixed
+	}
+	return AssigneeTokenOwner
+}
+
+// getAssignees returns the assignee list for task creation per assignee_strategy.
+func (s *Sink) getAssignees(ctx context.Context, b *beans.Bean) []int {
+	switch s.assigneeStrategy() {
+	case AssigneeNone:
+		return nil
+	case AssigneeFixed:
+		if s.config.Assignee == nil || *s.config.Assignee == 0 {
+			return nil
+		}
+		return []int{*s.config.Assignee}
+	case AssigneeBean:
+		if id, ok := s.beanUser(b, beans.ExtKeyAssignee); ok {
+			return []int{id}
+		}
+		// Beans without one fall back to the configured assignee, if any
+		if s.config.Assignee != nil && *s.config.Assignee != 0 {
+			return []int{*s.config.Assignee}
+		}
+		return nil
+	}
+
+	// Default: assign to token owner
+	user, err := s.client.GetAuthorizedUser(ctx)
+	if err != nil {
+		// Can't get user, leave unassigned
+		return nil
+	}
+	return []int{user.ID}
+}
+
+// beanUser resolves a user in the bean's extension field key, e.g.
+// extensions.clickup.assignee: ana, to a ClickUp user ID: a short name from
+// users, or a user ID. ok is false if it's unset or unknown.
+func (s *Sink) beanUser(b *beans.Bean, key string) (id int, ok bool) {
+	switch v := b.Extensions[beans.PluginClickUp][key].(type) {
+	case string:
+		name := strings.TrimPrefix(v, "@")
+		if id, ok := s.config.Users[name]; ok {
+			return id, true
+		}
+		if id, err := strconv.Atoi(name); err == nil && id != 0 {
+			return id, true
+		}
+	case int:
+		return v, v != 0
+	case float64: // Numbers decoded from JSON
+		return int(v), v != 0
+	}
+	return 0, false
+}
+
+// buildUpdateRequest builds an UpdateTaskRequest containing only fields that differ from current.
+func (s *Sink) buildUpdateRequest(current *TaskInfo, b *beans.Bean, description string, priority *int, clickUpStatus string) *UpdateTaskRequest {
+	update := &UpdateTaskRequest{}
+
+	// Only include name if changed, and fields ClickUp owns not at all
+	if name := s.TaskName(b); current.Name != name && !s.clickUpOwns("title") {
+		update.Name = &name
+	}
+
+	// Only include description if changed
+	if current.Description != description && !s.clickUpOwns("description") {
+		update.MarkdownDescription = &description
+	}
+
+	// Only include priority if changed
+	if !s.priorityEqual(current.Priority, priority) && !s.clickUpOwns("priority") {
+		update.Priority = priority
+	}
+
+	// Only include status if changed
+	if clickUpStatus != "" && current.Status.Status != clickUpStatus && !s.clickUpOwns("status") {
+		update.Status = &clickUpStatus
+	}
+
+	// Only include due date if changed
+	newDueMillis := beanDueToMillis(b.Due)
+	currentDueMillis := clickUpDueToMillis(current.DueDate)
+	if !int64PtrEqual(currentDueMillis, newDueMillis) && !s.clickUpOwns("due") {
+		if newDueMillis != nil {
+			update.DueDate = newDueMillis
+			update.DueDatetime = ptrBool(false)
+		} else {
+			// Clear due date: ClickUp accepts null to remove it
+			zero := int64(0)
+			update.DueDate = &zero
+		}
+	}
+
+	// Only include custom item ID if changed
+	newItemID := s.getClickUpCustomItemID(b.Type)
+	if !intPtrEqual(current.CustomItemID, newItemID) && !s.clickUpOwns("type") {
+		update.CustomItemID = newItemID
+	}
+
+	return update
+}
+
+// priorityEqual compares a TaskPriority (from ClickUp response) with a target priority int pointer.
+func (s *Sink) priorityEqual(current *TaskPriority, target *int) bool {
+	if current == nil && target == nil {
+		return true
+	}
+	if current == nil || target == nil {
+		return false
+	}
+	return current.ID == *target
+}
+
+// intPtrEqual compares two int pointers for equality.
+func intPtrEqual(a, b *int) bool {
+	if a == nil && b == nil {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return *a == *b
+}
+
+// updateChangedCustomFields updates only custom fields that have changed.
+// Returns true if any field was updated.
+func (s *Sink) updateChangedCustomFields(ctx context.Context, current *TaskInfo, taskID string, b *beans.Bean) bool {
+	if s.config == nil || s.config.CustomFields == nil {
+		return false
+	}
+
+	cf := s.config.CustomFields
+
+	// Build a map of current custom field values by ID for quick lookup
+	currentFields := make(map[string]any)
+	for _, f := range current.CustomFields {
+		currentFields[f.ID] = f.Value
+	}
+
+	// Only fields whose value differs are written
+	var mutations []func() error
+	set := func(fieldID string, value any) {
+		mutations = append(mutations, func() error {
+			return s.client.SetCustomFieldValue(ctx, taskID, fieldID, value)
+		})
+	}
+
+	// Bean ID field (text)
+	if cf.BeanID != "" {
+		currentVal, _ := currentFields[cf.BeanID].(string)
+		if currentVal != b.ID {
+			set(cf.BeanID, b.ID)
+		}
+	}
+
+	// Source URL field (url)
+	if cf.SourceURL != "" {
+		if u := s.SourceURL(b); u != "" {
+			currentVal, _ := currentFields[cf.SourceURL].(string)
+			if currentVal != u {
+				set(cf.SourceURL, u)
+			}
+		}
+	}
+
+	// Created at field (date - Unix milliseconds)
+	if cf.CreatedAt != "" && b.CreatedAt != nil {
+		newVal := toLocalDateMillis(*b.CreatedAt)
+		if !customFieldDateEqual(currentFields[cf.CreatedAt], newVal) {
+			set(cf.CreatedAt, newVal)
+		}
+	}
+
+	// Updated at field (date - Unix milliseconds)
+	if cf.UpdatedAt != "" && b.UpdatedAt != nil {
+		newVal := toLocalDateMillis(*b.UpdatedAt)
+		if !customFieldDateEqual(currentFields[cf.UpdatedAt], newVal) {
+			set(cf.UpdatedAt, newVal)
+		}
+	}
+
+	if cf.Progress != "" {
+		if f, ok := s.progressField(ctx, b, currentFields[cf.Progress]); ok {
+			set(f.ID, f.Value)
+		}
+	}
+
+	for _, f := range s.mappedFieldValues(ctx, b, currentFields) {
+		set(f.ID, f.Value)
+	}
+
+	return mutateConcurrently(mutations)
+}
+
+// taskMutationConcurrency bounds the custom field and tag requests made at
+// once for one task, on top of the beans syncing in parallel.
+const taskMutationConcurrency = 4
+
+// mutateConcurrently runs independent best-effort mutations of one task,
+// at most taskMutationConcurrency at a time, and reports whether any
+// succeeded.
+func mutateConcurrently(mutations []func() error) bool {
+	var wg sync.WaitGroup
+	var succeeded atomic.Bool
+	sem := make(chan struct{}, taskMutationConcurrency)
+	for _, mutate := range mutations {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if mutate() == nil {
+				succeeded.Store(true)
+			}
+		})
+	}
+	wg.Wait()
+	return succeeded.Load()
+}
+
+// commentStatusChange notes on the task that sync moved it from one status
+// to another, with comment_on_status_change. Best-effort.
+func (s *Sink) commentStatusChange(ctx context.Context, taskID, from, to string, b *beans.Bean) {
+	if !s.config.CommentOnStatusChange {
+		return
+	}
+	text := fmt.Sprintf("beanup: moved from %q to %q to match bean %s (%s)", from, to, b.ID, b.Status)
+	_, _ = s.client.CreateTaskComment(ctx, taskID, &CreateCommentRequest{Comment: []CommentPart{{Text: text}}})
+}
+
+// customFieldDateEqual compares a custom field date value (from ClickUp, can be string or number)
+// with a target milliseconds value.
+func customFieldDateEqual(current any, target int64) bool {
+	if current == nil {
+		return false
+	}
+
+	// ClickUp returns date fields as string timestamps in milliseconds
+	switch v := current.(type) {
+	case string:
+		// Parse string to int64
+		var parsed int64
+		if _, err := fmt.Sscanf(v, "%d", &parsed); err == nil {
+			return parsed == target
+		}
+	case float64:
+		// JSON numbers are decoded as float64
+		return int64(v) == target
+	case int64:
+		return v == target
+	}
+	return false
+}
+
+// getClickUpStatus maps a bean status to a ClickUp status name.
+func (s *Sink) getClickUpStatus(beanStatus string) string {
+	// Use custom mapping if configured
+	if s.config != nil && s.config.StatusMapping != nil {
+		if status, ok := s.config.StatusMapping[beanStatus]; ok {
+			return status
+		}
+	}
+
+	// Fall back to default mapping
+	if status, ok := config.DefaultStatusMapping[beanStatus]; ok {
+		return status
+	}
+
+	return ""
+}
+
+// getClickUpCustomItemID maps a bean type to a ClickUp custom item ID.
+// Returns nil if no mapping exists (task will use default type).
+func (s *Sink) getClickUpCustomItemID(beanType string) *int {
+	if beanType == "" {
+		return nil
+	}
+
+	// Use custom mapping if configured
+	if s.config != nil && s.config.TypeMapping != nil {
+		if customItemID, ok := s.config.TypeMapping[beanType]; ok {
+			return &customItemID
+		}
+	}
+
+	return nil
+}
+
+// syncTags syncs bean tags to ClickUp task tags.
+// Returns true if any tags were added or removed.
+func (s *Sink) syncTags(ctx context.Context, taskID string, b *beans.Bean, currentTags []Tag) bool {
+	// ClickUp may lower-case tag names, so compare case-insensitively
+	// to keep mapped or title-cased tags from being re-added every sync.
+
+	// Build set of current ClickUp tag names
+	current := make(map[string]bool)
+	for _, t := range currentTags {
+		current[strings.ToLower(t.Name)] = true
+	}
+
+	// Build set of desired bean tag names
+	desiredTags := s.taskTags(b)
+	desired := make(map[string]bool)
+	for _, t := range desiredTags {
+		desired[strings.ToLower(t)] = true
+	}
+
+	var mutations []func() error
+
+	// Add missing tags
+	for _, t := range desiredTags {
+		if !current[strings.ToLower(t)] {
+			mutations = append(mutations, func() error {
+				// Ensure tag exists at space level so it's discoverable in the tag picker
+				s.ensureSpaceTag(ctx, t)
+				return s.client.AddTagToTask(ctx, taskID, t)
+			})
+		}
+	}
+
+	// Remove extra tags, leaving ones sync doesn't manage
+	for _, t := range currentTags {
+		if !desired[strings.ToLower(t.Name)] && s.managesTag(t.Name) {
+			mutations = append(mutations, func() error {
+				return s.client.RemoveTagFromTask(ctx, taskID, t.Name)
+			})
+		}
+	}
+
+	return mutateConcurrently(mutations)
+}
+
+// Values accepted by tag_case.
+var tagCases = []string{"", "lower", "upper", "title"}
+
+// taskTags returns the bean's tags as they appear on its task: mapped by
+// tag_mapping, or normalized by tag_separator and tag_case and given the
+// tag_prefix.
+func (s *Sink) taskTags(b *beans.Bean) []string {
+	tags := make([]string, len(b.Tags))
+	for i, t := range b.Tags {
+		tags[i] = s.taskTag(t)
+	}
+	return tags
+}
+
+func (s *Sink) taskTag(tag string) string {
+	if s.config == nil {
+		return tag
+	}
+	if mapped, ok := s.config.TagMapping[tag]; ok {
+		return mapped
+	}
+	if sep := s.config.TagSeparator; sep != "" {
+		tag = strings.NewReplacer("-", sep, "_", sep).Replace(tag)
+	}
+	switch s.config.TagCase {
+	case "lower":
+		tag = strings.ToLower(tag)
+	case "upper":
+		tag = strings.ToUpper(tag)
+	case "title":
+		tag = titleCase(tag)
+	}
+	return s.config.TagPrefix + tag
+}
+
+// titleCase upper-cases the first letter of each word and lower-cases the
+// rest, splitting words on spaces, "-", and "_".
+func titleCase(s string) string {
+	start := true
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '_' {
+			start = true
+			return r
+		}
+		if start {
+			start = false
+			return unicode.ToUpper(r)
+		}
+		return unicode.ToLower(r)
+	}, s)
+}
+
+// managesTag reports whether sync may remove a task tag: it must not be
+// protected and, with a tag_prefix, must carry the prefix or be a
+// tag_mapping target.
+func (s *Sink) managesTag(name string) bool {
+	if s.config == nil {
+		return true
+	}
+	if slices.ContainsFunc(s.config.ProtectedTags, func(p string) bool { return strings.EqualFold(p, name) }) {
+		return false
+	}
+	for _, mapped := range s.config.TagMapping {
+		if strings.EqualFold(mapped, name) {
+			return true
+		}
+	}
+	return strings.HasPrefix(strings.ToLower(name), strings.ToLower(s.config.TagPrefix))
+}
+
+// parseBeanDueDate parses a bean due date string ("YYYY-MM-DD") into a time.Time.
+func parseBeanDueDate(s string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", s, time.Local)
+}
+
+// beanDueToMillis converts a bean due date string to Unix milliseconds (local midnight).
+// Returns nil if the bean has no due date or the date is unparseable.
+func beanDueToMillis(due *string) *int64 {
+	if due == nil || *due == "" {
+		return nil
+	}
+	t, err := parseBeanDueDate(*due)
+	if err != nil {
+		return nil
+	}
+	millis := toLocalDateMillis(t)
+	return &millis
+}
+
+// clickUpDueToMillis parses ClickUp's due_date string (Unix ms) into an *int64.
+// Returns nil if the string is nil or empty.
+func clickUpDueToMillis(s *string) *int64 {
+	if s == nil || *s == "" {
+		return nil
+	}
+	var millis int64
+	if _, err := fmt.Sscanf(*s, "%d", &millis); err != nil {
+		return nil
+	}
+	return &millis
+}
+
+// int64PtrEqual compares two int64 pointers for equality.
+func int64PtrEqual(a, b *int64) bool {
+	if a == nil && b == nil {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return *a == *b
+}
+
+// ptrBool returns a pointer to a bool value.
+func ptrBool(v bool) *bool {
+	return &v
+}
@@ -0,0 +1,72 @@
+package clickup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+)
+
+// createsSpaceTags reports whether sync registers tags it adds to tasks
+// as space tags, per create_space_tags (default true).
+func (s *Sink) createsSpaceTags() bool {
+	return s.spaceID != "" && (s.config == nil || s.config.CreateSpaceTags == nil || *s.config.CreateSpaceTags)
+}
+
+// ensureSpaceTag creates tag as a space tag, if create_space_tags allows,
+// so it shows in the tag picker, colored per tag_colors. A failure is
+// remembered for SpaceTagErrors rather than failing the tag, which is
+// still added to the task; once the token is refused, no more are tried.
+func (s *Sink) ensureSpaceTag(ctx context.Context, tag string) {
+	if !s.createsSpaceTags() {
+		return
+	}
+	s.spaceTagsMu.Lock()
+	denied := s.spaceTagsDenied
+	s.spaceTagsMu.Unlock()
+	if denied {
+		return
+	}
+
+	err := s.client.EnsureSpaceTag(ctx, s.spaceID, s.spaceTag(tag))
+	if err == nil || ctx.Err() != nil {
+		return
+	}
+	s.spaceTagsMu.Lock()
+	defer s.spaceTagsMu.Unlock()
+	if s.spaceTagErrs == nil {
+		s.spaceTagErrs = make(map[string]error)
+	}
+	s.spaceTagErrs[tag] = err
+	if isPermissionError(err) {
+		s.spaceTagsDenied = true
+	}
+}
+
+// SpaceTagErrors returns why space tags couldn't be created during the
+// sync, one error per tag in tag order. Tasks still got the tags.
+func (s *Sink) SpaceTagErrors() []error {
+	s.spaceTagsMu.Lock()
+	defer s.spaceTagsMu.Unlock()
+	var errs []error
+	for _, tag := range slices.Sorted(maps.Keys(s.spaceTagErrs)) {
+		err := s.spaceTagErrs[tag]
+		if isPermissionError(err) {
+			err = fmt.Errorf("%w; the token may not be allowed to create tags (set create_space_tags: false to stop trying)", err)
+		}
+		errs = append(errs, fmt.Errorf("creating space tag %q: %w", tag, err))
+	}
+	return errs
+}
+
+// isPermissionError reports whether err means the token isn't allowed to
+// make the request.
+func isPermissionError(err error) bool {
+	if errors.Is(err, ErrUnauthorized) {
+		return true
+	}
+	apiErr, ok := errors.AsType[*APIError](err)
+	return ok && apiErr.StatusCode == http.StatusForbidden
+}
@@ -0,0 +1,61 @@
+package clickup
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/toba/bean-me-up/internal/beans"
+)
+
+// sprintList returns the sprint list the bean's task belongs in per
+// sprint_lists: that of its sprint extension value, e.g.
+// extensions.clickup.sprint: 2026-07, or else of its first tag with one.
+func (s *Sink) sprintList(b *beans.Bean) string {
+	if sprint := b.GetExtensionString(beans.PluginClickUp, beans.ExtKeySprint); sprint != "" {
+		if listID, ok := s.config.SprintLists[sprint]; ok {
+			return listID
+		}
+	}
+	for _, tag := range b.Tags {
+		if listID, ok := s.config.SprintLists[tag]; ok {
+			return listID
+		}
+	}
+	return ""
+}
+
+// syncSprint adds the task to its bean's sprint list and takes it out of
+// the other configured sprint lists, reporting whether it changed either.
+// Lists that aren't in sprint_lists are left alone.
+func (s *Sink) syncSprint(ctx context.Context, task *TaskInfo, b *beans.Bean) (bool, error) {
+	if len(s.config.SprintLists) == 0 {
+		return false, nil
+	}
+	want := s.sprintList(b)
+	changed, in := false, false
+	for _, l := range task.Locations {
+		if l.ID == want {
+			in = true
+			continue
+		}
+		if !s.isSprintList(l.ID) {
+			continue
+		}
+		if err := s.client.RemoveTaskFromList(ctx, l.ID, task.ID); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+	if want == "" || in || want == task.List.ID {
+		return changed, nil
+	}
+	if err := s.client.AddTaskToList(ctx, want, task.ID); err != nil {
+		return changed, err
+	}
+	return true, nil
+}
+
+func (s *Sink) isSprintList(listID string) bool {
+	return slices.Contains(slices.Collect(maps.Values(s.config.SprintLists)), listID)
+}
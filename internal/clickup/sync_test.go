@@ -3,19 +3,24 @@ package clickup
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/toba/bean-me-up/internal/beans"
 	"github.com/toba/bean-me-up/internal/config"
+	"github.com/toba/bean-me-up/internal/syncer"
 )
 
-// memorySyncProvider is a simple in-memory SyncStateProvider for tests.
+// memorySyncProvider is a simple in-memory syncer.StateProvider for tests.
 type memorySyncProvider struct {
 	mu       sync.RWMutex
 	taskIDs  map[string]string
@@ -67,23 +72,39 @@ func (m *memorySyncProvider) Clear(beanID string) {
 
 func (m *memorySyncProvider) Flush() error { return nil }
 
-func newTestSyncer(t *testing.T, client *Client) *Syncer {
+// testSyncer drives a ClickUp Sink through the generic Syncer.
+type testSyncer struct {
+	*Sink
+	opts      syncer.Options
+	syncStore *memorySyncProvider
+}
+
+func newTestSyncer(t *testing.T, client *Client) *testSyncer {
 	t.Helper()
-	return &Syncer{
-		client:       client,
-		config:       &config.ClickUpConfig{},
-		opts:         SyncOptions{ListID: "test-list"},
-		syncStore:    newMemorySyncProvider(),
-		beanToTaskID: make(map[string]string),
+	return &testSyncer{
+		Sink:      NewSink(client, &config.ClickUpConfig{}, "test-list"),
+		syncStore: newMemorySyncProvider(),
 	}
 }
 
+// syncBean runs a full sync of a single bean.
+func (ts *testSyncer) syncBean(ctx context.Context, b *beans.Bean) syncer.Result {
+	results, err := syncer.New(ts.Sink, ts.opts, ts.syncStore).SyncBeans(ctx, []beans.Bean{*b})
+	if err != nil {
+		return syncer.Result{BeanID: b.ID, Action: "error", Error: err}
+	}
+	return results[0]
+}
+
 func TestSyncTags_SetDiff(t *testing.T) {
 	var calls []string
+	var mu sync.Mutex
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		parts := strings.Split(r.URL.Path, "/tag/")
 		if len(parts) == 2 {
+			mu.Lock()
 			calls = append(calls, r.Method+" "+parts[1])
+			mu.Unlock()
 		}
 		w.WriteHeader(200)
 		_, _ = w.Write([]byte("{}"))
@@ -95,6 +116,7 @@ func TestSyncTags_SetDiff(t *testing.T) {
 		httpClient: &http.Client{
 			Transport: &redirectTransport{target: server.URL},
 		},
+		baseURL: DefaultBaseURL,
 	}
 
 	syncer := newTestSyncer(t, client)
@@ -182,6 +204,62 @@ func TestSyncTags_SetDiff(t *testing.T) {
 	}
 }
 
+func TestSyncTags_PrefixAndProtected(t *testing.T) {
+	var calls []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The escaped path shows the tag name arrived as one segment
+		if parts := strings.Split(r.URL.EscapedPath(), "/tag/"); len(parts) == 2 {
+			mu.Lock()
+			calls = append(calls, r.Method+" "+parts[1])
+			mu.Unlock()
+		}
+		w.WriteHeader(200)
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token: "test",
+		httpClient: &http.Client{
+			Transport: &redirectTransport{target: server.URL},
+		},
+		baseURL: DefaultBaseURL,
+	}
+
+	tests := []struct {
+		name      string
+		cfg       config.ClickUpConfig
+		wantCalls []string
+	}{
+		{
+			name:      "prefix leaves unprefixed tags alone",
+			cfg:       config.ClickUpConfig{TagPrefix: "bean/"},
+			wantCalls: []string{"DELETE bean%2Fold", "POST bean%2Fnew"},
+		},
+		{
+			name:      "protected tags are never removed",
+			cfg:       config.ClickUpConfig{ProtectedTags: []string{"manual"}},
+			wantCalls: []string{"DELETE bean%2Fkeep", "DELETE bean%2Fold", "POST keep", "POST new"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			sink := NewSink(client, &tt.cfg, "test-list")
+			b := &beans.Bean{ID: "bean-1", Tags: []string{"keep", "new"}}
+			current := []Tag{{Name: "bean/keep"}, {Name: "bean/old"}, {Name: "manual"}}
+
+			sink.syncTags(context.Background(), "task-1", b, current)
+
+			sort.Strings(calls)
+			if !slicesEqual(calls, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
 func TestSyncBean_CreateWithTags(t *testing.T) {
 	var tagCalls []string
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -218,6 +296,7 @@ func TestSyncBean_CreateWithTags(t *testing.T) {
 		httpClient: &http.Client{
 			Transport: &redirectTransport{target: server.URL},
 		},
+		baseURL: DefaultBaseURL,
 	}
 
 	syncer := newTestSyncer(t, client)
@@ -280,17 +359,12 @@ func TestSyncBean_UpdateWithTagChanges(t *testing.T) {
 		httpClient: &http.Client{
 			Transport: &redirectTransport{target: server.URL},
 		},
+		baseURL: DefaultBaseURL,
 	}
 
-	store := newMemorySyncProvider()
-	store.SetTaskID("bean-1", "task-123")
-	syncer := &Syncer{
-		client:       client,
-		config:       &config.ClickUpConfig{},
-		opts:         SyncOptions{ListID: "test-list", Force: true},
-		syncStore:    store,
-		beanToTaskID: make(map[string]string),
-	}
+	syncer := newTestSyncer(t, client)
+	syncer.opts.Force = true
+	syncer.syncStore.SetTaskID("bean-1", "task-123")
 
 	now := time.Now()
 	b := &beans.Bean{
@@ -331,11 +405,14 @@ func (rt *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error
 
 func TestSyncTags_EnsureSpaceTagBeforeAdd(t *testing.T) {
 	var calls []string
+	var mu sync.Mutex
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		path := r.URL.Path
 		// Space tag creation: POST /api/v2/space/{id}/tag (path ends with /tag, not /tag/{name})
 		if r.Method == "POST" && strings.Contains(path, "/space/") && strings.Contains(path, "/tag") && !strings.Contains(path, "/task/") {
+			mu.Lock()
 			calls = append(calls, "space-create")
+			mu.Unlock()
 			w.WriteHeader(200)
 			_, _ = w.Write([]byte("{}"))
 			return
@@ -343,7 +420,9 @@ func TestSyncTags_EnsureSpaceTagBeforeAdd(t *testing.T) {
 		// Task tag addition: POST /api/v2/task/{id}/tag/{tagName}
 		if r.Method == "POST" && strings.Contains(path, "/task/") && strings.Contains(path, "/tag/") {
 			parts := strings.Split(path, "/tag/")
+			mu.Lock()
 			calls = append(calls, "task-add:"+parts[len(parts)-1])
+			mu.Unlock()
 			w.WriteHeader(200)
 			_, _ = w.Write([]byte("{}"))
 			return
@@ -358,6 +437,7 @@ func TestSyncTags_EnsureSpaceTagBeforeAdd(t *testing.T) {
 		httpClient: &http.Client{
 			Transport: &redirectTransport{target: server.URL},
 		},
+		baseURL:   DefaultBaseURL,
 		spaceTags: make(map[string]bool), // empty cache
 	}
 
@@ -394,6 +474,7 @@ func TestSyncTags_CachePreventsRedundantSpaceTagCreation(t *testing.T) {
 		httpClient: &http.Client{
 			Transport: &redirectTransport{target: server.URL},
 		},
+		baseURL:   DefaultBaseURL,
 		spaceTags: map[string]bool{"cached-tag": true}, // pre-cached tag
 	}
 
@@ -434,6 +515,325 @@ func TestSyncTags_CachePreventsRedundantSpaceTagCreation(t *testing.T) {
 	}
 }
 
+func TestSyncTags_CreateSpaceTags(t *testing.T) {
+	var spaceCreates, taskAdds atomic.Int32
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/tag"):
+			spaceCreates.Add(1)
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"err": "You do not have permission", "ECODE": "ACCESS_083"}`))
+		case r.Method == "POST" && strings.Contains(r.URL.Path, "/tag/"):
+			taskAdds.Add(1)
+			_, _ = w.Write([]byte("{}"))
+		}
+	}))
+	defer server.Close()
+	client := NewClient("test", WithBaseURL(server.URL))
+
+	// Disabled: tags go on the task only
+	ts := newTestSyncer(t, client)
+	ts.spaceID = "space-1"
+	ts.config.CreateSpaceTags = ptrBool(false)
+	if !ts.syncTags(context.Background(), "task-1", &beans.Bean{Tags: []string{"a", "b"}}, nil) {
+		t.Error("tags weren't added")
+	}
+	if spaceCreates.Load() != 0 || taskAdds.Load() != 2 {
+		t.Errorf("space creates = %d, task adds = %d, want 0 and 2", spaceCreates.Load(), taskAdds.Load())
+	}
+
+	// Refused: reported once, not retried, and the task still gets its tags
+	status = http.StatusForbidden
+	ts = newTestSyncer(t, client)
+	ts.spaceID = "space-1"
+	ts.syncTags(context.Background(), "task-1", &beans.Bean{Tags: []string{"a"}}, nil)
+	ts.syncTags(context.Background(), "task-2", &beans.Bean{Tags: []string{"b"}}, nil)
+	if spaceCreates.Load() != 1 || taskAdds.Load() != 4 {
+		t.Errorf("space creates = %d, task adds = %d, want 1 and 4", spaceCreates.Load(), taskAdds.Load())
+	}
+	errs := ts.SpaceTagErrors()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), `creating space tag "a"`) || !strings.Contains(errs[0].Error(), "create_space_tags: false") {
+		t.Errorf("SpaceTagErrors() = %v", errs)
+	}
+}
+
+func TestSyncTags_CacheIgnoresCase(t *testing.T) {
+	var spaceCreateCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && strings.Contains(r.URL.Path, "/space/") && strings.HasSuffix(r.URL.Path, "/tag") {
+			spaceCreateCalls.Add(1)
+		}
+		w.WriteHeader(200)
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token: "test",
+		httpClient: &http.Client{
+			Transport: &redirectTransport{target: server.URL},
+		},
+		baseURL:   DefaultBaseURL,
+		spaceTags: map[string]bool{"frontend": true},
+	}
+
+	syncer := newTestSyncer(t, client)
+	syncer.spaceID = "space-1"
+
+	b := &beans.Bean{ID: "bean-1", Tags: []string{"Frontend"}}
+	syncer.syncTags(context.Background(), "task-1", b, nil)
+
+	if n := spaceCreateCalls.Load(); n != 0 {
+		t.Errorf("expected no space tag creation for tag cached in another case, got %d", n)
+	}
+}
+
+func TestEnsureSpaceTag_StaleCache(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	createStatus, createBody := 400, `{"err":"Tag already exists","ECODE":"TAG_005"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, r.Method)
+		if r.Method == "GET" {
+			_, _ = w.Write([]byte(`{"tags":[{"name":"teammate-tag"}]}`))
+			return
+		}
+		w.WriteHeader(createStatus)
+		_, _ = w.Write([]byte(createBody))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token: "test",
+		httpClient: &http.Client{
+			Transport: &redirectTransport{target: server.URL},
+		},
+		baseURL:     DefaultBaseURL,
+		spaceTags:   make(map[string]bool),
+		retryConfig: &RetryConfig{MaxRetries: 0},
+	}
+	ctx := context.Background()
+
+	// Created by a teammate since the cache was filled
+	if err := client.EnsureSpaceTag(ctx, "space-1", Tag{Name: "added-meanwhile"}); err != nil {
+		t.Fatalf("expected an existing tag to count as ensured, got %v", err)
+	}
+	if !client.HasSpaceTag("added-meanwhile") {
+		t.Error("expected an existing tag to be cached")
+	}
+
+	// Any other 4xx has the next call refetch the space's tags
+	createStatus, createBody = 403, `{"err":"Team not authorized","ECODE":"OAUTH_027"}`
+	if err := client.EnsureSpaceTag(ctx, "space-1", Tag{Name: "forbidden"}); err == nil {
+		t.Fatal("expected an error for a rejected create")
+	}
+	if err := client.EnsureSpaceTag(ctx, "space-1", Tag{Name: "teammate-tag"}); err != nil {
+		t.Fatalf("expected a refetched tag to count as ensured, got %v", err)
+	}
+
+	want := []string{"POST", "POST", "GET"}
+	if !slicesEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestMutateConcurrently(t *testing.T) {
+	var running, peak atomic.Int32
+	mutation := func(err error) func() error {
+		return func() error {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+			return err
+		}
+	}
+
+	var mutations []func() error
+	for range 10 {
+		mutations = append(mutations, mutation(errors.New("failed")))
+	}
+	if mutateConcurrently(mutations) {
+		t.Error("expected false when every mutation fails")
+	}
+	if p := peak.Load(); p < 2 || p > taskMutationConcurrency {
+		t.Errorf("peak concurrency = %d, want 2..%d", p, taskMutationConcurrency)
+	}
+
+	mutations = append(mutations, mutation(nil))
+	if !mutateConcurrently(mutations) {
+		t.Error("expected true when a mutation succeeds")
+	}
+	if mutateConcurrently(nil) {
+		t.Error("expected false with no mutations")
+	}
+}
+
+func TestGetAssignees(t *testing.T) {
+	intp := func(n int) *int { return &n }
+	client := &Client{authorizedUser: &AuthorizedUser{ID: 1}} // token owner, cached
+	users := map[string]int{"ana": 7}
+	withAssignee := func(v any) *beans.Bean {
+		return &beans.Bean{ID: "bean-1", Extensions: map[string]map[string]any{beans.PluginClickUp: {beans.ExtKeyAssignee: v}}}
+	}
+
+	tests := []struct {
+		name string
+		cfg  config.ClickUpConfig
+		bean *beans.Bean
+		want []int
+	}{
+		{"default token owner", config.ClickUpConfig{}, &beans.Bean{}, []int{1}},
+		{"legacy assignee", config.ClickUpConfig{Assignee: intp(5)}, &beans.Bean{}, []int{5}},
+		{"legacy unassigned", config.ClickUpConfig{Assignee: intp(0)}, &beans.Bean{}, nil},
+		{"none", config.ClickUpConfig{AssigneeStrategy: AssigneeNone, Assignee: intp(5)}, &beans.Bean{}, nil},
+		{"fixed", config.ClickUpConfig{AssigneeStrategy: AssigneeFixed, Assignee: intp(5)}, &beans.Bean{}, []int{5}},
+		{"token owner despite assignee", config.ClickUpConfig{AssigneeStrategy: AssigneeTokenOwner, Assignee: intp(5)}, &beans.Bean{}, []int{1}},
+		{"bean short name", config.ClickUpConfig{AssigneeStrategy: AssigneeBean, Users: users}, withAssignee("@ana"), []int{7}},
+		{"bean user ID", config.ClickUpConfig{AssigneeStrategy: AssigneeBean}, withAssignee(float64(9)), []int{9}},
+		{"bean without one", config.ClickUpConfig{AssigneeStrategy: AssigneeBean}, &beans.Bean{}, nil},
+		{"bean falls back", config.ClickUpConfig{AssigneeStrategy: AssigneeBean, Assignee: intp(5)}, withAssignee("bo"), []int{5}},
+	}
+	for _, tt := range tests {
+		sink := NewSink(client, &tt.cfg, "test-list")
+		if got := sink.getAssignees(context.Background(), tt.bean); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: getAssignees() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewSinkFromConfig_AssigneeStrategy(t *testing.T) {
+	for _, tt := range []struct {
+		cfg  config.ClickUpConfig
+		want string
+	}{
+		{config.ClickUpConfig{ListID: "1", AssigneeStrategy: "owner"}, "unknown assignee_strategy"},
+		{config.ClickUpConfig{ListID: "1", AssigneeStrategy: AssigneeFixed}, "needs an assignee"},
+	} {
+		_, err := newSinkFromConfig(&config.Config{Beans: config.BeansWrapper{ClickUp: tt.cfg}})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("assignee_strategy %q: error %v, want %q", tt.cfg.AssigneeStrategy, err, tt.want)
+		}
+	}
+}
+
+func TestFieldOwnership(t *testing.T) {
+	for _, tt := range []struct {
+		ownership map[string]string
+		want      string
+	}{
+		{map[string]string{"body": OwnerBeans}, "unknown field_ownership field"},
+		{map[string]string{"status": "task"}, "unknown field_ownership owner"},
+	} {
+		_, err := newSinkFromConfig(&config.Config{Beans: config.BeansWrapper{ClickUp: config.ClickUpConfig{ListID: "1", FieldOwnership: tt.ownership}}})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("field_ownership %v: error %v, want %q", tt.ownership, err, tt.want)
+		}
+	}
+
+	sink := NewSink(nil, &config.ClickUpConfig{FieldOwnership: map[string]string{
+		"status": OwnerClickUp, "due": OwnerClickUp, "tags": OwnerClickUp, "title": OwnerBeans,
+	}}, "list")
+	due := "2026-03-01"
+	b := &beans.Bean{ID: "bean-1", Title: "New", Status: "completed", Due: &due, Tags: []string{"api"}}
+	task := &TaskInfo{Name: "Old", Status: Status{Status: "in progress"}}
+
+	// Sync leaves the fields ClickUp owns alone and pushes the rest
+	update := sink.buildUpdateRequest(task, b, "Details", nil, sink.getClickUpStatus(b.Status))
+	if got := update.changedFields(); !slices.Equal(got, []string{"name", "description"}) {
+		t.Errorf("changed fields = %v, want name and description", got)
+	}
+	if taskTags, beanTags := sink.TagFingerprints(&syncer.TaskRef{Tags: []string{"web"}}, b); taskTags != beanTags {
+		t.Error("task tags owned by ClickUp count as drift")
+	}
+	if owner := sink.FieldOwner("priority"); owner != "" {
+		t.Errorf("unset owner = %q", owner)
+	}
+}
+
+func TestBeanStatus(t *testing.T) {
+	sink := NewSink(nil, &config.ClickUpConfig{StatusMapping: map[string]string{"review": "in progress"}}, "list")
+	for _, tt := range []struct {
+		status string
+		want   string
+		ok     bool
+	}{
+		{"complete", "completed", true},
+		{"In Progress", "in-progress", true}, // sorts before review
+		{"blocked", "", false},
+	} {
+		if got, ok := sink.BeanStatus(tt.status); got != tt.want || ok != tt.ok {
+			t.Errorf("BeanStatus(%q) = %q, %v, want %q, %v", tt.status, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRequestReview(t *testing.T) {
+	var mu sync.Mutex
+	var comments []CreateCommentRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/comment") {
+			var req CreateCommentRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			mu.Lock()
+			comments = append(comments, req)
+			mu.Unlock()
+		}
+		_, _ = w.Write([]byte(`{"id":"c1"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test", WithBaseURL(server.URL))
+	sink := NewSink(client, &config.ClickUpConfig{
+		Users:         map[string]int{"alice": 42, "bo": 7},
+		ReviewRequest: &config.ReviewRequestConfig{Status: "in-review", Message: "please review, cc @bo"},
+	}, "test-list")
+	review := func(status, reviewer string) *beans.Bean {
+		return &beans.Bean{ID: "bean-1", Status: status, Extensions: map[string]map[string]any{
+			beans.PluginClickUp: {beans.ExtKeyReview: reviewer},
+		}}
+	}
+
+	ctx := context.Background()
+	sink.requestReview(ctx, "task-1", review("todo", "alice"))       // other status
+	sink.requestReview(ctx, "task-1", review("in-review", "nobody")) // unknown reviewer
+	sink.requestReview(ctx, "task-1", &beans.Bean{Status: "in-review"})
+	if len(comments) != 0 {
+		t.Fatalf("expected no review requests, got %+v", comments)
+	}
+
+	sink.requestReview(ctx, "task-1", review("in-review", "alice"))
+	if len(comments) != 1 {
+		t.Fatalf("expected 1 review request, got %d", len(comments))
+	}
+	got := comments[0]
+	if got.Assignee == nil || *got.Assignee != 42 {
+		t.Errorf("assignee = %v, want 42", got.Assignee)
+	}
+	want := []CommentPart{
+		{Type: "tag", User: &CommentUser{ID: 42}},
+		{Text: " please review, cc "},
+		{Type: "tag", User: &CommentUser{ID: 7}},
+	}
+	if len(got.Comment) != len(want) {
+		t.Fatalf("comment = %+v, want %+v", got.Comment, want)
+	}
+	for i := range want {
+		if got.Comment[i].Text != want[i].Text || got.Comment[i].Type != want[i].Type ||
+			(want[i].User != nil && (got.Comment[i].User == nil || got.Comment[i].User.ID != want[i].User.ID)) {
+			t.Errorf("comment part %d = %+v, want %+v", i, got.Comment[i], want[i])
+		}
+	}
+}
+
 func TestSyncBean_CreateWithDueDate(t *testing.T) {
 	var capturedReq CreateTaskRequest
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -464,6 +864,7 @@ func TestSyncBean_CreateWithDueDate(t *testing.T) {
 		httpClient: &http.Client{
 			Transport: &redirectTransport{target: server.URL},
 		},
+		baseURL: DefaultBaseURL,
 	}
 
 	syncer := newTestSyncer(t, client)
@@ -551,17 +952,12 @@ func TestSyncBean_UpdateDueDate(t *testing.T) {
 		httpClient: &http.Client{
 			Transport: &redirectTransport{target: server.URL},
 		},
+		baseURL: DefaultBaseURL,
 	}
 
-	store := newMemorySyncProvider()
-	store.SetTaskID("bean-1", "task-789")
-	syncer := &Syncer{
-		client:       client,
-		config:       &config.ClickUpConfig{},
-		opts:         SyncOptions{ListID: "test-list", Force: true},
-		syncStore:    store,
-		beanToTaskID: make(map[string]string),
-	}
+	syncer := newTestSyncer(t, client)
+	syncer.opts.Force = true
+	syncer.syncStore.SetTaskID("bean-1", "task-789")
 
 	due := "2025-12-25"
 	now := time.Now()
@@ -595,3 +991,123 @@ func slicesEqual(a, b []string) bool {
 	}
 	return true
 }
+
+func TestSyncBean_RecreatesDeletedTask(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && strings.Contains(r.URL.Path, "/task/") {
+			w.WriteHeader(404)
+			_, _ = w.Write([]byte(`{"err":"Task not found, deleted","ECODE":"ITEM_013"}`))
+			return
+		}
+		if r.Method == "POST" && strings.Contains(r.URL.Path, "/list/") {
+			resp := taskResponse{ID: "task-new", Name: "Test bean", URL: "https://app.clickup.com/t/task-new"}
+			_ = json.NewEncoder(w).Encode(resp)
+			return
+		}
+		w.WriteHeader(200)
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token: "test",
+		httpClient: &http.Client{
+			Transport: &redirectTransport{target: server.URL},
+		},
+		baseURL: DefaultBaseURL,
+	}
+
+	syncer := newTestSyncer(t, client)
+	syncer.opts.Force = true
+	syncer.syncStore.SetTaskID("bean-1", "task-gone")
+
+	now := time.Now()
+	b := &beans.Bean{ID: "bean-1", Title: "Test bean", Status: "todo", Type: "task", CreatedAt: &now, UpdatedAt: &now}
+
+	result := syncer.syncBean(context.Background(), b)
+	if result.Action != "created" || result.TaskID != "task-new" {
+		t.Fatalf("expected deleted task to be recreated, got action %q task %q (err %v)", result.Action, result.TaskID, result.Error)
+	}
+}
+
+func TestAPIError_TagExists(t *testing.T) {
+	exists := &APIError{StatusCode: 400, Code: "TAG_005", Message: "Tag already exists"}
+	if !errors.Is(fmt.Errorf("creating space tag: %w", exists), ErrTagExists) {
+		t.Errorf("expected %v to be ErrTagExists", exists)
+	}
+	if other := (&APIError{StatusCode: 400, Code: "INPUT_005", Message: "Tag name invalid"}); errors.Is(other, ErrTagExists) {
+		t.Errorf("expected %v not to be ErrTagExists", other)
+	}
+}
+
+func TestGetTask_NotFoundForms(t *testing.T) {
+	for _, tt := range []struct {
+		body     string
+		notFound bool
+	}{
+		{`{"err":"Task not found, deleted","ECODE":"ITEM_013"}`, true},
+		{`{"err":"Task not found"}`, true},
+		// A proxy's page or a wrong base URL isn't a deleted task
+		{`404 page not found`, false},
+		{`Task not found`, false},
+		{`{"err":"Route not found","ECODE":"APP_001"}`, false},
+	} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(tt.body))
+		}))
+		_, err := NewClient("test", WithBaseURL(server.URL)).GetTask(context.Background(), "gone")
+		server.Close()
+		if err == nil || errors.Is(err, ErrTaskNotFound) != tt.notFound {
+			t.Errorf("404 %s: error = %v, want ErrTaskNotFound %v", tt.body, err, tt.notFound)
+		}
+	}
+}
+
+func TestSyncBean_KeepsLinkOnPlain404(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("404 page not found"))
+	}))
+	defer server.Close()
+
+	syncer := newTestSyncer(t, NewClient("test", WithBaseURL(server.URL)))
+	syncer.opts.Force = true
+	syncer.syncStore.SetTaskID("bean-1", "task-1")
+
+	now := time.Now()
+	b := &beans.Bean{ID: "bean-1", Title: "Test bean", Status: "todo", CreatedAt: &now, UpdatedAt: &now}
+	if result := syncer.syncBean(context.Background(), b); result.Action != "error" {
+		t.Errorf("action = %q, want error", result.Action)
+	}
+	if id := syncer.syncStore.GetTaskID("bean-1"); id == nil || *id != "task-1" {
+		t.Errorf("task ID = %v, want the link kept", id)
+	}
+}
+
+func TestAPIError_Kinds(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		kind error
+	}{
+		{"task not found", &APIError{StatusCode: 404, Code: "ITEM_013"}, ErrTaskNotFound},
+		{"plain 404", &APIError{StatusCode: 404, Message: "404 page not found"}, nil},
+		{"other 404 code", &APIError{StatusCode: 404, Code: "SUBCAT_016", Message: "List not found"}, nil},
+		{"unauthorized status", &APIError{StatusCode: 401, Code: "OAUTH_019"}, ErrUnauthorized},
+		{"oauth code", &APIError{StatusCode: 400, Code: "OAUTH_027"}, ErrUnauthorized},
+		{"validation", &APIError{StatusCode: 400, Code: "INPUT_005"}, ErrValidation},
+		{"rate limit after retries", fmt.Errorf("max retries exceeded: %w", &RateLimitError{Code: "APP_002"}), ErrRateLimited},
+	}
+	kinds := []error{ErrTaskNotFound, ErrUnauthorized, ErrValidation, ErrRateLimited}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapped := fmt.Errorf("getting task: %w", tt.err)
+			for _, k := range kinds {
+				if got := errors.Is(wrapped, k); got != (k == tt.kind) {
+					t.Errorf("errors.Is(%v, %v) = %v", wrapped, k, got)
+				}
+			}
+		})
+	}
+}
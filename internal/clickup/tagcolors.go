@@ -0,0 +1,75 @@
+package clickup
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/toba/bean-me-up/internal/config"
+)
+
+// hexColorRe matches the #rrggbb colors tag_colors takes.
+var hexColorRe = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// validateTagColors checks that tag_colors has valid patterns and colors.
+func validateTagColors(colors map[string]config.TagColor) error {
+	for _, pattern := range slices.Sorted(maps.Keys(colors)) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("tag_colors pattern %q: %w", pattern, err)
+		}
+		c := colors[pattern]
+		if !hexColorRe.MatchString(c.Bg) {
+			return fmt.Errorf("tag_colors %q: bg %q is not a #rrggbb color", pattern, c.Bg)
+		}
+		if c.Fg != "" && !hexColorRe.MatchString(c.Fg) {
+			return fmt.Errorf("tag_colors %q: fg %q is not a #rrggbb color", pattern, c.Fg)
+		}
+	}
+	return nil
+}
+
+// spaceTag returns the space tag to create for a task tag, colored by the
+// tag_colors pattern it matches. The longest matching pattern wins, as
+// the most specific; without one ClickUp picks the colors.
+func (s *Sink) spaceTag(name string) Tag {
+	tag := Tag{Name: name}
+	if s.config == nil {
+		return tag
+	}
+	var best string
+	for _, pattern := range slices.Sorted(maps.Keys(s.config.TagColors)) {
+		ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(name))
+		if ok && (best == "" || len(pattern) > len(best)) {
+			best = pattern
+		}
+	}
+	if best == "" {
+		return tag
+	}
+	c := s.config.TagColors[best]
+	tag.Bg = c.Bg
+	tag.Fg = c.Fg
+	if tag.Fg == "" {
+		tag.Fg = contrastColor(c.Bg)
+	}
+	return tag
+}
+
+// contrastColor returns black or white, whichever reads better on the
+// #rrggbb color bg.
+func contrastColor(bg string) string {
+	rgb, err := strconv.ParseUint(strings.TrimPrefix(bg, "#"), 16, 32)
+	if err != nil {
+		return "#ffffff"
+	}
+	r, g, b := float64(rgb>>16&0xff), float64(rgb>>8&0xff), float64(rgb&0xff)
+	// Perceived brightness, per ITU-R BT.601
+	if 0.299*r+0.587*g+0.114*b > 150 {
+		return "#000000"
+	}
+	return "#ffffff"
+}
@@ -0,0 +1,77 @@
+package clickup
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+)
+
+func TestSpaceTag_Colors(t *testing.T) {
+	sink := NewSink(nil, &config.ClickUpConfig{TagColors: map[string]config.TagColor{
+		"area/*":     {Bg: "#1e90ff"},
+		"area/infra": {Bg: "#ffd700", Fg: "#333333"},
+		"prio/*":     {Bg: "#d32f2f"},
+	}}, "list")
+
+	for _, tt := range []struct {
+		name string
+		want Tag
+	}{
+		{"area/api", Tag{Name: "area/api", Bg: "#1e90ff", Fg: "#ffffff"}},
+		{"Area/Infra", Tag{Name: "Area/Infra", Bg: "#ffd700", Fg: "#333333"}}, // most specific, in any case
+		{"prio/high", Tag{Name: "prio/high", Bg: "#d32f2f", Fg: "#ffffff"}},
+		{"area/api/v2", Tag{Name: "area/api/v2"}}, // * stops at /
+		{"docs", Tag{Name: "docs"}},
+	} {
+		if got := sink.spaceTag(tt.name); got != tt.want {
+			t.Errorf("spaceTag(%q) = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+	if got := contrastColor("#ffd700"); got != "#000000" {
+		t.Errorf("contrastColor(gold) = %s, want black", got)
+	}
+}
+
+func TestSyncTags_CreatesColoredSpaceTag(t *testing.T) {
+	var created Tag
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/space/space-1/tag") {
+			var body struct{ Tag Tag }
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			created = body.Tag
+		}
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	cfg := &config.ClickUpConfig{TagColors: map[string]config.TagColor{"area/*": {Bg: "#1e90ff", Fg: "#fafafa"}}}
+	sink := NewSink(NewClient("test", WithBaseURL(server.URL)), cfg, "list")
+	sink.spaceID = "space-1"
+	sink.syncTags(context.Background(), "task-1", &beans.Bean{Tags: []string{"area/api"}}, nil)
+
+	if created != (Tag{Name: "area/api", Fg: "#fafafa", Bg: "#1e90ff"}) {
+		t.Errorf("created space tag %+v", created)
+	}
+}
+
+func TestValidateTagColors(t *testing.T) {
+	for colors, want := range map[config.TagColor]string{
+		{Bg: "blue"}:                   `bg "blue" is not a #rrggbb color`,
+		{Bg: "#1e90ff", Fg: "#fff"}:    `fg "#fff" is not a #rrggbb color`,
+		{Bg: "#1e90ff", Fg: "#FFFFFF"}: "",
+	} {
+		err := validateTagColors(map[string]config.TagColor{"area/*": colors})
+		if want == "" && err != nil || want != "" && (err == nil || !strings.Contains(err.Error(), want)) {
+			t.Errorf("%+v: error %v, want %q", colors, err, want)
+		}
+	}
+	if err := validateTagColors(map[string]config.TagColor{"area/[": {Bg: "#000000"}}); err == nil {
+		t.Error("expected an error for a bad pattern")
+	}
+}
@@ -0,0 +1,55 @@
+package clickup
+
+import (
+	"context"
+
+	"github.com/toba/bean-me-up/internal/beans"
+)
+
+// taskTemplate returns the task template for the bean's type per
+// task_templates, or else task_template_id.
+func (s *Sink) taskTemplate(b *beans.Bean) string {
+	if templateID, ok := s.config.TaskTemplates[b.Type]; ok {
+		return templateID
+	}
+	return s.config.TaskTemplateID
+}
+
+// createFromTemplate creates a task from a template, then sets the fields
+// req would have created it with. The template's checklists, description,
+// and custom field values stay where the bean has nothing to put instead.
+func (s *Sink) createFromTemplate(ctx context.Context, listID, templateID string, req *CreateTaskRequest) (*TaskInfo, error) {
+	taskID, err := s.client.CreateTaskFromTemplate(ctx, listID, templateID, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	update := &UpdateTaskRequest{
+		Priority:     req.Priority,
+		DueDate:      req.DueDate,
+		DueDatetime:  req.DueDatetime,
+		CustomItemID: req.CustomItemID,
+	}
+	if req.Status != "" {
+		update.Status = &req.Status
+	}
+	if req.MarkdownDescription != "" {
+		update.MarkdownDescription = &req.MarkdownDescription
+	}
+	if len(req.Assignees) > 0 {
+		update.Assignees = &AssigneesUpdate{Add: req.Assignees}
+	}
+	for _, f := range req.CustomFields {
+		_ = s.client.SetCustomFieldValue(ctx, taskID, f.ID, f.Value) // Best-effort
+	}
+
+	// The task exists now, so a failed update is left for the next sync to
+	// fix rather than losing the task's link
+	if task, err := s.client.UpdateTask(ctx, taskID, update); err == nil {
+		return task, nil
+	}
+	if task, err := s.client.GetTask(ctx, taskID); err == nil {
+		return task, nil
+	}
+	return &TaskInfo{ID: taskID}, nil
+}
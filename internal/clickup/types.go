@@ -1,19 +1,95 @@
 // Package clickup provides ClickUp API integration.
 package clickup
 
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
 // TaskInfo holds task data returned from ClickUp.
 type TaskInfo struct {
-	ID           string             `json:"id"`
-	Name         string             `json:"name"`
-	Description  string             `json:"description"`
-	Status       Status             `json:"status"`
-	URL          string             `json:"url"`
-	Parent       *string            `json:"parent"`         // Parent task ID if subtask
-	Priority     *TaskPriority      `json:"priority"`       // ClickUp priority (nil = no priority)
-	CustomItemID *int               `json:"custom_item_id"` // Custom task type ID
-	CustomFields []TaskCustomField  `json:"custom_fields"`  // Custom field values
-	Tags         []Tag              `json:"tags"`           // Task tags
-	DueDate      *string            `json:"due_date"`       // Due date as Unix ms string
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	Status       Status            `json:"status"`
+	URL          string            `json:"url"`
+	Parent       *string           `json:"parent"`         // Parent task ID if subtask
+	Priority     *TaskPriority     `json:"priority"`       // ClickUp priority (nil = no priority)
+	CustomItemID *int              `json:"custom_item_id"` // Custom task type ID
+	CustomFields []TaskCustomField `json:"custom_fields"`  // Custom field values
+	Tags         []Tag             `json:"tags"`           // Task tags
+	DueDate      *string           `json:"due_date"`       // Due date as Unix ms string
+	DateUpdated  *string           `json:"date_updated"`   // Last change as Unix ms string
+	List         TaskList          `json:"list"`           // The task's home list
+	Locations    []TaskList        `json:"locations"`      // Other lists the task was added to
+	Space        TaskSpace         `json:"space"`          // The space of the home list
+	TeamID       string            `json:"team_id"`        // Workspace ID
+	Dependencies []Dependency      `json:"dependencies"`   // Both directions; nil if not returned
+	Assignees    []TaskUser        `json:"assignees"`      // Users the task is assigned to
+	Attachments  []TaskAttachment  `json:"attachments"`    // Files attached to the task
+	Checklists   []Checklist       `json:"checklists"`     // Checklists and their items
+}
+
+// TaskAttachment is a file attached to a task.
+type TaskAttachment struct {
+	ID    string `json:"id"`
+	Title string `json:"title"` // File name
+	URL   string `json:"url"`
+}
+
+// TaskUser is a user a task refers to, such as an assignee.
+type TaskUser struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// Name returns the user's username, or email if they have none.
+func (u TaskUser) Name() string {
+	if u.Username != "" {
+		return u.Username
+	}
+	return u.Email
+}
+
+// Blocking returns the IDs of the tasks waiting on this one, or nil if the
+// response had no dependencies.
+func (t *TaskInfo) Blocking() []string {
+	if t.Dependencies == nil {
+		return nil
+	}
+	blocking := []string{}
+	for _, d := range t.Dependencies {
+		if d.DependsOn == t.ID && d.TaskID != t.ID {
+			blocking = append(blocking, d.TaskID)
+		}
+	}
+	return blocking
+}
+
+// TaskSpace is the space a task lives in.
+type TaskSpace struct {
+	ID string `json:"id"`
+}
+
+// TaskList is the list a task lives in.
+type TaskList struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// UpdatedAt returns when the task was last changed in ClickUp, or nil if unknown.
+func (t *TaskInfo) UpdatedAt() *time.Time {
+	if t.DateUpdated == nil {
+		return nil
+	}
+	millis, err := strconv.ParseInt(*t.DateUpdated, 10, 64)
+	if err != nil {
+		return nil
+	}
+	updated := time.UnixMilli(millis)
+	return &updated
 }
 
 // TaskPriority represents a ClickUp task priority.
@@ -21,6 +97,18 @@ type TaskPriority struct {
 	ID int `json:"id,string"` // Priority ID as string in JSON, parsed as int
 }
 
+// CustomFieldString returns the value of a task's custom field as a
+// string, or "" if it isn't set or isn't text.
+func (t *TaskInfo) CustomFieldString(fieldID string) string {
+	for _, f := range t.CustomFields {
+		if f.ID == fieldID {
+			s, _ := f.Value.(string)
+			return s
+		}
+	}
+	return ""
+}
+
 // TaskCustomField represents a custom field value on a task.
 type TaskCustomField struct {
 	ID    string `json:"id"`
@@ -31,22 +119,54 @@ type TaskCustomField struct {
 // Tag represents a ClickUp task tag.
 type Tag struct {
 	Name string `json:"name"`
+	// Foreground and background colors, as #rrggbb; set on space tags
+	Fg string `json:"tag_fg,omitempty"`
+	Bg string `json:"tag_bg,omitempty"`
 }
 
 // Status represents a ClickUp task status.
 type Status struct {
 	Status string `json:"status"`
 	Color  string `json:"color,omitempty"`
+	Type   string `json:"type,omitempty"` // open, custom, done, or closed
 }
 
+// statusTypeClosed is the type of a list's closed status.
+const statusTypeClosed = "closed"
+
 // List holds ClickUp list metadata.
 type List struct {
 	ID       string   `json:"id"`
 	Name     string   `json:"name"`
 	SpaceID  string   `json:"-"` // Populated from nested space object in API response
+	FolderID string   `json:"-"` // Empty for lists directly in the space
 	Statuses []Status `json:"statuses"`
 }
 
+// ListFolder is the folder a list is in. Lists directly in a space report
+// a hidden folder.
+type ListFolder struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Hidden bool   `json:"hidden"`
+}
+
+// ListDetails is a list with its description and location, as returned
+// when creating, updating, or fetching a single list.
+type ListDetails struct {
+	ID      string     `json:"id"`
+	Name    string     `json:"name"`
+	Content string     `json:"content"` // Description
+	Folder  ListFolder `json:"folder"`
+	Space   TaskSpace  `json:"space"`
+}
+
+// ListRequest is the request body for creating or updating a list.
+type ListRequest struct {
+	Name    string  `json:"name,omitempty"`
+	Content *string `json:"content,omitempty"`
+}
+
 // CreateTaskRequest is the request body for creating a task.
 type CreateTaskRequest struct {
 	Name                string        `json:"name"`
@@ -54,8 +174,8 @@ type CreateTaskRequest struct {
 	MarkdownDescription string        `json:"markdown_description,omitempty"`
 	Status              string        `json:"status,omitempty"`
 	Priority            *int          `json:"priority,omitempty"`
-	Assignees           []int         `json:"assignees,omitempty"`      // User IDs to assign
-	Parent              *string       `json:"parent,omitempty"`         // Parent task ID for subtasks
+	Assignees           []int         `json:"assignees,omitempty"` // User IDs to assign
+	Parent              *string       `json:"parent,omitempty"`    // Parent task ID for subtasks
 	DueDate             *int64        `json:"due_date,omitempty"`
 	DueDatetime         *bool         `json:"due_date_time,omitempty"`
 	CustomFields        []CustomField `json:"custom_fields,omitempty"`
@@ -70,15 +190,23 @@ type CustomField struct {
 
 // UpdateTaskRequest is the request body for updating a task.
 type UpdateTaskRequest struct {
-	Name                *string `json:"name,omitempty"`
-	Description         *string `json:"description,omitempty"`
-	MarkdownDescription *string `json:"markdown_description,omitempty"`
-	Status              *string `json:"status,omitempty"`
-	Priority            *int    `json:"priority,omitempty"`
-	DueDate             *int64  `json:"due_date,omitempty"`
-	DueDatetime         *bool   `json:"due_date_time,omitempty"`
-	Parent              *string `json:"parent,omitempty"`
-	CustomItemID        *int    `json:"custom_item_id,omitempty"` // Custom task type ID (e.g., Bug, Milestone)
+	Name                *string          `json:"name,omitempty"`
+	Description         *string          `json:"description,omitempty"`
+	MarkdownDescription *string          `json:"markdown_description,omitempty"`
+	Status              *string          `json:"status,omitempty"`
+	Priority            *int             `json:"priority,omitempty"`
+	DueDate             *int64           `json:"due_date,omitempty"`
+	DueDatetime         *bool            `json:"due_date_time,omitempty"`
+	Parent              *string          `json:"parent,omitempty"`
+	CustomItemID        *int             `json:"custom_item_id,omitempty"` // Custom task type ID (e.g., Bug, Milestone)
+	Archived            *bool            `json:"archived,omitempty"`
+	Assignees           *AssigneesUpdate `json:"assignees,omitempty"`
+}
+
+// AssigneesUpdate adds users to or removes them from a task's assignees.
+type AssigneesUpdate struct {
+	Add []int `json:"add,omitempty"`
+	Rem []int `json:"rem,omitempty"`
 }
 
 // hasChanges returns true if any field in the update request is set.
@@ -90,7 +218,55 @@ func (u *UpdateTaskRequest) hasChanges() bool {
 		u.Priority != nil ||
 		u.DueDate != nil ||
 		u.Parent != nil ||
-		u.CustomItemID != nil
+		u.CustomItemID != nil ||
+		u.Archived != nil ||
+		u.Assignees != nil
+}
+
+// changedFields names the task fields the update request sets.
+func (u *UpdateTaskRequest) changedFields() []string {
+	var fields []string
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{
+		{"name", u.Name != nil},
+		{"description", u.Description != nil || u.MarkdownDescription != nil},
+		{"status", u.Status != nil},
+		{"priority", u.Priority != nil},
+		{"due_date", u.DueDate != nil},
+		{"parent", u.Parent != nil},
+		{"type", u.CustomItemID != nil},
+		{"archived", u.Archived != nil},
+		{"assignees", u.Assignees != nil},
+	} {
+		if f.set {
+			fields = append(fields, f.name)
+		}
+	}
+	return fields
+}
+
+// TimeEntry is time tracked on a task. Start and Duration are Unix ms
+// strings.
+type TimeEntry struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Start       string `json:"start"`
+	Duration    string `json:"duration"`
+}
+
+// CreateTimeEntryRequest is the request body for creating a time entry.
+type CreateTimeEntryRequest struct {
+	TaskID      string `json:"tid"`
+	Description string `json:"description,omitempty"`
+	Start       int64  `json:"start"`    // Unix ms
+	Duration    int64  `json:"duration"` // ms
+}
+
+// timeEntriesResponse is the API response for getting time entries.
+type timeEntriesResponse struct {
+	Data []TimeEntry `json:"data"`
 }
 
 // Dependency represents a task dependency in ClickUp.
@@ -121,6 +297,20 @@ type taskResponse struct {
 	CustomFields []TaskCustomField `json:"custom_fields"`
 	Tags         []Tag             `json:"tags"`
 	DueDate      *string           `json:"due_date"`
+	DateUpdated  *string           `json:"date_updated"`
+	List         TaskList          `json:"list"`
+	Locations    []TaskList        `json:"locations"`
+	Space        TaskSpace         `json:"space"`
+	TeamID       string            `json:"team_id"`
+	Dependencies []Dependency      `json:"dependencies"`
+	Attachments  []TaskAttachment  `json:"attachments"`
+	Checklists   []Checklist       `json:"checklists"`
+}
+
+// listTasksResponse is one page of the API response for getting a list's tasks.
+type listTasksResponse struct {
+	Tasks    []taskResponse `json:"tasks"`
+	LastPage bool           `json:"last_page"`
 }
 
 // toTaskInfo converts a taskResponse to a TaskInfo.
@@ -137,6 +327,14 @@ func (r *taskResponse) toTaskInfo() *TaskInfo {
 		CustomFields: r.CustomFields,
 		Tags:         r.Tags,
 		DueDate:      r.DueDate,
+		DateUpdated:  r.DateUpdated,
+		List:         r.List,
+		Locations:    r.Locations,
+		Space:        r.Space,
+		TeamID:       r.TeamID,
+		Dependencies: r.Dependencies,
+		Attachments:  r.Attachments,
+		Checklists:   r.Checklists,
 	}
 }
 
@@ -148,6 +346,7 @@ type listResponse struct {
 	Space    struct {
 		ID string `json:"id"`
 	} `json:"space"`
+	Folder ListFolder `json:"folder"`
 }
 
 // errorResponse represents a ClickUp API error.
@@ -170,17 +369,79 @@ type fieldsResponse struct {
 	Fields []FieldInfo `json:"fields"`
 }
 
+// CreateFieldRequest is the request body for creating a custom field.
+type CreateFieldRequest struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// createFieldResponse is the API response for creating a custom field.
+// The field is returned either at the top level or under "field".
+type createFieldResponse struct {
+	FieldInfo
+	Field *FieldInfo `json:"field,omitempty"`
+}
+
 // teamsResponse is the API response for getting teams.
 type teamsResponse struct {
-	Teams []teamInfo `json:"teams"`
+	Teams []Team `json:"teams"`
 }
 
-// teamInfo represents a team/workspace.
-type teamInfo struct {
+// Team is a ClickUp workspace.
+type Team struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
 }
 
+// Space is a space in a workspace.
+type Space struct {
+	ID       string         `json:"id"`
+	Name     string         `json:"name"`
+	Features *SpaceFeatures `json:"features,omitempty"`
+}
+
+// SpaceFeatures are the ClickApps of a space that sync depends on.
+type SpaceFeatures struct {
+	Priorities struct {
+		Enabled    bool            `json:"enabled"`
+		Priorities []SpacePriority `json:"priorities"`
+	} `json:"priorities"`
+}
+
+// SpacePriority is a task priority available in a space.
+type SpacePriority struct {
+	ID       int    `json:"id,string"`
+	Priority string `json:"priority"` // e.g. "urgent"
+}
+
+// Folder is a folder in a space, with its lists.
+type Folder struct {
+	ID    string        `json:"id"`
+	Name  string        `json:"name"`
+	Lists []ListSummary `json:"lists"`
+}
+
+// ListSummary identifies a list in a folder or space.
+type ListSummary struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// spacesResponse is the API response for getting a team's spaces.
+type spacesResponse struct {
+	Spaces []Space `json:"spaces"`
+}
+
+// foldersResponse is the API response for getting a space's folders.
+type foldersResponse struct {
+	Folders []Folder `json:"folders"`
+}
+
+// listsResponse is the API response for getting the lists in a folder or space.
+type listsResponse struct {
+	Lists []ListSummary `json:"lists"`
+}
+
 // AuthorizedUser represents the authenticated user from the API token.
 type AuthorizedUser struct {
 	ID       int    `json:"id"`
@@ -209,3 +470,38 @@ type CustomItem struct {
 type customItemsResponse struct {
 	CustomItems []CustomItem `json:"custom_items"`
 }
+
+// CreateCommentRequest is the request body for commenting on a task.
+type CreateCommentRequest struct {
+	Comment   []CommentPart `json:"comment"`
+	Assignee  *int          `json:"assignee,omitempty"`
+	NotifyAll bool          `json:"notify_all"`
+}
+
+// CommentPart is a run of comment text or a user mention.
+type CommentPart struct {
+	Text string       `json:"text,omitempty"`
+	Type string       `json:"type,omitempty"` // "tag" for a mention
+	User *CommentUser `json:"user,omitempty"`
+}
+
+// CommentUser identifies a mentioned user.
+type CommentUser struct {
+	ID int `json:"id"`
+}
+
+// commentResponse is the API response for creating a comment.
+type commentResponse struct {
+	ID json.RawMessage `json:"id"` // number or string depending on endpoint version
+}
+
+// TaskComment is a comment on a task.
+type TaskComment struct {
+	ID          string `json:"id"`
+	CommentText string `json:"comment_text"` // Plain text of the comment
+}
+
+// taskCommentsResponse is the API response for getting task comments.
+type taskCommentsResponse struct {
+	Comments []TaskComment `json:"comments"`
+}
@@ -0,0 +1,86 @@
+package clickup
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/beans"
+)
+
+// worklogMarker tags a time entry's description with the hash of the
+// worklog entry it was created from.
+func worklogMarker(hash string) string {
+	return "[bean worklog " + hash + "]"
+}
+
+// syncWorklog creates a time entry on the task for each of the bean's
+// worklog entries it doesn't have yet, matching existing entries by the
+// marker in their description, and reports whether any were created.
+func (s *Sink) syncWorklog(ctx context.Context, taskID, teamID string, b *beans.Bean) (bool, error) {
+	if !s.config.TimeTracking {
+		return false, nil
+	}
+	entries := b.Worklog()
+	if len(entries) == 0 {
+		return false, nil
+	}
+	teamID = cmp.Or(teamID, s.config.TeamID)
+	if teamID == "" {
+		return false, fmt.Errorf("time tracking: workspace unknown; set team_id")
+	}
+
+	// Fetch just the days the worklog covers, with a day's slack for time zones
+	first, last := entries[0].Date, entries[0].Date
+	for _, e := range entries[1:] {
+		first, last = minTime(first, e.Date), maxTime(last, e.Date)
+	}
+	existing, err := s.client.GetTaskTimeEntries(ctx, teamID, taskID, first.AddDate(0, 0, -1), last.AddDate(0, 0, 2))
+	if err != nil {
+		return false, err
+	}
+
+	created := false
+	for _, e := range entries {
+		marker := worklogMarker(e.Hash(b.ID))
+		if hasTimeEntry(existing, marker) {
+			continue
+		}
+		req := &CreateTimeEntryRequest{
+			TaskID:      taskID,
+			Description: strings.TrimSpace(e.Note + " " + marker),
+			Start:       time.Date(e.Date.Year(), e.Date.Month(), e.Date.Day(), 0, 0, 0, 0, time.Local).UnixMilli(),
+			Duration:    e.Duration.Milliseconds(),
+		}
+		if err := s.client.CreateTimeEntry(ctx, teamID, req); err != nil {
+			return created, err
+		}
+		created = true
+	}
+	return created, nil
+}
+
+func hasTimeEntry(entries []TimeEntry, marker string) bool {
+	for _, e := range entries {
+		if strings.Contains(e.Description, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,851 @@
+package clickuptest
+
+import (
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// priorityNames are ClickUp's priority labels by ID.
+var priorityNames = map[int]string{1: "urgent", 2: "high", 3: "normal", 4: "low"}
+
+// taskJSON renders a task the way GET /task/{id} does. The caller holds
+// s.mu.
+func (s *Server) taskJSON(t *Task) map[string]any {
+	tags := make([]map[string]string, len(t.Tags))
+	for i, name := range t.Tags {
+		tags[i] = map[string]string{"name": name}
+	}
+	var fields []map[string]any
+	if l := s.lists[t.ListID]; l != nil {
+		for _, f := range l.fields {
+			field := map[string]any{"id": f.ID, "name": f.Name, "type": f.Type}
+			if v, ok := t.Fields[f.ID]; ok {
+				field["value"] = v
+			}
+			fields = append(fields, field)
+		}
+	}
+	var listName string
+	if l := s.lists[t.ListID]; l != nil {
+		listName = l.name
+	}
+	locations := []map[string]string{}
+	for _, id := range t.OtherLists {
+		if l := s.lists[id]; l != nil {
+			locations = append(locations, map[string]string{"id": id, "name": l.name})
+		}
+	}
+	deps := []map[string]any{}
+	for _, on := range t.DependsOn {
+		deps = append(deps, map[string]any{"task_id": t.ID, "depends_on": on, "type": 1})
+	}
+	for _, id := range s.order {
+		if other := s.tasks[id]; other != nil && slices.Contains(other.DependsOn, t.ID) {
+			deps = append(deps, map[string]any{"task_id": other.ID, "depends_on": t.ID, "type": 1})
+		}
+	}
+
+	assignees := []map[string]any{}
+	for _, id := range t.Assignees {
+		assignees = append(assignees, map[string]any{"id": id, "username": "user" + strconv.Itoa(id)})
+	}
+	attachments := []map[string]string{}
+	for i, name := range t.Attachments {
+		path := t.ID + "/" + strconv.Itoa(i)
+		attachments = append(attachments, map[string]string{"id": path, "title": name, "url": s.URL + "/files/" + path})
+	}
+
+	task := map[string]any{
+		"id":             t.ID,
+		"name":           t.Name,
+		"description":    t.Description,
+		"status":         map[string]string{"status": t.Status},
+		"url":            "https://app.clickup.com/t/" + t.ID,
+		"parent":         nil,
+		"priority":       nil,
+		"custom_item_id": t.CustomItemID,
+		"custom_fields":  fields,
+		"tags":           tags,
+		"due_date":       nil,
+		"date_updated":   strconv.FormatInt(t.Updated.UnixMilli(), 10),
+		"list":           map[string]string{"id": t.ListID, "name": listName},
+		"locations":      locations,
+		"team_id":        TeamID,
+		"space":          map[string]string{"id": SpaceID},
+		"dependencies":   deps,
+		"assignees":      assignees,
+		"attachments":    attachments,
+		"archived":       t.Archived,
+	}
+	if t.Parent != "" {
+		task["parent"] = t.Parent
+	}
+	if t.Priority != nil {
+		task["priority"] = map[string]string{"id": strconv.Itoa(*t.Priority), "priority": priorityNames[*t.Priority]}
+	}
+	if t.DueDate != nil {
+		task["due_date"] = strconv.FormatInt(*t.DueDate, 10)
+	}
+	return task
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]any{"user": map[string]any{"id": UserID, "username": "mock", "email": "mock@example.com"}})
+}
+
+func (s *Server) getTeams(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]any{"teams": []map[string]string{{"id": TeamID, "name": "Mock Workspace"}}})
+}
+
+func (s *Server) getSpaces(w http.ResponseWriter, r *http.Request) {
+	if r.PathValue("team") != TeamID {
+		notFound(w, "Team")
+		return
+	}
+	writeJSON(w, map[string]any{"spaces": []map[string]string{{"id": SpaceID, "name": "Mock Space"}}})
+}
+
+func (s *Server) getSpace(w http.ResponseWriter, r *http.Request) {
+	if r.PathValue("space") != SpaceID {
+		notFound(w, "Space")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	priorities := []map[string]string{}
+	if s.prioritiesEnabled {
+		for i, name := range []string{"urgent", "high", "normal", "low"} {
+			priorities = append(priorities, map[string]string{"id": strconv.Itoa(i + 1), "priority": name, "orderindex": strconv.Itoa(i + 1)})
+		}
+	}
+	writeJSON(w, map[string]any{
+		"id":       SpaceID,
+		"name":     "Mock Space",
+		"features": map[string]any{"priorities": map[string]any{"enabled": s.prioritiesEnabled, "priorities": priorities}},
+	})
+}
+
+func (s *Server) getCustomItems(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items := []map[string]any{}
+	for id, name := range s.customItems {
+		items = append(items, map[string]any{"id": id, "name": name, "name_plural": name + "s"})
+	}
+	slices.SortFunc(items, func(a, b map[string]any) int { return a["id"].(int) - b["id"].(int) })
+	writeJSON(w, map[string]any{"custom_items": items})
+}
+
+func (s *Server) getFolders(w http.ResponseWriter, r *http.Request) {
+	if r.PathValue("space") != SpaceID {
+		notFound(w, "Space")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	folders := []map[string]any{}
+	for _, id := range slices.Sorted(maps.Keys(s.folders)) {
+		folders = append(folders, map[string]any{"id": id, "name": s.folders[id], "lists": s.listSummaries(id)})
+	}
+	writeJSON(w, map[string]any{"folders": folders})
+}
+
+func (s *Server) createFolder(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if r.PathValue("space") != SpaceID {
+		notFound(w, "Space")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.newID()
+	s.folders[id] = req.Name
+	writeJSON(w, map[string]any{"id": id, "name": req.Name, "lists": []any{}})
+}
+
+func (s *Server) renameFolder(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := r.PathValue("folder")
+	if _, ok := s.folders[id]; !ok {
+		notFound(w, "Folder")
+		return
+	}
+	s.folders[id] = req.Name
+	writeJSON(w, map[string]any{"id": id, "name": req.Name})
+}
+
+func (s *Server) getFolderLists(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := r.PathValue("folder")
+	if _, ok := s.folders[id]; !ok {
+		notFound(w, "Folder")
+		return
+	}
+	writeJSON(w, map[string]any{"lists": s.listSummaries(id)})
+}
+
+func (s *Server) getSpaceLists(w http.ResponseWriter, r *http.Request) {
+	if r.PathValue("space") != SpaceID {
+		notFound(w, "Space")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, map[string]any{"lists": s.listSummaries("")})
+}
+
+// listSummaries returns the lists in a folder ("" for folderless ones),
+// sorted by ID. The caller holds s.mu.
+func (s *Server) listSummaries(folder string) []map[string]string {
+	lists := []map[string]string{}
+	for id, l := range s.lists {
+		if l.folder == folder {
+			lists = append(lists, map[string]string{"id": id, "name": l.name})
+		}
+	}
+	slices.SortFunc(lists, func(a, b map[string]string) int { return strings.Compare(a["id"], b["id"]) })
+	return lists
+}
+
+// listRequest is the body of a list create or update.
+type listRequest struct {
+	Name    *string `json:"name"`
+	Content *string `json:"content"`
+}
+
+// createList creates a list with DefaultStatuses in a folder or, without
+// one, directly in the space.
+func (s *Server) createList(w http.ResponseWriter, r *http.Request) {
+	var req listRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	folder := r.PathValue("folder")
+	if _, ok := s.folders[folder]; folder != "" && !ok {
+		notFound(w, "Folder")
+		return
+	}
+	if folder == "" && r.PathValue("space") != SpaceID {
+		notFound(w, "Space")
+		return
+	}
+	id := s.newID()
+	l := &list{folder: folder, statuses: slices.Clone(DefaultStatuses)}
+	if req.Name != nil {
+		l.name = *req.Name
+	}
+	if req.Content != nil {
+		l.content = *req.Content
+	}
+	s.lists[id] = l
+	writeJSON(w, s.listJSON(id, l))
+}
+
+func (s *Server) updateList(w http.ResponseWriter, r *http.Request) {
+	var req listRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := r.PathValue("list")
+	l := s.lists[id]
+	if l == nil {
+		notFound(w, "List")
+		return
+	}
+	if req.Name != nil {
+		l.name = *req.Name
+	}
+	if req.Content != nil {
+		l.content = *req.Content
+	}
+	writeJSON(w, s.listJSON(id, l))
+}
+
+func (s *Server) getSpaceTags(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tags := []map[string]string{}
+	for _, name := range s.spaceTags {
+		tags = append(tags, map[string]string{"name": name})
+	}
+	writeJSON(w, map[string]any{"tags": tags})
+}
+
+func (s *Server) createSpaceTag(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Tag struct {
+			Name string `json:"name"`
+		} `json:"tag"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !slices.Contains(s.spaceTags, req.Tag.Name) {
+		s.spaceTags = append(s.spaceTags, req.Tag.Name)
+	}
+	writeJSON(w, map[string]any{})
+}
+
+func (s *Server) getList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := r.PathValue("list")
+	l := s.lists[id]
+	if l == nil {
+		notFound(w, "List")
+		return
+	}
+	writeJSON(w, s.listJSON(id, l))
+}
+
+// listJSON renders a list. The caller holds s.mu.
+func (s *Server) listJSON(id string, l *list) map[string]any {
+	statuses := make([]map[string]string, len(l.statuses))
+	for i, name := range l.statuses {
+		// The first status opens the list and one named closed closes it
+		kind := "custom"
+		switch {
+		case i == 0:
+			kind = "open"
+		case name == "closed":
+			kind = "closed"
+		}
+		statuses[i] = map[string]string{"status": name, "type": kind}
+	}
+	folder := map[string]any{"id": "0", "name": "hidden", "hidden": true}
+	if l.folder != "" {
+		folder = map[string]any{"id": l.folder, "name": s.folders[l.folder], "hidden": false}
+	}
+	return map[string]any{
+		"id":       id,
+		"name":     l.name,
+		"content":  l.content,
+		"statuses": statuses,
+		"folder":   folder,
+		"space":    map[string]string{"id": SpaceID},
+	}
+}
+
+func (s *Server) getFields(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l := s.lists[r.PathValue("list")]
+	if l == nil {
+		notFound(w, "List")
+		return
+	}
+	writeJSON(w, map[string]any{"fields": append([]Field{}, l.fields...)})
+}
+
+func (s *Server) createField(w http.ResponseWriter, r *http.Request) {
+	var req Field
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l := s.lists[r.PathValue("list")]
+	if l == nil {
+		notFound(w, "List")
+		return
+	}
+	f := Field{ID: s.newID(), Name: req.Name, Type: req.Type}
+	l.fields = append(l.fields, f)
+	writeJSON(w, map[string]any{"field": f})
+}
+
+func (s *Server) getListTasks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	listID := r.PathValue("list")
+	if s.lists[listID] == nil {
+		notFound(w, "List")
+		return
+	}
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	var all []*Task
+	for _, id := range s.order {
+		if t := s.tasks[id]; t != nil && t.ListID == listID {
+			all = append(all, t)
+		}
+	}
+	start := min(page*PageSize, len(all))
+	end := min(start+PageSize, len(all))
+	tasks := []map[string]any{}
+	for _, t := range all[start:end] {
+		tasks = append(tasks, s.taskJSON(t))
+	}
+	writeJSON(w, map[string]any{"tasks": tasks, "last_page": end == len(all)})
+}
+
+// taskRequest is the body of a create or update. Pointers tell an omitted
+// field from a zero one.
+type taskRequest struct {
+	Name                *string `json:"name"`
+	Description         *string `json:"description"`
+	MarkdownDescription *string `json:"markdown_description"`
+	Status              *string `json:"status"`
+	Priority            *int    `json:"priority"`
+	Parent              *string `json:"parent"`
+	DueDate             *int64  `json:"due_date"`
+	CustomItemID        *int    `json:"custom_item_id"`
+	Archived            *bool   `json:"archived"`
+	CustomFields        []struct {
+		ID    string `json:"id"`
+		Value any    `json:"value"`
+	} `json:"custom_fields"`
+}
+
+// apply copies the request's fields onto t, checking the status against
+// the list. The caller holds s.mu.
+func (s *Server) apply(w http.ResponseWriter, t *Task, req *taskRequest) bool {
+	if req.Status != nil {
+		l := s.lists[t.ListID]
+		i := slices.IndexFunc(l.statuses, func(status string) bool { return strings.EqualFold(status, *req.Status) })
+		if i < 0 {
+			writeError(w, http.StatusBadRequest, "CRTSK_001", "Status does not exist")
+			return false
+		}
+		t.Status = l.statuses[i]
+	}
+	if req.Name != nil {
+		t.Name = *req.Name
+	}
+	if req.Description != nil {
+		t.Description = *req.Description
+	}
+	// ClickUp renders markdown to its own format; the mock keeps it as sent
+	if req.MarkdownDescription != nil {
+		t.Description = *req.MarkdownDescription
+	}
+	if req.Priority != nil {
+		t.Priority = req.Priority
+	}
+	if req.Parent != nil {
+		if s.tasks[*req.Parent] == nil {
+			notFound(w, "Parent task")
+			return false
+		}
+		t.Parent = *req.Parent
+	}
+	if req.DueDate != nil {
+		t.DueDate = req.DueDate
+		if *req.DueDate == 0 {
+			t.DueDate = nil
+		}
+	}
+	if req.CustomItemID != nil {
+		t.CustomItemID = req.CustomItemID
+	}
+	if req.Archived != nil {
+		t.Archived = *req.Archived
+	}
+	for _, f := range req.CustomFields {
+		if t.Fields == nil {
+			t.Fields = make(map[string]any)
+		}
+		t.Fields[f.ID] = fieldValue(s.lists[t.ListID], f.ID, f.Value)
+	}
+	t.Updated = time.Now()
+	return true
+}
+
+// fieldValue returns value as ClickUp stores it: dates as millisecond
+// strings.
+func fieldValue(l *list, fieldID string, value any) any {
+	i := slices.IndexFunc(l.fields, func(f Field) bool { return f.ID == fieldID })
+	if n, isNumber := value.(float64); isNumber && i >= 0 && l.fields[i].Type == "date" {
+		return strconv.FormatInt(int64(n), 10)
+	}
+	return value
+}
+
+func (s *Server) createTask(w http.ResponseWriter, r *http.Request) {
+	var req taskRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	l := s.lists[r.PathValue("list")]
+	if l == nil {
+		s.mu.Unlock()
+		notFound(w, "List")
+		return
+	}
+	if req.Name == nil || *req.Name == "" {
+		s.mu.Unlock()
+		writeError(w, http.StatusBadRequest, "INPUT_002", "Task name invalid")
+		return
+	}
+	t := &Task{ID: s.newID(), ListID: r.PathValue("list")}
+	if len(l.statuses) > 0 {
+		t.Status = l.statuses[0]
+	}
+	if !s.apply(w, t, &req) {
+		s.mu.Unlock()
+		return
+	}
+	s.tasks[t.ID] = t
+	s.order = append(s.order, t.ID)
+	body := s.taskJSON(t)
+	s.mu.Unlock()
+
+	writeJSON(w, body)
+	s.notify("taskCreated", t.ID)
+}
+
+func (s *Server) createTaskFromTemplate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	l := s.lists[r.PathValue("list")]
+	tmpl, ok := s.templates[r.PathValue("template")]
+	switch {
+	case l == nil:
+		s.mu.Unlock()
+		notFound(w, "List")
+		return
+	case !ok:
+		s.mu.Unlock()
+		notFound(w, "Template")
+		return
+	case req.Name == "":
+		s.mu.Unlock()
+		writeError(w, http.StatusBadRequest, "INPUT_002", "Task name invalid")
+		return
+	}
+	t := tmpl.clone()
+	t.ID, t.ListID, t.Name, t.Updated = s.newID(), r.PathValue("list"), req.Name, time.Now()
+	if t.Status == "" && len(l.statuses) > 0 {
+		t.Status = l.statuses[0]
+	}
+	s.tasks[t.ID] = &t
+	s.order = append(s.order, t.ID)
+	body := map[string]any{"id": t.ID, "task": s.taskJSON(&t)}
+	s.mu.Unlock()
+
+	writeJSON(w, body)
+	s.notify("taskCreated", t.ID)
+}
+
+func (s *Server) getTask(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tasks[r.PathValue("task")]
+	if t == nil {
+		notFound(w, "Task")
+		return
+	}
+	writeJSON(w, s.taskJSON(t))
+}
+
+func (s *Server) updateTask(w http.ResponseWriter, r *http.Request) {
+	var req taskRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	t := s.tasks[r.PathValue("task")]
+	if t == nil {
+		s.mu.Unlock()
+		notFound(w, "Task")
+		return
+	}
+	if !s.apply(w, t, &req) {
+		s.mu.Unlock()
+		return
+	}
+	body := s.taskJSON(t)
+	s.mu.Unlock()
+
+	writeJSON(w, body)
+	s.notify("taskUpdated", t.ID)
+}
+
+// moveTask serves API v3's home list change.
+func (s *Server) moveTask(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	if s.lists[r.PathValue("list")] == nil {
+		s.mu.Unlock()
+		notFound(w, "List")
+		return
+	}
+	s.mu.Unlock()
+	s.editTask(w, r, "taskMoved", func(t *Task) (any, bool) {
+		t.ListID = r.PathValue("list")
+		return map[string]any{}, true
+	})
+}
+
+func (s *Server) addTaskToList(w http.ResponseWriter, r *http.Request) {
+	listID := r.PathValue("list")
+	s.mu.Lock()
+	if s.lists[listID] == nil {
+		s.mu.Unlock()
+		notFound(w, "List")
+		return
+	}
+	s.mu.Unlock()
+	s.editTask(w, r, "taskUpdated", func(t *Task) (any, bool) {
+		if t.ListID != listID && !slices.Contains(t.OtherLists, listID) {
+			t.OtherLists = append(t.OtherLists, listID)
+		}
+		return map[string]any{}, true
+	})
+}
+
+func (s *Server) removeTaskFromList(w http.ResponseWriter, r *http.Request) {
+	listID := r.PathValue("list")
+	s.editTask(w, r, "taskUpdated", func(t *Task) (any, bool) {
+		t.OtherLists = slices.DeleteFunc(t.OtherLists, func(id string) bool { return id == listID })
+		return map[string]any{}, true
+	})
+}
+
+func (s *Server) deleteTask(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("task")
+	s.mu.Lock()
+	if s.tasks[id] == nil {
+		s.mu.Unlock()
+		notFound(w, "Task")
+		return
+	}
+	delete(s.tasks, id)
+	s.order = slices.DeleteFunc(s.order, func(other string) bool { return other == id })
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+	s.notify("taskDeleted", id)
+}
+
+// editTask runs edit on the path's task under the lock and answers with
+// body, or 404.
+func (s *Server) editTask(w http.ResponseWriter, r *http.Request, event string, edit func(*Task) (any, bool)) {
+	s.mu.Lock()
+	t := s.tasks[r.PathValue("task")]
+	if t == nil {
+		s.mu.Unlock()
+		notFound(w, "Task")
+		return
+	}
+	body, ok := edit(t)
+	if ok {
+		t.Updated = time.Now()
+	}
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	writeJSON(w, body)
+	if event != "" {
+		s.notify(event, t.ID)
+	}
+}
+
+// getTimeEntries filters by task_id and by start between start_date and
+// end_date. Like ClickUp, no range means the last 30 days.
+func (s *Server) getTimeEntries(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	end := time.Now().UnixMilli()
+	start := end - (30 * 24 * time.Hour).Milliseconds()
+	if v, err := strconv.ParseInt(q.Get("start_date"), 10, 64); err == nil {
+		start = v
+	}
+	if v, err := strconv.ParseInt(q.Get("end_date"), 10, 64); err == nil {
+		end = v
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data := []map[string]any{}
+	for _, e := range s.timeEntries {
+		if (q.Get("task_id") != "" && e.TaskID != q.Get("task_id")) || e.Start < start || e.Start > end {
+			continue
+		}
+		data = append(data, timeEntryJSON(e))
+	}
+	writeJSON(w, map[string]any{"data": data})
+}
+
+func (s *Server) createTimeEntry(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TaskID      string `json:"tid"`
+		Description string `json:"description"`
+		Start       int64  `json:"start"`
+		Duration    int64  `json:"duration"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tasks[req.TaskID] == nil {
+		notFound(w, "Task")
+		return
+	}
+	e := TimeEntry{ID: s.newID(), TaskID: req.TaskID, Description: req.Description, Start: req.Start, Duration: req.Duration}
+	s.timeEntries = append(s.timeEntries, e)
+	writeJSON(w, map[string]any{"data": timeEntryJSON(e)})
+}
+
+func timeEntryJSON(e TimeEntry) map[string]any {
+	return map[string]any{
+		"id":          e.ID,
+		"task":        map[string]string{"id": e.TaskID},
+		"description": e.Description,
+		"start":       strconv.FormatInt(e.Start, 10),
+		"duration":    strconv.FormatInt(e.Duration, 10),
+	}
+}
+
+func (s *Server) createComment(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Comment []struct {
+			Text string `json:"text"`
+		} `json:"comment"`
+		CommentText string `json:"comment_text"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	text := req.CommentText
+	for _, part := range req.Comment {
+		text += part.Text
+	}
+	s.editTask(w, r, "taskCommentPosted", func(t *Task) (any, bool) {
+		t.Comments = append(t.Comments, text)
+		return map[string]any{"id": s.newID(), "date": time.Now().UnixMilli()}, true
+	})
+}
+
+// getComments lists a task's comments newest first, like ClickUp.
+func (s *Server) getComments(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tasks[r.PathValue("task")]
+	if t == nil {
+		notFound(w, "Task")
+		return
+	}
+	comments := []map[string]string{}
+	for i, text := range slices.Backward(t.Comments) {
+		comments = append(comments, map[string]string{"id": t.ID + "-" + strconv.Itoa(i), "comment_text": text})
+	}
+	writeJSON(w, map[string]any{"comments": comments})
+}
+
+func (s *Server) createAttachment(w http.ResponseWriter, r *http.Request) {
+	file, header, err := r.FormFile("attachment")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "UPLOAD_002", "No attachment")
+		return
+	}
+	data, _ := io.ReadAll(file)
+	s.editTask(w, r, "taskUpdated", func(t *Task) (any, bool) {
+		s.files[t.ID+"/"+strconv.Itoa(len(t.Attachments))] = data
+		t.Attachments = append(t.Attachments, header.Filename)
+		return map[string]any{"id": s.newID(), "title": header.Filename}, true
+	})
+}
+
+// getFile serves an attachment's content from its URL in the task.
+func (s *Server) getFile(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	data, ok := s.files[r.PathValue("task")+"/"+r.PathValue("index")]
+	s.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_, _ = w.Write(data)
+}
+
+func (s *Server) addDependency(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		DependsOn string `json:"depends_on"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.editTask(w, r, "taskUpdated", func(t *Task) (any, bool) {
+		if s.tasks[req.DependsOn] == nil {
+			notFound(w, "Dependency task")
+			return nil, false
+		}
+		if !slices.Contains(t.DependsOn, req.DependsOn) {
+			t.DependsOn = append(t.DependsOn, req.DependsOn)
+		}
+		return map[string]any{}, true
+	})
+}
+
+func (s *Server) addTag(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("tag")
+	s.editTask(w, r, "taskTagUpdated", func(t *Task) (any, bool) {
+		if !slices.Contains(t.Tags, name) {
+			t.Tags = append(t.Tags, name)
+		}
+		return map[string]any{}, true
+	})
+}
+
+func (s *Server) removeTag(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("tag")
+	s.editTask(w, r, "taskTagUpdated", func(t *Task) (any, bool) {
+		t.Tags = slices.DeleteFunc(t.Tags, func(tag string) bool { return tag == name })
+		return map[string]any{}, true
+	})
+}
+
+func (s *Server) setField(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Value json.RawMessage `json:"value"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	var value any
+	_ = json.Unmarshal(req.Value, &value)
+	fieldID := r.PathValue("field")
+	s.editTask(w, r, "taskUpdated", func(t *Task) (any, bool) {
+		l := s.lists[t.ListID]
+		if !slices.ContainsFunc(l.fields, func(f Field) bool { return f.ID == fieldID }) {
+			notFound(w, "Field")
+			return nil, false
+		}
+		if t.Fields == nil {
+			t.Fields = make(map[string]any)
+		}
+		t.Fields[fieldID] = fieldValue(l, fieldID, value)
+		return map[string]any{}, true
+	})
+}
@@ -0,0 +1,434 @@
+// Package clickuptest is an in-memory ClickUp API for end-to-end tests. It
+// implements the endpoints beanup uses (lists, tasks, tags, custom fields,
+// dependencies, comments, attachments, and the workspace hierarchy) plus
+// webhooks, which it delivers signed like ClickUp does.
+//
+// The package doesn't import package clickup, so that package's own tests
+// can run against it.
+package clickuptest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IDs of the workspace a new Server is seeded with.
+const (
+	TeamID  = "1"
+	SpaceID = "10"
+	ListID  = "100"
+	UserID  = 1
+)
+
+// DefaultStatuses are the statuses of the seeded list, matching beanup's
+// default status mapping.
+var DefaultStatuses = []string{"backlog", "to do", "in progress", "complete", "closed"}
+
+// PageSize is how many tasks a list task page holds, so tests can exercise
+// pagination with a handful of tasks.
+var PageSize = 100
+
+// Task is a task held by the server.
+type Task struct {
+	ID           string
+	ListID       string
+	OtherLists   []string // Lists the task was added to besides its home list
+	Name         string
+	Description  string
+	Status       string
+	Priority     *int // 1 (urgent) to 4 (low)
+	Parent       string
+	DueDate      *int64 // Unix ms
+	CustomItemID *int
+	Tags         []string
+	Fields       map[string]any // Custom field values by field ID
+	DependsOn    []string       // Tasks this one is waiting on
+	Assignees    []int          // User IDs
+	Comments     []string
+	Attachments  []string // File names
+	Archived     bool
+	Updated      time.Time
+}
+
+// TimeEntry is time tracked on a task.
+type TimeEntry struct {
+	ID          string
+	TaskID      string
+	Description string
+	Start       int64 // Unix ms
+	Duration    int64 // ms
+}
+
+// Field is a custom field on a list.
+type Field struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Webhook is a registered webhook.
+type Webhook struct {
+	ID       string   `json:"id"`
+	TeamID   string   `json:"workspace_id"`
+	Endpoint string   `json:"endpoint"`
+	Events   []string `json:"events"`
+	Secret   string   `json:"secret"`
+}
+
+// List is a list held by the server.
+type List struct {
+	ID       string
+	Name     string
+	Content  string // Description
+	FolderID string // Empty for folderless lists
+}
+
+type list struct {
+	name     string
+	content  string
+	folder   string
+	statuses []string
+	fields   []Field
+}
+
+// Server is an in-memory ClickUp API. It is an http.Handler; Start runs it
+// on a local port. It is safe for concurrent use.
+type Server struct {
+	// URL is the API root after Start, for clickup.WithBaseURL
+	URL string
+
+	mu          sync.Mutex
+	lists       map[string]*list
+	folders     map[string]string // Folder names by ID
+	tasks       map[string]*Task
+	templates   map[string]Task // Task templates by ID
+	order       []string        // Task IDs in creation order
+	spaceTags   []string
+	customItems map[int]string
+	webhooks    []Webhook
+	timeEntries []TimeEntry
+	files       map[string][]byte // Attachment contents by "taskID/index"
+	requests    []string
+	nextID      int
+
+	prioritiesEnabled bool // The space's Priorities ClickApp
+
+	mux        *http.ServeMux
+	httpServer *httptest.Server
+	deliveries sync.WaitGroup
+}
+
+// New returns a server seeded with one workspace (TeamID), space (SpaceID),
+// and list (ListID) with DefaultStatuses.
+func New() *Server {
+	s := &Server{
+		lists:       make(map[string]*list),
+		folders:     make(map[string]string),
+		tasks:       make(map[string]*Task),
+		templates:   make(map[string]Task),
+		customItems: make(map[int]string),
+		files:       make(map[string][]byte),
+		nextID:      1000,
+
+		prioritiesEnabled: true,
+	}
+	s.AddList(ListID, "Beans", DefaultStatuses...)
+	s.routes()
+	return s
+}
+
+// Start returns a new server listening on a local port. Close stops it.
+func Start() *Server {
+	s := New()
+	s.httpServer = httptest.NewServer(s)
+	s.URL = s.httpServer.URL
+	return s
+}
+
+// Close stops a started server after any webhook deliveries finish.
+func (s *Server) Close() {
+	if s.httpServer != nil {
+		s.httpServer.Close()
+	}
+	s.deliveries.Wait()
+}
+
+// AddList adds an empty list to the seeded space.
+func (s *Server) AddList(id, name string, statuses ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lists[id] = &list{name: name, statuses: statuses}
+}
+
+// AddTemplate adds a task template. Tasks created from it start as copies
+// of t with the requested name, in the requested list.
+func (s *Server) AddTemplate(id string, t Task) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.templates[id] = t.clone()
+}
+
+// SetPrioritiesEnabled turns the space's Priorities ClickApp on or off.
+func (s *Server) SetPrioritiesEnabled(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prioritiesEnabled = enabled
+}
+
+// List returns a copy of a list.
+func (s *Server) List(id string) (List, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.lists[id]
+	if !ok {
+		return List{}, false
+	}
+	return List{ID: id, Name: l.name, Content: l.content, FolderID: l.folder}, true
+}
+
+// TimeEntries returns copies of a task's time entries in creation order.
+func (s *Server) TimeEntries(taskID string) []TimeEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var entries []TimeEntry
+	for _, e := range s.timeEntries {
+		if e.TaskID == taskID {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// Folder returns a folder's name.
+func (s *Server) Folder(id string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name, ok := s.folders[id]
+	return name, ok
+}
+
+// AddField adds a custom field to a list and returns its ID.
+func (s *Server) AddField(listID, name, fieldType string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l := s.lists[listID]
+	if l == nil {
+		panic("clickuptest: no list " + listID)
+	}
+	f := Field{ID: s.newID(), Name: name, Type: fieldType}
+	l.fields = append(l.fields, f)
+	return f.ID
+}
+
+// AddCustomItem adds a custom task type to the workspace.
+func (s *Server) AddCustomItem(id int, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.customItems[id] = name
+}
+
+// AddTask stores a copy of t, as if created in ClickUp, and returns its ID.
+// An empty ListID means the seeded list.
+func (s *Server) AddTask(t Task) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t.ListID == "" {
+		t.ListID = ListID
+	}
+	if t.ID == "" {
+		t.ID = s.newID()
+	}
+	if t.Updated.IsZero() {
+		t.Updated = time.Now()
+	}
+	s.tasks[t.ID] = &t
+	s.order = append(s.order, t.ID)
+	return t.ID
+}
+
+// Task returns a copy of a task.
+func (s *Server) Task(id string) (Task, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tasks[id]
+	if !ok {
+		return Task{}, false
+	}
+	return t.clone(), true
+}
+
+// Tasks returns copies of a list's tasks in creation order.
+func (s *Server) Tasks(listID string) []Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var tasks []Task
+	for _, id := range s.order {
+		if t := s.tasks[id]; t != nil && t.ListID == listID {
+			tasks = append(tasks, t.clone())
+		}
+	}
+	return tasks
+}
+
+// UpdateTask changes a task as if edited in ClickUp and delivers a
+// taskUpdated webhook. It reports whether the task exists.
+func (s *Server) UpdateTask(id string, edit func(*Task)) bool {
+	s.mu.Lock()
+	t, ok := s.tasks[id]
+	if ok {
+		edit(t)
+		t.Updated = time.Now()
+	}
+	s.mu.Unlock()
+	if ok {
+		s.notify("taskUpdated", id)
+	}
+	return ok
+}
+
+// SpaceTags returns the tags created in the space.
+func (s *Server) SpaceTags() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.spaceTags)
+}
+
+// Webhooks returns the registered webhooks.
+func (s *Server) Webhooks() []Webhook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.webhooks)
+}
+
+// Requests returns every request served, as "METHOD /path".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+func (t *Task) clone() Task {
+	c := *t
+	c.OtherLists = slices.Clone(t.OtherLists)
+	c.Tags = slices.Clone(t.Tags)
+	c.DependsOn = slices.Clone(t.DependsOn)
+	c.Assignees = slices.Clone(t.Assignees)
+	c.Comments = slices.Clone(t.Comments)
+	c.Attachments = slices.Clone(t.Attachments)
+	if t.Fields != nil {
+		c.Fields = make(map[string]any, len(t.Fields))
+		for k, v := range t.Fields {
+			c.Fields[k] = v
+		}
+	}
+	return c
+}
+
+// newID returns a fresh ID. The caller holds s.mu.
+func (s *Server) newID() string {
+	s.nextID++
+	return strconv.Itoa(s.nextID)
+}
+
+// ServeHTTP serves the API, with or without the /api/v2 prefix.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if p, ok := strings.CutPrefix(r.URL.Path, "/api/v2"); ok {
+		r.URL.Path = p
+	} else if p, ok := strings.CutPrefix(r.URL.Path, "/api/v3"); ok {
+		r.URL.Path = "/v3" + p
+	}
+	s.mu.Lock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+	s.mu.Unlock()
+
+	// Attachment URLs are signed file links, fetched without the token
+	if r.Header.Get("Authorization") == "" && !strings.HasPrefix(r.URL.Path, "/files/") {
+		writeError(w, http.StatusUnauthorized, "OAUTH_017", "Authorization header required")
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) routes() {
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("GET /user", s.getUser)
+	s.mux.HandleFunc("GET /team", s.getTeams)
+	s.mux.HandleFunc("GET /team/{team}/space", s.getSpaces)
+	s.mux.HandleFunc("GET /team/{team}/custom_item", s.getCustomItems)
+	s.mux.HandleFunc("GET /team/{team}/webhook", s.getWebhooks)
+	s.mux.HandleFunc("POST /team/{team}/webhook", s.createWebhook)
+	s.mux.HandleFunc("DELETE /webhook/{id}", s.deleteWebhook)
+	s.mux.HandleFunc("GET /team/{team}/time_entries", s.getTimeEntries)
+	s.mux.HandleFunc("POST /team/{team}/time_entries", s.createTimeEntry)
+	s.mux.HandleFunc("GET /space/{space}", s.getSpace)
+	s.mux.HandleFunc("GET /space/{space}/folder", s.getFolders)
+	s.mux.HandleFunc("POST /space/{space}/folder", s.createFolder)
+	s.mux.HandleFunc("PUT /folder/{folder}", s.renameFolder)
+	s.mux.HandleFunc("GET /space/{space}/list", s.getSpaceLists)
+	s.mux.HandleFunc("POST /space/{space}/list", s.createList)
+	s.mux.HandleFunc("POST /folder/{folder}/list", s.createList)
+	s.mux.HandleFunc("GET /space/{space}/tag", s.getSpaceTags)
+	s.mux.HandleFunc("POST /space/{space}/tag", s.createSpaceTag)
+	s.mux.HandleFunc("GET /folder/{folder}/list", s.getFolderLists)
+	s.mux.HandleFunc("GET /list/{list}", s.getList)
+	s.mux.HandleFunc("PUT /list/{list}", s.updateList)
+	s.mux.HandleFunc("GET /list/{list}/field", s.getFields)
+	s.mux.HandleFunc("POST /list/{list}/field", s.createField)
+	s.mux.HandleFunc("GET /list/{list}/task", s.getListTasks)
+	s.mux.HandleFunc("POST /list/{list}/task", s.createTask)
+	s.mux.HandleFunc("POST /list/{list}/taskTemplate/{template}", s.createTaskFromTemplate)
+	s.mux.HandleFunc("POST /list/{list}/task/{task}", s.addTaskToList)
+	s.mux.HandleFunc("DELETE /list/{list}/task/{task}", s.removeTaskFromList)
+	s.mux.HandleFunc("GET /task/{task}", s.getTask)
+	s.mux.HandleFunc("PUT /task/{task}", s.updateTask)
+	s.mux.HandleFunc("DELETE /task/{task}", s.deleteTask)
+	s.mux.HandleFunc("PUT /v3/workspaces/{team}/tasks/{task}/home_list/{list}", s.moveTask)
+	s.mux.HandleFunc("GET /task/{task}/comment", s.getComments)
+	s.mux.HandleFunc("POST /task/{task}/comment", s.createComment)
+	s.mux.HandleFunc("POST /task/{task}/attachment", s.createAttachment)
+	s.mux.HandleFunc("POST /task/{task}/dependency", s.addDependency)
+	s.mux.HandleFunc("POST /task/{task}/tag/{tag}", s.addTag)
+	s.mux.HandleFunc("DELETE /task/{task}/tag/{tag}", s.removeTag)
+	s.mux.HandleFunc("POST /task/{task}/field/{field}", s.setField)
+	s.mux.HandleFunc("GET /files/{task}/{index}", s.getFile)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error in ClickUp's {"err", "ECODE"} shape.
+func writeError(w http.ResponseWriter, status int, code, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"err": msg, "ECODE": code})
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "INPUT_001", "Invalid JSON: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func randomSecret() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// notFound writes the error for an unknown task or list.
+func notFound(w http.ResponseWriter, what string) {
+	writeError(w, http.StatusNotFound, "ITEM_013", fmt.Sprintf("%s not found", what))
+}
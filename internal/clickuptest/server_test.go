@@ -0,0 +1,69 @@
+package clickuptest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebhookDelivery(t *testing.T) {
+	mock := Start()
+	defer mock.Close()
+
+	got := make(chan string, 1)
+	var hook Webhook
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("X-Signature") != Signature(hook.Secret, body) {
+			t.Errorf("bad signature on %s", body)
+		}
+		got <- string(body)
+	}))
+	defer receiver.Close()
+
+	do := func(method, path, body string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(method, mock.URL+path, strings.NewReader(body))
+		req.Header.Set("Authorization", "token")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	resp := do("POST", "/team/"+TeamID+"/webhook", `{"endpoint":"`+receiver.URL+`","events":["taskStatusUpdated","taskUpdated"]}`)
+	var created struct {
+		Webhook Webhook `json:"webhook"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&created)
+	hook = created.Webhook
+
+	// Creating a task isn't subscribed to; updating it is
+	resp = do("POST", "/list/"+ListID+"/task", `{"name":"Task","status":"TO DO"}`)
+	var task struct {
+		ID     string `json:"id"`
+		Status struct {
+			Status string `json:"status"`
+		} `json:"status"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&task)
+	if task.Status.Status != "to do" {
+		t.Errorf("status = %q, want the list's spelling", task.Status.Status)
+	}
+	do("PUT", "/task/"+task.ID, `{"name":"Renamed"}`)
+	if body := <-got; !strings.Contains(body, `"event":"taskUpdated"`) || !strings.Contains(body, task.ID) {
+		t.Errorf("delivered %s", body)
+	}
+
+	// Unknown statuses are rejected like ClickUp does
+	if resp := do("PUT", "/task/"+task.ID, `{"status":"nope"}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("unknown status: %d, want 400", resp.StatusCode)
+	}
+	if resp := do("GET", "/user", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /user: %d", resp.StatusCode)
+	}
+}
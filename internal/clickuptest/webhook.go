@@ -0,0 +1,95 @@
+package clickuptest
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// Signature returns the X-Signature header ClickUp sends with a webhook
+// body: its HMAC-SHA256 under the webhook secret, hex encoded.
+func Signature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookClient delivers webhooks. Deliveries that fail are dropped.
+var webhookClient = &http.Client{Timeout: 5 * time.Second}
+
+func (s *Server) getWebhooks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, map[string]any{"webhooks": append([]Webhook{}, s.webhooks...)})
+}
+
+func (s *Server) createWebhook(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Endpoint string   `json:"endpoint"`
+		Events   []string `json:"events"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.Endpoint == "" {
+		writeError(w, http.StatusBadRequest, "OAUTH_061", "Webhook endpoint required")
+		return
+	}
+	if len(req.Events) == 0 {
+		req.Events = []string{"*"}
+	}
+	s.mu.Lock()
+	hook := Webhook{ID: s.newID(), TeamID: r.PathValue("team"), Endpoint: req.Endpoint, Events: req.Events, Secret: randomSecret()}
+	s.webhooks = append(s.webhooks, hook)
+	s.mu.Unlock()
+	writeJSON(w, map[string]any{"id": hook.ID, "webhook": hook})
+}
+
+func (s *Server) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.webhooks)
+	s.webhooks = slices.DeleteFunc(s.webhooks, func(h Webhook) bool { return h.ID == id })
+	if len(s.webhooks) == n {
+		notFound(w, "Webhook")
+		return
+	}
+	writeJSON(w, map[string]any{})
+}
+
+// notify delivers event for taskID to every webhook subscribed to it, in
+// the background. Close waits for deliveries.
+func (s *Server) notify(event, taskID string) {
+	s.mu.Lock()
+	var hooks []Webhook
+	for _, h := range s.webhooks {
+		if slices.Contains(h.Events, "*") || slices.Contains(h.Events, event) {
+			hooks = append(hooks, h)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, h := range hooks {
+		body, err := json.Marshal(map[string]string{"event": event, "task_id": taskID, "webhook_id": h.ID})
+		if err != nil {
+			continue
+		}
+		s.deliveries.Go(func() {
+			req, err := http.NewRequest(http.MethodPost, h.Endpoint, bytes.NewReader(body))
+			if err != nil {
+				return
+			}
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Signature", Signature(h.Secret, body))
+			if resp, err := webhookClient.Do(req); err == nil {
+				_ = resp.Body.Close()
+			}
+		})
+	}
+}
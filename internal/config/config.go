@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/toba/bean-me-up/internal/beans"
 	"gopkg.in/yaml.v3"
@@ -21,23 +22,173 @@ const (
 // Config holds the bean-me-up configuration.
 type Config struct {
 	Beans BeansWrapper `yaml:"beans"`
+
+	// Path is the file the config was loaded from.
+	Path string `yaml:"-"`
+	// Profile is the user profile layered under the file, if any.
+	Profile *Profile `yaml:"-"`
 }
 
-// BeansWrapper wraps the ClickUp configuration under the beans key.
+// BeansWrapper wraps the backend configurations under the beans key.
 type BeansWrapper struct {
 	ClickUp ClickUpConfig `yaml:"clickup"`
+	GitHub  *GitHubConfig `yaml:"github,omitempty"`
+	Jira    *JiraConfig   `yaml:"jira,omitempty"`
+	Linear  *LinearConfig `yaml:"linear,omitempty"`
+	GitLab  *GitLabConfig `yaml:"gitlab,omitempty"`
+	Azure   *AzureConfig  `yaml:"azure,omitempty"`
+	Notion  *NotionConfig `yaml:"notion,omitempty"`
 }
 
 // ClickUpConfig holds ClickUp-specific settings.
 type ClickUpConfig struct {
-	ListID          string            `yaml:"list_id"`
-	Assignee        *int              `yaml:"assignee,omitempty"`
-	StatusMapping   map[string]string `yaml:"status_mapping,omitempty"`
-	PriorityMapping map[string]int    `yaml:"priority_mapping,omitempty"`
-	TypeMapping     map[string]int    `yaml:"type_mapping,omitempty"`
-	CustomFields    *CustomFieldsMap  `yaml:"custom_fields,omitempty"`
+	ListID string `yaml:"list_id"`
+	// TeamID restricts workspace-wide lookups such as custom task types to
+	// one workspace when the token can access several.
+	TeamID   string `yaml:"team_id,omitempty"`
+	Assignee *int   `yaml:"assignee,omitempty"`
+	// AssigneeStrategy picks who new tasks are assigned to: token_owner,
+	// bean_assignee, fixed (Assignee), or none. Empty means fixed if
+	// Assignee is set (none if it's 0), else token_owner.
+	AssigneeStrategy string            `yaml:"assignee_strategy,omitempty"`
+	StatusMapping    map[string]string `yaml:"status_mapping,omitempty"`
+	PriorityMapping  map[string]int    `yaml:"priority_mapping,omitempty"`
+	TypeMapping      map[string]int    `yaml:"type_mapping,omitempty"`
+	CustomFields     *CustomFieldsMap  `yaml:"custom_fields,omitempty"`
+	// TitleTemplate is a text/template for task names, executed with the
+	// bean, e.g. "[{{.ID}}] {{.Title}}". Empty means the bean title.
+	TitleTemplate string `yaml:"title_template,omitempty"`
+	// TagPrefix is prepended to bean tags on tasks, e.g. "bean/". When set,
+	// only tags with the prefix are managed; others are never removed.
+	TagPrefix string `yaml:"tag_prefix,omitempty"`
+	// TagMapping renames bean tags on tasks, e.g. backend: "Back End".
+	// Mapped names are used as-is, without TagPrefix or TagCase.
+	TagMapping map[string]string `yaml:"tag_mapping,omitempty"`
+	// TagCase normalizes unmapped tags: lower, upper, or title.
+	TagCase string `yaml:"tag_case,omitempty"`
+	// TagSeparator, if set, replaces "-" and "_" in unmapped tags, e.g. " "
+	// turns "back-end" into "back end".
+	TagSeparator string `yaml:"tag_separator,omitempty"`
+	// CreateSpaceTags registers tags sync adds to tasks as space tags, so
+	// they show in the tag picker. Unset means true.
+	CreateSpaceTags *bool `yaml:"create_space_tags,omitempty"`
+	// TagColors colors the space tags sync creates by the longest glob
+	// pattern they match, e.g. "area/*". Existing tags keep their colors.
+	TagColors map[string]TagColor `yaml:"tag_colors,omitempty"`
+	// ProtectedTags are task tags sync never removes, e.g. ones added by
+	// hand in ClickUp.
+	ProtectedTags []string `yaml:"protected_tags,omitempty"`
+	// RawMarkdown sends bean bodies to ClickUp untouched instead of fixing
+	// up constructs ClickUp renders badly.
+	RawMarkdown bool `yaml:"raw_markdown,omitempty"`
+	// DescriptionLimit is the longest task description, in characters,
+	// written before the rest overflows. 0 means DefaultDescriptionLimit.
+	DescriptionLimit int `yaml:"description_limit,omitempty"`
+	// DescriptionOverflow says where the rest of a long description goes:
+	// comment (default), attachment, or truncate (dropped).
+	DescriptionOverflow string `yaml:"description_overflow,omitempty"`
+	// SourceFooter ends each description with a permalink to the bean's
+	// file, built from the git origin remote.
+	SourceFooter bool `yaml:"source_footer,omitempty"`
+	// StoreTaskURL records each task's URL in the bean's extension
+	// metadata next to its ID, so editors can link to it offline.
+	StoreTaskURL bool `yaml:"store_task_url,omitempty"`
+	// OnDelete is what sync does to the task of a deleted bean: close (set
+	// the scrapped status), archive, or delete. Empty leaves it alone.
+	OnDelete string `yaml:"on_delete,omitempty"`
+	// TimeTracking creates a ClickUp time entry on the task for each entry
+	// under a worklog: line in the bean body.
+	TimeTracking bool `yaml:"time_tracking,omitempty"`
+	// TaskTemplateID is a ClickUp task template new top-level tasks are
+	// created from, e.g. for its checklists, before the bean's fields are
+	// applied. TaskTemplates overrides it per bean type.
+	TaskTemplateID string            `yaml:"task_template_id,omitempty"`
+	TaskTemplates  map[string]string `yaml:"task_templates,omitempty"`
+	// SprintLists maps sprints to ClickUp Sprint list IDs. A bean's task is
+	// added to the list of its sprint extension value, or else of its first
+	// tag with an entry, on top of its home list.
+	SprintLists map[string]string `yaml:"sprint_lists,omitempty"`
+	// EpicsAs turns epic beans into ClickUp lists ("list", next to list_id)
+	// or folders holding a list ("folder") instead of tasks. Their
+	// children's tasks are created in that list.
+	EpicsAs string `yaml:"epics_as,omitempty"`
+	// OnMove is what sync does when a linked task has moved to another
+	// list: warn (default, leave it alone), follow (keep syncing it
+	// there), or return (move it back).
+	OnMove string `yaml:"on_move,omitempty"`
+	// CacheTTL is how long list, custom field, and task type metadata is
+	// reused from the response cache, as a Go duration ("30m", "24h").
+	// Empty means an hour; "0" always revalidates.
+	CacheTTL string `yaml:"cache_ttl,omitempty"`
+	// StaleAfter is how long after its last sync a linked bean counts as
+	// stale, as a Go duration. Empty means a week.
+	StaleAfter string `yaml:"stale_after,omitempty"`
+	// HTTP tunes the API client's timeout, retries, and connections.
+	HTTP *HTTPConfig `yaml:"http,omitempty"`
+	// Users maps short names to ClickUp user IDs for @mentions in comments.
+	Users map[string]int `yaml:"users,omitempty"`
+	// WebhookSecret verifies the signatures of ClickUp webhooks received by
+	// beanup serve. CLICKUP_WEBHOOK_SECRET overrides it.
+	WebhookSecret string `yaml:"webhook_secret,omitempty"`
+	// CommentOnStatusChange posts a comment on a task whenever sync changes
+	// its status, so ClickUp watchers see why it moved.
+	CommentOnStatusChange bool `yaml:"comment_on_status_change,omitempty"`
+	// FieldOwnership names the side, beans or clickup, authoritative for
+	// each of title, description, status, priority, type, due, and tags.
+	// Fields are owned by beans unless set to clickup.
+	FieldOwnership map[string]string `yaml:"field_ownership,omitempty"`
+	// ChecklistSection is the heading of the bean body section that beanup
+	// pull writes the task's checklists into as a markdown task list.
+	ChecklistSection string `yaml:"checklist_section,omitempty"`
+	// ReviewRequest asks a bean's reviewer for a review with an assigned
+	// comment when sync moves its task into a status.
+	ReviewRequest *ReviewRequestConfig `yaml:"review_request,omitempty"`
+
+	SyncFilter    *SyncFilter          `yaml:"sync_filter,omitempty"`
+	Notifications *NotificationsConfig `yaml:"notifications,omitempty"`
+
+	TokenSource `yaml:",inline"`
+}
 
-	SyncFilter      *SyncFilter       `yaml:"sync_filter,omitempty"`
+// TokenSource lets a backend read its API token from a secret helper or a
+// mounted file instead of an environment variable. It is evaluated only
+// when the backend's client is built.
+type TokenSource struct {
+	// TokenCommand is run through the shell; its trimmed stdout is the token,
+	// e.g. "op read op://dev/clickup/token".
+	TokenCommand string `yaml:"token_command,omitempty"`
+	// TokenFile is a file whose trimmed contents are the token.
+	TokenFile string `yaml:"token_file,omitempty"`
+	// UntrustedPath is the project config that set TokenCommand when the
+	// project isn't listed in the user config's trusted_projects. Such a
+	// command is refused instead of run, so cloning a repo can't make
+	// beanup execute it.
+	UntrustedPath string `yaml:"-"`
+}
+
+// HTTPConfig tunes the ClickUp API client (extensions.clickup.http). Unset
+// fields keep the client's defaults.
+type HTTPConfig struct {
+	// Timeout bounds each request attempt, as a Go duration. Empty means
+	// 30s; "0" means no limit.
+	Timeout string `yaml:"timeout,omitempty"`
+	// MaxRetries is how often a rate-limited or transiently failed request
+	// is retried. Unset means 5.
+	MaxRetries *int `yaml:"max_retries,omitempty"`
+	// BackoffBase is the delay before the first retry, doubling for each
+	// one after (up to 30s), as a Go duration. Empty means 1s.
+	BackoffBase string `yaml:"backoff_base,omitempty"`
+	// MaxIdleConns is how many idle keep-alive connections to ClickUp are
+	// kept for reuse. 0 means Go's default of 2.
+	MaxIdleConns int `yaml:"max_idle_conns,omitempty"`
+}
+
+// ReviewRequestConfig configures review requests (extensions.clickup.review_request).
+type ReviewRequestConfig struct {
+	// Status is the bean status whose task gets the request, e.g. "in-review".
+	Status string `yaml:"status"`
+	// Message is the comment text after the mention. Empty means "please review".
+	Message string `yaml:"message,omitempty"`
 }
 
 // BeansConfig represents the beans CLI configuration.
@@ -51,14 +202,183 @@ type BeansConfig struct {
 type beansYMLExtensions struct {
 	Extensions struct {
 		ClickUp ClickUpConfig `yaml:"clickup"`
+		GitHub  *GitHubConfig `yaml:"github"`
+		Jira    *JiraConfig   `yaml:"jira"`
+		Linear  *LinearConfig `yaml:"linear"`
+		GitLab  *GitLabConfig `yaml:"gitlab"`
+		Azure   *AzureConfig  `yaml:"azure"`
+		Notion  *NotionConfig `yaml:"notion"`
 	} `yaml:"extensions"`
 }
 
+// GitHubConfig holds GitHub Issues settings (extensions.github).
+type GitHubConfig struct {
+	// Repo is the target repository as "owner/name".
+	Repo string `yaml:"repo"`
+	// LabelMapping adds labels to issues based on bean status, type, and priority.
+	LabelMapping *LabelMapping `yaml:"label_mapping,omitempty"`
+	// MilestoneMapping maps a bean ID to a GitHub milestone title. Issues for
+	// that bean's direct children are assigned to the milestone.
+	MilestoneMapping map[string]string `yaml:"milestone_mapping,omitempty"`
+	// ClosedStatuses are bean statuses that close the issue.
+	// Defaults to completed and scrapped.
+	ClosedStatuses []string    `yaml:"closed_statuses,omitempty"`
+	SyncFilter     *SyncFilter `yaml:"sync_filter,omitempty"`
+
+	TokenSource `yaml:",inline"`
+}
+
+// GitLabConfig holds GitLab Issues settings (extensions.gitlab).
+type GitLabConfig struct {
+	// URL is the GitLab instance root. Defaults to https://gitlab.com.
+	URL string `yaml:"url,omitempty"`
+	// Project is the project path ("group/name") or numeric ID.
+	Project string `yaml:"project"`
+	// LabelMapping adds labels to issues based on bean status, type, and priority.
+	LabelMapping *LabelMapping `yaml:"label_mapping,omitempty"`
+	// MilestoneMapping maps a bean ID to a milestone title. Issues for that
+	// bean's direct children are assigned to the milestone.
+	MilestoneMapping map[string]string `yaml:"milestone_mapping,omitempty"`
+	// ClosedStatuses are bean statuses that close the issue.
+	// Defaults to completed and scrapped.
+	ClosedStatuses []string    `yaml:"closed_statuses,omitempty"`
+	SyncFilter     *SyncFilter `yaml:"sync_filter,omitempty"`
+
+	TokenSource `yaml:",inline"`
+}
+
+// JiraConfig holds Jira Cloud settings (extensions.jira).
+type JiraConfig struct {
+	// URL is the site root, e.g. https://acme.atlassian.net.
+	URL string `yaml:"url"`
+	// Project is the key of the project issues are created in.
+	Project string `yaml:"project"`
+	// Email is the Atlassian account the API token belongs to.
+	// JIRA_EMAIL overrides it.
+	Email string `yaml:"email,omitempty"`
+	// TypeMapping maps bean types to Jira issue type names.
+	TypeMapping map[string]string `yaml:"type_mapping,omitempty"`
+	// StatusMapping maps bean statuses to Jira status names, reached via
+	// whichever workflow transition leads there.
+	StatusMapping map[string]string `yaml:"status_mapping,omitempty"`
+	// PriorityMapping maps bean priorities to Jira priority names.
+	PriorityMapping map[string]string `yaml:"priority_mapping,omitempty"`
+	// LinkType is the issue link type used for blocking relationships.
+	// Defaults to "Blocks".
+	LinkType   string      `yaml:"link_type,omitempty"`
+	SyncFilter *SyncFilter `yaml:"sync_filter,omitempty"`
+
+	TokenSource `yaml:",inline"`
+}
+
+// AzureConfig holds Azure DevOps Boards settings (extensions.azure).
+type AzureConfig struct {
+	// URL is the service root. Defaults to https://dev.azure.com; set it to
+	// the server URL for Azure DevOps Server.
+	URL string `yaml:"url,omitempty"`
+	// Organization is the Azure DevOps organization (or server collection).
+	Organization string `yaml:"organization"`
+	// Project is the project work items are created in.
+	Project string `yaml:"project"`
+	// TypeMapping maps bean types to work item type names.
+	TypeMapping map[string]string `yaml:"type_mapping,omitempty"`
+	// StatusMapping maps bean statuses to work item states.
+	StatusMapping map[string]string `yaml:"status_mapping,omitempty"`
+	// PriorityMapping maps bean priorities to work item priorities (1-4).
+	PriorityMapping map[string]int `yaml:"priority_mapping,omitempty"`
+	SyncFilter      *SyncFilter    `yaml:"sync_filter,omitempty"`
+
+	TokenSource `yaml:",inline"`
+}
+
+// NotionConfig holds Notion database settings (extensions.notion).
+type NotionConfig struct {
+	// DatabaseID is the database pages are created in.
+	DatabaseID string `yaml:"database_id"`
+	// Properties names the database properties beans are written to.
+	Properties NotionProperties `yaml:"properties,omitempty"`
+	// StatusMapping maps bean statuses to Status property options.
+	StatusMapping map[string]string `yaml:"status_mapping,omitempty"`
+	// PriorityMapping maps bean priorities to Priority property options.
+	// Unmapped priorities are written as-is.
+	PriorityMapping map[string]string `yaml:"priority_mapping,omitempty"`
+	SyncFilter      *SyncFilter       `yaml:"sync_filter,omitempty"`
+
+	TokenSource `yaml:",inline"`
+}
+
+// NotionProperties names the database properties beanup writes. Properties
+// left empty use the defaults; defaults missing from the database are skipped.
+type NotionProperties struct {
+	Title     string `yaml:"title,omitempty"`      // title; defaults to the database's title property
+	Status    string `yaml:"status,omitempty"`     // status or select; default "Status"
+	Priority  string `yaml:"priority,omitempty"`   // select; default "Priority"
+	Type      string `yaml:"type,omitempty"`       // select; default "Type"
+	Tags      string `yaml:"tags,omitempty"`       // multi_select; default "Tags"
+	BeanID    string `yaml:"bean_id,omitempty"`    // rich_text; default "Bean ID"
+	Parent    string `yaml:"parent,omitempty"`     // relation to the same database; default "Parent"
+	BlockedBy string `yaml:"blocked_by,omitempty"` // relation to the same database; default "Blocked by"
+}
+
+// LinearConfig holds Linear settings (extensions.linear).
+type LinearConfig struct {
+	// Team is the key of the team issues are created in, e.g. "ENG".
+	Team string `yaml:"team"`
+	// StatusMapping maps bean statuses to the team's workflow state names.
+	StatusMapping map[string]string `yaml:"status_mapping,omitempty"`
+	// PriorityMapping maps bean priorities to Linear priorities
+	// (0=none, 1=urgent, 2=high, 3=medium, 4=low).
+	PriorityMapping map[string]int `yaml:"priority_mapping,omitempty"`
+	SyncFilter      *SyncFilter    `yaml:"sync_filter,omitempty"`
+
+	TokenSource `yaml:",inline"`
+}
+
+// LabelMapping maps bean fields to label names for label-based trackers.
+type LabelMapping struct {
+	Status   map[string]string `yaml:"status,omitempty"`
+	Type     map[string]string `yaml:"type,omitempty"`
+	Priority map[string]string `yaml:"priority,omitempty"`
+}
+
+// Labels returns the mapped labels for a bean's status, type, and priority.
+func (m *LabelMapping) Labels(status, beanType, priority string) []string {
+	if m == nil {
+		return nil
+	}
+	var labels []string
+	for _, l := range []string{m.Status[status], m.Type[beanType], m.Priority[priority]} {
+		if l != "" {
+			labels = append(labels, l)
+		}
+	}
+	return labels
+}
+
 // CustomFieldsMap maps bean fields to ClickUp custom field UUIDs.
 type CustomFieldsMap struct {
 	BeanID    string `yaml:"bean_id,omitempty"`
 	CreatedAt string `yaml:"created_at,omitempty"`
 	UpdatedAt string `yaml:"updated_at,omitempty"`
+	// SourceURL is a URL field set to a permalink to the bean's file.
+	SourceURL string `yaml:"source_url,omitempty"`
+	// Parent is a relationship field linking the task to its parent bean's
+	// task, in place of making it a subtask.
+	Parent string `yaml:"parent,omitempty"`
+	// Progress is a number or manual progress field set to how complete
+	// the bean is, from its progress value or its body's task list.
+	Progress string `yaml:"progress,omitempty"`
+	// Fields maps keys of a bean's extensions.clickup frontmatter to the
+	// IDs of the custom fields that hold their values.
+	Fields map[string]string `yaml:"fields,omitempty"`
+}
+
+// TagColor is the colors of a space tag, as #rrggbb.
+type TagColor struct {
+	Bg string `yaml:"bg"`
+	// Fg is the text color; black or white, whichever reads better on Bg,
+	// if empty.
+	Fg string `yaml:"fg,omitempty"`
 }
 
 // SyncFilter defines which beans to sync.
@@ -66,6 +386,21 @@ type SyncFilter struct {
 	ExcludeStatus []string `yaml:"exclude_status,omitempty"`
 }
 
+// NotificationsConfig configures chat notifications posted after a sync.
+type NotificationsConfig struct {
+	// WebhookURL is a Slack incoming webhook or Discord webhook URL.
+	WebhookURL string `yaml:"webhook_url"`
+	// Format is "slack" or "discord". Detected from the URL if empty.
+	Format string `yaml:"format,omitempty"`
+	// MinSeverity is the lowest run severity that triggers a notification:
+	// "info" (any change), "warning" (some beans failed), or "error" (sync failed).
+	MinSeverity string `yaml:"min_severity,omitempty"`
+}
+
+// DefaultDescriptionLimit is the default description_limit, kept well
+// under the size at which ClickUp starts cutting descriptions off.
+const DefaultDescriptionLimit = 100000
+
 // DefaultStatusMapping provides standard bean→ClickUp status mapping.
 var DefaultStatusMapping = map[string]string{
 	"draft":       "backlog",
@@ -85,6 +420,79 @@ var DefaultPriorityMapping = map[string]int{
 	"deferred": 4,
 }
 
+// DefaultJiraStatusMapping maps bean statuses to the default Jira workflow.
+var DefaultJiraStatusMapping = map[string]string{
+	"draft":       "To Do",
+	"todo":        "To Do",
+	"in-progress": "In Progress",
+	"completed":   "Done",
+	"scrapped":    "Done",
+}
+
+// DefaultJiraPriorityMapping maps bean priorities to Jira's default priority scheme.
+var DefaultJiraPriorityMapping = map[string]string{
+	"critical": "Highest",
+	"high":     "High",
+	"normal":   "Medium",
+	"low":      "Low",
+	"deferred": "Lowest",
+}
+
+// DefaultJiraTypeMapping maps bean types to default Jira issue types.
+var DefaultJiraTypeMapping = map[string]string{
+	"milestone": "Epic",
+	"epic":      "Epic",
+	"feature":   "Story",
+	"task":      "Task",
+	"bug":       "Bug",
+}
+
+// DefaultLinearStatusMapping maps bean statuses to Linear's default workflow states.
+var DefaultLinearStatusMapping = map[string]string{
+	"draft":       "Backlog",
+	"todo":        "Todo",
+	"in-progress": "In Progress",
+	"completed":   "Done",
+	"scrapped":    "Canceled",
+}
+
+// DefaultLinearPriorityMapping maps bean priorities to Linear priorities.
+var DefaultLinearPriorityMapping = map[string]int{
+	"critical": 1, // Urgent
+	"high":     2, // High
+	"normal":   3, // Medium
+	"low":      4, // Low
+	"deferred": 4, // Low
+}
+
+// DefaultAzureStatusMapping maps bean statuses to the Agile process states.
+var DefaultAzureStatusMapping = map[string]string{
+	"draft":       "New",
+	"todo":        "New",
+	"in-progress": "Active",
+	"completed":   "Closed",
+	"scrapped":    "Removed",
+}
+
+// DefaultAzureTypeMapping maps bean types to Agile process work item types.
+var DefaultAzureTypeMapping = map[string]string{
+	"milestone": "Epic",
+	"epic":      "Epic",
+	"feature":   "Feature",
+	"task":      "Task",
+	"bug":       "Bug",
+}
+
+// DefaultNotionStatusMapping maps bean statuses to the options of a new
+// Notion Status property.
+var DefaultNotionStatusMapping = map[string]string{
+	"draft":       "Not started",
+	"todo":        "Not started",
+	"in-progress": "In progress",
+	"completed":   "Done",
+	"scrapped":    "Done",
+}
+
 // FindConfig searches upward from the given directory for a legacy config file.
 // Returns the absolute path to the config file, or empty string if not found.
 func FindConfig(startDir string) (string, error) {
@@ -109,13 +517,16 @@ func FindConfig(startDir string) (string, error) {
 }
 
 // Load reads configuration from a legacy .beans.clickup.yml file path.
-func Load(configPath string) (*Config, error) {
+// Settings from profile, if not nil, apply where the file doesn't set them.
+func Load(configPath string, profile *Profile) (*Config, error) {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("reading config: %w", err)
 	}
 
 	cfg := &Config{
+		Path:    configPath,
+		Profile: profile,
 		Beans: BeansWrapper{
 			ClickUp: ClickUpConfig{
 				StatusMapping:   DefaultStatusMapping,
@@ -123,36 +534,59 @@ func Load(configPath string) (*Config, error) {
 			},
 		},
 	}
-	if err := yaml.Unmarshal(data, cfg); err != nil {
+	if err := profile.decodeInto(&cfg.Beans); err != nil {
+		return nil, err
+	}
+	if err := decodeExpanded(data, "", cfg); err != nil {
 		return nil, fmt.Errorf("parsing config: %w", err)
 	}
+	warnUnknownKeys(configPath, data)
+	if err := markUntrustedTokenCommands(configPath, data, "beans", &cfg.Beans); err != nil {
+		return nil, err
+	}
 
 	applyDefaults(cfg)
 	return cfg, nil
 }
 
 // LoadFromBeansYML reads ClickUp config from the extensions section of .beans.yml.
-func LoadFromBeansYML(beansYMLPath string) (*Config, error) {
+// Settings from profile, if not nil, apply where the file doesn't set them.
+func LoadFromBeansYML(beansYMLPath string, profile *Profile) (*Config, error) {
 	data, err := os.ReadFile(beansYMLPath)
 	if err != nil {
 		return nil, fmt.Errorf("reading %s: %w", beansYMLPath, err)
 	}
 
 	var ext beansYMLExtensions
-	if err := yaml.Unmarshal(data, &ext); err != nil {
-		return nil, fmt.Errorf("parsing %s: %w", beansYMLPath, err)
+	if err := profile.decodeInto(&ext.Extensions); err != nil {
+		return nil, err
 	}
-
-	// Check if extensions.clickup is actually configured (list_id is the minimum)
-	if ext.Extensions.ClickUp.ListID == "" {
-		return nil, fmt.Errorf("no extensions.clickup section found in %s", beansYMLPath)
+	if err := decodeExpanded(data, "extensions", &ext); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", beansYMLPath, err)
 	}
+	warnUnknownKeys(beansYMLPath, data)
 
 	cfg := &Config{
+		Path:    beansYMLPath,
+		Profile: profile,
 		Beans: BeansWrapper{
 			ClickUp: ext.Extensions.ClickUp,
+			GitHub:  ext.Extensions.GitHub,
+			Jira:    ext.Extensions.Jira,
+			Linear:  ext.Extensions.Linear,
+			GitLab:  ext.Extensions.GitLab,
+			Azure:   ext.Extensions.Azure,
+			Notion:  ext.Extensions.Notion,
 		},
 	}
+	if err := markUntrustedTokenCommands(beansYMLPath, data, "extensions", &cfg.Beans); err != nil {
+		return nil, err
+	}
+
+	// Check that at least one backend is actually configured
+	if len(cfg.Sinks()) == 0 {
+		return nil, fmt.Errorf("no sync backend (extensions.clickup, github, gitlab, jira, linear, azure, or notion) configured in %s", beansYMLPath)
+	}
 
 	applyDefaults(cfg)
 	return cfg, nil
@@ -182,8 +616,8 @@ func applyDefaults(cfg *Config) {
 }
 
 // LoadFromDirectory finds and loads config by searching for .beans.yml extensions
-// first, then falling back to legacy .beans.clickup.yml.
-func LoadFromDirectory(startDir string) (*Config, string, error) {
+// first, then falling back to legacy .beans.clickup.yml. profile may be nil.
+func LoadFromDirectory(startDir string, profile *Profile) (*Config, string, error) {
 	dir, err := filepath.Abs(startDir)
 	if err != nil {
 		return nil, "", err
@@ -192,7 +626,7 @@ func LoadFromDirectory(startDir string) (*Config, string, error) {
 	// First, try .beans.yml extensions section
 	beansYMLPath := findFileUpward(dir, BeansConfigFileName)
 	if beansYMLPath != "" {
-		cfg, err := LoadFromBeansYML(beansYMLPath)
+		cfg, err := LoadFromBeansYML(beansYMLPath, profile)
 		if err == nil {
 			return cfg, filepath.Dir(beansYMLPath), nil
 		}
@@ -202,11 +636,11 @@ func LoadFromDirectory(startDir string) (*Config, string, error) {
 	// Fall back to legacy .beans.clickup.yml
 	legacyPath := findFileUpward(dir, LegacyConfigFileName)
 	if legacyPath == "" {
-		return nil, "", fmt.Errorf("no ClickUp config found (searched for extensions.clickup in %s and %s from %s)",
+		return nil, "", fmt.Errorf("no sync config found (searched for extensions.clickup, github, gitlab, jira, linear, azure, or notion in %s and %s from %s)",
 			BeansConfigFileName, LegacyConfigFileName, startDir)
 	}
 
-	cfg, err := Load(legacyPath)
+	cfg, err := Load(legacyPath, profile)
 	if err != nil {
 		return nil, "", err
 	}
@@ -268,6 +702,66 @@ func LoadBeansPath(startDir string) (string, error) {
 	return beansPath, nil
 }
 
+// Sinks returns the names of the configured sync backends, in a fixed order.
+func (c *Config) Sinks() []string {
+	var names []string
+	if c.Beans.ClickUp.ListID != "" {
+		names = append(names, "clickup")
+	}
+	if c.Beans.GitHub != nil && c.Beans.GitHub.Repo != "" {
+		names = append(names, "github")
+	}
+	if c.Beans.GitLab != nil && c.Beans.GitLab.Project != "" {
+		names = append(names, "gitlab")
+	}
+	if c.Beans.Jira != nil && c.Beans.Jira.URL != "" && c.Beans.Jira.Project != "" {
+		names = append(names, "jira")
+	}
+	if c.Beans.Linear != nil && c.Beans.Linear.Team != "" {
+		names = append(names, "linear")
+	}
+	if c.Beans.Azure != nil && c.Beans.Azure.Organization != "" && c.Beans.Azure.Project != "" {
+		names = append(names, "azure")
+	}
+	if c.Beans.Notion != nil && c.Beans.Notion.DatabaseID != "" {
+		names = append(names, "notion")
+	}
+	return names
+}
+
+// SyncFilterFor returns the sync filter configured for the named backend.
+func (c *Config) SyncFilterFor(sink string) *SyncFilter {
+	switch sink {
+	case "clickup":
+		return c.Beans.ClickUp.SyncFilter
+	case "github":
+		if c.Beans.GitHub != nil {
+			return c.Beans.GitHub.SyncFilter
+		}
+	case "gitlab":
+		if c.Beans.GitLab != nil {
+			return c.Beans.GitLab.SyncFilter
+		}
+	case "jira":
+		if c.Beans.Jira != nil {
+			return c.Beans.Jira.SyncFilter
+		}
+	case "linear":
+		if c.Beans.Linear != nil {
+			return c.Beans.Linear.SyncFilter
+		}
+	case "azure":
+		if c.Beans.Azure != nil {
+			return c.Beans.Azure.SyncFilter
+		}
+	case "notion":
+		if c.Beans.Notion != nil {
+			return c.Beans.Notion.SyncFilter
+		}
+	}
+	return nil
+}
+
 // GetStatusMapping returns the effective status mapping.
 func (c *Config) GetStatusMapping() map[string]string {
 	if c.Beans.ClickUp.StatusMapping != nil {
@@ -276,6 +770,21 @@ func (c *Config) GetStatusMapping() map[string]string {
 	return DefaultStatusMapping
 }
 
+// DefaultStaleAfter is the stale_after used when none is configured.
+const DefaultStaleAfter = 7 * 24 * time.Hour
+
+// GetStaleAfter returns the effective stale_after duration.
+func (c *Config) GetStaleAfter() (time.Duration, error) {
+	if c.Beans.ClickUp.StaleAfter == "" {
+		return DefaultStaleAfter, nil
+	}
+	d, err := time.ParseDuration(c.Beans.ClickUp.StaleAfter)
+	if err != nil {
+		return 0, fmt.Errorf("invalid stale_after %q: %w", c.Beans.ClickUp.StaleAfter, err)
+	}
+	return d, nil
+}
+
 // GetPriorityMapping returns the effective priority mapping.
 func (c *Config) GetPriorityMapping() map[string]int {
 	if c.Beans.ClickUp.PriorityMapping != nil {
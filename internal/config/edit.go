@@ -0,0 +1,410 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Set sets key, a dotted path below extensions such as clickup.assignee,
+// to value in the .beans.yml at path. Only the affected lines are
+// rewritten, so comments and formatting elsewhere in the file are kept.
+// The key must be one beanup knows, and value is checked against its type;
+// lists are given comma-separated.
+func Set(path, key, value string) error {
+	segments, t, err := resolveKey(key)
+	if err != nil {
+		return err
+	}
+	text, err := formatValue(key, value, t)
+	if err != nil {
+		return err
+	}
+	return editFile(path, func(lines []string, root *yaml.Node) ([]string, error) {
+		return setLines(lines, root, append([]string{"extensions"}, segments...), text)
+	})
+}
+
+// Unset removes key, a dotted path below extensions, and anything nested
+// under it from the .beans.yml at path.
+func Unset(path, key string) error {
+	segments, _, err := resolveKey(key)
+	if err != nil {
+		return err
+	}
+	return editFile(path, func(lines []string, root *yaml.Node) ([]string, error) {
+		parent, k, _ := findKey(root, append([]string{"extensions"}, segments...))
+		if k == nil {
+			return nil, fmt.Errorf("%s is not set", key)
+		}
+		if parent.Style&yaml.FlowStyle != 0 {
+			return nil, fmt.Errorf("cannot edit %s: its section is written in flow style ({...})", key)
+		}
+		end := blockEnd(lines, k.Line, k.Column-1)
+		return slices.Delete(lines, k.Line-1, end), nil
+	})
+}
+
+// AppendExtension adds section, YAML text whose first key is "extensions:"
+// (optionally preceded by comments), to the file at path. If the file
+// already has an extensions section, the text under "extensions:" is
+// added to the end of it instead so the key isn't repeated.
+func AppendExtension(path, section string) error {
+	return editFile(path, func(lines []string, root *yaml.Node) ([]string, error) {
+		k, v := (*yaml.Node)(nil), (*yaml.Node)(nil)
+		if root != nil && root.Kind == yaml.MappingNode {
+			k, v = mappingEntry(root, "extensions")
+		}
+		if k == nil {
+			at := len(lines)
+			for at > 0 && strings.TrimSpace(lines[at-1]) == "" {
+				at--
+			}
+			added := strings.Split(strings.TrimRight(section, "\n"), "\n")
+			if at > 0 {
+				added = append([]string{""}, added...)
+			}
+			return append(lines[:at], append(added, "")...), nil
+		}
+		if v.Style&yaml.FlowStyle != 0 && len(v.Content) > 0 {
+			return nil, fmt.Errorf("cannot add to extensions: it is written in flow style ({...})")
+		}
+
+		// Split the section into its leading comments and the body under "extensions:"
+		var header, body []string
+		sectionLines := strings.Split(strings.TrimRight(section, "\n"), "\n")
+		for i, line := range sectionLines {
+			if strings.HasPrefix(line, "extensions:") {
+				body = sectionLines[i+1:]
+				break
+			}
+			header = append(header, line)
+		}
+
+		// Match the file's indentation of extension names
+		indent := 2
+		if v.Kind == yaml.MappingNode && len(v.Content) > 0 {
+			indent = v.Content[0].Column - 1
+		}
+		added := []string{""}
+		for _, line := range header {
+			added = append(added, strings.Repeat(" ", indent)+line)
+		}
+		for _, line := range body {
+			switch {
+			case line == "":
+			case indent > 2:
+				line = strings.Repeat(" ", indent-2) + line
+			case indent < 2:
+				line = line[min(2-indent, len(line)-len(strings.TrimLeft(line, " "))):]
+			}
+			added = append(added, line)
+		}
+
+		at := k.Line
+		if v.Kind == yaml.MappingNode && len(v.Content) > 0 {
+			at = blockEnd(lines, k.Line, k.Column-1)
+		} else {
+			// "extensions:" or "extensions: {}" with nothing under it
+			line := lines[k.Line-1]
+			lines[k.Line-1] = line[:strings.Index(line, ":")+1]
+			added = added[1:]
+		}
+		return slices.Insert(lines, at, added...), nil
+	})
+}
+
+// editFile applies edit to the lines of the YAML file at path and writes
+// the result back, keeping the file's permissions.
+func editFile(path string, edit func(lines []string, root *yaml.Node) ([]string, error)) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	var root *yaml.Node
+	if doc.Kind != 0 {
+		root = doc.Content[0]
+	}
+
+	lines, err := edit(strings.Split(string(data), "\n"), root)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")), info.Mode().Perm())
+}
+
+// setLines sets the value at path to text, replacing an existing scalar in
+// place or inserting the missing keys at the end of the deepest existing
+// section.
+func setLines(lines []string, root *yaml.Node, path []string, text string) ([]string, error) {
+	name := strings.Join(path[1:], ".")
+
+	parentKey, parent := (*yaml.Node)(nil), root
+	for i, seg := range path {
+		if parent == nil || parent.Kind != yaml.MappingNode {
+			return insertLines(lines, parentKey, parent, path[i:], text, name)
+		}
+		if parent.Style&yaml.FlowStyle != 0 && len(parent.Content) > 0 {
+			return nil, fmt.Errorf("cannot edit %s: its section is written in flow style ({...})", name)
+		}
+		k, v := mappingEntry(parent, seg)
+		if k == nil {
+			return insertLines(lines, parentKey, parent, path[i:], text, name)
+		}
+		if i == len(path)-1 {
+			return replaceValue(lines, k, v, text, name)
+		}
+		parentKey, parent = k, v
+	}
+	return lines, nil
+}
+
+// replaceValue replaces the scalar v of key k on its line, keeping any
+// trailing comment.
+func replaceValue(lines []string, k, v *yaml.Node, text, name string) ([]string, error) {
+	if v.Kind == yaml.ScalarNode && v.Tag == "!!null" && v.Value == "" {
+		// "key:" with nothing after it
+		line := lines[k.Line-1]
+		colon := strings.Index(line[k.Column-1:], ":") + k.Column - 1
+		lines[k.Line-1] = line[:colon+1] + " " + text + line[colon+1:]
+		return lines, nil
+	}
+	if v.Kind == yaml.SequenceNode && v.Style&yaml.FlowStyle != 0 && v.Line == k.Line {
+		line := lines[v.Line-1]
+		end := strings.LastIndex(line, "]")
+		if end < 0 {
+			return nil, fmt.Errorf("cannot edit %s: list spans several lines", name)
+		}
+		lines[v.Line-1] = line[:v.Column-1] + text + line[end+1:]
+		return lines, nil
+	}
+	if v.Kind == yaml.SequenceNode {
+		// Block list: replace it with a flow list on the key's line
+		end := blockEnd(lines, k.Line, k.Column-1)
+		lines = slices.Delete(lines, k.Line, end)
+		line := lines[k.Line-1]
+		colon := strings.Index(line[k.Column-1:], ":") + k.Column - 1
+		lines[k.Line-1] = line[:colon+1] + " " + text
+		return lines, nil
+	}
+	if v.Kind != yaml.ScalarNode {
+		return nil, fmt.Errorf("%s is a section; set one of its keys instead", name)
+	}
+	if v.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		return nil, fmt.Errorf("cannot edit %s: it is a multi-line value", name)
+	}
+
+	line := lines[v.Line-1]
+	start := v.Column - 1
+	end := scalarEnd(line, start, v.Style)
+	lines[v.Line-1] = line[:start] + text + line[end:]
+	return lines, nil
+}
+
+// scalarEnd returns the index just past a single-line scalar starting at
+// start on line.
+func scalarEnd(line string, start int, style yaml.Style) int {
+	switch {
+	case style&yaml.DoubleQuotedStyle != 0:
+		for i := start + 1; i < len(line); i++ {
+			if line[i] == '\\' {
+				i++
+			} else if line[i] == '"' {
+				return i + 1
+			}
+		}
+	case style&yaml.SingleQuotedStyle != 0:
+		for i := start + 1; i < len(line); i++ {
+			if line[i] == '\'' {
+				if i+1 < len(line) && line[i+1] == '\'' {
+					i++
+					continue
+				}
+				return i + 1
+			}
+		}
+	default:
+		rest := line[start:]
+		if i := strings.Index(rest, " #"); i >= 0 {
+			rest = rest[:i]
+		}
+		return start + len(strings.TrimRight(rest, " \t"))
+	}
+	return len(line)
+}
+
+// insertLines adds the keys in missing, nested, with the last set to text,
+// at the end of the section parent (whose key is parentKey, or the top
+// level when parentKey is nil).
+func insertLines(lines []string, parentKey, parent *yaml.Node, missing []string, text, name string) ([]string, error) {
+	var indent, at int
+	step := 2
+	switch {
+	case parentKey == nil:
+		// Top level: append to the end of the file
+		at = len(lines)
+		for at > 0 && strings.TrimSpace(lines[at-1]) == "" {
+			at--
+		}
+	case parent.Kind == yaml.MappingNode && len(parent.Content) > 0:
+		indent = parent.Content[0].Column - 1
+		step = max(indent-(parentKey.Column-1), 1)
+		at = blockEnd(lines, parentKey.Line, parentKey.Column-1)
+	case parent.Kind == yaml.MappingNode || (parent.Kind == yaml.ScalarNode && parent.Tag == "!!null" && parent.Value == ""):
+		// "key:" or "key: {}" with nothing under it
+		if parent.Kind == yaml.MappingNode {
+			line := lines[parentKey.Line-1]
+			colon := strings.Index(line[parentKey.Column-1:], ":") + parentKey.Column - 1
+			lines[parentKey.Line-1] = line[:colon+1]
+		}
+		indent = parentKey.Column - 1 + step
+		at = parentKey.Line
+	default:
+		return nil, fmt.Errorf("cannot set %s: %s is not a section", name, parentKey.Value)
+	}
+
+	added := make([]string, len(missing))
+	for i, seg := range missing {
+		added[i] = strings.Repeat(" ", indent+i*step) + formatScalar(seg) + ":"
+	}
+	added[len(added)-1] += " " + text
+	return slices.Insert(lines, at, added...), nil
+}
+
+// blockEnd returns the index of the first line after the block opened by
+// the key on keyLine (1-based) at the given indentation: the key's line and
+// every following line indented deeper, ignoring trailing blank lines.
+func blockEnd(lines []string, keyLine, indent int) int {
+	end := keyLine
+	for j := keyLine; j < len(lines); j++ {
+		trimmed := strings.TrimLeft(lines[j], " ")
+		if trimmed == "" {
+			continue
+		}
+		if len(lines[j])-len(trimmed) <= indent {
+			break
+		}
+		end = j + 1
+	}
+	return end
+}
+
+// findKey walks path through nested mappings from root, returning the
+// last key's mapping, key node, and value, or nil if any key is missing.
+func findKey(root *yaml.Node, path []string) (parent, key, value *yaml.Node) {
+	node := root
+	for _, seg := range path {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil, nil, nil
+		}
+		parent = node
+		key, node = mappingEntry(node, seg)
+	}
+	return parent, key, node
+}
+
+// mappingEntry returns the key and value nodes for key in a mapping.
+func mappingEntry(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// resolveKey splits a dotted key below extensions and returns the Go type
+// of the value it names. Keys inside mappings such as status_mapping may
+// be anything.
+func resolveKey(key string) ([]string, reflect.Type, error) {
+	segments := strings.Split(strings.TrimPrefix(key, "extensions."), ".")
+	t := reflect.TypeFor[beansYMLExtensions]().Field(0).Type
+	for i, seg := range segments {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if seg == "" {
+			return nil, nil, fmt.Errorf("invalid key %q", key)
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := yamlFields(t)
+			ft, ok := fields[seg]
+			if !ok {
+				section := "extensions"
+				if i > 0 {
+					section = strings.Join(segments[:i], ".")
+				}
+				msg := fmt.Sprintf("unknown key %q in %s", seg, section)
+				if s := closestKey(seg, fields); s != "" {
+					msg += fmt.Sprintf(" (did you mean %q?)", s)
+				}
+				return nil, nil, fmt.Errorf("%s", msg)
+			}
+			t = ft
+		case reflect.Map:
+			t = t.Elem()
+		default:
+			return nil, nil, fmt.Errorf("%s has no key %q", strings.Join(segments[:i], "."), seg)
+		}
+	}
+	return segments, t, nil
+}
+
+// formatValue renders value as YAML for a field of type t.
+func formatValue(key, value string, t reflect.Type) (string, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int64:
+		if _, err := strconv.Atoi(value); err != nil {
+			return "", fmt.Errorf("%s must be a number, got %q", key, value)
+		}
+		return value, nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("%s must be true or false, got %q", key, value)
+		}
+		return strconv.FormatBool(b), nil
+	case reflect.String:
+		if strings.Contains(value, "\n") {
+			return "", fmt.Errorf("%s must be a single line", key)
+		}
+		return formatScalar(value), nil
+	case reflect.Slice:
+		var items []string
+		for item := range strings.SplitSeq(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, formatScalar(item))
+			}
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	default:
+		return "", fmt.Errorf("%s is a section; set one of its keys instead", key)
+	}
+}
+
+// formatScalar renders s as a YAML string, quoting it only when it would
+// otherwise read as another type or break the syntax.
+func formatScalar(s string) string {
+	out, err := yaml.Marshal(s)
+	if err != nil {
+		return strconv.Quote(s)
+	}
+	return strings.TrimSuffix(string(out), "\n")
+}
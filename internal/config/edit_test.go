@@ -0,0 +1,126 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const editFixture = `# Project config
+beans:
+  path: .beans
+extensions:
+  clickup:
+    list_id: "123" # staging list
+    # who new tasks go to
+    assignee: 7
+    status_mapping:
+      todo: "to do"
+    sync_filter:
+      exclude_status:
+        - scrapped
+
+  other_tool:
+    keep: true
+`
+
+func TestSetAndUnset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), BeansConfigFileName)
+	if err := os.WriteFile(path, []byte(editFixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, kv := range [][2]string{
+		{"clickup.assignee", "12345"},
+		{"clickup.list_id", "456"},
+		{"clickup.status_mapping.in-progress", "doing"},
+		{"clickup.sync_filter.exclude_status", "scrapped, completed"},
+		{"clickup.custom_fields.bean_id", "cf-1"},
+		{"github.repo", "acme/widgets"},
+	} {
+		if err := Set(path, kv[0], kv[1]); err != nil {
+			t.Fatalf("Set(%s): %v", kv[0], err)
+		}
+	}
+	if err := Unset(path, "clickup.status_mapping"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(path)
+	want := `# Project config
+beans:
+  path: .beans
+extensions:
+  clickup:
+    list_id: "456" # staging list
+    # who new tasks go to
+    assignee: 12345
+    sync_filter:
+      exclude_status: [scrapped, completed]
+    custom_fields:
+      bean_id: cf-1
+
+  other_tool:
+    keep: true
+  github:
+    repo: acme/widgets
+`
+	if string(data) != want {
+		t.Errorf("edited file:\n%s\nwant:\n%s", data, want)
+	}
+
+	cfg, err := LoadFromBeansYML(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *cfg.Beans.ClickUp.Assignee != 12345 || cfg.Beans.ClickUp.ListID != "456" || cfg.Beans.GitHub.Repo != "acme/widgets" {
+		t.Errorf("reloaded config = %+v", cfg.Beans)
+	}
+}
+
+func TestSet_Rejects(t *testing.T) {
+	path := filepath.Join(t.TempDir(), BeansConfigFileName)
+	if err := os.WriteFile(path, []byte(editFixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range map[string]string{
+		"clickup.staus_mapping.todo": "x",
+		"clickup.assignee":           "bob",
+		"clickup.status_mapping":     "x",
+		"nope.key":                   "x",
+	} {
+		if err := Set(path, key, value); err == nil {
+			t.Errorf("Set(%s, %s) should fail", key, value)
+		}
+	}
+	if err := Unset(path, "clickup.type_mapping"); err == nil || !strings.Contains(err.Error(), "not set") {
+		t.Errorf("Unset of missing key: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != editFixture {
+		t.Error("failed edits should leave the file alone")
+	}
+}
+
+func TestAppendExtension(t *testing.T) {
+	section := "# Generated\nextensions:\n  clickup:\n    list_id: \"1\"\n"
+	for _, tc := range []struct{ name, before, after string }{
+		{"no extensions", "beans:\n  path: .beans\n", "beans:\n  path: .beans\n\n# Generated\nextensions:\n  clickup:\n    list_id: \"1\"\n"},
+		{"other extension", "extensions:\n    github:\n        repo: a/b\nbeans:\n  path: .beans\n", "extensions:\n    github:\n        repo: a/b\n\n    # Generated\n    clickup:\n      list_id: \"1\"\nbeans:\n  path: .beans\n"},
+		{"empty extensions", "extensions:\n", "extensions:\n  # Generated\n  clickup:\n    list_id: \"1\"\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), BeansConfigFileName)
+			if err := os.WriteFile(path, []byte(tc.before), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := AppendExtension(path, section); err != nil {
+				t.Fatal(err)
+			}
+			data, _ := os.ReadFile(path)
+			if string(data) != tc.after {
+				t.Errorf("got:\n%s\nwant:\n%s", data, tc.after)
+			}
+		})
+	}
+}
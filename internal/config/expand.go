@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envRef matches ${VAR} and ${VAR:-default} references in config values.
+var envRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-[^}]*)?\}`)
+
+// expandEnv replaces ${VAR} references in every scalar value under node
+// with the environment variable's value, so one committed config can point
+// at different lists per environment. ${VAR:-default} falls back to default
+// when VAR is unset or empty; any other unset variable is an error. Keys
+// are left alone.
+func expandEnv(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if !strings.Contains(node.Value, "${") {
+			return nil
+		}
+		var missing []string
+		node.Value = envRef.ReplaceAllStringFunc(node.Value, func(ref string) string {
+			m := envRef.FindStringSubmatch(ref)
+			if v := os.Getenv(m[1]); v != "" {
+				return v
+			}
+			if m[2] != "" {
+				return m[2][2:]
+			}
+			missing = append(missing, m[1])
+			return ""
+		})
+		if len(missing) > 0 {
+			return fmt.Errorf("line %d: environment variable %s is not set", node.Line, strings.Join(missing, ", "))
+		}
+		// Let the expanded value resolve as a number or bool again, e.g. assignee: ${ASSIGNEE}
+		node.Tag = ""
+		node.Style = 0
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := expandEnv(node.Content[i]); err != nil {
+				return err
+			}
+		}
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, n := range node.Content {
+			if err := expandEnv(n); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// decodeExpanded parses data, expands ${VAR} references under the
+// top-level key (or the whole document when key is empty), and decodes
+// the result into out.
+func decodeExpanded(data []byte, key string, out any) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if doc.Kind == 0 {
+		return nil
+	}
+	target := &doc
+	if key != "" {
+		target = mappingValue(doc.Content[0], key)
+	}
+	if target != nil {
+		if err := expandEnv(target); err != nil {
+			return err
+		}
+	}
+	return doc.Decode(out)
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadFromBeansYML_ExpandsEnv(t *testing.T) {
+	t.Setenv("BEANUP_TEST_LIST", "0901")
+	t.Setenv("BEANUP_TEST_ASSIGNEE", "42")
+	t.Setenv("BEANUP_TEST_FIELD", "")
+
+	path := filepath.Join(t.TempDir(), BeansConfigFileName)
+	if err := os.WriteFile(path, []byte(`
+beans:
+  path: ${NOT_EXPANDED}
+extensions:
+  clickup:
+    list_id: "${BEANUP_TEST_LIST}"
+    assignee: ${BEANUP_TEST_ASSIGNEE}
+    custom_fields:
+      bean_id: "${BEANUP_TEST_FIELD:-cf-default}"
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFromBeansYML(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cu := cfg.Beans.ClickUp
+	if cu.ListID != "0901" {
+		t.Errorf("list_id = %q, want 0901", cu.ListID)
+	}
+	if cu.Assignee == nil || *cu.Assignee != 42 {
+		t.Errorf("assignee = %v, want 42", cu.Assignee)
+	}
+	if cu.CustomFields == nil || cu.CustomFields.BeanID != "cf-default" {
+		t.Errorf("custom_fields = %+v, want default", cu.CustomFields)
+	}
+
+	t.Setenv("BEANUP_TEST_LIST", "")
+	if _, err := LoadFromBeansYML(path, nil); err == nil || !strings.Contains(err.Error(), "BEANUP_TEST_LIST") {
+		t.Errorf("expected error naming the unset variable, got %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnknownKey is a config key beanup doesn't recognize, usually a typo such
+// as staus_mapping that would otherwise be silently ignored.
+type UnknownKey struct {
+	File   string
+	Line   int
+	Column int
+	// Section is the dotted path of the enclosing mapping, e.g. extensions.clickup.
+	Section string
+	Key     string
+	// Suggestion is the closest known key, if any is near enough.
+	Suggestion string
+}
+
+func (u UnknownKey) String() string {
+	msg := fmt.Sprintf("%s:%d:%d: unknown key %q in %s", u.File, u.Line, u.Column, u.Key, u.Section)
+	if u.Suggestion != "" {
+		msg += fmt.Sprintf(" (did you mean %q?)", u.Suggestion)
+	}
+	return msg
+}
+
+// FindConfigFiles returns the .beans.yml and legacy .beans.clickup.yml
+// files found searching upward from startDir.
+func FindConfigFiles(startDir string) ([]string, error) {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, name := range []string{BeansConfigFileName, LegacyConfigFileName} {
+		if path := findFileUpward(dir, name); path != "" {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// Lint reports unknown keys in a config file. In .beans.yml only the
+// sections of the backends beanup owns are checked, since other tools
+// keep their own settings under extensions; a legacy .beans.clickup.yml
+// is checked in full.
+func Lint(path string) ([]UnknownKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return lintData(path, data)
+}
+
+func lintData(path string, data []byte) ([]UnknownKey, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		return nil, nil
+	}
+
+	var found []UnknownKey
+	if filepath.Base(path) == LegacyConfigFileName {
+		lintNode(path, doc.Content[0], reflect.TypeFor[Config](), "the top level", &found)
+		return found, nil
+	}
+
+	extensions := mappingValue(doc.Content[0], "extensions")
+	if extensions == nil || extensions.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	backends := yamlFields(reflect.TypeFor[beansYMLExtensions]().Field(0).Type)
+	for i := 0; i+1 < len(extensions.Content); i += 2 {
+		name := extensions.Content[i].Value
+		if t, ok := backends[name]; ok {
+			lintNode(path, extensions.Content[i+1], t, "extensions."+name, &found)
+		}
+	}
+	return found, nil
+}
+
+// warnUnknownKeys logs a warning for each unknown key in a config file
+// being loaded.
+func warnUnknownKeys(path string, data []byte) {
+	found, err := lintData(path, data)
+	if err != nil {
+		return
+	}
+	for _, u := range found {
+		log.Printf("Warning: %s", u)
+	}
+}
+
+// lintNode checks node's mapping keys against the yaml fields of t,
+// recursing into nested structs, maps, and lists.
+func lintNode(file string, node *yaml.Node, t reflect.Type, section string, found *[]UnknownKey) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if key.Value == "<<" {
+				continue
+			}
+			ft, ok := fields[key.Value]
+			if !ok {
+				*found = append(*found, UnknownKey{
+					File:       file,
+					Line:       key.Line,
+					Column:     key.Column,
+					Section:    section,
+					Key:        key.Value,
+					Suggestion: closestKey(key.Value, fields),
+				})
+				continue
+			}
+			lintNode(file, node.Content[i+1], ft, section+"."+key.Value, found)
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			lintNode(file, node.Content[i+1], t.Elem(), section+"."+node.Content[i].Value, found)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return
+		}
+		for _, item := range node.Content {
+			lintNode(file, item, t.Elem(), section, found)
+		}
+	}
+}
+
+// yamlFields maps the yaml keys of struct type t to their field types,
+// flattening inline fields.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(opts, "inline") {
+			for k, v := range yamlFields(f.Type) {
+				fields[k] = v
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// mappingValue returns the value of key in a mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// closestKey returns the known key within a small edit distance of key.
+func closestKey(key string, fields map[string]reflect.Type) string {
+	best, bestDist := "", max(2, len(key)/3)+1
+	for name := range fields {
+		if d := editDistance(key, name); d < bestDist || (d == bestDist && name < best) {
+			best, bestDist = name, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintData(t *testing.T) {
+	data := []byte(`beans:
+  path: .beans
+extensions:
+  other_tool:
+    whatever: true
+  clickup:
+    list_id: "123"
+    staus_mapping:
+      todo: "to do"
+    token_file: ~/token
+    sync_filter:
+      exclude_status: [completed]
+      exclude_statuses: [scrapped]
+  github:
+    repo: acme/widgets
+    label_mapping:
+      priority: {high: p1}
+      colour: {}
+`)
+	found, err := lintData(".beans.yml", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, u := range found {
+		got = append(got, u.String())
+	}
+	want := []string{
+		`.beans.yml:8:5: unknown key "staus_mapping" in extensions.clickup (did you mean "status_mapping"?)`,
+		`.beans.yml:13:7: unknown key "exclude_statuses" in extensions.clickup.sync_filter (did you mean "exclude_status"?)`,
+		`.beans.yml:18:7: unknown key "colour" in extensions.github.label_mapping`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("lintData() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLintData_Legacy(t *testing.T) {
+	found, err := lintData(LegacyConfigFileName, []byte("beans:\n  clikup:\n    list_id: \"1\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Key != "clikup" || found[0].Suggestion != "clickup" {
+		t.Errorf("lintData() = %+v", found)
+	}
+}
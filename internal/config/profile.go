@@ -0,0 +1,145 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UserConfig is the per-user beanup config holding named profiles, for
+// people who work across several workspaces:
+//
+//	default_profile: work
+//	profiles:
+//	  work:
+//	    env:
+//	      CLICKUP_TOKEN: pk_...
+//	    clickup:
+//	      assignee: 42
+//	      status_mapping: {...}
+type UserConfig struct {
+	DefaultProfile string               `yaml:"default_profile,omitempty"`
+	Profiles       map[string]yaml.Node `yaml:"profiles,omitempty"`
+	// TrustedProjects are project directories whose config may set
+	// token_command. Elsewhere only profiles may.
+	TrustedProjects []string `yaml:"trusted_projects,omitempty"`
+}
+
+// Profile is one named profile. Its backend sections have the same shape
+// as the .beans.yml extensions section and are defaults the project
+// config overrides key by key.
+type Profile struct {
+	Name string
+	// Env is exported into the process environment when the profile is
+	// applied, so tokens can differ per profile. Variables already set
+	// are kept.
+	Env map[string]string `yaml:"env,omitempty"`
+
+	sections yaml.Node
+}
+
+// UserConfigPath returns the path of the user config file:
+// beanup/config.yml under os.UserConfigDir (~/.config on Linux).
+func UserConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("finding user config directory: %w", err)
+	}
+	return filepath.Join(dir, "beanup", "config.yml"), nil
+}
+
+// LoadUserConfig reads the user config. It returns nil if there is none.
+func LoadUserConfig() (*UserConfig, error) {
+	path, err := UserConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	var uc UserConfig
+	if err := yaml.Unmarshal(data, &uc); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &uc, nil
+}
+
+// LoadProfile returns the named profile from the user config, or its
+// default_profile when name is empty. It returns nil when no profile is
+// requested and there is no default.
+func LoadProfile(name string) (*Profile, error) {
+	uc, err := LoadUserConfig()
+	if err != nil {
+		return nil, err
+	}
+	if uc == nil {
+		if name != "" {
+			path, _ := UserConfigPath()
+			return nil, fmt.Errorf("profile %q not found: %s does not exist", name, path)
+		}
+		return nil, nil
+	}
+	return uc.Profile(name)
+}
+
+// Profile returns the named profile, or the default profile when name is
+// empty.
+func (uc *UserConfig) Profile(name string) (*Profile, error) {
+	if name == "" {
+		name = uc.DefaultProfile
+	}
+	if name == "" {
+		return nil, nil
+	}
+	node, ok := uc.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(uc.Profiles))
+		for n := range uc.Profiles {
+			names = append(names, n)
+		}
+		slices.Sort(names)
+		return nil, fmt.Errorf("profile %q not found (available: %s)", name, strings.Join(names, ", "))
+	}
+	p := &Profile{Name: name, sections: node}
+	if err := node.Decode(p); err != nil {
+		return nil, fmt.Errorf("parsing profile %q: %w", name, err)
+	}
+	return p, nil
+}
+
+// ApplyEnv sets the profile's environment variables for this process.
+// Variables already in the environment win, so a one-off
+// CLICKUP_TOKEN=... beanup sync --profile work uses the exported token.
+func (p *Profile) ApplyEnv() error {
+	for k, v := range p.Env {
+		if _, set := os.LookupEnv(k); set {
+			continue
+		}
+		if err := os.Setenv(k, v); err != nil {
+			return fmt.Errorf("profile %q: setting %s: %w", p.Name, k, err)
+		}
+	}
+	return nil
+}
+
+// decodeInto fills out with the profile's backend sections. Decoding the
+// project config into the same value afterwards overrides them.
+func (p *Profile) decodeInto(out any) error {
+	if p == nil || p.sections.Kind == 0 {
+		return nil
+	}
+	if err := p.sections.Decode(out); err != nil {
+		return fmt.Errorf("parsing profile %q: %w", p.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeUserConfig(t *testing.T, content string) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("AppData", dir)
+	path, err := UserConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadProfile_MergesUnderProjectConfig(t *testing.T) {
+	writeUserConfig(t, `
+default_profile: personal
+profiles:
+  personal:
+    clickup:
+      list_id: "personal-list"
+  work:
+    env:
+      BEANUP_TEST_TOKEN: pk_work
+    clickup:
+      list_id: "work-list"
+      assignee: 42
+      status_mapping:
+        todo: "backlog"
+        completed: "shipped"
+`)
+
+	project := filepath.Join(t.TempDir(), BeansConfigFileName)
+	if err := os.WriteFile(project, []byte(`
+extensions:
+  clickup:
+    list_id: "project-list"
+    status_mapping:
+      completed: "done"
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadProfile("work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFromBeansYML(project, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cu := cfg.Beans.ClickUp
+	if cu.ListID != "project-list" {
+		t.Errorf("list_id = %q, want project value", cu.ListID)
+	}
+	if cu.Assignee == nil || *cu.Assignee != 42 {
+		t.Errorf("assignee = %v, want 42 from profile", cu.Assignee)
+	}
+	if cu.StatusMapping["todo"] != "backlog" || cu.StatusMapping["completed"] != "done" {
+		t.Errorf("status_mapping = %v, want profile keys overridden by project", cu.StatusMapping)
+	}
+
+	// An exported variable beats the profile
+	t.Setenv("BEANUP_TEST_TOKEN", "pk_shell")
+	if err := p.ApplyEnv(); err != nil || os.Getenv("BEANUP_TEST_TOKEN") != "pk_shell" {
+		t.Errorf("ApplyEnv() = %v, env %q; want the exported value kept", err, os.Getenv("BEANUP_TEST_TOKEN"))
+	}
+	if err := os.Unsetenv("BEANUP_TEST_TOKEN"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.ApplyEnv(); err != nil || os.Getenv("BEANUP_TEST_TOKEN") != "pk_work" {
+		t.Errorf("ApplyEnv() = %v, env %q", err, os.Getenv("BEANUP_TEST_TOKEN"))
+	}
+
+	if p, err := LoadProfile(""); err != nil || p == nil || p.Name != "personal" {
+		t.Errorf("LoadProfile(\"\") = %v, %v; want default profile", p, err)
+	}
+	if _, err := LoadProfile("missing"); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+}
+
+func TestLoadProfile_NoUserConfig(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	if p, err := LoadProfile(""); p != nil || err != nil {
+		t.Errorf("LoadProfile(\"\") = %v, %v; want nil, nil", p, err)
+	}
+	if _, err := LoadProfile("work"); err == nil {
+		t.Error("expected error when a profile is requested without a user config")
+	}
+}
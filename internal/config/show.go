@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// secretKeys are config keys whose values are redacted by Explain.
+var secretKeys = map[string]bool{
+	"webhook_url":    true,
+	"webhook_secret": true,
+}
+
+// Redacted replaces secret values in Explain output.
+const Redacted = "[redacted]"
+
+// Explain returns the effective settings of the configured backends as a
+// mapping node keyed by backend, with each value's line comment naming
+// where it came from: the project file (and line), the user profile, or a
+// built-in default. It also returns the same sources keyed by dotted path,
+// e.g. "clickup.list_id". Secret values are redacted.
+func (c *Config) Explain() (*yaml.Node, map[string]string, error) {
+	var effective yaml.Node
+	if err := effective.Encode(c.Beans); err != nil {
+		return nil, nil, fmt.Errorf("encoding config: %w", err)
+	}
+
+	// Only show the backends actually configured
+	configured := c.Sinks()
+	shown := &yaml.Node{Kind: yaml.MappingNode}
+	for i := 0; i+1 < len(effective.Content); i += 2 {
+		if slices.Contains(configured, effective.Content[i].Value) {
+			shown.Content = append(shown.Content, effective.Content[i], effective.Content[i+1])
+		}
+	}
+
+	project, err := c.projectSections()
+	if err != nil {
+		return nil, nil, err
+	}
+	var profile *yaml.Node
+	if c.Profile != nil && c.Profile.sections.Kind == yaml.MappingNode {
+		profile = &c.Profile.sections
+	}
+
+	sources := make(map[string]string)
+	annotate(shown, nil, func(path []string, value *yaml.Node) {
+		if secretKeys[path[len(path)-1]] && value.Kind == yaml.ScalarNode && value.Value != "" {
+			value.Value = Redacted
+			value.Tag = "!!str"
+			value.Style = 0
+		}
+		source := "default"
+		if n := lookup(project, path); n != nil {
+			source = fmt.Sprintf("%s:%d", filepath.Base(c.Path), n.Line)
+			if strings.Contains(n.Value, "${") {
+				source += " (" + n.Value + ")"
+			}
+		} else if n := lookup(profile, path); n != nil {
+			source = fmt.Sprintf("profile %s", c.Profile.Name)
+		}
+		value.LineComment = source
+		sources[strings.Join(path, ".")] = source
+	})
+	return shown, sources, nil
+}
+
+// projectSections returns the unexpanded backend sections of the file the
+// config was loaded from, keyed like BeansWrapper.
+func (c *Config) projectSections() (*yaml.Node, error) {
+	if c.Path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(c.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", c.Path, err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", c.Path, err)
+	}
+	if doc.Kind == 0 {
+		return nil, nil
+	}
+	key := "extensions"
+	if filepath.Base(c.Path) == LegacyConfigFileName {
+		key = "beans"
+	}
+	return mappingValue(doc.Content[0], key), nil
+}
+
+// annotate calls fn for every leaf value under node with its key path.
+// Lists are treated as single values.
+func annotate(node *yaml.Node, path []string, fn func(path []string, value *yaml.Node)) {
+	if node.Kind != yaml.MappingNode {
+		if len(path) > 0 {
+			fn(path, node)
+		}
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		annotate(node.Content[i+1], append(slices.Clone(path), node.Content[i].Value), fn)
+	}
+}
+
+// lookup follows path through nested mappings, returning nil if any key is missing.
+func lookup(node *yaml.Node, path []string) *yaml.Node {
+	for _, key := range path {
+		if node == nil {
+			return nil
+		}
+		if node.Kind == yaml.AliasNode {
+			node = node.Alias
+		}
+		node = mappingValue(node, key)
+	}
+	return node
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	writeUserConfig(t, `
+profiles:
+  work:
+    clickup:
+      assignee: 42
+`)
+	project := filepath.Join(t.TempDir(), BeansConfigFileName)
+	if err := os.WriteFile(project, []byte(`extensions:
+  clickup:
+    list_id: "123"
+    notifications:
+      webhook_url: https://hooks.slack.com/services/secret
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadProfile("work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFromBeansYML(project, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, sources, err := cfg.Explain()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]string{
+		"clickup.list_id":                   ".beans.yml:3",
+		"clickup.assignee":                  "profile work",
+		"clickup.status_mapping.todo":       "default",
+		"clickup.notifications.webhook_url": ".beans.yml:5",
+	} {
+		if sources[path] != want {
+			t.Errorf("source of %s = %q, want %q", path, sources[path], want)
+		}
+	}
+	if _, ok := sources["github.repo"]; ok {
+		t.Error("unconfigured backend should not be shown")
+	}
+	if v := lookup(node, []string{"clickup", "notifications", "webhook_url"}); v == nil || v.Value != Redacted {
+		t.Errorf("webhook_url = %v, want redacted", v)
+	}
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// markUntrustedTokenCommands sets UntrustedPath on every backend whose
+// token_command comes from the project file at path, unless the user
+// config trusts the project. key is the mapping that holds the backend
+// sections: extensions in .beans.yml, beans in the legacy file.
+func markUntrustedTokenCommands(path string, data []byte, key string, b *BeansWrapper) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || doc.Kind == 0 {
+		return nil
+	}
+	sections := mappingValue(doc.Content[0], key)
+	if sections == nil {
+		return nil
+	}
+	var untrusted []*TokenSource
+	for name, src := range b.tokenSources() {
+		if section := mappingValue(sections, name); section != nil && mappingValue(section, "token_command") != nil {
+			untrusted = append(untrusted, src)
+		}
+	}
+	if len(untrusted) == 0 {
+		return nil
+	}
+	uc, err := LoadUserConfig()
+	if err != nil {
+		return err
+	}
+	if uc.Trusts(filepath.Dir(path)) {
+		return nil
+	}
+	for _, src := range untrusted {
+		src.UntrustedPath = path
+	}
+	return nil
+}
+
+// tokenSources returns the token settings of each configured backend by
+// section name.
+func (b *BeansWrapper) tokenSources() map[string]*TokenSource {
+	sources := map[string]*TokenSource{"clickup": &b.ClickUp.TokenSource}
+	if b.GitHub != nil {
+		sources["github"] = &b.GitHub.TokenSource
+	}
+	if b.Jira != nil {
+		sources["jira"] = &b.Jira.TokenSource
+	}
+	if b.Linear != nil {
+		sources["linear"] = &b.Linear.TokenSource
+	}
+	if b.GitLab != nil {
+		sources["gitlab"] = &b.GitLab.TokenSource
+	}
+	if b.Azure != nil {
+		sources["azure"] = &b.Azure.TokenSource
+	}
+	if b.Notion != nil {
+		sources["notion"] = &b.Notion.TokenSource
+	}
+	return sources
+}
+
+// Trusts reports whether dir is one of the user's trusted_projects. A nil
+// UserConfig trusts nothing.
+func (uc *UserConfig) Trusts(dir string) bool {
+	if uc == nil {
+		return false
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	for _, p := range uc.TrustedProjects {
+		if rest, ok := strings.CutPrefix(p, "~/"); ok {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				continue
+			}
+			p = filepath.Join(home, rest)
+		}
+		if abs, err := filepath.Abs(p); err == nil && abs == dir {
+			return true
+		}
+	}
+	return false
+}
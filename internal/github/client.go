@@ -0,0 +1,119 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/toba/bean-me-up/internal/restapi"
+)
+
+// DefaultBaseURL is the GitHub REST API endpoint.
+const DefaultBaseURL = "https://api.github.com"
+
+// HTTPDoer sends HTTP requests. *http.Client satisfies it.
+type HTTPDoer = restapi.HTTPDoer
+
+// Client provides GitHub Issues access for a single repository.
+type Client struct {
+	api  *restapi.Client
+	repo string // "owner/name"
+}
+
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the transport used for API requests.
+func WithHTTPClient(doer HTTPDoer) ClientOption {
+	return func(c *Client) { c.api.HTTP = doer }
+}
+
+// WithBaseURL points the client at a different API root, such as GitHub Enterprise.
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) { c.api.BaseURL = strings.TrimRight(url, "/") }
+}
+
+// NewClient creates a client for repo ("owner/name") authenticated with token.
+func NewClient(token, repo string, opts ...ClientOption) (*Client, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid GitHub repo %q (expected owner/name)", repo)
+	}
+
+	api := restapi.New("github", DefaultBaseURL)
+	api.Authorize = func(req *http.Request) {
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	// GitHub signals an exhausted primary rate limit with 403
+	api.RateLimited = func(resp *http.Response) bool {
+		return resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0"
+	}
+	api.ErrorMessage = func(body []byte) string {
+		var errResp errorResponse
+		_ = json.Unmarshal(body, &errResp)
+		return errResp.Message
+	}
+
+	c := &Client{api: api, repo: repo}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// GetIssue fetches an issue by number.
+func (c *Client) GetIssue(ctx context.Context, number int) (*Issue, error) {
+	var issue Issue
+	if err := c.api.Do(ctx, "GET", fmt.Sprintf("/repos/%s/issues/%d", c.repo, number), nil, &issue); err != nil {
+		return nil, fmt.Errorf("getting issue #%d: %w", number, err)
+	}
+	return &issue, nil
+}
+
+// CreateIssue creates a new issue.
+func (c *Client) CreateIssue(ctx context.Context, req *IssueRequest) (*Issue, error) {
+	var issue Issue
+	if err := c.api.Do(ctx, "POST", fmt.Sprintf("/repos/%s/issues", c.repo), req, &issue); err != nil {
+		return nil, fmt.Errorf("creating issue: %w", err)
+	}
+	return &issue, nil
+}
+
+// UpdateIssue edits an existing issue.
+func (c *Client) UpdateIssue(ctx context.Context, number int, req *IssueRequest) (*Issue, error) {
+	var issue Issue
+	if err := c.api.Do(ctx, "PATCH", fmt.Sprintf("/repos/%s/issues/%d", c.repo, number), req, &issue); err != nil {
+		return nil, fmt.Errorf("updating issue #%d: %w", number, err)
+	}
+	return &issue, nil
+}
+
+// GetRepo fetches the repository, failing if the token can't see it.
+func (c *Client) GetRepo(ctx context.Context) error {
+	if err := c.api.Do(ctx, "GET", "/repos/"+c.repo, nil, nil); err != nil {
+		return fmt.Errorf("getting repo %s: %w", c.repo, err)
+	}
+	return nil
+}
+
+// ListMilestones returns all open and closed milestones in the repository.
+func (c *Client) ListMilestones(ctx context.Context) ([]Milestone, error) {
+	var all []Milestone
+	for page := 1; ; page++ {
+		var milestones []Milestone
+		path := fmt.Sprintf("/repos/%s/milestones?state=all&per_page=100&page=%d", c.repo, page)
+		if err := c.api.Do(ctx, "GET", path, nil, &milestones); err != nil {
+			return nil, fmt.Errorf("listing milestones: %w", err)
+		}
+		all = append(all, milestones...)
+		if len(milestones) < 100 {
+			return all, nil
+		}
+	}
+}
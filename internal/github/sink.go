@@ -0,0 +1,348 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/toba/bean-me-up/internal/auth"
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+	"github.com/toba/bean-me-up/internal/restapi"
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+// SinkName is the registry key and bean extension name for GitHub.
+const SinkName = "github"
+
+// defaultClosedStatuses are bean statuses that close an issue unless configured.
+var defaultClosedStatuses = []string{"completed", "scrapped"}
+
+// Markers delimiting the issue body sections beanup maintains. Text between
+// them is preserved when the bean body is pushed.
+const (
+	tasklistStart  = "<!-- beanup:tasklist -->"
+	tasklistEnd    = "<!-- /beanup:tasklist -->"
+	blockedByStart = "<!-- beanup:blocked-by -->"
+	blockedByEnd   = "<!-- /beanup:blocked-by -->"
+)
+
+func init() {
+	syncer.Register(SinkName, newSinkFromConfig)
+}
+
+// newSinkFromConfig builds the GitHub sink using GITHUB_TOKEN.
+func newSinkFromConfig(cfg *config.Config) (syncer.Sink, error) {
+	gh := cfg.Beans.GitHub
+	if gh == nil || gh.Repo == "" {
+		return nil, fmt.Errorf("GitHub repo is required in .beans.yml extensions.github")
+	}
+	token, err := auth.Token(context.Background(), "GITHUB_TOKEN", SinkName, gh.TokenSource)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewClient(token, gh.Repo)
+	if err != nil {
+		return nil, err
+	}
+	return NewSink(client, gh), nil
+}
+
+// Sink syncs beans to issues in a GitHub repository.
+//
+// Status maps to open/closed plus an optional label, parents get a task
+// list of their children, and blocking relationships are written as
+// "Blocked by" cross-references in the blocked issue.
+type Sink struct {
+	client *Client
+	config *config.GitHubConfig
+
+	// The repository, read before a missing issue counts as deleted
+	repo restapi.Container
+
+	// Milestone title -> number, loaded by Prepare
+	milestones map[string]int
+
+	// Serializes read-modify-write edits of issue bodies in the relationship pass
+	bodyMu sync.Mutex
+}
+
+// NewSink creates a sink that writes issues with client using the mappings in cfg.
+func NewSink(client *Client, cfg *config.GitHubConfig) *Sink {
+	s := &Sink{client: client, config: cfg}
+	s.repo.Check = client.GetRepo
+	return s
+}
+
+// Name returns SinkName.
+func (s *Sink) Name() string { return SinkName }
+
+// Prepare loads repository milestones when a milestone mapping is configured.
+func (s *Sink) Prepare(ctx context.Context) error {
+	if len(s.config.MilestoneMapping) == 0 {
+		return nil
+	}
+	milestones, err := s.client.ListMilestones(ctx)
+	if err != nil {
+		return err
+	}
+	s.milestones = make(map[string]int, len(milestones))
+	for _, m := range milestones {
+		s.milestones[m.Title] = m.Number
+	}
+	return nil
+}
+
+// GetTask fetches the issue. The *Issue is kept in the ref for UpdateTask.
+func (s *Sink) GetTask(ctx context.Context, taskID string) (*syncer.TaskRef, error) {
+	number, err := issueNumber(taskID)
+	if err != nil {
+		return nil, err
+	}
+	issue, err := s.client.GetIssue(ctx, number)
+	if err != nil {
+		return nil, s.repo.Missing(ctx, err)
+	}
+	return issueRef(issue), nil
+}
+
+// CreateTask opens an issue for the bean, closing it straight away if the
+// bean is already done. Parent links are added later by SetParent.
+func (s *Sink) CreateTask(ctx context.Context, b *beans.Bean, parentTaskID string) (*syncer.TaskRef, error) {
+	labels := s.labels(b)
+	req := &IssueRequest{
+		Title:     &b.Title,
+		Body:      &b.Body,
+		Labels:    &labels,
+		Milestone: s.milestone(b),
+	}
+	issue, err := s.client.CreateIssue(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if state, reason := s.state(b); state != issue.State {
+		closed, err := s.client.UpdateIssue(ctx, issue.Number, &IssueRequest{State: &state, StateReason: &reason})
+		if err != nil {
+			// Keep the link; the next update retries the state
+			return issueRef(issue), fmt.Errorf("closing issue #%d: %w", issue.Number, err)
+		}
+		issue = closed
+	}
+	return issueRef(issue), nil
+}
+
+// UpdateTask sends only the fields that differ from the current issue.
+func (s *Sink) UpdateTask(ctx context.Context, current *syncer.TaskRef, b *beans.Bean) (*syncer.TaskRef, bool, error) {
+	number, err := issueNumber(current.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	issue, ok := current.Remote.(*Issue)
+	if !ok {
+		if issue, err = s.client.GetIssue(ctx, number); err != nil {
+			return nil, false, err
+		}
+	}
+
+	update := s.buildUpdateRequest(issue, b)
+	if !update.hasChanges() {
+		return current, false, nil
+	}
+	updated, err := s.client.UpdateIssue(ctx, number, update)
+	if err != nil {
+		return nil, false, err
+	}
+	return issueRef(updated), true, nil
+}
+
+// SyncTags is a no-op: bean tags are sent as labels with every create and update.
+func (s *Sink) SyncTags(ctx context.Context, task *syncer.TaskRef, b *beans.Bean) bool {
+	return false
+}
+
+// SetParent adds the child issue to the parent's task list.
+func (s *Sink) SetParent(ctx context.Context, parentTaskID, childTaskID string) error {
+	return s.addToBlock(ctx, parentTaskID, tasklistStart, tasklistEnd, "### Sub-issues", "- [ ] #"+childTaskID)
+}
+
+// SetRelationship adds a "Blocked by" cross-reference to the blocked issue.
+func (s *Sink) SetRelationship(ctx context.Context, blockerTaskID, blockedTaskID string) error {
+	return s.addToBlock(ctx, blockedTaskID, blockedByStart, blockedByEnd, "**Blocked by**", "- #"+blockerTaskID)
+}
+
+// buildUpdateRequest builds an IssueRequest containing only fields that differ from current.
+func (s *Sink) buildUpdateRequest(current *Issue, b *beans.Bean) *IssueRequest {
+	update := &IssueRequest{}
+
+	if current.Title != b.Title {
+		update.Title = &b.Title
+	}
+
+	body := composeBody(b.Body, managedBlocks(current.Body))
+	if current.Body != body {
+		update.Body = &body
+	}
+
+	if state, reason := s.state(b); current.State != state {
+		update.State = &state
+		if state == "closed" {
+			update.StateReason = &reason
+		}
+	}
+
+	labels := s.labels(b)
+	currentLabels := make([]string, len(current.Labels))
+	for i, l := range current.Labels {
+		currentLabels[i] = l.Name
+	}
+	slices.Sort(currentLabels)
+	if !slices.Equal(currentLabels, labels) {
+		update.Labels = &labels
+	}
+
+	if m := s.milestone(b); m != nil && (current.Milestone == nil || current.Milestone.Number != *m) {
+		update.Milestone = m
+	}
+
+	return update
+}
+
+// labels returns the sorted label set for a bean: its tags plus mapped
+// status, type, and priority labels.
+func (s *Sink) labels(b *beans.Bean) []string {
+	set := make(map[string]bool)
+	for _, t := range b.Tags {
+		set[t] = true
+	}
+	for _, l := range s.config.LabelMapping.Labels(b.Status, b.Type, b.Priority) {
+		set[l] = true
+	}
+	labels := make([]string, 0, len(set))
+	for l := range set {
+		labels = append(labels, l)
+	}
+	slices.Sort(labels)
+	return labels
+}
+
+// state maps a bean status to an issue state and, when closed, a state reason.
+func (s *Sink) state(b *beans.Bean) (state, reason string) {
+	closed := s.config.ClosedStatuses
+	if closed == nil {
+		closed = defaultClosedStatuses
+	}
+	if !slices.Contains(closed, b.Status) {
+		return "open", ""
+	}
+	if b.Status == "scrapped" {
+		return "closed", "not_planned"
+	}
+	return "closed", "completed"
+}
+
+// milestone returns the milestone number for a bean whose parent is mapped.
+func (s *Sink) milestone(b *beans.Bean) *int {
+	if b.Parent == "" {
+		return nil
+	}
+	title, ok := s.config.MilestoneMapping[b.Parent]
+	if !ok {
+		return nil
+	}
+	if number, ok := s.milestones[title]; ok {
+		return &number
+	}
+	return nil
+}
+
+// addToBlock ensures line is present in the managed block of an issue's body.
+func (s *Sink) addToBlock(ctx context.Context, taskID, start, end, heading, line string) error {
+	number, err := issueNumber(taskID)
+	if err != nil {
+		return err
+	}
+
+	s.bodyMu.Lock()
+	defer s.bodyMu.Unlock()
+
+	issue, err := s.client.GetIssue(ctx, number)
+	if err != nil {
+		return err
+	}
+	body, changed := ensureBlockLine(issue.Body, start, end, heading, line)
+	if !changed {
+		return nil
+	}
+	_, err = s.client.UpdateIssue(ctx, number, &IssueRequest{Body: &body})
+	return err
+}
+
+// ensureBlockLine adds line to the block between start and end, creating
+// the block at the end of body if needed.
+func ensureBlockLine(body, start, end, heading, line string) (string, bool) {
+	i := strings.Index(body, start)
+	j := strings.Index(body, end)
+	if i < 0 || j < i {
+		block := start + "\n" + heading + "\n" + line + "\n" + end
+		if body == "" {
+			return block, true
+		}
+		return strings.TrimRight(body, "\n") + "\n\n" + block, true
+	}
+
+	inner := body[i+len(start) : j]
+	for l := range strings.SplitSeq(inner, "\n") {
+		if strings.TrimSpace(l) == line {
+			return body, false
+		}
+	}
+	return body[:j] + line + "\n" + body[j:], true
+}
+
+// managedBlocks returns the beanup-maintained blocks in body, in order.
+func managedBlocks(body string) []string {
+	var blocks []string
+	for _, m := range [][2]string{{tasklistStart, tasklistEnd}, {blockedByStart, blockedByEnd}} {
+		i := strings.Index(body, m[0])
+		j := strings.Index(body, m[1])
+		if i >= 0 && j > i {
+			blocks = append(blocks, body[i:j+len(m[1])])
+		}
+	}
+	return blocks
+}
+
+// composeBody appends the preserved managed blocks to the bean body.
+func composeBody(beanBody string, blocks []string) string {
+	if len(blocks) == 0 {
+		return beanBody
+	}
+	parts := make([]string, 0, len(blocks)+1)
+	if b := strings.TrimRight(beanBody, "\n"); b != "" {
+		parts = append(parts, b)
+	}
+	parts = append(parts, blocks...)
+	return strings.Join(parts, "\n\n")
+}
+
+// issueRef converts an issue to a sink-neutral reference.
+func issueRef(issue *Issue) *syncer.TaskRef {
+	ref := &syncer.TaskRef{ID: strconv.Itoa(issue.Number), URL: issue.HTMLURL, Remote: issue}
+	for _, l := range issue.Labels {
+		ref.Tags = append(ref.Tags, l.Name)
+	}
+	return ref
+}
+
+// issueNumber parses a stored task ID as an issue number.
+func issueNumber(taskID string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(taskID, "#"))
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid GitHub issue number %q", taskID)
+	}
+	return n, nil
+}
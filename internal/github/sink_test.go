@@ -0,0 +1,262 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+	"github.com/toba/bean-me-up/internal/syncer"
+	"github.com/toba/bean-me-up/internal/syncertest"
+)
+
+// fakeGitHub is an in-memory issues API for one repository.
+type fakeGitHub struct {
+	mu     sync.Mutex
+	url    string
+	issues map[int]*Issue
+	// failPatches makes that many PATCHes fail, e.g. closing a new issue
+	failPatches int
+}
+
+func newFakeGitHub(t *testing.T) (*fakeGitHub, *Client) {
+	t.Helper()
+	f := &fakeGitHub{issues: map[int]*Issue{}}
+	url := syncertest.Serve(t, f)
+	f.url = url
+
+	client, err := NewClient("test", "acme/widgets", WithBaseURL(url))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f, client
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/repos/acme/widgets":
+		_, _ = w.Write([]byte(`{"full_name":"acme/widgets"}`))
+		return
+	case "/repos/acme/widgets/milestones":
+		_ = json.NewEncoder(w).Encode([]Milestone{{Number: 7, Title: "v1.0"}})
+		return
+	}
+
+	var req IssueRequest
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	if r.Method == "POST" && r.URL.Path == "/repos/acme/widgets/issues" {
+		issue := &Issue{Number: len(f.issues) + 1, State: "open"}
+		f.issues[issue.Number] = issue
+		issue.HTMLURL = "https://github.com/acme/widgets/issues/" + strconv.Itoa(issue.Number)
+		apply(issue, &req)
+		_ = json.NewEncoder(w).Encode(issue)
+		return
+	}
+
+	number, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/repos/acme/widgets/issues/"))
+	issue, ok := f.issues[number]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		return
+	}
+	if r.Method == "PATCH" && f.failPatches > 0 {
+		f.failPatches--
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"message":"Validation Failed"}`))
+		return
+	}
+	if r.Method == "PATCH" {
+		apply(issue, &req)
+	}
+	_ = json.NewEncoder(w).Encode(issue)
+}
+
+func apply(issue *Issue, req *IssueRequest) {
+	if req.Title != nil {
+		issue.Title = *req.Title
+	}
+	if req.Body != nil {
+		issue.Body = *req.Body
+	}
+	if req.State != nil {
+		issue.State = *req.State
+	}
+	if req.Labels != nil {
+		issue.Labels = nil
+		for _, l := range *req.Labels {
+			issue.Labels = append(issue.Labels, Label{Name: l})
+		}
+	}
+	if req.Milestone != nil {
+		issue.Milestone = &Milestone{Number: *req.Milestone}
+	}
+}
+
+func TestSink(t *testing.T) {
+	syncertest.RunSinkTests(t, func(t *testing.T) syncertest.SinkFixture {
+		_, client := newFakeGitHub(t)
+		return syncertest.SinkFixture{Sink: NewSink(client, &config.GitHubConfig{Repo: "acme/widgets"}), DeletedTaskID: "99"}
+	})
+}
+
+func TestSink_MapsBeanFields(t *testing.T) {
+	gh, client := newFakeGitHub(t)
+	sink := NewSink(client, &config.GitHubConfig{
+		Repo: "acme/widgets",
+		LabelMapping: &config.LabelMapping{
+			Type:     map[string]string{"bug": "bug"},
+			Priority: map[string]string{"high": "priority: high"},
+		},
+		MilestoneMapping: map[string]string{"epic": "v1.0"},
+	})
+	state := syncertest.NewState()
+
+	beanList := []beans.Bean{
+		{ID: "epic", Title: "Epic", Type: "epic", Status: "todo"},
+		{ID: "fix", Title: "Fix crash", Type: "bug", Priority: "high", Status: "todo", Parent: "epic", Tags: []string{"ui"}, Blocking: []string{"done"}},
+		{ID: "done", Title: "Shipped", Type: "task", Status: "completed"},
+	}
+
+	results := syncertest.Sync(t, sink, syncer.Options{}, state, beanList)
+	syncertest.RequireActions(t, results, "created")
+
+	epic := gh.issues[mustAtoi(t, *state.GetTaskID("epic"))]
+	fix := gh.issues[mustAtoi(t, *state.GetTaskID("fix"))]
+	done := gh.issues[mustAtoi(t, *state.GetTaskID("done"))]
+
+	if !strings.Contains(epic.Body, "- [ ] #"+strconv.Itoa(fix.Number)) {
+		t.Errorf("parent body missing task list entry:\n%s", epic.Body)
+	}
+	if !strings.Contains(done.Body, "- #"+strconv.Itoa(fix.Number)) {
+		t.Errorf("blocked issue missing cross-reference:\n%s", done.Body)
+	}
+	if done.State != "closed" {
+		t.Errorf("completed bean issue state = %q, want closed", done.State)
+	}
+
+	var labels []string
+	for _, l := range fix.Labels {
+		labels = append(labels, l.Name)
+	}
+	if strings.Join(labels, ",") != "bug,priority: high,ui" {
+		t.Errorf("labels = %v", labels)
+	}
+	if fix.Milestone == nil || fix.Milestone.Number != 7 {
+		t.Errorf("milestone = %+v, want #7", fix.Milestone)
+	}
+}
+
+func TestSink_UpdatePreservesManagedBlocks(t *testing.T) {
+	gh, client := newFakeGitHub(t)
+	sink := NewSink(client, &config.GitHubConfig{Repo: "acme/widgets"})
+
+	gh.issues[1] = &Issue{
+		Number: 1,
+		Title:  "Old title",
+		Body:   "Old body\n\n" + tasklistStart + "\n### Sub-issues\n- [ ] #2\n" + tasklistEnd,
+		State:  "open",
+	}
+	state := syncertest.NewState()
+	state.SetTaskID("epic", "1")
+
+	b := beans.Bean{ID: "epic", Title: "New title", Body: "New body", Status: "scrapped"}
+	results, err := syncer.New(sink, syncer.Options{Force: true}, state).SyncBeans(context.Background(), []beans.Bean{b})
+	if err != nil || results[0].Action != "updated" {
+		t.Fatalf("action %q, err %v / %v", results[0].Action, err, results[0].Error)
+	}
+
+	issue := gh.issues[1]
+	if !strings.HasPrefix(issue.Body, "New body\n\n"+tasklistStart) || !strings.Contains(issue.Body, "- [ ] #2") {
+		t.Errorf("body lost managed block:\n%s", issue.Body)
+	}
+	if issue.State != "closed" {
+		t.Errorf("state = %q, want closed", issue.State)
+	}
+
+	// A second sync finds nothing to change
+	results, _ = syncer.New(sink, syncer.Options{Force: true}, state).SyncBeans(context.Background(), []beans.Bean{b})
+	if results[0].Action != "unchanged" {
+		t.Errorf("second sync action = %q, want unchanged", results[0].Action)
+	}
+}
+
+func TestSink_UnreadableRepoKeepsLink(t *testing.T) {
+	gh, _ := newFakeGitHub(t)
+	// GitHub answers 404 for a private repo the token can't see
+	client, err := NewClient("test", "acme/private", WithBaseURL(gh.url))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := NewSink(client, &config.GitHubConfig{Repo: "acme/private"})
+	state := syncertest.NewState()
+	state.SetTaskID("hidden", "5")
+
+	results, err := syncer.New(sink, syncer.Options{Force: true}, state).SyncBeans(context.Background(), []beans.Bean{{ID: "hidden", Title: "Hidden"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Action != "error" || *state.GetTaskID("hidden") != "5" {
+		t.Errorf("action %q task %q; want error with the link kept", results[0].Action, *state.GetTaskID("hidden"))
+	}
+}
+
+func TestSink_FailedCloseKeepsNewIssue(t *testing.T) {
+	gh, client := newFakeGitHub(t)
+	gh.failPatches = 1
+	sink := NewSink(client, &config.GitHubConfig{Repo: "acme/widgets"})
+	state := syncertest.NewState()
+	beanList := []beans.Bean{{ID: "done", Title: "Shipped", Status: "completed"}}
+
+	results := syncertest.Sync(t, sink, syncer.Options{}, state, beanList)
+	if results[0].Action != "error" || state.GetTaskID("done") == nil || state.GetSyncedAt("done") != nil {
+		t.Fatalf("first sync: action %q, err %v; want an error with the new issue linked but not synced", results[0].Action, results[0].Error)
+	}
+
+	// The next sync closes the same issue rather than opening another
+	results = syncertest.Sync(t, sink, syncer.Options{}, state, beanList)
+	syncertest.RequireActions(t, results, "updated")
+	if len(gh.issues) != 1 || gh.issues[1].State != "closed" {
+		t.Errorf("issues = %+v, want one closed issue", gh.issues)
+	}
+}
+
+func TestEnsureBlockLine(t *testing.T) {
+	body, changed := ensureBlockLine("Text", tasklistStart, tasklistEnd, "### Sub-issues", "- [ ] #4")
+	if !changed || !strings.HasPrefix(body, "Text\n\n"+tasklistStart+"\n### Sub-issues\n- [ ] #4\n") {
+		t.Fatalf("unexpected body:\n%s", body)
+	}
+	if _, changed := ensureBlockLine(body, tasklistStart, tasklistEnd, "### Sub-issues", "- [ ] #4"); changed {
+		t.Error("adding an existing line should be a no-op")
+	}
+	body, _ = ensureBlockLine(body, tasklistStart, tasklistEnd, "### Sub-issues", "- [ ] #5")
+	if !strings.Contains(body, "- [ ] #4\n- [ ] #5\n"+tasklistEnd) {
+		t.Errorf("line not appended inside block:\n%s", body)
+	}
+}
+
+func TestNewClient_ValidatesRepo(t *testing.T) {
+	for _, repo := range []string{"", "acme", "acme/", "/widgets", "a/b/c"} {
+		if _, err := NewClient("t", repo); err == nil {
+			t.Errorf("NewClient(%q) should fail", repo)
+		}
+	}
+}
+
+func mustAtoi(t *testing.T, s string) int {
+	t.Helper()
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
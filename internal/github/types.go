@@ -0,0 +1,45 @@
+// Package github provides GitHub Issues integration.
+package github
+
+// Issue holds issue data returned from GitHub.
+type Issue struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	State     string     `json:"state"` // "open" or "closed"
+	HTMLURL   string     `json:"html_url"`
+	Labels    []Label    `json:"labels"`
+	Milestone *Milestone `json:"milestone"`
+}
+
+// Label is a GitHub issue label.
+type Label struct {
+	Name string `json:"name"`
+}
+
+// Milestone is a GitHub repository milestone.
+type Milestone struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+}
+
+// IssueRequest is the request body for creating or updating an issue.
+// Nil fields are left unchanged on update.
+type IssueRequest struct {
+	Title       *string   `json:"title,omitempty"`
+	Body        *string   `json:"body,omitempty"`
+	State       *string   `json:"state,omitempty"`
+	StateReason *string   `json:"state_reason,omitempty"` // "completed" or "not_planned" when closing
+	Labels      *[]string `json:"labels,omitempty"`
+	Milestone   *int      `json:"milestone,omitempty"`
+}
+
+// hasChanges returns true if any field is set in the update request.
+func (r *IssueRequest) hasChanges() bool {
+	return r.Title != nil || r.Body != nil || r.State != nil || r.Labels != nil || r.Milestone != nil
+}
+
+// errorResponse is GitHub's error body.
+type errorResponse struct {
+	Message string `json:"message"`
+}
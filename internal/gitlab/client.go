@@ -0,0 +1,149 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/toba/bean-me-up/internal/restapi"
+)
+
+// DefaultURL is the GitLab.com instance.
+const DefaultURL = "https://gitlab.com"
+
+// HTTPDoer sends HTTP requests. *http.Client satisfies it.
+type HTTPDoer = restapi.HTTPDoer
+
+// Client provides GitLab Issues access for a single project.
+type Client struct {
+	api     *restapi.Client
+	project string // path or numeric ID, as configured
+}
+
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the transport used for API requests.
+func WithHTTPClient(doer HTTPDoer) ClientOption {
+	return func(c *Client) { c.api.HTTP = doer }
+}
+
+// NewClient creates a client for project ("group/name" or numeric ID) on the
+// instance at baseURL, authenticated with a personal or project access token.
+func NewClient(baseURL, token, project string, opts ...ClientOption) (*Client, error) {
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+	if u, err := url.Parse(baseURL); err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid GitLab URL %q", baseURL)
+	}
+	if project == "" {
+		return nil, fmt.Errorf("GitLab project is required")
+	}
+
+	api := restapi.New("gitlab", strings.TrimRight(baseURL, "/")+"/api/v4")
+	api.Authorize = func(req *http.Request) {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	}
+	api.ErrorMessage = func(body []byte) string {
+		// message is a string, or a map of field errors on validation failures
+		var errResp struct {
+			Message any    `json:"message"`
+			Error   string `json:"error"`
+		}
+		_ = json.Unmarshal(body, &errResp)
+		if errResp.Message != nil {
+			return fmt.Sprint(errResp.Message)
+		}
+		return errResp.Error
+	}
+
+	c := &Client{api: api, project: project}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// projectPath returns the URL prefix for the project's endpoints.
+func (c *Client) projectPath() string {
+	return "/projects/" + url.PathEscape(c.project)
+}
+
+// GetIssue fetches an issue by IID.
+func (c *Client) GetIssue(ctx context.Context, iid int) (*Issue, error) {
+	var issue Issue
+	if err := c.api.Do(ctx, "GET", fmt.Sprintf("%s/issues/%d", c.projectPath(), iid), nil, &issue); err != nil {
+		return nil, fmt.Errorf("getting issue #%d: %w", iid, err)
+	}
+	return &issue, nil
+}
+
+// CreateIssue creates a new issue.
+func (c *Client) CreateIssue(ctx context.Context, req *IssueRequest) (*Issue, error) {
+	var issue Issue
+	if err := c.api.Do(ctx, "POST", c.projectPath()+"/issues", req, &issue); err != nil {
+		return nil, fmt.Errorf("creating issue: %w", err)
+	}
+	return &issue, nil
+}
+
+// UpdateIssue edits an existing issue.
+func (c *Client) UpdateIssue(ctx context.Context, iid int, req *IssueRequest) (*Issue, error) {
+	var issue Issue
+	if err := c.api.Do(ctx, "PUT", fmt.Sprintf("%s/issues/%d", c.projectPath(), iid), req, &issue); err != nil {
+		return nil, fmt.Errorf("updating issue #%d: %w", iid, err)
+	}
+	return &issue, nil
+}
+
+// GetProject fetches the project, failing if the token can't see it.
+func (c *Client) GetProject(ctx context.Context) error {
+	if err := c.api.Do(ctx, "GET", c.projectPath(), nil, nil); err != nil {
+		return fmt.Errorf("getting project %s: %w", c.project, err)
+	}
+	return nil
+}
+
+// ListMilestones returns all milestones in the project.
+func (c *Client) ListMilestones(ctx context.Context) ([]Milestone, error) {
+	var all []Milestone
+	for page := 1; ; page++ {
+		var milestones []Milestone
+		path := fmt.Sprintf("%s/milestones?per_page=100&page=%d", c.projectPath(), page)
+		if err := c.api.Do(ctx, "GET", path, nil, &milestones); err != nil {
+			return nil, fmt.Errorf("listing milestones: %w", err)
+		}
+		all = append(all, milestones...)
+		if len(milestones) < 100 {
+			return all, nil
+		}
+	}
+}
+
+// ListLinks returns the issues linked to an issue.
+func (c *Client) ListLinks(ctx context.Context, iid int) ([]LinkedIssue, error) {
+	var links []LinkedIssue
+	if err := c.api.Do(ctx, "GET", fmt.Sprintf("%s/issues/%d/links", c.projectPath(), iid), nil, &links); err != nil {
+		return nil, fmt.Errorf("listing links of #%d: %w", iid, err)
+	}
+	return links, nil
+}
+
+// CreateLink links iid to targetIID in the same project with linkType
+// ("relates_to", "blocks", or "is_blocked_by").
+func (c *Client) CreateLink(ctx context.Context, iid, targetIID int, linkType string) error {
+	req := &linkRequest{
+		TargetProjectID: c.project,
+		TargetIssueIID:  strconv.Itoa(targetIID),
+		LinkType:        linkType,
+	}
+	if err := c.api.Do(ctx, "POST", fmt.Sprintf("%s/issues/%d/links", c.projectPath(), iid), req, nil); err != nil {
+		return fmt.Errorf("linking #%d to #%d: %w", iid, targetIID, err)
+	}
+	return nil
+}
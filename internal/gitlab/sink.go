@@ -0,0 +1,264 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/toba/bean-me-up/internal/auth"
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+	"github.com/toba/bean-me-up/internal/restapi"
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+// SinkName is the registry key and bean extension name for GitLab.
+const SinkName = "gitlab"
+
+// defaultClosedStatuses are bean statuses that close an issue unless configured.
+var defaultClosedStatuses = []string{"completed", "scrapped"}
+
+func init() {
+	syncer.Register(SinkName, newSinkFromConfig)
+}
+
+// newSinkFromConfig builds the GitLab sink using GITLAB_TOKEN.
+func newSinkFromConfig(cfg *config.Config) (syncer.Sink, error) {
+	gl := cfg.Beans.GitLab
+	if gl == nil || gl.Project == "" {
+		return nil, fmt.Errorf("GitLab project is required in .beans.yml extensions.gitlab")
+	}
+	token, err := auth.Token(context.Background(), "GITLAB_TOKEN", SinkName, gl.TokenSource)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewClient(gl.URL, token, gl.Project)
+	if err != nil {
+		return nil, err
+	}
+	return NewSink(client, gl), nil
+}
+
+// Sink syncs beans to issues in a GitLab project.
+//
+// Status maps to opened/closed plus an optional label, parents group their
+// children into milestones, and blocking relationships become "blocks"
+// issue links.
+type Sink struct {
+	client *Client
+	config *config.GitLabConfig
+
+	// The project, read before a missing issue counts as deleted
+	project restapi.Container
+
+	// Milestone title -> ID, loaded by Prepare
+	milestones map[string]int
+}
+
+// NewSink creates a sink that writes issues with client using the mappings in cfg.
+func NewSink(client *Client, cfg *config.GitLabConfig) *Sink {
+	s := &Sink{client: client, config: cfg}
+	s.project.Check = client.GetProject
+	return s
+}
+
+// Name returns SinkName.
+func (s *Sink) Name() string { return SinkName }
+
+// Prepare loads project milestones when a milestone mapping is configured.
+func (s *Sink) Prepare(ctx context.Context) error {
+	if len(s.config.MilestoneMapping) == 0 {
+		return nil
+	}
+	milestones, err := s.client.ListMilestones(ctx)
+	if err != nil {
+		return err
+	}
+	s.milestones = make(map[string]int, len(milestones))
+	for _, m := range milestones {
+		s.milestones[m.Title] = m.ID
+	}
+	return nil
+}
+
+// GetTask fetches the issue. The *Issue is kept in the ref for UpdateTask.
+func (s *Sink) GetTask(ctx context.Context, taskID string) (*syncer.TaskRef, error) {
+	iid, err := issueIID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	issue, err := s.client.GetIssue(ctx, iid)
+	if err != nil {
+		return nil, s.project.Missing(ctx, err)
+	}
+	return issueRef(issue), nil
+}
+
+// CreateTask opens an issue for the bean, closing it straight away if the
+// bean is already done.
+func (s *Sink) CreateTask(ctx context.Context, b *beans.Bean, parentTaskID string) (*syncer.TaskRef, error) {
+	labels := strings.Join(s.labels(b), ",")
+	req := &IssueRequest{
+		Title:       &b.Title,
+		Description: &b.Body,
+		Labels:      &labels,
+		MilestoneID: s.milestone(b),
+	}
+	issue, err := s.client.CreateIssue(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if state := s.state(b); state != issue.State {
+		event := stateEvent(state)
+		closed, err := s.client.UpdateIssue(ctx, issue.IID, &IssueRequest{StateEvent: &event})
+		if err != nil {
+			// Keep the link; the next update retries the state
+			return issueRef(issue), fmt.Errorf("closing issue #%d: %w", issue.IID, err)
+		}
+		issue = closed
+	}
+	return issueRef(issue), nil
+}
+
+// UpdateTask sends only the fields that differ from the current issue.
+func (s *Sink) UpdateTask(ctx context.Context, current *syncer.TaskRef, b *beans.Bean) (*syncer.TaskRef, bool, error) {
+	iid, err := issueIID(current.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	issue, ok := current.Remote.(*Issue)
+	if !ok {
+		if issue, err = s.client.GetIssue(ctx, iid); err != nil {
+			return nil, false, err
+		}
+	}
+
+	update := s.buildUpdateRequest(issue, b)
+	if !update.hasChanges() {
+		return current, false, nil
+	}
+	updated, err := s.client.UpdateIssue(ctx, iid, update)
+	if err != nil {
+		return nil, false, err
+	}
+	return issueRef(updated), true, nil
+}
+
+// SyncTags is a no-op: bean tags are sent as labels with every create and update.
+func (s *Sink) SyncTags(ctx context.Context, task *syncer.TaskRef, b *beans.Bean) bool {
+	return false
+}
+
+// SetRelationship adds a "blocks" link from the blocker unless it exists.
+// Blocking links need GitLab Premium; on other tiers the API rejects them.
+func (s *Sink) SetRelationship(ctx context.Context, blockerTaskID, blockedTaskID string) error {
+	blocker, err := issueIID(blockerTaskID)
+	if err != nil {
+		return err
+	}
+	blocked, err := issueIID(blockedTaskID)
+	if err != nil {
+		return err
+	}
+	links, err := s.client.ListLinks(ctx, blocker)
+	if err != nil {
+		return err
+	}
+	for _, l := range links {
+		if l.IID == blocked && l.LinkType == "blocks" {
+			return nil
+		}
+	}
+	return s.client.CreateLink(ctx, blocker, blocked, "blocks")
+}
+
+// buildUpdateRequest builds an IssueRequest containing only fields that differ from current.
+func (s *Sink) buildUpdateRequest(current *Issue, b *beans.Bean) *IssueRequest {
+	update := &IssueRequest{}
+
+	if current.Title != b.Title {
+		update.Title = &b.Title
+	}
+	if current.Description != b.Body {
+		update.Description = &b.Body
+	}
+
+	if state := s.state(b); current.State != state {
+		event := stateEvent(state)
+		update.StateEvent = &event
+	}
+
+	labels := s.labels(b)
+	currentLabels := slices.Clone(current.Labels)
+	slices.Sort(currentLabels)
+	if !slices.Equal(currentLabels, labels) {
+		joined := strings.Join(labels, ",")
+		update.Labels = &joined
+	}
+
+	if m := s.milestone(b); m != nil && (current.Milestone == nil || current.Milestone.ID != *m) {
+		update.MilestoneID = m
+	}
+
+	return update
+}
+
+// labels returns the sorted label set for a bean: its tags plus mapped
+// status, type, and priority labels.
+func (s *Sink) labels(b *beans.Bean) []string {
+	labels := slices.Concat(b.Tags, s.config.LabelMapping.Labels(b.Status, b.Type, b.Priority))
+	slices.Sort(labels)
+	return slices.Compact(labels)
+}
+
+// state maps a bean status to "opened" or "closed".
+func (s *Sink) state(b *beans.Bean) string {
+	closed := s.config.ClosedStatuses
+	if closed == nil {
+		closed = defaultClosedStatuses
+	}
+	if slices.Contains(closed, b.Status) {
+		return "closed"
+	}
+	return "opened"
+}
+
+// milestone returns the milestone ID for a bean whose parent is mapped.
+func (s *Sink) milestone(b *beans.Bean) *int {
+	if b.Parent == "" {
+		return nil
+	}
+	title, ok := s.config.MilestoneMapping[b.Parent]
+	if !ok {
+		return nil
+	}
+	if id, ok := s.milestones[title]; ok {
+		return &id
+	}
+	return nil
+}
+
+// stateEvent returns the state_event that moves an issue to state.
+func stateEvent(state string) string {
+	if state == "closed" {
+		return "close"
+	}
+	return "reopen"
+}
+
+// issueRef converts an issue to a sink-neutral reference.
+func issueRef(issue *Issue) *syncer.TaskRef {
+	return &syncer.TaskRef{ID: strconv.Itoa(issue.IID), URL: issue.WebURL, Tags: issue.Labels, Remote: issue}
+}
+
+// issueIID parses a stored task ID as an issue IID.
+func issueIID(taskID string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(taskID, "#"))
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid GitLab issue number %q", taskID)
+	}
+	return n, nil
+}
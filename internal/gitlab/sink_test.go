@@ -0,0 +1,218 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+	"github.com/toba/bean-me-up/internal/syncer"
+	"github.com/toba/bean-me-up/internal/syncertest"
+)
+
+// fakeGitLab is an in-memory issues API for project acme/widgets.
+type fakeGitLab struct {
+	mu     sync.Mutex
+	issues map[int]*Issue
+	links  map[int][]LinkedIssue
+	// failPuts makes that many PUTs fail, e.g. closing a new issue
+	failPuts int
+}
+
+func newFakeGitLab(t *testing.T) (*fakeGitLab, *Client) {
+	t.Helper()
+	f := &fakeGitLab{issues: map[int]*Issue{}, links: map[int][]LinkedIssue{}}
+	url := syncertest.Serve(t, f)
+
+	client, err := NewClient(url, "test", "acme/widgets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f, client
+}
+
+func (f *fakeGitLab) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	const prefix = "/api/v4/projects/acme%2Fwidgets"
+	if r.Header.Get("PRIVATE-TOKEN") != "test" || !strings.HasPrefix(r.URL.EscapedPath(), prefix) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.EscapedPath(), prefix)
+
+	switch path {
+	case "":
+		_, _ = w.Write([]byte(`{"path_with_namespace":"acme/widgets"}`))
+		return
+	case "/milestones":
+		_ = json.NewEncoder(w).Encode([]Milestone{{ID: 42, Title: "v1.0"}})
+		return
+	}
+
+	if r.Method == "POST" && path == "/issues" {
+		var req IssueRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		issue := &Issue{IID: len(f.issues) + 1, State: "opened"}
+		issue.WebURL = "https://gitlab.example.com/acme/widgets/-/issues/" + strconv.Itoa(issue.IID)
+		f.issues[issue.IID] = issue
+		apply(issue, &req)
+		_ = json.NewEncoder(w).Encode(issue)
+		return
+	}
+
+	rest, sub, _ := strings.Cut(strings.TrimPrefix(path, "/issues/"), "/")
+	iid, _ := strconv.Atoi(rest)
+	issue, ok := f.issues[iid]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"404 Not found"}`))
+		return
+	}
+
+	switch {
+	case sub == "links" && r.Method == "POST":
+		var req linkRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		target, _ := strconv.Atoi(req.TargetIssueIID)
+		f.links[iid] = append(f.links[iid], LinkedIssue{IID: target, LinkType: req.LinkType})
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{}`))
+	case sub == "links":
+		_ = json.NewEncoder(w).Encode(f.links[iid])
+	case r.Method == "PUT" && f.failPuts > 0:
+		f.failPuts--
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message":"400 Bad request"}`))
+	case r.Method == "PUT":
+		var req IssueRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		apply(issue, &req)
+		_ = json.NewEncoder(w).Encode(issue)
+	default:
+		_ = json.NewEncoder(w).Encode(issue)
+	}
+}
+
+func apply(issue *Issue, req *IssueRequest) {
+	if req.Title != nil {
+		issue.Title = *req.Title
+	}
+	if req.Description != nil {
+		issue.Description = *req.Description
+	}
+	if req.Labels != nil {
+		issue.Labels = nil
+		if *req.Labels != "" {
+			issue.Labels = strings.Split(*req.Labels, ",")
+		}
+	}
+	if req.MilestoneID != nil {
+		issue.Milestone = &Milestone{ID: *req.MilestoneID}
+	}
+	if req.StateEvent != nil {
+		issue.State = map[string]string{"close": "closed", "reopen": "opened"}[*req.StateEvent]
+	}
+}
+
+func TestSink(t *testing.T) {
+	syncertest.RunSinkTests(t, func(t *testing.T) syncertest.SinkFixture {
+		_, client := newFakeGitLab(t)
+		return syncertest.SinkFixture{Sink: NewSink(client, &config.GitLabConfig{Project: "acme/widgets"}), DeletedTaskID: "99"}
+	})
+}
+
+func TestSink_MapsBeanFields(t *testing.T) {
+	gl, client := newFakeGitLab(t)
+	sink := NewSink(client, &config.GitLabConfig{
+		Project: "acme/widgets",
+		LabelMapping: &config.LabelMapping{
+			Type: map[string]string{"bug": "bug"},
+		},
+		MilestoneMapping: map[string]string{"epic": "v1.0"},
+	})
+	state := syncertest.NewState()
+
+	beanList := []beans.Bean{
+		{ID: "epic", Title: "Epic", Type: "epic", Status: "todo"},
+		{ID: "fix", Title: "Fix crash", Type: "bug", Status: "todo", Parent: "epic", Tags: []string{"ui", "bug"}, Blocking: []string{"done"}},
+		{ID: "done", Title: "Shipped", Type: "task", Status: "completed"},
+	}
+
+	results := syncertest.Sync(t, sink, syncer.Options{}, state, beanList)
+	syncertest.RequireActions(t, results, "created")
+
+	fix := gl.issues[mustAtoi(t, *state.GetTaskID("fix"))]
+	done := gl.issues[mustAtoi(t, *state.GetTaskID("done"))]
+
+	if done.State != "closed" {
+		t.Errorf("completed bean issue state = %q, want closed", done.State)
+	}
+	if strings.Join(fix.Labels, ",") != "bug,ui" {
+		t.Errorf("labels = %v", fix.Labels)
+	}
+	if fix.Milestone == nil || fix.Milestone.ID != 42 {
+		t.Errorf("milestone = %+v, want 42", fix.Milestone)
+	}
+	if links := gl.links[fix.IID]; len(links) != 1 || links[0].IID != done.IID || links[0].LinkType != "blocks" {
+		t.Errorf("links = %+v, want #%d blocks #%d", links, fix.IID, done.IID)
+	}
+
+	// A second sync changes nothing and doesn't duplicate links
+	syncertest.RequireActions(t, syncertest.Sync(t, sink, syncer.Options{Force: true}, state, beanList), "unchanged")
+	if len(gl.links[fix.IID]) != 1 {
+		t.Errorf("second sync duplicated links: %+v", gl.links[fix.IID])
+	}
+}
+
+func TestSink_ReopensIssue(t *testing.T) {
+	gl, client := newFakeGitLab(t)
+	sink := NewSink(client, &config.GitLabConfig{Project: "acme/widgets"})
+	gl.issues[1] = &Issue{IID: 1, Title: "Old", State: "closed", Labels: []string{"stale"}}
+	state := syncertest.NewState()
+	state.SetTaskID("b", "1")
+
+	results, _ := syncer.New(sink, syncer.Options{Force: true}, state).SyncBeans(context.Background(),
+		[]beans.Bean{{ID: "b", Title: "New", Status: "in-progress"}})
+	if results[0].Action != "updated" {
+		t.Fatalf("action %q (err %v)", results[0].Action, results[0].Error)
+	}
+	if issue := gl.issues[1]; issue.State != "opened" || issue.Title != "New" || len(issue.Labels) != 0 {
+		t.Errorf("issue = %+v", issue)
+	}
+}
+
+func mustAtoi(t *testing.T, s string) int {
+	t.Helper()
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestSink_FailedCloseKeepsNewIssue(t *testing.T) {
+	gl, client := newFakeGitLab(t)
+	gl.failPuts = 1
+	sink := NewSink(client, &config.GitLabConfig{Project: "acme/widgets"})
+	state := syncertest.NewState()
+	beanList := []beans.Bean{{ID: "done", Title: "Shipped", Status: "completed"}}
+
+	results := syncertest.Sync(t, sink, syncer.Options{}, state, beanList)
+	if results[0].Action != "error" || state.GetTaskID("done") == nil || state.GetSyncedAt("done") != nil {
+		t.Fatalf("first sync: action %q, err %v; want an error with the new issue linked but not synced", results[0].Action, results[0].Error)
+	}
+
+	// The next sync closes the same issue rather than opening another
+	results = syncertest.Sync(t, sink, syncer.Options{}, state, beanList)
+	syncertest.RequireActions(t, results, "updated")
+	if len(gl.issues) != 1 || gl.issues[1].State != "closed" {
+		t.Errorf("issues = %+v, want one closed issue", gl.issues)
+	}
+}
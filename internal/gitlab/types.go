@@ -0,0 +1,52 @@
+// Package gitlab provides GitLab Issues integration.
+package gitlab
+
+// Issue holds issue data returned from GitLab.
+type Issue struct {
+	ID          int        `json:"id"`
+	IID         int        `json:"iid"` // project-scoped number shown in the UI
+	ProjectID   int        `json:"project_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	State       string     `json:"state"` // "opened" or "closed"
+	WebURL      string     `json:"web_url"`
+	Labels      []string   `json:"labels"`
+	Milestone   *Milestone `json:"milestone"`
+}
+
+// Milestone is a GitLab project milestone.
+type Milestone struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+// LinkedIssue is an issue returned by the issue links API, with the link
+// type as seen from the source issue.
+type LinkedIssue struct {
+	IID       int    `json:"iid"`
+	ProjectID int    `json:"project_id"`
+	LinkType  string `json:"link_type"` // "relates_to", "blocks", or "is_blocked_by"
+}
+
+// IssueRequest is the request body for creating or updating an issue.
+// Nil fields are left unchanged on update.
+type IssueRequest struct {
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+	// Labels is a comma-separated list replacing the issue's labels; "" clears them.
+	Labels      *string `json:"labels,omitempty"`
+	MilestoneID *int    `json:"milestone_id,omitempty"`
+	StateEvent  *string `json:"state_event,omitempty"` // "close" or "reopen"
+}
+
+// hasChanges returns true if any field is set in the update request.
+func (r *IssueRequest) hasChanges() bool {
+	return r.Title != nil || r.Description != nil || r.Labels != nil || r.MilestoneID != nil || r.StateEvent != nil
+}
+
+// linkRequest is the body for creating an issue link.
+type linkRequest struct {
+	TargetProjectID string `json:"target_project_id"`
+	TargetIssueIID  string `json:"target_issue_iid"`
+	LinkType        string `json:"link_type"`
+}
@@ -0,0 +1,134 @@
+// Package gitlink builds web permalinks to files in a git checkout, using
+// the origin remote to find the GitHub or GitLab project.
+package gitlink
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Linker builds permalinks to files in one repository.
+type Linker struct {
+	root string // Absolute path of the working tree
+	web  string // Project URL, e.g. https://github.com/org/repo
+	sep  string // Path between the project URL and the ref
+
+	mu   sync.Mutex
+	refs map[string]string // Commit by repository-relative path
+}
+
+// New returns a Linker for the repository containing dir. It fails when
+// dir isn't in a git checkout or the origin remote isn't a web host.
+func New(dir string) (*Linker, error) {
+	root, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("finding git repository: %w", err)
+	}
+	remote, err := git(dir, "remote", "get-url", "origin")
+	if err != nil {
+		return nil, fmt.Errorf("reading origin remote: %w", err)
+	}
+	web, err := WebURL(remote)
+	if err != nil {
+		return nil, err
+	}
+	return &Linker{
+		root: root,
+		web:  web,
+		sep:  blobPath(web),
+		refs: make(map[string]string),
+	}, nil
+}
+
+// URL returns the permalink to path at the last commit that changed it,
+// or at HEAD for files not yet committed. It returns "" for paths outside
+// the repository.
+func (l *Linker) URL(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	rel, err := filepath.Rel(l.root, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	rel = filepath.ToSlash(rel)
+
+	ref := l.ref(rel)
+	if ref == "" {
+		return ""
+	}
+	return l.web + l.sep + ref + "/" + escapePath(rel)
+}
+
+// ref returns the commit to link rel at, caching lookups.
+func (l *Linker) ref(rel string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if ref, ok := l.refs[rel]; ok {
+		return ref
+	}
+	ref, _ := git(l.root, "log", "-1", "--format=%H", "--", rel)
+	if ref == "" {
+		ref, _ = git(l.root, "rev-parse", "HEAD")
+	}
+	l.refs[rel] = ref
+	return ref
+}
+
+// WebURL converts a git remote (https, ssh, or scp-style) to the project's
+// web URL, without the .git suffix.
+func WebURL(remote string) (string, error) {
+	remote = strings.TrimSpace(remote)
+	var host, path string
+	if u, err := url.Parse(remote); err == nil && u.Host != "" {
+		if u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "ssh" && u.Scheme != "git" {
+			return "", fmt.Errorf("unsupported git remote %q", remote)
+		}
+		host, path = u.Hostname(), u.Path
+	} else if at := strings.Index(remote, "@"); at >= 0 && strings.Contains(remote[at:], ":") {
+		// scp-style: git@github.com:org/repo.git
+		host, path, _ = strings.Cut(remote[at+1:], ":")
+	} else {
+		return "", fmt.Errorf("unsupported git remote %q", remote)
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || path == "" {
+		return "", fmt.Errorf("unsupported git remote %q", remote)
+	}
+	return "https://" + host + "/" + path, nil
+}
+
+// blobPath is what follows the project URL to reach a file: GitLab puts
+// its routes under /-/.
+func blobPath(web string) string {
+	if strings.Contains(web, "gitlab") {
+		return "/-/blob/"
+	}
+	return "/blob/"
+}
+
+func escapePath(p string) string {
+	parts := strings.Split(p, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// git runs a git command in dir and returns its trimmed output.
+func git(dir string, args ...string) (string, error) {
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
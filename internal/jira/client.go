@@ -0,0 +1,136 @@
+package jira
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/toba/bean-me-up/internal/restapi"
+)
+
+// HTTPDoer sends HTTP requests. *http.Client satisfies it.
+type HTTPDoer = restapi.HTTPDoer
+
+// issueFields are the fields requested when reading an issue.
+const issueFields = "summary,description,status,priority,issuetype,labels,parent,issuelinks"
+
+// Client provides Jira Cloud REST API (v2) access for a site.
+type Client struct {
+	api *restapi.Client
+}
+
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the transport used for API requests.
+func WithHTTPClient(doer HTTPDoer) ClientOption {
+	return func(c *Client) { c.api.HTTP = doer }
+}
+
+// NewClient creates a client for the site at baseURL (e.g.
+// https://acme.atlassian.net) using basic auth with an API token.
+func NewClient(baseURL, email, token string, opts ...ClientOption) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid Jira URL %q", baseURL)
+	}
+
+	api := restapi.New("jira", baseURL)
+	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(email+":"+token))
+	api.Authorize = func(req *http.Request) {
+		req.Header.Set("Authorization", auth)
+	}
+	api.ErrorMessage = func(body []byte) string {
+		var errResp errorResponse
+		if json.Unmarshal(body, &errResp) != nil {
+			return string(body)
+		}
+		msgs := errResp.ErrorMessages
+		for _, field := range slices.Sorted(maps.Keys(errResp.Errors)) {
+			msgs = append(msgs, field+": "+errResp.Errors[field])
+		}
+		return strings.Join(msgs, "; ")
+	}
+
+	c := &Client{api: api}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// GetIssue fetches an issue by key.
+func (c *Client) GetIssue(ctx context.Context, key string) (*Issue, error) {
+	var issue Issue
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "?fields=" + issueFields
+	if err := c.api.Do(ctx, "GET", path, nil, &issue); err != nil {
+		return nil, fmt.Errorf("getting issue %s: %w", key, err)
+	}
+	return &issue, nil
+}
+
+// GetProject fetches a project by key, failing if the token can't see it.
+func (c *Client) GetProject(ctx context.Context, key string) error {
+	if err := c.api.Do(ctx, "GET", "/rest/api/2/project/"+url.PathEscape(key), nil, nil); err != nil {
+		return fmt.Errorf("getting project %s: %w", key, err)
+	}
+	return nil
+}
+
+// CreateIssue creates an issue and returns its key.
+func (c *Client) CreateIssue(ctx context.Context, fields *FieldsRequest) (string, error) {
+	var created IssueKey
+	if err := c.api.Do(ctx, "POST", "/rest/api/2/issue", &issueRequest{Fields: fields}, &created); err != nil {
+		return "", fmt.Errorf("creating issue: %w", err)
+	}
+	return created.Key, nil
+}
+
+// UpdateIssue edits fields of an existing issue.
+func (c *Client) UpdateIssue(ctx context.Context, key string, fields *FieldsRequest) error {
+	if err := c.api.Do(ctx, "PUT", "/rest/api/2/issue/"+url.PathEscape(key), &issueRequest{Fields: fields}, nil); err != nil {
+		return fmt.Errorf("updating issue %s: %w", key, err)
+	}
+	return nil
+}
+
+// GetTransitions lists the workflow transitions available from the issue's current status.
+func (c *Client) GetTransitions(ctx context.Context, key string) ([]Transition, error) {
+	var resp transitionsResponse
+	if err := c.api.Do(ctx, "GET", "/rest/api/2/issue/"+url.PathEscape(key)+"/transitions", nil, &resp); err != nil {
+		return nil, fmt.Errorf("getting transitions for %s: %w", key, err)
+	}
+	return resp.Transitions, nil
+}
+
+// TransitionIssue moves an issue through the transition with the given ID.
+func (c *Client) TransitionIssue(ctx context.Context, key, transitionID string) error {
+	var req transitionRequest
+	req.Transition.ID = transitionID
+	if err := c.api.Do(ctx, "POST", "/rest/api/2/issue/"+url.PathEscape(key)+"/transitions", &req, nil); err != nil {
+		return fmt.Errorf("transitioning %s: %w", key, err)
+	}
+	return nil
+}
+
+// LinkIssues creates a link of linkType reading "inward <outward description> outward",
+// e.g. for "Blocks": inward blocks outward.
+func (c *Client) LinkIssues(ctx context.Context, linkType, inwardKey, outwardKey string) error {
+	// The REST API names the sides after the description each issue shows on
+	// the *other* end, so the blocker goes in inwardIssue.
+	link := &IssueLink{
+		Type:         Named{Name: linkType},
+		InwardIssue:  &IssueKey{Key: inwardKey},
+		OutwardIssue: &IssueKey{Key: outwardKey},
+	}
+	if err := c.api.Do(ctx, "POST", "/rest/api/2/issueLink", link, nil); err != nil {
+		return fmt.Errorf("linking %s to %s: %w", inwardKey, outwardKey, err)
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/toba/bean-me-up/internal/auth"
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+	"github.com/toba/bean-me-up/internal/restapi"
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+// SinkName is the registry key and bean extension name for Jira.
+const SinkName = "jira"
+
+// defaultLinkType is the issue link type used for blocking relationships.
+const defaultLinkType = "Blocks"
+
+// defaultIssueType is used for bean types without a mapping.
+const defaultIssueType = "Task"
+
+func init() {
+	syncer.Register(SinkName, newSinkFromConfig)
+}
+
+// newSinkFromConfig builds the Jira sink using JIRA_API_TOKEN and the
+// account email from JIRA_EMAIL or the config.
+func newSinkFromConfig(cfg *config.Config) (syncer.Sink, error) {
+	jc := cfg.Beans.Jira
+	if jc == nil || jc.URL == "" || jc.Project == "" {
+		return nil, fmt.Errorf("Jira url and project are required in .beans.yml extensions.jira")
+	}
+	token, err := auth.Token(context.Background(), "JIRA_API_TOKEN", SinkName, jc.TokenSource)
+	if err != nil {
+		return nil, err
+	}
+	email := os.Getenv("JIRA_EMAIL")
+	if email == "" {
+		email = jc.Email
+	}
+	if email == "" {
+		return nil, fmt.Errorf("Jira account email is required: set extensions.jira.email or JIRA_EMAIL")
+	}
+	client, err := NewClient(jc.URL, email, token)
+	if err != nil {
+		return nil, err
+	}
+	return NewSink(client, jc), nil
+}
+
+// Sink syncs beans to issues in a Jira Cloud project.
+//
+// Bean types map to issue types, statuses are reached by discovering and
+// following workflow transitions, parents become the issue's parent (epic)
+// and blocking relationships become "Blocks" issue links.
+type Sink struct {
+	client *Client
+	config *config.JiraConfig
+
+	// The project, read before a missing issue counts as deleted
+	project restapi.Container
+}
+
+// NewSink creates a sink that writes issues with client using the mappings in cfg.
+func NewSink(client *Client, cfg *config.JiraConfig) *Sink {
+	s := &Sink{client: client, config: cfg}
+	s.project.Check = func(ctx context.Context) error { return client.GetProject(ctx, cfg.Project) }
+	return s
+}
+
+// Name returns SinkName.
+func (s *Sink) Name() string { return SinkName }
+
+// Prepare is a no-op; workflow transitions are discovered per issue.
+func (s *Sink) Prepare(ctx context.Context) error { return nil }
+
+// GetTask fetches the issue. The *Issue is kept in the ref for UpdateTask.
+func (s *Sink) GetTask(ctx context.Context, taskID string) (*syncer.TaskRef, error) {
+	issue, err := s.client.GetIssue(ctx, taskID)
+	if err != nil {
+		return nil, s.project.Missing(ctx, err)
+	}
+	return s.issueRef(issue), nil
+}
+
+// CreateTask creates an issue under parentTaskID (if any), then transitions
+// it to the bean's mapped status.
+func (s *Sink) CreateTask(ctx context.Context, b *beans.Bean, parentTaskID string) (*syncer.TaskRef, error) {
+	labels := labels(b)
+	fields := &FieldsRequest{
+		Project:     &IssueKey{Key: s.config.Project},
+		Summary:     &b.Title,
+		Description: &b.Body,
+		IssueType:   &Named{Name: s.issueType(b.Type)},
+		Priority:    s.priority(b.Priority),
+		Labels:      &labels,
+	}
+	if parentTaskID != "" {
+		fields.Parent = &IssueKey{Key: parentTaskID}
+	}
+
+	key, err := s.client.CreateIssue(ctx, fields)
+	if err != nil {
+		return nil, err
+	}
+	issue, err := s.client.GetIssue(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.transition(ctx, issue, b); err != nil {
+		// Keep the link; the next update retries the transition
+		return s.issueRef(issue), err
+	}
+	return s.issueRef(issue), nil
+}
+
+// UpdateTask edits the fields that differ from the current issue and
+// transitions it if the mapped status changed.
+func (s *Sink) UpdateTask(ctx context.Context, current *syncer.TaskRef, b *beans.Bean) (*syncer.TaskRef, bool, error) {
+	issue, ok := current.Remote.(*Issue)
+	if !ok {
+		var err error
+		if issue, err = s.client.GetIssue(ctx, current.ID); err != nil {
+			return nil, false, err
+		}
+	}
+
+	update := s.buildUpdateRequest(issue, b)
+	if update.hasChanges() {
+		if err := s.client.UpdateIssue(ctx, issue.Key, update); err != nil {
+			return nil, false, err
+		}
+	}
+	transitioned, err := s.transition(ctx, issue, b)
+	if err != nil {
+		return nil, false, err
+	}
+	if !update.hasChanges() && !transitioned {
+		return current, false, nil
+	}
+
+	updated, err := s.client.GetIssue(ctx, issue.Key)
+	if err != nil {
+		return nil, false, err
+	}
+	return s.issueRef(updated), true, nil
+}
+
+// SyncTags is a no-op: bean tags are sent as labels with every create and update.
+func (s *Sink) SyncTags(ctx context.Context, task *syncer.TaskRef, b *beans.Bean) bool {
+	return false
+}
+
+// SetParent sets the child issue's parent, e.g. linking a story to its epic.
+func (s *Sink) SetParent(ctx context.Context, parentTaskID, childTaskID string) error {
+	child, err := s.client.GetIssue(ctx, childTaskID)
+	if err != nil {
+		return err
+	}
+	if child.Fields.Parent != nil && child.Fields.Parent.Key == parentTaskID {
+		return nil
+	}
+	return s.client.UpdateIssue(ctx, childTaskID, &FieldsRequest{Parent: &IssueKey{Key: parentTaskID}})
+}
+
+// SetRelationship links the blocker to the blocked issue unless the link exists.
+func (s *Sink) SetRelationship(ctx context.Context, blockerTaskID, blockedTaskID string) error {
+	linkType := s.linkType()
+	blocker, err := s.client.GetIssue(ctx, blockerTaskID)
+	if err != nil {
+		return err
+	}
+	for _, l := range blocker.Fields.IssueLinks {
+		if strings.EqualFold(l.Type.Name, linkType) && l.OutwardIssue != nil && l.OutwardIssue.Key == blockedTaskID {
+			return nil
+		}
+	}
+	return s.client.LinkIssues(ctx, linkType, blockerTaskID, blockedTaskID)
+}
+
+// buildUpdateRequest builds a FieldsRequest containing only fields that differ
+// from current. Issue type is only set on create; Jira requires a move to change it.
+func (s *Sink) buildUpdateRequest(current *Issue, b *beans.Bean) *FieldsRequest {
+	update := &FieldsRequest{}
+
+	if current.Fields.Summary != b.Title {
+		update.Summary = &b.Title
+	}
+	if current.Fields.Description != b.Body {
+		update.Description = &b.Body
+	}
+
+	if p := s.priority(b.Priority); p != nil && (current.Fields.Priority == nil || !strings.EqualFold(current.Fields.Priority.Name, p.Name)) {
+		update.Priority = p
+	}
+
+	labels := labels(b)
+	currentLabels := slices.Clone(current.Fields.Labels)
+	slices.Sort(currentLabels)
+	if !slices.Equal(currentLabels, labels) {
+		update.Labels = &labels
+	}
+
+	return update
+}
+
+// transition moves the issue to the bean's mapped status using whichever
+// available transition leads there. It reports whether a transition was made.
+func (s *Sink) transition(ctx context.Context, issue *Issue, b *beans.Bean) (bool, error) {
+	want := lookup(s.config.StatusMapping, config.DefaultJiraStatusMapping, b.Status)
+	if want == "" || (issue.Fields.Status != nil && strings.EqualFold(issue.Fields.Status.Name, want)) {
+		return false, nil
+	}
+
+	transitions, err := s.client.GetTransitions(ctx, issue.Key)
+	if err != nil {
+		return false, err
+	}
+	var available []string
+	for _, t := range transitions {
+		if strings.EqualFold(t.To.Name, want) || strings.EqualFold(t.Name, want) {
+			return true, s.client.TransitionIssue(ctx, issue.Key, t.ID)
+		}
+		available = append(available, t.To.Name)
+	}
+	return false, fmt.Errorf("no transition from %q to %q for %s (available: %s)",
+		statusName(issue), want, issue.Key, strings.Join(available, ", "))
+}
+
+// issueType returns the mapped issue type name for a bean type.
+func (s *Sink) issueType(beanType string) string {
+	if t := lookup(s.config.TypeMapping, config.DefaultJiraTypeMapping, beanType); t != "" {
+		return t
+	}
+	return defaultIssueType
+}
+
+// priority returns the mapped priority for a bean priority, or nil if unmapped.
+func (s *Sink) priority(beanPriority string) *Named {
+	if p := lookup(s.config.PriorityMapping, config.DefaultJiraPriorityMapping, beanPriority); p != "" {
+		return &Named{Name: p}
+	}
+	return nil
+}
+
+// linkType returns the configured blocking link type.
+func (s *Sink) linkType() string {
+	if s.config.LinkType != "" {
+		return s.config.LinkType
+	}
+	return defaultLinkType
+}
+
+// issueRef converts an issue to a sink-neutral reference.
+func (s *Sink) issueRef(issue *Issue) *syncer.TaskRef {
+	return &syncer.TaskRef{
+		ID:     issue.Key,
+		URL:    strings.TrimRight(s.config.URL, "/") + "/browse/" + issue.Key,
+		Tags:   issue.Fields.Labels,
+		Remote: issue,
+	}
+}
+
+// labels returns the sorted label set for a bean. Jira labels can't contain
+// spaces, so they are replaced with dashes.
+func labels(b *beans.Bean) []string {
+	labels := make([]string, 0, len(b.Tags))
+	for _, t := range b.Tags {
+		l := strings.ReplaceAll(t, " ", "-")
+		if !slices.Contains(labels, l) {
+			labels = append(labels, l)
+		}
+	}
+	slices.Sort(labels)
+	return labels
+}
+
+// lookup returns custom[key] if set, falling back to defaults[key].
+func lookup(custom, defaults map[string]string, key string) string {
+	if key == "" {
+		return ""
+	}
+	if v, ok := custom[key]; ok {
+		return v
+	}
+	return defaults[key]
+}
+
+func statusName(issue *Issue) string {
+	if issue.Fields.Status == nil {
+		return ""
+	}
+	return issue.Fields.Status.Name
+}
@@ -0,0 +1,205 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+	"github.com/toba/bean-me-up/internal/syncer"
+	"github.com/toba/bean-me-up/internal/syncertest"
+)
+
+// fakeJira is an in-memory Jira project with a To Do → In Progress → Done workflow.
+type fakeJira struct {
+	mu     sync.Mutex
+	issues map[string]*Issue
+	links  []IssueLink
+}
+
+var fakeWorkflow = []Transition{
+	{ID: "11", Name: "To Do", To: Named{Name: "To Do"}},
+	{ID: "21", Name: "Start", To: Named{Name: "In Progress"}},
+	{ID: "31", Name: "Resolve", To: Named{Name: "Done"}},
+}
+
+func newFakeJira(t *testing.T) (*fakeJira, *Client) {
+	t.Helper()
+	f := &fakeJira{issues: map[string]*Issue{}}
+	url := syncertest.Serve(t, f)
+
+	client, err := NewClient(url, "me@example.com", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f, client
+}
+
+func (f *fakeJira) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if user, pass, ok := r.BasicAuth(); !ok || user != "me@example.com" || pass != "test" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.Method == "POST" && r.URL.Path == "/rest/api/2/issue":
+		var req issueRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		key := "WID-" + strconv.Itoa(len(f.issues)+1)
+		issue := &Issue{Key: key, Fields: Fields{Status: &Named{Name: "To Do"}, IssueType: req.Fields.IssueType}}
+		applyFields(issue, req.Fields)
+		f.issues[key] = issue
+		_ = json.NewEncoder(w).Encode(IssueKey{Key: key})
+		return
+	case r.URL.Path == "/rest/api/2/project/WID":
+		_, _ = w.Write([]byte(`{"key":"WID"}`))
+		return
+	case r.Method == "POST" && r.URL.Path == "/rest/api/2/issueLink":
+		var link IssueLink
+		_ = json.NewDecoder(r.Body).Decode(&link)
+		f.links = append(f.links, link)
+		if blocker, ok := f.issues[link.InwardIssue.Key]; ok {
+			blocker.Fields.IssueLinks = append(blocker.Fields.IssueLinks, IssueLink{Type: link.Type, OutwardIssue: link.OutwardIssue})
+		}
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+
+	rest := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")
+	key, sub, _ := strings.Cut(rest, "/")
+	issue, ok := f.issues[key]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errorMessages":["Issue does not exist or you do not have permission to see it."]}`))
+		return
+	}
+
+	switch {
+	case sub == "transitions" && r.Method == "GET":
+		_ = json.NewEncoder(w).Encode(transitionsResponse{Transitions: fakeWorkflow})
+	case sub == "transitions" && r.Method == "POST":
+		var req transitionRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		for _, t := range fakeWorkflow {
+			if t.ID == req.Transition.ID {
+				issue.Fields.Status = &Named{Name: t.To.Name}
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == "PUT":
+		var req issueRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		applyFields(issue, req.Fields)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		_ = json.NewEncoder(w).Encode(issue)
+	}
+}
+
+func applyFields(issue *Issue, req *FieldsRequest) {
+	if req.Summary != nil {
+		issue.Fields.Summary = *req.Summary
+	}
+	if req.Description != nil {
+		issue.Fields.Description = *req.Description
+	}
+	if req.Priority != nil {
+		issue.Fields.Priority = req.Priority
+	}
+	if req.Labels != nil {
+		issue.Fields.Labels = *req.Labels
+	}
+	if req.Parent != nil {
+		issue.Fields.Parent = req.Parent
+	}
+}
+
+func TestSink(t *testing.T) {
+	syncertest.RunSinkTests(t, func(t *testing.T) syncertest.SinkFixture {
+		_, client := newFakeJira(t)
+		return syncertest.SinkFixture{Sink: NewSink(client, &config.JiraConfig{URL: "https://acme.atlassian.net", Project: "WID"}), DeletedTaskID: "WID-99"}
+	})
+}
+
+func TestSink_MapsBeanFields(t *testing.T) {
+	jira, client := newFakeJira(t)
+	sink := NewSink(client, &config.JiraConfig{URL: "https://acme.atlassian.net", Project: "WID"})
+	state := syncertest.NewState()
+
+	beanList := []beans.Bean{
+		{ID: "epic", Title: "Epic", Type: "epic", Status: "in-progress"},
+		{ID: "fix", Title: "Fix crash", Type: "bug", Priority: "high", Status: "todo", Parent: "epic", Tags: []string{"needs review", "ui"}, Blocking: []string{"done"}},
+		{ID: "done", Title: "Shipped", Type: "feature", Status: "completed"},
+	}
+
+	results := syncertest.Sync(t, sink, syncer.Options{}, state, beanList)
+	syncertest.RequireActions(t, results, "created")
+
+	epic := jira.issues[*state.GetTaskID("epic")]
+	fix := jira.issues[*state.GetTaskID("fix")]
+	done := jira.issues[*state.GetTaskID("done")]
+
+	if epic.Fields.Status.Name != "In Progress" || done.Fields.Status.Name != "Done" {
+		t.Errorf("statuses = %q, %q; want In Progress, Done", epic.Fields.Status.Name, done.Fields.Status.Name)
+	}
+	if fix.Fields.IssueType.Name != "Bug" || done.Fields.IssueType.Name != "Story" {
+		t.Errorf("issue types = %q, %q; want Bug, Story", fix.Fields.IssueType.Name, done.Fields.IssueType.Name)
+	}
+	if fix.Fields.Parent == nil || fix.Fields.Parent.Key != epic.Key {
+		t.Errorf("parent = %+v, want %s", fix.Fields.Parent, epic.Key)
+	}
+	if fix.Fields.Priority == nil || fix.Fields.Priority.Name != "High" {
+		t.Errorf("priority = %+v, want High", fix.Fields.Priority)
+	}
+	if strings.Join(fix.Fields.Labels, ",") != "needs-review,ui" {
+		t.Errorf("labels = %v", fix.Fields.Labels)
+	}
+	if results[1].TaskURL != "https://acme.atlassian.net/browse/"+fix.Key {
+		t.Errorf("task URL = %q", results[1].TaskURL)
+	}
+	if len(jira.links) != 1 || jira.links[0].InwardIssue.Key != fix.Key || jira.links[0].OutwardIssue.Key != done.Key {
+		t.Errorf("links = %+v, want %s blocks %s", jira.links, fix.Key, done.Key)
+	}
+
+	// Syncing again leaves the issues and links alone
+	syncertest.RequireActions(t, syncertest.Sync(t, sink, syncer.Options{Force: true}, state, beanList), "unchanged")
+	if len(jira.links) != 1 {
+		t.Errorf("second sync created duplicate links: %+v", jira.links)
+	}
+}
+
+func TestSink_UnreachableStatus(t *testing.T) {
+	jira, client := newFakeJira(t)
+	sink := NewSink(client, &config.JiraConfig{
+		URL:           "https://acme.atlassian.net",
+		Project:       "WID",
+		StatusMapping: map[string]string{"completed": "Released"},
+	})
+	state := syncertest.NewState()
+	bs := []beans.Bean{{ID: "b", Title: "B", Status: "completed"}}
+
+	results, _ := syncer.New(sink, syncer.Options{}, state).SyncBeans(context.Background(), bs)
+	if results[0].Error == nil || !strings.Contains(results[0].Error.Error(), "available: To Do, In Progress, Done") {
+		t.Errorf("error = %v, want list of available transitions", results[0].Error)
+	}
+	if state.GetTaskID("b") == nil || state.GetSyncedAt("b") != nil {
+		t.Fatalf("task ID = %v, synced at = %v; want linked but unsynced", state.GetTaskID("b"), state.GetSyncedAt("b"))
+	}
+
+	// Syncing again retries the transition on the same issue
+	results, _ = syncer.New(sink, syncer.Options{}, state).SyncBeans(context.Background(), bs)
+	if results[0].Error == nil {
+		t.Error("second sync succeeded, want the transition to fail again")
+	}
+	if len(jira.issues) != 1 {
+		t.Errorf("issues = %d, want 1", len(jira.issues))
+	}
+}
@@ -0,0 +1,88 @@
+// Package jira provides Jira Cloud integration.
+package jira
+
+// Issue holds issue data returned from Jira.
+type Issue struct {
+	ID     string `json:"id"`
+	Key    string `json:"key"`
+	Fields Fields `json:"fields"`
+}
+
+// Fields are the issue fields beanup reads.
+type Fields struct {
+	Summary     string      `json:"summary"`
+	Description string      `json:"description"`
+	Status      *Named      `json:"status"`
+	Priority    *Named      `json:"priority"`
+	IssueType   *Named      `json:"issuetype"`
+	Labels      []string    `json:"labels"`
+	Parent      *IssueKey   `json:"parent"`
+	IssueLinks  []IssueLink `json:"issuelinks"`
+}
+
+// Named is a Jira field value identified by name (status, priority, issue type, link type).
+type Named struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+// IssueKey refers to an issue or project by key.
+type IssueKey struct {
+	Key string `json:"key"`
+}
+
+// IssueLink is a link between two issues. Only the far side is set when read
+// from an issue's issuelinks field.
+type IssueLink struct {
+	Type         Named     `json:"type"`
+	InwardIssue  *IssueKey `json:"inwardIssue,omitempty"`
+	OutwardIssue *IssueKey `json:"outwardIssue,omitempty"`
+}
+
+// Transition is a workflow transition available from an issue's current status.
+type Transition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	To   Named  `json:"to"`
+}
+
+// FieldsRequest holds the fields for creating or editing an issue.
+// Nil fields are left unchanged on update.
+type FieldsRequest struct {
+	Project     *IssueKey `json:"project,omitempty"`
+	Summary     *string   `json:"summary,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	IssueType   *Named    `json:"issuetype,omitempty"`
+	Priority    *Named    `json:"priority,omitempty"`
+	Labels      *[]string `json:"labels,omitempty"`
+	Parent      *IssueKey `json:"parent,omitempty"`
+}
+
+// hasChanges returns true if any field is set in the update request.
+func (r *FieldsRequest) hasChanges() bool {
+	return r.Summary != nil || r.Description != nil || r.IssueType != nil ||
+		r.Priority != nil || r.Labels != nil || r.Parent != nil
+}
+
+// issueRequest wraps fields for the create and edit endpoints.
+type issueRequest struct {
+	Fields *FieldsRequest `json:"fields"`
+}
+
+// transitionsResponse is the body of GET /issue/{key}/transitions.
+type transitionsResponse struct {
+	Transitions []Transition `json:"transitions"`
+}
+
+// transitionRequest is the body of POST /issue/{key}/transitions.
+type transitionRequest struct {
+	Transition struct {
+		ID string `json:"id"`
+	} `json:"transition"`
+}
+
+// errorResponse is Jira's error body.
+type errorResponse struct {
+	ErrorMessages []string          `json:"errorMessages"`
+	Errors        map[string]string `json:"errors"`
+}
@@ -0,0 +1,195 @@
+package linear
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/toba/bean-me-up/internal/restapi"
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+// DefaultBaseURL is the Linear API endpoint; queries are POSTed to /graphql.
+const DefaultBaseURL = "https://api.linear.app"
+
+// HTTPDoer sends HTTP requests. *http.Client satisfies it.
+type HTTPDoer = restapi.HTTPDoer
+
+// issueFields is the selection set used for every issue query and mutation.
+const issueFields = `id identifier url title description priority
+	state { id name type }
+	parent { id identifier }
+	relations { nodes { type relatedIssue { id identifier } } }`
+
+// Error is a GraphQL error returned with an HTTP 200 response.
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return "linear API error: " + e.Message
+	}
+	return fmt.Sprintf("linear API error: %s (code: %s)", e.Message, e.Code)
+}
+
+// Is matches syncer.ErrTaskNotFound for Linear's "Entity not found" errors.
+func (e *Error) Is(target error) bool {
+	return target == syncer.ErrTaskNotFound && strings.Contains(strings.ToLower(e.Message), "not found")
+}
+
+// Client provides Linear GraphQL API access.
+type Client struct {
+	api *restapi.Client
+}
+
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the transport used for API requests.
+func WithHTTPClient(doer HTTPDoer) ClientOption {
+	return func(c *Client) { c.api.HTTP = doer }
+}
+
+// WithBaseURL points the client at a different API root, such as a mock server.
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) { c.api.BaseURL = strings.TrimRight(url, "/") }
+}
+
+// NewClient creates a client authenticated with a personal API key.
+func NewClient(apiKey string, opts ...ClientOption) *Client {
+	api := restapi.New("linear", DefaultBaseURL)
+	api.Authorize = func(req *http.Request) {
+		// Personal API keys are sent bare; OAuth tokens carry their own "Bearer " prefix
+		req.Header.Set("Authorization", apiKey)
+	}
+	// Linear rejects over-limit requests with 400 and an empty request budget
+	api.RateLimited = func(resp *http.Response) bool {
+		return resp.Header.Get("X-RateLimit-Requests-Remaining") == "0"
+	}
+	api.ErrorMessage = func(body []byte) string {
+		var resp struct {
+			Errors []graphQLError `json:"errors"`
+		}
+		_ = json.Unmarshal(body, &resp)
+		msgs := make([]string, len(resp.Errors))
+		for i, e := range resp.Errors {
+			msgs[i] = e.Message
+		}
+		return strings.Join(msgs, "; ")
+	}
+
+	c := &Client{api: api}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// query runs a GraphQL operation and decodes its data into data.
+func (c *Client) query(ctx context.Context, query string, vars map[string]any, data any) error {
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphQLError  `json:"errors"`
+	}
+	if err := c.api.Do(ctx, "POST", "/graphql", &graphQLRequest{Query: query, Variables: vars}, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		e := resp.Errors[0]
+		msg := e.Message
+		if e.Extensions.UserPresentableMessage != "" {
+			msg += ": " + e.Extensions.UserPresentableMessage
+		}
+		return &Error{Code: e.Extensions.Code, Message: msg}
+	}
+	if err := json.Unmarshal(resp.Data, data); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// GetTeam fetches a team and its workflow states by key.
+func (c *Client) GetTeam(ctx context.Context, key string) (*Team, error) {
+	var data struct {
+		Teams struct {
+			Nodes []Team `json:"nodes"`
+		} `json:"teams"`
+	}
+	q := `query($key: String!) { teams(filter: { key: { eq: $key } }) { nodes { id key states { nodes { id name type } } } } }`
+	if err := c.query(ctx, q, map[string]any{"key": key}, &data); err != nil {
+		return nil, fmt.Errorf("getting team %s: %w", key, err)
+	}
+	if len(data.Teams.Nodes) == 0 {
+		return nil, fmt.Errorf("team %q not found", key)
+	}
+	return &data.Teams.Nodes[0], nil
+}
+
+// GetIssue fetches an issue by UUID or identifier (e.g. "ENG-123").
+func (c *Client) GetIssue(ctx context.Context, id string) (*Issue, error) {
+	var data struct {
+		Issue *Issue `json:"issue"`
+	}
+	q := `query($id: String!) { issue(id: $id) { ` + issueFields + ` } }`
+	if err := c.query(ctx, q, map[string]any{"id": id}, &data); err != nil {
+		return nil, fmt.Errorf("getting issue %s: %w", id, err)
+	}
+	if data.Issue == nil {
+		return nil, fmt.Errorf("getting issue %s: %w", id, syncer.ErrTaskNotFound)
+	}
+	return data.Issue, nil
+}
+
+// CreateIssue creates a new issue.
+func (c *Client) CreateIssue(ctx context.Context, input *IssueInput) (*Issue, error) {
+	var data struct {
+		IssueCreate struct {
+			Issue *Issue `json:"issue"`
+		} `json:"issueCreate"`
+	}
+	q := `mutation($input: IssueCreateInput!) { issueCreate(input: $input) { issue { ` + issueFields + ` } } }`
+	if err := c.query(ctx, q, map[string]any{"input": input}, &data); err != nil {
+		return nil, fmt.Errorf("creating issue: %w", err)
+	}
+	if data.IssueCreate.Issue == nil {
+		return nil, fmt.Errorf("creating issue: no issue returned")
+	}
+	return data.IssueCreate.Issue, nil
+}
+
+// UpdateIssue edits an existing issue.
+func (c *Client) UpdateIssue(ctx context.Context, id string, input *IssueInput) (*Issue, error) {
+	var data struct {
+		IssueUpdate struct {
+			Issue *Issue `json:"issue"`
+		} `json:"issueUpdate"`
+	}
+	q := `mutation($id: String!, $input: IssueUpdateInput!) { issueUpdate(id: $id, input: $input) { issue { ` + issueFields + ` } } }`
+	if err := c.query(ctx, q, map[string]any{"id": id, "input": input}, &data); err != nil {
+		return nil, fmt.Errorf("updating issue %s: %w", id, err)
+	}
+	if data.IssueUpdate.Issue == nil {
+		return nil, fmt.Errorf("updating issue %s: no issue returned", id)
+	}
+	return data.IssueUpdate.Issue, nil
+}
+
+// CreateRelation relates issueID to relatedIssueID, e.g. type "blocks"
+// means issueID blocks relatedIssueID.
+func (c *Client) CreateRelation(ctx context.Context, issueID, relatedIssueID, relationType string) error {
+	var data struct {
+		IssueRelationCreate struct {
+			Success bool `json:"success"`
+		} `json:"issueRelationCreate"`
+	}
+	q := `mutation($input: IssueRelationCreateInput!) { issueRelationCreate(input: $input) { success } }`
+	input := map[string]any{"issueId": issueID, "relatedIssueId": relatedIssueID, "type": relationType}
+	if err := c.query(ctx, q, map[string]any{"input": input}, &data); err != nil {
+		return fmt.Errorf("relating %s to %s: %w", issueID, relatedIssueID, err)
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package linear
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/toba/bean-me-up/internal/auth"
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+// SinkName is the registry key and bean extension name for Linear.
+const SinkName = "linear"
+
+func init() {
+	syncer.Register(SinkName, newSinkFromConfig)
+}
+
+// newSinkFromConfig builds the Linear sink using LINEAR_API_KEY.
+func newSinkFromConfig(cfg *config.Config) (syncer.Sink, error) {
+	lc := cfg.Beans.Linear
+	if lc == nil || lc.Team == "" {
+		return nil, fmt.Errorf("Linear team is required in .beans.yml extensions.linear")
+	}
+	apiKey, err := auth.Token(context.Background(), "LINEAR_API_KEY", SinkName, lc.TokenSource)
+	if err != nil {
+		return nil, err
+	}
+	return NewSink(NewClient(apiKey), lc), nil
+}
+
+// Sink syncs beans to issues in a Linear team.
+//
+// Statuses map to the team's workflow states, parents become sub-issue
+// links, and blocking relationships become "blocks" issue relations.
+// Task IDs are issue identifiers such as "ENG-123".
+type Sink struct {
+	client *Client
+	config *config.LinearConfig
+
+	// Loaded by Prepare
+	teamID string
+	states map[string]WorkflowState // lowercased name -> state
+
+	// Identifier -> UUID; parent and relation inputs take UUIDs
+	mu  sync.Mutex
+	ids map[string]string
+}
+
+// NewSink creates a sink that writes issues with client using the mappings in cfg.
+func NewSink(client *Client, cfg *config.LinearConfig) *Sink {
+	return &Sink{client: client, config: cfg, ids: make(map[string]string)}
+}
+
+// Name returns SinkName.
+func (s *Sink) Name() string { return SinkName }
+
+// Prepare resolves the team and loads its workflow states.
+func (s *Sink) Prepare(ctx context.Context) error {
+	team, err := s.client.GetTeam(ctx, s.config.Team)
+	if err != nil {
+		return err
+	}
+	s.teamID = team.ID
+	s.states = make(map[string]WorkflowState, len(team.States.Nodes))
+	for _, st := range team.States.Nodes {
+		s.states[strings.ToLower(st.Name)] = st
+	}
+	return nil
+}
+
+// GetTask fetches the issue. The *Issue is kept in the ref for UpdateTask.
+func (s *Sink) GetTask(ctx context.Context, taskID string) (*syncer.TaskRef, error) {
+	issue, err := s.client.GetIssue(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return s.issueRef(issue), nil
+}
+
+// CreateTask creates an issue in the bean's mapped state, as a sub-issue of
+// parentTaskID if set.
+func (s *Sink) CreateTask(ctx context.Context, b *beans.Bean, parentTaskID string) (*syncer.TaskRef, error) {
+	state, err := s.state(b)
+	if err != nil {
+		return nil, err
+	}
+	input := &IssueInput{
+		TeamID:      &s.teamID,
+		Title:       &b.Title,
+		Description: &b.Body,
+		Priority:    s.priority(b),
+	}
+	if state != nil {
+		input.StateID = &state.ID
+	}
+	if parentTaskID != "" {
+		parentID, err := s.uuid(ctx, parentTaskID)
+		if err != nil {
+			return nil, err
+		}
+		input.ParentID = &parentID
+	}
+
+	issue, err := s.client.CreateIssue(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return s.issueRef(issue), nil
+}
+
+// UpdateTask sends only the fields that differ from the current issue.
+func (s *Sink) UpdateTask(ctx context.Context, current *syncer.TaskRef, b *beans.Bean) (*syncer.TaskRef, bool, error) {
+	issue, ok := current.Remote.(*Issue)
+	if !ok {
+		var err error
+		if issue, err = s.client.GetIssue(ctx, current.ID); err != nil {
+			return nil, false, err
+		}
+	}
+
+	update, err := s.buildUpdateInput(issue, b)
+	if err != nil {
+		return nil, false, err
+	}
+	if !update.hasChanges() {
+		return current, false, nil
+	}
+	updated, err := s.client.UpdateIssue(ctx, issue.ID, update)
+	if err != nil {
+		return nil, false, err
+	}
+	return s.issueRef(updated), true, nil
+}
+
+// SyncTags is a no-op: Linear labels are team-managed and not synced from tags.
+func (s *Sink) SyncTags(ctx context.Context, task *syncer.TaskRef, b *beans.Bean) bool {
+	return false
+}
+
+// SetParent makes the child issue a sub-issue of the parent.
+func (s *Sink) SetParent(ctx context.Context, parentTaskID, childTaskID string) error {
+	child, err := s.client.GetIssue(ctx, childTaskID)
+	if err != nil {
+		return err
+	}
+	if child.Parent != nil && child.Parent.Identifier == parentTaskID {
+		return nil
+	}
+	parentID, err := s.uuid(ctx, parentTaskID)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.UpdateIssue(ctx, child.ID, &IssueInput{ParentID: &parentID})
+	return err
+}
+
+// SetRelationship adds a "blocks" relation from blocker to blocked unless it exists.
+func (s *Sink) SetRelationship(ctx context.Context, blockerTaskID, blockedTaskID string) error {
+	blocker, err := s.client.GetIssue(ctx, blockerTaskID)
+	if err != nil {
+		return err
+	}
+	for _, r := range blocker.Relations.Nodes {
+		if r.Type == "blocks" && r.RelatedIssue.Identifier == blockedTaskID {
+			return nil
+		}
+	}
+	blockedID, err := s.uuid(ctx, blockedTaskID)
+	if err != nil {
+		return err
+	}
+	return s.client.CreateRelation(ctx, blocker.ID, blockedID, "blocks")
+}
+
+// buildUpdateInput builds an IssueInput containing only fields that differ from current.
+func (s *Sink) buildUpdateInput(current *Issue, b *beans.Bean) (*IssueInput, error) {
+	update := &IssueInput{}
+
+	if current.Title != b.Title {
+		update.Title = &b.Title
+	}
+	if current.Description != b.Body {
+		update.Description = &b.Body
+	}
+	if p := s.priority(b); p != nil && *p != current.Priority {
+		update.Priority = p
+	}
+
+	state, err := s.state(b)
+	if err != nil {
+		return nil, err
+	}
+	if state != nil && state.ID != current.State.ID {
+		update.StateID = &state.ID
+	}
+
+	return update, nil
+}
+
+// state returns the workflow state for the bean's status, or nil if unmapped.
+func (s *Sink) state(b *beans.Bean) (*WorkflowState, error) {
+	name, ok := s.config.StatusMapping[b.Status]
+	if !ok {
+		name = config.DefaultLinearStatusMapping[b.Status]
+	}
+	if name == "" {
+		return nil, nil
+	}
+	st, ok := s.states[strings.ToLower(name)]
+	if !ok {
+		names := make([]string, 0, len(s.states))
+		for _, st := range s.states {
+			names = append(names, st.Name)
+		}
+		slices.Sort(names)
+		return nil, fmt.Errorf("workflow state %q not found in team %s (available: %s)",
+			name, s.config.Team, strings.Join(names, ", "))
+	}
+	return &st, nil
+}
+
+// priority returns the Linear priority for the bean, or nil if unmapped.
+func (s *Sink) priority(b *beans.Bean) *int {
+	if b.Priority == "" {
+		return nil
+	}
+	if p, ok := s.config.PriorityMapping[b.Priority]; ok {
+		return &p
+	}
+	if p, ok := config.DefaultLinearPriorityMapping[b.Priority]; ok {
+		return &p
+	}
+	return nil
+}
+
+// uuid resolves an issue identifier to its UUID, fetching it if not yet seen.
+func (s *Sink) uuid(ctx context.Context, identifier string) (string, error) {
+	s.mu.Lock()
+	id, ok := s.ids[identifier]
+	s.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+	issue, err := s.client.GetIssue(ctx, identifier)
+	if err != nil {
+		return "", err
+	}
+	s.issueRef(issue)
+	return issue.ID, nil
+}
+
+// issueRef converts an issue to a sink-neutral reference, remembering its UUID.
+func (s *Sink) issueRef(issue *Issue) *syncer.TaskRef {
+	s.mu.Lock()
+	s.ids[issue.Identifier] = issue.ID
+	s.mu.Unlock()
+	return &syncer.TaskRef{ID: issue.Identifier, URL: issue.URL, Remote: issue}
+}
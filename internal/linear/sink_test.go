@@ -0,0 +1,195 @@
+package linear
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+	"github.com/toba/bean-me-up/internal/syncer"
+	"github.com/toba/bean-me-up/internal/syncertest"
+)
+
+var fakeStates = []WorkflowState{
+	{ID: "s-backlog", Name: "Backlog", Type: "backlog"},
+	{ID: "s-todo", Name: "Todo", Type: "unstarted"},
+	{ID: "s-doing", Name: "In Progress", Type: "started"},
+	{ID: "s-done", Name: "Done", Type: "completed"},
+	{ID: "s-canceled", Name: "Canceled", Type: "canceled"},
+}
+
+// fakeLinear is an in-memory Linear GraphQL API for team ENG. It dispatches
+// on the root field named in the query.
+type fakeLinear struct {
+	mu     sync.Mutex
+	issues map[string]*Issue // by UUID
+}
+
+func newFakeLinear(t *testing.T) (*fakeLinear, *Client) {
+	t.Helper()
+	f := &fakeLinear{issues: map[string]*Issue{}}
+	url := syncertest.Serve(t, f)
+	return f, NewClient("lin_api_test", WithBaseURL(url))
+}
+
+func (f *fakeLinear) find(id string) *Issue {
+	if issue, ok := f.issues[id]; ok {
+		return issue
+	}
+	for _, issue := range f.issues {
+		if issue.Identifier == id {
+			return issue
+		}
+	}
+	return nil
+}
+
+func (f *fakeLinear) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "lin_api_test" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Query     string `json:"query"`
+		Variables struct {
+			Key   string          `json:"key"`
+			ID    string          `json:"id"`
+			Input json.RawMessage `json:"input"`
+		} `json:"variables"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	var input IssueInput
+	_ = json.Unmarshal(req.Variables.Input, &input)
+
+	reply := func(data any) { _ = json.NewEncoder(w).Encode(map[string]any{"data": data}) }
+
+	switch {
+	case strings.Contains(req.Query, "teams("):
+		team := Team{ID: "team-eng", Key: "ENG"}
+		team.States.Nodes = fakeStates
+		reply(map[string]any{"teams": map[string]any{"nodes": []Team{team}}})
+
+	case strings.Contains(req.Query, "issueCreate("):
+		n := len(f.issues) + 1
+		issue := &Issue{ID: "uuid-" + strconv.Itoa(n), Identifier: "ENG-" + strconv.Itoa(n), State: fakeStates[0]}
+		issue.URL = "https://linear.app/acme/issue/" + issue.Identifier
+		f.issues[issue.ID] = issue
+		f.apply(issue, &input)
+		reply(map[string]any{"issueCreate": map[string]any{"issue": issue}})
+
+	case strings.Contains(req.Query, "issueRelationCreate("):
+		var rel struct {
+			IssueID        string `json:"issueId"`
+			RelatedIssueID string `json:"relatedIssueId"`
+			Type           string `json:"type"`
+		}
+		_ = json.Unmarshal(req.Variables.Input, &rel)
+		from, to := f.issues[rel.IssueID], f.issues[rel.RelatedIssueID]
+		from.Relations.Nodes = append(from.Relations.Nodes, Relation{Type: rel.Type, RelatedIssue: IssueID{ID: to.ID, Identifier: to.Identifier}})
+		reply(map[string]any{"issueRelationCreate": map[string]any{"success": true}})
+
+	default:
+		issue := f.find(req.Variables.ID)
+		if issue == nil {
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data":   nil,
+				"errors": []map[string]any{{"message": "Entity not found: Issue", "extensions": map[string]string{"code": "INVALID_INPUT"}}},
+			})
+			return
+		}
+		if strings.Contains(req.Query, "issueUpdate(") {
+			f.apply(issue, &input)
+			reply(map[string]any{"issueUpdate": map[string]any{"issue": issue}})
+			return
+		}
+		reply(map[string]any{"issue": issue})
+	}
+}
+
+func (f *fakeLinear) apply(issue *Issue, in *IssueInput) {
+	if in.Title != nil {
+		issue.Title = *in.Title
+	}
+	if in.Description != nil {
+		issue.Description = *in.Description
+	}
+	if in.Priority != nil {
+		issue.Priority = *in.Priority
+	}
+	if in.StateID != nil {
+		for _, st := range fakeStates {
+			if st.ID == *in.StateID {
+				issue.State = st
+			}
+		}
+	}
+	if in.ParentID != nil {
+		parent := f.issues[*in.ParentID]
+		issue.Parent = &IssueID{ID: parent.ID, Identifier: parent.Identifier}
+	}
+}
+
+func TestSink(t *testing.T) {
+	syncertest.RunSinkTests(t, func(t *testing.T) syncertest.SinkFixture {
+		_, client := newFakeLinear(t)
+		return syncertest.SinkFixture{Sink: NewSink(client, &config.LinearConfig{Team: "ENG"}), DeletedTaskID: "ENG-99"}
+	})
+}
+
+func TestSink_MapsBeanFields(t *testing.T) {
+	linear, client := newFakeLinear(t)
+	sink := NewSink(client, &config.LinearConfig{Team: "ENG"})
+	state := syncertest.NewState()
+
+	beanList := []beans.Bean{
+		{ID: "epic", Title: "Epic", Type: "epic", Status: "in-progress"},
+		{ID: "fix", Title: "Fix crash", Type: "bug", Priority: "critical", Status: "todo", Parent: "epic", Blocking: []string{"done"}},
+		{ID: "done", Title: "Shipped", Type: "task", Status: "scrapped"},
+	}
+
+	results := syncertest.Sync(t, sink, syncer.Options{}, state, beanList)
+	syncertest.RequireActions(t, results, "created")
+
+	epic := linear.find(*state.GetTaskID("epic"))
+	fix := linear.find(*state.GetTaskID("fix"))
+	done := linear.find(*state.GetTaskID("done"))
+
+	if epic.State.Name != "In Progress" || fix.State.Name != "Todo" || done.State.Name != "Canceled" {
+		t.Errorf("states = %q, %q, %q", epic.State.Name, fix.State.Name, done.State.Name)
+	}
+	if fix.Priority != 1 {
+		t.Errorf("priority = %d, want 1 (urgent)", fix.Priority)
+	}
+	if fix.Parent == nil || fix.Parent.ID != epic.ID {
+		t.Errorf("parent = %+v, want %s", fix.Parent, epic.Identifier)
+	}
+	if len(fix.Relations.Nodes) != 1 || fix.Relations.Nodes[0].Type != "blocks" || fix.Relations.Nodes[0].RelatedIssue.ID != done.ID {
+		t.Errorf("relations = %+v, want blocks %s", fix.Relations.Nodes, done.Identifier)
+	}
+
+	// Syncing again changes nothing and adds no duplicate relations
+	syncertest.RequireActions(t, syncertest.Sync(t, sink, syncer.Options{Force: true}, state, beanList), "unchanged")
+	if len(fix.Relations.Nodes) != 1 {
+		t.Errorf("second sync duplicated relations: %+v", fix.Relations.Nodes)
+	}
+}
+
+func TestSink_UnknownState(t *testing.T) {
+	_, client := newFakeLinear(t)
+	sink := NewSink(client, &config.LinearConfig{Team: "ENG", StatusMapping: map[string]string{"todo": "Ready"}})
+
+	results, _ := syncer.New(sink, syncer.Options{}, syncertest.NewState()).SyncBeans(context.Background(),
+		[]beans.Bean{{ID: "b", Title: "B", Status: "todo"}})
+	if results[0].Error == nil || !strings.Contains(results[0].Error.Error(), `"Ready" not found`) {
+		t.Errorf("error = %v, want unknown state", results[0].Error)
+	}
+}
@@ -0,0 +1,80 @@
+// Package linear provides Linear integration via its GraphQL API.
+package linear
+
+// Issue holds issue data returned from Linear.
+type Issue struct {
+	ID          string             `json:"id"`
+	Identifier  string             `json:"identifier"` // e.g. "ENG-123"
+	URL         string             `json:"url"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	Priority    int                `json:"priority"`
+	State       WorkflowState      `json:"state"`
+	Parent      *IssueID           `json:"parent"`
+	Relations   relationConnection `json:"relations"`
+}
+
+// IssueID refers to an issue.
+type IssueID struct {
+	ID         string `json:"id"`
+	Identifier string `json:"identifier"`
+}
+
+// WorkflowState is a state in a team's workflow.
+type WorkflowState struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Type is one of triage, backlog, unstarted, started, completed, canceled.
+	Type string `json:"type"`
+}
+
+// Team is a Linear team with its workflow states.
+type Team struct {
+	ID     string `json:"id"`
+	Key    string `json:"key"`
+	States struct {
+		Nodes []WorkflowState `json:"nodes"`
+	} `json:"states"`
+}
+
+// Relation is a relation from an issue to another issue.
+type Relation struct {
+	Type         string  `json:"type"` // "blocks", "related", "duplicate"
+	RelatedIssue IssueID `json:"relatedIssue"`
+}
+
+type relationConnection struct {
+	Nodes []Relation `json:"nodes"`
+}
+
+// IssueInput holds the fields for creating or updating an issue.
+// Nil fields are left unchanged on update.
+type IssueInput struct {
+	TeamID      *string `json:"teamId,omitempty"`
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Priority    *int    `json:"priority,omitempty"`
+	StateID     *string `json:"stateId,omitempty"`
+	ParentID    *string `json:"parentId,omitempty"`
+}
+
+// hasChanges returns true if any field is set in the update input.
+func (in *IssueInput) hasChanges() bool {
+	return in.Title != nil || in.Description != nil || in.Priority != nil ||
+		in.StateID != nil || in.ParentID != nil
+}
+
+// graphQLRequest is the body of a GraphQL POST.
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// graphQLError is one entry of a GraphQL response's errors array.
+type graphQLError struct {
+	Message    string `json:"message"`
+	Extensions struct {
+		Code                   string `json:"code"`
+		UserPresentableMessage string `json:"userPresentableMessage"`
+	} `json:"extensions"`
+}
@@ -0,0 +1,122 @@
+// Package lock provides a cross-process advisory file lock so that
+// concurrent beanup invocations don't sync the same project at once.
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileName is the lock file created inside the beans directory.
+const FileName = ".beanup.lock"
+
+// ErrLocked is returned when the lock is held by another process.
+var ErrLocked = errors.New("lock is held by another process")
+
+// pollInterval is how often Wait retries a held lock.
+const pollInterval = 200 * time.Millisecond
+
+// Lock is an exclusive lock on a file. The lock is released automatically
+// by the operating system if the process exits.
+type Lock struct {
+	f    *os.File
+	path string
+}
+
+// TryAcquire takes the lock at path without blocking.
+// Returns an error wrapping ErrLocked if another process holds it.
+func TryAcquire(path string) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating lock directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening lock file: %w", err)
+	}
+
+	if err := lockFile(f); err != nil {
+		_ = f.Close()
+		if errors.Is(err, ErrLocked) {
+			if pid := holderPID(path); pid > 0 {
+				return nil, fmt.Errorf("%w (pid %d, %s)", ErrLocked, pid, path)
+			}
+			return nil, fmt.Errorf("%w (%s)", ErrLocked, path)
+		}
+		return nil, fmt.Errorf("locking %s: %w", path, err)
+	}
+
+	// Record our PID for diagnostics; the lock itself is the flock, not the content
+	if err := f.Truncate(0); err == nil {
+		_, _ = f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+
+	return &Lock{f: f, path: path}, nil
+}
+
+// Acquire takes the lock at path, retrying until it is free, timeout
+// elapses, or ctx is cancelled. A zero timeout tries exactly once.
+func Acquire(ctx context.Context, path string, timeout time.Duration) (*Lock, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		l, err := TryAcquire(path)
+		if err == nil || !errors.Is(err, ErrLocked) || !time.Now().Before(deadline) {
+			return l, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// Release unlocks and closes the lock file. The file itself is left in
+// place, since removing it would race with processes waiting on it.
+func (l *Lock) Release() error {
+	if l == nil || l.f == nil {
+		return nil
+	}
+	_ = l.f.Truncate(0)
+	err := unlockFile(l.f)
+	closeErr := l.f.Close()
+	l.f = nil
+	if err != nil {
+		return fmt.Errorf("unlocking %s: %w", l.path, err)
+	}
+	return closeErr
+}
+
+// Holder reports whether another process holds the lock at path, and the
+// PID it recorded. A missing lock file isn't created.
+func Holder(path string) (pid int, held bool) {
+	if _, err := os.Stat(path); err != nil {
+		return 0, false
+	}
+	l, err := TryAcquire(path)
+	if err == nil {
+		_ = l.Release()
+		return 0, false
+	}
+	if !errors.Is(err, ErrLocked) {
+		return 0, false
+	}
+	return holderPID(path), true
+}
+
+// holderPID reads the PID recorded by the current lock holder, if any.
+func holderPID(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	return pid
+}
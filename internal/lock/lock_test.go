@@ -0,0 +1,113 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTryAcquire_Exclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+
+	l, err := TryAcquire(path)
+	if err != nil {
+		t.Fatalf("TryAcquire() error = %v", err)
+	}
+
+	_, err = TryAcquire(path)
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("second TryAcquire() error = %v, want ErrLocked", err)
+	}
+
+	if err := l.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+
+	l2, err := TryAcquire(path)
+	if err != nil {
+		t.Fatalf("TryAcquire() after release error = %v", err)
+	}
+	_ = l2.Release()
+}
+
+func TestTryAcquire_ReportsHolderPID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+
+	l, err := TryAcquire(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = l.Release() }()
+
+	_, err = TryAcquire(path)
+	if err == nil || !strings.Contains(err.Error(), "pid ") {
+		t.Errorf("expected holder pid in error, got %v", err)
+	}
+	if pid := holderPID(path); pid <= 0 {
+		t.Errorf("holderPID() = %d, want our pid", pid)
+	} else if !strings.Contains(err.Error(), strconv.Itoa(pid)) {
+		t.Errorf("error %q does not mention pid %d", err, pid)
+	}
+}
+
+func TestHolder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if _, held := Holder(path); held {
+		t.Error("missing lock file reported held")
+	}
+
+	l, err := TryAcquire(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pid, held := Holder(path); !held || pid != os.Getpid() {
+		t.Errorf("Holder() = %d, %v, want our pid", pid, held)
+	}
+	_ = l.Release()
+	if _, held := Holder(path); held {
+		t.Error("released lock reported held")
+	}
+}
+
+func TestAcquire_WaitsForRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+
+	held, err := TryAcquire(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		_ = held.Release()
+	}()
+
+	l, err := Acquire(context.Background(), path, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	_ = l.Release()
+}
+
+func TestAcquire_Timeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+
+	held, err := TryAcquire(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = held.Release() }()
+
+	start := time.Now()
+	_, err = Acquire(context.Background(), path, 250*time.Millisecond)
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("Acquire() error = %v, want ErrLocked", err)
+	}
+	if time.Since(start) < 200*time.Millisecond {
+		t.Error("Acquire() returned before the timeout elapsed")
+	}
+}
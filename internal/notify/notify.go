@@ -0,0 +1,265 @@
+// Package notify posts sync summaries to chat webhooks (Slack or Discord).
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Severity classifies the outcome of a sync run.
+type Severity int
+
+const (
+	// SeverityNone means nothing happened worth reporting (no changes, no errors).
+	SeverityNone Severity = iota
+	// SeverityInfo means the sync succeeded and changed at least one task.
+	SeverityInfo
+	// SeverityWarning means some beans failed to sync while others succeeded.
+	SeverityWarning
+	// SeverityError means the sync failed outright or every bean failed.
+	SeverityError
+)
+
+// ParseSeverity parses a severity name (info, warning, error).
+// An empty string defaults to info.
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "info":
+		return SeverityInfo, nil
+	case "warning", "warn":
+		return SeverityWarning, nil
+	case "error":
+		return SeverityError, nil
+	default:
+		return SeverityNone, fmt.Errorf("invalid severity %q (valid: info, warning, error)", s)
+	}
+}
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return "none"
+	}
+}
+
+// Item is a single bean outcome to include in a summary.
+type Item struct {
+	BeanID string
+	Title  string
+	URL    string
+	Error  string
+}
+
+// Summary describes the outcome of a sync run.
+type Summary struct {
+	Created   []Item
+	Updated   []Item
+	Errors    []Item
+	Unchanged int
+	// Failure is set when the run aborted before producing results.
+	Failure error
+}
+
+// Severity returns the severity of the run.
+func (s *Summary) Severity() Severity {
+	switch {
+	case s.Failure != nil:
+		return SeverityError
+	case len(s.Errors) > 0 && len(s.Created)+len(s.Updated)+s.Unchanged == 0:
+		return SeverityError
+	case len(s.Errors) > 0:
+		return SeverityWarning
+	case len(s.Created)+len(s.Updated) > 0:
+		return SeverityInfo
+	default:
+		return SeverityNone
+	}
+}
+
+// maxListedItems caps the number of beans listed per section.
+const maxListedItems = 10
+
+// discordMaxContent is Discord's message content limit.
+const discordMaxContent = 2000
+
+// Notifier posts summaries to a webhook.
+type Notifier struct {
+	webhookURL  string
+	format      string // "slack" or "discord"
+	minSeverity Severity
+	httpClient  *http.Client
+}
+
+// New creates a notifier. format may be "slack", "discord", or empty to
+// detect it from the webhook URL.
+func New(webhookURL, format string, minSeverity Severity) (*Notifier, error) {
+	if webhookURL == "" {
+		return nil, fmt.Errorf("webhook URL is required")
+	}
+	switch format = strings.ToLower(format); format {
+	case "":
+		format = detectFormat(webhookURL)
+	case "slack", "discord":
+	default:
+		return nil, fmt.Errorf("invalid notification format %q (valid: slack, discord)", format)
+	}
+	return &Notifier{
+		webhookURL:  webhookURL,
+		format:      format,
+		minSeverity: minSeverity,
+		httpClient:  &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+// detectFormat guesses the payload format from the webhook host.
+func detectFormat(webhookURL string) string {
+	if strings.Contains(webhookURL, "discord.com/") || strings.Contains(webhookURL, "discordapp.com/") {
+		return "discord"
+	}
+	return "slack"
+}
+
+// Notify posts the summary if its severity meets the configured minimum.
+// Returns false if the summary was below the threshold and nothing was sent.
+func (n *Notifier) Notify(ctx context.Context, title string, s *Summary) (bool, error) {
+	sev := s.Severity()
+	if sev == SeverityNone || sev < n.minSeverity {
+		return false, nil
+	}
+
+	var payload any
+	if n.format == "discord" {
+		content := formatMessage(title, s, discordMarkup)
+		if runes := []rune(content); len(runes) > discordMaxContent {
+			content = string(runes[:discordMaxContent-1]) + "…"
+		}
+		payload = discordMessage{Content: content, AllowedMentions: discordAllowedMentions{Parse: []string{}}}
+	} else {
+		payload = map[string]string{"text": formatMessage(title, s, slackMarkup)}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return false, fmt.Errorf("marshaling notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", n.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("posting notification: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode >= 300 {
+		return false, fmt.Errorf("posting notification: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return true, nil
+}
+
+// discordMessage is a Discord webhook body. AllowedMentions is sent with
+// nothing to parse, so a bean titled "@everyone" doesn't ping the channel.
+type discordMessage struct {
+	Content         string                 `json:"content"`
+	AllowedMentions discordAllowedMentions `json:"allowed_mentions"`
+}
+
+type discordAllowedMentions struct {
+	Parse []string `json:"parse"`
+}
+
+// markup renders text and links in the target chat's message format.
+type markup struct {
+	// escape makes plain text, such as a bean title, safe to include
+	escape func(text string) string
+	// link renders escaped text as a link to url
+	link func(text, url string) string
+}
+
+var (
+	slackMarkup   = markup{escape: slackEscaper.Replace, link: slackLink}
+	discordMarkup = markup{escape: func(text string) string { return text }, link: discordLink}
+)
+
+// slackEscaper escapes the characters Slack reserves for links and
+// mentions, so a title like "<!channel>" shows as written.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func slackLink(text, url string) string {
+	if url == "" {
+		return text
+	}
+	return fmt.Sprintf("<%s|%s>", url, text)
+}
+
+func discordLink(text, url string) string {
+	if url == "" {
+		return text
+	}
+	return fmt.Sprintf("[%s](%s)", text, url)
+}
+
+// formatMessage renders a plain-text summary in m's format.
+func formatMessage(title string, s *Summary, m markup) string {
+	var b strings.Builder
+
+	icon := ":white_check_mark:"
+	switch s.Severity() {
+	case SeverityWarning:
+		icon = ":warning:"
+	case SeverityError:
+		icon = ":x:"
+	}
+
+	fmt.Fprintf(&b, "%s *%s*: ", icon, m.escape(title))
+	if s.Failure != nil {
+		fmt.Fprintf(&b, "sync failed: %s", m.escape(s.Failure.Error()))
+		return b.String()
+	}
+	fmt.Fprintf(&b, "%d created, %d updated, %d unchanged, %d errors",
+		len(s.Created), len(s.Updated), s.Unchanged, len(s.Errors))
+
+	writeSection := func(heading string, items []Item) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n%s:", heading)
+		for i, it := range items {
+			if i == maxListedItems {
+				fmt.Fprintf(&b, "\n• …and %d more", len(items)-maxListedItems)
+				break
+			}
+			label := it.BeanID
+			if it.Title != "" {
+				label += " " + it.Title
+			}
+			fmt.Fprintf(&b, "\n• %s", m.link(m.escape(label), it.URL))
+			if it.Error != "" {
+				fmt.Fprintf(&b, ": %s", m.escape(it.Error))
+			}
+		}
+	}
+
+	writeSection("Created", s.Created)
+	writeSection("Updated", s.Updated)
+	writeSection("Errors", s.Errors)
+
+	return b.String()
+}
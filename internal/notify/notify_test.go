@@ -0,0 +1,137 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// webhookPayload decodes both Slack and Discord webhook bodies.
+type webhookPayload struct {
+	Text            string `json:"text"`
+	Content         string `json:"content"`
+	AllowedMentions *struct {
+		Parse []string `json:"parse"`
+	} `json:"allowed_mentions"`
+}
+
+func TestSummarySeverity(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary Summary
+		want    Severity
+	}{
+		{"nothing happened", Summary{Unchanged: 3}, SeverityNone},
+		{"changes", Summary{Created: []Item{{BeanID: "a"}}}, SeverityInfo},
+		{"partial failure", Summary{Updated: []Item{{BeanID: "a"}}, Errors: []Item{{BeanID: "b"}}}, SeverityWarning},
+		{"all failed", Summary{Errors: []Item{{BeanID: "b"}}}, SeverityError},
+		{"aborted", Summary{Failure: errors.New("boom")}, SeverityError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.summary.Severity(); got != tt.want {
+				t.Errorf("Severity() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotify_FormatsAndThreshold(t *testing.T) {
+	var payload webhookPayload
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(204)
+	}))
+	defer server.Close()
+
+	summary := &Summary{
+		Created: []Item{{BeanID: "bup-1", Title: "New thing", URL: "https://app.clickup.com/t/1"}},
+	}
+
+	// Slack format (default for non-Discord URLs)
+	n, err := New(server.URL, "", SeverityInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent, err := n.Notify(context.Background(), "beanup sync", summary)
+	if err != nil || !sent {
+		t.Fatalf("Notify() = %v, %v; want sent", sent, err)
+	}
+	if !strings.Contains(payload.Text, "<https://app.clickup.com/t/1|bup-1 New thing>") {
+		t.Errorf("unexpected slack text: %q", payload.Text)
+	}
+
+	// Discord format
+	n, _ = New(server.URL, "discord", SeverityInfo)
+	if _, err := n.Notify(context.Background(), "beanup sync", summary); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(payload.Content, "[bup-1 New thing](https://app.clickup.com/t/1)") {
+		t.Errorf("unexpected discord content: %q", payload.Content)
+	}
+
+	// Below threshold: nothing sent
+	calls = 0
+	n, _ = New(server.URL, "slack", SeverityWarning)
+	sent, err = n.Notify(context.Background(), "beanup sync", summary)
+	if err != nil || sent || calls != 0 {
+		t.Errorf("expected no notification below threshold, sent=%v calls=%d err=%v", sent, calls, err)
+	}
+}
+
+func TestNotify_EscapesAndTruncates(t *testing.T) {
+	var payload webhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(204)
+	}))
+	defer server.Close()
+
+	// A title Slack would otherwise read as a mention and a broken link
+	summary := &Summary{Created: []Item{{BeanID: "bup-1", Title: "<!channel> R&D > ops", URL: "https://app.clickup.com/t/1"}}}
+	n, _ := New(server.URL, "slack", SeverityInfo)
+	if _, err := n.Notify(context.Background(), "beanup sync", summary); err != nil {
+		t.Fatal(err)
+	}
+	if want := "<https://app.clickup.com/t/1|bup-1 &lt;!channel&gt; R&amp;D &gt; ops>"; !strings.Contains(payload.Text, want) {
+		t.Errorf("slack text = %q, want %q", payload.Text, want)
+	}
+
+	// Discord gets no escaping, but must not turn names into pings
+	summary = &Summary{Created: []Item{{BeanID: "bup-1", Title: "@everyone look"}}}
+	n, _ = New(server.URL, "discord", SeverityInfo)
+	payload = webhookPayload{}
+	if _, err := n.Notify(context.Background(), "beanup sync", summary); err != nil {
+		t.Fatal(err)
+	}
+	if m := payload.AllowedMentions; m == nil || m.Parse == nil || len(m.Parse) != 0 {
+		t.Errorf("allowed_mentions = %+v, want an empty parse list", m)
+	}
+
+	// Discord's limit counts characters, and none may be cut in half
+	summary = &Summary{Created: []Item{{BeanID: "bup-1", Title: strings.Repeat("é", 3000)}}}
+	n, _ = New(server.URL, "discord", SeverityInfo)
+	if _, err := n.Notify(context.Background(), "beanup sync", summary); err != nil {
+		t.Fatal(err)
+	}
+	content := payload.Content
+	if n := utf8.RuneCountInString(content); n != discordMaxContent || !utf8.ValidString(content) || !strings.HasSuffix(content, "é…") {
+		t.Errorf("content has %d runes (valid UTF-8: %v), want %d ending in é…", n, utf8.ValidString(content), discordMaxContent)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	if got := detectFormat("https://discord.com/api/webhooks/1/abc"); got != "discord" {
+		t.Errorf("discord URL detected as %q", got)
+	}
+	if got := detectFormat("https://hooks.slack.com/services/T/B/X"); got != "slack" {
+		t.Errorf("slack URL detected as %q", got)
+	}
+}
@@ -0,0 +1,85 @@
+package notion
+
+import (
+	"regexp"
+	"strings"
+)
+
+// numberedItem matches an ordered list item such as "1. " or "12) ".
+var numberedItem = regexp.MustCompile(`^\d+[.)] `)
+
+// toBlocks converts a markdown bean body into page content blocks. Headings,
+// list items, quotes, and fenced code become their block types; other lines
+// are grouped into paragraphs. Inline formatting is kept as plain text.
+func toBlocks(body string) []Block {
+	var blocks []Block
+	var para []string
+	var code []string
+	inCode := false
+
+	flush := func() {
+		if len(para) > 0 {
+			blocks = append(blocks, Block{Type: "paragraph", Text: strings.Join(para, "\n")})
+			para = nil
+		}
+	}
+
+	for line := range strings.SplitSeq(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if inCode {
+			if strings.HasPrefix(trimmed, "```") {
+				blocks = append(blocks, Block{Type: "code", Text: strings.Join(code, "\n")})
+				code, inCode = nil, false
+			} else {
+				code = append(code, line)
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			inCode = true
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "### "):
+			flush()
+			blocks = append(blocks, Block{Type: "heading_3", Text: trimmed[4:]})
+		case strings.HasPrefix(trimmed, "## "):
+			flush()
+			blocks = append(blocks, Block{Type: "heading_2", Text: trimmed[3:]})
+		case strings.HasPrefix(trimmed, "# "):
+			flush()
+			blocks = append(blocks, Block{Type: "heading_1", Text: trimmed[2:]})
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			flush()
+			blocks = append(blocks, Block{Type: "bulleted_list_item", Text: trimmed[2:]})
+		case numberedItem.MatchString(trimmed):
+			flush()
+			blocks = append(blocks, Block{Type: "numbered_list_item", Text: numberedItem.ReplaceAllString(trimmed, "")})
+		case strings.HasPrefix(trimmed, "> "):
+			flush()
+			blocks = append(blocks, Block{Type: "quote", Text: trimmed[2:]})
+		default:
+			para = append(para, trimmed)
+		}
+	}
+	if inCode {
+		blocks = append(blocks, Block{Type: "code", Text: strings.Join(code, "\n")})
+	}
+	flush()
+	return blocks
+}
+
+// sameBlocks reports whether two block lists have the same types and text.
+func sameBlocks(a, b []Block) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Type != b[i].Type || a[i].Text != b[i].Text {
+			return false
+		}
+	}
+	return true
+}
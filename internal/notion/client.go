@@ -0,0 +1,154 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/toba/bean-me-up/internal/restapi"
+)
+
+// DefaultBaseURL is the Notion API endpoint.
+const DefaultBaseURL = "https://api.notion.com/v1"
+
+// apiVersion is the Notion-Version header sent with every request.
+const apiVersion = "2022-06-28"
+
+// maxBlocksPerRequest is the most children Notion accepts in one append.
+const maxBlocksPerRequest = 100
+
+// HTTPDoer sends HTTP requests. *http.Client satisfies it.
+type HTTPDoer = restapi.HTTPDoer
+
+// Client provides Notion API access.
+type Client struct {
+	api *restapi.Client
+}
+
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the transport used for API requests.
+func WithHTTPClient(doer HTTPDoer) ClientOption {
+	return func(c *Client) { c.api.HTTP = doer }
+}
+
+// WithBaseURL points the client at a different API root, such as a mock server.
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) { c.api.BaseURL = strings.TrimRight(url, "/") }
+}
+
+// NewClient creates a client authenticated with an internal integration token.
+func NewClient(token string, opts ...ClientOption) *Client {
+	api := restapi.New("notion", DefaultBaseURL)
+	api.Authorize = func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Notion-Version", apiVersion)
+	}
+	api.ErrorMessage = func(body []byte) string {
+		var errResp errorResponse
+		_ = json.Unmarshal(body, &errResp)
+		return errResp.Message
+	}
+
+	c := &Client{api: api}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// GetDatabase fetches a database's property schema.
+func (c *Client) GetDatabase(ctx context.Context, id string) (*Database, error) {
+	var db Database
+	if err := c.api.Do(ctx, "GET", "/databases/"+url.PathEscape(id), nil, &db); err != nil {
+		return nil, fmt.Errorf("getting database %s: %w", id, err)
+	}
+	return &db, nil
+}
+
+// GetPage fetches a page and its properties.
+func (c *Client) GetPage(ctx context.Context, id string) (*Page, error) {
+	var page Page
+	if err := c.api.Do(ctx, "GET", "/pages/"+url.PathEscape(id), nil, &page); err != nil {
+		return nil, fmt.Errorf("getting page %s: %w", id, err)
+	}
+	return &page, nil
+}
+
+// CreatePage creates a page in a database with the given property values
+// (in the API's write format) and content.
+func (c *Client) CreatePage(ctx context.Context, databaseID string, properties map[string]any, children []Block) (*Page, error) {
+	first, rest := children, []Block(nil)
+	if len(children) > maxBlocksPerRequest {
+		first, rest = children[:maxBlocksPerRequest], children[maxBlocksPerRequest:]
+	}
+	req := map[string]any{
+		"parent":     map[string]string{"database_id": databaseID},
+		"properties": properties,
+		"children":   first,
+	}
+	var page Page
+	if err := c.api.Do(ctx, "POST", "/pages", req, &page); err != nil {
+		return nil, fmt.Errorf("creating page: %w", err)
+	}
+	if err := c.AppendBlocks(ctx, page.ID, rest); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// UpdatePage sets property values (in the API's write format) on a page.
+func (c *Client) UpdatePage(ctx context.Context, id string, properties map[string]any) (*Page, error) {
+	var page Page
+	req := map[string]any{"properties": properties}
+	if err := c.api.Do(ctx, "PATCH", "/pages/"+url.PathEscape(id), req, &page); err != nil {
+		return nil, fmt.Errorf("updating page %s: %w", id, err)
+	}
+	return &page, nil
+}
+
+// ListBlocks returns the top-level content blocks of a page.
+func (c *Client) ListBlocks(ctx context.Context, pageID string) ([]Block, error) {
+	var all []Block
+	cursor := ""
+	for {
+		path := "/blocks/" + url.PathEscape(pageID) + "/children?page_size=100"
+		if cursor != "" {
+			path += "&start_cursor=" + url.QueryEscape(cursor)
+		}
+		var resp listResponse[Block]
+		if err := c.api.Do(ctx, "GET", path, nil, &resp); err != nil {
+			return nil, fmt.Errorf("listing blocks of %s: %w", pageID, err)
+		}
+		all = append(all, resp.Results...)
+		if !resp.HasMore || resp.NextCursor == "" {
+			return all, nil
+		}
+		cursor = resp.NextCursor
+	}
+}
+
+// AppendBlocks adds blocks to the end of a page, in batches of 100.
+func (c *Client) AppendBlocks(ctx context.Context, pageID string, blocks []Block) error {
+	for len(blocks) > 0 {
+		n := min(len(blocks), maxBlocksPerRequest)
+		req := map[string]any{"children": blocks[:n]}
+		if err := c.api.Do(ctx, "PATCH", "/blocks/"+url.PathEscape(pageID)+"/children", req, nil); err != nil {
+			return fmt.Errorf("appending blocks to %s: %w", pageID, err)
+		}
+		blocks = blocks[n:]
+	}
+	return nil
+}
+
+// DeleteBlock moves a block to the trash.
+func (c *Client) DeleteBlock(ctx context.Context, id string) error {
+	if err := c.api.Do(ctx, "DELETE", "/blocks/"+url.PathEscape(id), nil, nil); err != nil {
+		return fmt.Errorf("deleting block %s: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,376 @@
+package notion
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/toba/bean-me-up/internal/auth"
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+	"github.com/toba/bean-me-up/internal/restapi"
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+// SinkName is the registry key and bean extension name for Notion.
+const SinkName = "notion"
+
+// Default property names, used when the database has them.
+var defaultProperties = config.NotionProperties{
+	Status:    "Status",
+	Priority:  "Priority",
+	Type:      "Type",
+	Tags:      "Tags",
+	BeanID:    "Bean ID",
+	Parent:    "Parent",
+	BlockedBy: "Blocked by",
+}
+
+func init() {
+	syncer.Register(SinkName, newSinkFromConfig)
+}
+
+// newSinkFromConfig builds the Notion sink using NOTION_TOKEN.
+func newSinkFromConfig(cfg *config.Config) (syncer.Sink, error) {
+	nc := cfg.Beans.Notion
+	if nc == nil || nc.DatabaseID == "" {
+		return nil, fmt.Errorf("Notion database_id is required in .beans.yml extensions.notion")
+	}
+	token, err := auth.Token(context.Background(), "NOTION_TOKEN", SinkName, nc.TokenSource)
+	if err != nil {
+		return nil, err
+	}
+	return NewSink(NewClient(token), nc), nil
+}
+
+// Sink syncs beans to pages in a Notion database.
+//
+// Title, status, priority, type, tags, and bean ID are written to database
+// properties, the body to page content blocks, and parents and blockers to
+// relation properties when the database has them.
+type Sink struct {
+	client *Client
+	config *config.NotionConfig
+
+	// The database, read before a missing page counts as deleted
+	database restapi.Container
+
+	// Resolved property names and their types, loaded by Prepare.
+	// A property missing from the database has an empty name.
+	props config.NotionProperties
+	types map[string]string
+
+	// Serializes read-modify-write edits of relation properties
+	relMu sync.Mutex
+}
+
+// NewSink creates a sink that writes pages with client using the mappings in cfg.
+func NewSink(client *Client, cfg *config.NotionConfig) *Sink {
+	s := &Sink{client: client, config: cfg}
+	s.database.Check = func(ctx context.Context) error {
+		_, err := client.GetDatabase(ctx, cfg.DatabaseID)
+		return err
+	}
+	return s
+}
+
+// Name returns SinkName.
+func (s *Sink) Name() string { return SinkName }
+
+// Prepare reads the database schema and resolves which properties to write.
+// Explicitly configured properties must exist; defaults are skipped if absent.
+func (s *Sink) Prepare(ctx context.Context) error {
+	db, err := s.client.GetDatabase(ctx, s.config.DatabaseID)
+	if err != nil {
+		return err
+	}
+	s.types = make(map[string]string, len(db.Properties))
+	for name, p := range db.Properties {
+		s.types[name] = p.Type
+		if p.Type == "title" && s.config.Properties.Title == "" {
+			s.props.Title = name
+		}
+	}
+
+	configured := s.config.Properties
+	for _, f := range []struct {
+		dst        *string
+		configured string
+		fallback   string
+		types      []string
+	}{
+		{&s.props.Title, configured.Title, s.props.Title, []string{"title"}},
+		{&s.props.Status, configured.Status, defaultProperties.Status, []string{"status", "select"}},
+		{&s.props.Priority, configured.Priority, defaultProperties.Priority, []string{"select"}},
+		{&s.props.Type, configured.Type, defaultProperties.Type, []string{"select"}},
+		{&s.props.Tags, configured.Tags, defaultProperties.Tags, []string{"multi_select"}},
+		{&s.props.BeanID, configured.BeanID, defaultProperties.BeanID, []string{"rich_text"}},
+		{&s.props.Parent, configured.Parent, defaultProperties.Parent, []string{"relation"}},
+		{&s.props.BlockedBy, configured.BlockedBy, defaultProperties.BlockedBy, []string{"relation"}},
+	} {
+		name := f.configured
+		if name == "" {
+			name = f.fallback
+		}
+		typ, ok := s.types[name]
+		switch {
+		case ok && slices.Contains(f.types, typ):
+			*f.dst = name
+		case f.configured != "":
+			return fmt.Errorf("database property %q must exist and be of type %s", f.configured, strings.Join(f.types, " or "))
+		default:
+			*f.dst = ""
+		}
+	}
+	if s.props.Title == "" {
+		return fmt.Errorf("database %s has no title property", s.config.DatabaseID)
+	}
+	return nil
+}
+
+// GetTask fetches the page, treating archived pages as deleted. The *Page is
+// kept in the ref for UpdateTask.
+func (s *Sink) GetTask(ctx context.Context, taskID string) (*syncer.TaskRef, error) {
+	page, err := s.client.GetPage(ctx, taskID)
+	if err != nil {
+		return nil, s.database.Missing(ctx, err)
+	}
+	if page.Archived || page.InTrash {
+		return nil, fmt.Errorf("page %s is archived: %w", taskID, syncer.ErrTaskNotFound)
+	}
+	return s.pageRef(page), nil
+}
+
+// CreateTask creates a database page with the bean's properties and body.
+func (s *Sink) CreateTask(ctx context.Context, b *beans.Bean, parentTaskID string) (*syncer.TaskRef, error) {
+	props := s.properties(b)
+	if s.props.Parent != "" && parentTaskID != "" {
+		props[s.props.Parent] = relation([]PageRef{{ID: parentTaskID}})
+	}
+	page, err := s.client.CreatePage(ctx, s.config.DatabaseID, props, toBlocks(b.Body))
+	if err != nil {
+		return nil, err
+	}
+	return s.pageRef(page), nil
+}
+
+// UpdateTask writes changed properties and replaces the page content when
+// the body changed.
+func (s *Sink) UpdateTask(ctx context.Context, current *syncer.TaskRef, b *beans.Bean) (*syncer.TaskRef, bool, error) {
+	page, ok := current.Remote.(*Page)
+	if !ok {
+		var err error
+		if page, err = s.client.GetPage(ctx, current.ID); err != nil {
+			return nil, false, err
+		}
+	}
+
+	changed := false
+	if props := s.changedProperties(page, b); len(props) > 0 {
+		updated, err := s.client.UpdatePage(ctx, page.ID, props)
+		if err != nil {
+			return nil, false, err
+		}
+		page, changed = updated, true
+	}
+
+	existing, err := s.client.ListBlocks(ctx, page.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	if want := toBlocks(b.Body); !sameBlocks(existing, want) {
+		for _, block := range existing {
+			if err := s.client.DeleteBlock(ctx, block.ID); err != nil {
+				return nil, false, err
+			}
+		}
+		if err := s.client.AppendBlocks(ctx, page.ID, want); err != nil {
+			return nil, false, err
+		}
+		changed = true
+	}
+
+	if !changed {
+		return current, false, nil
+	}
+	return s.pageRef(page), true, nil
+}
+
+// SyncTags is a no-op: bean tags are written to the tags property with every create and update.
+func (s *Sink) SyncTags(ctx context.Context, task *syncer.TaskRef, b *beans.Bean) bool {
+	return false
+}
+
+// SetParent sets the child page's parent relation, if the database has one.
+func (s *Sink) SetParent(ctx context.Context, parentTaskID, childTaskID string) error {
+	if s.props.Parent == "" {
+		return nil
+	}
+	child, err := s.client.GetPage(ctx, childTaskID)
+	if err != nil {
+		return err
+	}
+	current := child.Properties[s.props.Parent].Relation
+	if len(current) == 1 && sameID(current[0].ID, parentTaskID) {
+		return nil
+	}
+	_, err = s.client.UpdatePage(ctx, childTaskID, map[string]any{
+		s.props.Parent: relation([]PageRef{{ID: parentTaskID}}),
+	})
+	return err
+}
+
+// SetRelationship adds the blocker to the blocked page's "blocked by"
+// relation, if the database has one.
+func (s *Sink) SetRelationship(ctx context.Context, blockerTaskID, blockedTaskID string) error {
+	if s.props.BlockedBy == "" {
+		return nil
+	}
+
+	s.relMu.Lock()
+	defer s.relMu.Unlock()
+
+	blocked, err := s.client.GetPage(ctx, blockedTaskID)
+	if err != nil {
+		return err
+	}
+	refs := blocked.Properties[s.props.BlockedBy].Relation
+	if slices.ContainsFunc(refs, func(r PageRef) bool { return sameID(r.ID, blockerTaskID) }) {
+		return nil
+	}
+	_, err = s.client.UpdatePage(ctx, blockedTaskID, map[string]any{
+		s.props.BlockedBy: relation(append(refs, PageRef{ID: blockerTaskID})),
+	})
+	return err
+}
+
+// properties returns the write-format values of every mapped property.
+func (s *Sink) properties(b *beans.Bean) map[string]any {
+	props := map[string]any{
+		s.props.Title: map[string]any{"title": richText(b.Title)},
+	}
+	if s.props.Status != "" {
+		props[s.props.Status] = s.option(s.props.Status, s.status(b))
+	}
+	if s.props.Priority != "" {
+		props[s.props.Priority] = s.option(s.props.Priority, s.priority(b))
+	}
+	if s.props.Type != "" {
+		props[s.props.Type] = s.option(s.props.Type, b.Type)
+	}
+	if s.props.Tags != "" {
+		opts := make([]Option, 0, len(b.Tags))
+		for _, t := range sortedTags(b.Tags) {
+			opts = append(opts, Option{Name: t})
+		}
+		props[s.props.Tags] = map[string]any{"multi_select": opts}
+	}
+	if s.props.BeanID != "" {
+		props[s.props.BeanID] = map[string]any{"rich_text": richText(b.ID)}
+	}
+	return props
+}
+
+// changedProperties returns the mapped properties whose values differ from the page.
+func (s *Sink) changedProperties(page *Page, b *beans.Bean) map[string]any {
+	want := s.properties(b)
+	current := page.Properties
+	changed := make(map[string]any)
+
+	if plainText(current[s.props.Title].Title) != b.Title {
+		changed[s.props.Title] = want[s.props.Title]
+	}
+	for name, value := range map[string]string{
+		s.props.Status:   s.status(b),
+		s.props.Priority: s.priority(b),
+		s.props.Type:     b.Type,
+	} {
+		if name != "" && optionName(current[name]) != value {
+			changed[name] = want[name]
+		}
+	}
+	if s.props.Tags != "" {
+		var names []string
+		for _, o := range current[s.props.Tags].MultiSelect {
+			names = append(names, o.Name)
+		}
+		if !slices.Equal(sortedTags(names), sortedTags(b.Tags)) {
+			changed[s.props.Tags] = want[s.props.Tags]
+		}
+	}
+	if s.props.BeanID != "" && plainText(current[s.props.BeanID].RichText) != b.ID {
+		changed[s.props.BeanID] = want[s.props.BeanID]
+	}
+	return changed
+}
+
+// option returns a status or select value for the named property; an empty
+// name clears it.
+func (s *Sink) option(property, name string) map[string]any {
+	typ := s.types[property]
+	if name == "" {
+		return map[string]any{typ: nil}
+	}
+	return map[string]any{typ: Option{Name: name}}
+}
+
+// status returns the mapped status option for the bean.
+func (s *Sink) status(b *beans.Bean) string {
+	if name, ok := s.config.StatusMapping[b.Status]; ok {
+		return name
+	}
+	return config.DefaultNotionStatusMapping[b.Status]
+}
+
+// priority returns the mapped priority option for the bean.
+func (s *Sink) priority(b *beans.Bean) string {
+	if name, ok := s.config.PriorityMapping[b.Priority]; ok {
+		return name
+	}
+	return b.Priority
+}
+
+// pageRef converts a page to a sink-neutral reference.
+func (s *Sink) pageRef(page *Page) *syncer.TaskRef {
+	ref := &syncer.TaskRef{ID: page.ID, URL: page.URL, Remote: page}
+	if s.props.Tags != "" {
+		for _, o := range page.Properties[s.props.Tags].MultiSelect {
+			ref.Tags = append(ref.Tags, o.Name)
+		}
+	}
+	return ref
+}
+
+// relation returns a relation property value.
+func relation(refs []PageRef) map[string]any {
+	return map[string]any{"relation": refs}
+}
+
+// optionName returns the selected option of a status or select value.
+func optionName(v PropertyValue) string {
+	switch {
+	case v.Status != nil:
+		return v.Status.Name
+	case v.Select != nil:
+		return v.Select.Name
+	}
+	return ""
+}
+
+// sortedTags returns a sorted, de-duplicated copy. Notion option names
+// can't contain commas, so they are replaced with spaces.
+func sortedTags(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		out = append(out, strings.ReplaceAll(t, ",", " "))
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// sameID compares Notion IDs with or without dashes.
+func sameID(a, b string) bool {
+	return strings.ReplaceAll(a, "-", "") == strings.ReplaceAll(b, "-", "")
+}
@@ -0,0 +1,243 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+	"github.com/toba/bean-me-up/internal/syncer"
+	"github.com/toba/bean-me-up/internal/syncertest"
+)
+
+// fakeSchema is the database schema served by fakeNotion. It has no Type property.
+var fakeSchema = map[string]PropertySchema{
+	"Name":       {Type: "title"},
+	"Status":     {Type: "status"},
+	"Priority":   {Type: "select"},
+	"Tags":       {Type: "multi_select"},
+	"Bean ID":    {Type: "rich_text"},
+	"Parent":     {Type: "relation"},
+	"Blocked by": {Type: "relation"},
+}
+
+// fakeNotion is an in-memory Notion API with one database, "db".
+type fakeNotion struct {
+	mu     sync.Mutex
+	pages  map[string]*Page
+	blocks map[string][]Block
+	nextID int
+}
+
+func newFakeNotion(t *testing.T) (*fakeNotion, *Client) {
+	t.Helper()
+	f := &fakeNotion{pages: map[string]*Page{}, blocks: map[string][]Block{}}
+	url := syncertest.Serve(t, f)
+	return f, NewClient("secret", WithBaseURL(url))
+}
+
+func (f *fakeNotion) id() string {
+	f.nextID++
+	return "0000-" + strconv.Itoa(f.nextID)
+}
+
+func (f *fakeNotion) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Notion-Version") == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Properties map[string]PropertyValue `json:"properties"`
+		Children   []Block                  `json:"children"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	notFound := func() {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"object":"error","code":"object_not_found","message":"Could not find page"}`))
+	}
+
+	path := r.URL.Path
+	switch {
+	case path == "/databases/db":
+		_ = json.NewEncoder(w).Encode(Database{ID: "db", Properties: fakeSchema})
+
+	case path == "/pages" && r.Method == "POST":
+		page := &Page{ID: f.id(), Properties: map[string]PropertyValue{}}
+		page.URL = "https://www.notion.so/" + page.ID
+		f.pages[page.ID] = page
+		f.setProperties(page, req.Properties)
+		f.appendBlocks(page.ID, req.Children)
+		_ = json.NewEncoder(w).Encode(page)
+
+	case strings.HasPrefix(path, "/pages/"):
+		page, ok := f.pages[strings.TrimPrefix(path, "/pages/")]
+		if !ok {
+			notFound()
+			return
+		}
+		if r.Method == "PATCH" {
+			f.setProperties(page, req.Properties)
+		}
+		_ = json.NewEncoder(w).Encode(page)
+
+	case strings.HasSuffix(path, "/children"):
+		pageID := strings.TrimSuffix(strings.TrimPrefix(path, "/blocks/"), "/children")
+		if r.Method == "PATCH" {
+			f.appendBlocks(pageID, req.Children)
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		var results []map[string]any
+		for _, b := range f.blocks[pageID] {
+			results = append(results, map[string]any{
+				"id": b.ID, "type": b.Type,
+				b.Type: map[string]any{"rich_text": []RichText{{PlainText: b.Text}}},
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"results": results, "has_more": false})
+
+	case strings.HasPrefix(path, "/blocks/") && r.Method == "DELETE":
+		id := strings.TrimPrefix(path, "/blocks/")
+		for pageID, blocks := range f.blocks {
+			for i, b := range blocks {
+				if b.ID == id {
+					f.blocks[pageID] = append(blocks[:i:i], blocks[i+1:]...)
+				}
+			}
+		}
+		_, _ = w.Write([]byte(`{}`))
+
+	default:
+		notFound()
+	}
+}
+
+func (f *fakeNotion) setProperties(page *Page, props map[string]PropertyValue) {
+	for name, v := range props {
+		v.Type = fakeSchema[name].Type
+		page.Properties[name] = v
+	}
+}
+
+func (f *fakeNotion) appendBlocks(pageID string, blocks []Block) {
+	for _, b := range blocks {
+		b.ID = f.id()
+		f.blocks[pageID] = append(f.blocks[pageID], b)
+	}
+}
+
+func TestSink(t *testing.T) {
+	syncertest.RunSinkTests(t, func(t *testing.T) syncertest.SinkFixture {
+		notion, client := newFakeNotion(t)
+		notion.pages["old"] = &Page{ID: "old", Archived: true, Properties: map[string]PropertyValue{}}
+		return syncertest.SinkFixture{Sink: NewSink(client, &config.NotionConfig{DatabaseID: "db"}), DeletedTaskID: "old"}
+	})
+}
+
+func TestSink_MapsBeanFields(t *testing.T) {
+	notion, client := newFakeNotion(t)
+	sink := NewSink(client, &config.NotionConfig{DatabaseID: "db"})
+	state := syncertest.NewState()
+
+	beanList := []beans.Bean{
+		{ID: "epic", Title: "Epic", Type: "epic", Status: "in-progress"},
+		{ID: "fix", Title: "Fix crash", Body: "# Repro\nOpen it\nthen close\n\n- step", Priority: "high", Status: "todo", Parent: "epic", Tags: []string{"ui", "api"}, Blocking: []string{"done"}},
+		{ID: "done", Title: "Shipped", Status: "completed"},
+	}
+
+	results := syncertest.Sync(t, sink, syncer.Options{}, state, beanList)
+	syncertest.RequireActions(t, results, "created")
+
+	epicID, fixID, doneID := *state.GetTaskID("epic"), *state.GetTaskID("fix"), *state.GetTaskID("done")
+	fix := notion.pages[fixID].Properties
+
+	if got := plainText(fix["Name"].Title); got != "Fix crash" {
+		t.Errorf("title = %q", got)
+	}
+	if fix["Status"].Status == nil || fix["Status"].Status.Name != "Not started" {
+		t.Errorf("status = %+v", fix["Status"].Status)
+	}
+	if fix["Priority"].Select == nil || fix["Priority"].Select.Name != "high" {
+		t.Errorf("priority = %+v", fix["Priority"].Select)
+	}
+	if len(fix["Tags"].MultiSelect) != 2 || fix["Tags"].MultiSelect[0].Name != "api" {
+		t.Errorf("tags = %+v", fix["Tags"].MultiSelect)
+	}
+	if rel := fix["Parent"].Relation; len(rel) != 1 || rel[0].ID != epicID {
+		t.Errorf("parent = %+v, want %s", rel, epicID)
+	}
+	if rel := notion.pages[doneID].Properties["Blocked by"].Relation; len(rel) != 1 || rel[0].ID != fixID {
+		t.Errorf("blocked by = %+v, want %s", rel, fixID)
+	}
+
+	blocks := notion.blocks[fixID]
+	if len(blocks) != 3 || blocks[0].Type != "heading_1" || blocks[1].Text != "Open it\nthen close" || blocks[2].Type != "bulleted_list_item" {
+		t.Errorf("blocks = %+v", blocks)
+	}
+
+	// A second sync finds nothing to change
+	syncertest.RequireActions(t, syncertest.Sync(t, sink, syncer.Options{Force: true}, state, beanList), "unchanged")
+}
+
+func TestSink_UpdateReplacesContent(t *testing.T) {
+	notion, client := newFakeNotion(t)
+	sink := NewSink(client, &config.NotionConfig{DatabaseID: "db"})
+	state := syncertest.NewState()
+
+	b := beans.Bean{ID: "b", Title: "B", Body: "Old", Status: "todo"}
+	if _, err := syncer.New(sink, syncer.Options{}, state).SyncBeans(context.Background(), []beans.Bean{b}); err != nil {
+		t.Fatal(err)
+	}
+
+	b.Body = "New\n\n```\ncode\n```"
+	b.Status = "completed"
+	results, _ := syncer.New(sink, syncer.Options{Force: true}, state).SyncBeans(context.Background(), []beans.Bean{b})
+	if results[0].Action != "updated" {
+		t.Fatalf("action %q (err %v)", results[0].Action, results[0].Error)
+	}
+
+	pageID := *state.GetTaskID("b")
+	blocks := notion.blocks[pageID]
+	if len(blocks) != 2 || blocks[0].Text != "New" || blocks[1].Type != "code" || blocks[1].Text != "code" {
+		t.Errorf("blocks = %+v", blocks)
+	}
+	if s := notion.pages[pageID].Properties["Status"].Status; s == nil || s.Name != "Done" {
+		t.Errorf("status = %+v", s)
+	}
+}
+
+func TestSink_ConfiguredPropertyMustExist(t *testing.T) {
+	_, client := newFakeNotion(t)
+	sink := NewSink(client, &config.NotionConfig{
+		DatabaseID: "db",
+		Properties: config.NotionProperties{Type: "Kind"},
+	})
+	if err := sink.Prepare(context.Background()); err == nil || !strings.Contains(err.Error(), `"Kind"`) {
+		t.Errorf("Prepare() error = %v, want missing property error", err)
+	}
+}
+
+func TestToBlocks(t *testing.T) {
+	got := toBlocks("Intro line\nwraps\n\n## Steps\n1. one\n2) two\n> note\n```go\nx := 1\n\ny := 2\n```\ntail")
+	want := []Block{
+		{Type: "paragraph", Text: "Intro line\nwraps"},
+		{Type: "heading_2", Text: "Steps"},
+		{Type: "numbered_list_item", Text: "one"},
+		{Type: "numbered_list_item", Text: "two"},
+		{Type: "quote", Text: "note"},
+		{Type: "code", Text: "x := 1\n\ny := 2"},
+		{Type: "paragraph", Text: "tail"},
+	}
+	if !sameBlocks(got, want) {
+		t.Errorf("toBlocks() =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -0,0 +1,134 @@
+// Package notion provides Notion database integration.
+package notion
+
+import "encoding/json"
+
+// Page holds page data returned from Notion.
+type Page struct {
+	ID         string                   `json:"id"`
+	URL        string                   `json:"url"`
+	Archived   bool                     `json:"archived"`
+	InTrash    bool                     `json:"in_trash"`
+	Properties map[string]PropertyValue `json:"properties"`
+}
+
+// PropertyValue is a page property as returned by the API. Only the field
+// matching Type is set.
+type PropertyValue struct {
+	Type        string     `json:"type"`
+	Title       []RichText `json:"title"`
+	RichText    []RichText `json:"rich_text"`
+	Select      *Option    `json:"select"`
+	Status      *Option    `json:"status"`
+	MultiSelect []Option   `json:"multi_select"`
+	Relation    []PageRef  `json:"relation"`
+}
+
+// RichText is a run of text. PlainText is set on reads, Text on writes.
+type RichText struct {
+	PlainText string    `json:"plain_text,omitempty"`
+	Text      *TextBody `json:"text,omitempty"`
+}
+
+// TextBody is the content of a text rich text object.
+type TextBody struct {
+	Content string `json:"content"`
+}
+
+// Option is a select, status, or multi-select option.
+type Option struct {
+	Name string `json:"name"`
+}
+
+// PageRef refers to a page in a relation property.
+type PageRef struct {
+	ID string `json:"id"`
+}
+
+// Database holds the property schema of a database.
+type Database struct {
+	ID         string                    `json:"id"`
+	Properties map[string]PropertySchema `json:"properties"`
+}
+
+// PropertySchema describes a database property.
+type PropertySchema struct {
+	ID   string `json:"id"`
+	Type string `json:"type"` // "title", "status", "select", "multi_select", "rich_text", "relation", ...
+}
+
+// Block is a content block, reduced to its type and plain text.
+type Block struct {
+	ID   string
+	Type string // "paragraph", "heading_1", "bulleted_list_item", "code", ...
+	Text string
+}
+
+// UnmarshalJSON reads the block's type and the plain text of its rich_text.
+func (b *Block) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	_ = json.Unmarshal(raw["id"], &b.ID)
+	_ = json.Unmarshal(raw["type"], &b.Type)
+
+	var content struct {
+		RichText []RichText `json:"rich_text"`
+	}
+	if body, ok := raw[b.Type]; ok {
+		_ = json.Unmarshal(body, &content)
+	}
+	b.Text = plainText(content.RichText)
+	return nil
+}
+
+// MarshalJSON writes the block as a block object for append requests.
+func (b Block) MarshalJSON() ([]byte, error) {
+	content := map[string]any{"rich_text": richText(b.Text)}
+	if b.Type == "code" {
+		content["language"] = "plain text"
+	}
+	return json.Marshal(map[string]any{"object": "block", "type": b.Type, b.Type: content})
+}
+
+// listResponse is a page of a paginated list endpoint.
+type listResponse[T any] struct {
+	Results    []T    `json:"results"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// errorResponse is Notion's error body.
+type errorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// maxTextLength is the longest content Notion accepts in one rich text object.
+const maxTextLength = 2000
+
+// richText splits s into rich text objects within Notion's length limit.
+func richText(s string) []RichText {
+	runes := []rune(s)
+	out := []RichText{}
+	for len(runes) > 0 {
+		n := min(len(runes), maxTextLength)
+		out = append(out, RichText{Text: &TextBody{Content: string(runes[:n])}})
+		runes = runes[n:]
+	}
+	return out
+}
+
+// plainText joins the plain text of rich text objects.
+func plainText(rt []RichText) string {
+	var s string
+	for _, r := range rt {
+		if r.PlainText != "" {
+			s += r.PlainText
+		} else if r.Text != nil {
+			s += r.Text.Content
+		}
+	}
+	return s
+}
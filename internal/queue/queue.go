@@ -0,0 +1,131 @@
+// Package queue keeps a journal of syncs that couldn't reach their backend,
+// so they can be replayed in order once it is back.
+package queue
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileName is the journal created inside the beans directory.
+const FileName = ".beanup-queue.jsonl"
+
+// Ops recorded in the journal.
+const (
+	OpCreate = "create"
+	OpUpdate = "update"
+)
+
+// Entry is one queued sync: a bean whose task should be created or
+// updated (tags included) in a backend.
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Sink   string    `json:"sink"`
+	BeanID string    `json:"bean_id"`
+	Op     string    `json:"op"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// Load reads the journal at path, oldest entry first. A missing journal is
+// empty.
+func Load(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading sync queue: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading sync queue: %w", err)
+	}
+	return entries, nil
+}
+
+// Save replaces the journal at path with entries, removing it when there
+// are none. The file is replaced atomically so a crash can't truncate it.
+func Save(path string, entries []Entry) error {
+	if len(entries) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("clearing sync queue: %w", err)
+		}
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), FileName+".*")
+	if err != nil {
+		return fmt.Errorf("writing sync queue: %w", err)
+	}
+	enc := json.NewEncoder(tmp)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+			return fmt.Errorf("writing sync queue: %w", err)
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("writing sync queue: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("writing sync queue: %w", err)
+	}
+	return nil
+}
+
+// For returns the IDs of beans queued for sink, in the order first queued.
+func For(entries []Entry, sink string) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, e := range entries {
+		if e.Sink == sink && !seen[e.BeanID] {
+			seen[e.BeanID] = true
+			ids = append(ids, e.BeanID)
+		}
+	}
+	return ids
+}
+
+// Update drops sink's entries for beans in done and appends failed ones
+// not already queued, keeping the original order.
+func Update(entries []Entry, sink string, done map[string]bool, failed []Entry) []Entry {
+	var out []Entry
+	queued := make(map[string]bool)
+	for _, e := range entries {
+		if e.Sink == sink && done[e.BeanID] {
+			continue
+		}
+		out = append(out, e)
+		if e.Sink == sink {
+			queued[e.BeanID] = true
+		}
+	}
+	for _, e := range failed {
+		if !queued[e.BeanID] {
+			queued[e.BeanID] = true
+			out = append(out, e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,67 @@
+package queue
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+
+	entries, err := Load(path)
+	if err != nil || entries != nil {
+		t.Fatalf("Load() of missing journal = %v, %v", entries, err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	want := []Entry{
+		{Time: now, Sink: "clickup", BeanID: "b1", Op: OpCreate},
+		{Time: now, Sink: "clickup", BeanID: "b2", Op: OpUpdate, Error: "dial tcp: no route to host"},
+	}
+	if err := Save(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Load() = %v, want %v", got, want)
+	}
+
+	if err := Save(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("empty Save() left %s behind", path)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	entries := []Entry{
+		{Sink: "clickup", BeanID: "b1", Op: OpCreate},
+		{Sink: "github", BeanID: "b1", Op: OpUpdate},
+		{Sink: "clickup", BeanID: "b2", Op: OpUpdate},
+	}
+	failed := []Entry{
+		{Sink: "clickup", BeanID: "b2", Op: OpUpdate},
+		{Sink: "clickup", BeanID: "b3", Op: OpCreate},
+	}
+
+	got := Update(entries, "clickup", map[string]bool{"b1": true}, failed)
+
+	var keys []string
+	for _, e := range got {
+		keys = append(keys, e.Sink+"/"+e.BeanID)
+	}
+	want := []string{"github/b1", "clickup/b2", "clickup/b3"}
+	if !slices.Equal(keys, want) {
+		t.Errorf("Update() = %v, want %v", keys, want)
+	}
+	if ids := For(got, "clickup"); !slices.Equal(ids, []string{"b2", "b3"}) {
+		t.Errorf("For() = %v", ids)
+	}
+}
@@ -0,0 +1,236 @@
+// Package restapi is the JSON-over-HTTP client shared by the issue tracker
+// sinks. It handles auth headers, tracing, and retries for rate limits and
+// server errors so each backend only describes its endpoints.
+package restapi
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/syncer"
+	"github.com/toba/bean-me-up/internal/tracing"
+)
+
+// Default retry configuration
+const (
+	defaultMaxRetries = 4
+	baseRetryDelay    = 1 * time.Second
+	maxRetryDelay     = 30 * time.Second
+)
+
+// HTTPDoer sends HTTP requests. *http.Client satisfies it.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Error is a non-2xx response.
+type Error struct {
+	Service    string
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s API error: HTTP %d: %s", e.Service, e.StatusCode, e.Message)
+}
+
+// Gone reports whether err is a missing (404) or deleted (410) response.
+func Gone(err error) bool {
+	apiErr, ok := errors.AsType[*Error](err)
+	return ok && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusGone)
+}
+
+// Container is the repo or project holding a sink's issues. APIs answer 404
+// for a container the token can't see and for a wrong base URL as well as
+// for a deleted issue, so a missing issue only counts as deleted once the
+// container itself has been read.
+type Container struct {
+	// Check fetches the container, failing if it can't be read.
+	Check func(ctx context.Context) error
+
+	mu        sync.Mutex
+	reachable bool
+}
+
+// Missing turns err from fetching one issue into syncer.ErrTaskNotFound if
+// it is a 404 or 410 and the container can be read. Otherwise it returns err
+// unchanged, or the reason the container can't be read, so the Syncer keeps
+// the bean's link. A successful check is remembered.
+func (c *Container) Missing(ctx context.Context, err error) error {
+	if !Gone(err) {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.reachable {
+		if checkErr := c.Check(ctx); checkErr != nil {
+			return fmt.Errorf("%w (container check: %w)", err, checkErr)
+		}
+		c.reachable = true
+	}
+	return fmt.Errorf("%w: %w", syncer.ErrTaskNotFound, err)
+}
+
+// Client sends JSON requests to one API.
+type Client struct {
+	// Service names the API in span names and errors, e.g. "github".
+	Service string
+	BaseURL string
+	HTTP    HTTPDoer
+	// Authorize sets credentials and API-specific headers on each attempt.
+	Authorize func(req *http.Request)
+	// RateLimited reports whether a 4xx response other than 429 is a rate
+	// limit that should be retried (e.g. GitHub's 403 with no quota left).
+	RateLimited func(resp *http.Response) bool
+	// ErrorMessage extracts a readable message from an error body.
+	// Defaults to the raw body.
+	ErrorMessage func(body []byte) string
+	// ContentType is sent with request bodies. Defaults to application/json.
+	ContentType string
+	MaxRetries  int
+	// RetryDelay is the first backoff delay; it doubles on each retry.
+	RetryDelay time.Duration
+}
+
+// New creates a client with a 30s HTTP timeout and default retries.
+func New(service, baseURL string) *Client {
+	return &Client{
+		Service:    service,
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTP:       &http.Client{Timeout: 30 * time.Second},
+		MaxRetries: defaultMaxRetries,
+		RetryDelay: baseRetryDelay,
+	}
+}
+
+// Do sends body as JSON to path (relative to BaseURL) and decodes the
+// response into result. Rate limits (429) and 5xx responses are retried
+// with exponential backoff, honoring Retry-After when present.
+func (c *Client) Do(ctx context.Context, method, path string, body, result any) (err error) {
+	ctx, span := tracing.StartClient(ctx, c.Service+" "+method+" "+strings.SplitN(path, "?", 2)[0],
+		tracing.String("http.request.method", method),
+		tracing.String("url.path", path),
+	)
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	var payload []byte
+	if body != nil {
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("marshaling request: %w", err)
+		}
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.MaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := min(c.RetryDelay*time.Duration(1<<(attempt-1)), maxRetryDelay)
+			delay += time.Duration(rand.Int64N(int64(delay/4) + 1))
+			var retryErr *retryableError
+			if errors.As(lastErr, &retryErr) && retryErr.retryAfter > 0 {
+				delay = min(retryErr.retryAfter, maxRetryDelay)
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+			syncer.NoteRetry(ctx)
+		}
+
+		var reqBody io.Reader
+		if payload != nil {
+			reqBody = bytes.NewReader(payload)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reqBody)
+		if err != nil {
+			return fmt.Errorf("creating request: %w", err)
+		}
+		req.Header.Set("Accept", "application/json")
+		if payload != nil {
+			req.Header.Set("Content-Type", cmp.Or(c.ContentType, "application/json"))
+		}
+		if c.Authorize != nil {
+			c.Authorize(req)
+		}
+
+		span.SetAttributes(tracing.Int("http.request.resend_count", attempt))
+
+		resp, err := c.HTTP.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			lastErr = fmt.Errorf("executing request: %w", err)
+			continue
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("reading response: %w", err)
+		}
+
+		span.SetAttributes(tracing.Int("http.response.status_code", resp.StatusCode))
+
+		if resp.StatusCode >= 400 {
+			apiErr := &Error{Service: c.Service, StatusCode: resp.StatusCode, Message: string(respBody)}
+			if c.ErrorMessage != nil {
+				if msg := c.ErrorMessage(respBody); msg != "" {
+					apiErr.Message = msg
+				}
+			}
+
+			rateLimited := resp.StatusCode == http.StatusTooManyRequests ||
+				(c.RateLimited != nil && c.RateLimited(resp))
+			if rateLimited || resp.StatusCode >= 500 {
+				lastErr = &retryableError{err: apiErr, retryAfter: retryAfter(resp.Header)}
+				continue
+			}
+			return apiErr
+		}
+
+		if result != nil && len(respBody) > 0 {
+			if err := json.Unmarshal(respBody, result); err != nil {
+				return fmt.Errorf("decoding response: %w", err)
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("max retries exceeded: %w", lastErr)
+}
+
+// retryableError wraps a rate limit or server error with the server's requested delay.
+type retryableError struct {
+	err        *Error
+	retryAfter time.Duration
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+// retryAfter reads the delay requested by Retry-After or X-RateLimit-Reset.
+func retryAfter(h http.Header) time.Duration {
+	if secs, err := strconv.Atoi(h.Get("Retry-After")); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		if d := time.Until(time.Unix(reset, 0)); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,97 @@
+package restapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+func TestDo_RetriesRateLimitsAndServerErrors(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch calls {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			if r.Header.Get("Authorization") != "Bearer tok" {
+				t.Errorf("missing auth header on retry")
+			}
+			_, _ = w.Write([]byte(`{"id":42}`))
+		}
+	}))
+	defer server.Close()
+
+	c := New("test", server.URL)
+	c.RetryDelay = time.Millisecond
+	c.Authorize = func(req *http.Request) { req.Header.Set("Authorization", "Bearer tok") }
+
+	var result struct{ ID int }
+	if err := c.Do(context.Background(), "POST", "/things", map[string]string{"a": "b"}, &result); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if calls != 3 || result.ID != 42 {
+		t.Errorf("calls = %d, id = %d; want 3 calls and id 42", calls, result.ID)
+	}
+}
+
+func TestDo_NotFoundIsNotErrTaskNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"gone"}`))
+	}))
+	defer server.Close()
+
+	c := New("test", server.URL)
+	c.ErrorMessage = func(body []byte) string { return "custom" }
+
+	err := c.Do(context.Background(), "GET", "/things/1", nil, nil)
+	if errors.Is(err, syncer.ErrTaskNotFound) {
+		t.Fatalf("error = %v, want a plain API error", err)
+	}
+	if !Gone(err) {
+		t.Errorf("Gone(%v) = false, want true", err)
+	}
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Message != "custom" {
+		t.Errorf("error = %#v, want message from ErrorMessage", err)
+	}
+}
+
+func TestContainer_Missing(t *testing.T) {
+	notFound := &Error{Service: "test", StatusCode: http.StatusNotFound, Message: "Not Found"}
+	tests := []struct {
+		name     string
+		err      error
+		checkErr error
+		want     bool
+	}{
+		{"issue gone, container readable", notFound, nil, true},
+		{"410 gone", &Error{Service: "test", StatusCode: http.StatusGone}, nil, true},
+		{"container unreadable", notFound, notFound, false},
+		{"forbidden", &Error{Service: "test", StatusCode: http.StatusForbidden}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := 0
+			c := &Container{Check: func(context.Context) error { checks++; return tt.checkErr }}
+			for range 2 {
+				err := c.Missing(context.Background(), fmt.Errorf("getting issue: %w", tt.err))
+				if got := errors.Is(err, syncer.ErrTaskNotFound); got != tt.want {
+					t.Fatalf("errors.Is(%v, ErrTaskNotFound) = %v, want %v", err, got, tt.want)
+				}
+			}
+			if tt.want && checks != 1 {
+				t.Errorf("checks = %d, want the container checked once", checks)
+			}
+		})
+	}
+}
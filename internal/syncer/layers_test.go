@@ -0,0 +1,31 @@
+package syncer
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/beans"
+)
+
+func TestParentLayers(t *testing.T) {
+	beanList := []beans.Bean{
+		{ID: "c", Parent: "b"},
+		{ID: "b", Parent: "a"},
+		{ID: "a"},
+		{ID: "x", Parent: "y"}, // x and y are each other's parent
+		{ID: "y", Parent: "x"},
+		{ID: "z", Parent: "x"},
+	}
+	var got []string
+	for _, layer := range parentLayers(beanList) {
+		var ids []string
+		for _, b := range layer {
+			ids = append(ids, b.ID)
+		}
+		got = append(got, strings.Join(ids, ","))
+	}
+	if want := []string{"a,x,y", "b,z", "c"}; !slices.Equal(got, want) {
+		t.Errorf("layers = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,196 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+)
+
+// ErrTaskNotFound is returned by Sink.GetTask when a linked task no longer
+// exists. The Syncer unlinks the bean and creates a new task.
+var ErrTaskNotFound = errors.New("task not found")
+
+// ErrUnreachable marks a sync that failed because the backend couldn't be
+// reached. Once one bean fails this way the Syncer fails the rest fast.
+var ErrUnreachable = errors.New("backend unreachable")
+
+// IsUnreachable reports whether err means the backend couldn't be reached:
+// ErrUnreachable, or a network failure (no route, DNS, refused or reset
+// connection, timeout) rather than an error response or a cancellation.
+func IsUnreachable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, ErrUnreachable) {
+		return true
+	}
+	if _, ok := errors.AsType[*url.Error](err); ok {
+		return true
+	}
+	_, ok := errors.AsType[net.Error](err)
+	return ok
+}
+
+// TaskRef identifies a task in a sink.
+type TaskRef struct {
+	ID  string
+	URL string
+	// Tags are the task's current tags or labels, as returned by GetTask.
+	Tags []string
+	// Remote is a backend-specific snapshot from GetTask, handed back to
+	// UpdateTask so the sink can diff without refetching.
+	Remote any
+	// MovedTo is set by GetTask when the task has left the sink's
+	// configured list or project; it names where the task is now.
+	MovedTo string
+	// UpdatedAt is when the task last changed remotely, if known.
+	UpdatedAt *time.Time
+	// Blocking are the IDs of the tasks this task blocks, if known.
+	Blocking []string
+}
+
+// Sink is an issue tracker backend that beans are pushed to. The Syncer
+// owns ordering, filtering, and sync state; a Sink only maps a bean onto
+// its tracker's task model.
+type Sink interface {
+	// Name is the registry key, also used as the bean extension name for sync state.
+	Name() string
+	// Prepare runs once before a sync to warm caches. Errors are non-fatal.
+	Prepare(ctx context.Context) error
+	// GetTask fetches a linked task. It returns an error matching
+	// ErrTaskNotFound if the task was deleted.
+	GetTask(ctx context.Context, taskID string) (*TaskRef, error)
+	// CreateTask creates a task for the bean, as a child of parentTaskID if
+	// set. If the task was created but a follow-up step such as setting
+	// its state failed, it returns the task with the error: the bean is
+	// linked without a sync time, so the next sync retries as an update.
+	CreateTask(ctx context.Context, b *beans.Bean, parentTaskID string) (*TaskRef, error)
+	// UpdateTask pushes changed bean fields to an existing task and reports
+	// whether anything changed.
+	UpdateTask(ctx context.Context, current *TaskRef, b *beans.Bean) (*TaskRef, bool, error)
+	// SyncTags makes the task's tags match the bean's. Best-effort; reports
+	// whether anything changed.
+	SyncTags(ctx context.Context, task *TaskRef, b *beans.Bean) bool
+	// SetRelationship records that blockerTaskID blocks blockedTaskID.
+	SetRelationship(ctx context.Context, blockerTaskID, blockedTaskID string) error
+}
+
+// StatusUpdater is implemented by sinks that can set a task's status
+// without a full update. Options.StatusOnly uses it; other sinks get a
+// full update.
+type StatusUpdater interface {
+	// UpdateStatus sets the status mapped from the bean's and reports
+	// whether it changed.
+	UpdateStatus(ctx context.Context, taskID string, b *beans.Bean) (*TaskRef, bool, error)
+}
+
+// FieldReporter is implemented by sinks that can say which task fields an
+// update changed. The Syncer calls UpdateTaskFields instead of UpdateTask
+// and reports the fields in Result.FieldsChanged; none means unchanged.
+type FieldReporter interface {
+	UpdateTaskFields(ctx context.Context, current *TaskRef, b *beans.Bean) (*TaskRef, []string, error)
+}
+
+// TaskReturner is implemented by sinks that can move a task back into
+// their configured list. Options.OnMove OnMoveReturn uses it.
+type TaskReturner interface {
+	ReturnTask(ctx context.Context, task *TaskRef) (*TaskRef, error)
+}
+
+// TaskRemover is implemented by sinks that can act on the tasks of deleted
+// beans. RemoveTask closes, archives, or deletes the task as configured and
+// returns what it did, or "" if the sink leaves such tasks alone.
+type TaskRemover interface {
+	RemoveTask(ctx context.Context, taskID string) (string, error)
+}
+
+// TaskLister is implemented by sinks that can fetch their tasks in bulk.
+// The Syncer lists them before an incremental sync so beans whose task was
+// edited remotely are synced too, though the bean itself is unchanged.
+type TaskLister interface {
+	// ListTasks fetches the sink's tasks, keyed by task ID. Tasks it can't
+	// reach this way are simply missing.
+	ListTasks(ctx context.Context) (map[string]*TaskRef, error)
+	// TagFingerprints hashes the task's tags that SyncTags manages, and
+	// the tags SyncTags would give it for the bean. They differ when the
+	// task's tags were edited remotely.
+	TagFingerprints(task *TaskRef, b *beans.Bean) (taskTags, beanTags string)
+}
+
+// ParentLinker is implemented by sinks that can set a task's parent after
+// creation, whether or not they can at creation time. The Syncer calls
+// SetParent during the relationship pass for every bean whose parent is
+// also synced; it does nothing if the parent is already set.
+type ParentLinker interface {
+	SetParent(ctx context.Context, parentTaskID, childTaskID string) error
+}
+
+// BeanValidator is implemented by sinks that can tell a bean's values
+// won't be accepted before sending anything. The Syncer calls ValidateBean
+// for every bean it is about to create or update, dry runs included, and
+// fails the bean with its error.
+type BeanValidator interface {
+	ValidateBean(ctx context.Context, b *beans.Bean) error
+}
+
+// ReferenceLinker is implemented by sinks that record a bean's references
+// to other beans, such as its parent, in task fields. During the
+// relationship pass the Syncer asks for the beans b refers to and, if any
+// are synced, calls SetReferences with their task IDs.
+type ReferenceLinker interface {
+	// References returns the IDs of the beans b refers to in linked fields.
+	References(ctx context.Context, b *beans.Bean) []string
+	// SetReferences points the task's linked fields at the tasks of the
+	// beans b refers to, given by taskIDs (bean ID to task ID).
+	SetReferences(ctx context.Context, taskID string, b *beans.Bean, taskIDs map[string]string) error
+}
+
+// Factory builds a Sink from the loaded configuration.
+type Factory func(cfg *config.Config) (Sink, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+// Register makes a sink available by name. It panics if name is already
+// registered, so call it from the backend package's init.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := registry[name]; dup {
+		panic("syncer: sink registered twice: " + name)
+	}
+	registry[name] = factory
+}
+
+// NewSink builds the named sink.
+func NewSink(name string, cfg *config.Config) (Sink, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown sink %q (available: %v)", name, Sinks())
+	}
+	return factory(cfg)
+}
+
+// Sinks returns the registered sink names in sorted order.
+func Sinks() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -1,4 +1,4 @@
-package clickup
+package syncer
 
 import (
 	"sync"
@@ -7,8 +7,8 @@ import (
 	"github.com/toba/bean-me-up/internal/beans"
 )
 
-// SyncStateProvider abstracts sync state storage for the syncer.
-type SyncStateProvider interface {
+// StateProvider abstracts sync state storage for the syncer.
+type StateProvider interface {
 	GetTaskID(beanID string) *string
 	GetSyncedAt(beanID string) *time.Time
 	SetTaskID(beanID, taskID string)
@@ -17,9 +17,17 @@ type SyncStateProvider interface {
 	Flush() error
 }
 
+// TaskURLStore is implemented by state providers that can also record
+// the task's URL. The Syncer uses it when Options.StoreTaskURL is set.
+type TaskURLStore interface {
+	GetTaskURL(beanID string) string
+	SetTaskURL(beanID, url string)
+}
+
 // extensionCache holds cached sync state for a single bean.
 type extensionCache struct {
 	taskID   string
+	taskURL  string
 	syncedAt *time.Time
 }
 
@@ -29,28 +37,33 @@ type pendingOp struct {
 	set    *beans.ExtensionDataOp // nil means remove
 }
 
-// ExtensionSyncProvider implements SyncStateProvider using beans' extension metadata.
-type ExtensionSyncProvider struct {
+// ExtensionStateProvider implements StateProvider using beans' extension
+// metadata, stored under the sink's extension name.
+type ExtensionStateProvider struct {
 	client *beans.Client
+	name   string
 	mu     sync.RWMutex
 	cache  map[string]*extensionCache
 	ops    []pendingOp
 }
 
-// NewExtensionSyncProvider creates a provider pre-populated from a bean list.
-func NewExtensionSyncProvider(client *beans.Client, beanList []beans.Bean) *ExtensionSyncProvider {
-	p := &ExtensionSyncProvider{
+// NewExtensionStateProvider creates a provider for the named extension
+// (e.g. beans.PluginClickUp), pre-populated from a bean list.
+func NewExtensionStateProvider(client *beans.Client, name string, beanList []beans.Bean) *ExtensionStateProvider {
+	p := &ExtensionStateProvider{
 		client: client,
+		name:   name,
 		cache:  make(map[string]*extensionCache, len(beanList)),
 	}
 
 	for _, b := range beanList {
-		taskID := b.GetExtensionString(beans.PluginClickUp, beans.ExtKeyTaskID)
-		syncedAt := b.GetExtensionTime(beans.PluginClickUp, beans.ExtKeySyncedAt)
+		taskID := b.GetExtensionString(name, beans.ExtKeyTaskID)
+		syncedAt := b.GetExtensionTime(name, beans.ExtKeySyncedAt)
 
 		if taskID != "" || syncedAt != nil {
 			p.cache[b.ID] = &extensionCache{
 				taskID:   taskID,
+				taskURL:  b.GetExtensionString(name, beans.ExtKeyTaskURL),
 				syncedAt: syncedAt,
 			}
 		}
@@ -59,7 +72,7 @@ func NewExtensionSyncProvider(client *beans.Client, beanList []beans.Bean) *Exte
 	return p
 }
 
-func (p *ExtensionSyncProvider) GetTaskID(beanID string) *string {
+func (p *ExtensionStateProvider) GetTaskID(beanID string) *string {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
@@ -70,7 +83,7 @@ func (p *ExtensionSyncProvider) GetTaskID(beanID string) *string {
 	return &c.taskID
 }
 
-func (p *ExtensionSyncProvider) GetSyncedAt(beanID string) *time.Time {
+func (p *ExtensionStateProvider) GetSyncedAt(beanID string) *time.Time {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
@@ -81,7 +94,7 @@ func (p *ExtensionSyncProvider) GetSyncedAt(beanID string) *time.Time {
 	return c.syncedAt
 }
 
-func (p *ExtensionSyncProvider) SetTaskID(beanID, taskID string) {
+func (p *ExtensionStateProvider) SetTaskID(beanID, taskID string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -92,7 +105,7 @@ func (p *ExtensionSyncProvider) SetTaskID(beanID, taskID string) {
 	p.appendSetOp(beanID)
 }
 
-func (p *ExtensionSyncProvider) SetSyncedAt(beanID string, t time.Time) {
+func (p *ExtensionStateProvider) SetSyncedAt(beanID string, t time.Time) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -104,7 +117,28 @@ func (p *ExtensionSyncProvider) SetSyncedAt(beanID string, t time.Time) {
 	p.appendSetOp(beanID)
 }
 
-func (p *ExtensionSyncProvider) Clear(beanID string) {
+func (p *ExtensionStateProvider) GetTaskURL(beanID string) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if c, ok := p.cache[beanID]; ok {
+		return c.taskURL
+	}
+	return ""
+}
+
+func (p *ExtensionStateProvider) SetTaskURL(beanID, url string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cache[beanID] == nil {
+		p.cache[beanID] = &extensionCache{}
+	}
+	p.cache[beanID].taskURL = url
+	p.appendSetOp(beanID)
+}
+
+func (p *ExtensionStateProvider) Clear(beanID string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -114,7 +148,7 @@ func (p *ExtensionSyncProvider) Clear(beanID string) {
 
 // Flush writes all pending operations to beans via GraphQL.
 // Set operations are batched; remove operations are executed individually.
-func (p *ExtensionSyncProvider) Flush() error {
+func (p *ExtensionStateProvider) Flush() error {
 	p.mu.Lock()
 	ops := p.ops
 	p.ops = nil
@@ -152,7 +186,7 @@ func (p *ExtensionSyncProvider) Flush() error {
 
 	// Remove operations individually
 	for _, id := range removeIDs {
-		if err := p.client.RemoveExtensionData(id, beans.PluginClickUp); err != nil {
+		if err := p.client.RemoveExtensionData(id, p.name); err != nil {
 			return err
 		}
 	}
@@ -162,11 +196,14 @@ func (p *ExtensionSyncProvider) Flush() error {
 
 // appendSetOp adds or updates a pending set operation for the given bean.
 // Must be called with p.mu held for writing.
-func (p *ExtensionSyncProvider) appendSetOp(beanID string) {
+func (p *ExtensionStateProvider) appendSetOp(beanID string) {
 	c := p.cache[beanID]
 	data := map[string]any{
 		beans.ExtKeyTaskID: c.taskID,
 	}
+	if c.taskURL != "" {
+		data[beans.ExtKeyTaskURL] = c.taskURL
+	}
 	if c.syncedAt != nil {
 		data[beans.ExtKeySyncedAt] = c.syncedAt.Format(time.RFC3339)
 	}
@@ -175,8 +212,8 @@ func (p *ExtensionSyncProvider) appendSetOp(beanID string) {
 		beanID: beanID,
 		set: &beans.ExtensionDataOp{
 			ID:   beanID,
-			Name: beans.PluginClickUp,
-			Data:   data,
+			Name: p.name,
+			Data: data,
 		},
 	})
 }
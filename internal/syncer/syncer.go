@@ -0,0 +1,839 @@
+// Package syncer pushes beans to an issue tracker through a pluggable Sink.
+// It owns the multi-pass ordering (parents before children, then blocking
+// relationships), change detection, and sync state; backends only map
+// beans onto their task model.
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+	"github.com/toba/bean-me-up/internal/tracing"
+)
+
+// Result holds the result of syncing a single bean.
+type Result struct {
+	BeanID    string
+	BeanTitle string
+	TaskID    string
+	TaskURL   string
+	Action    string // "created", "updated", "unchanged", "skipped", "moved", "would create", "would update", "cancelled", "error"
+	Error     error
+	// MovedTo is where a task that left the sink's configured list now
+	// lives, for "moved" results.
+	MovedTo string
+	// Duration is how long syncing the bean took.
+	Duration time.Duration
+	// Reason says why the bean was synced or left alone, one of the
+	// Reason constants; empty when there's nothing to explain.
+	Reason string
+	// FieldsChanged names the task fields an update changed, e.g.
+	// "status" or "tags", for sinks that report them (see FieldReporter).
+	FieldsChanged []string
+	// Retried is set if any request for the bean had to be retried.
+	Retried bool
+	// Conflict is set if the bean and its task both changed since the
+	// last sync, so the task's edits were overwritten.
+	Conflict bool
+}
+
+// Reasons reported in Result.Reason.
+const (
+	ReasonNew               = "never synced"
+	ReasonBeanChanged       = "bean changed"
+	ReasonTaskEdited        = "task edited remotely"
+	ReasonTaskTags          = "task tags changed"
+	ReasonTaskRelationships = "task relationships changed"
+	ReasonForced            = "forced"
+	ReasonUpToDate          = "up to date"
+	ReasonNotLinked         = "not linked"
+	ReasonTaskDeleted       = "task deleted"
+	ReasonTaskMoved         = "task moved"
+)
+
+// driftReasons maps RemoteDrift results to the reason they give for a sync.
+var driftReasons = map[string]string{
+	DriftEdited:        ReasonTaskEdited,
+	DriftTags:          ReasonTaskTags,
+	DriftRelationships: ReasonTaskRelationships,
+}
+
+// retriedKey is the context key of the flag NoteRetry sets.
+type retriedKey struct{}
+
+// NoteRetry records that a request made with ctx was retried, for
+// Result.Retried. Sink clients call it from their retry loops.
+func NoteRetry(ctx context.Context) {
+	if retried, ok := ctx.Value(retriedKey{}).(*atomic.Bool); ok {
+		retried.Store(true)
+	}
+}
+
+// ProgressFunc is called when a bean sync completes.
+type ProgressFunc func(result Result, completed, total int)
+
+// Options configures the sync operation.
+type Options struct {
+	DryRun          bool
+	Force           bool
+	NoRelationships bool
+	StoreTaskURL    bool // Record task URLs if the state provider is a TaskURLStore
+	// StatusOnly pushes just the status of changed, linked beans. Unlinked
+	// beans are skipped and relationships aren't synced. With a
+	// StatusUpdater sink synced_at isn't advanced, so a later full sync
+	// still picks up other changes; other sinks fall back to a full
+	// update, which does advance it
+	StatusOnly bool
+	// OnMove says what to do with a task that moved out of the sink's
+	// configured list: OnMoveWarn (default), OnMoveFollow, or OnMoveReturn
+	OnMove string
+	// RelationshipsOnly leaves tasks alone and just links the parents and
+	// blockers of linked beans, e.g. after bulk-linking existing tasks.
+	// Unlinked beans are skipped and synced_at isn't advanced
+	RelationshipsOnly bool
+	// RemoteTasks are the sink's tasks already listed by
+	// FilterBeansNeedingRemoteSync, used instead of listing them again
+	RemoteTasks map[string]*TaskRef
+	OnProgress  ProgressFunc // Optional callback for progress updates
+}
+
+// Values for Options.OnMove.
+const (
+	// OnMoveWarn reports the move and leaves the task alone.
+	OnMoveWarn = "warn"
+	// OnMoveFollow keeps syncing the task where it now lives.
+	OnMoveFollow = "follow"
+	// OnMoveReturn moves the task back, if the sink is a TaskReturner,
+	// then syncs it.
+	OnMoveReturn = "return"
+)
+
+// Syncer syncs beans to a Sink.
+type Syncer struct {
+	sink      Sink
+	opts      Options
+	syncStore StateProvider
+
+	// Tracking for relationship pass
+	beanToTaskID map[string]string // bean ID -> task ID
+
+	// Tasks prefetched from a TaskLister, by task ID; nil if not listed
+	remote map[string]*TaskRef
+
+	// Set once the sink is found unreachable; later beans fail fast
+	// instead of each waiting out the client's retries
+	unreachable atomic.Bool
+}
+
+// New creates a syncer that pushes beans to sink, recording links in syncStore.
+func New(sink Sink, opts Options, syncStore StateProvider) *Syncer {
+	return &Syncer{
+		sink:         sink,
+		opts:         opts,
+		syncStore:    syncStore,
+		beanToTaskID: make(map[string]string),
+	}
+}
+
+// SyncBeans syncs a list of beans to tasks.
+// Uses a multi-pass approach:
+//  1. Create/update tasks one hierarchy level at a time (see parentLayers),
+//     so each parent task exists before its children reference it
+//  2. Sync blocking relationships (and parent links for ParentLinker sinks,
+//     references for ReferenceLinker sinks)
+func (s *Syncer) SyncBeans(ctx context.Context, beanList []beans.Bean) ([]Result, error) {
+	ctx, span := tracing.Start(ctx, "sync.beans",
+		tracing.String("sync.sink", s.sink.Name()),
+		tracing.Int("beans.count", len(beanList)),
+		tracing.Bool("sync.dry_run", s.opts.DryRun),
+	)
+	defer span.End()
+
+	// Pre-fetch backend metadata; non-fatal, the sink degrades gracefully
+	if err := s.sink.Prepare(ctx); err != nil {
+		_ = err
+	}
+
+	// Pre-populate mapping with already-synced beans from sync store
+	for _, b := range beanList {
+		taskID := s.syncStore.GetTaskID(b.ID)
+		if taskID != nil && *taskID != "" {
+			s.beanToTaskID[b.ID] = *taskID
+		}
+	}
+
+	if s.opts.RelationshipsOnly {
+		return s.linkRelationships(ctx, beanList), nil
+	}
+
+	// Prefetch linked tasks to notice remote-only edits; without them
+	// change detection goes by bean timestamps alone
+	if lister, ok := s.sink.(TaskLister); ok && len(s.beanToTaskID) > 0 && !s.opts.Force && !s.opts.StatusOnly {
+		if s.opts.RemoteTasks != nil {
+			s.remote = s.opts.RemoteTasks
+		} else if tasks, err := lister.ListTasks(ctx); err == nil {
+			s.remote = tasks
+		}
+	}
+
+	// Create index mapping for results
+	beanIndex := make(map[string]int)
+	for i, b := range beanList {
+		beanIndex[b.ID] = i
+	}
+	results := make([]Result, len(beanList))
+	total := len(beanList)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex // protects beanToTaskID and completed count
+	var completed int
+
+	// Helper to report progress
+	reportProgress := func(result Result) {
+		if s.opts.OnProgress != nil {
+			mu.Lock()
+			completed++
+			current := completed
+			mu.Unlock()
+			s.opts.OnProgress(result, current, total)
+		}
+	}
+
+	syncLayer := func(layer []beans.Bean) {
+		for _, bean := range layer {
+			wg.Go(func() {
+				start := time.Now()
+				result := s.syncBean(ctx, &bean, &mu)
+				result.Duration = time.Since(start)
+				results[beanIndex[bean.ID]] = result
+
+				if result.Error == nil && result.Action != "skipped" && result.TaskID != "" {
+					mu.Lock()
+					s.beanToTaskID[bean.ID] = result.TaskID
+					mu.Unlock()
+				}
+				reportProgress(result)
+			})
+		}
+		wg.Wait()
+	}
+
+	// Pass 1: Create/update tasks in parallel within each level, parents
+	// before children at any depth
+	for _, layer := range parentLayers(beanList) {
+		syncLayer(layer)
+	}
+
+	// Pass 2: Sync blocking relationships in parallel (if not disabled)
+	if !s.opts.NoRelationships && !s.opts.StatusOnly && !s.opts.DryRun && !s.unreachable.Load() && ctx.Err() == nil {
+		relCtx, relSpan := tracing.Start(ctx, "sync.relationships")
+		for _, bean := range beanList {
+			wg.Go(func() {
+				_, _ = s.syncRelationships(relCtx, &bean)
+			})
+		}
+		wg.Wait()
+		relSpan.End()
+	}
+
+	return results, nil
+}
+
+// parentLayers schedules beans by their parent graph: each layer holds the
+// beans whose parent is in an earlier layer or isn't being synced, so
+// parents are created before children however deep the hierarchy. Order
+// within a layer follows beanList. Beans in a parent cycle have no valid
+// order and go in the first layer.
+func parentLayers(beanList []beans.Bean) [][]beans.Bean {
+	parentOf := make(map[string]string, len(beanList))
+	for _, b := range beanList {
+		parentOf[b.ID] = b.Parent
+	}
+
+	depth := make(map[string]int, len(beanList))
+	for _, b := range beanList {
+		// Walk up to a bean of known depth or one whose parent isn't
+		// being synced, then number the chain on the way back down
+		var chain []string
+		onChain := make(map[string]int)
+		base := -1
+		for id := b.ID; ; {
+			if d, ok := depth[id]; ok {
+				base = d
+				break
+			}
+			if i, ok := onChain[id]; ok {
+				for _, c := range chain[i:] {
+					depth[c] = 0
+				}
+				chain, base = chain[:i], 0
+				break
+			}
+			onChain[id] = len(chain)
+			chain = append(chain, id)
+			parent := parentOf[id]
+			if _, ok := parentOf[parent]; !ok {
+				break
+			}
+			id = parent
+		}
+		for i := len(chain) - 1; i >= 0; i-- {
+			base++
+			depth[chain[i]] = base
+		}
+	}
+
+	var layers [][]beans.Bean
+	for _, b := range beanList {
+		d := depth[b.ID]
+		for len(layers) <= d {
+			layers = append(layers, nil)
+		}
+		layers[d] = append(layers[d], b)
+	}
+	return layers
+}
+
+// syncBean syncs a single bean, recording a span for it.
+func (s *Syncer) syncBean(ctx context.Context, b *beans.Bean, mu *sync.Mutex) Result {
+	// Once the sync is cancelled, beans not yet started are left alone
+	if ctx.Err() != nil {
+		return Result{BeanID: b.ID, BeanTitle: b.Title, Action: "cancelled", Error: ctx.Err()}
+	}
+	ctx, span := tracing.Start(ctx, "sync.bean", tracing.String("bean.id", b.ID))
+	var retried atomic.Bool
+	result := s.doSyncBean(context.WithValue(ctx, retriedKey{}, &retried), b, mu)
+	result.Retried = retried.Load()
+	if IsUnreachable(result.Error) {
+		s.unreachable.Store(true)
+	}
+	if result.Error != nil && ctx.Err() != nil {
+		result.Action = "cancelled"
+	}
+	span.SetAttributes(tracing.String("sync.action", result.Action))
+	if result.TaskID != "" {
+		span.SetAttributes(tracing.String(s.sink.Name()+".task_id", result.TaskID))
+	}
+	span.RecordError(result.Error)
+	span.End()
+	return result
+}
+
+// doSyncBean creates or updates the task for a single bean.
+func (s *Syncer) doSyncBean(ctx context.Context, b *beans.Bean, mu *sync.Mutex) Result {
+	result := Result{
+		BeanID:    b.ID,
+		BeanTitle: b.Title,
+	}
+
+	// Check if already linked (from sync store)
+	taskID := s.syncStore.GetTaskID(b.ID)
+	if taskID != nil && *taskID != "" {
+		result.TaskID = *taskID
+
+		// Check if bean has changed since last sync
+		result.Reason, result.Conflict = s.syncReason(b)
+		if result.Reason == "" {
+			if !s.opts.Force {
+				result.Action = "skipped"
+				result.Reason = ReasonUpToDate
+				return result
+			}
+			result.Reason = ReasonForced
+		}
+
+		if s.unreachable.Load() {
+			return s.unreachableResult(result)
+		}
+
+		if updater, ok := s.sink.(StatusUpdater); ok && s.opts.StatusOnly {
+			return s.updateStatus(ctx, updater, b, result)
+		}
+
+		if err := s.validateBean(ctx, b); err != nil {
+			result.Action = "error"
+			result.Error = err
+			return result
+		}
+
+		// Verify task still exists
+		task, err := s.sink.GetTask(ctx, *taskID)
+		if err != nil {
+			// Check if task was deleted - if so, unlink and create new
+			if errors.Is(err, ErrTaskNotFound) {
+				s.syncStore.Clear(b.ID)
+				result.Reason, result.Conflict = ReasonTaskDeleted, false
+				// Fall through to create new task below
+			} else {
+				result.Action = "error"
+				result.Error = fmt.Errorf("fetching task %s: %w", *taskID, err)
+				return result
+			}
+		} else {
+			// Task exists - update it
+			result.TaskURL = task.URL
+
+			if task.MovedTo != "" {
+				result.MovedTo = task.MovedTo
+				if s.opts.OnMove != OnMoveFollow && s.opts.OnMove != OnMoveReturn {
+					// Don't silently edit a task in someone else's list
+					result.Action = "moved"
+					result.Reason = ReasonTaskMoved
+					return result
+				}
+			}
+
+			if s.opts.DryRun {
+				result.Action = "would update"
+				return result
+			}
+
+			if task.MovedTo != "" && s.opts.OnMove == OnMoveReturn {
+				returner, ok := s.sink.(TaskReturner)
+				if !ok {
+					result.Action = "error"
+					result.Error = fmt.Errorf("task %s moved to %s and %s can't move it back", task.ID, task.MovedTo, s.sink.Name())
+					return result
+				}
+				if task, err = returner.ReturnTask(ctx, task); err != nil {
+					result.Action = "error"
+					result.Error = fmt.Errorf("moving task back: %w", err)
+					return result
+				}
+			}
+
+			updated, changed, err := s.updateTask(ctx, task, b, &result)
+			if err != nil {
+				result.Action = "error"
+				result.Error = fmt.Errorf("updating task: %w", err)
+				return result
+			}
+			if updated != nil && updated.URL != "" {
+				result.TaskURL = updated.URL
+			}
+
+			// Sync tags (best-effort)
+			tagsChanged := s.sink.SyncTags(ctx, task, b)
+			if tagsChanged {
+				result.FieldsChanged = append(result.FieldsChanged, "tags")
+			}
+
+			// Update synced_at timestamp in sync store
+			s.syncStore.SetSyncedAt(b.ID, time.Now().UTC())
+			s.storeTaskURL(b.ID, result.TaskURL)
+
+			if result.MovedTo != "" {
+				result.Action = "moved"
+			} else if changed || tagsChanged {
+				result.Action = "updated"
+			} else {
+				result.Action = "unchanged"
+			}
+			return result
+		}
+	}
+
+	// Status-only syncs never create tasks
+	if s.opts.StatusOnly {
+		result.Action = "skipped"
+		result.Reason = ReasonNotLinked
+		return result
+	}
+	if result.Reason == "" {
+		result.Reason = ReasonNew
+	}
+
+	if err := s.validateBean(ctx, b); err != nil {
+		result.Action = "error"
+		result.Error = err
+		return result
+	}
+
+	// Create new task
+	if s.opts.DryRun {
+		result.Action = "would create"
+		return result
+	}
+
+	// Set parent task ID if bean has a parent that's already synced
+	var parentTaskID string
+	if b.Parent != "" {
+		mu.Lock()
+		parentTaskID = s.beanToTaskID[b.Parent]
+		mu.Unlock()
+	}
+
+	if s.unreachable.Load() {
+		return s.unreachableResult(result)
+	}
+
+	// A create cut short by a cancel could leave a task the bean isn't
+	// linked to, duplicated by the next sync, so it runs to completion
+	task, err := s.sink.CreateTask(context.WithoutCancel(ctx), b, parentTaskID)
+	if err != nil {
+		result.Action = "error"
+		result.Error = fmt.Errorf("creating task: %w", err)
+		if task != nil {
+			// Link the half-made task so the next sync finishes it
+			// instead of creating another; no sync time keeps it pending
+			result.TaskID = task.ID
+			result.TaskURL = task.URL
+			s.syncStore.SetTaskID(b.ID, task.ID)
+			s.storeTaskURL(b.ID, task.URL)
+			mu.Lock()
+			s.beanToTaskID[b.ID] = task.ID
+			mu.Unlock()
+		}
+		return result
+	}
+
+	result.TaskID = task.ID
+	result.TaskURL = task.URL
+
+	// Sync tags for new task (no existing tags to remove)
+	s.sink.SyncTags(ctx, task, b)
+
+	// Store task ID and sync timestamp in sync store
+	s.syncStore.SetTaskID(b.ID, task.ID)
+	s.syncStore.SetSyncedAt(b.ID, time.Now().UTC())
+	s.storeTaskURL(b.ID, task.URL)
+
+	result.Action = "created"
+	return result
+}
+
+// updateStatus pushes just the bean's status to its linked task, without
+// a full update or recording the sync.
+func (s *Syncer) updateStatus(ctx context.Context, updater StatusUpdater, b *beans.Bean, result Result) Result {
+	if s.opts.DryRun {
+		result.Action = "would update"
+		return result
+	}
+	task, changed, err := updater.UpdateStatus(ctx, result.TaskID, b)
+	if err != nil {
+		result.Action = "error"
+		result.Error = fmt.Errorf("updating status: %w", err)
+		return result
+	}
+	if task != nil {
+		result.TaskURL = task.URL
+	}
+	if changed {
+		result.Action = "updated"
+		result.FieldsChanged = []string{"status"}
+	} else {
+		result.Action = "unchanged"
+	}
+	return result
+}
+
+// updateTask updates the task for b through the sink, recording the
+// changed fields in result if the sink reports them.
+func (s *Syncer) updateTask(ctx context.Context, task *TaskRef, b *beans.Bean, result *Result) (*TaskRef, bool, error) {
+	reporter, ok := s.sink.(FieldReporter)
+	if !ok {
+		return s.sink.UpdateTask(ctx, task, b)
+	}
+	updated, fields, err := reporter.UpdateTaskFields(ctx, task, b)
+	result.FieldsChanged = fields
+	return updated, len(fields) > 0, err
+}
+
+// syncReason says why a bean needs to be synced, or "" if it doesn't: it
+// was never synced, it changed since the last sync, or its prefetched task
+// shows a remote edit (see RemoteDrift). conflict is set if both changed.
+func (s *Syncer) syncReason(b *beans.Bean) (reason string, conflict bool) {
+	syncedAt := s.syncStore.GetSyncedAt(b.ID)
+	if syncedAt == nil {
+		return ReasonNew, false
+	}
+	if b.UpdatedAt != nil && b.UpdatedAt.After(*syncedAt) {
+		reason = ReasonBeanChanged
+	}
+	lister, _ := s.sink.(TaskLister)
+	drift := storedDrift(lister, s.remote, s.syncStore, b, *syncedAt)
+	switch {
+	case drift == "":
+		return reason, false
+	case reason != "":
+		return reason, true
+	}
+	return driftReasons[drift], false
+}
+
+// storedDrift is RemoteDrift for the bean's task among remote, with the
+// task IDs it blocks looked up in store. It returns "" if the task wasn't
+// listed.
+func storedDrift(lister TaskLister, remote map[string]*TaskRef, store StateProvider, b *beans.Bean, syncedAt time.Time) string {
+	taskID := store.GetTaskID(b.ID)
+	if remote == nil || lister == nil || taskID == nil {
+		return ""
+	}
+	task, ok := remote[*taskID]
+	if !ok {
+		return ""
+	}
+	var blocking []string
+	for _, blockedID := range b.Blocking {
+		if id := store.GetTaskID(blockedID); id != nil && *id != "" {
+			blocking = append(blocking, *id)
+		}
+	}
+	return RemoteDrift(lister, task, b, syncedAt, blocking)
+}
+
+// TaskClockSkew is how far a task's update time may trail past synced_at
+// without counting as a remote edit. Sync writes the task before recording
+// synced_at, and the tracker's clock is not ours.
+const TaskClockSkew = 5 * time.Second
+
+// Remote drift reported by RemoteDrift.
+const (
+	DriftEdited        = "edited"
+	DriftTags          = "tags"
+	DriftRelationships = "relationships"
+)
+
+// RemoteDrift reports how a linked task was changed remotely since it was
+// synced at syncedAt, judging by the task alone: DriftEdited if it was
+// updated afterwards, DriftTags if its managed tags don't match the bean's,
+// or DriftRelationships if it no longer blocks one of blocking, the task
+// IDs of the beans b blocks. It returns "" if none of those show.
+func RemoteDrift(lister TaskLister, task *TaskRef, b *beans.Bean, syncedAt time.Time, blocking []string) string {
+	if task.UpdatedAt != nil && task.UpdatedAt.After(syncedAt.Add(TaskClockSkew)) {
+		return DriftEdited
+	}
+	if taskTags, beanTags := lister.TagFingerprints(task, b); taskTags != beanTags {
+		return DriftTags
+	}
+	// Blocking is only known for sinks that report it
+	if task.Blocking != nil {
+		for _, id := range blocking {
+			if !slices.Contains(task.Blocking, id) {
+				return DriftRelationships
+			}
+		}
+	}
+	return ""
+}
+
+// validateBean checks the bean's values with a BeanValidator sink.
+func (s *Syncer) validateBean(ctx context.Context, b *beans.Bean) error {
+	validator, ok := s.sink.(BeanValidator)
+	if !ok {
+		return nil
+	}
+	if err := validator.ValidateBean(ctx, b); err != nil {
+		return fmt.Errorf("invalid bean: %w", err)
+	}
+	return nil
+}
+
+// syncRelationships syncs parent (for ParentLinker sinks), reference (for
+// ReferenceLinker sinks), and blocking relationships for a bean. It
+// returns how many it set and why any failed; a full sync ignores
+// failures, as relationships are best-effort there. In a dry run nothing
+// is set, only counted.
+func (s *Syncer) syncRelationships(ctx context.Context, b *beans.Bean) (int, error) {
+	taskID, ok := s.beanToTaskID[b.ID]
+	if !ok {
+		return 0, nil // Bean not synced
+	}
+
+	var linked int
+	var errs []error
+	if linker, ok := s.sink.(ParentLinker); ok && b.Parent != "" {
+		if parentTaskID, ok := s.beanToTaskID[b.Parent]; ok {
+			linked++
+			if !s.opts.DryRun {
+				if err := linker.SetParent(ctx, parentTaskID, taskID); err != nil {
+					errs = append(errs, fmt.Errorf("linking parent %s: %w", b.Parent, err))
+				}
+			}
+		}
+	}
+
+	if linker, ok := s.sink.(ReferenceLinker); ok {
+		taskIDs := make(map[string]string)
+		for _, refID := range linker.References(ctx, b) {
+			if refTaskID, ok := s.beanToTaskID[refID]; ok {
+				taskIDs[refID] = refTaskID
+			}
+		}
+		if len(taskIDs) > 0 {
+			linked++
+			if !s.opts.DryRun {
+				if err := linker.SetReferences(ctx, taskID, b, taskIDs); err != nil {
+					errs = append(errs, fmt.Errorf("linking references: %w", err))
+				}
+			}
+		}
+	}
+
+	// In beans: bean A with blocking: [B, C] means A is blocking B and C
+	for _, blockedID := range b.Blocking {
+		blockedTaskID, ok := s.beanToTaskID[blockedID]
+		if !ok {
+			continue // Blocked bean not synced
+		}
+
+		linked++
+		if !s.opts.DryRun {
+			if err := s.sink.SetRelationship(ctx, taskID, blockedTaskID); err != nil {
+				// Relationships might fail if they already exist
+				errs = append(errs, fmt.Errorf("linking blocked %s: %w", blockedID, err))
+			}
+		}
+	}
+	return linked, errors.Join(errs...)
+}
+
+// linkRelationships syncs just the relationships of each linked bean, for
+// Options.RelationshipsOnly. Beans with relationships to set are reported
+// as updated (or would update), the rest as unchanged.
+func (s *Syncer) linkRelationships(ctx context.Context, beanList []beans.Bean) []Result {
+	ctx, span := tracing.Start(ctx, "sync.relationships")
+	defer span.End()
+
+	results := make([]Result, len(beanList))
+	var wg sync.WaitGroup
+	var mu sync.Mutex // protects completed
+	var completed int
+	for i, bean := range beanList {
+		wg.Go(func() {
+			result := Result{BeanID: bean.ID, BeanTitle: bean.Title, TaskID: s.beanToTaskID[bean.ID]}
+			if store, ok := s.syncStore.(TaskURLStore); ok {
+				result.TaskURL = store.GetTaskURL(bean.ID)
+			}
+			linked, err := s.syncRelationships(ctx, &bean)
+			switch {
+			case result.TaskID == "":
+				result.Action = "skipped"
+				result.Reason = ReasonNotLinked
+			case err != nil && ctx.Err() != nil:
+				result.Action = "cancelled"
+				result.Error = ctx.Err()
+			case err != nil:
+				result.Action = "error"
+				result.Error = err
+			case linked == 0:
+				result.Action = "unchanged"
+			case s.opts.DryRun:
+				result.Action = "would update"
+				result.FieldsChanged = []string{"relationships"}
+			default:
+				result.Action = "updated"
+				result.FieldsChanged = []string{"relationships"}
+			}
+			results[i] = result
+
+			if s.opts.OnProgress != nil {
+				mu.Lock()
+				completed++
+				current := completed
+				mu.Unlock()
+				s.opts.OnProgress(result, current, len(beanList))
+			}
+		})
+	}
+	wg.Wait()
+	return results
+}
+
+// unreachableResult fails a bean without contacting the sink, after an
+// earlier bean found it unreachable.
+func (s *Syncer) unreachableResult(result Result) Result {
+	result.Action = "error"
+	result.Error = fmt.Errorf("%s: %w", s.sink.Name(), ErrUnreachable)
+	return result
+}
+
+// storeTaskURL records a task's URL when Options.StoreTaskURL is set and
+// it differs from the stored one, e.g. after the task moved lists.
+func (s *Syncer) storeTaskURL(beanID, url string) {
+	store, ok := s.syncStore.(TaskURLStore)
+	if !s.opts.StoreTaskURL || !ok || url == "" || store.GetTaskURL(beanID) == url {
+		return
+	}
+	store.SetTaskURL(beanID, url)
+}
+
+// FilterBeansNeedingSync returns only beans that need to be synced based on timestamps.
+// A bean needs sync if: force is true, it has no sync record, or it was updated after last sync.
+func FilterBeansNeedingSync(beanList []beans.Bean, store StateProvider, force bool) []beans.Bean {
+	var needSync []beans.Bean
+	for _, b := range beanList {
+		if force {
+			needSync = append(needSync, b)
+			continue
+		}
+		syncedAt := store.GetSyncedAt(b.ID)
+		if syncedAt == nil {
+			needSync = append(needSync, b) // Never synced
+			continue
+		}
+		if b.UpdatedAt != nil && b.UpdatedAt.After(*syncedAt) {
+			needSync = append(needSync, b) // Updated since last sync
+		}
+	}
+	return needSync
+}
+
+// FilterBeansNeedingRemoteSync is FilterBeansNeedingSync that also keeps
+// linked beans whose task was changed remotely (see RemoteDrift), for a
+// sink that is a TaskLister. It returns the listed tasks for
+// Options.RemoteTasks, or nil if the sink can't list them or listing
+// failed, in which case only timestamps are compared.
+func FilterBeansNeedingRemoteSync(ctx context.Context, sink Sink, beanList []beans.Bean, store StateProvider, force bool) ([]beans.Bean, map[string]*TaskRef) {
+	needSync := FilterBeansNeedingSync(beanList, store, force)
+	lister, ok := sink.(TaskLister)
+	linked := slices.ContainsFunc(beanList, func(b beans.Bean) bool {
+		id := store.GetTaskID(b.ID)
+		return id != nil && *id != ""
+	})
+	if !ok || force || !linked || len(needSync) == len(beanList) {
+		return needSync, nil
+	}
+	remote, err := lister.ListTasks(ctx)
+	if err != nil {
+		return needSync, nil
+	}
+	needSync = needSync[:0:0]
+	for _, b := range beanList {
+		syncedAt := store.GetSyncedAt(b.ID)
+		switch {
+		case syncedAt == nil, b.UpdatedAt != nil && b.UpdatedAt.After(*syncedAt):
+			needSync = append(needSync, b)
+		case storedDrift(lister, remote, store, &b, *syncedAt) != "":
+			needSync = append(needSync, b) // Changed remotely
+		}
+	}
+	return needSync, remote
+}
+
+// FilterBeansForSync filters beans based on sync filter configuration.
+func FilterBeansForSync(beanList []beans.Bean, filter *config.SyncFilter) []beans.Bean {
+	if filter == nil {
+		return beanList
+	}
+
+	excludeStatus := make(map[string]bool)
+	for _, s := range filter.ExcludeStatus {
+		excludeStatus[s] = true
+	}
+
+	var filtered []beans.Bean
+	for _, b := range beanList {
+		if !excludeStatus[b.Status] {
+			filtered = append(filtered, b)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,505 @@
+package syncer_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/config"
+	"github.com/toba/bean-me-up/internal/syncer"
+	"github.com/toba/bean-me-up/internal/syncertest"
+)
+
+// fakeSink records calls and stores tasks in memory.
+type fakeSink struct {
+	mu        sync.Mutex
+	tasks     map[string]*beans.Bean
+	parents   map[string]string // task ID -> parent task ID
+	relations []string          // "blocker->blocked"
+	nextID    int
+}
+
+func newFakeSink() *fakeSink {
+	return &fakeSink{tasks: map[string]*beans.Bean{}, parents: map[string]string{}}
+}
+
+func (f *fakeSink) Name() string                      { return "fake" }
+func (f *fakeSink) Prepare(ctx context.Context) error { return nil }
+
+func (f *fakeSink) GetTask(ctx context.Context, taskID string) (*syncer.TaskRef, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.tasks[taskID]; !ok {
+		return nil, fmt.Errorf("fake: %w", syncer.ErrTaskNotFound)
+	}
+	return &syncer.TaskRef{ID: taskID, URL: "fake://" + taskID}, nil
+}
+
+func (f *fakeSink) CreateTask(ctx context.Context, b *beans.Bean, parentTaskID string) (*syncer.TaskRef, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	id := fmt.Sprintf("t%d", f.nextID)
+	f.tasks[id] = b
+	f.parents[id] = parentTaskID
+	return &syncer.TaskRef{ID: id, URL: "fake://" + id}, nil
+}
+
+func (f *fakeSink) UpdateTask(ctx context.Context, current *syncer.TaskRef, b *beans.Bean) (*syncer.TaskRef, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	changed := f.tasks[current.ID].Title != b.Title
+	f.tasks[current.ID] = b
+	return current, changed, nil
+}
+
+func (f *fakeSink) SyncTags(ctx context.Context, task *syncer.TaskRef, b *beans.Bean) bool {
+	return false
+}
+
+func (f *fakeSink) SetRelationship(ctx context.Context, blockerTaskID, blockedTaskID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.relations = append(f.relations, blockerTaskID+"->"+blockedTaskID)
+	return nil
+}
+
+func TestSyncBeans_ParentsBeforeChildrenAndRelationships(t *testing.T) {
+	sink := newFakeSink()
+	state := syncertest.NewState()
+
+	beanList := []beans.Bean{
+		{ID: "child", Title: "Child", Parent: "epic"},
+		{ID: "epic", Title: "Epic", Blocking: []string{"other"}},
+		{ID: "other", Title: "Other"},
+	}
+
+	results, err := syncer.New(sink, syncer.Options{}, state).SyncBeans(context.Background(), beanList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.Action != "created" {
+			t.Errorf("%s: action = %q, want created (err %v)", r.BeanID, r.Action, r.Error)
+		}
+	}
+
+	epicTask := *state.GetTaskID("epic")
+	childTask := *state.GetTaskID("child")
+	if sink.parents[childTask] != epicTask {
+		t.Errorf("child created under %q, want %q", sink.parents[childTask], epicTask)
+	}
+
+	want := epicTask + "->" + *state.GetTaskID("other")
+	if len(sink.relations) != 1 || sink.relations[0] != want {
+		t.Errorf("relations = %v, want [%s]", sink.relations, want)
+	}
+}
+
+func TestSyncBeans_RelationshipsOnly(t *testing.T) {
+	sink := newFakeSink()
+	sink.tasks["t1"] = &beans.Bean{Title: "Blocker"}
+	sink.tasks["t2"] = &beans.Bean{Title: "Blocked"}
+	state := syncertest.NewState()
+	state.SetTaskID("blocker", "t1")
+	state.SetTaskID("blocked", "t2")
+
+	beanList := []beans.Bean{
+		{ID: "blocker", Title: "Renamed", Blocking: []string{"blocked", "new"}},
+		{ID: "blocked", Title: "Blocked"},
+		{ID: "new", Title: "New"},
+	}
+
+	results, err := syncer.New(sink, syncer.Options{RelationshipsOnly: true, DryRun: true}, state).SyncBeans(context.Background(), beanList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Action != "would update" || len(sink.relations) != 0 {
+		t.Errorf("dry run: %s, relations %v", results[0].Action, sink.relations)
+	}
+
+	results, err = syncer.New(sink, syncer.Options{RelationshipsOnly: true}, state).SyncBeans(context.Background(), beanList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{"updated", "unchanged", "skipped"} {
+		if results[i].Action != want {
+			t.Errorf("%s: action = %q, want %q", results[i].BeanID, results[i].Action, want)
+		}
+	}
+	if !slices.Equal(sink.relations, []string{"t1->t2"}) {
+		t.Errorf("relations = %v, want only the linked pair", sink.relations)
+	}
+	if sink.tasks["t1"].Title != "Blocker" || len(sink.tasks) != 2 || state.GetSyncedAt("blocker") != nil {
+		t.Error("relationships-only sync changed tasks or sync state")
+	}
+}
+
+// referencingSink links each bean's parent as a reference.
+type referencingSink struct {
+	*fakeSink
+	refs map[string]map[string]string // task ID -> bean ID -> task ID
+}
+
+func (r *referencingSink) References(ctx context.Context, b *beans.Bean) []string {
+	if b.Parent == "" {
+		return nil
+	}
+	return []string{b.Parent}
+}
+
+func (r *referencingSink) SetReferences(ctx context.Context, taskID string, b *beans.Bean, taskIDs map[string]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.refs[taskID] = taskIDs
+	return nil
+}
+
+func TestSyncBeans_References(t *testing.T) {
+	sink := &referencingSink{fakeSink: newFakeSink(), refs: map[string]map[string]string{}}
+	state := syncertest.NewState()
+
+	beanList := []beans.Bean{
+		{ID: "child", Title: "Child", Parent: "epic"},
+		{ID: "epic", Title: "Epic"},
+		{ID: "orphan", Title: "Orphan", Parent: "unsynced"},
+	}
+	if _, err := syncer.New(sink, syncer.Options{}, state).SyncBeans(context.Background(), beanList); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only references to synced beans are set
+	childTask, epicTask := *state.GetTaskID("child"), *state.GetTaskID("epic")
+	if len(sink.refs) != 1 || sink.refs[childTask]["epic"] != epicTask {
+		t.Errorf("references = %v, want %s -> epic %s", sink.refs, childTask, epicTask)
+	}
+}
+
+// validatingSink rejects beans titled "bad".
+type validatingSink struct{ *fakeSink }
+
+func (v validatingSink) ValidateBean(ctx context.Context, b *beans.Bean) error {
+	if b.Title == "bad" {
+		return errors.New("bad title")
+	}
+	return nil
+}
+
+func TestSyncBeans_ValidateBean(t *testing.T) {
+	sink := validatingSink{newFakeSink()}
+	state := syncertest.NewState()
+	beanList := []beans.Bean{{ID: "good", Title: "good"}, {ID: "bad", Title: "bad"}}
+
+	// The plan shows the rejection, before anything is sent
+	results, err := syncer.New(sink, syncer.Options{DryRun: true}, state).SyncBeans(context.Background(), beanList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Action != "would create" || results[1].Action != "error" || !strings.Contains(results[1].Error.Error(), "invalid bean: bad title") {
+		t.Errorf("dry run = %s, %s (%v)", results[0].Action, results[1].Action, results[1].Error)
+	}
+
+	results, _ = syncer.New(sink, syncer.Options{}, state).SyncBeans(context.Background(), beanList)
+	if results[1].Action != "error" || len(sink.tasks) != 1 {
+		t.Errorf("sync = %s with %d tasks, want the bad bean rejected", results[1].Action, len(sink.tasks))
+	}
+}
+
+func TestSyncBeans_DeepHierarchy(t *testing.T) {
+	sink := newFakeSink()
+	state := syncertest.NewState()
+
+	// Listed deepest first, so a fixed number of passes would get it wrong
+	beanList := []beans.Bean{
+		{ID: "subtask", Title: "Subtask", Parent: "task"},
+		{ID: "task", Title: "Task", Parent: "feature"},
+		{ID: "feature", Title: "Feature", Parent: "epic"},
+		{ID: "epic", Title: "Epic", Parent: "milestone"}, // milestone isn't synced
+	}
+	if _, err := syncer.New(sink, syncer.Options{}, state).SyncBeans(context.Background(), beanList); err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range beanList[:3] {
+		task, parent := *state.GetTaskID(b.ID), *state.GetTaskID(b.Parent)
+		if sink.parents[task] != parent {
+			t.Errorf("%s created under %q, want %s's task %q", b.ID, sink.parents[task], b.Parent, parent)
+		}
+	}
+}
+
+func TestSyncBeans_RecreatesDeletedAndSkipsUnchanged(t *testing.T) {
+	sink := newFakeSink()
+	state := syncertest.NewState()
+
+	past := time.Now().Add(-time.Hour)
+	state.SetTaskID("gone", "deleted-task")
+	state.SetTaskID("same", "t-same")
+	state.SetSyncedAt("same", time.Now())
+	sink.tasks["t-same"] = &beans.Bean{ID: "same"}
+
+	beanList := []beans.Bean{
+		{ID: "gone", Title: "Gone", UpdatedAt: &past},
+		{ID: "same", Title: "Same", UpdatedAt: &past},
+	}
+
+	results, err := syncer.New(sink, syncer.Options{NoRelationships: true}, state).SyncBeans(context.Background(), beanList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Action != "created" || *state.GetTaskID("gone") == "deleted-task" {
+		t.Errorf("deleted task: action %q, task %q; want recreated", results[0].Action, *state.GetTaskID("gone"))
+	}
+	if results[1].Action != "skipped" {
+		t.Errorf("unchanged bean: action %q, want skipped", results[1].Action)
+	}
+	if results[0].Reason != syncer.ReasonTaskDeleted || results[1].Reason != syncer.ReasonUpToDate {
+		t.Errorf("reasons = %q, %q; want %q, %q", results[0].Reason, results[1].Reason, syncer.ReasonTaskDeleted, syncer.ReasonUpToDate)
+	}
+}
+
+// listingSink lists prefetched task snapshots, comparing tags verbatim.
+type listingSink struct {
+	*fakeSink
+	remote map[string]*syncer.TaskRef
+}
+
+func (l *listingSink) ListTasks(ctx context.Context) (map[string]*syncer.TaskRef, error) {
+	return l.remote, nil
+}
+
+func (l *listingSink) TagFingerprints(task *syncer.TaskRef, b *beans.Bean) (string, string) {
+	return strings.Join(task.Tags, ","), strings.Join(b.Tags, ",")
+}
+
+func TestSyncBeans_RemoteDrift(t *testing.T) {
+	synced := time.Now().Add(-time.Hour)
+	before, after := synced.Add(-time.Hour), synced.Add(time.Minute)
+	sink := &listingSink{fakeSink: newFakeSink(), remote: map[string]*syncer.TaskRef{
+		"t-same":     {ID: "t-same", UpdatedAt: &before, Tags: []string{"ui"}, Blocking: []string{"t-edited"}},
+		"t-edited":   {ID: "t-edited", UpdatedAt: &after},
+		"t-tagged":   {ID: "t-tagged", UpdatedAt: &before, Tags: []string{"ui", "extra"}},
+		"t-unlinked": {ID: "t-unlinked", UpdatedAt: &before, Blocking: []string{}},
+	}}
+	state := syncertest.NewState()
+	var beanList []beans.Bean
+	for _, id := range []string{"same", "edited", "tagged", "unlinked"} {
+		state.SetTaskID(id, "t-"+id)
+		state.SetSyncedAt(id, synced)
+		sink.tasks["t-"+id] = &beans.Bean{ID: id}
+		beanList = append(beanList, beans.Bean{ID: id, Title: id, UpdatedAt: &before})
+	}
+	beanList[0].Tags, beanList[0].Blocking = []string{"ui"}, []string{"edited"}
+	beanList[2].Tags = []string{"ui"}
+	beanList[3].Blocking = []string{"same"} // the blocked task lost the relationship
+
+	results, err := syncer.New(sink, syncer.Options{NoRelationships: true}, state).SyncBeans(context.Background(), beanList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"skipped", "updated", "updated", "updated"}
+	wantReasons := []string{syncer.ReasonUpToDate, syncer.ReasonTaskEdited, syncer.ReasonTaskTags, syncer.ReasonTaskRelationships}
+	for i, r := range results {
+		if r.Action != want[i] || r.Reason != wantReasons[i] || r.Conflict {
+			t.Errorf("%s: action %q reason %q conflict %v, want %q %q", r.BeanID, r.Action, r.Reason, r.Conflict, want[i], wantReasons[i])
+		}
+	}
+
+	drift := syncer.RemoteDrift(sink, sink.remote["t-tagged"], &beanList[2], synced, nil)
+	if drift != syncer.DriftTags {
+		t.Errorf("RemoteDrift() = %q, want %q", drift, syncer.DriftTags)
+	}
+}
+
+// reportingSink reports the fields it changed, and retries each update once.
+type reportingSink struct {
+	*listingSink
+}
+
+func (r *reportingSink) UpdateTaskFields(ctx context.Context, current *syncer.TaskRef, b *beans.Bean) (*syncer.TaskRef, []string, error) {
+	syncer.NoteRetry(ctx)
+	ref, changed, err := r.UpdateTask(ctx, current, b)
+	if !changed {
+		return ref, nil, err
+	}
+	return ref, []string{"name"}, err
+}
+
+func TestSyncBeans_ResultDetail(t *testing.T) {
+	synced := time.Now().Add(-time.Hour)
+	before, after := synced.Add(-time.Hour), synced.Add(time.Minute)
+	sink := &reportingSink{&listingSink{fakeSink: newFakeSink(), remote: map[string]*syncer.TaskRef{
+		"t-both": {ID: "t-both", UpdatedAt: &after},
+	}}}
+	state := syncertest.NewState()
+	state.SetTaskID("both", "t-both")
+	state.SetSyncedAt("both", synced)
+	sink.tasks["t-both"] = &beans.Bean{ID: "both", Title: "Old"}
+	beanList := []beans.Bean{
+		{ID: "both", Title: "New", UpdatedAt: &after},
+		{ID: "fresh", Title: "Fresh", UpdatedAt: &before},
+	}
+
+	results, err := syncer.New(sink, syncer.Options{NoRelationships: true}, state).SyncBeans(context.Background(), beanList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	both, fresh := results[0], results[1]
+	if both.Action != "updated" || both.Reason != syncer.ReasonBeanChanged || !both.Conflict || !both.Retried {
+		t.Errorf("both changed: %+v, want updated, %q, conflict, retried", both, syncer.ReasonBeanChanged)
+	}
+	if !slices.Equal(both.FieldsChanged, []string{"name"}) {
+		t.Errorf("fields changed = %v, want [name]", both.FieldsChanged)
+	}
+	if fresh.Action != "created" || fresh.Reason != syncer.ReasonNew || fresh.Conflict || fresh.Retried {
+		t.Errorf("new bean: %+v, want created, %q", fresh, syncer.ReasonNew)
+	}
+}
+
+func TestSyncBeans_DryRun(t *testing.T) {
+	sink := newFakeSink()
+	state := syncertest.NewState()
+	sink.tasks["t1"] = &beans.Bean{ID: "linked"}
+	state.SetTaskID("linked", "t1")
+
+	results, err := syncer.New(sink, syncer.Options{DryRun: true}, state).SyncBeans(context.Background(), []beans.Bean{
+		{ID: "linked", Title: "Linked"},
+		{ID: "new", Title: "New"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Action != "would update" || results[1].Action != "would create" {
+		t.Errorf("actions = %q, %q", results[0].Action, results[1].Action)
+	}
+	if len(sink.tasks) != 1 {
+		t.Errorf("dry run created tasks: %v", sink.tasks)
+	}
+}
+
+func TestSyncBeans_StoreTaskURL(t *testing.T) {
+	sink := newFakeSink()
+	state := syncertest.NewState()
+
+	// A linked task whose stored URL is stale, as after a move
+	sink.tasks["t-moved"] = &beans.Bean{ID: "moved"}
+	state.SetTaskID("moved", "t-moved")
+	state.SetTaskURL("moved", "fake://old-list/t-moved")
+
+	beanList := []beans.Bean{{ID: "new", Title: "New"}, {ID: "moved", Title: "Moved"}}
+	if _, err := syncer.New(sink, syncer.Options{NoRelationships: true}, state).SyncBeans(context.Background(), beanList); err != nil {
+		t.Fatal(err)
+	}
+	if url := state.GetTaskURL("new"); url != "" {
+		t.Errorf("URL %q stored without StoreTaskURL", url)
+	}
+
+	if _, err := syncer.New(sink, syncer.Options{NoRelationships: true, StoreTaskURL: true, Force: true}, state).SyncBeans(context.Background(), beanList); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"new": "fake://" + *state.GetTaskID("new"), "moved": "fake://t-moved"}
+	for id, url := range want {
+		if got := state.GetTaskURL(id); got != url {
+			t.Errorf("%s: URL %q, want %q", id, got, url)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	syncer.Register("fake-registry-test", func(cfg *config.Config) (syncer.Sink, error) { return newFakeSink(), nil })
+
+	s, err := syncer.NewSink("fake-registry-test", &config.Config{})
+	if err != nil || s.Name() != "fake" {
+		t.Fatalf("NewSink() = %v, %v", s, err)
+	}
+	if _, err := syncer.NewSink("nope", &config.Config{}); err == nil {
+		t.Error("expected error for unknown sink")
+	}
+}
+
+// offlineSink fails every call as if the network were down.
+type offlineSink struct {
+	fakeSink
+	calls int
+}
+
+func (o *offlineSink) CreateTask(ctx context.Context, b *beans.Bean, parentTaskID string) (*syncer.TaskRef, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.calls++
+	return nil, fmt.Errorf("executing request: %w", &net.OpError{Op: "dial", Err: errors.New("no route to host")})
+}
+
+func TestSyncBeans_UnreachableFailsFast(t *testing.T) {
+	sink := &offlineSink{fakeSink: *newFakeSink()}
+	beanList := []beans.Bean{{ID: "a", Title: "A"}, {ID: "b", Title: "B", Parent: "a"}}
+
+	results, err := syncer.New(sink, syncer.Options{}, syncertest.NewState()).SyncBeans(context.Background(), beanList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if !syncer.IsUnreachable(r.Error) {
+			t.Errorf("%s: error %v, want unreachable", r.BeanID, r.Error)
+		}
+	}
+	if sink.calls != 1 {
+		t.Errorf("sink called %d times, want 1 before failing fast", sink.calls)
+	}
+}
+
+// cancellingSink cancels the sync after creating its first task, like a
+// Ctrl-C arriving mid-sync.
+type cancellingSink struct {
+	fakeSink
+	cancel context.CancelFunc
+}
+
+func (c *cancellingSink) CreateTask(ctx context.Context, b *beans.Bean, parentTaskID string) (*syncer.TaskRef, error) {
+	defer c.cancel()
+	return c.fakeSink.CreateTask(ctx, b, parentTaskID)
+}
+
+func TestSyncBeans_CancelledKeepsCompleted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sink := &cancellingSink{fakeSink: *newFakeSink(), cancel: cancel}
+	state := syncertest.NewState()
+	beanList := []beans.Bean{
+		{ID: "a", Title: "A", Blocking: []string{"b"}},
+		{ID: "b", Title: "B", Parent: "a"},
+		{ID: "c", Title: "C", Parent: "b"},
+	}
+
+	results, err := syncer.New(sink, syncer.Options{}, state).SyncBeans(ctx, beanList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{"created", "cancelled", "cancelled"} {
+		if results[i].Action != want {
+			t.Errorf("%s: action = %q, want %q", results[i].BeanID, results[i].Action, want)
+		}
+	}
+	if state.GetTaskID("a") == nil || state.GetSyncedAt("a") == nil {
+		t.Error("the bean synced before the cancel wasn't recorded")
+	}
+	if len(sink.tasks) != 1 || len(sink.relations) != 0 {
+		t.Errorf("sink was called after the cancel: tasks %v, relations %v", sink.tasks, sink.relations)
+	}
+}
+
+func TestIsUnreachable(t *testing.T) {
+	if syncer.IsUnreachable(nil) || syncer.IsUnreachable(errors.New("HTTP 400")) || syncer.IsUnreachable(fmt.Errorf("x: %w", context.Canceled)) {
+		t.Error("IsUnreachable() true for a reachable backend")
+	}
+	if !syncer.IsUnreachable(fmt.Errorf("x: %w", &url.Error{Op: "Get", URL: "https://api", Err: errors.New("no such host")})) {
+		t.Error("IsUnreachable() false for a url.Error")
+	}
+}
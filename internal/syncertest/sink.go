@@ -0,0 +1,96 @@
+package syncertest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+// SinkFixture is a sink backed by a fresh fake server.
+type SinkFixture struct {
+	Sink syncer.Sink
+	// DeletedTaskID names a task the server reports as deleted or archived.
+	DeletedTaskID string
+}
+
+// Serve starts h on a test server that closes when the test ends, and
+// returns its URL.
+func Serve(t *testing.T, h http.Handler) string {
+	t.Helper()
+	server := httptest.NewServer(h)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// Sync syncs beanList to sink, failing the test if the sync can't run.
+func Sync(t *testing.T, sink syncer.Sink, opts syncer.Options, state *State, beanList []beans.Bean) []syncer.Result {
+	t.Helper()
+	results, err := syncer.New(sink, opts, state).SyncBeans(context.Background(), beanList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return results
+}
+
+// RequireActions fails the test unless every result has the given action.
+func RequireActions(t *testing.T, results []syncer.Result, action string) {
+	t.Helper()
+	for _, r := range results {
+		if r.Action != action {
+			t.Fatalf("%s: action %q, want %q (err %v)", r.BeanID, r.Action, action, r.Error)
+		}
+	}
+}
+
+// RunSinkTests checks the behavior every sink shares. newFixture is called
+// once per subtest.
+func RunSinkTests(t *testing.T, newFixture func(t *testing.T) SinkFixture) {
+	t.Run("SyncCreatesTasks", func(t *testing.T) {
+		f := newFixture(t)
+		state := NewState()
+		beanList := []beans.Bean{
+			{ID: "epic", Title: "Epic", Type: "epic", Status: "in-progress"},
+			{ID: "fix", Title: "Fix crash", Type: "bug", Priority: "high", Status: "todo", Parent: "epic", Tags: []string{"ui"}, Blocking: []string{"done"}},
+			{ID: "done", Title: "Shipped", Type: "task", Status: "completed"},
+		}
+
+		results := Sync(t, f.Sink, syncer.Options{}, state, beanList)
+		RequireActions(t, results, "created")
+		for _, r := range results {
+			id := state.GetTaskID(r.BeanID)
+			if id == nil || r.TaskURL == "" {
+				t.Fatalf("%s: task %v URL %q; want both recorded", r.BeanID, id, r.TaskURL)
+			}
+			task, err := f.Sink.GetTask(context.Background(), *id)
+			if err != nil || task.ID != *id {
+				t.Errorf("%s: GetTask(%q) = %+v, %v", r.BeanID, *id, task, err)
+			}
+		}
+
+		// A second sync finds nothing to change
+		results = Sync(t, f.Sink, syncer.Options{Force: true}, state, beanList)
+		for _, r := range results {
+			if r.Action != "unchanged" {
+				t.Errorf("%s: second sync action %q (err %v)", r.BeanID, r.Action, r.Error)
+			}
+		}
+	})
+
+	t.Run("DeletedTaskIsRecreated", func(t *testing.T) {
+		f := newFixture(t)
+		state := NewState()
+		state.SetTaskID("gone", f.DeletedTaskID)
+
+		results := Sync(t, f.Sink, syncer.Options{Force: true}, state, []beans.Bean{{ID: "gone", Title: "Gone"}})
+		if results[0].Action != "created" || *state.GetTaskID("gone") == f.DeletedTaskID {
+			t.Errorf("action %q task %q; want recreated", results[0].Action, *state.GetTaskID("gone"))
+		}
+		if results[0].TaskURL == "" {
+			t.Error("recreated task has no URL")
+		}
+	})
+}
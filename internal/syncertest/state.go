@@ -0,0 +1,73 @@
+// Package syncertest provides fakes and shared tests for sync backends.
+package syncertest
+
+import (
+	"sync"
+	"time"
+)
+
+// State is an in-memory syncer.StateProvider and syncer.TaskURLStore.
+type State struct {
+	mu       sync.Mutex
+	taskIDs  map[string]string
+	taskURLs map[string]string
+	syncedAt map[string]time.Time
+}
+
+// NewState returns an empty State.
+func NewState() *State {
+	return &State{taskIDs: map[string]string{}, taskURLs: map[string]string{}, syncedAt: map[string]time.Time{}}
+}
+
+func (s *State) GetTaskID(beanID string) *string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id, ok := s.taskIDs[beanID]; ok {
+		return &id
+	}
+	return nil
+}
+
+func (s *State) GetSyncedAt(beanID string) *time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, ok := s.syncedAt[beanID]; ok {
+		return &t
+	}
+	return nil
+}
+
+func (s *State) SetTaskID(beanID, taskID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.taskIDs[beanID] = taskID
+}
+
+func (s *State) GetTaskURL(beanID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.taskURLs[beanID]
+}
+
+func (s *State) SetTaskURL(beanID, url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.taskURLs[beanID] = url
+}
+
+func (s *State) SetSyncedAt(beanID string, t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.syncedAt[beanID] = t
+}
+
+func (s *State) Clear(beanID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.taskIDs, beanID)
+	delete(s.taskURLs, beanID)
+	delete(s.syncedAt, beanID)
+}
+
+// Flush does nothing; the state is only in memory.
+func (s *State) Flush() error { return nil }
@@ -0,0 +1,139 @@
+// Package tombstone remembers the tasks of beans that were deleted, so sync
+// can close, archive, or delete them in their backend.
+package tombstone
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// FileName is the store created inside the beans directory.
+const FileName = ".beanup-tombstones.json"
+
+// Tombstone is the task of a deleted bean in one backend.
+type Tombstone struct {
+	Sink    string    `json:"sink"`
+	BeanID  string    `json:"bean_id"`
+	TaskID  string    `json:"task_id"`
+	Deleted time.Time `json:"deleted"`
+	// Action is what was done to the task (close, archive, or delete);
+	// empty until it has been handled.
+	Action  string     `json:"action,omitempty"`
+	Handled *time.Time `json:"handled,omitempty"`
+}
+
+// Store holds the links seen by earlier syncs and the tombstones of beans
+// that have since disappeared.
+type Store struct {
+	// Links maps sink name to bean ID to task ID.
+	Links      map[string]map[string]string `json:"links,omitempty"`
+	Tombstones []Tombstone                  `json:"tombstones,omitempty"`
+}
+
+// Load reads the store at path. A missing store is empty.
+func Load(path string) (*Store, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Store{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading tombstones: %w", err)
+	}
+	var s Store
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Save replaces the store at path atomically.
+func (s *Store) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding tombstones: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), FileName+".*")
+	if err != nil {
+		return fmt.Errorf("writing tombstones: %w", err)
+	}
+	_, werr := tmp.Write(append(data, '\n'))
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("writing tombstones: %w", errors.Join(werr, cerr))
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("writing tombstones: %w", err)
+	}
+	return nil
+}
+
+// Observe updates sink's links from a listing and buries the beans that
+// are gone. seen maps each listed bean to its task ID, or "" if it has
+// none. Known beans that weren't listed are buried only if exists reports
+// them missing, since filtered listings leave beans out. Pending tombstones
+// of beans that exist again are dropped. It returns the new tombstones.
+func (s *Store) Observe(sink string, seen map[string]string, exists func(beanID string) bool, now time.Time) []Tombstone {
+	links := s.Links[sink]
+	var buried []Tombstone
+	for beanID, taskID := range links {
+		if _, ok := seen[beanID]; ok || exists(beanID) {
+			continue
+		}
+		t := Tombstone{Sink: sink, BeanID: beanID, TaskID: taskID, Deleted: now}
+		buried = append(buried, t)
+		delete(links, beanID)
+	}
+	slices.SortFunc(buried, func(a, b Tombstone) int { return strings.Compare(a.BeanID, b.BeanID) })
+	s.Tombstones = append(s.Tombstones, buried...)
+
+	s.Tombstones = slices.DeleteFunc(s.Tombstones, func(t Tombstone) bool {
+		return t.Sink == sink && t.Action == "" && exists(t.BeanID)
+	})
+
+	for beanID, taskID := range seen {
+		if taskID == "" {
+			delete(links, beanID)
+			continue
+		}
+		if links == nil {
+			links = make(map[string]string)
+		}
+		links[beanID] = taskID
+	}
+	if s.Links == nil {
+		s.Links = make(map[string]map[string]string)
+	}
+	if len(links) == 0 {
+		delete(s.Links, sink)
+	} else {
+		s.Links[sink] = links
+	}
+	return buried
+}
+
+// Pending returns sink's tombstones that haven't been handled yet.
+func (s *Store) Pending(sink string) []*Tombstone {
+	var pending []*Tombstone
+	for i := range s.Tombstones {
+		if t := &s.Tombstones[i]; t.Sink == sink && t.Action == "" {
+			pending = append(pending, t)
+		}
+	}
+	return pending
+}
+
+// Purge removes tombstones of beans deleted before cutoff and returns how
+// many were removed.
+func (s *Store) Purge(cutoff time.Time) int {
+	n := len(s.Tombstones)
+	s.Tombstones = slices.DeleteFunc(s.Tombstones, func(t Tombstone) bool { return t.Deleted.Before(cutoff) })
+	return n - len(s.Tombstones)
+}
@@ -0,0 +1,61 @@
+package tombstone
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestObserve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	existing := map[string]bool{"a": true, "b": true, "c": true}
+	exists := func(id string) bool { return existing[id] }
+	day1 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	if buried := s.Observe("clickup", map[string]string{"a": "t1", "b": "t2", "c": ""}, exists, day1); len(buried) != 0 {
+		t.Fatalf("first sync buried %v", buried)
+	}
+
+	// b is filtered out of the listing but still exists; a was deleted
+	delete(existing, "a")
+	buried := s.Observe("clickup", map[string]string{"c": "t3"}, exists, day1)
+	if len(buried) != 1 || buried[0].BeanID != "a" || buried[0].TaskID != "t1" {
+		t.Fatalf("buried %+v, want only a", buried)
+	}
+	if got := s.Links["clickup"]; len(got) != 2 || got["b"] != "t2" || got["c"] != "t3" {
+		t.Errorf("links = %v", got)
+	}
+
+	// The store round-trips, and handled tombstones are no longer pending
+	if err := s.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if s, err = Load(path); err != nil {
+		t.Fatal(err)
+	}
+	pending := s.Pending("clickup")
+	if len(pending) != 1 {
+		t.Fatalf("pending = %v", pending)
+	}
+	pending[0].Action = "close"
+	if len(s.Pending("clickup")) != 0 {
+		t.Error("handled tombstone still pending")
+	}
+
+	// A deleted bean restored before it was handled is no longer buried
+	delete(existing, "b")
+	s.Observe("clickup", map[string]string{"c": "t3"}, exists, day1.AddDate(0, 0, 10))
+	existing["b"] = true
+	s.Observe("clickup", map[string]string{"b": "t2", "c": "t3"}, exists, day1.AddDate(0, 0, 11))
+	if len(s.Pending("clickup")) != 0 || s.Links["clickup"]["b"] != "t2" {
+		t.Errorf("restored bean: pending %v, links %v", s.Pending("clickup"), s.Links)
+	}
+
+	if n := s.Purge(day1.AddDate(0, 0, 5)); n != 1 || len(s.Tombstones) != 0 {
+		t.Errorf("Purge() = %d, left %v", n, s.Tombstones)
+	}
+}
@@ -0,0 +1,227 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	scopeName      = "github.com/toba/bean-me-up"
+	defaultTimeout = 10 * time.Second
+)
+
+// exporter sends spans to an OTLP/HTTP collector using the JSON encoding.
+type exporter struct {
+	endpoint   string
+	headers    map[string]string
+	resource   []Attr
+	httpClient *http.Client
+}
+
+// newExporterFromEnv builds an exporter from OTEL_* environment variables.
+// Returns nil if tracing is disabled or no endpoint is configured.
+func newExporterFromEnv(serviceName, version string) *exporter {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return nil
+	}
+	if strings.EqualFold(os.Getenv("OTEL_TRACES_EXPORTER"), "none") {
+		return nil
+	}
+
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	if endpoint == "" {
+		base := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+		if base == "" {
+			return nil
+		}
+		endpoint = strings.TrimRight(base, "/") + "/v1/traces"
+	}
+
+	headers := parseKeyValues(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+	for k, v := range parseKeyValues(os.Getenv("OTEL_EXPORTER_OTLP_TRACES_HEADERS")) {
+		headers[k] = v
+	}
+
+	if name := os.Getenv("OTEL_SERVICE_NAME"); name != "" {
+		serviceName = name
+	}
+	resource := []Attr{String("service.name", serviceName)}
+	if version != "" {
+		resource = append(resource, String("service.version", version))
+	}
+	for k, v := range parseKeyValues(os.Getenv("OTEL_RESOURCE_ATTRIBUTES")) {
+		if k != "service.name" {
+			resource = append(resource, String(k, v))
+		}
+	}
+
+	timeout := defaultTimeout
+	if ms, err := strconv.Atoi(os.Getenv("OTEL_EXPORTER_OTLP_TIMEOUT")); err == nil && ms > 0 {
+		timeout = time.Duration(ms) * time.Millisecond
+	}
+
+	return &exporter{
+		endpoint:   endpoint,
+		headers:    headers,
+		resource:   resource,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// parseKeyValues parses the OTEL "key1=value1,key2=value2" format.
+// Values are URL-decoded as required by the spec.
+func parseKeyValues(s string) map[string]string {
+	result := make(map[string]string)
+	for pair := range strings.SplitSeq(s, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		k = strings.TrimSpace(k)
+		if decoded, err := url.QueryUnescape(strings.TrimSpace(v)); err == nil {
+			v = decoded
+		}
+		if k != "" {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// export posts the spans as a single OTLP ExportTraceServiceRequest.
+func (e *exporter) export(ctx context.Context, spans []*Span) error {
+	body, err := json.Marshal(e.buildRequest(spans))
+	if err != nil {
+		return fmt.Errorf("marshaling spans: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("exporting spans: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("exporting spans: collector returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// OTLP JSON wire types (only the fields we populate).
+type (
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              int            `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		Status            *otlpStatus    `json:"status,omitempty"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code"` // 2 = ERROR
+		Message string `json:"message,omitempty"`
+	}
+	otlpKeyValue struct {
+		Key   string         `json:"key"`
+		Value map[string]any `json:"value"`
+	}
+)
+
+func (e *exporter) buildRequest(spans []*Span) otlpRequest {
+	out := make([]otlpSpan, 0, len(spans))
+	var zeroParent [8]byte
+	for _, s := range spans {
+		s.mu.Lock()
+		span := otlpSpan{
+			TraceID:           hex.EncodeToString(s.traceID[:]),
+			SpanID:            hex.EncodeToString(s.spanID[:]),
+			Name:              s.name,
+			Kind:              s.kind,
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+			Attributes:        toKeyValues(s.attrs),
+		}
+		if s.parentID != zeroParent {
+			span.ParentSpanID = hex.EncodeToString(s.parentID[:])
+		}
+		if s.hasError {
+			span.Status = &otlpStatus{Code: 2, Message: s.errMsg}
+		}
+		s.mu.Unlock()
+		out = append(out, span)
+	}
+
+	return otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{Attributes: toKeyValues(e.resource)},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: scopeName},
+				Spans: out,
+			}},
+		}},
+	}
+}
+
+// toKeyValues converts attributes to OTLP AnyValue encoding.
+func toKeyValues(attrs []Attr) []otlpKeyValue {
+	kvs := make([]otlpKeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		var v map[string]any
+		switch val := a.Value.(type) {
+		case string:
+			v = map[string]any{"stringValue": val}
+		case bool:
+			v = map[string]any{"boolValue": val}
+		case int:
+			v = map[string]any{"intValue": strconv.Itoa(val)}
+		case int64:
+			v = map[string]any{"intValue": strconv.FormatInt(val, 10)}
+		case float64:
+			v = map[string]any{"doubleValue": val}
+		default:
+			v = map[string]any{"stringValue": fmt.Sprint(val)}
+		}
+		kvs = append(kvs, otlpKeyValue{Key: a.Key, Value: v})
+	}
+	return kvs
+}
@@ -0,0 +1,308 @@
+// Package tracing provides lightweight OpenTelemetry-compatible tracing.
+//
+// Finished spans are buffered in memory and exported in OTLP/HTTP JSON format
+// in batches: once a batch fills, every few seconds, and when Shutdown is
+// called, so long-running commands don't hold every span until they exit.
+// Export is configured with the standard OTEL_* environment variables; when no
+// endpoint is configured, tracing is a no-op.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// batchSize is how many buffered spans trigger an export, and the most
+	// sent in one request.
+	batchSize = 512
+	// exportInterval is the longest a finished span waits to be exported.
+	exportInterval = 5 * time.Second
+	// maxBuffered bounds the spans held while exports lag or fail; spans
+	// finished beyond it are dropped.
+	maxBuffered = 4 * batchSize
+)
+
+// Span kinds (subset of the OTLP SpanKind enum).
+const (
+	KindInternal = 1
+	KindClient   = 3
+)
+
+// Attr is a span attribute. Value may be a string, bool, int, int64, or float64.
+type Attr struct {
+	Key   string
+	Value any
+}
+
+// String returns a string attribute.
+func String(key, value string) Attr { return Attr{Key: key, Value: value} }
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attr { return Attr{Key: key, Value: int64(value)} }
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) Attr { return Attr{Key: key, Value: value} }
+
+// Span records a single timed operation.
+// A nil *Span is valid and all methods are no-ops.
+type Span struct {
+	tracer   *Tracer
+	traceID  [16]byte
+	spanID   [8]byte
+	parentID [8]byte
+	name     string
+	kind     int
+	start    time.Time
+	end      time.Time
+
+	mu       sync.Mutex
+	attrs    []Attr
+	errMsg   string
+	hasError bool
+	ended    bool
+}
+
+// SetAttributes sets attributes on the span, replacing any with the same key.
+func (s *Span) SetAttributes(attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, a := range attrs {
+		replaced := false
+		for i := range s.attrs {
+			if s.attrs[i].Key == a.Key {
+				s.attrs[i] = a
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			s.attrs = append(s.attrs, a)
+		}
+	}
+}
+
+// RecordError marks the span as failed. A nil error is ignored.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	s.hasError = true
+	s.errMsg = err.Error()
+	s.mu.Unlock()
+}
+
+// End finishes the span and hands it to the tracer for export.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.mu.Unlock()
+	s.tracer.record(s)
+}
+
+// TraceID returns the hex-encoded trace ID, or empty for a nil span.
+func (s *Span) TraceID() string {
+	if s == nil {
+		return ""
+	}
+	return hex.EncodeToString(s.traceID[:])
+}
+
+// Tracer creates spans and exports finished spans in batches from a
+// background goroutine.
+type Tracer struct {
+	exporter  *exporter
+	batchSize int
+	interval  time.Duration
+	full      chan struct{} // signals a full batch
+	stop      chan struct{}
+	stopped   chan struct{}
+	stopOnce  sync.Once
+
+	mu        sync.Mutex
+	spans     []*Span
+	dropped   int
+	exportErr error // the first failed background export
+}
+
+// newTracer returns a tracer exporting to exp and starts its export loop.
+func newTracer(exp *exporter, batchSize int, interval time.Duration) *Tracer {
+	t := &Tracer{
+		exporter:  exp,
+		batchSize: batchSize,
+		interval:  interval,
+		full:      make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+	go t.run()
+	return t
+}
+
+type spanKey struct{}
+
+var (
+	globalMu sync.RWMutex
+	global   *Tracer
+)
+
+// Init configures the global tracer from OTEL_* environment variables.
+// It returns a shutdown function that exports buffered spans; the function
+// is always non-nil and safe to call when tracing is disabled.
+func Init(serviceName, version string) func(context.Context) error {
+	exp := newExporterFromEnv(serviceName, version)
+	if exp == nil {
+		return func(context.Context) error { return nil }
+	}
+
+	t := newTracer(exp, batchSize, exportInterval)
+	globalMu.Lock()
+	global = t
+	globalMu.Unlock()
+
+	return func(ctx context.Context) error {
+		globalMu.Lock()
+		if global == t {
+			global = nil
+		}
+		globalMu.Unlock()
+		return t.Shutdown(ctx)
+	}
+}
+
+// Start begins a span as a child of any span in ctx.
+// When tracing is disabled it returns ctx unchanged and a nil span.
+func Start(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	return start(ctx, name, KindInternal, attrs)
+}
+
+// StartClient begins a client-kind span, used for outgoing API calls.
+func StartClient(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	return start(ctx, name, KindClient, attrs)
+}
+
+func start(ctx context.Context, name string, kind int, attrs []Attr) (context.Context, *Span) {
+	globalMu.RLock()
+	t := global
+	globalMu.RUnlock()
+	if t == nil {
+		return ctx, nil
+	}
+
+	s := &Span{
+		tracer: t,
+		name:   name,
+		kind:   kind,
+		start:  time.Now(),
+		attrs:  attrs,
+	}
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok && parent != nil {
+		s.traceID = parent.traceID
+		s.parentID = parent.spanID
+	} else {
+		_, _ = rand.Read(s.traceID[:])
+	}
+	_, _ = rand.Read(s.spanID[:])
+
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// SpanFromContext returns the active span in ctx, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanKey{}).(*Span)
+	return s
+}
+
+// record buffers a finished span, waking the export loop once a batch is
+// full. Spans beyond maxBuffered are dropped.
+func (t *Tracer) record(s *Span) {
+	t.mu.Lock()
+	if len(t.spans) >= maxBuffered {
+		t.dropped++
+		t.mu.Unlock()
+		return
+	}
+	t.spans = append(t.spans, s)
+	full := len(t.spans) >= t.batchSize
+	t.mu.Unlock()
+	if full {
+		select {
+		case t.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// run exports the buffered spans whenever a batch fills or the interval
+// passes, until Shutdown stops it.
+func (t *Tracer) run() {
+	defer close(t.stopped)
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-t.full:
+		case <-ticker.C:
+		}
+		if err := t.flush(context.Background()); err != nil {
+			t.mu.Lock()
+			if t.exportErr == nil {
+				t.exportErr = err
+			}
+			t.mu.Unlock()
+		}
+	}
+}
+
+// flush exports and clears the buffered spans, a batch per request.
+func (t *Tracer) flush(ctx context.Context) error {
+	t.mu.Lock()
+	spans := t.spans
+	t.spans = nil
+	t.mu.Unlock()
+
+	var errs []error
+	for batch := range slices.Chunk(spans, t.batchSize) {
+		if err := t.exporter.export(ctx, batch); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Shutdown stops the export loop and exports the spans still buffered. It
+// also reports the first background export that failed and any spans
+// dropped because the buffer was full.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	t.stopOnce.Do(func() { close(t.stop) })
+	<-t.stopped
+
+	err := t.flush(ctx)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	err = errors.Join(t.exportErr, err)
+	if t.dropped > 0 {
+		err = errors.Join(err, fmt.Errorf("dropped %d spans while exports lagged", t.dropped))
+	}
+	return err
+}
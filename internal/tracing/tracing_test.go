@@ -0,0 +1,175 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDisabledWithoutEndpoint(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+
+	shutdown := Init("beanup", "")
+	ctx, span := Start(context.Background(), "noop")
+	if span != nil {
+		t.Fatal("expected nil span when tracing is disabled")
+	}
+	if SpanFromContext(ctx) != nil {
+		t.Fatal("expected no span in context")
+	}
+
+	// Nil spans must be safe to use
+	span.SetAttributes(String("k", "v"))
+	span.RecordError(errors.New("boom"))
+	span.End()
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+}
+
+func TestExportParentChild(t *testing.T) {
+	var got otlpRequest
+	var gotHeader string
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotHeader = r.Header.Get("X-Api-Key")
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("decoding export: %v", err)
+		}
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", server.URL+"/")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "X-Api-Key=secret%20value")
+	t.Setenv("OTEL_SERVICE_NAME", "")
+
+	shutdown := Init("beanup", "1.2.3")
+
+	ctx, parent := Start(context.Background(), "sync.beans", Int("beans.count", 2))
+	_, child := StartClient(ctx, "clickup GET /task/1")
+	child.SetAttributes(Int("http.response.status_code", 500))
+	child.SetAttributes(Int("http.response.status_code", 200))
+	child.RecordError(errors.New("boom"))
+	child.End()
+	parent.End()
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	if gotPath != "/v1/traces" {
+		t.Errorf("path = %q, want /v1/traces", gotPath)
+	}
+	if gotHeader != "secret value" {
+		t.Errorf("header = %q, want %q", gotHeader, "secret value")
+	}
+	if len(got.ResourceSpans) != 1 || len(got.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("unexpected payload shape: %+v", got)
+	}
+
+	spans := got.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	byName := make(map[string]otlpSpan)
+	for _, s := range spans {
+		byName[s.Name] = s
+	}
+	p, c := byName["sync.beans"], byName["clickup GET /task/1"]
+	if c.TraceID != p.TraceID {
+		t.Errorf("child trace ID %s != parent %s", c.TraceID, p.TraceID)
+	}
+	if c.ParentSpanID != p.SpanID {
+		t.Errorf("child parent ID %s != parent span ID %s", c.ParentSpanID, p.SpanID)
+	}
+	if p.ParentSpanID != "" {
+		t.Errorf("root span should have no parent, got %s", p.ParentSpanID)
+	}
+	if c.Kind != KindClient {
+		t.Errorf("child kind = %d, want %d", c.Kind, KindClient)
+	}
+	if c.Status == nil || c.Status.Code != 2 || c.Status.Message != "boom" {
+		t.Errorf("child status = %+v, want error boom", c.Status)
+	}
+	if len(c.Attributes) != 1 || c.Attributes[0].Value["intValue"] != "200" {
+		t.Errorf("child attributes = %+v, want single status_code=200", c.Attributes)
+	}
+}
+
+func TestParseKeyValues(t *testing.T) {
+	got := parseKeyValues("a=1, b = two ,bad,c=x%3Dy")
+	want := map[string]string{"a": "1", "b": "two", "c": "x=y"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestExportBatches(t *testing.T) {
+	exported := make(chan int, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req otlpRequest
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Errorf("decoding export: %v", err)
+		}
+		exported <- len(req.ResourceSpans[0].ScopeSpans[0].Spans)
+	}))
+	defer server.Close()
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", server.URL)
+
+	wait := func(want int) {
+		t.Helper()
+		select {
+		case n := <-exported:
+			if n != want {
+				t.Errorf("exported %d spans, want %d", n, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no export of %d spans", want)
+		}
+	}
+	end := func(tr *Tracer, n int) {
+		for range n {
+			(&Span{tracer: tr, name: "op", start: time.Now()}).End()
+		}
+	}
+
+	// A full batch is exported, and cleared, without waiting for Shutdown
+	tr := newTracer(newExporterFromEnv("beanup", ""), 2, time.Hour)
+	end(tr, 2)
+	wait(2)
+	tr.mu.Lock()
+	buffered := len(tr.spans)
+	tr.mu.Unlock()
+	if buffered != 0 {
+		t.Errorf("%d spans still buffered after export", buffered)
+	}
+	end(tr, 1)
+	if err := tr.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	wait(1)
+
+	// A partial batch is exported once the interval passes
+	tr = newTracer(newExporterFromEnv("beanup", ""), 100, 10*time.Millisecond)
+	defer func() { _ = tr.Shutdown(context.Background()) }()
+	end(tr, 3)
+	wait(3)
+}
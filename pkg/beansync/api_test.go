@@ -0,0 +1,150 @@
+package beansync_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/toba/bean-me-up/pkg/beansync"
+	"github.com/toba/bean-me-up/pkg/clickup"
+)
+
+// TestPublicAPI_NamesEveryType checks that each internal type reachable
+// from the exported API of pkg/beansync and pkg/clickup has an exported
+// name there, so callers can declare values of it.
+func TestPublicAPI_NamesEveryType(t *testing.T) {
+	public := map[reflect.Type]bool{}
+	for _, typ := range append(beansyncTypes(), clickupTypes()...) {
+		public[typ] = true
+	}
+	roots := append(beansyncTypes(), clickupTypes()...)
+	roots = append(roots,
+		reflect.TypeOf(beansync.NewSyncer), reflect.TypeOf(beansync.NewClickUpSink),
+		reflect.TypeOf(beansync.LoadConfig), reflect.TypeOf(beansync.ListBeans),
+		reflect.TypeOf(beansync.NewExtensionStateProvider), reflect.TypeOf(beansync.FilterBeansForSync),
+		reflect.TypeOf(beansync.FilterBeansNeedingSync), reflect.TypeOf(clickup.NewClient),
+	)
+
+	seen := map[reflect.Type]bool{}
+	var walk func(typ reflect.Type, path string)
+	walk = func(typ reflect.Type, path string) {
+		if seen[typ] {
+			return
+		}
+		seen[typ] = true
+		if typ.Name() != "" && strings.HasPrefix(typ.PkgPath(), "github.com/toba/bean-me-up/internal/") && !public[typ] {
+			t.Errorf("%s has no public name (reached from %s)", typ, path)
+		}
+		for i := range typ.NumMethod() {
+			if m := typ.Method(i); m.IsExported() {
+				walk(m.Type, path+"."+m.Name)
+			}
+		}
+		switch typ.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Chan:
+			walk(typ.Elem(), path)
+		case reflect.Map:
+			walk(typ.Key(), path)
+			walk(typ.Elem(), path)
+		case reflect.Func:
+			for i := range typ.NumIn() {
+				walk(typ.In(i), path)
+			}
+			for i := range typ.NumOut() {
+				walk(typ.Out(i), path)
+			}
+		case reflect.Struct:
+			for i := range typ.NumField() {
+				if f := typ.Field(i); f.IsExported() {
+					walk(f.Type, path+"."+f.Name)
+				}
+			}
+		}
+		if typ.Name() != "" && typ.Kind() != reflect.Pointer && typ.Kind() != reflect.Interface {
+			walk(reflect.PointerTo(typ), path)
+		}
+	}
+	for _, typ := range roots {
+		walk(typ, typ.String())
+	}
+}
+
+func beansyncTypes() []reflect.Type {
+	return []reflect.Type{
+		reflect.TypeFor[beansync.Bean](),
+		reflect.TypeFor[beansync.WorklogEntry](),
+		reflect.TypeFor[beansync.Config](),
+		reflect.TypeFor[beansync.SyncFilter](),
+		reflect.TypeFor[beansync.CustomFieldsMap](),
+		reflect.TypeFor[beansync.TagColor](),
+		reflect.TypeFor[beansync.HTTPConfig](),
+		reflect.TypeFor[beansync.TokenSource](),
+		reflect.TypeFor[beansync.ReviewRequestConfig](),
+		reflect.TypeFor[beansync.NotificationsConfig](),
+		reflect.TypeFor[beansync.Syncer](),
+		reflect.TypeFor[beansync.Options](),
+		reflect.TypeFor[beansync.Result](),
+		reflect.TypeFor[beansync.ProgressFunc](),
+		reflect.TypeFor[beansync.StateProvider](),
+		reflect.TypeFor[beansync.TaskURLStore](),
+		reflect.TypeFor[beansync.Sink](),
+		reflect.TypeFor[beansync.TaskRef](),
+		reflect.TypeFor[beansync.StatusUpdater](),
+		reflect.TypeFor[beansync.FieldReporter](),
+		reflect.TypeFor[beansync.TaskRemover](),
+		reflect.TypeFor[beansync.TaskReturner](),
+		reflect.TypeFor[beansync.TaskLister](),
+		reflect.TypeFor[beansync.ParentLinker](),
+		reflect.TypeFor[beansync.BeanValidator](),
+		reflect.TypeFor[beansync.ReferenceLinker](),
+	}
+}
+
+func clickupTypes() []reflect.Type {
+	return []reflect.Type{
+		reflect.TypeFor[clickup.Client](),
+		reflect.TypeFor[clickup.ClientOption](),
+		reflect.TypeFor[clickup.HTTPDoer](),
+		reflect.TypeFor[clickup.RetryConfig](),
+		reflect.TypeFor[clickup.TaskInfo](),
+		reflect.TypeFor[clickup.TaskPriority](),
+		reflect.TypeFor[clickup.TaskCustomField](),
+		reflect.TypeFor[clickup.TaskList](),
+		reflect.TypeFor[clickup.TaskSpace](),
+		reflect.TypeFor[clickup.TaskUser](),
+		reflect.TypeFor[clickup.TaskAttachment](),
+		reflect.TypeFor[clickup.Dependency](),
+		reflect.TypeFor[clickup.Checklist](),
+		reflect.TypeFor[clickup.ChecklistItem](),
+		reflect.TypeFor[clickup.HistoryItem](),
+		reflect.TypeFor[clickup.CreateTaskRequest](),
+		reflect.TypeFor[clickup.UpdateTaskRequest](),
+		reflect.TypeFor[clickup.AssigneesUpdate](),
+		reflect.TypeFor[clickup.Tag](),
+		reflect.TypeFor[clickup.CreateCommentRequest](),
+		reflect.TypeFor[clickup.CommentPart](),
+		reflect.TypeFor[clickup.CommentUser](),
+		reflect.TypeFor[clickup.TaskComment](),
+		reflect.TypeFor[clickup.TimeEntry](),
+		reflect.TypeFor[clickup.CreateTimeEntryRequest](),
+		reflect.TypeFor[clickup.Team](),
+		reflect.TypeFor[clickup.Space](),
+		reflect.TypeFor[clickup.SpaceFeatures](),
+		reflect.TypeFor[clickup.SpacePriority](),
+		reflect.TypeFor[clickup.Folder](),
+		reflect.TypeFor[clickup.List](),
+		reflect.TypeFor[clickup.ListDetails](),
+		reflect.TypeFor[clickup.ListFolder](),
+		reflect.TypeFor[clickup.ListSummary](),
+		reflect.TypeFor[clickup.ListRequest](),
+		reflect.TypeFor[clickup.Status](),
+		reflect.TypeFor[clickup.CustomItem](),
+		reflect.TypeFor[clickup.AuthorizedUser](),
+		reflect.TypeFor[clickup.CustomField](),
+		reflect.TypeFor[clickup.FieldInfo](),
+		reflect.TypeFor[clickup.CreateFieldRequest](),
+		reflect.TypeFor[clickup.APIError](),
+		reflect.TypeFor[clickup.RateLimitError](),
+		reflect.TypeFor[clickup.TransientError](),
+	}
+}
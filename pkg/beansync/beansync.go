@@ -0,0 +1,123 @@
+// Package beansync is the public API for syncing beans to ClickUp tasks.
+//
+// It exposes the same multi-pass Syncer the beanup CLI uses, so other Go
+// tools can embed bean→ClickUp syncing without shelling out to beanup.
+// Every type reachable from it has a name here, or in pkg/clickup for the
+// client, so callers never need to name an internal package.
+// The Syncer pushes to a Sink; implement Sink to target another tracker.
+// Sync state is read and written through a StateProvider; use
+// NewExtensionStateProvider to store it in bean extension metadata like
+// the CLI does, or supply your own implementation.
+package beansync
+
+import (
+	"github.com/toba/bean-me-up/internal/beans"
+	"github.com/toba/bean-me-up/internal/clickup"
+	"github.com/toba/bean-me-up/internal/config"
+	"github.com/toba/bean-me-up/internal/syncer"
+)
+
+// Beans and configuration.
+type (
+	Bean            = beans.Bean
+	WorklogEntry    = beans.WorklogEntry
+	Config          = config.ClickUpConfig
+	SyncFilter      = config.SyncFilter
+	CustomFieldsMap = config.CustomFieldsMap
+	TagColor        = config.TagColor
+	HTTPConfig      = config.HTTPConfig
+	TokenSource     = config.TokenSource
+
+	ReviewRequestConfig = config.ReviewRequestConfig
+	NotificationsConfig = config.NotificationsConfig
+)
+
+// Syncer and its inputs and outputs.
+type (
+	Syncer       = syncer.Syncer
+	Options      = syncer.Options
+	Result       = syncer.Result
+	ProgressFunc = syncer.ProgressFunc
+	// StateProvider stores the bean → task link and last sync time.
+	// Changes are buffered until Flush is called.
+	StateProvider = syncer.StateProvider
+	// TaskURLStore is a StateProvider that also records task URLs, used
+	// when Options.StoreTaskURL is set.
+	TaskURLStore = syncer.TaskURLStore
+)
+
+// Sinks: the issue tracker backends a Syncer pushes to.
+type (
+	Sink    = syncer.Sink
+	TaskRef = syncer.TaskRef
+	// StatusUpdater lets a Sink serve Options.StatusOnly with one request.
+	StatusUpdater = syncer.StatusUpdater
+	// FieldReporter lets a Sink report which task fields an update changed.
+	FieldReporter = syncer.FieldReporter
+	// TaskRemover lets a Sink close, archive, or delete tasks of deleted beans.
+	TaskRemover = syncer.TaskRemover
+	// TaskReturner lets a Sink move a task back for Options.OnMove "return".
+	TaskReturner = syncer.TaskReturner
+	// TaskLister lets a Sink list its tasks to find those edited remotely.
+	TaskLister = syncer.TaskLister
+	// ParentLinker lets a Sink set a task's parent after creation.
+	ParentLinker = syncer.ParentLinker
+	// BeanValidator lets a Sink reject a bean before anything is sent.
+	BeanValidator = syncer.BeanValidator
+	// ReferenceLinker lets a Sink record a bean's references in task fields.
+	ReferenceLinker = syncer.ReferenceLinker
+)
+
+// Values for Options.OnMove.
+const (
+	OnMoveWarn   = syncer.OnMoveWarn
+	OnMoveFollow = syncer.OnMoveFollow
+	OnMoveReturn = syncer.OnMoveReturn
+)
+
+// ErrTaskNotFound is returned by Sink.GetTask for deleted tasks.
+var ErrTaskNotFound = syncer.ErrTaskNotFound
+
+// NewSyncer creates a Syncer that pushes beans to sink.
+func NewSyncer(sink Sink, opts Options, state StateProvider) *Syncer {
+	return syncer.New(sink, opts, state)
+}
+
+// NewClickUpSink returns a sink that creates tasks in cfg.ListID, using
+// cfg's status, priority, type, and custom field mappings.
+func NewClickUpSink(client *clickup.Client, cfg *Config) Sink {
+	return clickup.NewSink(client, cfg, cfg.ListID)
+}
+
+// LoadConfig finds and loads the ClickUp configuration by searching upward
+// from startDir. It returns the config and the directory it was found in.
+func LoadConfig(startDir string) (*Config, string, error) {
+	cfg, dir, err := config.LoadFromDirectory(startDir, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	return &cfg.Beans.ClickUp, dir, nil
+}
+
+// ListBeans returns all beans in beansPath using the beans CLI.
+func ListBeans(beansPath string) ([]Bean, error) {
+	return beans.NewClient(beansPath).List()
+}
+
+// NewExtensionStateProvider returns a StateProvider backed by bean extension
+// metadata under the sink's name, pre-populated from beanList. Flush writes
+// all changes with a single beans CLI call.
+func NewExtensionStateProvider(beansPath, sinkName string, beanList []Bean) StateProvider {
+	return syncer.NewExtensionStateProvider(beans.NewClient(beansPath), sinkName, beanList)
+}
+
+// FilterBeansForSync drops beans excluded by filter.
+func FilterBeansForSync(beanList []Bean, filter *SyncFilter) []Bean {
+	return syncer.FilterBeansForSync(beanList, filter)
+}
+
+// FilterBeansNeedingSync returns beans that are unlinked or changed since
+// their last sync. With force, every bean is returned.
+func FilterBeansNeedingSync(beanList []Bean, state StateProvider, force bool) []Bean {
+	return syncer.FilterBeansNeedingSync(beanList, state, force)
+}
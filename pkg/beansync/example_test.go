@@ -0,0 +1,39 @@
+package beansync_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/toba/bean-me-up/pkg/beansync"
+	"github.com/toba/bean-me-up/pkg/clickup"
+)
+
+func Example() {
+	cfg, _, err := beansync.LoadConfig(".")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	beanList, err := beansync.ListBeans(".beans")
+	if err != nil {
+		log.Fatal(err)
+	}
+	beanList = beansync.FilterBeansForSync(beanList, cfg.SyncFilter)
+
+	sink := beansync.NewClickUpSink(clickup.NewClient(os.Getenv("CLICKUP_TOKEN")), cfg)
+	state := beansync.NewExtensionStateProvider(".beans", sink.Name(), beanList)
+	syncer := beansync.NewSyncer(sink, beansync.Options{}, state)
+
+	results, err := syncer.SyncBeans(context.Background(), beansync.FilterBeansNeedingSync(beanList, state, false))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := state.Flush(); err != nil {
+		log.Fatal(err)
+	}
+	for _, r := range results {
+		fmt.Println(r.BeanID, r.Action, r.TaskURL)
+	}
+}
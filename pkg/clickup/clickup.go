@@ -0,0 +1,126 @@
+// Package clickup is the public ClickUp REST client used by beanup.
+//
+// It re-exports the client from the internal implementation so other Go
+// tools can talk to ClickUp with the same retry and error handling. Every
+// type the client's methods take or return is named here:
+//
+//	c := clickup.NewClient(token, clickup.WithHTTPClient(myDoer))
+//	task, err := c.GetTask(ctx, "abc123")
+//	if errors.Is(err, clickup.ErrTaskNotFound) { ... }
+package clickup
+
+import (
+	"time"
+
+	"github.com/toba/bean-me-up/internal/clickup"
+)
+
+// DefaultBaseURL is the ClickUp REST API v2 endpoint.
+const DefaultBaseURL = clickup.DefaultBaseURL
+
+// Client and its configuration.
+type (
+	Client       = clickup.Client
+	ClientOption = clickup.ClientOption
+	HTTPDoer     = clickup.HTTPDoer
+	RetryConfig  = clickup.RetryConfig
+)
+
+// Tasks.
+type (
+	TaskInfo          = clickup.TaskInfo
+	TaskPriority      = clickup.TaskPriority
+	TaskCustomField   = clickup.TaskCustomField
+	TaskList          = clickup.TaskList
+	TaskSpace         = clickup.TaskSpace
+	TaskUser          = clickup.TaskUser
+	TaskAttachment    = clickup.TaskAttachment
+	Dependency        = clickup.Dependency
+	Checklist         = clickup.Checklist
+	ChecklistItem     = clickup.ChecklistItem
+	HistoryItem       = clickup.HistoryItem
+	CreateTaskRequest = clickup.CreateTaskRequest
+	UpdateTaskRequest = clickup.UpdateTaskRequest
+	AssigneesUpdate   = clickup.AssigneesUpdate
+	Tag               = clickup.Tag
+)
+
+// Comments and time tracking.
+type (
+	CreateCommentRequest   = clickup.CreateCommentRequest
+	CommentPart            = clickup.CommentPart
+	CommentUser            = clickup.CommentUser
+	TaskComment            = clickup.TaskComment
+	TimeEntry              = clickup.TimeEntry
+	CreateTimeEntryRequest = clickup.CreateTimeEntryRequest
+)
+
+// Workspaces, spaces, folders, and lists.
+type (
+	Team           = clickup.Team
+	Space          = clickup.Space
+	SpaceFeatures  = clickup.SpaceFeatures
+	SpacePriority  = clickup.SpacePriority
+	Folder         = clickup.Folder
+	List           = clickup.List
+	ListDetails    = clickup.ListDetails
+	ListFolder     = clickup.ListFolder
+	ListSummary    = clickup.ListSummary
+	ListRequest    = clickup.ListRequest
+	Status         = clickup.Status
+	CustomItem     = clickup.CustomItem
+	AuthorizedUser = clickup.AuthorizedUser
+)
+
+// Custom fields.
+type (
+	CustomField        = clickup.CustomField
+	FieldInfo          = clickup.FieldInfo
+	CreateFieldRequest = clickup.CreateFieldRequest
+)
+
+// Errors returned by the client.
+type (
+	APIError       = clickup.APIError
+	RateLimitError = clickup.RateLimitError
+	TransientError = clickup.TransientError
+)
+
+// Error kinds; match them with errors.Is.
+var (
+	ErrTaskNotFound = clickup.ErrTaskNotFound
+	ErrRateLimited  = clickup.ErrRateLimited
+	ErrUnauthorized = clickup.ErrUnauthorized
+	ErrValidation   = clickup.ErrValidation
+)
+
+// NewClient creates a ClickUp client authenticated with an API token.
+func NewClient(token string, opts ...ClientOption) *Client {
+	return clickup.NewClient(token, opts...)
+}
+
+// WithHTTPClient sets the transport used for API requests.
+func WithHTTPClient(doer HTTPDoer) ClientOption {
+	return clickup.WithHTTPClient(doer)
+}
+
+// WithBaseURL points the client at a different API root, such as a mock server.
+func WithBaseURL(url string) ClientOption {
+	return clickup.WithBaseURL(url)
+}
+
+// WithTimeout sets the HTTP timeout of the default transport.
+func WithTimeout(d time.Duration) ClientOption {
+	return clickup.WithTimeout(d)
+}
+
+// WithMaxIdleConns sets how many idle connections the default transport
+// keeps per host.
+func WithMaxIdleConns(n int) ClientOption {
+	return clickup.WithMaxIdleConns(n)
+}
+
+// WithRetryConfig overrides the default retry settings.
+func WithRetryConfig(rc RetryConfig) ClientOption {
+	return clickup.WithRetryConfig(rc)
+}